@@ -0,0 +1,264 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"sttbot/pkg/retry"
+)
+
+// ChainID представляет идентификатор цепочки задач.
+type ChainID int
+
+// StepErrorPolicy определяет реакцию цепочки на ошибку шага.
+type StepErrorPolicy int
+
+const (
+	// StopChain прерывает цепочку при ошибке шага (по умолчанию).
+	StopChain StepErrorPolicy = iota
+	// ContinueChain переходит к следующему шагу. Ошибка сообщается только хуками шага.
+	ContinueChain
+	// RetryStep повторяет шаг по политике ChainStep.Retry.
+	// Если все попытки неудачны, цепочка прерывается, как при StopChain.
+	RetryStep
+)
+
+// ErrInvalidChain - некорректное описание цепочки в AddChain.
+var ErrInvalidChain = errors.New("scheduler: invalid chain")
+
+// ChainStep описывает шаг цепочки задач.
+type ChainStep struct {
+	// Name - имя шага. В хуках и логах шаг называется "<цепочка>/<шаг>"
+	// (по умолчанию номер шага, начиная с 1).
+	Name string
+	// Job - функция шага.
+	Job JobFunc
+	// Timeout - максимальное время выполнения шага вместе со всеми попытками (необязательно).
+	Timeout time.Duration
+	// OnError - реакция цепочки на ошибку шага.
+	OnError StepErrorPolicy
+	// Retry - политика повторов для RetryStep (по умолчанию retry.DefaultConfig()).
+	// При других политиках не используется.
+	Retry *retry.Config
+}
+
+// ChainOptions содержит опции цепочки задач.
+// Должно быть задано ровно одно из Schedule и Interval.
+type ChainOptions struct {
+	// Schedule - cron-расписание цепочки.
+	Schedule string
+	// Interval - интервал запуска цепочки как ticker-задачи.
+	Interval time.Duration
+	// Job - опции цепочки как задачи: Timeout всей цепочки, OverlapPolicy и т.д.
+	// Name заменяется именем цепочки. Key игнорируется: цепочки не сохраняются в JobStore.
+	Job JobOptions
+}
+
+// ChainStepError - ошибка шага, прервавшего цепочку. Передаётся в JobHooks.OnJobError
+// с именем цепочки, поэтому по ней видно, какой шаг завершился неудачей.
+type ChainStepError struct {
+	Chain string
+	Step  string
+	Err   error
+}
+
+func (e *ChainStepError) Error() string {
+	return fmt.Sprintf("chain %s: step %s failed: %v", e.Chain, e.Step, e.Err)
+}
+
+func (e *ChainStepError) Unwrap() error {
+	return e.Err
+}
+
+// chainStep - подготовленный шаг цепочки.
+type chainStep struct {
+	name    string
+	policy  StepErrorPolicy
+	wrapper *jobWrapper // опции шага для runWithRetry и статистика
+}
+
+// chain содержит информацию о цепочке задач.
+type chain struct {
+	id       ChainID
+	name     string
+	steps    []chainStep
+	ctx      context.Context // отменяется при RemoveChain
+	cancel   context.CancelFunc
+	cronID   CronJobID
+	tickerID TickerJobID
+}
+
+// AddChain добавляет цепочку задач, шаги которой выполняются последовательно в одной
+// горутине. Цепочка планируется как обычная cron- или ticker-задача с именем name,
+// поэтому на неё действуют JobOptions из opts.Job, пауза и глобальный лимит, а в
+// Jobs() она видна как одна задача. Хуки вызываются и для цепочки целиком (name),
+// и для каждого шага ("name/step"). Если шаг с StopChain или RetryStep завершился
+// ошибкой, цепочка завершается с *ChainStepError.
+func (s *Scheduler) AddChain(name string, steps []ChainStep, opts ChainOptions) (ChainID, error) {
+	if err := validateChain(name, steps, opts); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	c := &chain{
+		name:   name,
+		steps:  make([]chainStep, 0, len(steps)),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for i, step := range steps {
+		stepName := step.Name
+		if stepName == "" {
+			stepName = strconv.Itoa(i + 1)
+		}
+		stepOpts := JobOptions{
+			Name:    name + "/" + stepName,
+			Timeout: step.Timeout,
+		}
+		if step.OnError == RetryStep {
+			cfg := retry.DefaultConfig()
+			if step.Retry != nil {
+				cfg = *step.Retry
+			}
+			stepOpts.Retry = &cfg
+		}
+		c.steps = append(c.steps, chainStep{
+			name:    stepName,
+			policy:  step.OnError,
+			wrapper: &jobWrapper{job: step.Job, options: stepOpts},
+		})
+	}
+
+	jobOpts := opts.Job
+	jobOpts.Name = name
+	jobOpts.Key = ""
+	job := func(ctx context.Context) error {
+		return s.runChain(ctx, c)
+	}
+
+	if opts.Schedule != "" {
+		id, err := s.AddCronJobWithOptions(opts.Schedule, job, jobOpts)
+		if err != nil {
+			cancel()
+			return 0, err
+		}
+		c.cronID = id
+	} else {
+		c.tickerID = s.AddTickerJobWithOptions(opts.Interval, job, jobOpts)
+	}
+
+	s.mu.Lock()
+	c.id = s.nextChainID
+	s.nextChainID++
+	s.chains[c.id] = c
+	s.mu.Unlock()
+
+	s.logger.Info("job chain added", "name", name, "steps", len(steps), "id", c.id)
+	return c.id, nil
+}
+
+// RemoveChain удаляет цепочку задач и отменяет контекст выполняющегося шага.
+// Возвращает false, если цепочка не найдена.
+func (s *Scheduler) RemoveChain(id ChainID) bool {
+	s.mu.Lock()
+	c, exists := s.chains[id]
+	delete(s.chains, id)
+	s.mu.Unlock()
+	if !exists {
+		return false
+	}
+
+	c.cancel()
+	if c.cronID != 0 {
+		s.RemoveCronJob(c.cronID)
+	} else {
+		s.RemoveTickerJob(c.tickerID)
+	}
+
+	s.logger.Info("job chain removed", "id", id, "name", c.name)
+	return true
+}
+
+// validateChain проверяет аргументы AddChain.
+func validateChain(name string, steps []ChainStep, opts ChainOptions) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidChain)
+	}
+	if len(steps) == 0 {
+		return fmt.Errorf("%w: no steps", ErrInvalidChain)
+	}
+	for i, step := range steps {
+		if step.Job == nil {
+			return fmt.Errorf("%w: step %d has no job", ErrInvalidChain, i+1)
+		}
+	}
+	if (opts.Schedule == "") == (opts.Interval <= 0) {
+		return fmt.Errorf("%w: exactly one of Schedule and Interval is required", ErrInvalidChain)
+	}
+	return nil
+}
+
+// runChain последовательно выполняет шаги цепочки.
+// Контекст шагов отменяется и при отмене ctx, и при удалении цепочки.
+func (s *Scheduler) runChain(ctx context.Context, c *chain) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(c.ctx, cancel)
+	defer stop()
+
+	for _, step := range c.steps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := s.runStep(ctx, step)
+		if err == nil {
+			continue
+		}
+		if step.policy == ContinueChain && ctx.Err() == nil {
+			s.logger.Warn("chain step failed, continuing", "name", step.wrapper.options.Name, "error", err)
+			continue
+		}
+		return &ChainStepError{Chain: c.name, Step: step.name, Err: err}
+	}
+	return nil
+}
+
+// runStep выполняет шаг цепочки с таймаутом, ретраями и хуками.
+// Паника в шаге преобразуется в ошибку шага.
+func (s *Scheduler) runStep(ctx context.Context, step chainStep) (err error) {
+	wrapper := step.wrapper
+	stepName := wrapper.options.Name
+	ctx = s.withRun(ctx, stepName)
+
+	s.hookStart(ctx, stepName)
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+			s.logger.Error("chain step panicked", "name", stepName, "panic", r)
+		}
+
+		duration := time.Since(start)
+		wrapper.recordRun(start, duration, err)
+		s.hookFinish(ctx, stepName, duration, err)
+		if err != nil {
+			s.logger.Error("chain step failed", "name", stepName, "error", err, "duration", duration)
+			s.hookError(ctx, stepName, err)
+		} else {
+			s.logger.Debug("chain step completed successfully", "name", stepName, "duration", duration)
+		}
+	}()
+
+	if wrapper.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, wrapper.options.Timeout)
+		defer cancel()
+	}
+
+	return s.runWithRetry(ctx, wrapper, stepName)
+}
@@ -0,0 +1,309 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/pkg/retry"
+)
+
+// chainRecorder записывает вызовы хуков и сигнализирует о завершении цепочки.
+type chainRecorder struct {
+	mu       sync.Mutex
+	started  []string
+	errors   map[string]error
+	retries  []string
+	finished chan error
+	chain    string
+}
+
+func newChainRecorder(chain string) *chainRecorder {
+	return &chainRecorder{
+		errors:   make(map[string]error),
+		finished: make(chan error, 10),
+		chain:    chain,
+	}
+}
+
+func (r *chainRecorder) hooks() JobHooks {
+	return JobHooks{
+		OnJobStart: func(jobName string) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.started = append(r.started, jobName)
+		},
+		OnJobFinish: func(jobName string, duration time.Duration, err error) {
+			if jobName == r.chain {
+				r.finished <- err
+			}
+		},
+		OnJobError: func(jobName string, err error) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.errors[jobName] = err
+		},
+		OnJobRetry: func(jobName string, attempt int, err error) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.retries = append(r.retries, jobName)
+		},
+	}
+}
+
+func (r *chainRecorder) wait(t *testing.T) error {
+	t.Helper()
+	select {
+	case err := <-r.finished:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("цепочка не завершилась")
+		return nil
+	}
+}
+
+// runOnce - опции цепочки с одним запуском сразу после Start.
+var runOnce = ChainOptions{Interval: time.Hour, Job: JobOptions{RunImmediately: true}}
+
+func TestScheduler_ChainRunsStepsInOrder(t *testing.T) {
+	rec := newChainRecorder("nightly")
+	s := New(Config{JobHooks: rec.hooks()})
+	defer s.Stop()
+
+	var order []string
+	step := func(name string) ChainStep {
+		return ChainStep{Name: name, Job: func(ctx context.Context) error {
+			order = append(order, name)
+			return nil
+		}}
+	}
+
+	_, err := s.AddChain("nightly", []ChainStep{step("export"), step("compress"), step("upload")}, runOnce)
+	require.NoError(t, err)
+	s.Start()
+
+	require.NoError(t, rec.wait(t))
+	assert.Equal(t, []string{"export", "compress", "upload"}, order)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	assert.Equal(t, []string{"nightly", "nightly/export", "nightly/compress", "nightly/upload"}, rec.started)
+	assert.Empty(t, rec.errors)
+}
+
+func TestScheduler_ChainStopOnError(t *testing.T) {
+	rec := newChainRecorder("nightly")
+	s := New(Config{JobHooks: rec.hooks()})
+	defer s.Stop()
+
+	errCompress := errors.New("disk full")
+	uploaded := false
+	_, err := s.AddChain("nightly", []ChainStep{
+		{Name: "export", Job: func(ctx context.Context) error { return nil }},
+		{Name: "compress", Job: func(ctx context.Context) error { return errCompress }},
+		{Name: "upload", Job: func(ctx context.Context) error { uploaded = true; return nil }},
+	}, runOnce)
+	require.NoError(t, err)
+	s.Start()
+
+	chainErr := rec.wait(t)
+	assert.False(t, uploaded, "шаг после ошибки не должен выполняться")
+
+	var stepErr *ChainStepError
+	require.ErrorAs(t, chainErr, &stepErr)
+	assert.Equal(t, "nightly", stepErr.Chain)
+	assert.Equal(t, "compress", stepErr.Step)
+	assert.ErrorIs(t, chainErr, errCompress)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	assert.ErrorIs(t, rec.errors["nightly/compress"], errCompress)
+	require.ErrorAs(t, rec.errors["nightly"], &stepErr)
+	assert.Equal(t, "compress", stepErr.Step)
+}
+
+func TestScheduler_ChainContinueOnError(t *testing.T) {
+	rec := newChainRecorder("pipeline")
+	s := New(Config{JobHooks: rec.hooks()})
+	defer s.Stop()
+
+	ran := false
+	_, err := s.AddChain("pipeline", []ChainStep{
+		{Job: func(ctx context.Context) error { return errors.New("optional step failed") }, OnError: ContinueChain},
+		{Job: func(ctx context.Context) error { ran = true; return nil }},
+	}, runOnce)
+	require.NoError(t, err)
+	s.Start()
+
+	require.NoError(t, rec.wait(t))
+	assert.True(t, ran)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	assert.Contains(t, rec.errors, "pipeline/1", "шаги без имени нумеруются с 1")
+	assert.NotContains(t, rec.errors, "pipeline")
+}
+
+func TestScheduler_ChainRetryStep(t *testing.T) {
+	rec := newChainRecorder("nightly")
+	s := New(Config{JobHooks: rec.hooks()})
+	defer s.Stop()
+
+	attempts := 0
+	_, err := s.AddChain("nightly", []ChainStep{{
+		Name: "upload",
+		Job: func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("connection reset")
+			}
+			return nil
+		},
+		OnError: RetryStep,
+		Retry:   &retry.Config{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	}}, runOnce)
+	require.NoError(t, err)
+	s.Start()
+
+	require.NoError(t, rec.wait(t))
+	assert.Equal(t, 3, attempts)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	assert.Equal(t, []string{"nightly/upload", "nightly/upload"}, rec.retries)
+}
+
+func TestScheduler_ChainRetryStepExhausted(t *testing.T) {
+	rec := newChainRecorder("nightly")
+	s := New(Config{JobHooks: rec.hooks()})
+	defer s.Stop()
+
+	_, err := s.AddChain("nightly", []ChainStep{{
+		Name:    "upload",
+		Job:     func(ctx context.Context) error { return errors.New("connection reset") },
+		OnError: RetryStep,
+		Retry:   &retry.Config{MaxAttempts: 2, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	}}, runOnce)
+	require.NoError(t, err)
+	s.Start()
+
+	var stepErr *ChainStepError
+	require.ErrorAs(t, rec.wait(t), &stepErr)
+	assert.Equal(t, "upload", stepErr.Step)
+	var exceeded *retry.RetriesExceededError
+	assert.ErrorAs(t, stepErr, &exceeded)
+}
+
+func TestScheduler_ChainStepTimeoutAndPanic(t *testing.T) {
+	rec := newChainRecorder("nightly")
+	s := New(Config{JobHooks: rec.hooks()})
+	defer s.Stop()
+
+	_, err := s.AddChain("nightly", []ChainStep{
+		{
+			Name: "slow",
+			Job: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			Timeout: 10 * time.Millisecond,
+			OnError: ContinueChain,
+		},
+		{Name: "broken", Job: func(ctx context.Context) error { panic("boom") }},
+	}, runOnce)
+	require.NoError(t, err)
+	s.Start()
+
+	var stepErr *ChainStepError
+	require.ErrorAs(t, rec.wait(t), &stepErr)
+	assert.Equal(t, "broken", stepErr.Step)
+	assert.Contains(t, stepErr.Error(), "panic: boom")
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	assert.ErrorIs(t, rec.errors["nightly/slow"], context.DeadlineExceeded)
+}
+
+func TestScheduler_RemoveChainCancelsInFlightStep(t *testing.T) {
+	rec := newChainRecorder("nightly")
+	s := New(Config{JobHooks: rec.hooks()})
+	defer s.Stop()
+
+	running := make(chan struct{})
+	id, err := s.AddChain("nightly", []ChainStep{
+		{Name: "export", Job: func(ctx context.Context) error {
+			close(running)
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+		{Name: "upload", Job: func(ctx context.Context) error {
+			t.Error("шаг после удаления цепочки не должен выполняться")
+			return nil
+		}},
+	}, runOnce)
+	require.NoError(t, err)
+	s.Start()
+
+	select {
+	case <-running:
+	case <-time.After(5 * time.Second):
+		t.Fatal("шаг не запустился")
+	}
+	assert.True(t, s.RemoveChain(id))
+	assert.False(t, s.RemoveChain(id))
+
+	assert.ErrorIs(t, rec.wait(t), context.Canceled)
+	assert.Empty(t, s.Jobs(), "задача цепочки должна быть удалена")
+}
+
+func TestScheduler_AddChainCron(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	id, err := s.AddChain("nightly", []ChainStep{
+		{Job: func(ctx context.Context) error { return nil }},
+	}, ChainOptions{Schedule: "0 0 3 * * *"})
+	require.NoError(t, err)
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "nightly", jobs[0].Name)
+	assert.Equal(t, JobTypeCron, jobs[0].Type)
+
+	assert.True(t, s.RemoveChain(id))
+	assert.Empty(t, s.Jobs())
+}
+
+func TestScheduler_AddChainValidation(t *testing.T) {
+	noop := func(ctx context.Context) error { return nil }
+	tests := []struct {
+		name  string
+		chain string
+		steps []ChainStep
+		opts  ChainOptions
+	}{
+		{name: "empty name", steps: []ChainStep{{Job: noop}}, opts: ChainOptions{Interval: time.Hour}},
+		{name: "no steps", chain: "c", opts: ChainOptions{Interval: time.Hour}},
+		{name: "nil job", chain: "c", steps: []ChainStep{{Name: "x"}}, opts: ChainOptions{Interval: time.Hour}},
+		{name: "no schedule", chain: "c", steps: []ChainStep{{Job: noop}}},
+		{name: "both schedules", chain: "c", steps: []ChainStep{{Job: noop}}, opts: ChainOptions{Schedule: "@daily", Interval: time.Hour}},
+	}
+
+	s := New(Config{})
+	defer s.Stop()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.AddChain(tt.chain, tt.steps, tt.opts)
+			assert.ErrorIs(t, err, ErrInvalidChain)
+		})
+	}
+
+	_, err := s.AddChain("c", []ChainStep{{Job: noop}}, ChainOptions{Schedule: "invalid"})
+	assert.Error(t, err)
+	assert.Empty(t, s.Jobs())
+}
@@ -3,16 +3,31 @@
 //
 // Features:
 //   - Cron-style scheduling using github.com/robfig/cron/v3
-//   - Simple interval-based jobs with time.Ticker
+//   - Simple interval-based jobs with jitter, initial delay and immediate first run
+//   - One-shot delayed jobs (RunOnceAfter/RunOnceAt) with cancellation
+//   - Job chains running dependent steps sequentially (AddChain)
 //   - Job overlap control policies (Allow/Skip/Delay)
+//   - Global limit of concurrently running jobs (Config.MaxConcurrentJobs)
+//   - Minimum interval between runs with optional trailing run (JobOptions.MinInterval)
 //   - Per-job timeouts and named jobs
+//   - Per-job retry policy via pkg/retry (JobOptions.Retry, OnJobRetry hook)
 //   - Job ID management with add/remove capabilities
+//   - Name-based addressing of jobs (AddNamedCronJob, RemoveJob, TriggerJob, JobByName)
+//   - Pause/resume of cron and ticker jobs preserving their IDs
+//   - Manual synchronous runs (TriggerCronJob, TriggerTickerJob) for admin commands
+//   - Misfire policy for cron runs missed while the scheduler was down
+//   - Cron specs with or without seconds field (Config.ScheduleFormat)
+//   - Cron schedule validation and next run preview (ValidateSchedule, NextOccurrences)
+//   - Job run history and introspection (Jobs, CronJobInfo, TickerJobInfo)
+//   - Health report of stuck and failing critical jobs with an HTTP handler (Health, HealthHandler)
+//   - Optional persistence of job definitions across restarts (Config.JobStore, RestoreJobs)
 //   - Parent context support for lifecycle management
 //   - Graceful shutdown with optional deadline (StopContext)
 //   - Idempotent Start/Stop operations
 //   - Error handling and panic recovery
 //   - Structured logging with slog integration
-//   - Optional hooks for observability
+//   - Optional hooks for observability, composable with JobHooks.Chain
+//   - Prometheus-style job metrics via NewMetricsHooks
 //
 // Basic usage:
 //
@@ -41,6 +56,40 @@
 //	scheduler.RemoveCronJob(cronID)
 //	scheduler.RemoveTickerJob(tickerID)
 //
+//	// Run a job once after a delay (e.g. retry a failed delivery)
+//	onceID := scheduler.RunOnceAfter(10*time.Minute, func(ctx context.Context) error {
+//		return nil
+//	}, JobOptions{Name: "redeliver"})
+//	scheduler.CancelOneShot(onceID)
+//
+// Jobs added with AddNamedCronJob or AddNamedTickerJob are addressed by name instead of ID,
+// e.g. from admin commands. Duplicate names are rejected with ErrJobNameTaken marked
+// shared.KindConflict; the name is released when the job is removed or the scheduler stops:
+//
+//	err := scheduler.AddNamedCronJob("daily-report", "0 0 9 * * *", sendReport, JobOptions{})
+//	scheduler.PauseJob("daily-report")
+//	err = scheduler.TriggerJob(ctx, "daily-report") // ErrJobNotFound for unknown names
+//	info, ok := scheduler.JobByName("daily-report")
+//	scheduler.RemoveJob("daily-report")
+//
+// Validate a schedule entered by an admin and preview its next runs before adding the job
+// (the method uses the same parser as AddCronJob, see Config.ScheduleFormat):
+//
+//	if err := scheduler.ValidateSchedule(spec); errors.Is(err, ErrUnsupportedDescriptor) {
+//		// unknown "@name"; other parse errors match ErrInvalidSchedule
+//	}
+//	next, err := NextOccurrences(spec, time.Now(), 5, nil) // nil - time.Local, as the scheduler
+//
+// Schedule formats (Config.ScheduleFormat) apply to AddCronJob, AddChain and
+// ValidateSchedule alike; descriptors ("@hourly", "@every 5m") are accepted by all:
+//   - SecondsOptional (default): "30 9 * * 1" runs at 09:30:00, "0 30 9 * * 1" is the same
+//   - SecondsRequired: only six fields, "30 9 * * 1" is rejected
+//   - Standard5Field: only five fields as in crontab
+//
+// Parse errors state the expected format: `scheduler: invalid cron schedule "30 9 * * 1"
+// (expected 6 fields with seconds): ...`. The package-level ValidateSchedule and
+// NextOccurrences use SecondsOptional.
+//
 // Advanced usage with parent context and hooks:
 //
 //	hooks := JobHooks{
@@ -57,10 +106,135 @@
 //		JobHooks: hooks,
 //	})
 //
+// Run context:
+//
+// Each run gets a UUID shared by all its retry attempts and hooks. The job reads it
+// together with its name and a logger with job and run_id attributes from the context;
+// hooks with the Ctx suffix (OnJobStartCtx, OnJobFinishCtx, ...) receive the same context:
+//
+//	func(ctx context.Context) error {
+//		runID, _ := RunIDFrom(ctx)
+//		LoggerFrom(ctx).Info("sending digest") // job=digest run_id=...
+//		return nil
+//	}
+//
+// Health check:
+//
+// Health reports per-job last success, last error, consecutive failures and whether
+// a run is stuck (longer than JobOptions.StuckThreshold, by default StuckTimeoutFactor
+// times Timeout). Only jobs with JobOptions.Critical affect HealthReport.Healthy;
+// a job that has not run yet counts as failing only after its first expected run
+// plus HealthStartGrace. HealthHandler serves the report as JSON with 200 or 503:
+//
+//	scheduler.AddCronJobWithOptions("0 */5 * * * *", syncPayments, JobOptions{
+//		Name:     "sync-payments",
+//		Timeout:  time.Minute,
+//		Critical: true,
+//	})
+//	mux.Handle("/health/scheduler", scheduler.HealthHandler())
+//
+// Metrics:
+//
+// NewMetricsHooks maintains jobs_started_total, jobs_failed_total, jobs_skipped_total
+// and job_duration_seconds labeled by job name through a small MetricsRegistrar
+// interface, so the scheduler does not depend on a metrics library:
+//
+//	scheduler := New(Config{
+//		JobHooks: hooks.Chain(NewMetricsHooks(registrar)),
+//	})
+//
 // Overlap policies:
 //   - AllowOverlap: Jobs can run concurrently (default)
 //   - SkipIfRunning: Skip execution if previous run is still active
 //   - DelayIfRunning: Wait for previous run to finish before starting
+//     (at most JobOptions.MaxQueuedRuns pending runs, extra runs are dropped)
+//
+// Skipped and dropped runs are reported through JobHooks.OnJobSkipped.
+//
+// Runs still waiting in the DelayIfRunning queue when shutdown begins are dropped
+// by default (Config.DrainPolicy = DropPending); with RunPending they execute
+// before Stop returns, receiving the already canceled scheduler context. Queued
+// runs of a removed ticker or one-shot job are always dropped.
+//
+// Throttling:
+//
+// JobOptions.MinInterval limits how often a job runs regardless of what triggers it:
+// scheduled ticks and TriggerCronJob/TriggerTickerJob calls share the time of the
+// last finished run. Earlier runs are skipped with ErrJobThrottled and reported
+// through JobHooks.OnJobSkipped (SkipReasonThrottled). With JobOptions.TrailingRun
+// they are deferred instead: one pending run executes when the interval elapses and
+// further requests are merged into it:
+//
+//	id, err := scheduler.AddCronJobWithOptions("0 0 * * * *", sendDigest, JobOptions{
+//		Name:        "digest",
+//		MinInterval: 5 * time.Minute,
+//		TrailingRun: true,
+//	})
+//
+// Concurrency limit:
+//
+// Config.MaxConcurrentJobs caps the number of jobs of all kinds running at once
+// (0 means unlimited). Runs over the limit wait for a free slot; waiting is aborted
+// on shutdown. With JobOptions.AcquireTimeout set, a run that could not get a slot
+// in time is skipped with ErrConcurrencyLimit and reported through
+// JobHooks.OnJobSkipped (SkipReasonConcurrencyLimit) only, like other skipped runs:
+//
+//	scheduler := New(Config{Logger: logger, MaxConcurrentJobs: 3})
+//	scheduler.AddTickerJobWithOptions(time.Minute, syncUsers, JobOptions{
+//		Name:           "sync-users",
+//		AcquireTimeout: 10 * time.Second,
+//	})
+//
+// Job chains:
+//
+// AddChain schedules dependent steps as one cron or ticker job. Steps run
+// sequentially in one goroutine; hooks fire for the chain and for each step
+// named "chain/step". A failed StopChain or RetryStep step ends the run with
+// *ChainStepError naming the step; ContinueChain moves on to the next step.
+// RemoveChain cancels the context of the step in flight:
+//
+//	chainID, err := scheduler.AddChain("nightly", []ChainStep{
+//		{Name: "export", Job: exportData, Timeout: 30 * time.Minute},
+//		{Name: "compress", Job: compress},
+//		{Name: "upload", Job: upload, OnError: RetryStep, Retry: &retryCfg},
+//	}, ChainOptions{Schedule: "0 0 3 * * *", Job: JobOptions{OverlapPolicy: SkipIfRunning}})
+//
+// Job persistence:
+//
+// With Config.JobStore set, cron and ticker jobs added with JobOptions.Key are saved
+// to the store and deleted from it by RemoveCronJob/RemoveTickerJob (not by Stop).
+// Functions cannot be serialized, so RestoreJobs looks them up by key at startup.
+// Only StoredJobOptions are persisted (no Retry, RetryIf or LastRun); chains are not
+// persisted. the sqlitestore subpackage provides a ready-made SQLite JobStore:
+//
+//	store, err := sqlitestore.New(ctx, runner) // runner is *sqlite.TxRunner
+//	scheduler := New(Config{Logger: logger, JobStore: store})
+//	err = scheduler.RestoreJobs(func(key string) JobFunc {
+//		if chatID, ok := strings.CutPrefix(key, "reminder:"); ok {
+//			return reminderJob(chatID)
+//		}
+//		return nil // unknown keys are reported with ErrUnknownJobKey and kept in the store
+//	})
+//
+//	// Later, at runtime
+//	scheduler.AddTickerJobWithOptions(time.Hour, reminderJob(chatID), JobOptions{
+//		Name: "reminder",
+//		Key:  "reminder:" + chatID,
+//	})
+//
+// Misfire policies (cron jobs only, require JobOptions.LastRun):
+//   - MisfireIgnore: Missed runs are not executed (default)
+//   - MisfireRunOnceOnStart: Run once at Start if any run was missed since LastRun
+//   - MisfireCatchUpAll: Run once per missed occurrence, at most JobOptions.MaxCatchUp times
+//
+// LastRun is supplied by the caller from its own storage, typically persisted in
+// JobHooks.OnJobFinish:
+//
+//	scheduler.AddCronJobWithOptions("@daily", cleanup, JobOptions{
+//		Name:    "cleanup",
+//		Misfire: MisfireRunOnceOnStart,
+//		LastRun: lastCleanupAt,
+//	})
 //
 // Cron schedule examples:
 //   - "@hourly" - every hour
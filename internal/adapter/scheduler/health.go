@@ -0,0 +1,200 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const (
+	// StuckTimeoutFactor - во сколько раз выполнение должно превысить JobOptions.Timeout,
+	// чтобы задача считалась зависшей (если StuckThreshold не задан).
+	StuckTimeoutFactor = 3
+	// HealthStartGrace - запас после первого ожидаемого запуска, в течение которого
+	// ещё не запускавшаяся задача не считается проблемной.
+	HealthStartGrace = time.Minute
+)
+
+// Причины, по которым задача считается нездоровой (JobHealth.Reason).
+const (
+	HealthReasonStuck    = "stuck"
+	HealthReasonFailing  = "failing"
+	HealthReasonNeverRan = "never_ran"
+)
+
+// HealthReport содержит состояние всех cron- и ticker-задач.
+type HealthReport struct {
+	// Healthy - все задачи с JobOptions.Critical здоровы.
+	Healthy bool `json:"healthy"`
+	// CheckedAt - время формирования отчёта.
+	CheckedAt time.Time `json:"checked_at"`
+	// Jobs - состояние задач в порядке Scheduler.Jobs.
+	Jobs []JobHealth `json:"jobs"`
+}
+
+// JobHealth содержит состояние одной задачи в HealthReport.
+type JobHealth struct {
+	ID       int     `json:"id"`
+	Type     JobType `json:"type"`
+	Name     string  `json:"name"`
+	Critical bool    `json:"critical"`
+	// Healthy - задача не зависла, последнее выполнение успешно и первый
+	// ожидаемый запуск (с запасом HealthStartGrace) не пропущен.
+	Healthy bool `json:"healthy"`
+	// Reason - причина нездорового состояния (пусто, если Healthy).
+	Reason string `json:"reason,omitempty"`
+	// LastSuccessAt - время завершения последнего успешного выполнения.
+	LastSuccessAt time.Time `json:"last_success_at,omitzero"`
+	// LastError - ошибка последнего выполнения, включая панику.
+	LastError string `json:"last_error,omitempty"`
+	// ConsecutiveFailures - количество неудачных выполнений подряд.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+	// Running - задача выполняется сейчас.
+	Running bool `json:"running"`
+	// RunningSince - начало самого долгого текущего выполнения.
+	RunningSince time.Time `json:"running_since,omitzero"`
+	// Stuck - текущее выполнение длится дольше порога (см. JobOptions.StuckThreshold).
+	Stuck bool `json:"stuck"`
+	// NextRunAt - ожидаемое время следующего запуска (нулевое, если неизвестно).
+	NextRunAt time.Time `json:"next_run_at,omitzero"`
+	// Paused - задача приостановлена.
+	Paused bool `json:"paused"`
+}
+
+// Health возвращает состояние задач для health check.
+// Задачи без JobOptions.Critical попадают в отчёт, но не влияют на HealthReport.Healthy.
+func (s *Scheduler) Health() HealthReport {
+	return s.health(time.Now())
+}
+
+// HealthHandler возвращает http.Handler, отдающий Health в JSON
+// со статусом 200, если планировщик здоров, и 503 иначе.
+func (s *Scheduler) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		report := s.Health()
+		status := http.StatusOK
+		if !report.Healthy {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			s.logger.Warn("failed to write health report", "error", err)
+		}
+	})
+}
+
+// health формирует отчёт на момент now.
+func (s *Scheduler) health(now time.Time) HealthReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := HealthReport{
+		Healthy:   true,
+		CheckedAt: now,
+		Jobs:      make([]JobHealth, 0, len(s.cronJobs)+len(s.tickerJobs)),
+	}
+	for _, job := range s.cronJobs {
+		var firstRun time.Time
+		if !s.startedAt.IsZero() {
+			from := s.startedAt
+			if job.wrapper.addedAt.After(from) {
+				from = job.wrapper.addedAt
+			}
+			if entry := s.cron.Entry(job.id); entry.Schedule != nil {
+				firstRun = entry.Schedule.Next(from)
+			}
+		}
+		h := job.wrapper.health(now, firstRun)
+		h.ID = int(job.id)
+		h.Type = JobTypeCron
+		h.NextRunAt = s.cron.Entry(job.id).Next
+		report.Jobs = append(report.Jobs, h)
+	}
+	for _, job := range s.tickerJobs {
+		h := job.wrapper.health(now, s.tickerFirstRun(job))
+		h.ID = int(job.id)
+		h.Type = JobTypeTicker
+		report.Jobs = append(report.Jobs, h)
+	}
+
+	sort.Slice(report.Jobs, func(i, j int) bool {
+		if report.Jobs[i].Type != report.Jobs[j].Type {
+			return report.Jobs[i].Type == JobTypeCron
+		}
+		return report.Jobs[i].ID < report.Jobs[j].ID
+	})
+	for _, h := range report.Jobs {
+		if h.Critical && !h.Healthy {
+			report.Healthy = false
+		}
+	}
+	return report
+}
+
+// tickerFirstRun возвращает время первого ожидаемого запуска ticker-задачи
+// (нулевое, если запуск ещё не ожидается). Вызывается под s.mu.
+func (s *Scheduler) tickerFirstRun(job *tickerJob) time.Time {
+	opts := job.wrapper.options
+	if opts.RunImmediately {
+		if s.startedAt.IsZero() {
+			return time.Time{}
+		}
+		if job.wrapper.addedAt.After(s.startedAt) {
+			return job.wrapper.addedAt
+		}
+		return s.startedAt
+	}
+	first := job.interval
+	if opts.InitialDelay > 0 {
+		first = opts.InitialDelay
+	}
+	return job.wrapper.addedAt.Add(first)
+}
+
+// health собирает JobHealth без ID, Type и NextRunAt.
+// firstRun - время первого ожидаемого запуска (нулевое, если неизвестно).
+func (w *jobWrapper) health(now, firstRun time.Time) JobHealth {
+	w.statsMu.Lock()
+	defer w.statsMu.Unlock()
+
+	h := JobHealth{
+		Name:                w.options.Name,
+		Critical:            w.options.Critical,
+		LastSuccessAt:       w.stats.lastSuccessAt,
+		ConsecutiveFailures: w.stats.consecutiveFailures,
+		NextRunAt:           w.stats.nextRunAt,
+		Paused:              w.paused.Load(),
+	}
+	if h.Name == "" {
+		h.Name = unnamedJob
+	}
+	if w.stats.lastError != nil {
+		h.LastError = w.stats.lastError.Error()
+	}
+	for _, start := range w.stats.runningStarts {
+		if h.RunningSince.IsZero() || start.Before(h.RunningSince) {
+			h.RunningSince = start
+		}
+	}
+	h.Running = !h.RunningSince.IsZero()
+
+	threshold := w.options.StuckThreshold
+	if threshold <= 0 {
+		threshold = StuckTimeoutFactor * w.options.Timeout
+	}
+	h.Stuck = h.Running && threshold > 0 && now.Sub(h.RunningSince) > threshold
+
+	switch {
+	case h.Stuck:
+		h.Reason = HealthReasonStuck
+	case h.ConsecutiveFailures > 0:
+		h.Reason = HealthReasonFailing
+	case w.stats.runCount == 0 && !h.Running && !h.Paused &&
+		!firstRun.IsZero() && now.After(firstRun.Add(HealthStartGrace)):
+		h.Reason = HealthReasonNeverRan
+	}
+	h.Healthy = h.Reason == ""
+	return h
+}
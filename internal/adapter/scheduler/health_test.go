@@ -0,0 +1,206 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_HealthPanicIsFailure(t *testing.T) {
+	s := New(Config{})
+	var runCount int64
+	s.AddTickerJobWithOptions(20*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		panic("boom")
+	}, JobOptions{Name: "panicky", Critical: true})
+
+	s.Start()
+	waitForAtLeast(t, &runCount, 2, time.Second)
+	s.Stop()
+
+	report := s.Health()
+	assert.False(t, report.Healthy)
+	require.Len(t, report.Jobs, 1)
+	job := report.Jobs[0]
+	assert.Equal(t, "panicky", job.Name)
+	assert.Equal(t, JobTypeTicker, job.Type)
+	assert.False(t, job.Healthy)
+	assert.Equal(t, HealthReasonFailing, job.Reason)
+	assert.GreaterOrEqual(t, job.ConsecutiveFailures, 2)
+	assert.Contains(t, job.LastError, "boom")
+	assert.True(t, job.LastSuccessAt.IsZero())
+	assert.False(t, job.Running)
+}
+
+func TestScheduler_HealthRecovers(t *testing.T) {
+	s := New(Config{})
+	var runCount int64
+	s.AddTickerJobWithOptions(20*time.Millisecond, func(ctx context.Context) error {
+		if atomic.AddInt64(&runCount, 1) == 1 {
+			return errors.New("first run fails")
+		}
+		return nil
+	}, JobOptions{Name: "flaky", Critical: true})
+
+	s.Start()
+	waitForAtLeast(t, &runCount, 2, time.Second)
+	s.Stop()
+
+	report := s.Health()
+	assert.True(t, report.Healthy)
+	job := report.Jobs[0]
+	assert.Zero(t, job.ConsecutiveFailures, "успешный запуск сбрасывает счётчик")
+	assert.False(t, job.LastSuccessAt.IsZero())
+}
+
+func TestScheduler_HealthStuck(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	release := make(chan struct{})
+	defer close(release)
+	var runCount int64
+	s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		<-release
+		return nil
+	}, JobOptions{Name: "hanging", Critical: true, RunImmediately: true, StuckThreshold: 30 * time.Millisecond})
+
+	s.Start()
+	waitForAtLeast(t, &runCount, 1, time.Second)
+
+	job := s.Health().Jobs[0]
+	assert.True(t, job.Running)
+	assert.False(t, job.RunningSince.IsZero())
+	assert.False(t, job.Stuck, "порог ещё не превышен")
+
+	require.Eventually(t, func() bool {
+		return s.Health().Jobs[0].Stuck
+	}, time.Second, 10*time.Millisecond)
+	report := s.Health()
+	assert.False(t, report.Healthy)
+	assert.Equal(t, HealthReasonStuck, report.Jobs[0].Reason)
+}
+
+func TestScheduler_HealthStuckByTimeout(t *testing.T) {
+	w := &jobWrapper{options: JobOptions{Timeout: time.Second}}
+	start := time.Now()
+	w.markRunning(start)
+
+	assert.False(t, w.health(start.Add(StuckTimeoutFactor*time.Second), time.Time{}).Stuck)
+	assert.True(t, w.health(start.Add(StuckTimeoutFactor*time.Second+time.Millisecond), time.Time{}).Stuck)
+
+	w.recordRun(start, 4*time.Second, nil)
+	h := w.health(start.Add(time.Hour), time.Time{})
+	assert.False(t, h.Running)
+	assert.False(t, h.Stuck)
+	assert.True(t, h.Healthy)
+
+	noTimeout := &jobWrapper{}
+	noTimeout.markRunning(start)
+	assert.False(t, noTimeout.health(start.Add(24*time.Hour), time.Time{}).Stuck,
+		"без Timeout и StuckThreshold зависание не определяется")
+}
+
+func TestScheduler_HealthNeverRan(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	_, err := s.AddCronJobWithOptions("@hourly", func(ctx context.Context) error {
+		return nil
+	}, JobOptions{Name: "hourly", Critical: true})
+	require.NoError(t, err)
+
+	// До Start первый запуск не ожидается
+	assert.True(t, s.health(time.Now().Add(24*time.Hour)).Healthy)
+
+	s.Start()
+	report := s.Health()
+	assert.True(t, report.Healthy, "первый запуск ещё не наступил")
+	assert.False(t, report.Jobs[0].NextRunAt.IsZero())
+
+	late := s.health(time.Now().Add(time.Hour + HealthStartGrace + time.Second))
+	assert.False(t, late.Healthy, "первый ожидаемый запуск пропущен")
+	assert.Equal(t, HealthReasonNeverRan, late.Jobs[0].Reason)
+}
+
+func TestScheduler_HealthNeverRanTicker(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error {
+		return nil
+	}, JobOptions{Critical: true, InitialDelay: time.Minute})
+
+	assert.True(t, s.health(time.Now().Add(time.Minute)).Healthy)
+	late := s.health(time.Now().Add(time.Minute + HealthStartGrace + time.Second))
+	assert.False(t, late.Healthy)
+	assert.Equal(t, unnamedJob, late.Jobs[0].Name)
+}
+
+func TestScheduler_HealthIgnoresNonCritical(t *testing.T) {
+	s := New(Config{})
+	var runCount int64
+	s.AddTickerJobWithOptions(20*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return errors.New("boom")
+	}, JobOptions{Name: "optional"})
+
+	s.Start()
+	waitForAtLeast(t, &runCount, 1, time.Second)
+	s.Stop()
+
+	report := s.Health()
+	assert.True(t, report.Healthy)
+	assert.False(t, report.Jobs[0].Healthy)
+}
+
+func TestScheduler_HealthHandler(t *testing.T) {
+	s := New(Config{})
+	var runCount int64
+	var fail atomic.Bool
+	s.AddTickerJobWithOptions(20*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		if fail.Load() {
+			return errors.New("boom")
+		}
+		return nil
+	}, JobOptions{Name: "sync", Critical: true})
+	s.Start()
+	defer s.Stop()
+	waitForAtLeast(t, &runCount, 1, time.Second)
+
+	serve := func() (*httptest.ResponseRecorder, HealthReport) {
+		rec := httptest.NewRecorder()
+		s.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		var report HealthReport
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+		return rec, report
+	}
+
+	rec, report := serve()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.True(t, report.Healthy)
+	require.Len(t, report.Jobs, 1)
+	assert.Equal(t, "sync", report.Jobs[0].Name)
+
+	fail.Store(true)
+	require.Eventually(t, func() bool {
+		return !s.Health().Healthy
+	}, time.Second, 10*time.Millisecond)
+
+	rec, report = serve()
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.False(t, report.Healthy)
+	assert.Equal(t, "boom", report.Jobs[0].LastError)
+	assert.Contains(t, rec.Body.String(), `"consecutive_failures"`)
+}
@@ -0,0 +1,185 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+)
+
+// JobType определяет тип задачи планировщика.
+type JobType string
+
+const (
+	// JobTypeCron - задача по cron-расписанию.
+	JobTypeCron JobType = "cron"
+	// JobTypeTicker - задача с фиксированным интервалом.
+	JobTypeTicker JobType = "ticker"
+)
+
+// JobInfo содержит снимок состояния задачи и историю её выполнения.
+type JobInfo struct {
+	// ID - идентификатор задачи (CronJobID или TickerJobID в зависимости от Type).
+	ID int
+	// Name - имя задачи из JobOptions.
+	Name string
+	// Key - ключ задачи для JobStore из JobOptions.
+	Key string
+	// Type - тип задачи.
+	Type JobType
+	// Schedule - cron-расписание (только для cron-задач).
+	Schedule string
+	// Interval - интервал запуска (только для ticker-задач).
+	Interval time.Duration
+	// LastRunAt - время начала последнего выполнения (нулевое, если задача не запускалась).
+	LastRunAt time.Time
+	// LastDuration - длительность последнего выполнения.
+	LastDuration time.Duration
+	// LastError - ошибка последнего выполнения (nil при успехе).
+	LastError error
+	// NextRunAt - ожидаемое время следующего запуска (нулевое, если неизвестно).
+	NextRunAt time.Time
+	// RunCount - количество выполнений.
+	RunCount int64
+	// ErrorCount - количество выполнений, завершившихся ошибкой или паникой.
+	ErrorCount int64
+	// Paused - задача приостановлена.
+	Paused bool
+}
+
+// jobStats содержит историю выполнения задачи.
+type jobStats struct {
+	lastRunAt           time.Time
+	lastDuration        time.Duration
+	lastError           error
+	nextRunAt           time.Time
+	runCount            int64
+	errorCount          int64
+	lastSuccessAt       time.Time
+	consecutiveFailures int
+	// runningStarts - времена начала незавершённых выполнений
+	runningStarts []time.Time
+}
+
+// markRunning отмечает начало выполнения задачи; завершение отмечает recordRun.
+func (w *jobWrapper) markRunning(start time.Time) {
+	w.statsMu.Lock()
+	defer w.statsMu.Unlock()
+
+	w.stats.runningStarts = append(w.stats.runningStarts, start)
+}
+
+// recordRun сохраняет результат выполнения задачи.
+func (w *jobWrapper) recordRun(start time.Time, duration time.Duration, err error) {
+	w.statsMu.Lock()
+	defer w.statsMu.Unlock()
+
+	w.stats.lastRunAt = start
+	w.stats.lastDuration = duration
+	w.stats.lastError = err
+	w.stats.runCount++
+	if err != nil {
+		w.stats.errorCount++
+		w.stats.consecutiveFailures++
+	} else {
+		w.stats.lastSuccessAt = start.Add(duration)
+		w.stats.consecutiveFailures = 0
+	}
+	for i, t := range w.stats.runningStarts {
+		if t.Equal(start) {
+			w.stats.runningStarts = append(w.stats.runningStarts[:i], w.stats.runningStarts[i+1:]...)
+			break
+		}
+	}
+}
+
+// setNextRun сохраняет ожидаемое время следующего запуска.
+func (w *jobWrapper) setNextRun(t time.Time) {
+	w.statsMu.Lock()
+	defer w.statsMu.Unlock()
+
+	w.stats.nextRunAt = t
+}
+
+// info возвращает копию состояния задачи.
+func (w *jobWrapper) info() JobInfo {
+	w.statsMu.Lock()
+	defer w.statsMu.Unlock()
+
+	return JobInfo{
+		Name:         w.options.Name,
+		Key:          w.options.Key,
+		LastRunAt:    w.stats.lastRunAt,
+		LastDuration: w.stats.lastDuration,
+		LastError:    w.stats.lastError,
+		NextRunAt:    w.stats.nextRunAt,
+		RunCount:     w.stats.runCount,
+		ErrorCount:   w.stats.errorCount,
+		Paused:       w.paused.Load(),
+	}
+}
+
+// Jobs возвращает состояние всех cron- и ticker-задач.
+// Сначала идут cron-задачи, затем ticker-задачи, каждая группа упорядочена по ID.
+// Возвращаемый срез является копией и безопасен для чтения после Stop.
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]JobInfo, 0, len(s.cronJobs)+len(s.tickerJobs))
+	for _, job := range s.cronJobs {
+		jobs = append(jobs, s.cronJobInfo(job))
+	}
+	for _, job := range s.tickerJobs {
+		jobs = append(jobs, tickerJobInfo(job))
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].Type != jobs[j].Type {
+			return jobs[i].Type == JobTypeCron
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+	return jobs
+}
+
+// CronJobInfo возвращает состояние cron-задачи по ID.
+func (s *Scheduler) CronJobInfo(id CronJobID) (JobInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.cronJobs[id]
+	if !exists {
+		return JobInfo{}, false
+	}
+	return s.cronJobInfo(job), true
+}
+
+// TickerJobInfo возвращает состояние ticker-задачи по ID.
+func (s *Scheduler) TickerJobInfo(id TickerJobID) (JobInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.tickerJobs[id]
+	if !exists {
+		return JobInfo{}, false
+	}
+	return tickerJobInfo(job), true
+}
+
+// cronJobInfo собирает JobInfo для cron-задачи.
+func (s *Scheduler) cronJobInfo(job *cronJob) JobInfo {
+	info := job.wrapper.info()
+	info.ID = int(job.id)
+	info.Type = JobTypeCron
+	info.Schedule = job.schedule
+	info.NextRunAt = s.cron.Entry(job.id).Next
+	return info
+}
+
+// tickerJobInfo собирает JobInfo для ticker-задачи.
+func tickerJobInfo(job *tickerJob) JobInfo {
+	info := job.wrapper.info()
+	info.ID = int(job.id)
+	info.Type = JobTypeTicker
+	info.Interval = job.interval
+	return info
+}
@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Jobs(t *testing.T) {
+	s := New(Config{})
+
+	cronID, err := s.AddCronJobWithOptions("@every 1h", func(ctx context.Context) error {
+		return nil
+	}, JobOptions{Name: "hourly"})
+	require.NoError(t, err)
+
+	var runCount int64
+	jobErr := errors.New("boom")
+	tickerID := s.AddTickerJobWithOptions(30*time.Millisecond, func(ctx context.Context) error {
+		if atomic.AddInt64(&runCount, 1)%2 == 0 {
+			return jobErr
+		}
+		return nil
+	}, JobOptions{Name: "ticker"})
+
+	s.Start()
+	waitForAtLeast(t, &runCount, 2, time.Second)
+	s.Stop()
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 2)
+
+	cronInfo := jobs[0]
+	assert.Equal(t, int(cronID), cronInfo.ID)
+	assert.Equal(t, JobTypeCron, cronInfo.Type)
+	assert.Equal(t, "hourly", cronInfo.Name)
+	assert.Equal(t, "@every 1h", cronInfo.Schedule)
+	assert.Zero(t, cronInfo.RunCount, "cron-задача ещё не должна была выполниться")
+	assert.True(t, cronInfo.LastRunAt.IsZero())
+
+	tickerInfo := jobs[1]
+	assert.Equal(t, int(tickerID), tickerInfo.ID)
+	assert.Equal(t, JobTypeTicker, tickerInfo.Type)
+	assert.Equal(t, 30*time.Millisecond, tickerInfo.Interval)
+	assert.Equal(t, atomic.LoadInt64(&runCount), tickerInfo.RunCount)
+	assert.GreaterOrEqual(t, tickerInfo.ErrorCount, int64(1))
+	assert.False(t, tickerInfo.LastRunAt.IsZero())
+	assert.True(t, tickerInfo.NextRunAt.After(tickerInfo.LastRunAt))
+}
+
+func TestScheduler_CronJobInfoNextRun(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	id, err := s.AddCronJob("@every 1h", func(ctx context.Context) error {
+		return nil
+	})
+	require.NoError(t, err)
+	s.Start()
+
+	info, ok := s.CronJobInfo(id)
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), info.NextRunAt, time.Minute)
+
+	s.RemoveCronJob(id)
+	_, ok = s.CronJobInfo(id)
+	assert.False(t, ok, "удалённая задача не должна находиться")
+}
+
+func TestScheduler_TickerJobInfoPanic(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runCount int64
+	id := s.AddTickerJob(30*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		panic("test panic")
+	})
+	s.Start()
+
+	require.Eventually(t, func() bool {
+		info, ok := s.TickerJobInfo(id)
+		return ok && info.ErrorCount >= 1
+	}, time.Second, 10*time.Millisecond, "паника должна учитываться как ошибка")
+
+	info, ok := s.TickerJobInfo(id)
+	require.True(t, ok)
+	assert.ErrorContains(t, info.LastError, "panic")
+
+	_, ok = s.TickerJobInfo(999)
+	assert.False(t, ok)
+}
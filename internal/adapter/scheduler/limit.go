@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrConcurrencyLimit - выполнение пропущено, так как за JobOptions.AcquireTimeout
+// не освободился слот глобального лимита Config.MaxConcurrentJobs.
+var ErrConcurrencyLimit = errors.New("scheduler: concurrency limit reached")
+
+// acquireSlot занимает слот глобального лимита одновременных задач.
+// Возвращает функцию освобождения слота. Ожидание прерывается отменой parent
+// (остановка планировщика) и, если задан, JobOptions.AcquireTimeout.
+func (s *Scheduler) acquireSlot(parent context.Context, wrapper *jobWrapper) (func(), error) {
+	if s.slots == nil {
+		return func() {}, nil
+	}
+
+	// Быстрый путь без создания контекста с таймаутом
+	if s.slots.TryAcquire(1) {
+		return func() { s.slots.Release(1) }, nil
+	}
+
+	ctx := parent
+	timeout := wrapper.options.AcquireTimeout
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parent, timeout)
+		defer cancel()
+	}
+
+	if err := s.slots.Acquire(ctx, 1); err != nil {
+		if parentErr := parent.Err(); parentErr != nil {
+			return nil, parentErr
+		}
+		return nil, fmt.Errorf("%w: no free slot within %s (max %d concurrent jobs)",
+			ErrConcurrencyLimit, timeout, s.maxConcurrent)
+	}
+	return func() { s.slots.Release(1) }, nil
+}
@@ -0,0 +1,168 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// concurrencyTracker считает одновременно выполняемые задачи и запоминает максимум.
+type concurrencyTracker struct {
+	current atomic.Int64
+	max     atomic.Int64
+	runs    int64
+}
+
+func (c *concurrencyTracker) job(d time.Duration) JobFunc {
+	return func(ctx context.Context) error {
+		n := c.current.Add(1)
+		defer c.current.Add(-1)
+		for {
+			m := c.max.Load()
+			if n <= m || c.max.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		atomic.AddInt64(&c.runs, 1)
+		time.Sleep(d)
+		return nil
+	}
+}
+
+func TestScheduler_MaxConcurrentJobs(t *testing.T) {
+	s := New(Config{MaxConcurrentJobs: 2})
+	defer s.Stop()
+
+	var tracker concurrencyTracker
+	for range 6 {
+		s.AddTickerJobWithOptions(5*time.Millisecond, tracker.job(20*time.Millisecond), JobOptions{Name: "worker"})
+	}
+	_, err := s.AddCronJob("@every 1s", tracker.job(20*time.Millisecond))
+	require.NoError(t, err)
+	s.Start()
+
+	waitForAtLeast(t, &tracker.runs, 20, 5*time.Second)
+	s.Stop()
+
+	assert.LessOrEqual(t, tracker.max.Load(), int64(2), "лимит одновременных задач превышен")
+	assert.Equal(t, int64(2), tracker.max.Load(), "лимит должен использоваться полностью")
+}
+
+func TestScheduler_MaxConcurrentJobsUnlimitedByDefault(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var tracker concurrencyTracker
+	wrapper := &jobWrapper{job: tracker.job(50 * time.Millisecond)}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runJobWrapper(wrapper)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(4), tracker.max.Load())
+}
+
+func TestScheduler_AcquireTimeout(t *testing.T) {
+	var skipReasons []string
+	var jobErrors []error
+	var mu sync.Mutex
+	s := New(Config{MaxConcurrentJobs: 1, JobHooks: JobHooks{
+		OnJobSkipped: func(jobName, reason string) {
+			mu.Lock()
+			defer mu.Unlock()
+			skipReasons = append(skipReasons, reason)
+		},
+		OnJobError: func(jobName string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			jobErrors = append(jobErrors, err)
+		},
+	}})
+	defer s.Stop()
+
+	release := make(chan struct{})
+	var started int64
+	blocker := &jobWrapper{job: func(ctx context.Context) error {
+		atomic.AddInt64(&started, 1)
+		<-release
+		return nil
+	}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.runJobWrapper(blocker)
+	}()
+	waitForAtLeast(t, &started, 1, time.Second)
+
+	var ran atomic.Bool
+	waiter := &jobWrapper{
+		job: func(ctx context.Context) error {
+			ran.Store(true)
+			return nil
+		},
+		options: JobOptions{Name: "waiter", AcquireTimeout: 30 * time.Millisecond},
+	}
+	err := s.runJob(s.ctx, waiter, false)
+	require.ErrorIs(t, err, ErrConcurrencyLimit)
+	assert.Contains(t, err.Error(), "30ms")
+	assert.False(t, ran.Load())
+
+	close(release)
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{SkipReasonConcurrencyLimit}, skipReasons)
+	assert.Empty(t, jobErrors, "skipped run is not a job error")
+
+	// После освобождения слота задача выполняется
+	require.NoError(t, s.runJob(s.ctx, waiter, false))
+	assert.True(t, ran.Load())
+}
+
+func TestScheduler_WaitingForSlotAbortsOnStop(t *testing.T) {
+	var skipped int64
+	s := New(Config{MaxConcurrentJobs: 1, JobHooks: JobHooks{
+		OnJobSkipped: func(string, string) { atomic.AddInt64(&skipped, 1) },
+	}})
+
+	release := make(chan struct{})
+	defer close(release)
+	var started int64
+	blocker := &jobWrapper{job: func(ctx context.Context) error {
+		atomic.AddInt64(&started, 1)
+		<-release
+		return nil
+	}}
+	go s.runJobWrapper(blocker)
+	waitForAtLeast(t, &started, 1, time.Second)
+
+	var ran atomic.Bool
+	waiter := &jobWrapper{job: func(ctx context.Context) error {
+		ran.Store(true)
+		return nil
+	}}
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.runJob(s.ctx, waiter, false) }()
+
+	s.Stop()
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("ожидание слота не прервано остановкой планировщика")
+	}
+	assert.False(t, ran.Load())
+	assert.Zero(t, atomic.LoadInt64(&skipped), "прерывание при остановке не считается пропуском")
+}
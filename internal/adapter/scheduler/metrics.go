@@ -0,0 +1,160 @@
+package scheduler
+
+import "time"
+
+// Имена метрик, создаваемых NewMetricsHooks. У всех метрик одна метка MetricLabelJob.
+const (
+	// MetricJobsStarted - количество начатых выполнений.
+	MetricJobsStarted = "jobs_started_total"
+	// MetricJobsFailed - количество выполнений, завершившихся ошибкой или паникой.
+	MetricJobsFailed = "jobs_failed_total"
+	// MetricJobsSkipped - количество пропущенных выполнений (см. JobHooks.OnJobSkipped).
+	MetricJobsSkipped = "jobs_skipped_total"
+	// MetricJobDuration - длительность выполнения в секундах.
+	MetricJobDuration = "job_duration_seconds"
+	// MetricLabelJob - метка с именем задачи.
+	MetricLabelJob = "job"
+)
+
+// unnamedJob - имя задачи без JobOptions.Name в логах, хуках и метриках.
+const unnamedJob = "unnamed"
+
+// Counter - счётчик с зафиксированными значениями меток.
+type Counter interface {
+	Inc()
+}
+
+// Observer - гистограмма с зафиксированными значениями меток.
+type Observer interface {
+	Observe(value float64)
+}
+
+// CounterVec - семейство счётчиков, различающихся значениями меток.
+type CounterVec interface {
+	WithLabelValues(labelValues ...string) Counter
+}
+
+// HistogramVec - семейство гистограмм, различающихся значениями меток.
+type HistogramVec interface {
+	WithLabelValues(labelValues ...string) Observer
+}
+
+// MetricsRegistrar создаёт метрики для NewMetricsHooks. Интерфейс повторяет
+// CounterVec/HistogramVec из prometheus/client_golang, поэтому адаптер к нему
+// занимает несколько строк, а планировщик не зависит от конкретной библиотеки.
+type MetricsRegistrar interface {
+	CounterVec(name, help string, labelNames ...string) CounterVec
+	HistogramVec(name, help string, labelNames ...string) HistogramVec
+}
+
+// NewMetricsHooks создаёт хуки, которые ведут метрики задач:
+// jobs_started_total, jobs_failed_total, jobs_skipped_total и job_duration_seconds
+// с меткой job. Задачи без имени учитываются под меткой "unnamed".
+// Метрики регистрируются один раз при вызове. Для совместного использования
+// со своими хуками объедините их через JobHooks.Chain:
+//
+//	hooks := userHooks.Chain(scheduler.NewMetricsHooks(registrar))
+//	s := scheduler.New(scheduler.Config{JobHooks: hooks})
+func NewMetricsHooks(reg MetricsRegistrar) JobHooks {
+	started := reg.CounterVec(MetricJobsStarted, "Number of started job runs.", MetricLabelJob)
+	failed := reg.CounterVec(MetricJobsFailed, "Number of job runs finished with an error or panic.", MetricLabelJob)
+	skipped := reg.CounterVec(MetricJobsSkipped, "Number of skipped job runs.", MetricLabelJob)
+	duration := reg.HistogramVec(MetricJobDuration, "Duration of job runs in seconds.", MetricLabelJob)
+
+	return JobHooks{
+		OnJobStart: func(jobName string) {
+			started.WithLabelValues(jobLabel(jobName)).Inc()
+		},
+		OnJobFinish: func(jobName string, d time.Duration, err error) {
+			duration.WithLabelValues(jobLabel(jobName)).Observe(d.Seconds())
+		},
+		OnJobError: func(jobName string, err error) {
+			failed.WithLabelValues(jobLabel(jobName)).Inc()
+		},
+		OnJobSkipped: func(jobName string, reason string) {
+			skipped.WithLabelValues(jobLabel(jobName)).Inc()
+		},
+	}
+}
+
+// jobLabel возвращает стабильное значение метки для имени задачи.
+func jobLabel(jobName string) string {
+	if jobName == "" {
+		return unnamedJob
+	}
+	return jobName
+}
+
+// Chain возвращает хуки, которые вызывают сначала хуки h, затем хуки other.
+// Отсутствующие хуки пропускаются.
+func (h JobHooks) Chain(other JobHooks) JobHooks {
+	return JobHooks{
+		OnJobStart:   chain1(h.OnJobStart, other.OnJobStart),
+		OnJobFinish:  chain3(h.OnJobFinish, other.OnJobFinish),
+		OnJobError:   chain2(h.OnJobError, other.OnJobError),
+		OnJobRetry:   chain3(h.OnJobRetry, other.OnJobRetry),
+		OnJobSkipped: chain2(h.OnJobSkipped, other.OnJobSkipped),
+
+		OnJobStartCtx:   chain2(h.OnJobStartCtx, other.OnJobStartCtx),
+		OnJobFinishCtx:  chain4(h.OnJobFinishCtx, other.OnJobFinishCtx),
+		OnJobErrorCtx:   chain3(h.OnJobErrorCtx, other.OnJobErrorCtx),
+		OnJobRetryCtx:   chain4(h.OnJobRetryCtx, other.OnJobRetryCtx),
+		OnJobSkippedCtx: chain3(h.OnJobSkippedCtx, other.OnJobSkippedCtx),
+	}
+}
+
+// chain1 объединяет два хука с одним аргументом.
+func chain1[A any](first, second func(A)) func(A) {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(a A) {
+		first(a)
+		second(a)
+	}
+}
+
+// chain2 объединяет два хука с двумя аргументами.
+func chain2[A, B any](first, second func(A, B)) func(A, B) {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(a A, b B) {
+		first(a, b)
+		second(a, b)
+	}
+}
+
+// chain3 объединяет два хука с тремя аргументами.
+func chain3[A, B, C any](first, second func(A, B, C)) func(A, B, C) {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(a A, b B, c C) {
+		first(a, b, c)
+		second(a, b, c)
+	}
+}
+
+// chain4 объединяет два хука с четырьмя аргументами.
+func chain4[A, B, C, D any](first, second func(A, B, C, D)) func(A, B, C, D) {
+	if first == nil {
+		return second
+	}
+	if second == nil {
+		return first
+	}
+	return func(a A, b B, c C, d D) {
+		first(a, b, c, d)
+		second(a, b, c, d)
+	}
+}
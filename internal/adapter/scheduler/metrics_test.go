@@ -0,0 +1,172 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistrar запоминает созданные метрики и их значения по ключу "имя{метка}".
+type fakeRegistrar struct {
+	mu           sync.Mutex
+	registered   map[string][]string // имя метрики -> имена меток
+	counters     map[string]int
+	observations map[string][]float64
+}
+
+func newFakeRegistrar() *fakeRegistrar {
+	return &fakeRegistrar{
+		registered:   make(map[string][]string),
+		counters:     make(map[string]int),
+		observations: make(map[string][]float64),
+	}
+}
+
+func (r *fakeRegistrar) CounterVec(name, help string, labelNames ...string) CounterVec {
+	r.register(name, labelNames)
+	return fakeCounterVec{r: r, name: name}
+}
+
+func (r *fakeRegistrar) HistogramVec(name, help string, labelNames ...string) HistogramVec {
+	r.register(name, labelNames)
+	return fakeHistogramVec{r: r, name: name}
+}
+
+func (r *fakeRegistrar) register(name string, labelNames []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.registered[name] = labelNames
+}
+
+func (r *fakeRegistrar) counter(name, job string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counters[name+"{"+job+"}"]
+}
+
+func (r *fakeRegistrar) observed(name, job string) []float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.observations[name+"{"+job+"}"]
+}
+
+type fakeCounterVec struct {
+	r    *fakeRegistrar
+	name string
+}
+
+func (v fakeCounterVec) WithLabelValues(labelValues ...string) Counter {
+	return fakeMetric{r: v.r, key: v.name + "{" + strings.Join(labelValues, ",") + "}"}
+}
+
+type fakeHistogramVec struct {
+	r    *fakeRegistrar
+	name string
+}
+
+func (v fakeHistogramVec) WithLabelValues(labelValues ...string) Observer {
+	return fakeMetric{r: v.r, key: v.name + "{" + strings.Join(labelValues, ",") + "}"}
+}
+
+// fakeMetric реализует Counter и Observer.
+type fakeMetric struct {
+	r   *fakeRegistrar
+	key string
+}
+
+func (m fakeMetric) Inc() {
+	m.r.mu.Lock()
+	defer m.r.mu.Unlock()
+	m.r.counters[m.key]++
+}
+
+func (m fakeMetric) Observe(value float64) {
+	m.r.mu.Lock()
+	defer m.r.mu.Unlock()
+	m.r.observations[m.key] = append(m.r.observations[m.key], value)
+}
+
+func TestNewMetricsHooks(t *testing.T) {
+	reg := newFakeRegistrar()
+	s := New(Config{JobHooks: NewMetricsHooks(reg)})
+	defer s.Stop()
+
+	assert.Equal(t, map[string][]string{
+		MetricJobsStarted: {MetricLabelJob},
+		MetricJobsFailed:  {MetricLabelJob},
+		MetricJobsSkipped: {MetricLabelJob},
+		MetricJobDuration: {MetricLabelJob},
+	}, reg.registered)
+
+	run := func(opts JobOptions, job JobFunc) error {
+		return s.runJob(s.ctx, &jobWrapper{job: job, options: opts}, false)
+	}
+	require.NoError(t, run(JobOptions{Name: "cleanup"}, func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}))
+	require.Error(t, run(JobOptions{Name: "cleanup"}, func(ctx context.Context) error { return errors.New("boom") }))
+	require.Error(t, run(JobOptions{Name: "sync"}, func(ctx context.Context) error { panic("oops") }))
+	require.NoError(t, run(JobOptions{}, func(ctx context.Context) error { return nil }))
+
+	assert.Equal(t, 2, reg.counter(MetricJobsStarted, "cleanup"))
+	assert.Equal(t, 1, reg.counter(MetricJobsFailed, "cleanup"))
+	assert.Equal(t, 1, reg.counter(MetricJobsFailed, "sync"), "паника считается ошибкой")
+	assert.Equal(t, 1, reg.counter(MetricJobsStarted, unnamedJob), "задача без имени учитывается под стабильной меткой")
+
+	durations := reg.observed(MetricJobDuration, "cleanup")
+	require.Len(t, durations, 2)
+	assert.GreaterOrEqual(t, durations[0], 0.01)
+}
+
+func TestNewMetricsHooks_Skipped(t *testing.T) {
+	reg := newFakeRegistrar()
+	hooks := NewMetricsHooks(reg)
+	hooks.OnJobSkipped("report", SkipReasonRunning)
+	hooks.OnJobSkipped("", SkipReasonQueueFull)
+
+	assert.Equal(t, 1, reg.counter(MetricJobsSkipped, "report"))
+	assert.Equal(t, 1, reg.counter(MetricJobsSkipped, unnamedJob))
+}
+
+func TestJobHooks_Chain(t *testing.T) {
+	var calls []string
+	first := JobHooks{
+		OnJobStart:  func(name string) { calls = append(calls, "first start "+name) },
+		OnJobFinish: func(name string, d time.Duration, err error) { calls = append(calls, "first finish "+name) },
+	}
+	second := JobHooks{
+		OnJobStart: func(name string) { calls = append(calls, "second start "+name) },
+		OnJobError: func(name string, err error) { calls = append(calls, "second error "+name) },
+	}
+
+	hooks := first.Chain(second)
+	hooks.OnJobStart("a")
+	hooks.OnJobFinish("a", time.Second, nil)
+	hooks.OnJobError("a", errors.New("boom"))
+	assert.Nil(t, hooks.OnJobRetry, "отсутствующие с обеих сторон хуки остаются nil")
+	assert.Nil(t, hooks.OnJobSkipped)
+
+	assert.Equal(t, []string{"first start a", "second start a", "first finish a", "second error a"}, calls)
+}
+
+func TestJobHooks_ChainWithMetrics(t *testing.T) {
+	reg := newFakeRegistrar()
+	var started []string
+	user := JobHooks{OnJobStart: func(name string) { started = append(started, name) }}
+
+	s := New(Config{JobHooks: user.Chain(NewMetricsHooks(reg))})
+	defer s.Stop()
+
+	id := s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error { return nil }, JobOptions{Name: "hourly"})
+	require.NoError(t, s.TriggerTickerJob(context.Background(), id))
+
+	assert.Equal(t, []string{"hourly"}, started)
+	assert.Equal(t, 1, reg.counter(MetricJobsStarted, "hourly"))
+}
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// MisfirePolicy определяет обработку срабатываний cron-задачи, пропущенных,
+// пока планировщик не работал.
+type MisfirePolicy int
+
+const (
+	// MisfireIgnore не выполняет пропущенные срабатывания (по умолчанию).
+	MisfireIgnore MisfirePolicy = iota
+	// MisfireRunOnceOnStart выполняет задачу один раз при Start, если после
+	// JobOptions.LastRun было хотя бы одно срабатывание расписания.
+	MisfireRunOnceOnStart
+	// MisfireCatchUpAll выполняет задачу по одному разу на каждое пропущенное
+	// срабатывание, но не больше JobOptions.MaxCatchUp раз.
+	MisfireCatchUpAll
+)
+
+// defaultMaxCatchUp - лимит догоняющих запусков, если JobOptions.MaxCatchUp не задан.
+const defaultMaxCatchUp = 10
+
+// maxCatchUp возвращает лимит догоняющих запусков с учетом значения по умолчанию.
+func (w *jobWrapper) maxCatchUp() int {
+	if w.options.MaxCatchUp <= 0 {
+		return defaultMaxCatchUp
+	}
+	return w.options.MaxCatchUp
+}
+
+// scheduleCatchUp запускает пропущенные выполнения cron-задачи после Start.
+// Пропуски считаются в момент старта, поэтому задача, добавленная до Start,
+// догоняет и срабатывания, пропущенные между добавлением и запуском планировщика.
+// Запуски идут последовательно через runJobWrapper, поэтому к ним применяются
+// политика перекрытий, таймаут, ретраи и хуки.
+func (s *Scheduler) scheduleCatchUp(id CronJobID, wrapper *jobWrapper, schedule cron.Schedule) {
+	opts := wrapper.options
+	if opts.Misfire == MisfireIgnore || opts.LastRun.IsZero() {
+		return
+	}
+
+	limit := 1
+	if opts.Misfire == MisfireCatchUpAll {
+		limit = wrapper.maxCatchUp()
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		select {
+		case <-s.started:
+		case <-s.ctx.Done():
+			return
+		}
+
+		runs := missedRuns(schedule, opts.LastRun, time.Now(), limit)
+		if runs == 0 {
+			return
+		}
+		s.logger.Info("running missed cron job executions", "name", opts.Name, "id", id, "runs", runs, "last_run", opts.LastRun)
+
+		for range runs {
+			// Задача могла быть удалена или планировщик остановлен между запусками
+			if s.ctx.Err() != nil || !s.hasCronJob(id) {
+				return
+			}
+			s.runJobWrapper(wrapper)
+		}
+	}()
+}
+
+// hasCronJob проверяет, зарегистрирована ли cron-задача.
+func (s *Scheduler) hasCronJob(id CronJobID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.cronJobs[id]
+	return exists
+}
+
+// missedRuns возвращает число срабатываний расписания в интервале (lastRun, now],
+// но не больше limit.
+func missedRuns(schedule cron.Schedule, lastRun, now time.Time, limit int) int {
+	n := 0
+	for t := schedule.Next(lastRun); n < limit && !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		n++
+	}
+	return n
+}
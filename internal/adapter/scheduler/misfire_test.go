@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMissedRuns(t *testing.T) {
+	schedule, err := cron.ParseStandard("0 * * * *") // каждый час
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		lastRun time.Time
+		limit   int
+		want    int
+	}{
+		{name: "no misses", lastRun: now.Add(-10 * time.Minute), limit: 10, want: 0},
+		{name: "one miss", lastRun: now.Add(-40 * time.Minute), limit: 10, want: 1},
+		{name: "several misses", lastRun: now.Add(-3*time.Hour - 10*time.Minute), limit: 10, want: 3},
+		{name: "capped", lastRun: now.Add(-48 * time.Hour), limit: 5, want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, missedRuns(schedule, tt.lastRun, now, tt.limit))
+		})
+	}
+}
+
+func TestScheduler_Misfire(t *testing.T) {
+	// Расписание раз в год гарантирует, что обычных срабатываний во время теста не будет
+	const schedule = "0 0 0 1 1 *"
+	// Пропущенных срабатываний больше любого лимита, поэтому результат детерминирован
+	lastRun := time.Now().AddDate(-20, 0, 0)
+
+	tests := []struct {
+		name string
+		opts JobOptions
+		want int64
+	}{
+		{name: "ignore", opts: JobOptions{Misfire: MisfireIgnore, LastRun: lastRun}, want: 0},
+		{name: "no last run", opts: JobOptions{Misfire: MisfireCatchUpAll}, want: 0},
+		{name: "run once", opts: JobOptions{Misfire: MisfireRunOnceOnStart, LastRun: lastRun}, want: 1},
+		{name: "catch up default cap", opts: JobOptions{Misfire: MisfireCatchUpAll, LastRun: lastRun}, want: defaultMaxCatchUp},
+		{name: "catch up capped", opts: JobOptions{Misfire: MisfireCatchUpAll, LastRun: lastRun, MaxCatchUp: 2}, want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var finished int64
+			s := New(Config{JobHooks: JobHooks{
+				OnJobFinish: func(string, time.Duration, error) { atomic.AddInt64(&finished, 1) },
+			}})
+			defer s.Stop()
+
+			var runs int64
+			_, err := s.AddCronJobWithOptions(schedule, func(ctx context.Context) error {
+				atomic.AddInt64(&runs, 1)
+				return nil
+			}, tt.opts)
+			require.NoError(t, err)
+
+			// До Start пропущенные запуски не выполняются
+			ensureNoIncrement(t, &runs, 0, 50*time.Millisecond)
+
+			s.Start()
+			if tt.want > 0 {
+				waitForAtLeast(t, &runs, tt.want, time.Second)
+			}
+			ensureNoIncrement(t, &runs, tt.want, 50*time.Millisecond)
+			assert.Equal(t, tt.want, atomic.LoadInt64(&finished))
+		})
+	}
+}
+
+func TestScheduler_MisfireRemovedBeforeStart(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runs int64
+	id, err := s.AddCronJobWithOptions("0 0 0 1 1 *", func(ctx context.Context) error {
+		atomic.AddInt64(&runs, 1)
+		return nil
+	}, JobOptions{Misfire: MisfireCatchUpAll, LastRun: time.Now().AddDate(-3, 0, 0)})
+	require.NoError(t, err)
+
+	s.RemoveCronJob(id)
+	s.Start()
+	ensureNoIncrement(t, &runs, 0, 100*time.Millisecond)
+}
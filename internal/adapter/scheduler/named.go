@@ -0,0 +1,178 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sttbot/internal/shared"
+)
+
+var (
+	// ErrJobNameTaken - задача с таким именем уже зарегистрирована (помечена shared.KindConflict).
+	ErrJobNameTaken = errors.New("scheduler: job name already taken")
+	// ErrEmptyJobName - имя задачи не указано.
+	ErrEmptyJobName = errors.New("scheduler: empty job name")
+)
+
+// namedJob - запись индекса имён: тип задачи и её ID.
+// Нулевой id означает, что имя зарезервировано, но задача ещё добавляется.
+type namedJob struct {
+	kind JobType
+	id   int
+}
+
+// AddNamedCronJob добавляет cron-задачу, адресуемую по имени (см. RemoveJob, TriggerJob,
+// PauseJob, ResumeJob, JobByName). Имя записывается в opts.Name. Возвращает ErrEmptyJobName
+// для пустого имени и ErrJobNameTaken, помеченную shared.KindConflict, если задача с таким
+// именем уже есть. Имя освобождается при удалении задачи (в том числе через RemoveCronJob)
+// и при остановке планировщика.
+func (s *Scheduler) AddNamedCronJob(name, schedule string, job JobFunc, opts JobOptions) error {
+	if err := s.reserveName(name, JobTypeCron); err != nil {
+		return err
+	}
+
+	opts.Name = name
+	id, err := s.addCronJob(schedule, job, opts, "")
+	if err != nil {
+		s.mu.Lock()
+		delete(s.names, name)
+		s.mu.Unlock()
+		return err
+	}
+
+	s.mu.Lock()
+	s.names[name] = namedJob{kind: JobTypeCron, id: int(id)}
+	s.mu.Unlock()
+	return nil
+}
+
+// AddNamedTickerJob добавляет ticker-задачу, адресуемую по имени.
+// Ошибки и освобождение имени - как у AddNamedCronJob.
+func (s *Scheduler) AddNamedTickerJob(name string, interval time.Duration, job JobFunc, opts JobOptions) error {
+	if interval <= 0 {
+		panic("scheduler: non-positive interval for ticker job")
+	}
+	if err := s.reserveName(name, JobTypeTicker); err != nil {
+		return err
+	}
+
+	opts.Name = name
+	id := s.addTickerJob(interval, job, opts, "")
+
+	s.mu.Lock()
+	s.names[name] = namedJob{kind: JobTypeTicker, id: int(id)}
+	s.mu.Unlock()
+	return nil
+}
+
+// reserveName резервирует имя до добавления задачи, чтобы параллельные
+// добавления с одним именем не прошли оба.
+func (s *Scheduler) reserveName(name string, kind JobType) error {
+	if name == "" {
+		return ErrEmptyJobName
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, taken := s.names[name]; taken {
+		return shared.MarkKind(fmt.Errorf("%w: %q", ErrJobNameTaken, name), shared.KindConflict)
+	}
+	s.names[name] = namedJob{kind: kind}
+	return nil
+}
+
+// lookupName возвращает запись индекса для имени. Зарезервированные имена не находятся.
+func (s *Scheduler) lookupName(name string) (namedJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ref, exists := s.names[name]
+	return ref, exists && ref.id != 0
+}
+
+// unindexLocked удаляет имя из индекса, если оно указывает на задачу kind/id.
+// Вызывается под s.mu.
+func (s *Scheduler) unindexLocked(name string, kind JobType, id int) {
+	if ref, exists := s.names[name]; exists && ref == (namedJob{kind: kind, id: id}) {
+		delete(s.names, name)
+	}
+}
+
+// RemoveJob удаляет задачу по имени. Возвращает false, если задача не найдена.
+func (s *Scheduler) RemoveJob(name string) bool {
+	ref, exists := s.lookupName(name)
+	if !exists {
+		return false
+	}
+
+	switch ref.kind {
+	case JobTypeCron:
+		s.RemoveCronJob(CronJobID(ref.id))
+		return true
+	case JobTypeTicker:
+		return s.RemoveTickerJob(TickerJobID(ref.id))
+	}
+	return false
+}
+
+// TriggerJob немедленно выполняет задачу по имени (см. TriggerCronJob).
+// Возвращает ErrJobNotFound, если задача не найдена.
+func (s *Scheduler) TriggerJob(ctx context.Context, name string) error {
+	ref, exists := s.lookupName(name)
+	if !exists {
+		return ErrJobNotFound
+	}
+
+	switch ref.kind {
+	case JobTypeCron:
+		return s.TriggerCronJob(ctx, CronJobID(ref.id))
+	case JobTypeTicker:
+		return s.TriggerTickerJob(ctx, TickerJobID(ref.id))
+	}
+	return ErrJobNotFound
+}
+
+// PauseJob приостанавливает задачу по имени (см. PauseCronJob).
+// Возвращает false, если задача не найдена.
+func (s *Scheduler) PauseJob(name string) bool {
+	return s.setNamedPaused(name, true)
+}
+
+// ResumeJob возобновляет приостановленную задачу по имени.
+// Возвращает false, если задача не найдена.
+func (s *Scheduler) ResumeJob(name string) bool {
+	return s.setNamedPaused(name, false)
+}
+
+// setNamedPaused меняет состояние паузы задачи по имени.
+func (s *Scheduler) setNamedPaused(name string, paused bool) bool {
+	ref, exists := s.lookupName(name)
+	if !exists {
+		return false
+	}
+
+	switch ref.kind {
+	case JobTypeCron:
+		return s.setCronPaused(CronJobID(ref.id), paused)
+	case JobTypeTicker:
+		return s.setTickerPaused(TickerJobID(ref.id), paused)
+	}
+	return false
+}
+
+// JobByName возвращает состояние задачи по имени.
+func (s *Scheduler) JobByName(name string) (JobInfo, bool) {
+	ref, exists := s.lookupName(name)
+	if !exists {
+		return JobInfo{}, false
+	}
+
+	switch ref.kind {
+	case JobTypeCron:
+		return s.CronJobInfo(CronJobID(ref.id))
+	case JobTypeTicker:
+		return s.TickerJobInfo(TickerJobID(ref.id))
+	}
+	return JobInfo{}, false
+}
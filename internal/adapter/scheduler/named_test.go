@@ -0,0 +1,134 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+func TestScheduler_NamedCronJob(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runs int64
+	job := func(ctx context.Context) error {
+		atomic.AddInt64(&runs, 1)
+		return nil
+	}
+	require.NoError(t, s.AddNamedCronJob("report", "0 0 0 1 1 *", job, JobOptions{Name: "ignored"}))
+
+	err := s.AddNamedCronJob("report", "@hourly", job, JobOptions{})
+	require.ErrorIs(t, err, ErrJobNameTaken)
+	assert.True(t, shared.IsConflict(err))
+	require.ErrorIs(t, s.AddNamedCronJob("", "@hourly", job, JobOptions{}), ErrEmptyJobName)
+
+	info, ok := s.JobByName("report")
+	require.True(t, ok)
+	assert.Equal(t, JobTypeCron, info.Type)
+	assert.Equal(t, "report", info.Name)
+	assert.Equal(t, "0 0 0 1 1 *", info.Schedule)
+
+	require.NoError(t, s.TriggerJob(context.Background(), "report"))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&runs))
+
+	require.True(t, s.PauseJob("report"))
+	info, _ = s.JobByName("report")
+	assert.True(t, info.Paused)
+	require.True(t, s.ResumeJob("report"))
+	info, _ = s.JobByName("report")
+	assert.False(t, info.Paused)
+
+	require.True(t, s.RemoveJob("report"))
+	assert.False(t, s.RemoveJob("report"))
+	_, ok = s.JobByName("report")
+	assert.False(t, ok)
+	require.ErrorIs(t, s.TriggerJob(context.Background(), "report"), ErrJobNotFound)
+	assert.False(t, s.PauseJob("report"))
+	assert.Empty(t, s.Jobs())
+}
+
+func TestScheduler_NamedTickerJob(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	require.NoError(t, s.AddNamedTickerJob("sync", time.Hour, func(ctx context.Context) error { return nil }, JobOptions{}))
+	require.ErrorIs(t, s.AddNamedCronJob("sync", "@hourly", func(ctx context.Context) error { return nil }, JobOptions{}), ErrJobNameTaken)
+
+	info, ok := s.JobByName("sync")
+	require.True(t, ok)
+	assert.Equal(t, JobTypeTicker, info.Type)
+	assert.Equal(t, time.Hour, info.Interval)
+
+	require.True(t, s.RemoveJob("sync"))
+	assert.Empty(t, s.Jobs())
+}
+
+func TestScheduler_NamedJob_InvalidScheduleReleasesName(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	job := func(ctx context.Context) error { return nil }
+	require.Error(t, s.AddNamedCronJob("report", "bad schedule", job, JobOptions{}))
+	require.NoError(t, s.AddNamedCronJob("report", "@hourly", job, JobOptions{}))
+}
+
+func TestScheduler_NamedJob_RemoveByIDReleasesName(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	job := func(ctx context.Context) error { return nil }
+	for range 3 {
+		require.NoError(t, s.AddNamedCronJob("report", "@hourly", job, JobOptions{}))
+		info, ok := s.JobByName("report")
+		require.True(t, ok)
+		s.RemoveCronJob(CronJobID(info.ID))
+
+		require.NoError(t, s.AddNamedTickerJob("report", time.Hour, job, JobOptions{}))
+		info, ok = s.JobByName("report")
+		require.True(t, ok)
+		require.True(t, s.RemoveTickerJob(TickerJobID(info.ID)))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Empty(t, s.names)
+}
+
+func TestScheduler_NamedJob_UnnamedJobsUnaffected(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	jobErr := errors.New("boom")
+	id, err := s.AddCronJobWithOptions("@hourly", func(ctx context.Context) error { return jobErr }, JobOptions{Name: "report"})
+	require.NoError(t, err)
+
+	// Имя из JobOptions не регистрируется в индексе
+	_, ok := s.JobByName("report")
+	assert.False(t, ok)
+	require.ErrorIs(t, s.TriggerCronJob(context.Background(), id), jobErr)
+
+	require.NoError(t, s.AddNamedCronJob("report", "@daily", func(ctx context.Context) error { return nil }, JobOptions{}))
+	s.RemoveCronJob(id)
+	_, ok = s.JobByName("report")
+	assert.True(t, ok, "removing unnamed job with same name keeps index entry")
+}
+
+func TestScheduler_NamedJob_ClearedOnStop(t *testing.T) {
+	s := New(Config{})
+	require.NoError(t, s.AddNamedCronJob("report", "@hourly", func(ctx context.Context) error { return nil }, JobOptions{}))
+	s.Start()
+	s.Stop()
+
+	_, ok := s.JobByName("report")
+	assert.False(t, ok)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Empty(t, s.names)
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// OneShotJobID представляет идентификатор одноразовой задачи.
+type OneShotJobID int
+
+// oneShotJob содержит информацию об одноразовой задаче, ожидающей запуска.
+type oneShotJob struct {
+	id      OneShotJobID
+	cancel  context.CancelFunc
+	wrapper *jobWrapper
+}
+
+// RunOnceAfter планирует однократное выполнение задачи через указанную задержку.
+// Задача использует родительский контекст планировщика, вызывает те же JobHooks
+// и учитывается при graceful shutdown: выполняющаяся задача дожидается завершения,
+// ещё не запущенная отменяется.
+// После срабатывания задача удаляется из внутреннего реестра.
+func (s *Scheduler) RunOnceAfter(d time.Duration, job JobFunc, opts JobOptions) OneShotJobID {
+	wrapper := &jobWrapper{
+		job:     job,
+		options: opts,
+	}
+
+	s.mu.Lock()
+	id := s.nextOneShotID
+	s.nextOneShotID++
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	wrapper.ctx = ctx
+	s.oneShotJobs[id] = &oneShotJob{
+		id:      id,
+		cancel:  cancel,
+		wrapper: wrapper,
+	}
+	s.mu.Unlock()
+
+	timer := time.NewTimer(d)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer timer.Stop()
+		defer cancel()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			s.logger.Debug("one-shot job cancelled before run", "name", opts.Name, "id", id)
+			return
+		}
+
+		// Задача могла быть отменена одновременно со срабатыванием таймера
+		if !s.takeOneShot(id) || ctx.Err() != nil {
+			return
+		}
+		s.runJobWrapper(wrapper)
+	}()
+
+	s.logger.Info("one-shot job added", "delay", d, "name", opts.Name, "id", id)
+	return id
+}
+
+// RunOnceAt планирует однократное выполнение задачи в указанный момент времени.
+// Если момент уже прошёл, задача запускается немедленно.
+func (s *Scheduler) RunOnceAt(t time.Time, job JobFunc, opts JobOptions) OneShotJobID {
+	return s.RunOnceAfter(time.Until(t), job, opts)
+}
+
+// CancelOneShot отменяет одноразовую задачу, которая ещё не была запущена.
+// Возвращает false, если задача не найдена или уже сработала.
+func (s *Scheduler) CancelOneShot(id OneShotJobID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.oneShotJobs[id]
+	if !exists {
+		return false
+	}
+
+	job.cancel()
+	delete(s.oneShotJobs, id)
+
+	s.logger.Info("one-shot job cancelled", "id", id, "name", job.wrapper.options.Name)
+	return true
+}
+
+// takeOneShot удаляет задачу из реестра перед запуском.
+// Возвращает false, если задача уже была отменена.
+func (s *Scheduler) takeOneShot(id OneShotJobID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.oneShotJobs[id]; !exists {
+		return false
+	}
+	delete(s.oneShotJobs, id)
+	return true
+}
@@ -0,0 +1,126 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_RunOnceAfter(t *testing.T) {
+	var startCalls int64
+	s := New(Config{JobHooks: JobHooks{
+		OnJobStart: func(jobName string) {
+			atomic.AddInt64(&startCalls, 1)
+			assert.Equal(t, "once", jobName)
+		},
+	}})
+	defer s.Stop()
+
+	var runCount int64
+	s.RunOnceAfter(30*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return nil
+	}, JobOptions{Name: "once"})
+
+	waitForAtLeast(t, &runCount, 1, time.Second)
+	ensureNoIncrement(t, &runCount, 1, 200*time.Millisecond)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&startCalls), "хук запуска должен вызываться для одноразовой задачи")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Empty(t, s.oneShotJobs, "сработавшая задача должна удаляться из реестра")
+}
+
+func TestScheduler_RunOnceAt(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runCount int64
+	s.RunOnceAt(time.Now().Add(30*time.Millisecond), func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return nil
+	}, JobOptions{})
+
+	waitForAtLeast(t, &runCount, 1, time.Second)
+}
+
+func TestScheduler_CancelOneShot(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runCount int64
+	id := s.RunOnceAfter(100*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return nil
+	}, JobOptions{})
+
+	assert.True(t, s.CancelOneShot(id), "ожидающая задача должна отменяться")
+	assert.False(t, s.CancelOneShot(id), "повторная отмена должна вернуть false")
+	ensureNoIncrement(t, &runCount, 0, 300*time.Millisecond)
+}
+
+func TestScheduler_CancelOneShotAfterRun(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runCount int64
+	id := s.RunOnceAfter(10*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return nil
+	}, JobOptions{})
+
+	waitForAtLeast(t, &runCount, 1, time.Second)
+	assert.False(t, s.CancelOneShot(id), "сработавшую задачу отменить нельзя")
+}
+
+func TestScheduler_StopCancelsPendingOneShot(t *testing.T) {
+	s := New(Config{})
+
+	var runCount int64
+	s.RunOnceAfter(200*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return nil
+	}, JobOptions{})
+
+	s.Stop()
+	ensureNoIncrement(t, &runCount, 0, 400*time.Millisecond)
+}
+
+func TestScheduler_StopWaitsForInFlightOneShot(t *testing.T) {
+	s := New(Config{})
+
+	var started, finished int64
+	s.RunOnceAfter(0, func(ctx context.Context) error {
+		atomic.AddInt64(&started, 1)
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		atomic.AddInt64(&finished, 1)
+		return ctx.Err()
+	}, JobOptions{})
+
+	waitForAtLeast(t, &started, 1, time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	require.NoError(t, s.StopContext(ctx))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&finished), "остановка должна дождаться выполняющейся задачи")
+}
+
+func TestScheduler_OneShotRespectsParentContext(t *testing.T) {
+	parentCtx, parentCancel := context.WithCancel(context.Background())
+	s := NewWithContext(parentCtx, Config{})
+	defer s.Stop()
+
+	var runCount int64
+	s.RunOnceAfter(100*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return nil
+	}, JobOptions{})
+
+	parentCancel()
+	ensureNoIncrement(t, &runCount, 0, 300*time.Millisecond)
+}
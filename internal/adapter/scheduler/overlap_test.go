@@ -0,0 +1,145 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runBlockedJob запускает n выполнений задачи, пока первое из них удерживает блокировку,
+// и возвращает число выполнений и причины пропусков.
+func runBlockedJob(t *testing.T, opts JobOptions, n int) (int64, []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var reasons []string
+	s := New(Config{JobHooks: JobHooks{
+		OnJobSkipped: func(jobName string, reason string) {
+			mu.Lock()
+			defer mu.Unlock()
+			reasons = append(reasons, reason)
+		},
+	}})
+	defer s.Stop()
+
+	release := make(chan struct{})
+	var runCount int64
+	wrapper := &jobWrapper{
+		job: func(ctx context.Context) error {
+			if atomic.AddInt64(&runCount, 1) == 1 {
+				<-release
+			}
+			return nil
+		},
+		options: opts,
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.runJobWrapper(wrapper)
+	}()
+	waitForAtLeast(t, &runCount, 1, time.Second)
+
+	for range n - 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runJobWrapper(wrapper)
+		}()
+	}
+
+	// Ждем, пока лишние запуски будут поставлены в очередь или отброшены
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		skipped := len(reasons)
+		mu.Unlock()
+		return int(wrapper.queued.Load())+skipped == n-1
+	}, time.Second, 5*time.Millisecond, "все запуски должны попасть в очередь или быть отброшены")
+
+	close(release)
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	return atomic.LoadInt64(&runCount), reasons
+}
+
+func TestScheduler_DelayIfRunningDefaultQueue(t *testing.T) {
+	runs, reasons := runBlockedJob(t, JobOptions{OverlapPolicy: DelayIfRunning}, 4)
+
+	assert.Equal(t, int64(2), runs, "по умолчанию ожидать должен только один запуск")
+	assert.Equal(t, []string{SkipReasonQueueFull, SkipReasonQueueFull}, reasons)
+}
+
+func TestScheduler_DelayIfRunningMaxQueuedRuns(t *testing.T) {
+	runs, reasons := runBlockedJob(t, JobOptions{OverlapPolicy: DelayIfRunning, MaxQueuedRuns: 2}, 4)
+
+	assert.Equal(t, int64(3), runs)
+	assert.Equal(t, []string{SkipReasonQueueFull}, reasons)
+}
+
+func TestScheduler_SkipIfRunningHook(t *testing.T) {
+	runs, reasons := runBlockedJob(t, JobOptions{OverlapPolicy: SkipIfRunning}, 3)
+
+	assert.Equal(t, int64(1), runs)
+	assert.Equal(t, []string{SkipReasonRunning, SkipReasonRunning}, reasons)
+}
+
+func TestScheduler_DrainPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy DrainPolicy
+		runs   int64
+	}{
+		{"drop pending", DropPending, 1},
+		{"run pending", RunPending, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(Config{DrainPolicy: tt.policy})
+			s.Start()
+
+			release := make(chan struct{})
+			var runCount int64
+			wrapper := &jobWrapper{
+				job: func(ctx context.Context) error {
+					if atomic.AddInt64(&runCount, 1) == 1 {
+						<-release
+					}
+					return nil
+				},
+				options: JobOptions{OverlapPolicy: DelayIfRunning},
+			}
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				s.runJobWrapper(wrapper)
+			}()
+			waitForAtLeast(t, &runCount, 1, time.Second)
+			go func() {
+				defer wg.Done()
+				s.runJobWrapper(wrapper)
+			}()
+			require.Eventually(t, func() bool { return wrapper.queued.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+			stopped := make(chan error, 1)
+			go func() { stopped <- s.StopContext(context.Background()) }()
+			<-s.ctx.Done()
+
+			close(release)
+			wg.Wait()
+			require.NoError(t, <-stopped)
+			assert.Equal(t, tt.runs, atomic.LoadInt64(&runCount))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+// PauseTickerJob приостанавливает ticker-задачу, сохраняя её ID и настройки.
+// Пока задача приостановлена, тики пропускаются и хуки не вызываются.
+// Повторная приостановка ничего не делает. Возвращает false, если задача не найдена.
+func (s *Scheduler) PauseTickerJob(id TickerJobID) bool {
+	return s.setTickerPaused(id, true)
+}
+
+// ResumeTickerJob возобновляет приостановленную ticker-задачу.
+// Возвращает false, если задача не найдена.
+func (s *Scheduler) ResumeTickerJob(id TickerJobID) bool {
+	return s.setTickerPaused(id, false)
+}
+
+// PauseCronJob приостанавливает cron-задачу, сохраняя её EntryID и расписание.
+// Пока задача приостановлена, срабатывания пропускаются и хуки не вызываются.
+// Повторная приостановка ничего не делает. Возвращает false, если задача не найдена.
+func (s *Scheduler) PauseCronJob(id CronJobID) bool {
+	return s.setCronPaused(id, true)
+}
+
+// ResumeCronJob возобновляет приостановленную cron-задачу.
+// Возвращает false, если задача не найдена.
+func (s *Scheduler) ResumeCronJob(id CronJobID) bool {
+	return s.setCronPaused(id, false)
+}
+
+// setTickerPaused меняет состояние паузы ticker-задачи.
+func (s *Scheduler) setTickerPaused(id TickerJobID, paused bool) bool {
+	s.mu.Lock()
+	job, exists := s.tickerJobs[id]
+	s.mu.Unlock()
+	if !exists {
+		return false
+	}
+
+	if job.wrapper.paused.Swap(paused) != paused {
+		s.logger.Info("ticker job pause state changed", "id", id, "name", job.wrapper.options.Name, "paused", paused)
+	}
+	return true
+}
+
+// setCronPaused меняет состояние паузы cron-задачи.
+func (s *Scheduler) setCronPaused(id CronJobID, paused bool) bool {
+	s.mu.Lock()
+	job, exists := s.cronJobs[id]
+	s.mu.Unlock()
+	if !exists {
+		return false
+	}
+
+	if job.wrapper.paused.Swap(paused) != paused {
+		s.logger.Info("cron job pause state changed", "id", id, "name", job.wrapper.options.Name, "paused", paused)
+	}
+	return true
+}
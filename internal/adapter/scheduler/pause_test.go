@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_PauseResumeTickerJob(t *testing.T) {
+	var startCalls int64
+	s := New(Config{JobHooks: JobHooks{
+		OnJobStart: func(jobName string) {
+			atomic.AddInt64(&startCalls, 1)
+		},
+	}})
+	defer s.Stop()
+
+	var runCount int64
+	id := s.AddTickerJob(20*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return nil
+	})
+	s.Start()
+	waitForAtLeast(t, &runCount, 1, time.Second)
+
+	require.True(t, s.PauseTickerJob(id))
+	require.True(t, s.PauseTickerJob(id), "повторная пауза должна возвращать true")
+
+	// Даём завершиться выполнению, которое могло стартовать до паузы
+	time.Sleep(30 * time.Millisecond)
+	paused := atomic.LoadInt64(&runCount)
+	hooksBefore := atomic.LoadInt64(&startCalls)
+	ensureNoIncrement(t, &runCount, paused, 150*time.Millisecond)
+	assert.Equal(t, hooksBefore, atomic.LoadInt64(&startCalls), "хуки не должны вызываться для приостановленной задачи")
+
+	info, ok := s.TickerJobInfo(id)
+	require.True(t, ok)
+	assert.True(t, info.Paused)
+
+	require.True(t, s.ResumeTickerJob(id))
+	waitForAtLeast(t, &runCount, paused+1, time.Second)
+
+	info, ok = s.TickerJobInfo(id)
+	require.True(t, ok)
+	assert.False(t, info.Paused)
+}
+
+func TestScheduler_PauseResumeCronJob(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runCount int64
+	id, err := s.AddCronJob("@every 20ms", func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return nil
+	})
+	require.NoError(t, err)
+	s.Start()
+	waitForAtLeast(t, &runCount, 1, 2*time.Second)
+
+	require.True(t, s.PauseCronJob(id))
+	time.Sleep(30 * time.Millisecond)
+	paused := atomic.LoadInt64(&runCount)
+	ensureNoIncrement(t, &runCount, paused, 1500*time.Millisecond)
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1)
+	assert.True(t, jobs[0].Paused)
+
+	require.True(t, s.ResumeCronJob(id))
+	waitForAtLeast(t, &runCount, paused+1, 2*time.Second)
+}
+
+func TestScheduler_PauseNonExistentJob(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	assert.False(t, s.PauseTickerJob(999))
+	assert.False(t, s.ResumeTickerJob(999))
+	assert.False(t, s.PauseCronJob(999))
+	assert.False(t, s.ResumeCronJob(999))
+}
+
+func TestScheduler_StopWithPausedJob(t *testing.T) {
+	s := New(Config{})
+
+	id := s.AddTickerJob(20*time.Millisecond, func(ctx context.Context) error {
+		return nil
+	})
+	s.Start()
+	require.True(t, s.PauseTickerJob(id))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, s.StopContext(ctx), "приостановленная задача не должна мешать остановке")
+}
@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/pkg/retry"
+)
+
+func testRetryConfig(maxAttempts int) *retry.Config {
+	return &retry.Config{
+		MaxAttempts:  maxAttempts,
+		InitialDelay: 10 * time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+	}
+}
+
+func TestScheduler_JobRetrySucceeds(t *testing.T) {
+	var retryCalls, errorCalls, finishCalls int64
+	s := New(Config{JobHooks: JobHooks{
+		OnJobRetry: func(jobName string, attempt int, err error) {
+			atomic.AddInt64(&retryCalls, 1)
+			assert.Equal(t, "flaky", jobName)
+		},
+		OnJobError: func(jobName string, err error) {
+			atomic.AddInt64(&errorCalls, 1)
+		},
+		OnJobFinish: func(jobName string, duration time.Duration, err error) {
+			atomic.AddInt64(&finishCalls, 1)
+		},
+	}})
+	defer s.Stop()
+
+	var attempts int64
+	s.RunOnceAfter(0, func(ctx context.Context) error {
+		if atomic.AddInt64(&attempts, 1) < 3 {
+			return errors.New("database is locked")
+		}
+		return nil
+	}, JobOptions{Name: "flaky", Retry: testRetryConfig(5)})
+
+	waitForAtLeast(t, &finishCalls, 1, time.Second)
+	assert.Equal(t, int64(3), atomic.LoadInt64(&attempts))
+	assert.Equal(t, int64(2), atomic.LoadInt64(&retryCalls), "промежуточные ошибки должны попадать в OnJobRetry")
+	assert.Equal(t, int64(0), atomic.LoadInt64(&errorCalls), "OnJobError не должен вызываться при успешном повторе")
+}
+
+func TestScheduler_JobRetryExhausted(t *testing.T) {
+	var retryCalls, errorCalls int64
+	jobErr := errors.New("network blip")
+	s := New(Config{JobHooks: JobHooks{
+		OnJobRetry: func(jobName string, attempt int, err error) {
+			atomic.AddInt64(&retryCalls, 1)
+			assert.ErrorIs(t, err, jobErr)
+		},
+		OnJobError: func(jobName string, err error) {
+			atomic.AddInt64(&errorCalls, 1)
+			assert.ErrorIs(t, err, jobErr)
+		},
+	}})
+	defer s.Stop()
+
+	var attempts int64
+	s.RunOnceAfter(0, func(ctx context.Context) error {
+		atomic.AddInt64(&attempts, 1)
+		return jobErr
+	}, JobOptions{Retry: testRetryConfig(3)})
+
+	waitForAtLeast(t, &errorCalls, 1, time.Second)
+	assert.Equal(t, int64(3), atomic.LoadInt64(&attempts))
+	assert.Equal(t, int64(2), atomic.LoadInt64(&retryCalls))
+	ensureNoIncrement(t, &errorCalls, 1, 100*time.Millisecond)
+}
+
+func TestScheduler_JobRetryRespectsTimeout(t *testing.T) {
+	var finishCalls int64
+	var finishErr atomic.Value
+	s := New(Config{JobHooks: JobHooks{
+		OnJobFinish: func(jobName string, duration time.Duration, err error) {
+			finishErr.Store(err)
+			atomic.AddInt64(&finishCalls, 1)
+		},
+	}})
+	defer s.Stop()
+
+	var attempts int64
+	s.RunOnceAfter(0, func(ctx context.Context) error {
+		atomic.AddInt64(&attempts, 1)
+		return errors.New("busy")
+	}, JobOptions{
+		Timeout: 50 * time.Millisecond,
+		Retry: &retry.Config{
+			MaxAttempts:  100,
+			InitialDelay: 20 * time.Millisecond,
+			MaxDelay:     20 * time.Millisecond,
+		},
+	})
+
+	waitForAtLeast(t, &finishCalls, 1, time.Second)
+	assert.Less(t, atomic.LoadInt64(&attempts), int64(10), "таймаут должен ограничивать все попытки")
+	assert.ErrorIs(t, finishErr.Load().(error), context.DeadlineExceeded)
+}
+
+func TestScheduler_JobRetryAbortedByPanic(t *testing.T) {
+	var retryCalls, errorCalls int64
+	s := New(Config{JobHooks: JobHooks{
+		OnJobRetry: func(jobName string, attempt int, err error) {
+			atomic.AddInt64(&retryCalls, 1)
+		},
+		OnJobError: func(jobName string, err error) {
+			atomic.AddInt64(&errorCalls, 1)
+		},
+	}})
+	defer s.Stop()
+
+	var attempts int64
+	s.RunOnceAfter(0, func(ctx context.Context) error {
+		atomic.AddInt64(&attempts, 1)
+		panic("test panic")
+	}, JobOptions{Retry: testRetryConfig(5)})
+
+	waitForAtLeast(t, &errorCalls, 1, time.Second)
+	ensureNoIncrement(t, &attempts, 1, 100*time.Millisecond)
+	assert.Equal(t, int64(0), atomic.LoadInt64(&retryCalls), "паника должна прерывать повторы")
+}
+
+func TestScheduler_JobRetrySkipIfRunning(t *testing.T) {
+	s := New(Config{})
+
+	var active, maxActive, attempts int64
+	s.AddTickerJobWithOptions(10*time.Millisecond, func(ctx context.Context) error {
+		n := atomic.AddInt64(&active, 1)
+		defer atomic.AddInt64(&active, -1)
+		for {
+			m := atomic.LoadInt64(&maxActive)
+			if n <= m || atomic.CompareAndSwapInt64(&maxActive, m, n) {
+				break
+			}
+		}
+		atomic.AddInt64(&attempts, 1)
+		return errors.New("busy")
+	}, JobOptions{
+		OverlapPolicy: SkipIfRunning,
+		Retry: &retry.Config{
+			MaxAttempts:  3,
+			InitialDelay: 30 * time.Millisecond,
+			MaxDelay:     30 * time.Millisecond,
+		},
+	})
+
+	waitForAtLeast(t, &attempts, 4, 2*time.Second)
+	s.Stop()
+
+	require.Equal(t, int64(1), atomic.LoadInt64(&maxActive), "повторы должны считаться одним выполнением")
+}
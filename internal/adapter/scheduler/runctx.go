@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// runKey - ключ контекста с данными текущего выполнения задачи.
+type runKey struct{}
+
+// runInfo - данные выполнения, доступные задаче и хукам через контекст.
+type runInfo struct {
+	id      string
+	jobName string
+	logger  *slog.Logger
+}
+
+// withRun добавляет в контекст новый идентификатор выполнения, имя задачи и логгер
+// Config.Logger с атрибутами job и run_id.
+func (s *Scheduler) withRun(ctx context.Context, jobName string) context.Context {
+	id := uuid.NewString()
+	return context.WithValue(ctx, runKey{}, &runInfo{
+		id:      id,
+		jobName: jobName,
+		logger:  s.logger.With("job", jobName, "run_id", id),
+	})
+}
+
+// RunIDFrom возвращает идентификатор текущего выполнения задачи (UUID).
+// Он одинаков для задачи, всех её повторов и всех хуков одного выполнения и
+// различается между выполнениями. Шаг цепочки получает собственный идентификатор.
+// Возвращает false, если ctx не передан планировщиком.
+func RunIDFrom(ctx context.Context) (string, bool) {
+	if run, ok := ctx.Value(runKey{}).(*runInfo); ok {
+		return run.id, true
+	}
+	return "", false
+}
+
+// JobNameFrom возвращает имя выполняемой задачи ("unnamed" для задач без JobOptions.Name).
+// Возвращает false, если ctx не передан планировщиком.
+func JobNameFrom(ctx context.Context) (string, bool) {
+	if run, ok := ctx.Value(runKey{}).(*runInfo); ok {
+		return run.jobName, true
+	}
+	return "", false
+}
+
+// LoggerFrom возвращает логгер выполнения: Config.Logger с атрибутами job и run_id.
+// Если ctx не передан планировщиком, возвращает slog.Default().
+func LoggerFrom(ctx context.Context) *slog.Logger {
+	if run, ok := ctx.Value(runKey{}).(*runInfo); ok {
+		return run.logger
+	}
+	return slog.Default()
+}
+
+// hookStart вызывает OnJobStart и OnJobStartCtx.
+func (s *Scheduler) hookStart(ctx context.Context, jobName string) {
+	if s.hooks.OnJobStart != nil {
+		s.hooks.OnJobStart(jobName)
+	}
+	if s.hooks.OnJobStartCtx != nil {
+		s.hooks.OnJobStartCtx(ctx, jobName)
+	}
+}
+
+// hookFinish вызывает OnJobFinish и OnJobFinishCtx.
+func (s *Scheduler) hookFinish(ctx context.Context, jobName string, duration time.Duration, err error) {
+	if s.hooks.OnJobFinish != nil {
+		s.hooks.OnJobFinish(jobName, duration, err)
+	}
+	if s.hooks.OnJobFinishCtx != nil {
+		s.hooks.OnJobFinishCtx(ctx, jobName, duration, err)
+	}
+}
+
+// hookError вызывает OnJobError и OnJobErrorCtx.
+func (s *Scheduler) hookError(ctx context.Context, jobName string, err error) {
+	if s.hooks.OnJobError != nil {
+		s.hooks.OnJobError(jobName, err)
+	}
+	if s.hooks.OnJobErrorCtx != nil {
+		s.hooks.OnJobErrorCtx(ctx, jobName, err)
+	}
+}
+
+// hookRetry вызывает OnJobRetry и OnJobRetryCtx.
+func (s *Scheduler) hookRetry(ctx context.Context, jobName string, attempt int, err error) {
+	if s.hooks.OnJobRetry != nil {
+		s.hooks.OnJobRetry(jobName, attempt, err)
+	}
+	if s.hooks.OnJobRetryCtx != nil {
+		s.hooks.OnJobRetryCtx(ctx, jobName, attempt, err)
+	}
+}
+
+// hookSkipped вызывает OnJobSkipped и OnJobSkippedCtx.
+func (s *Scheduler) hookSkipped(ctx context.Context, jobName, reason string) {
+	if s.hooks.OnJobSkipped != nil {
+		s.hooks.OnJobSkipped(jobName, reason)
+	}
+	if s.hooks.OnJobSkippedCtx != nil {
+		s.hooks.OnJobSkippedCtx(ctx, jobName, reason)
+	}
+}
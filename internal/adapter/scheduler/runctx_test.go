@@ -0,0 +1,172 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runIDRecorder собирает run ID, увиденные задачей и хуками.
+type runIDRecorder struct {
+	mu  sync.Mutex
+	ids map[string][]string // источник -> run ID по порядку
+}
+
+func (r *runIDRecorder) record(source string, ctx context.Context) {
+	id, _ := RunIDFrom(ctx)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ids == nil {
+		r.ids = make(map[string][]string)
+	}
+	r.ids[source] = append(r.ids[source], id)
+}
+
+func (r *runIDRecorder) get(source string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.ids[source]...)
+}
+
+func TestScheduler_RunContext(t *testing.T) {
+	rec := &runIDRecorder{}
+	s := New(Config{JobHooks: JobHooks{
+		OnJobStartCtx: func(ctx context.Context, jobName string) { rec.record("start", ctx) },
+		OnJobRetryCtx: func(ctx context.Context, jobName string, attempt int, err error) { rec.record("retry", ctx) },
+		OnJobErrorCtx: func(ctx context.Context, jobName string, err error) { rec.record("error", ctx) },
+		OnJobFinishCtx: func(ctx context.Context, jobName string, duration time.Duration, err error) {
+			rec.record("finish", ctx)
+		},
+	}})
+	defer s.Stop()
+
+	var names []string
+	attempts := 0
+	id := s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error {
+		rec.record("job", ctx)
+		name, ok := JobNameFrom(ctx)
+		require.True(t, ok)
+		names = append(names, name)
+		attempts++
+		if attempts%2 == 1 {
+			return errors.New("temporary")
+		}
+		return nil
+	}, JobOptions{Name: "digest", Retry: testRetryConfig(3)})
+	s.Start()
+
+	// Два выполнения по две попытки
+	require.NoError(t, s.TriggerTickerJob(context.Background(), id))
+	require.NoError(t, s.TriggerTickerJob(context.Background(), id))
+
+	jobIDs := rec.get("job")
+	require.Len(t, jobIDs, 4)
+	assert.Equal(t, jobIDs[0], jobIDs[1], "run ID одинаков для всех попыток выполнения")
+	assert.Equal(t, jobIDs[2], jobIDs[3])
+	assert.NotEqual(t, jobIDs[0], jobIDs[2], "run ID различается между выполнениями")
+	_, err := uuid.Parse(jobIDs[0])
+	assert.NoError(t, err)
+
+	runs := []string{jobIDs[0], jobIDs[2]}
+	assert.Equal(t, runs, rec.get("start"))
+	assert.Equal(t, runs, rec.get("retry"))
+	assert.Equal(t, runs, rec.get("finish"))
+	assert.Empty(t, rec.get("error"))
+	assert.Equal(t, []string{"digest", "digest", "digest", "digest"}, names)
+}
+
+func TestScheduler_RunContextSkipped(t *testing.T) {
+	var skippedID string
+	s := New(Config{JobHooks: JobHooks{
+		OnJobSkippedCtx: func(ctx context.Context, jobName string, reason string) {
+			skippedID, _ = RunIDFrom(ctx)
+			assert.Equal(t, SkipReasonRunning, reason)
+		},
+	}})
+	defer s.Stop()
+
+	started := make(chan string)
+	release := make(chan struct{})
+	id := s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error {
+		runID, _ := RunIDFrom(ctx)
+		started <- runID
+		<-release
+		return nil
+	}, JobOptions{OverlapPolicy: SkipIfRunning})
+	s.Start()
+
+	go func() { _ = s.TriggerTickerJob(context.Background(), id) }()
+	runningID := <-started
+	require.ErrorIs(t, s.TriggerTickerJob(context.Background(), id), ErrJobAlreadyRunning)
+	close(release)
+
+	assert.NotEmpty(t, skippedID)
+	assert.NotEqual(t, runningID, skippedID, "пропущенный запуск - отдельное выполнение")
+}
+
+func TestScheduler_LoggerFrom(t *testing.T) {
+	var buf bytes.Buffer
+	var mu sync.Mutex
+	logger := slog.New(slog.NewTextHandler(&lockedWriter{mu: &mu, w: &buf}, nil))
+	s := New(Config{Logger: logger})
+	defer s.Stop()
+
+	var runID string
+	id := s.AddTickerJob(time.Hour, func(ctx context.Context) error {
+		runID, _ = RunIDFrom(ctx)
+		LoggerFrom(ctx).Info("processing")
+		return nil
+	})
+	s.Start()
+	require.NoError(t, s.TriggerTickerJob(context.Background(), id))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(t, buf.String(), "msg=processing job=unnamed run_id="+runID)
+}
+
+func TestRunContextOutsideScheduler(t *testing.T) {
+	ctx := context.Background()
+	id, ok := RunIDFrom(ctx)
+	assert.False(t, ok)
+	assert.Empty(t, id)
+	_, ok = JobNameFrom(ctx)
+	assert.False(t, ok)
+	assert.Same(t, slog.Default(), LoggerFrom(ctx))
+}
+
+func TestJobHooks_ChainCtx(t *testing.T) {
+	var calls []string
+	first := JobHooks{OnJobStartCtx: func(ctx context.Context, name string) { calls = append(calls, "first "+name) }}
+	second := JobHooks{
+		OnJobStartCtx: func(ctx context.Context, name string) { calls = append(calls, "second "+name) },
+		OnJobFinishCtx: func(ctx context.Context, name string, d time.Duration, err error) {
+			calls = append(calls, "finish "+name)
+		},
+	}
+	hooks := first.Chain(second)
+	hooks.OnJobStartCtx(context.Background(), "a")
+	hooks.OnJobFinishCtx(context.Background(), "a", time.Second, nil)
+	assert.Equal(t, []string{"first a", "second a", "finish a"}, calls)
+	assert.Nil(t, hooks.OnJobRetryCtx)
+}
+
+// lockedWriter сериализует запись в буфер из нескольких горутин.
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  *bytes.Buffer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
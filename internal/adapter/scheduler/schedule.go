@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	// ErrInvalidSchedule - синтаксическая ошибка в cron-расписании.
+	ErrInvalidSchedule = errors.New("scheduler: invalid cron schedule")
+	// ErrUnsupportedDescriptor - расписание начинается с неизвестного дескриптора вида "@name".
+	// Поддерживаются @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly и @every.
+	ErrUnsupportedDescriptor = errors.New("scheduler: unsupported schedule descriptor")
+)
+
+// ScheduleFormat определяет допустимое число полей cron-расписания (см. Config.ScheduleFormat).
+// Дескрипторы (@hourly, @every 5m и т.д.) допускаются при любом формате.
+type ScheduleFormat int
+
+const (
+	// SecondsOptional допускает шесть полей с секундами и стандартные пять полей,
+	// при которых секунды равны 0 ("30 9 * * 1" - в 9:30:00 по понедельникам). По умолчанию.
+	SecondsOptional ScheduleFormat = iota
+	// SecondsRequired требует шесть полей, первое - секунды (как cron.WithSeconds()).
+	SecondsRequired
+	// Standard5Field допускает только стандартные пять полей без секунд.
+	Standard5Field
+)
+
+// String возвращает описание формата для сообщений об ошибках.
+func (f ScheduleFormat) String() string {
+	switch f {
+	case SecondsRequired:
+		return "6 fields with seconds"
+	case Standard5Field:
+		return "5 fields without seconds"
+	default:
+		return "5 fields or 6 fields with seconds"
+	}
+}
+
+// scheduleParsers - парсеры расписаний для каждого формата.
+var scheduleParsers = map[ScheduleFormat]cron.Parser{
+	SecondsOptional: cron.NewParser(
+		cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+	),
+	SecondsRequired: cron.NewParser(
+		cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+	),
+	Standard5Field: cron.NewParser(
+		cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+	),
+}
+
+// parser возвращает парсер формата; неизвестные значения считаются SecondsOptional.
+func (f ScheduleFormat) parser() cron.Parser {
+	if p, ok := scheduleParsers[f]; ok {
+		return p
+	}
+	return scheduleParsers[SecondsOptional]
+}
+
+// ValidateSchedule проверяет cron-расписание в формате по умолчанию (SecondsOptional)
+// так же, как AddCronJob, не добавляя задачу. Для планировщика с другим
+// Config.ScheduleFormat используйте Scheduler.ValidateSchedule.
+// Возвращает ошибку, соответствующую ErrUnsupportedDescriptor для неизвестного дескриптора
+// и ErrInvalidSchedule для остальных ошибок.
+func ValidateSchedule(spec string) error {
+	_, err := parseSchedule(spec, SecondsOptional)
+	return err
+}
+
+// ValidateSchedule проверяет cron-расписание в формате Config.ScheduleFormat этого
+// планировщика, т.е. ровно так, как его примет AddCronJob.
+func (s *Scheduler) ValidateSchedule(spec string) error {
+	_, err := parseSchedule(spec, s.cronFormat)
+	return err
+}
+
+// NextOccurrences возвращает до n ближайших моментов запуска по расписанию строго после from.
+// Расписание разбирается в формате SecondsOptional.
+// loc - часовой пояс, в котором вычисляется расписание (nil - time.Local, как у планировщика);
+// префикс CRON_TZ= или TZ= в расписании имеет приоритет. Время возвращается в loc.
+// Результат короче n, если у расписания нет запусков в ближайшие пять лет (например, 30 февраля).
+func NextOccurrences(spec string, from time.Time, n int, loc *time.Location) ([]time.Time, error) {
+	schedule, err := parseSchedule(spec, SecondsOptional)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+
+	occurrences := make([]time.Time, 0, max(n, 0))
+	next := from.In(loc)
+	for range n {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			break
+		}
+		occurrences = append(occurrences, next.In(loc))
+	}
+	return occurrences, nil
+}
+
+// parseSchedule разбирает расписание в формате format и классифицирует ошибку разбора.
+func parseSchedule(spec string, format ScheduleFormat) (cron.Schedule, error) {
+	schedule, err := format.parser().Parse(spec)
+	if err == nil {
+		return schedule, nil
+	}
+
+	body := strings.TrimSpace(spec)
+	if strings.HasPrefix(body, "TZ=") || strings.HasPrefix(body, "CRON_TZ=") {
+		if i := strings.IndexByte(body, ' '); i >= 0 {
+			body = strings.TrimSpace(body[i:])
+		}
+	}
+	if strings.HasPrefix(body, "@") && !strings.HasPrefix(body, "@every ") {
+		return nil, fmt.Errorf("%w %q: %w", ErrUnsupportedDescriptor, spec, err)
+	}
+	return nil, fmt.Errorf("%w %q (expected %s): %w", ErrInvalidSchedule, spec, format, err)
+}
@@ -0,0 +1,155 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr error
+	}{
+		{name: "шесть полей", spec: "0 30 * * * *"},
+		{name: "дескриптор", spec: "@hourly"},
+		{name: "интервал", spec: "@every 5m"},
+		{name: "часовой пояс", spec: "CRON_TZ=Europe/Moscow 0 0 9 * * *"},
+		{name: "пять полей без секунд", spec: "30 9 * * 1"},
+		{name: "пять полей с поясом", spec: "CRON_TZ=UTC 30 9 * * 1"},
+		{name: "четыре поля", spec: "30 9 * *", wantErr: ErrInvalidSchedule},
+		{name: "мусор", spec: "invalid schedule", wantErr: ErrInvalidSchedule},
+		{name: "пустое", spec: "", wantErr: ErrInvalidSchedule},
+		{name: "значение вне диапазона", spec: "0 61 * * * *", wantErr: ErrInvalidSchedule},
+		{name: "неверный интервал", spec: "@every soon", wantErr: ErrInvalidSchedule},
+		{name: "неизвестный часовой пояс", spec: "CRON_TZ=Mars/Base 0 0 9 * * *", wantErr: ErrInvalidSchedule},
+		{name: "неизвестный дескриптор", spec: "@fortnightly", wantErr: ErrUnsupportedDescriptor},
+		{name: "неизвестный дескриптор с поясом", spec: "TZ=UTC @reboot", wantErr: ErrUnsupportedDescriptor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSchedule(tt.spec)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), tt.spec)
+		})
+	}
+}
+
+func TestScheduleFormat(t *testing.T) {
+	tests := []struct {
+		spec     string
+		optional bool
+		required bool
+		standard bool
+	}{
+		{spec: "0 30 9 * * 1", optional: true, required: true},
+		{spec: "30 9 * * 1", optional: true, standard: true},
+		{spec: "@hourly", optional: true, required: true, standard: true},
+		{spec: "@every 5m", optional: true, required: true, standard: true},
+		{spec: "0 0 30 9 * * 1"},
+	}
+
+	formats := []ScheduleFormat{SecondsOptional, SecondsRequired, Standard5Field}
+	for _, tt := range tests {
+		for i, want := range []bool{tt.optional, tt.required, tt.standard} {
+			format := formats[i]
+			t.Run(format.String()+"/"+tt.spec, func(t *testing.T) {
+				s := New(Config{ScheduleFormat: format})
+				defer s.Stop()
+
+				validateErr := s.ValidateSchedule(tt.spec)
+				_, addErr := s.AddCronJob(tt.spec, func(context.Context) error { return nil })
+				if want {
+					assert.NoError(t, validateErr)
+					assert.NoError(t, addErr)
+					return
+				}
+				// ValidateSchedule и AddCronJob согласованы
+				assert.ErrorIs(t, validateErr, ErrInvalidSchedule)
+				assert.ErrorIs(t, addErr, ErrInvalidSchedule)
+				assert.Contains(t, validateErr.Error(), "expected "+format.String())
+			})
+		}
+	}
+}
+
+func TestScheduleFormat_FiveFieldsRunAtZeroSeconds(t *testing.T) {
+	from := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC) // понедельник
+	got, err := NextOccurrences("30 9 * * 1", from, 1, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{time.Date(2024, 1, 8, 9, 30, 0, 0, time.UTC)}, got)
+
+	for _, format := range []ScheduleFormat{SecondsOptional, Standard5Field} {
+		schedule, err := parseSchedule("30 9 * * 1", format)
+		require.NoError(t, err)
+		assert.Equal(t, got[0], schedule.Next(from.In(time.UTC)))
+	}
+}
+
+func TestNextOccurrences(t *testing.T) {
+	from := time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)
+
+	got, err := NextOccurrences("0 0 * * * *", from, 3, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC),
+	}, got)
+
+	t.Run("часовой пояс", func(t *testing.T) {
+		moscow := time.FixedZone("MSK", 3*60*60)
+		got, err := NextOccurrences("0 0 9 * * *", from, 2, moscow)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, time.Date(2024, 1, 2, 9, 0, 0, 0, moscow), got[0])
+		assert.Equal(t, time.Date(2024, 1, 3, 9, 0, 0, 0, moscow), got[1])
+		assert.Equal(t, moscow, got[0].Location())
+	})
+
+	t.Run("префикс CRON_TZ важнее loc", func(t *testing.T) {
+		got, err := NextOccurrences("CRON_TZ=UTC 0 0 9 * * *", from, 1, time.FixedZone("MSK", 3*60*60))
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.True(t, got[0].Equal(time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("nil - локальный пояс", func(t *testing.T) {
+		got, err := NextOccurrences("@daily", from, 1, nil)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, time.Local, got[0].Location())
+	})
+
+	t.Run("интервал", func(t *testing.T) {
+		got, err := NextOccurrences("@every 10m", from, 2, time.UTC)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Time{from.Add(10 * time.Minute), from.Add(20 * time.Minute)}, got)
+	})
+
+	t.Run("нет запусков", func(t *testing.T) {
+		got, err := NextOccurrences("0 0 0 30 2 *", from, 5, time.UTC)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("n не положительное", func(t *testing.T) {
+		got, err := NextOccurrences("@hourly", from, 0, time.UTC)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("ошибка разбора", func(t *testing.T) {
+		_, err := NextOccurrences("@fortnightly", from, 5, time.UTC)
+		assert.ErrorIs(t, err, ErrUnsupportedDescriptor)
+	})
+}
@@ -2,12 +2,17 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/robfig/cron/v3"
+	"golang.org/x/sync/semaphore"
+
+	"sttbot/pkg/retry"
 )
 
 // JobFunc представляет функцию задачи планировщика.
@@ -28,17 +33,85 @@ const (
 	// SkipIfRunning пропускает выполнение, если задача уже запущена.
 	SkipIfRunning
 	// DelayIfRunning ждет завершения предыдущего выполнения.
+	// Число ожидающих запусков ограничено JobOptions.MaxQueuedRuns.
 	DelayIfRunning
 )
 
+// DrainPolicy определяет судьбу запусков, ожидающих в очереди DelayIfRunning,
+// при остановке планировщика.
+type DrainPolicy int
+
+const (
+	// DropPending отбрасывает ожидающие запуски после начала остановки (по умолчанию).
+	DropPending DrainPolicy = iota
+	// RunPending выполняет уже поставленные в очередь запуски во время остановки.
+	// Задачи получают отменённый контекст планировщика.
+	RunPending
+)
+
+// Причины пропуска выполнения, передаваемые в JobHooks.OnJobSkipped.
+const (
+	// SkipReasonRunning - задача уже выполняется (SkipIfRunning).
+	SkipReasonRunning = "already running"
+	// SkipReasonQueueFull - превышен лимит ожидающих запусков (DelayIfRunning).
+	SkipReasonQueueFull = "queue full"
+	// SkipReasonConcurrencyLimit - не дождались слота Config.MaxConcurrentJobs
+	// за JobOptions.AcquireTimeout.
+	SkipReasonConcurrencyLimit = "concurrency limit"
+	// SkipReasonThrottled - с завершения предыдущего выполнения прошло меньше
+	// JobOptions.MinInterval.
+	SkipReasonThrottled = "throttled"
+)
+
 // JobOptions содержит опции для настройки задач.
 type JobOptions struct {
 	// Name - имя задачи для логирования (необязательно).
 	Name string
+	// Key - ключ задачи для Config.JobStore (необязательно). Cron- и ticker-задачи
+	// с Key сохраняются в хранилище и восстанавливаются через RestoreJobs.
+	Key string
 	// Timeout - максимальное время выполнения задачи (необязательно).
 	Timeout time.Duration
 	// OverlapPolicy - политика обработки перекрывающихся выполнений.
 	OverlapPolicy OverlapPolicy
+	// MaxQueuedRuns - максимальное число запусков, ожидающих завершения
+	// текущего при DelayIfRunning (по умолчанию 1). Лишние запуски отбрасываются.
+	MaxQueuedRuns int
+	// Jitter - случайное смещение каждого тика в пределах ±Jitter (только для ticker-задач).
+	Jitter time.Duration
+	// InitialDelay - задержка перед первым тиком вместо полного интервала (только для ticker-задач).
+	InitialDelay time.Duration
+	// RunImmediately - однократный запуск сразу после Start (только для ticker-задач).
+	RunImmediately bool
+	// Retry - политика повторов при ошибке задачи (необязательно).
+	// Timeout ограничивает все попытки вместе, а не каждую по отдельности.
+	Retry *retry.Config
+	// RetryIf определяет, какие ошибки повторять (по умолчанию все, кроме context.Canceled).
+	RetryIf retry.IsRetryableFunc
+	// Misfire - политика обработки срабатываний, пропущенных до Start (только для cron-задач).
+	Misfire MisfirePolicy
+	// LastRun - время последнего завершённого выполнения из внешнего хранилища,
+	// от которого считаются пропуски (только для cron-задач). Сохранять его
+	// удобно в JobHooks.OnJobFinish. Нулевое значение отключает Misfire.
+	LastRun time.Time
+	// MaxCatchUp - максимальное число догоняющих запусков при MisfireCatchUpAll
+	// (по умолчанию 10).
+	MaxCatchUp int
+	// AcquireTimeout - максимальное время ожидания слота при Config.MaxConcurrentJobs > 0
+	// (по умолчанию ждать без ограничения). По истечении выполнение пропускается.
+	AcquireTimeout time.Duration
+	// Critical - состояние задачи учитывается в HealthReport.Healthy (см. Scheduler.Health).
+	Critical bool
+	// StuckThreshold - выполнение дольше этого времени считается зависшим
+	// (по умолчанию StuckTimeoutFactor×Timeout; без Timeout не проверяется).
+	StuckThreshold time.Duration
+	// MinInterval - минимальное время от завершения предыдущего выполнения до
+	// следующего, общее для запусков по расписанию и ручных (TriggerCronJob,
+	// TriggerTickerJob). Более ранние запуски пропускаются с SkipReasonThrottled.
+	MinInterval time.Duration
+	// TrailingRun - вместо пропуска по MinInterval выполнить один отложенный
+	// запуск по истечении интервала; повторные запросы объединяются с ним.
+	TrailingRun bool
 }
 
 // jobWrapper оборачивает задачу с её опциями.
@@ -46,14 +119,42 @@ type jobWrapper struct {
 	job     JobFunc
 	options JobOptions
 	running sync.Mutex // для контроля перекрытий
+	statsMu sync.Mutex // защищает stats
+	stats   jobStats
+	paused  atomic.Bool
+	queued  atomic.Int32 // число запусков, ожидающих running
+	// trailing - запланирован отложенный запуск JobOptions.TrailingRun
+	trailing atomic.Bool
+	// recordID - ID записи в Config.JobStore (пусто, если задача не сохраняется)
+	recordID string
+	// addedAt - время добавления задачи, от него считается первый ожидаемый запуск
+	addedAt time.Time
+	// ctx - контекст расписания ticker- и one-shot-задачи, отменяется при её удалении
+	// (nil для cron-задач)
+	ctx context.Context
+}
+
+// maxQueuedRuns возвращает лимит ожидающих запусков с учетом значения по умолчанию.
+func (w *jobWrapper) maxQueuedRuns() int {
+	if w.options.MaxQueuedRuns <= 0 {
+		return 1
+	}
+	return w.options.MaxQueuedRuns
+}
+
+// cronJob содержит информацию о cron-задаче.
+type cronJob struct {
+	id       CronJobID
+	schedule string
+	wrapper  *jobWrapper
 }
 
 // tickerJob содержит информацию о ticker-задаче.
 type tickerJob struct {
-	id      TickerJobID
-	ticker  *time.Ticker
-	cancel  context.CancelFunc
-	wrapper *jobWrapper
+	id       TickerJobID
+	interval time.Duration
+	cancel   context.CancelFunc
+	wrapper  *jobWrapper
 }
 
 // cronLogger адаптер для интеграции cron logger с slog.
@@ -88,30 +189,72 @@ func (l cronLogger) Error(err error, msg string, keysAndValues ...interface{}) {
 
 // Scheduler управляет периодическими задачами.
 type Scheduler struct {
-	cron         *cron.Cron
-	logger       *slog.Logger
-	hooks        JobHooks
-	ctx          context.Context
-	cancel       context.CancelFunc
-	wg           sync.WaitGroup
-	tickerJobs   map[TickerJobID]*tickerJob
-	nextTickerID TickerJobID
-	mu           sync.Mutex
-	stopOnce     sync.Once
-	startOnce    sync.Once
+	cron          *cron.Cron
+	logger        *slog.Logger
+	hooks         JobHooks
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+	cronJobs      map[CronJobID]*cronJob
+	tickerJobs    map[TickerJobID]*tickerJob
+	nextTickerID  TickerJobID
+	oneShotJobs   map[OneShotJobID]*oneShotJob
+	nextOneShotID OneShotJobID
+	chains        map[ChainID]*chain
+	nextChainID   ChainID
+	names         map[string]namedJob // индекс задач по имени (AddNamedCronJob), защищён mu
+	mu            sync.Mutex
+	stopOnce      sync.Once
+	startOnce     sync.Once
+	started       chan struct{}       // закрывается при Start
+	slots         *semaphore.Weighted // глобальный лимит одновременных задач (nil - без лимита)
+	maxConcurrent int
+	store         JobStore
+	drainPolicy   DrainPolicy
+	// cronFormat - формат cron-расписаний (Config.ScheduleFormat)
+	cronFormat ScheduleFormat
+	startedAt  time.Time // время Start, защищено mu
 }
 
 // JobHooks содержит необязательные хуки для наблюдаемости.
+//
+// Хуки с суффиксом Ctx вызываются после одноимённых хуков без суффикса и получают
+// контекст выполнения: RunIDFrom, JobNameFrom и LoggerFrom возвращают те же значения,
+// что видит задача, поэтому все хуки одного выполнения связаны одним run ID.
 type JobHooks struct {
 	OnJobStart  func(jobName string)
 	OnJobFinish func(jobName string, duration time.Duration, err error)
 	OnJobError  func(jobName string, err error)
+	// OnJobRetry вызывается при неудачной попытке, после которой будет повтор.
+	OnJobRetry func(jobName string, attempt int, err error)
+	// OnJobSkipped вызывается при пропуске выполнения из-за политики перекрытий.
+	OnJobSkipped func(jobName string, reason string)
+
+	OnJobStartCtx   func(ctx context.Context, jobName string)
+	OnJobFinishCtx  func(ctx context.Context, jobName string, duration time.Duration, err error)
+	OnJobErrorCtx   func(ctx context.Context, jobName string, err error)
+	OnJobRetryCtx   func(ctx context.Context, jobName string, attempt int, err error)
+	OnJobSkippedCtx func(ctx context.Context, jobName string, reason string)
 }
 
 // Config содержит конфигурацию планировщика.
 type Config struct {
 	Logger   *slog.Logger
 	JobHooks JobHooks
+	// MaxConcurrentJobs - максимальное число одновременно выполняемых задач всех типов
+	// (по умолчанию 0 - без ограничения). Задачи сверх лимита ждут свободного слота.
+	MaxConcurrentJobs int
+	// JobStore - хранилище определений задач с JobOptions.Key (необязательно).
+	// Задачи сохраняются при добавлении и удаляются из хранилища при RemoveCronJob
+	// и RemoveTickerJob, но не при Stop. См. RestoreJobs.
+	JobStore JobStore
+	// DrainPolicy - выполнять ли при остановке запуски, ожидающие в очереди
+	// DelayIfRunning (по умолчанию DropPending).
+	DrainPolicy DrainPolicy
+	// ScheduleFormat - допустимый формат cron-расписаний (по умолчанию SecondsOptional:
+	// пять или шесть полей). Действует на AddCronJob, AddChain, RestoreJobs
+	// и Scheduler.ValidateSchedule.
+	ScheduleFormat ScheduleFormat
 }
 
 // New создает новый экземпляр планировщика с background контекстом.
@@ -130,18 +273,35 @@ func NewWithContext(parentCtx context.Context, cfg Config) *Scheduler {
 
 	// Создаем cron с интегрированным логгером
 	cronOpts := []cron.Option{
-		cron.WithSeconds(),
+		cron.WithParser(cfg.ScheduleFormat.parser()),
 		cron.WithLogger(cronLogger{logger: logger.With("component", "cron")}),
 	}
 
+	var slots *semaphore.Weighted
+	if cfg.MaxConcurrentJobs > 0 {
+		slots = semaphore.NewWeighted(int64(cfg.MaxConcurrentJobs))
+	}
+
 	return &Scheduler{
-		cron:         cron.New(cronOpts...),
-		logger:       logger,
-		hooks:        cfg.JobHooks,
-		ctx:          ctx,
-		cancel:       cancel,
-		tickerJobs:   make(map[TickerJobID]*tickerJob),
-		nextTickerID: 1,
+		cron:          cron.New(cronOpts...),
+		logger:        logger,
+		hooks:         cfg.JobHooks,
+		ctx:           ctx,
+		cancel:        cancel,
+		cronJobs:      make(map[CronJobID]*cronJob),
+		tickerJobs:    make(map[TickerJobID]*tickerJob),
+		nextTickerID:  1,
+		oneShotJobs:   make(map[OneShotJobID]*oneShotJob),
+		nextOneShotID: 1,
+		chains:        make(map[ChainID]*chain),
+		nextChainID:   1,
+		names:         make(map[string]namedJob),
+		started:       make(chan struct{}),
+		slots:         slots,
+		maxConcurrent: cfg.MaxConcurrentJobs,
+		store:         cfg.JobStore,
+		drainPolicy:   cfg.DrainPolicy,
+		cronFormat:    cfg.ScheduleFormat,
 	}
 }
 
@@ -156,30 +316,46 @@ func (s *Scheduler) AddCronJob(schedule string, job JobFunc) (CronJobID, error)
 
 // AddCronJobWithOptions добавляет задачу по cron-расписанию с указанными опциями.
 func (s *Scheduler) AddCronJobWithOptions(schedule string, job JobFunc, opts JobOptions) (CronJobID, error) {
-	wrapper := &jobWrapper{
-		job:     job,
-		options: opts,
-	}
+	return s.addCronJob(schedule, job, opts, "")
+}
 
-	// Создаем цепочку для обработки перекрытий
-	var chain cron.Chain
-	switch opts.OverlapPolicy {
-	case SkipIfRunning:
-		chain = cron.NewChain(cron.SkipIfStillRunning(cron.DefaultLogger))
-	case DelayIfRunning:
-		chain = cron.NewChain(cron.DelayIfStillRunning(cron.DefaultLogger))
-	default: // AllowOverlap
-		chain = cron.NewChain()
+// addCronJob добавляет cron-задачу. Пустой recordID означает новую задачу,
+// которая сохраняется в JobStore, если задан JobOptions.Key.
+func (s *Scheduler) addCronJob(schedule string, job JobFunc, opts JobOptions, recordID string) (CronJobID, error) {
+	wrapper := &jobWrapper{
+		job:      job,
+		options:  opts,
+		recordID: recordID,
+		addedAt:  time.Now(),
 	}
 
-	id, err := s.cron.AddJob(schedule, chain.Then(cron.FuncJob(func() {
-		s.runJobWrapper(wrapper)
-	})))
+	parsed, err := parseSchedule(schedule, s.cronFormat)
 	if err != nil {
 		s.logger.Error("failed to add cron job", "schedule", schedule, "name", opts.Name, "error", err)
 		return 0, err
 	}
 
+	// Перекрытия обрабатываются в runJobWrapper, чтобы хуки и лимит очереди
+	// работали одинаково для cron- и ticker-задач
+	id := s.cron.Schedule(parsed, cron.FuncJob(func() {
+		s.runJobWrapper(wrapper)
+	}))
+
+	if recordID == "" {
+		wrapper.recordID = s.persistJob(JobRecord{
+			Key:      opts.Key,
+			Type:     JobTypeCron,
+			Schedule: schedule,
+			Options:  storedOptions(opts),
+		})
+	}
+
+	s.mu.Lock()
+	s.cronJobs[id] = &cronJob{id: id, schedule: schedule, wrapper: wrapper}
+	s.mu.Unlock()
+
+	s.scheduleCatchUp(id, wrapper, s.cron.Entry(id).Schedule)
+
 	s.logger.Info("cron job added", "schedule", schedule, "name", opts.Name, "overlap_policy", opts.OverlapPolicy, "id", id)
 	return id, nil
 }
@@ -191,23 +367,42 @@ func (s *Scheduler) AddTickerJob(interval time.Duration, job JobFunc) TickerJobI
 
 // AddTickerJobWithOptions добавляет задачу с фиксированным интервалом с указанными опциями.
 func (s *Scheduler) AddTickerJobWithOptions(interval time.Duration, job JobFunc, opts JobOptions) TickerJobID {
+	if interval <= 0 {
+		panic("scheduler: non-positive interval for ticker job")
+	}
+	return s.addTickerJob(interval, job, opts, "")
+}
+
+// addTickerJob добавляет ticker-задачу. Пустой recordID означает новую задачу,
+// которая сохраняется в JobStore, если задан JobOptions.Key.
+func (s *Scheduler) addTickerJob(interval time.Duration, job JobFunc, opts JobOptions, recordID string) TickerJobID {
 	wrapper := &jobWrapper{
-		job:     job,
-		options: opts,
+		job:      job,
+		options:  opts,
+		recordID: recordID,
+		addedAt:  time.Now(),
+	}
+	if recordID == "" {
+		wrapper.recordID = s.persistJob(JobRecord{
+			Key:      opts.Key,
+			Type:     JobTypeTicker,
+			Interval: interval,
+			Options:  storedOptions(opts),
+		})
 	}
 
 	s.mu.Lock()
 	id := s.nextTickerID
 	s.nextTickerID++
 
-	ticker := time.NewTicker(interval)
 	ctx, cancel := context.WithCancel(s.ctx)
+	wrapper.ctx = ctx
 
 	tickerJob := &tickerJob{
-		id:      id,
-		ticker:  ticker,
-		cancel:  cancel,
-		wrapper: wrapper,
+		id:       id,
+		interval: interval,
+		cancel:   cancel,
+		wrapper:  wrapper,
 	}
 
 	s.tickerJobs[id] = tickerJob
@@ -216,43 +411,51 @@ func (s *Scheduler) AddTickerJobWithOptions(interval time.Duration, job JobFunc,
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
-		defer ticker.Stop()
 		defer cancel()
 
-		for {
-			select {
-			case <-ticker.C:
-				s.runJobWrapper(wrapper)
-			case <-ctx.Done():
-				s.logger.Debug("ticker job stopped due to context cancellation", "name", opts.Name, "id", id)
-				return
-			}
-		}
+		s.runTickerLoop(ctx, tickerJob)
+		s.logger.Debug("ticker job stopped due to context cancellation", "name", opts.Name, "id", id)
 	}()
 
 	s.logger.Info("ticker job added", "interval", interval, "name", opts.Name, "overlap_policy", opts.OverlapPolicy, "id", id)
 	return id
 }
 
-// RemoveCronJob удаляет cron-задачу по ID.
+// RemoveCronJob удаляет cron-задачу по ID (и её запись из Config.JobStore).
 func (s *Scheduler) RemoveCronJob(id CronJobID) {
 	s.cron.Remove(id)
+
+	s.mu.Lock()
+	job, exists := s.cronJobs[id]
+	delete(s.cronJobs, id)
+	if exists {
+		s.unindexLocked(job.wrapper.options.Name, JobTypeCron, int(id))
+	}
+	s.mu.Unlock()
+
+	if exists {
+		s.forgetJob(job.wrapper)
+	}
+
 	s.logger.Info("cron job removed", "id", id)
 }
 
-// RemoveTickerJob удаляет ticker-задачу по ID.
+// RemoveTickerJob удаляет ticker-задачу по ID (и её запись из Config.JobStore).
 func (s *Scheduler) RemoveTickerJob(id TickerJobID) bool {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	job, exists := s.tickerJobs[id]
 	if !exists {
+		s.mu.Unlock()
 		return false
 	}
 
 	// Отменяем контекст задачи
 	job.cancel()
 	delete(s.tickerJobs, id)
+	s.unindexLocked(job.wrapper.options.Name, JobTypeTicker, int(id))
+	s.mu.Unlock()
+
+	s.forgetJob(job.wrapper)
 
 	s.logger.Info("ticker job removed", "id", id, "name", job.wrapper.options.Name)
 	return true
@@ -262,7 +465,11 @@ func (s *Scheduler) RemoveTickerJob(id TickerJobID) bool {
 func (s *Scheduler) Start() {
 	s.startOnce.Do(func() {
 		s.logger.Info("starting scheduler")
+		s.mu.Lock()
+		s.startedAt = time.Now()
+		s.mu.Unlock()
 		s.cron.Start()
+		close(s.started)
 
 		// Запускаем горутину для отслеживания контекста
 		go func() {
@@ -325,6 +532,11 @@ func (s *Scheduler) stop() {
 	for _, job := range s.tickerJobs {
 		job.cancel()
 	}
+	for id, job := range s.oneShotJobs {
+		job.cancel()
+		delete(s.oneShotJobs, id)
+	}
+	clear(s.names)
 	s.mu.Unlock()
 
 	// Ждем завершения всех горутин
@@ -332,67 +544,170 @@ func (s *Scheduler) stop() {
 	s.logger.Info("scheduler stopped")
 }
 
-// runJobWrapper выполняет задачу с учетом её опций.
+// runJobWrapper выполняет задачу по расписанию с учетом её опций.
 func (s *Scheduler) runJobWrapper(wrapper *jobWrapper) {
+	_ = s.runJob(s.ctx, wrapper, false)
+}
+
+// runJob выполняет задачу с учетом политики перекрытий, таймаута, ретраев и хуков.
+// Возвращает ошибку задачи (паника преобразуется в ошибку), ErrJobAlreadyRunning,
+// ErrJobQueueFull, ErrJobThrottled или ErrConcurrencyLimit при пропуске выполнения, ошибку parent,
+// если он отменён во время ожидания слота, и ошибку контекста, если запуск отброшен
+// при остановке или удалении задачи (см. DrainPolicy). Приостановленная задача пропускается,
+// если запуск не ручной (manual). Задача и хуки получают контекст выполнения (см. RunIDFrom).
+func (s *Scheduler) runJob(parent context.Context, wrapper *jobWrapper, manual bool) (err error) {
 	jobName := wrapper.options.Name
 	if jobName == "" {
-		jobName = "unnamed"
+		jobName = unnamedJob
 	}
+	parent = s.withRun(parent, jobName)
 
-	// Обработка политики перекрытий для ticker задач
+	if !manual && wrapper.paused.Load() {
+		s.logger.Debug("skipping job execution, paused", "name", jobName)
+		return nil
+	}
+
+	if s.throttled(parent, wrapper, jobName) {
+		return ErrJobThrottled
+	}
+
+	// Обработка политики перекрытий
 	if wrapper.options.OverlapPolicy != AllowOverlap {
 		if wrapper.options.OverlapPolicy == SkipIfRunning {
 			if !wrapper.running.TryLock() {
-				s.logger.Debug("skipping job execution, already running", "name", jobName)
-				return
+				s.skipJob(parent, jobName, SkipReasonRunning)
+				return ErrJobAlreadyRunning
 			}
 			defer wrapper.running.Unlock()
 		} else if wrapper.options.OverlapPolicy == DelayIfRunning {
-			wrapper.running.Lock()
+			if !wrapper.running.TryLock() {
+				if wrapper.queued.Add(1) > int32(wrapper.maxQueuedRuns()) {
+					wrapper.queued.Add(-1)
+					s.skipJob(parent, jobName, SkipReasonQueueFull)
+					return ErrJobQueueFull
+				}
+				wrapper.running.Lock()
+				wrapper.queued.Add(-1)
+				// Проверка MinInterval выше видела время завершения ещё до окончания
+				// предыдущего выполнения, поэтому повторяем её от его фактического конца
+				if s.throttled(parent, wrapper, jobName) {
+					wrapper.running.Unlock()
+					return ErrJobThrottled
+				}
+			}
 			defer wrapper.running.Unlock()
 		}
 	}
 
-	// Вызываем хук начала задачи
-	if s.hooks.OnJobStart != nil {
-		s.hooks.OnJobStart(jobName)
+	// Пока запуск ждал блокировку, могла начаться остановка или задача могла быть удалена
+	if !manual {
+		if err := s.dropErr(wrapper); err != nil {
+			s.logger.Debug("job run dropped", "name", jobName, "error", err)
+			return err
+		}
+	}
+
+	// Глобальный лимит занимается после политики перекрытий, чтобы ожидающие
+	// в очереди запуски не держали слоты
+	release, err := s.acquireSlot(parent, wrapper)
+	if err != nil {
+		if errors.Is(err, ErrConcurrencyLimit) {
+			s.skipJob(parent, jobName, SkipReasonConcurrencyLimit)
+		} else {
+			s.logger.Debug("job aborted while waiting for a free slot", "name", jobName, "error", err)
+		}
+		return err
 	}
+	defer release()
+
+	// Вызываем хук начала задачи
+	s.hookStart(parent, jobName)
 
+	start := time.Now()
+	wrapper.markRunning(start)
 	defer func() {
 		if r := recover(); r != nil {
 			panicErr := fmt.Errorf("panic: %v", r)
+			wrapper.recordRun(start, time.Since(start), panicErr)
 			s.logger.Error("job panicked", "name", jobName, "panic", r)
-			if s.hooks.OnJobError != nil {
-				s.hooks.OnJobError(jobName, panicErr)
-			}
+			s.hookError(parent, jobName, panicErr)
+			err = panicErr
 		}
 	}()
 
 	// Создаем контекст с таймаутом, если указан
-	ctx := s.ctx
+	ctx := parent
 	var cancel context.CancelFunc
 	if wrapper.options.Timeout > 0 {
-		ctx, cancel = context.WithTimeout(s.ctx, wrapper.options.Timeout)
+		ctx, cancel = context.WithTimeout(parent, wrapper.options.Timeout)
 		defer cancel()
 	}
 
-	start := time.Now()
-	err := wrapper.job(ctx)
+	err = s.runWithRetry(ctx, wrapper, jobName)
 	duration := time.Since(start)
+	wrapper.recordRun(start, duration, err)
 
 	// Вызываем хук завершения задачи
-	if s.hooks.OnJobFinish != nil {
-		s.hooks.OnJobFinish(jobName, duration, err)
-	}
+	s.hookFinish(parent, jobName, duration, err)
 
 	if err != nil {
 		s.logger.Error("job failed", "name", jobName, "error", err, "duration", duration)
-		if s.hooks.OnJobError != nil {
-			s.hooks.OnJobError(jobName, err)
-		}
+		s.hookError(parent, jobName, err)
 	} else {
 		s.logger.Debug("job completed successfully", "name", jobName, "duration", duration)
 	}
+	return err
+}
+
+// dropErr возвращает ошибку контекста, если запланированный запуск выполнять не нужно.
+// При остановке планировщика запуск отбрасывается согласно Config.DrainPolicy,
+// запуск удалённой задачи отбрасывается всегда.
+func (s *Scheduler) dropErr(wrapper *jobWrapper) error {
+	if err := s.ctx.Err(); err != nil {
+		if s.drainPolicy == DropPending {
+			return err
+		}
+		return nil
+	}
+	if wrapper.ctx != nil {
+		return wrapper.ctx.Err()
+	}
+	return nil
+}
+
+// skipJob логирует пропуск выполнения и вызывает хук.
+func (s *Scheduler) skipJob(ctx context.Context, jobName, reason string) {
+	s.logger.Debug("skipping job execution", "name", jobName, "reason", reason)
+	s.hookSkipped(ctx, jobName, reason)
+}
+
+// runWithRetry выполняет задачу с учетом политики повторов.
+// Паника в задаче прерывает повторы и обрабатывается в runJobWrapper.
+func (s *Scheduler) runWithRetry(ctx context.Context, wrapper *jobWrapper, jobName string) error {
+	if wrapper.options.Retry == nil {
+		return wrapper.job(ctx)
+	}
+
+	cfg := *wrapper.options.Retry
+	onRetry := cfg.OnRetry
+	cfg.OnRetry = func(attempt int, err error, nextDelay time.Duration) {
+		s.logger.Warn("job attempt failed, retrying", "name", jobName, "attempt", attempt, "error", err, "next_delay", nextDelay)
+		if onRetry != nil {
+			onRetry(attempt, err, nextDelay)
+		}
+		s.hookRetry(ctx, jobName, attempt, err)
+	}
+
+	isRetryable := wrapper.options.RetryIf
+	if isRetryable == nil {
+		isRetryable = retryAnyError
+	}
+	return retry.DoWithRetryable(ctx, cfg, retry.RetryableFunc(wrapper.job), isRetryable)
+}
+
+// retryAnyError повторяет любые ошибки, кроме отмены контекста.
+func retryAnyError(err error) bool {
+	return !errors.Is(err, context.Canceled)
 }
 
 // IsRunning возвращает true, если планировщик запущен.
@@ -74,7 +74,7 @@ func TestScheduler_AddCronJobInvalidSchedule(t *testing.T) {
 	}
 
 	_, err := s.AddCronJob("invalid schedule", job)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidSchedule)
 }
 
 func TestScheduler_AddTickerJob(t *testing.T) {
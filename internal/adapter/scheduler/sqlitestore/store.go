@@ -0,0 +1,103 @@
+// Package sqlitestore хранит определения задач планировщика в SQLite (scheduler.Config.JobStore).
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"sttbot/internal/adapter/scheduler"
+	"sttbot/internal/platform/sqlite"
+)
+
+// Table - таблица, в которой JobStore хранит задачи планировщика.
+const Table = "scheduler_jobs"
+
+// Убедимся на этапе компиляции, что JobStore реализует интерфейс планировщика
+var _ scheduler.JobStore = (*JobStore)(nil)
+
+// JobStore хранит определения задач планировщика (scheduler.Config.JobStore) в SQLite.
+// Запись идёт через sqlite.TxRunner.WithinTx, поэтому учитывает очередь записи и ретраи на SQLITE_BUSY.
+// Методы интерфейса scheduler.JobStore не принимают контекст и выполняются с context.Background().
+type JobStore struct {
+	runner *sqlite.TxRunner
+}
+
+// New создаёт JobStore и таблицу Table, если её ещё нет.
+func New(ctx context.Context, runner *sqlite.TxRunner) (*JobStore, error) {
+	err := runner.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := runner.GetQuerier(ctx).ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+Table+` (
+			id          TEXT PRIMARY KEY,
+			job_key     TEXT NOT NULL,
+			job_type    TEXT NOT NULL,
+			schedule    TEXT NOT NULL DEFAULT '',
+			interval_ns INTEGER NOT NULL DEFAULT 0,
+			options     TEXT NOT NULL DEFAULT '{}',
+			created_at  TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s table: %w", Table, err)
+	}
+	return &JobStore{runner: runner}, nil
+}
+
+// SaveJob сохраняет запись или заменяет запись с тем же ID.
+func (s *JobStore) SaveJob(record scheduler.JobRecord) error {
+	options, err := json.Marshal(record.Options)
+	if err != nil {
+		return fmt.Errorf("failed to encode job options: %w", err)
+	}
+
+	ctx := context.Background()
+	return s.runner.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := s.runner.GetQuerier(ctx).ExecContext(ctx, `INSERT INTO `+Table+`
+			(id, job_key, job_type, schedule, interval_ns, options) VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET
+				job_key = excluded.job_key,
+				job_type = excluded.job_type,
+				schedule = excluded.schedule,
+				interval_ns = excluded.interval_ns,
+				options = excluded.options`,
+			record.ID, record.Key, string(record.Type), record.Schedule, int64(record.Interval), string(options))
+		return err
+	})
+}
+
+// DeleteJob удаляет запись. Отсутствие записи ошибкой не считается.
+func (s *JobStore) DeleteJob(id string) error {
+	ctx := context.Background()
+	return s.runner.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := s.runner.GetQuerier(ctx).ExecContext(ctx, `DELETE FROM `+Table+` WHERE id = ?`, id)
+		return err
+	})
+}
+
+// LoadJobs возвращает все записи в порядке добавления.
+func (s *JobStore) LoadJobs() ([]scheduler.JobRecord, error) {
+	ctx := context.Background()
+	return sqlite.QueryMany(ctx, s.runner.GetQuerier(ctx), scanJobRecord,
+		`SELECT id, job_key, job_type, schedule, interval_ns, options FROM `+Table+` ORDER BY rowid`)
+}
+
+// scanJobRecord сканирует строку таблицы Table.
+func scanJobRecord(rows *sql.Rows) (scheduler.JobRecord, error) {
+	var (
+		record   scheduler.JobRecord
+		jobType  string
+		interval int64
+		options  string
+	)
+	if err := rows.Scan(&record.ID, &record.Key, &jobType, &record.Schedule, &interval, &options); err != nil {
+		return record, err
+	}
+	record.Type = scheduler.JobType(jobType)
+	record.Interval = time.Duration(interval)
+	if err := json.Unmarshal([]byte(options), &record.Options); err != nil {
+		return record, fmt.Errorf("failed to decode options of job %s: %w", record.ID, err)
+	}
+	return record, nil
+}
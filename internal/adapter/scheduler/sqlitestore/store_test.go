@@ -0,0 +1,93 @@
+package sqlitestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/adapter/scheduler"
+	"sttbot/internal/platform/sqlite"
+)
+
+func TestJobStore(t *testing.T) {
+	testDB := sqlite.NewTestDBInMemory(t)
+	ctx := context.Background()
+
+	store, err := New(ctx, testDB.TxRunner)
+	require.NoError(t, err)
+	// Повторное создание не трогает существующую таблицу
+	_, err = New(ctx, testDB.TxRunner)
+	require.NoError(t, err)
+
+	records, err := store.LoadJobs()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	cronRecord := scheduler.JobRecord{
+		ID:       "a",
+		Key:      "daily-digest",
+		Type:     scheduler.JobTypeCron,
+		Schedule: "0 0 9 * * *",
+		Options:  scheduler.StoredJobOptions{Name: "digest", Timeout: time.Minute, Misfire: scheduler.MisfireRunOnceOnStart},
+	}
+	tickerRecord := scheduler.JobRecord{
+		ID:       "b",
+		Key:      "reminder:42",
+		Type:     scheduler.JobTypeTicker,
+		Interval: 15 * time.Minute,
+		Options:  scheduler.StoredJobOptions{OverlapPolicy: scheduler.SkipIfRunning, Jitter: time.Second, RunImmediately: true},
+	}
+	require.NoError(t, store.SaveJob(cronRecord))
+	require.NoError(t, store.SaveJob(tickerRecord))
+
+	records, err = store.LoadJobs()
+	require.NoError(t, err)
+	assert.Equal(t, []scheduler.JobRecord{cronRecord, tickerRecord}, records)
+
+	// Сохранение с тем же ID заменяет запись, не меняя порядок
+	cronRecord.Schedule = "@hourly"
+	require.NoError(t, store.SaveJob(cronRecord))
+	records, err = store.LoadJobs()
+	require.NoError(t, err)
+	assert.Equal(t, []scheduler.JobRecord{cronRecord, tickerRecord}, records)
+
+	require.NoError(t, store.DeleteJob("a"))
+	require.NoError(t, store.DeleteJob("missing"))
+	records, err = store.LoadJobs()
+	require.NoError(t, err)
+	assert.Equal(t, []scheduler.JobRecord{tickerRecord}, records)
+}
+
+func TestJobStore_SchedulerRestart(t *testing.T) {
+	testDB := sqlite.NewTestDBFile(t)
+	ctx := context.Background()
+	store, err := New(ctx, testDB.TxRunner)
+	require.NoError(t, err)
+
+	noop := func(ctx context.Context) error { return nil }
+	first := scheduler.New(scheduler.Config{JobStore: store})
+	first.AddTickerJobWithOptions(time.Hour, noop, scheduler.JobOptions{Name: "reminder", Key: "reminder:42"})
+	_, err = first.AddCronJobWithOptions("@daily", noop, scheduler.JobOptions{Name: "digest", Key: "digest"})
+	require.NoError(t, err)
+	first.Stop()
+
+	second := scheduler.New(scheduler.Config{JobStore: store})
+	defer second.Stop()
+	require.NoError(t, second.RestoreJobs(func(string) scheduler.JobFunc { return noop }))
+
+	jobs := second.Jobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "digest", jobs[0].Key)
+	assert.Equal(t, "@daily", jobs[0].Schedule)
+	assert.Equal(t, "reminder:42", jobs[1].Key)
+	assert.Equal(t, time.Hour, jobs[1].Interval)
+
+	second.RemoveCronJob(scheduler.CronJobID(jobs[0].ID))
+	records, err := store.LoadJobs()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "reminder:42", records[0].Key)
+}
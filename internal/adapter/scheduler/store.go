@@ -0,0 +1,210 @@
+package scheduler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnknownJobKey - RestoreJobs не нашёл JobFunc для ключа сохранённой задачи.
+var ErrUnknownJobKey = errors.New("scheduler: unknown job key")
+
+// JobStore сохраняет определения cron- и ticker-задач, чтобы восстановить их
+// после перезапуска через RestoreJobs. Сохраняются только задачи с JobOptions.Key.
+type JobStore interface {
+	// SaveJob сохраняет или заменяет запись с тем же ID.
+	SaveJob(record JobRecord) error
+	// DeleteJob удаляет запись. Отсутствие записи ошибкой не считается.
+	DeleteJob(id string) error
+	// LoadJobs возвращает все записи в порядке сохранения.
+	LoadJobs() ([]JobRecord, error)
+}
+
+// JobRecord - сохраняемое определение задачи. Функцию задачи сохранить нельзя,
+// поэтому при восстановлении она ищется по Key.
+type JobRecord struct {
+	// ID - идентификатор записи, не меняется при восстановлении.
+	ID string
+	// Key - ключ задачи из JobOptions.Key.
+	Key string
+	// Type - тип задачи.
+	Type JobType
+	// Schedule - cron-расписание (только для cron-задач).
+	Schedule string
+	// Interval - интервал запуска (только для ticker-задач).
+	Interval time.Duration
+	// Options - сериализуемые опции задачи.
+	Options StoredJobOptions
+}
+
+// StoredJobOptions - часть JobOptions, которую можно сохранить.
+// Retry, RetryIf и LastRun не сохраняются.
+type StoredJobOptions struct {
+	Name           string        `json:"name,omitempty"`
+	Timeout        time.Duration `json:"timeout,omitempty"`
+	OverlapPolicy  OverlapPolicy `json:"overlap_policy,omitempty"`
+	MaxQueuedRuns  int           `json:"max_queued_runs,omitempty"`
+	Jitter         time.Duration `json:"jitter,omitempty"`
+	InitialDelay   time.Duration `json:"initial_delay,omitempty"`
+	RunImmediately bool          `json:"run_immediately,omitempty"`
+	Misfire        MisfirePolicy `json:"misfire,omitempty"`
+	MaxCatchUp     int           `json:"max_catch_up,omitempty"`
+	AcquireTimeout time.Duration `json:"acquire_timeout,omitempty"`
+	Critical       bool          `json:"critical,omitempty"`
+	StuckThreshold time.Duration `json:"stuck_threshold,omitempty"`
+	MinInterval    time.Duration `json:"min_interval,omitempty"`
+	TrailingRun    bool          `json:"trailing_run,omitempty"`
+}
+
+// storedOptions выделяет из opts сохраняемые опции.
+func storedOptions(opts JobOptions) StoredJobOptions {
+	return StoredJobOptions{
+		Name:           opts.Name,
+		Timeout:        opts.Timeout,
+		OverlapPolicy:  opts.OverlapPolicy,
+		MaxQueuedRuns:  opts.MaxQueuedRuns,
+		Jitter:         opts.Jitter,
+		InitialDelay:   opts.InitialDelay,
+		RunImmediately: opts.RunImmediately,
+		Misfire:        opts.Misfire,
+		MaxCatchUp:     opts.MaxCatchUp,
+		AcquireTimeout: opts.AcquireTimeout,
+		Critical:       opts.Critical,
+		StuckThreshold: opts.StuckThreshold,
+		MinInterval:    opts.MinInterval,
+		TrailingRun:    opts.TrailingRun,
+	}
+}
+
+// jobOptions восстанавливает JobOptions из записи.
+func (r JobRecord) jobOptions() JobOptions {
+	o := r.Options
+	return JobOptions{
+		Name:           o.Name,
+		Key:            r.Key,
+		Timeout:        o.Timeout,
+		OverlapPolicy:  o.OverlapPolicy,
+		MaxQueuedRuns:  o.MaxQueuedRuns,
+		Jitter:         o.Jitter,
+		InitialDelay:   o.InitialDelay,
+		RunImmediately: o.RunImmediately,
+		Misfire:        o.Misfire,
+		MaxCatchUp:     o.MaxCatchUp,
+		AcquireTimeout: o.AcquireTimeout,
+		Critical:       o.Critical,
+		StuckThreshold: o.StuckThreshold,
+		MinInterval:    o.MinInterval,
+		TrailingRun:    o.TrailingRun,
+	}
+}
+
+// RestoreJobs загружает задачи из Config.JobStore и регистрирует их заново, получая
+// функцию задачи через resolver по JobRecord.Key. Обычно вызывается при старте до Start.
+// Записи сохраняют свои ID, поэтому повторно не сохраняются, а уже восстановленные
+// пропускаются. Записи с неизвестным ключом (resolver вернул nil) и некорректные
+// записи пропускаются и остаются в хранилище; их ошибки (ErrUnknownJobKey и др.)
+// возвращаются вместе через errors.Join после обработки остальных записей.
+func (s *Scheduler) RestoreJobs(resolver func(jobKey string) JobFunc) error {
+	if s.store == nil {
+		return nil
+	}
+
+	records, err := s.store.LoadJobs()
+	if err != nil {
+		return fmt.Errorf("scheduler: load jobs: %w", err)
+	}
+
+	var errs []error
+	restored := 0
+	for _, record := range records {
+		if s.hasRecord(record.ID) {
+			continue
+		}
+
+		job := resolver(record.Key)
+		if job == nil {
+			s.logger.Warn("stored job skipped, unknown key", "key", record.Key, "record_id", record.ID)
+			errs = append(errs, restoreError(record, ErrUnknownJobKey))
+			continue
+		}
+
+		opts := record.jobOptions()
+		switch record.Type {
+		case JobTypeCron:
+			if _, err := s.addCronJob(record.Schedule, job, opts, record.ID); err != nil {
+				errs = append(errs, restoreError(record, err))
+				continue
+			}
+		case JobTypeTicker:
+			if record.Interval <= 0 {
+				errs = append(errs, restoreError(record, fmt.Errorf("non-positive interval %s", record.Interval)))
+				continue
+			}
+			s.addTickerJob(record.Interval, job, opts, record.ID)
+		default:
+			errs = append(errs, restoreError(record, fmt.Errorf("unknown job type %q", record.Type)))
+			continue
+		}
+		restored++
+	}
+
+	s.logger.Info("jobs restored from store", "restored", restored, "total", len(records))
+	return errors.Join(errs...)
+}
+
+// restoreError описывает ошибку восстановления записи.
+func restoreError(record JobRecord, err error) error {
+	return fmt.Errorf("scheduler: restore job %s (key %q): %w", record.ID, record.Key, err)
+}
+
+// hasRecord проверяет, зарегистрирована ли задача с указанным ID записи.
+func (s *Scheduler) hasRecord(recordID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.cronJobs {
+		if job.wrapper.recordID == recordID {
+			return true
+		}
+	}
+	for _, job := range s.tickerJobs {
+		if job.wrapper.recordID == recordID {
+			return true
+		}
+	}
+	return false
+}
+
+// persistJob сохраняет новую задачу с JobOptions.Key в хранилище и возвращает ID записи.
+// Ошибка хранилища логируется: задача продолжает работать, но не переживёт перезапуск.
+func (s *Scheduler) persistJob(record JobRecord) string {
+	if s.store == nil || record.Key == "" {
+		return ""
+	}
+
+	record.ID = newRecordID()
+	if err := s.store.SaveJob(record); err != nil {
+		s.logger.Error("failed to persist job", "key", record.Key, "name", record.Options.Name, "error", err)
+		return ""
+	}
+	return record.ID
+}
+
+// forgetJob удаляет запись задачи из хранилища.
+func (s *Scheduler) forgetJob(wrapper *jobWrapper) {
+	if s.store == nil || wrapper.recordID == "" {
+		return
+	}
+	if err := s.store.DeleteJob(wrapper.recordID); err != nil {
+		s.logger.Error("failed to delete stored job", "record_id", wrapper.recordID, "name", wrapper.options.Name, "error", err)
+	}
+}
+
+// newRecordID возвращает случайный идентификатор записи.
+func newRecordID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
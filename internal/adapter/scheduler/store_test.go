@@ -0,0 +1,206 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore - JobStore в памяти.
+type memStore struct {
+	mu      sync.Mutex
+	records []JobRecord
+	saveErr error
+}
+
+func (m *memStore) SaveJob(record JobRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.saveErr != nil {
+		return m.saveErr
+	}
+	for i := range m.records {
+		if m.records[i].ID == record.ID {
+			m.records[i] = record
+			return nil
+		}
+	}
+	m.records = append(m.records, record)
+	return nil
+}
+
+func (m *memStore) DeleteJob(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.records {
+		if m.records[i].ID == id {
+			m.records = append(m.records[:i], m.records[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *memStore) LoadJobs() ([]JobRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]JobRecord(nil), m.records...), nil
+}
+
+func (m *memStore) snapshot() []JobRecord {
+	records, _ := m.LoadJobs()
+	return records
+}
+
+func TestScheduler_JobStoreSavesKeyedJobs(t *testing.T) {
+	store := &memStore{}
+	s := New(Config{JobStore: store})
+	defer s.Stop()
+
+	noop := func(ctx context.Context) error { return nil }
+	cronID, err := s.AddCronJobWithOptions("0 0 9 * * *", noop, JobOptions{Name: "digest", Key: "daily-digest", Timeout: time.Minute})
+	require.NoError(t, err)
+	tickerID := s.AddTickerJobWithOptions(time.Hour, noop, JobOptions{
+		Name:          "reminder chat 42",
+		Key:           "reminder:42",
+		OverlapPolicy: SkipIfRunning,
+		Jitter:        time.Second,
+	})
+	s.AddTickerJob(time.Hour, noop) // без Key не сохраняется
+	_, err = s.AddChain("nightly", []ChainStep{{Job: noop}}, ChainOptions{Interval: time.Hour, Job: JobOptions{Key: "chain"}})
+	require.NoError(t, err)
+
+	records := store.snapshot()
+	require.Len(t, records, 2)
+	assert.Equal(t, "daily-digest", records[0].Key)
+	assert.Equal(t, JobTypeCron, records[0].Type)
+	assert.Equal(t, "0 0 9 * * *", records[0].Schedule)
+	assert.Equal(t, StoredJobOptions{Name: "digest", Timeout: time.Minute}, records[0].Options)
+	assert.Equal(t, "reminder:42", records[1].Key)
+	assert.Equal(t, JobTypeTicker, records[1].Type)
+	assert.Equal(t, time.Hour, records[1].Interval)
+	assert.Equal(t, StoredJobOptions{Name: "reminder chat 42", OverlapPolicy: SkipIfRunning, Jitter: time.Second}, records[1].Options)
+	assert.NotEmpty(t, records[0].ID)
+	assert.NotEqual(t, records[0].ID, records[1].ID)
+
+	info, ok := s.TickerJobInfo(tickerID)
+	require.True(t, ok)
+	assert.Equal(t, "reminder:42", info.Key)
+
+	s.RemoveCronJob(cronID)
+	assert.True(t, s.RemoveTickerJob(tickerID))
+	assert.Empty(t, store.snapshot(), "удалённые задачи должны удаляться из хранилища")
+}
+
+func TestScheduler_RestoreJobs(t *testing.T) {
+	store := &memStore{}
+	old := New(Config{JobStore: store})
+	noop := func(ctx context.Context) error { return nil }
+	_, err := old.AddCronJobWithOptions("@every 1h", noop, JobOptions{Name: "digest", Key: "digest"})
+	require.NoError(t, err)
+	old.AddTickerJobWithOptions(50*time.Millisecond, noop, JobOptions{Name: "reminder", Key: "reminder:42"})
+	old.Stop()
+	saved := store.snapshot()
+	require.Len(t, saved, 2, "Stop не удаляет задачи из хранилища")
+
+	// Перезапуск процесса: новый планировщик с тем же хранилищем
+	var runs int64
+	var keys []string
+	s := New(Config{JobStore: store})
+	defer s.Stop()
+	err = s.RestoreJobs(func(key string) JobFunc {
+		keys = append(keys, key)
+		if key == "reminder:42" {
+			return func(ctx context.Context) error {
+				atomic.AddInt64(&runs, 1)
+				return nil
+			}
+		}
+		return noop
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"digest", "reminder:42"}, keys)
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, JobTypeCron, jobs[0].Type)
+	assert.Equal(t, "@every 1h", jobs[0].Schedule)
+	assert.Equal(t, "digest", jobs[0].Name)
+	assert.Equal(t, JobTypeTicker, jobs[1].Type)
+	assert.Equal(t, "reminder:42", jobs[1].Key)
+	assert.Equal(t, saved, store.snapshot(), "восстановленные задачи не сохраняются заново")
+
+	s.Start()
+	waitForAtLeast(t, &runs, 1, 2*time.Second)
+
+	// Повторное восстановление не дублирует задачи
+	require.NoError(t, s.RestoreJobs(func(string) JobFunc { return noop }))
+	assert.Len(t, s.Jobs(), 2)
+
+	// Удаление восстановленной задачи удаляет её запись
+	assert.True(t, s.RemoveTickerJob(TickerJobID(jobs[1].ID)))
+	require.Len(t, store.snapshot(), 1)
+	assert.Equal(t, "digest", store.snapshot()[0].Key)
+}
+
+func TestScheduler_RestoreJobsErrors(t *testing.T) {
+	store := &memStore{records: []JobRecord{
+		{ID: "1", Key: "removed-feature", Type: JobTypeTicker, Interval: time.Hour},
+		{ID: "2", Key: "bad-cron", Type: JobTypeCron, Schedule: "invalid"},
+		{ID: "3", Key: "bad-interval", Type: JobTypeTicker},
+		{ID: "4", Key: "bad-type", Type: "oneshot"},
+		{ID: "5", Key: "ok", Type: JobTypeTicker, Interval: time.Hour},
+	}}
+	s := New(Config{JobStore: store})
+	defer s.Stop()
+
+	err := s.RestoreJobs(func(key string) JobFunc {
+		if key == "removed-feature" {
+			return nil
+		}
+		return func(ctx context.Context) error { return nil }
+	})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnknownJobKey)
+	for _, key := range []string{"removed-feature", "bad-cron", "bad-interval", "bad-type"} {
+		assert.Contains(t, err.Error(), key)
+	}
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1, "корректные записи восстанавливаются несмотря на ошибки")
+	assert.Equal(t, "ok", jobs[0].Key)
+	assert.Len(t, store.snapshot(), 5, "записи с ошибками остаются в хранилище")
+}
+
+func TestScheduler_JobStoreSaveError(t *testing.T) {
+	store := &memStore{saveErr: errors.New("disk full")}
+	s := New(Config{JobStore: store})
+	defer s.Stop()
+
+	var runs int64
+	id := s.AddTickerJobWithOptions(20*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt64(&runs, 1)
+		return nil
+	}, JobOptions{Key: "reminder"})
+	s.Start()
+
+	// Задача работает, хотя не сохранилась
+	waitForAtLeast(t, &runs, 1, 2*time.Second)
+	assert.True(t, s.RemoveTickerJob(id))
+}
+
+func TestScheduler_RestoreJobsWithoutStore(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	require.NoError(t, s.RestoreJobs(func(string) JobFunc {
+		t.Fatal("resolver не должен вызываться без JobStore")
+		return nil
+	}))
+}
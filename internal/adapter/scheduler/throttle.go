@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrJobThrottled - выполнение пропущено (или отложено при JobOptions.TrailingRun),
+// так как с завершения предыдущего прошло меньше JobOptions.MinInterval.
+var ErrJobThrottled = errors.New("scheduler: job throttled")
+
+// throttled проверяет ограничение JobOptions.MinInterval и возвращает true, если
+// запуск сейчас выполнять нельзя. Без TrailingRun запуск пропускается с
+// SkipReasonThrottled, с TrailingRun переносится на момент истечения интервала.
+func (s *Scheduler) throttled(ctx context.Context, wrapper *jobWrapper, jobName string) bool {
+	minInterval := wrapper.options.MinInterval
+	if minInterval <= 0 {
+		return false
+	}
+	finishedAt, ok := wrapper.lastFinishedAt()
+	if !ok {
+		return false
+	}
+	wait := minInterval - time.Since(finishedAt)
+	if wait <= 0 {
+		return false
+	}
+
+	if wrapper.options.TrailingRun {
+		s.scheduleTrailingRun(wrapper, jobName, wait)
+		return true
+	}
+	s.skipJob(ctx, jobName, SkipReasonThrottled)
+	return true
+}
+
+// scheduleTrailingRun планирует отложенный запуск через wait. Запросы, пришедшие,
+// пока отложенный запуск уже запланирован, объединяются с ним.
+// Отложенный запуск отменяется при остановке планировщика и удалении ticker-задачи.
+func (s *Scheduler) scheduleTrailingRun(wrapper *jobWrapper, jobName string, wait time.Duration) {
+	if !wrapper.trailing.CompareAndSwap(false, true) {
+		s.logger.Debug("job run coalesced with pending trailing run", "name", jobName)
+		return
+	}
+
+	ctx := s.ctx
+	if wrapper.ctx != nil {
+		ctx = wrapper.ctx
+	}
+	if ctx.Err() != nil {
+		wrapper.trailing.Store(false)
+		return
+	}
+
+	s.logger.Debug("job run deferred by min interval", "name", jobName, "wait", wait)
+	timer := time.NewTimer(wait)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			wrapper.trailing.Store(false)
+			return
+		}
+
+		// Сбрасываем флаг до запуска, чтобы запросы во время выполнения
+		// могли запланировать следующий отложенный запуск
+		wrapper.trailing.Store(false)
+		s.runJobWrapper(wrapper)
+	}()
+}
+
+// lastFinishedAt возвращает время завершения последнего выполнения.
+func (w *jobWrapper) lastFinishedAt() (time.Time, bool) {
+	w.statsMu.Lock()
+	defer w.statsMu.Unlock()
+
+	if w.stats.runCount == 0 {
+		return time.Time{}, false
+	}
+	return w.stats.lastRunAt.Add(w.stats.lastDuration), true
+}
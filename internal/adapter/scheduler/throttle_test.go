@@ -0,0 +1,195 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// skipRecorder собирает причины пропусков из JobHooks.OnJobSkipped.
+type skipRecorder struct {
+	mu      sync.Mutex
+	reasons []string
+}
+
+func (r *skipRecorder) hooks() JobHooks {
+	return JobHooks{OnJobSkipped: func(_ string, reason string) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.reasons = append(r.reasons, reason)
+	}}
+}
+
+func (r *skipRecorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.reasons...)
+}
+
+func TestScheduler_MinIntervalSkips(t *testing.T) {
+	var skips skipRecorder
+	s := New(Config{JobHooks: skips.hooks()})
+	defer s.Stop()
+
+	var runs atomic.Int32
+	id, err := s.AddCronJobWithOptions("0 0 0 1 1 *", func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}, JobOptions{Name: "digest", MinInterval: 100 * time.Millisecond})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, s.TriggerCronJob(ctx, id))
+	require.ErrorIs(t, s.TriggerCronJob(ctx, id), ErrJobThrottled)
+	require.ErrorIs(t, s.TriggerCronJob(ctx, id), ErrJobThrottled)
+	assert.Equal(t, int32(1), runs.Load())
+	assert.Equal(t, []string{SkipReasonThrottled, SkipReasonThrottled}, skips.list())
+
+	// После интервала запуск снова разрешён, а ожидающих запусков нет
+	time.Sleep(120 * time.Millisecond)
+	require.NoError(t, s.TriggerCronJob(ctx, id))
+	assert.Equal(t, int32(2), runs.Load())
+}
+
+func TestScheduler_MinIntervalFromFinish(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runs atomic.Int32
+	id := s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			time.Sleep(80 * time.Millisecond)
+		}
+		return nil
+	}, JobOptions{Name: "export", MinInterval: 50 * time.Millisecond})
+	s.Start()
+
+	ctx := context.Background()
+	require.NoError(t, s.TriggerTickerJob(ctx, id))
+
+	// Интервал отсчитывается от завершения, а не от начала долгого выполнения
+	require.ErrorIs(t, s.TriggerTickerJob(ctx, id), ErrJobThrottled)
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, s.TriggerTickerJob(ctx, id))
+	assert.Equal(t, int32(2), runs.Load())
+}
+
+func TestScheduler_MinIntervalWithDelayIfRunning(t *testing.T) {
+	for _, trailing := range []bool{false, true} {
+		var skips skipRecorder
+		s := New(Config{JobHooks: skips.hooks()})
+
+		var runs atomic.Int32
+		release := make(chan struct{})
+		id := s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error {
+			if runs.Add(1) == 1 {
+				<-release
+			}
+			return nil
+		}, JobOptions{Name: "export", OverlapPolicy: DelayIfRunning, MinInterval: 80 * time.Millisecond, TrailingRun: trailing})
+		s.Start()
+
+		ctx := context.Background()
+		first := make(chan error, 1)
+		go func() { first <- s.TriggerTickerJob(ctx, id) }()
+		require.Eventually(t, func() bool { return runs.Load() == 1 }, time.Second, time.Millisecond)
+
+		// Запуск ждёт в очереди DelayIfRunning, пока выполняется первый
+		queued := make(chan error, 1)
+		go func() { queued <- s.TriggerTickerJob(ctx, id) }()
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		require.NoError(t, <-first)
+
+		// После освобождения блокировки MinInterval отсчитывается от конца первого запуска
+		require.ErrorIs(t, <-queued, ErrJobThrottled)
+		if !trailing {
+			assert.Equal(t, int32(1), runs.Load())
+			assert.Equal(t, []string{SkipReasonThrottled}, skips.list())
+		} else {
+			require.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, 5*time.Millisecond)
+			assert.Empty(t, skips.list())
+		}
+		s.Stop()
+	}
+}
+
+func TestScheduler_MinIntervalSharedByScheduleAndTrigger(t *testing.T) {
+	var skips skipRecorder
+	s := New(Config{JobHooks: skips.hooks()})
+	defer s.Stop()
+
+	var runs atomic.Int32
+	id := s.AddTickerJobWithOptions(20*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}, JobOptions{Name: "sync", MinInterval: time.Hour})
+
+	require.NoError(t, s.TriggerTickerJob(context.Background(), id))
+	s.Start()
+
+	// Тики после ручного запуска пропускаются тем же ограничением
+	require.Eventually(t, func() bool { return len(skips.list()) >= 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int32(1), runs.Load())
+	assert.Equal(t, SkipReasonThrottled, skips.list()[0])
+}
+
+func TestScheduler_TrailingRun(t *testing.T) {
+	var skips skipRecorder
+	s := New(Config{JobHooks: skips.hooks()})
+	defer s.Stop()
+
+	var runs atomic.Int32
+	id, err := s.AddCronJobWithOptions("0 0 0 1 1 *", func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}, JobOptions{Name: "digest", MinInterval: 80 * time.Millisecond, TrailingRun: true})
+	require.NoError(t, err)
+	s.Start()
+
+	ctx := context.Background()
+	require.NoError(t, s.TriggerCronJob(ctx, id))
+	for range 3 {
+		require.ErrorIs(t, s.TriggerCronJob(ctx, id), ErrJobThrottled)
+	}
+	assert.Equal(t, int32(1), runs.Load())
+
+	// Запросы объединяются в один отложенный запуск
+	require.Eventually(t, func() bool { return runs.Load() == 2 }, time.Second, 5*time.Millisecond)
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, int32(2), runs.Load())
+	assert.Empty(t, skips.list())
+}
+
+func TestScheduler_TrailingRunCanceledOnStop(t *testing.T) {
+	s := New(Config{})
+
+	var runs atomic.Int32
+	id := s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	}, JobOptions{Name: "report", MinInterval: time.Hour, TrailingRun: true})
+	s.Start()
+
+	ctx := context.Background()
+	require.NoError(t, s.TriggerTickerJob(ctx, id))
+	require.ErrorIs(t, s.TriggerTickerJob(ctx, id), ErrJobThrottled)
+
+	// Stop не ждёт истечения MinInterval
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop waited for trailing run")
+	}
+	assert.Equal(t, int32(1), runs.Load())
+}
@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"context"
+	randv2 "math/rand/v2"
+	"time"
+)
+
+// runTickerLoop выполняет ticker-задачу до отмены контекста.
+// Тики идут по фиксированной сетке start+InitialDelay+k*interval, поэтому Jitter
+// не накапливает дрейф. Пропущенные из-за долгого выполнения тики отбрасываются,
+// как в time.Ticker.
+func (s *Scheduler) runTickerLoop(ctx context.Context, job *tickerJob) {
+	wrapper := job.wrapper
+	opts := wrapper.options
+	interval := job.interval
+
+	first := interval
+	if opts.InitialDelay > 0 {
+		first = opts.InitialDelay
+	}
+	next := time.Now().Add(first)
+	wrapper.setNextRun(next)
+
+	if opts.RunImmediately {
+		select {
+		case <-s.started:
+			s.runJobWrapper(wrapper)
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	for {
+		now := time.Now()
+		if now.After(next) {
+			// Отбрасываем пропущенные тики, оставляя один немедленный
+			missed := now.Sub(next) / interval
+			next = next.Add(missed * interval)
+		}
+		wait := time.Until(next) + jitterOffset(opts.Jitter)
+		if wait < 0 {
+			wait = 0
+		}
+		wrapper.setNextRun(now.Add(wait))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+			next = next.Add(interval)
+			s.runJobWrapper(wrapper)
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// jitterOffset возвращает случайное смещение в диапазоне [-jitter, jitter].
+func jitterOffset(jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return 0
+	}
+	return time.Duration(randv2.Int64N(2*int64(jitter)+1)) - jitter
+}
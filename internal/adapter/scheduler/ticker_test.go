@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler_TickerInitialDelay(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runCount int64
+	s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return nil
+	}, JobOptions{InitialDelay: 30 * time.Millisecond})
+	s.Start()
+
+	waitForAtLeast(t, &runCount, 1, time.Second)
+	ensureNoIncrement(t, &runCount, 1, 200*time.Millisecond)
+}
+
+func TestScheduler_TickerRunImmediately(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runCount int64
+	s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return nil
+	}, JobOptions{RunImmediately: true})
+
+	ensureNoIncrement(t, &runCount, 0, 100*time.Millisecond)
+
+	s.Start()
+	waitForAtLeast(t, &runCount, 1, time.Second)
+	ensureNoIncrement(t, &runCount, 1, 200*time.Millisecond)
+}
+
+func TestScheduler_TickerRunImmediatelyWithInitialDelay(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runCount int64
+	s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return nil
+	}, JobOptions{RunImmediately: true, InitialDelay: 50 * time.Millisecond})
+	s.Start()
+
+	waitForAtLeast(t, &runCount, 2, time.Second)
+	ensureNoIncrement(t, &runCount, 2, 200*time.Millisecond)
+}
+
+func TestScheduler_TickerJitter(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runCount int64
+	s.AddTickerJobWithOptions(30*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt64(&runCount, 1)
+		return nil
+	}, JobOptions{Jitter: 10 * time.Millisecond})
+	s.Start()
+
+	waitForAtLeast(t, &runCount, 3, time.Second)
+}
+
+func TestJitterOffset(t *testing.T) {
+	assert.Zero(t, jitterOffset(0))
+	for range 1000 {
+		d := jitterOffset(10 * time.Millisecond)
+		assert.GreaterOrEqual(t, d, -10*time.Millisecond)
+		assert.LessOrEqual(t, d, 10*time.Millisecond)
+	}
+}
@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrJobNotFound - задача с указанным ID не найдена.
+	ErrJobNotFound = errors.New("scheduler: job not found")
+	// ErrJobAlreadyRunning - выполнение пропущено, так как задача уже выполняется (SkipIfRunning).
+	ErrJobAlreadyRunning = errors.New("scheduler: job already running")
+	// ErrJobQueueFull - выполнение пропущено, так как превышен лимит ожидающих запусков (DelayIfRunning).
+	ErrJobQueueFull = errors.New("scheduler: job queue full")
+	// ErrSchedulerStopped - планировщик остановлен.
+	ErrSchedulerStopped = errors.New("scheduler: stopped")
+)
+
+// TriggerCronJob немедленно выполняет cron-задачу вне расписания и возвращает её ошибку.
+// Запуск проходит через те же политику перекрытий, таймаут, ретраи, хуки и
+// восстановление после паники, что и запуск по расписанию; расписание не сдвигается.
+// Работает и до Start. Приостановленная задача тоже выполняется.
+// Отмена ctx отменяет контекст задачи.
+func (s *Scheduler) TriggerCronJob(ctx context.Context, id CronJobID) error {
+	s.mu.Lock()
+	job, exists := s.cronJobs[id]
+	s.mu.Unlock()
+	if !exists {
+		return ErrJobNotFound
+	}
+
+	s.logger.Info("cron job triggered manually", "id", id, "name", job.wrapper.options.Name)
+	return s.trigger(ctx, job.wrapper)
+}
+
+// TriggerTickerJob немедленно выполняет ticker-задачу вне интервала и возвращает её ошибку.
+// Гарантии те же, что у TriggerCronJob; сетка тиков не сдвигается.
+func (s *Scheduler) TriggerTickerJob(ctx context.Context, id TickerJobID) error {
+	s.mu.Lock()
+	job, exists := s.tickerJobs[id]
+	s.mu.Unlock()
+	if !exists {
+		return ErrJobNotFound
+	}
+
+	s.logger.Info("ticker job triggered manually", "id", id, "name", job.wrapper.options.Name)
+	return s.trigger(ctx, job.wrapper)
+}
+
+// trigger выполняет задачу синхронно в контексте планировщика, отменяемом также через ctx.
+func (s *Scheduler) trigger(ctx context.Context, wrapper *jobWrapper) error {
+	if !s.IsRunning() {
+		return ErrSchedulerStopped
+	}
+
+	jobCtx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	return s.runJob(jobCtx, wrapper, true)
+}
@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_TriggerCronJob(t *testing.T) {
+	var finished int64
+	s := New(Config{JobHooks: JobHooks{
+		OnJobFinish: func(string, time.Duration, error) { atomic.AddInt64(&finished, 1) },
+	}})
+	defer s.Stop()
+
+	jobErr := errors.New("boom")
+	id, err := s.AddCronJobWithOptions("0 0 0 1 1 *", func(ctx context.Context) error {
+		return jobErr
+	}, JobOptions{Name: "yearly"})
+	require.NoError(t, err)
+
+	// Запуск до Start
+	require.ErrorIs(t, s.TriggerCronJob(context.Background(), id), jobErr)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&finished))
+
+	info, ok := s.CronJobInfo(id)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), info.RunCount)
+	assert.Equal(t, int64(1), info.ErrorCount)
+
+	require.ErrorIs(t, s.TriggerCronJob(context.Background(), id+100), ErrJobNotFound)
+}
+
+func TestScheduler_TriggerTickerJob(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	var runs int64
+	id := s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error {
+		atomic.AddInt64(&runs, 1)
+		return nil
+	}, JobOptions{Name: "hourly"})
+	s.Start()
+
+	require.True(t, s.PauseTickerJob(id))
+	require.NoError(t, s.TriggerTickerJob(context.Background(), id))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&runs))
+
+	require.ErrorIs(t, s.TriggerTickerJob(context.Background(), id+100), ErrJobNotFound)
+}
+
+func TestScheduler_TriggerAlreadyRunning(t *testing.T) {
+	var skipped int64
+	s := New(Config{JobHooks: JobHooks{
+		OnJobSkipped: func(string, string) { atomic.AddInt64(&skipped, 1) },
+	}})
+	defer s.Stop()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	id := s.AddTickerJobWithOptions(time.Hour, func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	}, JobOptions{OverlapPolicy: SkipIfRunning})
+
+	done := make(chan error, 1)
+	go func() { done <- s.TriggerTickerJob(context.Background(), id) }()
+	<-started
+
+	require.ErrorIs(t, s.TriggerTickerJob(context.Background(), id), ErrJobAlreadyRunning)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&skipped))
+
+	close(release)
+	require.NoError(t, <-done)
+}
+
+func TestScheduler_TriggerPanicAndCancel(t *testing.T) {
+	s := New(Config{})
+	defer s.Stop()
+
+	panicID := s.AddTickerJob(time.Hour, func(ctx context.Context) error {
+		panic("oops")
+	})
+	err := s.TriggerTickerJob(context.Background(), panicID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "panic: oops")
+
+	waitID := s.AddTickerJob(time.Hour, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, s.TriggerTickerJob(ctx, waitID), context.Canceled)
+
+	s.Stop()
+	require.ErrorIs(t, s.TriggerTickerJob(context.Background(), waitID), ErrSchedulerStopped)
+}
@@ -0,0 +1,236 @@
+package httpclient
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"io"
+	"log/slog"
+	stdhttp "net/http"
+	"net/http/httputil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStore stores serialized responses. Implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Get returns payload stored under key, or false if it is missing or expired.
+	Get(key string) ([]byte, bool)
+	// Set stores payload under key for ttl (non-positive ttl means no expiration).
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// CacheStats contains cache hit and miss counters.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// WithCache enables caching of successful GET and HEAD responses in store for ttl.
+// Requests with Cache-Control: no-store, responses with Cache-Control: no-store
+// and requests sent with NoCache bypass the cache.
+// Cached responses do not trigger response hooks.
+func WithCache(store CacheStore, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = store
+		c.cacheTTL = ttl
+	}
+}
+
+// WithCacheKeyHeaders adds request headers whose values become part of cache key,
+// for example Authorization or Accept-Language.
+func WithCacheKeyHeaders(headers ...string) Option {
+	return func(c *Client) {
+		for _, h := range headers {
+			c.cacheKeyHeaders = append(c.cacheKeyHeaders, stdhttp.CanonicalHeaderKey(h))
+		}
+		sort.Strings(c.cacheKeyHeaders)
+	}
+}
+
+// NoCache bypasses response cache for single request.
+func NoCache() DoOption {
+	return func(o *doOptions) { o.noCache = true }
+}
+
+// CacheStats returns cache hit and miss counters.
+func (c *Client) CacheStats() CacheStats {
+	return CacheStats{Hits: c.cacheHits.Load(), Misses: c.cacheMisses.Load()}
+}
+
+// cacheKey returns cache key for request, or false if request must bypass cache.
+func (c *Client) cacheKey(req *stdhttp.Request, o doOptions) (string, bool) {
+	if c.cache == nil || o.noCache {
+		return "", false
+	}
+	if req.Method != stdhttp.MethodGet && req.Method != stdhttp.MethodHead {
+		return "", false
+	}
+	if hasNoStore(req.Header) {
+		return "", false
+	}
+
+	var b strings.Builder
+	b.WriteString(req.Method)
+	b.WriteByte(' ')
+	b.WriteString(req.URL.String())
+	for _, h := range c.cacheKeyHeaders {
+		b.WriteByte('\n')
+		b.WriteString(h)
+		b.WriteByte(':')
+		b.WriteString(strings.Join(req.Header.Values(h), ","))
+	}
+	return b.String(), true
+}
+
+// cachedResponse returns response stored under key and updates hit/miss counters.
+func (c *Client) cachedResponse(req *stdhttp.Request, key string) (*stdhttp.Response, bool) {
+	data, ok := c.cache.Get(key)
+	if !ok {
+		c.cacheMisses.Add(1)
+		return nil, false
+	}
+	resp, err := stdhttp.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+	if err != nil {
+		c.log.Warn("http cache entry corrupted", slog.String("method", req.Method), slog.String("url", c.redactURL(req.URL)), slog.Any("error", err))
+		c.cacheMisses.Add(1)
+		return nil, false
+	}
+	c.cacheHits.Add(1)
+	return resp, true
+}
+
+// storeResponse stores successful response under key.
+// Body is buffered up to maxBody bytes (0 disables limit); larger responses are not cached,
+// and their body is left readable from the beginning.
+func (c *Client) storeResponse(key string, resp *stdhttp.Response, maxBody int64) {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || hasNoStore(resp.Header) {
+		return
+	}
+
+	orig := resp.Body
+	var body []byte
+	if orig != nil && orig != stdhttp.NoBody {
+		var r io.Reader = orig
+		if maxBody > 0 {
+			r = io.LimitReader(orig, maxBody+1)
+		}
+		var err error
+		body, err = io.ReadAll(r)
+		if err != nil || (maxBody > 0 && int64(len(body)) > maxBody) {
+			// Hand the caller the consumed prefix followed by the rest of the body.
+			resp.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(body), orig), orig}
+			return
+		}
+		_ = orig.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	stored := *resp
+	stored.Body = io.NopCloser(bytes.NewReader(body))
+	stored.ContentLength = int64(len(body))
+	stored.TransferEncoding = nil
+	data, err := httputil.DumpResponse(&stored, true)
+	if err != nil {
+		return
+	}
+	c.cache.Set(key, data, c.cacheTTL)
+}
+
+// hasNoStore reports whether Cache-Control header contains no-store directive.
+func hasNoStore(h stdhttp.Header) bool {
+	for _, v := range h.Values("Cache-Control") {
+		for _, directive := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-store") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LRUCache is in-memory CacheStore evicting least recently used entries.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+	now      func() time.Time
+}
+
+// lruEntry is single LRUCache entry.
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUCache creates LRUCache holding up to capacity entries (minimum 1).
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+// Get returns value for key if present and not expired.
+func (l *LRUCache) Get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*lruEntry)
+	if !e.expiresAt.IsZero() && !l.now().Before(e.expiresAt) {
+		l.ll.Remove(el)
+		delete(l.items, key)
+		return nil, false
+	}
+	l.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores value for key, evicting least recently used entry when full.
+func (l *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = l.now().Add(ttl)
+	}
+
+	if el, ok := l.items[key]; ok {
+		e := el.Value.(*lruEntry)
+		e.value = value
+		e.expiresAt = expiresAt
+		l.ll.MoveToFront(el)
+		return
+	}
+
+	l.items[key] = l.ll.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for l.ll.Len() > l.capacity {
+		oldest := l.ll.Back()
+		l.ll.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns number of entries in cache, including expired ones not yet evicted.
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ll.Len()
+}
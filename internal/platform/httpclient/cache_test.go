@@ -0,0 +1,157 @@
+package httpclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func getBody(t *testing.T, c *httpclient.Client, req *http.Request, opts ...httpclient.DoOption) (int, string) {
+	t.Helper()
+	resp, err := c.Do(context.Background(), req, opts...)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(b)
+}
+
+func TestClient_Cache_HitAndMiss(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("X-Test", "1")
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(httpclient.WithCache(httpclient.NewLRUCache(16), time.Minute))
+	for range 3 {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		status, body := getBody(t, c, req)
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, "hello", body)
+	}
+
+	require.EqualValues(t, 1, calls.Load())
+	require.Equal(t, httpclient.CacheStats{Hits: 2, Misses: 1}, c.CacheStats())
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "1", resp.Header.Get("X-Test"))
+}
+
+func TestClient_Cache_Bypass(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch r.URL.Path {
+		case "/nostore":
+			w.Header().Set("Cache-Control", "private, no-store")
+		case "/error":
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header string
+		opts   []httpclient.DoOption
+	}{
+		{name: "post", method: http.MethodPost, path: "/"},
+		{name: "response no-store", method: http.MethodGet, path: "/nostore"},
+		{name: "request no-store", method: http.MethodGet, path: "/", header: "no-store"},
+		{name: "non 2xx", method: http.MethodGet, path: "/error"},
+		{name: "NoCache option", method: http.MethodGet, path: "/", opts: []httpclient.DoOption{httpclient.NoCache()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			c := newTestClient(httpclient.WithCache(httpclient.NewLRUCache(16), time.Minute))
+			for range 2 {
+				req, err := http.NewRequest(tt.method, srv.URL+tt.path, nil)
+				require.NoError(t, err)
+				if tt.header != "" {
+					req.Header.Set("Cache-Control", tt.header)
+				}
+				_, body := getBody(t, c, req, tt.opts...)
+				require.Equal(t, "ok", body)
+			}
+			require.EqualValues(t, 2, calls.Load())
+			require.Zero(t, c.CacheStats().Hits)
+		})
+	}
+}
+
+func TestClient_Cache_KeyHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Accept-Language")))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(httpclient.WithCache(httpclient.NewLRUCache(16), time.Minute), httpclient.WithCacheKeyHeaders("accept-language"))
+	for _, lang := range []string{"ru", "en", "ru"} {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept-Language", lang)
+		_, body := getBody(t, c, req)
+		require.Equal(t, lang, body)
+	}
+	require.Equal(t, httpclient.CacheStats{Hits: 1, Misses: 2}, c.CacheStats())
+}
+
+func TestClient_Cache_ResponseTooLarge(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(httpclient.WithCache(httpclient.NewLRUCache(16), time.Minute), httpclient.WithMaxResponseBody(4))
+	for range 2 {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		_, body := getBody(t, c, req)
+		require.Equal(t, "0123456789", body)
+	}
+	require.EqualValues(t, 2, calls.Load())
+}
+
+func TestLRUCache(t *testing.T) {
+	c := httpclient.NewLRUCache(2)
+	c.Set("a", []byte("1"), 0)
+	c.Set("b", []byte("2"), 0)
+	_, ok := c.Get("a")
+	require.True(t, ok)
+
+	c.Set("c", []byte("3"), 0)
+	_, ok = c.Get("b")
+	require.False(t, ok, "least recently used entry must be evicted")
+	require.Equal(t, 2, c.Len())
+
+	c.Set("d", []byte("4"), 10*time.Millisecond)
+	v, ok := c.Get("d")
+	require.True(t, ok)
+	require.Equal(t, "4", string(v))
+
+	time.Sleep(20 * time.Millisecond)
+	_, ok = c.Get("d")
+	require.False(t, ok, "expired entry must not be returned")
+}
@@ -13,24 +13,66 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
+
+	"sttbot/internal/shared"
 )
 
 // Client wraps http.Client with logging and retries.
 type Client struct {
-	hc               *stdhttp.Client
-	log              *slog.Logger
-	retries          int
-	baseBackoff      time.Duration
-	maxBackoff       time.Duration
-	headers          map[string]string
-	urlRedactor      func(*url.URL) string
-	retryMethods     map[string]struct{}
-	maxRetryDuration time.Duration
-	retryNonIdem     bool
-	maxReplayBody    int64
-	retryPolicy      func(*stdhttp.Response, error) (time.Duration, bool)
+	hc                  *stdhttp.Client
+	log                 *slog.Logger
+	retries             int
+	baseBackoff         time.Duration
+	maxBackoff          time.Duration
+	headers             map[string]string
+	urlRedactor         func(*url.URL) string
+	retryMethods        map[string]struct{}
+	maxRetryDuration    time.Duration
+	retryNonIdem        bool
+	maxReplayBody       int64
+	retryPolicy         func(*stdhttp.Response, error) (time.Duration, bool)
+	maxResponseBody     int64
+	requestHooks        []RequestHook
+	responseHooks       []ResponseHook
+	retryStatuses       map[int]struct{}
+	retryAfterHeaders   []string
+	classifyErrors      bool
+	hostLimits          map[string]rateLimit
+	defaultLimit        rateLimit
+	limitMu             sync.Mutex
+	limiters            map[string]*tokenBucket
+	cache               CacheStore
+	cacheTTL            time.Duration
+	cacheKeyHeaders     []string
+	cacheHits           atomic.Int64
+	cacheMisses         atomic.Int64
+	traceCallback       func(TraceInfo)
+	proxySet            bool
+	proxyURL            *url.URL
+	proxyErr            error
+	hostTransports      map[string]stdhttp.RoundTripper
+	compression         []string
+	decompressors       map[string]Decompressor
+	autoIdemHeader      string
+	autoIdemGen         func() string
+	autoIdemMethods     map[string]struct{}
+	signer              Signer
+	retryBudget         *RetryBudget
+	dns                 dnsConfig
+	dnsCache            *dnsCache
+	responseValidator   func(*stdhttp.Response) error
+	validationRetryable func(error) bool
+	attemptTimeout      time.Duration
+	overallTimeout      time.Duration
+	compressRequests    bool
+	compressMinSize     int64
+	attemptHistory      int
+	spanner             Spanner
+	mirror              *mirror
 }
 
 // Option configures Client.
@@ -116,11 +158,13 @@ func New(opts ...Option) *Client {
 			Timeout:   15 * time.Second,
 			Transport: tr,
 		},
-		log:           slog.Default(),
-		retries:       0,
-		baseBackoff:   200 * time.Millisecond,
-		maxReplayBody: 1 << 20,
-		retryPolicy:   retryInfo,
+		log:             slog.Default(),
+		retries:         0,
+		baseBackoff:     200 * time.Millisecond,
+		maxReplayBody:   1 << 20,
+		maxResponseBody: 10 << 20,
+		attemptHistory:  defaultAttemptHistory,
+		retryPolicy:     retryInfo,
 		retryMethods: map[string]struct{}{
 			stdhttp.MethodGet:     {},
 			stdhttp.MethodHead:    {},
@@ -133,6 +177,7 @@ func New(opts ...Option) *Client {
 	for _, o := range opts {
 		o(c)
 	}
+	c.applyTransportOptions()
 	return c
 }
 
@@ -227,9 +272,15 @@ func isRetryableError(err error) bool {
 	if err == nil {
 		return false
 	}
+	if errors.Is(err, ErrAttemptTimeout) {
+		return true
+	}
 	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 		return false
 	}
+	if shared.IsRetryable(err) {
+		return true
+	}
 	if errors.Is(err, net.ErrClosed) {
 		return true
 	}
@@ -285,7 +336,35 @@ func retryInfo(resp *stdhttp.Response, err error) (time.Duration, bool) {
 }
 
 // Do sends HTTP request with context, logging and retries.
-func (c *Client) Do(ctx context.Context, req *stdhttp.Request) (*stdhttp.Response, error) {
+// Per-request options override client defaults only for this call.
+func (c *Client) Do(ctx context.Context, req *stdhttp.Request, opts ...DoOption) (*stdhttp.Response, error) {
+	ctx, cancel := withTimeout(ctx, c.overallTimeout)
+	ctx, reqSpan := c.startRequestSpan(ctx, req)
+	history := attemptLog{limit: c.attemptHistory}
+	resp, err := c.do(ctx, req, c.resolveDoOptions(opts), &history)
+	releaseWithBody(resp, cancel)
+	err = history.requestError(req.Method, c.redactURL(req.URL), err)
+	endRequestSpan(reqSpan, resp, err, history.attempts)
+	if err != nil && c.classifyErrors {
+		return nil, classifyError(err)
+	}
+	return resp, err
+}
+
+// do performs request attempts with retries, recording sent attempts in log.
+func (c *Client) do(ctx context.Context, req *stdhttp.Request, o doOptions, log *attemptLog) (*stdhttp.Response, error) {
+	if c.proxyErr != nil {
+		return nil, c.proxyErr
+	}
+	hc := c.httpClient(o)
+	if len(o.headers) > 0 && req.Header == nil {
+		req.Header = make(stdhttp.Header)
+	}
+	for k, v := range o.headers {
+		req.Header.Set(k, v)
+	}
+
+	var bodyHash []byte
 	if req.Body != nil && req.GetBody == nil {
 		var body []byte
 		var err error
@@ -308,42 +387,160 @@ func (c *Client) Do(ctx context.Context, req *stdhttp.Request) (*stdhttp.Respons
 		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
 		rc, _ := req.GetBody()
 		req.Body = rc
+		if c.signer != nil {
+			bodyHash = hashBody(body)
+		}
+	}
+	compressed, err := c.compressRequest(req, o)
+	if err != nil {
+		return nil, err
+	}
+	if compressed {
+		bodyHash = nil // signature covers body as sent
 	}
+	if c.signer != nil && bodyHash == nil {
+		h, err := replayBodyHash(req)
+		if err != nil {
+			return nil, err
+		}
+		bodyHash = h
+	}
+
+	// Generated key is shared by all attempts of this call
+	idemKey := c.autoIdempotencyKey(req)
+	idemHeader := c.idempotencyHeader()
 
-	retries := c.retries
+	retries := o.retries
 	if _, ok := c.retryMethods[req.Method]; !ok {
-		if !(req.Method == stdhttp.MethodPost && req.Header.Get("Idempotency-Key") != "") && !c.retryNonIdem {
+		hasKey := idemKey != "" || req.Method == stdhttp.MethodPost &&
+			(req.Header.Get(defaultIdempotencyHeader) != "" || req.Header.Get(idemHeader) != "")
+		if !hasKey && !o.retryNonIdem {
 			retries = 0
 		}
 	}
 
+	// Cache is consulted only before the first attempt; retries always go to network
+	cacheKey, cacheable := c.cacheKey(req, o)
+	if cacheable {
+		if resp, ok := c.cachedResponse(req, cacheKey); ok {
+			c.log.Debug("http cache hit", slog.String("method", req.Method), slog.String("url", c.redactURL(req.URL)), slog.Int("status", resp.StatusCode))
+			return resp, nil
+		}
+	}
+
 	var lastErr error
-	var budgetExceeded bool
+	var durationExceeded bool
 	start := time.Now()
+	log.start = start
+	if c.retryBudget != nil {
+		c.retryBudget.recordRequest()
+	}
+	// Span of current attempt is ended before waiting for the next one, or on return
+	var attemptSpan Span
+	endAttempt := func() {
+		if attemptSpan != nil {
+			attemptSpan.End()
+			attemptSpan = nil
+		}
+	}
+	defer endAttempt()
 	for attempt := 1; attempt <= retries+1; attempt++ {
-		r := req.Clone(ctx)
+		u := c.redactURL(req.URL)
+		limitWait, cancelLimit := c.reserveRateLimit(req.URL.Hostname())
+		if limitWait > 0 {
+			// Rate limit wait of retries counts toward MaxRetryDuration
+			if c.maxRetryDuration > 0 && attempt > 1 && time.Since(start)+limitWait > c.maxRetryDuration {
+				cancelLimit()
+				durationExceeded = true
+				break
+			}
+			c.log.Debug("http rate limit wait", slog.String("method", req.Method), slog.String("url", u), slog.Int("attempt", attempt), slog.Duration("wait", limitWait))
+			if err := sleepCtx(ctx, limitWait); err != nil {
+				cancelLimit()
+				return nil, err
+			}
+		}
+		var attemptCtx context.Context
+		attemptCtx, attemptSpan = c.startAttemptSpan(ctx, req, attempt, limitWait)
+		attemptCtx, tracer := c.withTracer(withAttemptInfo(attemptCtx, AttemptInfo{Attempt: attempt, RedactedURL: u, RateLimitWait: limitWait}))
+		attemptCtx, cancelAttempt := withTimeout(attemptCtx, c.attemptTimeout)
+		r := req.Clone(attemptCtx)
 		for k, v := range c.headers {
 			if r.Header.Get(k) == "" {
 				r.Header.Set(k, v)
 			}
 		}
+		if idemKey != "" {
+			r.Header.Set(idemHeader, idemKey)
+		}
+		// Responses are decoded only when Accept-Encoding was set by the client itself
+		decode := false
+		if len(c.compression) > 0 && r.Header.Get("Accept-Encoding") == "" {
+			if ae := c.acceptEncoding(); ae != "" {
+				r.Header.Set("Accept-Encoding", ae)
+				decode = true
+			}
+		}
+		c.runRequestHooks(r)
 		if r.GetBody != nil {
 			rc, err := r.GetBody()
 			if err != nil {
+				releaseWithBody(nil, cancelAttempt)
 				return nil, err
 			}
 			r.Body = rc
 		}
-		u := c.redactURL(r.URL)
+		if c.signer != nil {
+			if err := c.signer.Sign(r, bodyHash); err != nil {
+				releaseWithBody(nil, cancelAttempt)
+				return nil, fmt.Errorf("sign request: %w", err)
+			}
+		}
+		if attempt == 1 {
+			// Retries are never mirrored
+			c.mirrorRequest(r)
+		}
 		st := time.Now()
-		resp, err := c.hc.Do(r)
+		resp, err := hc.Do(r)
 		dur := time.Since(st)
-		delay, retry := c.retryPolicy(resp, err)
+		log.attempts++
+		releaseWithBody(resp, cancelAttempt)
+		c.runResponseHooks(r, resp, err, dur, attempt)
+		if tracer != nil {
+			c.reportTrace(tracer, r, resp, err, attempt)
+		}
+		var delay time.Duration
+		var retry bool
+		var validationErr error
+		if err == nil && c.responseValidator != nil {
+			// Validator sees decoded body, so decompression happens before it
+			if decode {
+				c.decompressResponse(r, resp)
+				decode = false
+			}
+			var readErr error
+			validationErr, readErr = c.validateResponse(resp)
+			if readErr != nil {
+				drainAndClose(resp.Body)
+				resp, err = nil, readErr
+			}
+		}
+		err = c.attemptTimeoutError(ctx, err)
+		if validationErr != nil {
+			delay, retry = c.classifyValidationError(validationErr)
+			drainAndClose(resp.Body)
+			err = &ValidationError{Method: r.Method, URL: u, StatusCode: resp.StatusCode, Err: validationErr}
+			resp = nil
+		} else {
+			delay, retry = c.classifyRetry(resp, err)
+		}
 		retryAfterDelay := delay > 0
+		if retry || err != nil {
+			log.record(attempt, st, attemptStatus(resp, err), err)
+		}
+		annotateAttempt(attemptSpan, attemptStatus(resp, err), err)
 		if resp != nil && resp.StatusCode == 421 {
-			if tr, ok := c.hc.Transport.(interface{ CloseIdleConnections() }); ok {
-				tr.CloseIdleConnections()
-			}
+			c.closeIdleConnections(r.URL)
 		}
 		if !retry {
 			if err != nil {
@@ -351,6 +548,12 @@ func (c *Client) Do(ctx context.Context, req *stdhttp.Request) (*stdhttp.Respons
 				return nil, err
 			}
 			c.log.Info("http request", slog.String("method", r.Method), slog.String("url", u), slog.Int("status", resp.StatusCode), slog.Duration("dur", dur), slog.Int("attempt", attempt))
+			if decode {
+				c.decompressResponse(r, resp)
+			}
+			if cacheable {
+				c.storeResponse(cacheKey, resp, o.maxResponseBody)
+			}
 			return resp, nil
 		}
 		wait := c.baseBackoff * time.Duration(1<<uint(attempt-1))
@@ -377,12 +580,17 @@ func (c *Client) Do(ctx context.Context, req *stdhttp.Request) (*stdhttp.Respons
 		if attemptsLeft < 0 {
 			attemptsLeft = 0
 		}
+		logAttrs := []any{slog.String("method", r.Method), slog.String("url", u), slog.Int("attempt", attempt), slog.Int("attempts_left", attemptsLeft), slog.Duration("wait", wait), slog.Duration("retry_after", delay), slog.Bool("idempotency_key", r.Header.Get(idemHeader) != "" || r.Header.Get(defaultIdempotencyHeader) != "")}
+		if idemKey != "" {
+			// Prefix of generated key allows correlating retries with server logs
+			logAttrs = append(logAttrs, slog.String("idempotency_key_prefix", idempotencyKeyPrefix(idemKey)))
+		}
 		if err != nil {
 			lastErr = err
-			c.log.Warn("http request error", slog.String("method", r.Method), slog.String("url", u), slog.Int("attempt", attempt), slog.Int("attempts_left", attemptsLeft), slog.Duration("wait", wait), slog.Duration("retry_after", delay), slog.Bool("idempotency_key", r.Header.Get("Idempotency-Key") != ""), slog.Any("error", err))
+			c.log.Warn("http request error", append(logAttrs, slog.Any("error", err))...)
 		} else {
-			lastErr = fmt.Errorf("%s %s: unexpected status %d", r.Method, c.redactURL(r.URL), resp.StatusCode)
-			c.log.Warn("http request status", slog.String("method", r.Method), slog.String("url", u), slog.Int("attempt", attempt), slog.Int("attempts_left", attemptsLeft), slog.Duration("wait", wait), slog.Duration("retry_after", delay), slog.Bool("idempotency_key", r.Header.Get("Idempotency-Key") != ""), slog.Int("status", resp.StatusCode))
+			lastErr = &HTTPError{Method: r.Method, URL: c.redactURL(r.URL), StatusCode: resp.StatusCode}
+			c.log.Warn("http request status", append(logAttrs, slog.Int("status", resp.StatusCode))...)
 		}
 		if err := ctx.Err(); err != nil {
 			return nil, err
@@ -391,10 +599,17 @@ func (c *Client) Do(ctx context.Context, req *stdhttp.Request) (*stdhttp.Respons
 			if c.maxRetryDuration > 0 {
 				elapsed := time.Since(start)
 				if elapsed+wait > c.maxRetryDuration {
-					budgetExceeded = true
+					durationExceeded = true
 					break
 				}
 			}
+			if c.retryBudget != nil && !c.retryBudget.Allow() {
+				c.log.Warn("http retry budget exhausted", slog.String("method", r.Method), slog.String("url", u), slog.Int("attempt", attempt))
+				return nil, fmt.Errorf("%w: %w", ErrRetryBudgetExhausted, lastErr)
+			}
+			log.setWait(attempt, wait)
+			annotateRetryWait(attemptSpan, wait)
+			endAttempt()
 			timer := time.NewTimer(wait)
 			select {
 			case <-timer.C:
@@ -414,8 +629,20 @@ func (c *Client) Do(ctx context.Context, req *stdhttp.Request) (*stdhttp.Respons
 			}
 		}
 	}
-	if budgetExceeded && lastErr != nil {
-		return nil, fmt.Errorf("retry budget exceeded: %w", lastErr)
+	if durationExceeded && lastErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMaxRetryDurationExceeded, lastErr)
 	}
 	return nil, lastErr
 }
+
+// attemptStatus returns response status of attempt, including rejected by validator.
+func attemptStatus(resp *stdhttp.Response, err error) int {
+	if resp != nil {
+		return resp.StatusCode
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.StatusCode
+	}
+	return 0
+}
@@ -3,6 +3,7 @@ package httpclient_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"net"
@@ -18,10 +19,18 @@ import (
 	"time"
 
 	httpclient "sttbot/internal/platform/httpclient"
+	"sttbot/internal/shared"
 
 	"github.com/stretchr/testify/require"
 )
 
+// newTestClient returns a client with a discarded logger and the given options
+func newTestClient(opts ...httpclient.Option) *httpclient.Client {
+	return httpclient.New(append([]httpclient.Option{
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	}, opts...)...)
+}
+
 func TestClient_Do_Retries(t *testing.T) {
 	var attempts int
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -203,6 +212,34 @@ func TestClient_Do_RetryNetErrClosed(t *testing.T) {
 	require.Equal(t, 2, attempts)
 }
 
+func TestClient_Do_RetryMarkedRetryable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var attempts int
+	rt := rtFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, shared.MarkRetryable(errors.New("upstream proxy busy"))
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, 0),
+		httpclient.WithTransport(rt),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 2, attempts)
+}
+
 func TestClient_Do_Headers(t *testing.T) {
 	var header string
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -472,7 +509,7 @@ func TestClient_Do_MaxRetryDuration(t *testing.T) {
 
 	_, err = c.Do(context.Background(), req)
 	require.Error(t, err)
-	require.ErrorContains(t, err, "retry budget exceeded")
+	require.ErrorIs(t, err, httpclient.ErrMaxRetryDurationExceeded)
 	require.Equal(t, 2, attempts)
 }
 
@@ -0,0 +1,222 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	stdhttp "net/http"
+	"strings"
+)
+
+// Decompressor creates reader decoding body compressed with a content encoding.
+type Decompressor func(r io.Reader) (io.ReadCloser, error)
+
+// WithCompression advertises encodings in Accept-Encoding, in the given order, and
+// transparently decodes responses compressed with them. gzip is supported out of the
+// box; other encodings such as br or zstd need a decoder registered with WithDecompressor
+// and are not advertised without one. Requests that already set Accept-Encoding are
+// sent as is. Decoded responses have Content-Encoding and Content-Length removed,
+// ContentLength set to -1 and Uncompressed set to true; responses without a known
+// Content-Encoding pass through untouched.
+func WithCompression(encodings ...string) Option {
+	return func(c *Client) {
+		c.compression = c.compression[:0]
+		for _, e := range encodings {
+			if e = strings.ToLower(strings.TrimSpace(e)); e != "" {
+				c.compression = append(c.compression, e)
+			}
+		}
+	}
+}
+
+// WithRequestCompression gzips request bodies larger than minSize bytes and sets
+// Content-Encoding: gzip. Only replayable bodies are compressed: bodies buffered by
+// the client and bodies with GetBody of known size within WithMaxReplayBody; streaming
+// bodies of unknown size (see PostMultipart) and requests that already set
+// Content-Encoding are sent as is. Body is compressed once and every attempt sends
+// the same compressed bytes with updated Content-Length. Use NoRequestCompression
+// for servers that do not accept compressed requests.
+func WithRequestCompression(minSize int64) Option {
+	return func(c *Client) {
+		c.compressRequests = true
+		c.compressMinSize = minSize
+	}
+}
+
+// NoRequestCompression sends body of single request uncompressed despite WithRequestCompression.
+func NoRequestCompression() DoOption {
+	return func(o *doOptions) { o.noCompressBody = true }
+}
+
+// WithDecompressor registers decoder for content encoding used by WithCompression,
+// for example a zstd or brotli implementation:
+//
+//	httpclient.WithDecompressor("zstd", func(r io.Reader) (io.ReadCloser, error) {
+//		d, err := zstd.NewReader(r)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return d.IOReadCloser(), nil
+//	})
+//
+// Registering gzip replaces the built-in decoder.
+func WithDecompressor(encoding string, d Decompressor) Option {
+	return func(c *Client) {
+		if encoding == "" || d == nil {
+			return
+		}
+		if c.decompressors == nil {
+			c.decompressors = make(map[string]Decompressor)
+		}
+		c.decompressors[strings.ToLower(encoding)] = d
+	}
+}
+
+// gzipDecompressor is the built-in gzip decoder.
+func gzipDecompressor(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// decompressor returns decoder for encoding, including built-in gzip.
+func (c *Client) decompressor(encoding string) (Decompressor, bool) {
+	if d, ok := c.decompressors[encoding]; ok {
+		return d, true
+	}
+	if encoding == "gzip" {
+		return gzipDecompressor, true
+	}
+	return nil, false
+}
+
+// acceptEncoding returns Accept-Encoding value with encodings that can be decoded.
+func (c *Client) acceptEncoding() string {
+	var accepted []string
+	for _, e := range c.compression {
+		if _, ok := c.decompressor(e); ok {
+			accepted = append(accepted, e)
+		}
+	}
+	return strings.Join(accepted, ", ")
+}
+
+// compressRequest replaces replayable body of req with its gzip encoding if it is
+// eligible for WithRequestCompression, and reports whether body was compressed.
+func (c *Client) compressRequest(req *stdhttp.Request, o doOptions) (bool, error) {
+	if !c.compressRequests || o.noCompressBody || req.GetBody == nil || req.Header.Get("Content-Encoding") != "" {
+		return false, nil
+	}
+	// Unknown length (-1) means streaming body, zero means length was not set when buffered
+	if req.ContentLength < 0 || (req.ContentLength > 0 && req.ContentLength <= c.compressMinSize) {
+		return false, nil
+	}
+	if c.maxReplayBody > 0 && req.ContentLength > c.maxReplayBody {
+		return false, nil
+	}
+
+	rc, err := req.GetBody()
+	if err != nil {
+		return false, err
+	}
+	var r io.Reader = rc
+	if c.maxReplayBody > 0 {
+		r = io.LimitReader(rc, c.maxReplayBody+1)
+	}
+	body, err := io.ReadAll(r)
+	_ = rc.Close()
+	if err != nil {
+		return false, err
+	}
+	if int64(len(body)) <= c.compressMinSize || (c.maxReplayBody > 0 && int64(len(body)) > c.maxReplayBody) {
+		return false, nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return false, err
+	}
+	if err := zw.Close(); err != nil {
+		return false, err
+	}
+	compressed := buf.Bytes()
+
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(compressed)), nil }
+	req.Body, _ = req.GetBody()
+	req.ContentLength = int64(len(compressed))
+	if req.Header == nil {
+		req.Header = make(stdhttp.Header)
+	}
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Del("Content-Length")
+	return true, nil
+}
+
+// decompressResponse wraps body of response encoded with advertised encoding.
+// Decoder is created on first read, so errors in compressed data are reported by Read.
+func (c *Client) decompressResponse(req *stdhttp.Request, resp *stdhttp.Response) {
+	if req.Method == stdhttp.MethodHead || resp.Body == nil || resp.Body == stdhttp.NoBody {
+		return
+	}
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" || !c.acceptsEncoding(encoding) {
+		return
+	}
+	d, ok := c.decompressor(encoding)
+	if !ok {
+		return
+	}
+
+	resp.Body = &decompressedBody{raw: resp.Body, newDecoder: d, encoding: encoding}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// acceptsEncoding reports whether encoding was advertised with WithCompression.
+func (c *Client) acceptsEncoding(encoding string) bool {
+	for _, e := range c.compression {
+		if e == encoding {
+			return true
+		}
+	}
+	return false
+}
+
+// decompressedBody decodes raw response body lazily and closes both readers.
+type decompressedBody struct {
+	raw        io.ReadCloser
+	newDecoder Decompressor
+	encoding   string
+	decoder    io.ReadCloser
+	err        error
+}
+
+// Read implements io.Reader.
+func (b *decompressedBody) Read(p []byte) (int, error) {
+	if b.decoder == nil && b.err == nil {
+		decoder, err := b.newDecoder(b.raw)
+		if err != nil {
+			b.err = fmt.Errorf("http: decode %s response: %w", b.encoding, err)
+		} else {
+			b.decoder = decoder
+		}
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.decoder.Read(p)
+}
+
+// Close closes decoder and drains and closes raw body so connection can be reused.
+func (b *decompressedBody) Close() error {
+	if b.decoder != nil {
+		_ = b.decoder.Close()
+	}
+	drainAndClose(b.raw)
+	return nil
+}
@@ -0,0 +1,312 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func flateBytes(t *testing.T, data string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	fw, err := flate.NewWriter(&buf, flate.BestSpeed)
+	require.NoError(t, err)
+	_, err = fw.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, fw.Close())
+	return buf.Bytes()
+}
+
+// encodedServer responds with body compressed according to encoding and records Accept-Encoding.
+func encodedServer(t *testing.T, encoding string, body []byte, acceptEncoding *atomic.Value) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding.Store(r.Header.Get("Accept-Encoding"))
+		if encoding != "" {
+			w.Header().Set("Content-Encoding", encoding)
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func doGet(t *testing.T, c *httpclient.Client, rawURL string, header http.Header) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	require.NoError(t, err)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestWithCompression_Gzip(t *testing.T) {
+	var accept atomic.Value
+	srv := encodedServer(t, "gzip", gzipBytes(t, `{"text":"hello"}`), &accept)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithCompression("zstd", "gzip"),
+	)
+	resp, body := doGet(t, c, srv.URL, nil)
+
+	require.Equal(t, "gzip", accept.Load(), "encodings without decoder are not advertised")
+	require.Equal(t, `{"text":"hello"}`, body)
+	require.Empty(t, resp.Header.Get("Content-Encoding"))
+	require.Empty(t, resp.Header.Get("Content-Length"))
+	require.Equal(t, int64(-1), resp.ContentLength)
+	require.True(t, resp.Uncompressed)
+}
+
+func TestWithCompression_CustomDecompressor(t *testing.T) {
+	var accept atomic.Value
+	// flate stands in for a real brotli decoder
+	srv := encodedServer(t, "br", flateBytes(t, "brotli payload"), &accept)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithCompression("br", "gzip"),
+		httpclient.WithDecompressor("BR", func(r io.Reader) (io.ReadCloser, error) {
+			return flate.NewReader(r), nil
+		}),
+	)
+	resp, body := doGet(t, c, srv.URL, nil)
+
+	require.Equal(t, "br, gzip", accept.Load())
+	require.Equal(t, "brotli payload", body)
+	require.Equal(t, int64(-1), resp.ContentLength)
+}
+
+func TestWithCompression_PassThrough(t *testing.T) {
+	var accept atomic.Value
+	srv := encodedServer(t, "", []byte("plain"), &accept)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithCompression("gzip"),
+	)
+	resp, body := doGet(t, c, srv.URL, nil)
+
+	require.Equal(t, "plain", body)
+	require.Equal(t, int64(5), resp.ContentLength)
+	require.False(t, resp.Uncompressed)
+}
+
+func TestWithCompression_CallerAcceptEncoding(t *testing.T) {
+	var accept atomic.Value
+	compressed := gzipBytes(t, "raw")
+	srv := encodedServer(t, "gzip", compressed, &accept)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithCompression("gzip"),
+	)
+	resp, body := doGet(t, c, srv.URL, http.Header{"Accept-Encoding": {"gzip"}})
+
+	// Caller asked for encoding itself and gets the raw body
+	require.Equal(t, "gzip", accept.Load())
+	require.Equal(t, string(compressed), body)
+	require.Equal(t, "gzip", resp.Header.Get("Content-Encoding"))
+}
+
+func TestWithCompression_Retries(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write(gzipBytes(t, "try later"))
+			return
+		}
+		_, _ = w.Write(gzipBytes(t, `{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, 0),
+		httpclient.WithCompression("gzip"),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	require.NoError(t, c.DoJSON(context.Background(), req, &out))
+	require.True(t, out.OK)
+	require.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
+
+func TestWithCompression_CorruptBody(t *testing.T) {
+	var accept atomic.Value
+	srv := encodedServer(t, "gzip", []byte("not gzip at all"), &accept)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithCompression("gzip"),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	_, err = io.ReadAll(resp.Body)
+	require.ErrorContains(t, err, "decode gzip response")
+}
+
+// receivedBody describes request body as seen by server.
+type receivedBody struct {
+	encoding      string
+	contentLength int64
+	body          string
+	gzipErr       error
+}
+
+// recordingServer decodes gzip request bodies and answers first failAttempts requests with 503.
+func recordingServer(t *testing.T, failAttempts int32) (*httptest.Server, chan receivedBody) {
+	t.Helper()
+	received := make(chan receivedBody, 10)
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rb := receivedBody{encoding: r.Header.Get("Content-Encoding"), contentLength: r.ContentLength}
+		var body io.Reader = r.Body
+		if rb.encoding == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				rb.gzipErr = err
+				received <- rb
+				return
+			}
+			body = zr
+		}
+		data, err := io.ReadAll(body)
+		rb.gzipErr = err
+		rb.body = string(data)
+		received <- rb
+		if attempts.Add(1) <= failAttempts {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, received
+}
+
+func TestClient_RequestCompression_RetriedAttempt(t *testing.T) {
+	srv, received := recordingServer(t, 1)
+	payload := strings.Repeat(`{"text":"hello world"}`, 100)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+		httpclient.WithRequestCompression(1024),
+	)
+	req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(payload))
+	require.NoError(t, err)
+	resp, err := c.Do(context.Background(), req, httpclient.PerRequestRetryNonIdempotent(true))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.Len(t, received, 2)
+	for attempt := 1; attempt <= 2; attempt++ {
+		rb := <-received
+		require.Equal(t, "gzip", rb.encoding, "attempt %d", attempt)
+		require.NoError(t, rb.gzipErr, "attempt %d", attempt)
+		require.Equal(t, payload, rb.body, "attempt %d", attempt)
+		require.Positive(t, rb.contentLength)
+		require.Less(t, rb.contentLength, int64(len(payload)), "attempt %d", attempt)
+	}
+}
+
+func TestClient_RequestCompression_BufferedBody(t *testing.T) {
+	srv, received := recordingServer(t, 0)
+	payload := strings.Repeat("a", 4096)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRequestCompression(1024),
+	)
+	// Reader without GetBody is buffered by client
+	req, err := http.NewRequest(http.MethodPut, srv.URL, io.MultiReader(strings.NewReader(payload)))
+	require.NoError(t, err)
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	rb := <-received
+	require.Equal(t, "gzip", rb.encoding)
+	require.NoError(t, rb.gzipErr)
+	require.Equal(t, payload, rb.body)
+}
+
+func TestClient_RequestCompression_Skipped(t *testing.T) {
+	largePayload := strings.Repeat("b", 4096)
+
+	tests := []struct {
+		name     string
+		body     string
+		encoding string
+		opts     []httpclient.DoOption
+	}{
+		{name: "below threshold", body: strings.Repeat("c", 1024)},
+		{name: "opt-out", body: largePayload, opts: []httpclient.DoOption{httpclient.NoRequestCompression()}},
+		{name: "content encoding already set", body: largePayload, encoding: "identity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, received := recordingServer(t, 0)
+			c := httpclient.New(
+				httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+				httpclient.WithRequestCompression(1024),
+			)
+			req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			resp, err := c.Do(context.Background(), req, tt.opts...)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			rb := <-received
+			require.Equal(t, tt.encoding, rb.encoding)
+			require.Equal(t, tt.body, rb.body)
+			require.Equal(t, int64(len(tt.body)), rb.contentLength)
+		})
+	}
+}
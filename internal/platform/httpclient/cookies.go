@@ -0,0 +1,111 @@
+package httpclient
+
+import (
+	"net"
+	stdhttp "net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// WithCookieJar sets cookie jar used for all requests, redirects and retries.
+// Cookies received in any response, including failed attempts and redirects,
+// are stored in jar and sent with following attempts and requests.
+// Nil jar disables cookies.
+func WithCookieJar(jar stdhttp.CookieJar) Option {
+	return func(c *Client) { c.hc.Jar = jar }
+}
+
+// WithSessionCookies installs in-memory cookie jar backed by net/http/cookiejar
+// with public suffix list, so cookies are never shared across registrable domains
+// ("example.com" cannot set cookies for "co.uk"). Each site gets separate jar,
+// which lets ClearCookies reset one session without touching others.
+//
+// Retried attempts reuse jar state: cookies set by failed attempt (e.g. 5xx
+// response refreshing session) are sent with the retry, as browser would do.
+func WithSessionCookies() Option {
+	return WithCookieJar(newSessionJar())
+}
+
+// cookieClearer is implemented by jars supporting ClearCookies.
+type cookieClearer interface {
+	ClearCookies(host string)
+}
+
+// ClearCookies removes cookies stored for host. Jar installed by WithSessionCookies
+// keeps cookies per registrable domain, so cookies of other hosts of same site
+// (e.g. "login.example.com" for "portal.example.com") are removed too, since
+// they may be shared through Domain attribute.
+// Custom jar from WithCookieJar is cleared only if it has ClearCookies(host string) method.
+// Reports whether cookies were cleared.
+func (c *Client) ClearCookies(host string) bool {
+	clearer, ok := c.hc.Jar.(cookieClearer)
+	if !ok {
+		return false
+	}
+	clearer.ClearCookies(host)
+	return true
+}
+
+// sessionJar keeps separate cookiejar.Jar per registrable domain.
+type sessionJar struct {
+	mu   sync.Mutex
+	jars map[string]*cookiejar.Jar
+}
+
+func newSessionJar() *sessionJar {
+	return &sessionJar{jars: make(map[string]*cookiejar.Jar)}
+}
+
+// SetCookies stores cookies received from u.
+func (j *sessionJar) SetCookies(u *url.URL, cookies []*stdhttp.Cookie) {
+	j.jar(u.Hostname(), true).SetCookies(u, cookies)
+}
+
+// Cookies returns cookies to send to u.
+func (j *sessionJar) Cookies(u *url.URL) []*stdhttp.Cookie {
+	if jar := j.jar(u.Hostname(), false); jar != nil {
+		return jar.Cookies(u)
+	}
+	return nil
+}
+
+// ClearCookies drops jar of site host belongs to.
+func (j *sessionJar) ClearCookies(host string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	delete(j.jars, siteOf(host))
+}
+
+// jar returns jar of site host belongs to, creating it if create is set.
+func (j *sessionJar) jar(host string, create bool) *cookiejar.Jar {
+	site := siteOf(host)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	jar := j.jars[site]
+	if jar == nil && create {
+		// cookiejar.New never fails
+		jar, _ = cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+		j.jars[site] = jar
+	}
+	return jar
+}
+
+// siteOf returns registrable domain of host (eTLD+1), or host itself for IP
+// addresses, single-label hosts and public suffixes.
+func siteOf(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(strings.Trim(host, "[]"), "."))
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	if site, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return site
+	}
+	return host
+}
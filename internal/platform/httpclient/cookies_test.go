@@ -0,0 +1,157 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/platform/httpclient"
+)
+
+// get performs GET request and returns response status and body.
+func get(t *testing.T, c *httpclient.Client, url string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+// sessionPortal serves /login setting session cookie and /data requiring it.
+func sessionPortal() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cret", Path: "/"})
+		if r.URL.Query().Get("next") != "" {
+			http.Redirect(w, r, r.URL.Query().Get("next"), http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/data", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, "data")
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestClient_SessionCookies(t *testing.T) {
+	srv := sessionPortal()
+	defer srv.Close()
+
+	cold := httpclient.New()
+	status, _ := get(t, cold, srv.URL+"/login")
+	require.Equal(t, http.StatusOK, status)
+	status, _ = get(t, cold, srv.URL+"/data")
+	require.Equal(t, http.StatusUnauthorized, status, "without jar every request starts cold")
+
+	c := httpclient.New(httpclient.WithSessionCookies())
+	status, _ = get(t, c, srv.URL+"/login")
+	require.Equal(t, http.StatusOK, status)
+	status, body := get(t, c, srv.URL+"/data")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "data", body)
+
+	require.True(t, c.ClearCookies(strings.TrimPrefix(srv.URL, "http://")))
+	status, _ = get(t, c, srv.URL+"/data")
+	require.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestClient_SessionCookiesAcrossRedirect(t *testing.T) {
+	srv := sessionPortal()
+	defer srv.Close()
+
+	c := httpclient.New(httpclient.WithSessionCookies())
+	status, body := get(t, c, srv.URL+"/login?next=/data")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "data", body)
+}
+
+func TestClient_SessionCookiesOnRetry(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			// Failed attempt still refreshes session
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "renewed"})
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "renewed" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	c := httpclient.New(
+		httpclient.WithSessionCookies(),
+		httpclient.WithRetries(1, 0),
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+	status, _ := get(t, c, srv.URL)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, 2, attempts)
+	require.NotContains(t, logs.String(), "renewed", "cookie values must not be logged")
+}
+
+func TestClient_SessionCookiesSiteIsolation(t *testing.T) {
+	sent := make(map[string]string)
+	rt := rtFunc(func(r *http.Request) (*http.Response, error) {
+		sent[r.URL.Host] = r.Header.Get("Cookie")
+		h := http.Header{}
+		switch r.URL.Host {
+		case "login.example.com":
+			h.Add("Set-Cookie", "sso=1; Domain=example.com; Path=/")
+		case "evil.co.uk":
+			h.Add("Set-Cookie", "tracker=1; Domain=co.uk; Path=/")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Header: h, Body: http.NoBody, Request: r}, nil
+	})
+	c := httpclient.New(httpclient.WithTransport(rt), httpclient.WithSessionCookies())
+
+	for _, host := range []string{"login.example.com", "portal.example.com", "example.org", "evil.co.uk", "shop.co.uk"} {
+		get(t, c, "http://"+host+"/")
+	}
+	require.Equal(t, "sso=1", sent["portal.example.com"], "domain cookie shared within site")
+	require.Empty(t, sent["example.org"], "cookies never cross sites")
+	require.Empty(t, sent["shop.co.uk"], "cookies for public suffix are rejected")
+
+	// Clearing one host resets its site only
+	get(t, c, "http://login.example.com/")
+	require.True(t, c.ClearCookies("portal.example.com"))
+	get(t, c, "http://login.example.com/")
+	require.Empty(t, sent["login.example.com"])
+}
+
+func TestClient_WithCookieJar(t *testing.T) {
+	srv := sessionPortal()
+	defer srv.Close()
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	c := httpclient.New(httpclient.WithCookieJar(jar))
+
+	get(t, c, srv.URL+"/login")
+	status, _ := get(t, c, srv.URL+"/data")
+	require.Equal(t, http.StatusOK, status)
+	require.False(t, c.ClearCookies(strings.TrimPrefix(srv.URL, "http://")), "standard jar cannot be cleared")
+}
@@ -0,0 +1,291 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	stdhttp "net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultDNSMaxStale limits how long expired DNS entry may be served when lookups fail.
+const defaultDNSMaxStale = 10 * time.Minute
+
+// DNSResolver resolves host names. *net.Resolver implements it.
+type DNSResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// DNSCacheStats contains DNS cache counters.
+type DNSCacheStats struct {
+	Hits   int64 // lookups answered from fresh entries, including cached failures
+	Misses int64 // lookups sent to resolver
+	Stale  int64 // expired entries served because resolver failed
+}
+
+// dnsConfig contains DNS cache settings collected from options.
+type dnsConfig struct {
+	enabled     bool
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxStale    time.Duration
+	maxStaleSet bool
+	resolver    DNSResolver
+}
+
+// WithDNSCache caches DNS lookups of dialed hosts for ttl and failed lookups for negativeTTL
+// (0 disables negative caching). Connections rotate among returned addresses and fall back
+// to the next one if dial fails. When fresh lookup fails, expired entry is served for up to
+// max staleness (10 minutes by default, see WithDNSMaxStale). Cache is installed into
+// client transport after all options, so it works together with WithTransport and WithProxy
+// as long as that transport is *http.Transport (it is cloned, not modified); with other
+// transports the cache is not used. With proxy, the proxy host is resolved through the cache.
+func WithDNSCache(ttl time.Duration, negativeTTL time.Duration) Option {
+	return func(c *Client) {
+		c.dns.enabled = ttl > 0
+		c.dns.ttl = ttl
+		c.dns.negativeTTL = negativeTTL
+	}
+}
+
+// WithDNSMaxStale limits how long expired entry of WithDNSCache may be served
+// when lookups fail. Zero or negative value disables serving stale entries.
+func WithDNSMaxStale(d time.Duration) Option {
+	return func(c *Client) {
+		c.dns.maxStale = max(d, 0)
+		c.dns.maxStaleSet = true
+	}
+}
+
+// WithDNSResolver sets resolver used by WithDNSCache (net.DefaultResolver by default).
+func WithDNSResolver(r DNSResolver) Option {
+	return func(c *Client) { c.dns.resolver = r }
+}
+
+// FlushDNSCache drops all entries of WithDNSCache. Counters are kept.
+func (c *Client) FlushDNSCache() {
+	if c.dnsCache != nil {
+		c.dnsCache.flush()
+	}
+}
+
+// DNSCacheStats returns counters of WithDNSCache.
+func (c *Client) DNSCacheStats() DNSCacheStats {
+	if c.dnsCache == nil {
+		return DNSCacheStats{}
+	}
+	return DNSCacheStats{
+		Hits:   c.dnsCache.hits.Load(),
+		Misses: c.dnsCache.misses.Load(),
+		Stale:  c.dnsCache.stale.Load(),
+	}
+}
+
+// installDNSCache wraps dialer of transport with DNS cache.
+func (c *Client) installDNSCache() {
+	if !c.dns.enabled {
+		return
+	}
+	tr, ok := c.hc.Transport.(*stdhttp.Transport)
+	if !ok {
+		c.log.Warn("http dns cache disabled, transport is not *http.Transport", slog.String("transport", fmt.Sprintf("%T", c.hc.Transport)))
+		return
+	}
+	maxStale := c.dns.maxStale
+	if !c.dns.maxStaleSet {
+		maxStale = defaultDNSMaxStale
+	}
+	resolver := c.dns.resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	c.dnsCache = &dnsCache{
+		resolver:    resolver,
+		ttl:         c.dns.ttl,
+		negativeTTL: c.dns.negativeTTL,
+		maxStale:    maxStale,
+		entries:     make(map[string]*dnsEntry),
+		now:         time.Now,
+	}
+
+	dial := tr.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+	tr = tr.Clone()
+	tr.DialContext = c.dnsCache.dialContext(dial)
+	c.hc.Transport = tr
+}
+
+// dnsEntry is cached result of lookup.
+type dnsEntry struct {
+	addrs   []net.IPAddr
+	err     error // cached failure, addrs is empty
+	expires time.Time
+	next    atomic.Uint32 // rotation counter
+}
+
+// dnsCache resolves hosts through resolver and caches results.
+type dnsCache struct {
+	resolver    DNSResolver
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxStale    time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]*dnsEntry
+	inflight map[string]*dnsCall
+
+	hits   atomic.Int64
+	misses atomic.Int64
+	stale  atomic.Int64
+}
+
+// dnsCall is lookup in progress shared by concurrent dials to the same host.
+type dnsCall struct {
+	done  chan struct{}
+	entry *dnsEntry
+	err   error
+}
+
+// flush drops all entries.
+func (d *dnsCache) flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries = make(map[string]*dnsEntry)
+}
+
+// lookup returns addresses of host rotated for this call.
+func (d *dnsCache) lookup(ctx context.Context, host string) ([]net.IPAddr, error) {
+	d.mu.Lock()
+	entry := d.entries[host]
+	if entry != nil && d.now().Before(entry.expires) {
+		d.mu.Unlock()
+		d.hits.Add(1)
+		if entry.err != nil {
+			return nil, entry.err
+		}
+		return entry.rotated(), nil
+	}
+	call, ok := d.inflight[host]
+	if !ok {
+		call = &dnsCall{done: make(chan struct{})}
+		if d.inflight == nil {
+			d.inflight = make(map[string]*dnsCall)
+		}
+		d.inflight[host] = call
+		d.misses.Add(1)
+		go d.resolve(host, entry, call)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if call.err != nil {
+		return nil, call.err
+	}
+	return call.entry.rotated(), nil
+}
+
+// resolve performs lookup and stores its result. Lookup is detached from dial context,
+// so canceled dial does not fail other dials waiting for the same host.
+func (d *dnsCache) resolve(host string, prev *dnsEntry, call *dnsCall) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	addrs, err := d.resolver.LookupIPAddr(ctx, host)
+	if err == nil && len(addrs) == 0 {
+		err = &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	now := d.now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.inflight, host)
+	defer close(call.done)
+
+	switch {
+	case err == nil:
+		entry := &dnsEntry{addrs: addrs, expires: now.Add(d.ttl)}
+		if prev != nil && prev.err == nil {
+			entry.next.Store(prev.next.Load())
+		}
+		d.entries[host] = entry
+		call.entry = entry
+	case prev != nil && prev.err == nil && now.Sub(prev.expires) <= d.maxStale:
+		// Serve stale entry, it stays expired so next dial retries lookup
+		d.stale.Add(1)
+		call.entry = prev
+	default:
+		if d.negativeTTL > 0 {
+			d.entries[host] = &dnsEntry{err: err, expires: now.Add(d.negativeTTL)}
+		} else if prev != nil {
+			delete(d.entries, host)
+		}
+		call.err = err
+	}
+}
+
+// rotated returns addresses starting from next one in rotation.
+func (e *dnsEntry) rotated() []net.IPAddr {
+	n := len(e.addrs)
+	start := int((e.next.Add(1) - 1) % uint32(n))
+	out := make([]net.IPAddr, 0, n)
+	out = append(out, e.addrs[start:]...)
+	return append(out, e.addrs[:start]...)
+}
+
+// dialContext returns dialer resolving host through cache and dialing its addresses in order.
+func (d *dnsCache) dialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+		addrs, err := d.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		var firstErr error
+		for _, ip := range addrs {
+			if !networkMatches(network, ip.IP) {
+				continue
+			}
+			target := ip.IP.String()
+			if ip.Zone != "" {
+				target += "%" + ip.Zone
+			}
+			conn, err := dial(ctx, network, net.JoinHostPort(target, port))
+			if err == nil {
+				return conn, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		if firstErr == nil {
+			firstErr = &net.AddrError{Err: "no suitable address found", Addr: host}
+		}
+		return nil, firstErr
+	}
+}
+
+// networkMatches reports whether ip can be dialed over network ("tcp4", "tcp6" or any).
+func networkMatches(network string, ip net.IP) bool {
+	switch network {
+	case "tcp4", "udp4":
+		return ip.To4() != nil
+	case "tcp6", "udp6":
+		return ip.To4() == nil
+	}
+	return true
+}
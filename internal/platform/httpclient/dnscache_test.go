@@ -0,0 +1,220 @@
+package httpclient_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResolver returns configured addresses and counts lookups.
+type fakeResolver struct {
+	mu    sync.Mutex
+	addrs []net.IPAddr
+	err   error
+	calls int
+}
+
+func (r *fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	return r.addrs, r.err
+}
+
+func (r *fakeResolver) set(err error, ips ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+	r.addrs = nil
+	for _, ip := range ips {
+		r.addrs = append(r.addrs, net.IPAddr{IP: net.ParseIP(ip)})
+	}
+}
+
+func (r *fakeResolver) lookups() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+// dnsTestSetup starts server on 127.0.0.1 and returns URL with fake host name and its port.
+func dnsTestSetup(t *testing.T) (string, string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	return "http://telegram.test:" + u.Port(), u.Port()
+}
+
+// recordingDialer records dialed addresses.
+type recordingDialer struct {
+	mu    sync.Mutex
+	addrs []string
+}
+
+func (d *recordingDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	d.mu.Lock()
+	d.addrs = append(d.addrs, addr)
+	d.mu.Unlock()
+	return (&net.Dialer{}).DialContext(ctx, network, addr)
+}
+
+func (d *recordingDialer) dialed() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.addrs...)
+}
+
+func newDNSClient(resolver httpclient.DNSResolver, dialer *recordingDialer, opts ...httpclient.Option) *httpclient.Client {
+	tr := &http.Transport{DisableKeepAlives: true}
+	if dialer != nil {
+		tr.DialContext = dialer.DialContext
+	}
+	return httpclient.New(append([]httpclient.Option{
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithTransport(tr),
+		httpclient.WithDNSResolver(resolver),
+	}, opts...)...)
+}
+
+func dnsGet(t *testing.T, c *httpclient.Client, u string) error {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	require.NoError(t, err)
+	resp, err := c.Do(context.Background(), req)
+	if err == nil {
+		resp.Body.Close()
+	}
+	return err
+}
+
+func TestClient_DNSCache_HitsAndFlush(t *testing.T) {
+	u, _ := dnsTestSetup(t)
+	resolver := &fakeResolver{}
+	resolver.set(nil, "127.0.0.1")
+	c := newDNSClient(resolver, nil, httpclient.WithDNSCache(time.Minute, 0))
+
+	for range 3 {
+		require.NoError(t, dnsGet(t, c, u))
+	}
+	require.Equal(t, 1, resolver.lookups())
+	require.Equal(t, httpclient.DNSCacheStats{Hits: 2, Misses: 1}, c.DNSCacheStats())
+
+	c.FlushDNSCache()
+	require.NoError(t, dnsGet(t, c, u))
+	require.Equal(t, 2, resolver.lookups())
+	require.Equal(t, httpclient.DNSCacheStats{Hits: 2, Misses: 2}, c.DNSCacheStats())
+}
+
+func TestClient_DNSCache_RotationAndFallback(t *testing.T) {
+	u, port := dnsTestSetup(t)
+	resolver := &fakeResolver{}
+	// Server listens only on 127.0.0.1, so dial to 127.0.0.2 fails and falls back
+	resolver.set(nil, "127.0.0.2", "127.0.0.1")
+	dialer := &recordingDialer{}
+	c := newDNSClient(resolver, dialer, httpclient.WithDNSCache(time.Minute, 0))
+
+	require.NoError(t, dnsGet(t, c, u))
+	require.NoError(t, dnsGet(t, c, u))
+	require.Equal(t, []string{
+		"127.0.0.2:" + port, "127.0.0.1:" + port, // first address fails, second works
+		"127.0.0.1:" + port, // rotation starts from second address
+	}, dialer.dialed())
+}
+
+func TestClient_DNSCache_ServeStale(t *testing.T) {
+	u, _ := dnsTestSetup(t)
+	resolver := &fakeResolver{}
+	resolver.set(nil, "127.0.0.1")
+	c := newDNSClient(resolver, nil,
+		httpclient.WithDNSCache(10*time.Millisecond, 0),
+		httpclient.WithDNSMaxStale(time.Hour),
+	)
+	require.NoError(t, dnsGet(t, c, u))
+
+	time.Sleep(20 * time.Millisecond)
+	resolver.set(errors.New("resolver down"))
+	require.NoError(t, dnsGet(t, c, u))
+	require.Equal(t, httpclient.DNSCacheStats{Misses: 2, Stale: 1}, c.DNSCacheStats())
+
+	t.Run("staleness bounded", func(t *testing.T) {
+		resolver.set(nil, "127.0.0.1")
+		c := newDNSClient(resolver, nil,
+			httpclient.WithDNSCache(10*time.Millisecond, 0),
+			httpclient.WithDNSMaxStale(0),
+		)
+		require.NoError(t, dnsGet(t, c, u))
+
+		time.Sleep(20 * time.Millisecond)
+		resolver.set(errors.New("resolver down"))
+		require.ErrorContains(t, dnsGet(t, c, u), "resolver down")
+	})
+}
+
+func TestClient_DNSCache_NegativeTTL(t *testing.T) {
+	u, _ := dnsTestSetup(t)
+	resolver := &fakeResolver{}
+	resolver.set(&net.DNSError{Err: "no such host", Name: "telegram.test", IsNotFound: true})
+	c := newDNSClient(resolver, nil, httpclient.WithDNSCache(time.Minute, time.Minute))
+
+	var dnsErr *net.DNSError
+	require.ErrorAs(t, dnsGet(t, c, u), &dnsErr)
+	require.ErrorAs(t, dnsGet(t, c, u), &dnsErr)
+	require.Equal(t, 1, resolver.lookups())
+	require.Equal(t, httpclient.DNSCacheStats{Hits: 1, Misses: 1}, c.DNSCacheStats())
+}
+
+func TestClient_DNSCache_ConcurrentLookupsShared(t *testing.T) {
+	u, _ := dnsTestSetup(t)
+	resolver := &fakeResolver{}
+	resolver.set(nil, "127.0.0.1")
+	c := newDNSClient(resolver, nil, httpclient.WithDNSCache(time.Minute, 0))
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, u, nil)
+			if resp, err := c.Do(context.Background(), req); err == nil {
+				resp.Body.Close()
+			} else {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, resolver.lookups())
+}
+
+func TestClient_DNSCache_CustomRoundTripperIgnored(t *testing.T) {
+	resolver := &fakeResolver{}
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithTransport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+		})),
+		httpclient.WithDNSResolver(resolver),
+		httpclient.WithDNSCache(time.Minute, 0),
+	)
+	require.NoError(t, dnsGet(t, c, "http://telegram.test/"))
+	require.Zero(t, resolver.lookups())
+	require.Equal(t, httpclient.DNSCacheStats{}, c.DNSCacheStats())
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
@@ -0,0 +1,249 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log/slog"
+	stdhttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrChecksumMismatch indicates downloaded content does not match DownloadOptions.SHA256.
+var ErrChecksumMismatch = errors.New("http: download checksum mismatch")
+
+// ErrSizeMismatch indicates downloaded size differs from size announced by server.
+var ErrSizeMismatch = errors.New("http: download size mismatch")
+
+// downloadBufferSize is size of buffer used to copy response body to destination.
+const downloadBufferSize = 32 << 10
+
+// DownloadOptions configures Download.
+type DownloadOptions struct {
+	// SHA256 is expected hex-encoded SHA-256 of whole content (optional).
+	SHA256 string
+	// Progress is called after each written chunk with bytes written so far and
+	// total size (-1 if unknown). After restart from zero done starts over.
+	Progress func(done, total int64)
+	// Options are applied to every request made by Download.
+	Options []DoOption
+}
+
+// Download streams content of url to dst and returns number of bytes written.
+// Each request goes through Do, so failed responses are retried by client retry policy.
+// If body read fails with error retryable by that policy, download continues with
+// Range request from current offset when server advertised Accept-Ranges: bytes,
+// otherwise it starts over from zero. Server ignoring Range (200 instead of 206)
+// also causes clean restart from zero. Number of consecutive failures without
+// progress is limited by retries (see WithRetries and PerRequestRetries).
+// Size is verified against Content-Length/Content-Range, checksum against
+// DownloadOptions.SHA256. dst is not truncated, so caller must discard its tail
+// beyond returned size if it was not empty. Client timeout applies to each request.
+func (c *Client) Download(ctx context.Context, url string, dst io.WriterAt, opts DownloadOptions) (int64, error) {
+	n, err := c.download(ctx, url, dst, opts)
+	if err != nil && c.classifyErrors {
+		return n, classifyError(err)
+	}
+	return n, err
+}
+
+// downloadState tracks progress of single Download call.
+type downloadState struct {
+	offset       int64
+	total        int64 // -1 if unknown
+	acceptRanges bool
+	validator    string // ETag or Last-Modified for If-Range
+	hash         hash.Hash
+}
+
+// restart discards downloaded content.
+func (s *downloadState) restart() {
+	s.offset = 0
+	s.hash.Reset()
+}
+
+// download performs requests until content is fully written.
+func (c *Client) download(ctx context.Context, url string, dst io.WriterAt, opts DownloadOptions) (int64, error) {
+	base, err := stdhttp.NewRequestWithContext(ctx, stdhttp.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	doOpts := append(append([]DoOption(nil), opts.Options...), NoCache())
+	retries := c.resolveDoOptions(doOpts).retries
+	s := &downloadState{total: -1, hash: sha256.New()}
+
+	failures := 0
+	for {
+		start := s.offset
+		readErr, err := c.downloadOnce(ctx, base, dst, s, opts.Progress, doOpts)
+		if err != nil {
+			return s.offset, err
+		}
+		if readErr == nil {
+			break
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return s.offset, ctxErr
+		}
+		delay, retry := c.classifyRetry(nil, readErr)
+		if !retry {
+			return s.offset, readErr
+		}
+		if s.offset > start && s.acceptRanges {
+			failures = 0 // progress was made and is kept
+		}
+		failures++
+		if failures > retries {
+			return s.offset, readErr
+		}
+		if c.retryBudget != nil && !c.retryBudget.Allow() {
+			return s.offset, fmt.Errorf("%w: %w", ErrRetryBudgetExhausted, readErr)
+		}
+		if !s.acceptRanges {
+			s.restart()
+		}
+		c.log.Warn("http download interrupted", slog.String("url", c.redactURL(base.URL)), slog.Int64("offset", s.offset), slog.Int64("total", s.total), slog.Int("attempt", failures), slog.Any("error", readErr))
+		if err := sleepCtx(ctx, c.downloadBackoff(failures, delay)); err != nil {
+			return s.offset, err
+		}
+	}
+
+	if s.total >= 0 && s.offset != s.total {
+		return s.offset, fmt.Errorf("%w: got %d bytes, expected %d", ErrSizeMismatch, s.offset, s.total)
+	}
+	if opts.SHA256 != "" {
+		if sum := hex.EncodeToString(s.hash.Sum(nil)); !strings.EqualFold(sum, opts.SHA256) {
+			return s.offset, fmt.Errorf("%w: got %s, expected %s", ErrChecksumMismatch, sum, strings.ToLower(opts.SHA256))
+		}
+	}
+	return s.offset, nil
+}
+
+// downloadOnce sends single request from current offset and copies its body to dst.
+// It returns readErr if body was interrupted and download may continue,
+// and err if download must stop.
+func (c *Client) downloadOnce(ctx context.Context, base *stdhttp.Request, dst io.WriterAt, s *downloadState, progress func(done, total int64), opts []DoOption) (readErr, err error) {
+	req := base.Clone(ctx)
+	// Offsets must refer to raw content, so transparent decompression is disabled
+	req.Header.Set("Accept-Encoding", "identity")
+	if s.offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(s.offset, 10)+"-")
+		if s.validator != "" {
+			req.Header.Set("If-Range", s.validator)
+		}
+	}
+
+	resp, err := c.Do(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer drainAndClose(resp.Body)
+
+	switch resp.StatusCode {
+	case stdhttp.StatusOK:
+		if s.offset > 0 {
+			c.log.Info("http download restarted, range ignored by server", slog.String("url", c.redactURL(req.URL)), slog.Int64("offset", s.offset))
+			s.restart()
+		}
+		s.total = resp.ContentLength
+		s.acceptRanges = strings.EqualFold(resp.Header.Get("Accept-Ranges"), "bytes")
+		s.validator = rangeValidator(resp.Header)
+	case stdhttp.StatusPartialContent:
+		first, total, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || first != s.offset {
+			return nil, fmt.Errorf("http: unexpected Content-Range %q for offset %d", resp.Header.Get("Content-Range"), s.offset)
+		}
+		if total >= 0 && s.total >= 0 && total != s.total {
+			return nil, fmt.Errorf("%w: content size changed from %d to %d", ErrSizeMismatch, s.total, total)
+		}
+		if total >= 0 {
+			s.total = total
+		}
+	default:
+		return nil, &HTTPError{Method: req.Method, URL: c.redactURL(req.URL), StatusCode: resp.StatusCode}
+	}
+
+	buf := make([]byte, downloadBufferSize)
+	for {
+		n, rerr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := dst.WriteAt(buf[:n], s.offset); err != nil {
+				return nil, fmt.Errorf("write download: %w", err)
+			}
+			s.hash.Write(buf[:n])
+			s.offset += int64(n)
+			if progress != nil {
+				progress(s.offset, s.total)
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return c.attemptTimeoutError(ctx, rerr), nil
+		}
+	}
+	if s.total >= 0 && s.offset < s.total {
+		return io.ErrUnexpectedEOF, nil
+	}
+	return nil, nil
+}
+
+// downloadBackoff returns wait before next request after failure number n.
+func (c *Client) downloadBackoff(n int, delay time.Duration) time.Duration {
+	wait := delay
+	if wait <= 0 {
+		wait = c.baseBackoff * time.Duration(1<<uint(n-1))
+	}
+	if c.maxBackoff > 0 && wait > c.maxBackoff {
+		wait = c.maxBackoff
+	}
+	return wait
+}
+
+// rangeValidator returns strong ETag or Last-Modified usable in If-Range.
+func rangeValidator(h stdhttp.Header) string {
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return h.Get("Last-Modified")
+}
+
+// parseContentRange parses "bytes first-last/total" header value.
+// Total is -1 when server reports it as "*".
+func parseContentRange(v string) (first, total int64, ok bool) {
+	spec, found := strings.CutPrefix(strings.TrimSpace(v), "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rng, size, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+	firstStr, lastStr, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, false
+	}
+	first, err := strconv.ParseInt(firstStr, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	last, err := strconv.ParseInt(lastStr, 10, 64)
+	if err != nil || last < first {
+		return 0, 0, false
+	}
+	if size == "*" {
+		return first, -1, true
+	}
+	total, err = strconv.ParseInt(size, 10, 64)
+	if err != nil || total <= last {
+		return 0, 0, false
+	}
+	return first, total, true
+}
@@ -0,0 +1,230 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memWriterAt is in-memory io.WriterAt.
+type memWriterAt struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (m *memWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if end := int(off) + len(p); end > len(m.buf) {
+		m.buf = append(m.buf, make([]byte, end-len(m.buf))...)
+	}
+	copy(m.buf[off:], p)
+	return len(p), nil
+}
+
+// downloadServer serves content and drops connection after dropAfter bytes
+// of first drops responses. Range requests are honored only if ranges is set.
+type downloadServer struct {
+	content   []byte
+	ranges    bool
+	advertise bool // send Accept-Ranges even if ranges are ignored
+	drops     int32
+	dropAfter int
+	calls     atomic.Int32
+	mu        sync.Mutex
+	rangeHdrs []string
+}
+
+func (s *downloadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	call := s.calls.Add(1)
+	s.mu.Lock()
+	s.rangeHdrs = append(s.rangeHdrs, r.Header.Get("Range"))
+	s.mu.Unlock()
+
+	if s.ranges || s.advertise {
+		w.Header().Set("Accept-Ranges", "bytes")
+	}
+	w.Header().Set("ETag", `"v1"`)
+	body := s.content
+	status := http.StatusOK
+	if rng := r.Header.Get("Range"); rng != "" && s.ranges {
+		first, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+		if err != nil || first >= len(s.content) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		body = s.content[first:]
+		status = http.StatusPartialContent
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", first, len(s.content)-1, len(s.content)))
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+
+	if call <= s.drops && len(body) > s.dropAfter {
+		_, _ = w.Write(body[:s.dropAfter])
+		w.(http.Flusher).Flush()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+func (s *downloadServer) rangeHeaders() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.rangeHdrs...)
+}
+
+func newDownloadClient(retries int) *httpclient.Client {
+	return httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(retries, 0),
+		httpclient.WithBaseBackoff(time.Millisecond),
+	)
+}
+
+func testContent(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i % 251)
+	}
+	return b
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestClient_Download_Resume(t *testing.T) {
+	content := testContent(200 << 10)
+	ds := &downloadServer{content: content, ranges: true, drops: 2, dropAfter: 50 << 10}
+	srv := httptest.NewServer(ds)
+	defer srv.Close()
+
+	var lastDone, lastTotal int64
+	dst := &memWriterAt{}
+	n, err := newDownloadClient(2).Download(context.Background(), srv.URL, dst, httpclient.DownloadOptions{
+		SHA256:   sha256Hex(content),
+		Progress: func(done, total int64) { lastDone, lastTotal = done, total },
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, len(content), n)
+	require.True(t, bytes.Equal(content, dst.buf))
+	require.EqualValues(t, len(content), lastDone)
+	require.EqualValues(t, len(content), lastTotal)
+
+	hdrs := ds.rangeHeaders()
+	require.Len(t, hdrs, 3)
+	require.Equal(t, "", hdrs[0])
+	require.Equal(t, "bytes=51200-", hdrs[1])
+	require.Equal(t, "bytes=102400-", hdrs[2])
+}
+
+func TestClient_Download_RangeIgnoredRestarts(t *testing.T) {
+	content := testContent(100 << 10)
+	// Server advertises ranges, but answers Range requests with full 200 response
+	ds := &downloadServer{content: content, advertise: true, drops: 1, dropAfter: 30 << 10}
+	srv := httptest.NewServer(ds)
+	defer srv.Close()
+
+	var progress []int64
+	dst := &memWriterAt{}
+	n, err := newDownloadClient(1).Download(context.Background(), srv.URL, dst, httpclient.DownloadOptions{
+		SHA256:   sha256Hex(content),
+		Progress: func(done, total int64) { progress = append(progress, done) },
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, len(content), n)
+	require.True(t, bytes.Equal(content, dst.buf))
+	require.Equal(t, []string{"", "bytes=30720-"}, ds.rangeHeaders())
+	require.Contains(t, progress, int64(30<<10))
+	require.EqualValues(t, len(content), progress[len(progress)-1])
+}
+
+func TestClient_Download_NoAcceptRangesStartsOver(t *testing.T) {
+	content := testContent(100 << 10)
+	ds := &downloadServer{content: content, drops: 1, dropAfter: 30 << 10}
+	srv := httptest.NewServer(ds)
+	defer srv.Close()
+
+	dst := &memWriterAt{}
+	n, err := newDownloadClient(1).Download(context.Background(), srv.URL, dst, httpclient.DownloadOptions{})
+	require.NoError(t, err)
+	require.EqualValues(t, len(content), n)
+	require.True(t, bytes.Equal(content, dst.buf))
+	require.Equal(t, []string{"", ""}, ds.rangeHeaders())
+}
+
+func TestClient_Download_Errors(t *testing.T) {
+	content := testContent(10 << 10)
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		ds := &downloadServer{content: content, ranges: true, drops: 10, dropAfter: 0}
+		srv := httptest.NewServer(ds)
+		defer srv.Close()
+
+		_, err := newDownloadClient(2).Download(context.Background(), srv.URL, &memWriterAt{}, httpclient.DownloadOptions{})
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		require.EqualValues(t, 3, ds.calls.Load())
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		srv := httptest.NewServer(&downloadServer{content: content})
+		defer srv.Close()
+
+		n, err := newDownloadClient(0).Download(context.Background(), srv.URL, &memWriterAt{}, httpclient.DownloadOptions{
+			SHA256: sha256Hex([]byte("other")),
+		})
+		require.ErrorIs(t, err, httpclient.ErrChecksumMismatch)
+		require.EqualValues(t, len(content), n)
+	})
+
+	t.Run("size changed between ranges", func(t *testing.T) {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Accept-Ranges", "bytes")
+			if calls.Add(1) == 1 {
+				w.Header().Set("Content-Length", "100")
+				_, _ = w.Write(content[:10])
+				return
+			}
+			w.Header().Set("Content-Range", "bytes 10-199/200")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(content[10:200])
+		}))
+		defer srv.Close()
+
+		_, err := newDownloadClient(1).Download(context.Background(), srv.URL, &memWriterAt{}, httpclient.DownloadOptions{})
+		require.ErrorIs(t, err, httpclient.ErrSizeMismatch)
+	})
+
+	t.Run("status error", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		defer srv.Close()
+
+		_, err := newDownloadClient(0).Download(context.Background(), srv.URL, &memWriterAt{}, httpclient.DownloadOptions{})
+		var httpErr *httpclient.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	})
+}
@@ -0,0 +1,195 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"time"
+
+	"sttbot/internal/shared"
+)
+
+// ErrResponseTooLarge indicates response body exceeds configured limit.
+var ErrResponseTooLarge = errors.New("http: response body too large")
+
+// ErrMaxRetryDurationExceeded indicates retries stopped because MaxRetryDuration was reached.
+var ErrMaxRetryDurationExceeded = errors.New("http: max retry duration exceeded")
+
+// ErrRetryBudgetExceeded is former name of ErrMaxRetryDurationExceeded.
+//
+// Deprecated: Use ErrMaxRetryDurationExceeded. Shared retry budget is reported with ErrRetryBudgetExhausted.
+var ErrRetryBudgetExceeded = ErrMaxRetryDurationExceeded
+
+// maxErrorBodySnippet limits size of response body kept in HTTPError.
+const maxErrorBodySnippet = 512
+
+// HTTPError describes non-2xx response returned by server.
+type HTTPError struct {
+	Method     string
+	URL        string // redacted URL
+	StatusCode int
+	Body       string // truncated body snippet
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("%s %s: unexpected status %d", e.Method, e.URL, e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s: unexpected status %d: %s", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// defaultAttemptHistory is default number of attempts kept in RequestError.History.
+const defaultAttemptHistory = 10
+
+// RequestError is returned by Do when request fails after at least one attempt was sent:
+// retries exhausted on status or network error, retry duration or budget exceeded,
+// non-retryable error or canceled context. Unwrap returns underlying error,
+// so errors.Is and errors.As see it as before.
+type RequestError struct {
+	Method        string
+	URL           string // redacted URL
+	Attempts      int    // number of attempts sent
+	TotalDuration time.Duration
+	LastStatus    int // status of last attempt, 0 if it failed without response
+	// History lists last failed attempts, at most WithAttemptHistory entries.
+	History []AttemptRecord
+	Err     error
+}
+
+// AttemptRecord describes single failed attempt in RequestError.History.
+type AttemptRecord struct {
+	Attempt int
+	At      time.Time     // attempt start
+	Status  int           // response status, 0 if attempt failed without response
+	Error   string        // error summary, empty for retried status
+	Wait    time.Duration // wait applied before next attempt
+}
+
+func (e *RequestError) Error() string {
+	noun := "attempts"
+	if e.Attempts == 1 {
+		noun = "attempt"
+	}
+	return fmt.Sprintf("%s %s failed after %d %s in %s: %v", e.Method, e.URL, e.Attempts, noun, e.TotalDuration.Round(time.Millisecond), e.Err)
+}
+
+func (e *RequestError) Unwrap() error { return e.Err }
+
+// LogValue reports error fields as slog group.
+func (e *RequestError) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("method", e.Method),
+		slog.String("url", e.URL),
+		slog.Int("attempts", e.Attempts),
+		slog.Duration("total_duration", e.TotalDuration),
+		slog.Int("last_status", e.LastStatus),
+		slog.String("error", e.Err.Error()),
+	}
+	if len(e.History) > 0 {
+		attrs = append(attrs, slog.Any("history", e.History))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// WithAttemptHistory sets number of last failed attempts kept in RequestError.History
+// (default 10, 0 keeps none).
+func WithAttemptHistory(n int) Option {
+	return func(c *Client) { c.attemptHistory = max(n, 0) }
+}
+
+// attemptLog collects attempts of single Do call for RequestError.
+type attemptLog struct {
+	limit      int
+	start      time.Time
+	attempts   int
+	lastStatus int
+	records    []AttemptRecord
+}
+
+// record adds failed attempt, dropping oldest one when history is full.
+func (l *attemptLog) record(attempt int, at time.Time, status int, err error) {
+	l.lastStatus = status
+	if l.limit == 0 {
+		return
+	}
+	rec := AttemptRecord{Attempt: attempt, At: at, Status: status}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	if len(l.records) == l.limit {
+		copy(l.records, l.records[1:])
+		l.records = l.records[:len(l.records)-1]
+	}
+	l.records = append(l.records, rec)
+}
+
+// setWait stores wait applied after last recorded attempt.
+func (l *attemptLog) setWait(attempt int, wait time.Duration) {
+	if n := len(l.records); n > 0 && l.records[n-1].Attempt == attempt {
+		l.records[n-1].Wait = wait
+	}
+}
+
+// requestError wraps err into RequestError if any attempt was sent.
+func (l *attemptLog) requestError(method, u string, err error) error {
+	if err == nil || l.attempts == 0 {
+		return err
+	}
+	return &RequestError{
+		Method:        method,
+		URL:           u,
+		Attempts:      l.attempts,
+		TotalDuration: time.Since(l.start),
+		LastStatus:    l.lastStatus,
+		History:       l.records,
+		Err:           err,
+	}
+}
+
+// WithErrorClassification marks terminal errors of Do with shared error kinds.
+// Timeouts become KindTimeout, transport failures, exceeded retry duration or budget and
+// 5xx statuses become KindDependencyFailure, 4xx statuses become KindValidation.
+// ValidationError keeps kind of validator error, unclassified ones become KindDependencyFailure.
+// Canceled context is left as-is so shared.KindOf reports KindCanceled.
+func WithErrorClassification(v bool) Option {
+	return func(c *Client) { c.classifyErrors = v }
+}
+
+// classifyError marks error with shared error kind.
+func classifyError(err error) error {
+	if err == nil || shared.IsCanceled(err) {
+		return err
+	}
+	if shared.IsTimeout(err) {
+		return shared.MarkKind(err, shared.KindTimeout)
+	}
+	if errors.Is(err, ErrMaxRetryDurationExceeded) || errors.Is(err, ErrRetryBudgetExhausted) {
+		return shared.MarkKind(err, shared.KindDependencyFailure)
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		if shared.KindOf(err) == shared.KindUnknown {
+			return shared.MarkKind(err, shared.KindDependencyFailure)
+		}
+		return err
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch {
+		case httpErr.StatusCode >= 500:
+			return shared.MarkKind(err, shared.KindDependencyFailure)
+		case httpErr.StatusCode >= 400:
+			return shared.MarkKind(err, shared.KindValidation)
+		}
+		return err
+	}
+	var urlErr *url.Error
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &urlErr) || errors.As(err, &opErr) || errors.As(err, &dnsErr) || errors.Is(err, net.ErrClosed) {
+		return shared.MarkKind(err, shared.KindDependencyFailure)
+	}
+	return err
+}
@@ -0,0 +1,334 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+	"sttbot/internal/shared"
+
+	"github.com/stretchr/testify/require"
+)
+
+func statusServer(t *testing.T, status int) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClient_Do_ClassifyContextDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(httpclient.WithErrorClassification(true))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(ctx, req)
+	require.Equal(t, shared.KindTimeout, shared.KindOf(err))
+}
+
+func TestClient_Do_ClassifyNetTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	c := newTestClient(httpclient.WithErrorClassification(true), httpclient.WithTimeout(20*time.Millisecond))
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	require.Equal(t, shared.KindTimeout, shared.KindOf(err))
+}
+
+func TestClient_Do_ClassifyCanceled(t *testing.T) {
+	srv := statusServer(t, http.StatusOK)
+
+	c := newTestClient(httpclient.WithErrorClassification(true))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(ctx, req)
+	require.Equal(t, shared.KindCanceled, shared.KindOf(err))
+}
+
+func TestClient_Do_ClassifyConnectionRefused(t *testing.T) {
+	srv := statusServer(t, http.StatusOK)
+	addr := srv.URL
+	srv.Close()
+
+	c := newTestClient(httpclient.WithErrorClassification(true))
+	req, err := http.NewRequest(http.MethodGet, addr, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	require.Equal(t, shared.KindDependencyFailure, shared.KindOf(err))
+}
+
+func TestClient_Do_ClassifyDNSFailure(t *testing.T) {
+	c := newTestClient(httpclient.WithErrorClassification(true), httpclient.WithTransport(rtFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, &net.DNSError{Err: "no such host", Name: r.URL.Host, IsNotFound: true}
+	})))
+	req, err := http.NewRequest(http.MethodGet, "http://example.invalid", nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	require.Equal(t, shared.KindDependencyFailure, shared.KindOf(err))
+	var dnsErr *net.DNSError
+	require.True(t, errors.As(err, &dnsErr))
+}
+
+func TestClient_Do_ClassifyMaxRetryDurationExceeded(t *testing.T) {
+	srv := statusServer(t, http.StatusInternalServerError)
+
+	c := newTestClient(
+		httpclient.WithErrorClassification(true),
+		httpclient.WithRetries(5, 50*time.Millisecond),
+		httpclient.WithMaxRetryDuration(60*time.Millisecond),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	require.ErrorIs(t, err, httpclient.ErrMaxRetryDurationExceeded)
+	require.Equal(t, shared.KindDependencyFailure, shared.KindOf(err))
+}
+
+func TestClient_Do_ClassifyExhaustedStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		kind   shared.Kind
+	}{
+		{"5xx", http.StatusBadGateway, shared.KindDependencyFailure},
+		{"4xx", http.StatusRequestTimeout, shared.KindValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := statusServer(t, tt.status)
+
+			c := newTestClient(httpclient.WithErrorClassification(true), httpclient.WithRetries(1, time.Millisecond))
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			require.NoError(t, err)
+
+			_, err = c.Do(context.Background(), req)
+			require.Equal(t, tt.kind, shared.KindOf(err))
+			var httpErr *httpclient.HTTPError
+			require.ErrorAs(t, err, &httpErr)
+			require.Equal(t, tt.status, httpErr.StatusCode)
+		})
+	}
+}
+
+func TestClient_Do_ClassifyLeavesResponsesUntouched(t *testing.T) {
+	srv := statusServer(t, http.StatusNotFound)
+
+	c := newTestClient(httpclient.WithErrorClassification(true))
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestClient_Do_NoClassificationByDefault(t *testing.T) {
+	srv := statusServer(t, http.StatusBadGateway)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	require.Equal(t, shared.KindUnknown, shared.KindOf(err))
+}
+
+func TestClient_Do_RequestErrorStatusExhausted(t *testing.T) {
+	srv := statusServer(t, http.StatusServiceUnavailable)
+
+	c := newTestClient(httpclient.WithErrorClassification(true), httpclient.WithRetries(2, time.Millisecond))
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/path?token=secret", nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	var reqErr *httpclient.RequestError
+	require.ErrorAs(t, err, &reqErr)
+	require.Equal(t, http.MethodGet, reqErr.Method)
+	require.Equal(t, srv.URL+"/path?token=secret", reqErr.URL)
+	require.Equal(t, 3, reqErr.Attempts)
+	require.Equal(t, http.StatusServiceUnavailable, reqErr.LastStatus)
+	require.Positive(t, reqErr.TotalDuration)
+	require.Len(t, reqErr.History, 3)
+	for i, rec := range reqErr.History {
+		require.Equal(t, i+1, rec.Attempt)
+		require.Equal(t, http.StatusServiceUnavailable, rec.Status)
+		require.Empty(t, rec.Error)
+		require.False(t, rec.At.IsZero())
+	}
+	require.Positive(t, reqErr.History[0].Wait)
+	require.Positive(t, reqErr.History[1].Wait)
+	require.Zero(t, reqErr.History[2].Wait, "no wait after last attempt")
+
+	// Underlying error is still reachable
+	var httpErr *httpclient.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	require.Equal(t, shared.KindDependencyFailure, shared.KindOf(err))
+	require.ErrorContains(t, err, "failed after 3 attempts")
+	require.ErrorContains(t, err, "unexpected status 503")
+}
+
+func TestClient_Do_RequestErrorNetworkExhausted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	u := srv.URL
+	srv.Close()
+
+	c := newTestClient(httpclient.WithErrorClassification(true), httpclient.WithRetries(1, time.Millisecond))
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	var reqErr *httpclient.RequestError
+	require.ErrorAs(t, err, &reqErr)
+	require.Equal(t, 2, reqErr.Attempts)
+	require.Zero(t, reqErr.LastStatus)
+	require.Len(t, reqErr.History, 2)
+	require.Zero(t, reqErr.History[1].Status)
+	require.Contains(t, reqErr.History[1].Error, "connection refused")
+	require.Equal(t, shared.KindDependencyFailure, shared.KindOf(err))
+}
+
+func TestClient_Do_RequestErrorRetryBudget(t *testing.T) {
+	srv := statusServer(t, http.StatusBadGateway)
+
+	c := newTestClient(
+		httpclient.WithErrorClassification(true),
+		httpclient.WithRetries(3, time.Millisecond),
+		httpclient.WithRetryBudget(httpclient.NewRetryBudget(httpclient.RetryBudgetConfig{MaxRetries: 1})),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	require.ErrorIs(t, err, httpclient.ErrRetryBudgetExhausted)
+	var reqErr *httpclient.RequestError
+	require.ErrorAs(t, err, &reqErr)
+	require.Equal(t, 2, reqErr.Attempts)
+	require.Equal(t, http.StatusBadGateway, reqErr.LastStatus)
+	require.Zero(t, reqErr.History[1].Wait, "retry denied by budget was not waited")
+}
+
+func TestClient_Do_RequestErrorHistoryLimit(t *testing.T) {
+	srv := statusServer(t, http.StatusInternalServerError)
+
+	c := newTestClient(httpclient.WithErrorClassification(true), httpclient.WithRetries(4, time.Millisecond), httpclient.WithAttemptHistory(2))
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	var reqErr *httpclient.RequestError
+	require.ErrorAs(t, err, &reqErr)
+	require.Equal(t, 5, reqErr.Attempts)
+	require.Len(t, reqErr.History, 2)
+	require.Equal(t, 4, reqErr.History[0].Attempt)
+	require.Equal(t, 5, reqErr.History[1].Attempt)
+
+	c = newTestClient(httpclient.WithErrorClassification(true), httpclient.WithRetries(1, time.Millisecond), httpclient.WithAttemptHistory(0))
+	_, err = c.Do(context.Background(), req)
+	require.ErrorAs(t, err, &reqErr)
+	require.Equal(t, 2, reqErr.Attempts)
+	require.Equal(t, http.StatusInternalServerError, reqErr.LastStatus)
+	require.Empty(t, reqErr.History)
+}
+
+func TestClient_Do_RequestErrorContextCanceled(t *testing.T) {
+	srv := statusServer(t, http.StatusServiceUnavailable)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := newTestClient(
+		httpclient.WithErrorClassification(true),
+		httpclient.WithRetries(3, time.Second),
+		httpclient.WithResponseHook(func(*http.Request, *http.Response, error, time.Duration, int) { cancel() }),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(ctx, req)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, shared.KindCanceled, shared.KindOf(err))
+	var reqErr *httpclient.RequestError
+	require.ErrorAs(t, err, &reqErr)
+	require.Equal(t, 1, reqErr.Attempts)
+}
+
+func TestClient_Do_NoRequestErrorWithoutAttempts(t *testing.T) {
+	c := newTestClient(httpclient.WithErrorClassification(true), httpclient.WithMaxReplayBodySize(4))
+	req, err := http.NewRequest(http.MethodPost, "http://example.invalid", strings.NewReader("too large body"))
+	require.NoError(t, err)
+	req.GetBody = nil
+
+	_, err = c.Do(context.Background(), req)
+	require.ErrorIs(t, err, httpclient.ErrReplayBodyTooLarge)
+	var reqErr *httpclient.RequestError
+	require.False(t, errors.As(err, &reqErr))
+}
+
+func TestRequestError_LogValue(t *testing.T) {
+	err := &httpclient.RequestError{
+		Method:        http.MethodGet,
+		URL:           "https://api.example.com/items",
+		Attempts:      2,
+		TotalDuration: 1500 * time.Millisecond,
+		LastStatus:    http.StatusServiceUnavailable,
+		History: []httpclient.AttemptRecord{
+			{Attempt: 1, Status: http.StatusServiceUnavailable, Wait: time.Second},
+			{Attempt: 2, Status: http.StatusServiceUnavailable},
+		},
+		Err: errors.New("boom"),
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("request failed", slog.Any("error", err))
+	var entry struct {
+		Error struct {
+			Method     string
+			URL        string
+			Attempts   int
+			LastStatus int `json:"last_status"`
+			Error      string
+			History    []httpclient.AttemptRecord
+		}
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, http.MethodGet, entry.Error.Method)
+	require.Equal(t, 2, entry.Error.Attempts)
+	require.Equal(t, http.StatusServiceUnavailable, entry.Error.LastStatus)
+	require.Equal(t, "boom", entry.Error.Error)
+	require.Len(t, entry.Error.History, 2)
+	require.Equal(t, time.Second, entry.Error.History[0].Wait)
+	require.Equal(t, "GET https://api.example.com/items failed after 2 attempts in 1.5s: boom", err.Error())
+}
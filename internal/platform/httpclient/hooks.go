@@ -0,0 +1,72 @@
+package httpclient
+
+import (
+	"context"
+	stdhttp "net/http"
+	"time"
+)
+
+// RequestHook is called before each attempt is sent.
+type RequestHook func(*stdhttp.Request)
+
+// ResponseHook is called after each attempt with its result and duration.
+type ResponseHook func(req *stdhttp.Request, resp *stdhttp.Response, err error, dur time.Duration, attempt int)
+
+// AttemptInfo describes current attempt and is available to hooks via AttemptInfoFromRequest.
+type AttemptInfo struct {
+	Attempt     int
+	RedactedURL string
+	// RateLimitWait is time spent waiting for client-side rate limiter before attempt.
+	RateLimitWait time.Duration
+}
+
+// attemptInfoKey is context key for AttemptInfo.
+type attemptInfoKey struct{}
+
+// AttemptInfoFromRequest returns info about attempt the request belongs to.
+func AttemptInfoFromRequest(r *stdhttp.Request) (AttemptInfo, bool) {
+	info, ok := r.Context().Value(attemptInfoKey{}).(AttemptInfo)
+	return info, ok
+}
+
+// withAttemptInfo stores AttemptInfo in context.
+func withAttemptInfo(ctx context.Context, info AttemptInfo) context.Context {
+	return context.WithValue(ctx, attemptInfoKey{}, info)
+}
+
+// WithRequestHook adds hook called before every attempt.
+// Hooks run in registration order and may modify headers.
+// Request body is set after hooks run, so hooks cannot break body replay.
+func WithRequestHook(h RequestHook) Option {
+	return func(c *Client) {
+		if h != nil {
+			c.requestHooks = append(c.requestHooks, h)
+		}
+	}
+}
+
+// WithResponseHook adds hook called after every attempt, including retried ones.
+// Hooks run in registration order and must not read or close response body.
+func WithResponseHook(h ResponseHook) Option {
+	return func(c *Client) {
+		if h != nil {
+			c.responseHooks = append(c.responseHooks, h)
+		}
+	}
+}
+
+// runRequestHooks calls request hooks and restores body replay function.
+func (c *Client) runRequestHooks(r *stdhttp.Request) {
+	getBody := r.GetBody
+	for _, h := range c.requestHooks {
+		h(r)
+	}
+	r.GetBody = getBody
+}
+
+// runResponseHooks calls response hooks.
+func (c *Client) runResponseHooks(r *stdhttp.Request, resp *stdhttp.Response, err error, dur time.Duration, attempt int) {
+	for _, h := range c.responseHooks {
+		h(r, resp, err, dur, attempt)
+	}
+}
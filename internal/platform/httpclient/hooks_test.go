@@ -0,0 +1,124 @@
+package httpclient_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Do_Hooks(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		require.Equal(t, "trace-1", r.Header.Get("X-Trace-Id"))
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var mu sync.Mutex
+	var order []string
+	var statuses []int
+	var hookAttempts []int
+	var urls []string
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+		httpclient.WithRequestHook(func(r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, "req1")
+			r.Header.Set("X-Trace-Id", "trace-1")
+		}),
+		httpclient.WithRequestHook(func(r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, "req2")
+		}),
+		httpclient.WithResponseHook(func(r *http.Request, resp *http.Response, err error, dur time.Duration, attempt int) {
+			mu.Lock()
+			defer mu.Unlock()
+			require.NoError(t, err)
+			statuses = append(statuses, resp.StatusCode)
+			hookAttempts = append(hookAttempts, attempt)
+			info, ok := httpclient.AttemptInfoFromRequest(r)
+			require.True(t, ok)
+			require.Equal(t, attempt, info.Attempt)
+			urls = append(urls, info.RedactedURL)
+		}),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, []string{"req1", "req2", "req1", "req2"}, order)
+	require.Equal(t, []int{http.StatusServiceUnavailable, http.StatusOK}, statuses)
+	require.Equal(t, []int{1, 2}, hookAttempts)
+	require.Equal(t, []string{srv.URL, srv.URL}, urls)
+}
+
+func TestClient_Do_RequestHookCannotBreakReplay(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		b, _ := io.ReadAll(r.Body)
+		require.Equal(t, "payload", string(b))
+		if attempts == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+		httpclient.WithRequestHook(func(r *http.Request) {
+			r.Body = io.NopCloser(strings.NewReader("broken"))
+			r.GetBody = nil
+		}),
+	)
+	req, err := http.NewRequest(http.MethodPut, srv.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 2, attempts)
+}
+
+func TestClient_Do_ResponseHookOnError(t *testing.T) {
+	var hookErr error
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithTransport(rtFunc(func(r *http.Request) (*http.Response, error) {
+			return nil, io.ErrClosedPipe
+		})),
+		httpclient.WithResponseHook(func(r *http.Request, resp *http.Response, err error, dur time.Duration, attempt int) {
+			hookErr = err
+		}),
+	)
+	req, err := http.NewRequest(http.MethodGet, "http://example.invalid", nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	require.Error(t, err)
+	require.ErrorIs(t, hookErr, io.ErrClosedPipe)
+}
@@ -0,0 +1,87 @@
+package httpclient
+
+import (
+	stdhttp "net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// defaultIdempotencyHeader is the header that unlocks POST retries.
+const defaultIdempotencyHeader = "Idempotency-Key"
+
+// idempotencyKeyPrefixLen is the number of key characters written to logs.
+const idempotencyKeyPrefixLen = 8
+
+// WithAutoIdempotencyKey generates an idempotency key for POST and PATCH requests
+// that do not carry headerName (Idempotency-Key when empty). The key is created by
+// gen (random UUIDv4 when nil) once per Do call, set on every attempt and unlocks
+// retries for the request like a caller-provided Idempotency-Key. Methods that are
+// already retried (see WithRetryMethods) are left untouched unless listed in
+// WithAutoIdempotencyMethods. Retry log lines include the key prefix for correlation
+// with server logs.
+func WithAutoIdempotencyKey(headerName string, gen func() string) Option {
+	return func(c *Client) {
+		if headerName == "" {
+			headerName = defaultIdempotencyHeader
+		}
+		if gen == nil {
+			gen = newIdempotencyKey
+		}
+		c.autoIdemHeader = stdhttp.CanonicalHeaderKey(headerName)
+		c.autoIdemGen = gen
+	}
+}
+
+// WithAutoIdempotencyMethods overrides methods that get a key from WithAutoIdempotencyKey
+// (POST and PATCH by default). Listed methods get a key even if they are retry methods.
+func WithAutoIdempotencyMethods(methods ...string) Option {
+	return func(c *Client) {
+		c.autoIdemMethods = make(map[string]struct{}, len(methods))
+		for _, m := range methods {
+			c.autoIdemMethods[strings.ToUpper(m)] = struct{}{}
+		}
+	}
+}
+
+// autoIdempotencyKey returns a generated key for req, or an empty string
+// when the request already has one or its method is not covered.
+func (c *Client) autoIdempotencyKey(req *stdhttp.Request) string {
+	if c.autoIdemGen == nil || req.Header.Get(c.autoIdemHeader) != "" {
+		return ""
+	}
+	if c.autoIdemMethods != nil {
+		if _, ok := c.autoIdemMethods[req.Method]; !ok {
+			return ""
+		}
+	} else {
+		if req.Method != stdhttp.MethodPost && req.Method != stdhttp.MethodPatch {
+			return ""
+		}
+		if _, ok := c.retryMethods[req.Method]; ok {
+			return ""
+		}
+	}
+	return c.autoIdemGen()
+}
+
+// idempotencyHeader returns the header name used for idempotency keys.
+func (c *Client) idempotencyHeader() string {
+	if c.autoIdemHeader != "" {
+		return c.autoIdemHeader
+	}
+	return defaultIdempotencyHeader
+}
+
+// newIdempotencyKey returns a random UUIDv4.
+func newIdempotencyKey() string {
+	return uuid.NewString()
+}
+
+// idempotencyKeyPrefix shortens key for logs.
+func idempotencyKeyPrefix(key string) string {
+	if len(key) <= idempotencyKeyPrefixLen {
+		return key
+	}
+	return key[:idempotencyKeyPrefixLen] + "..."
+}
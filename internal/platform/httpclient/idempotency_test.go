@@ -0,0 +1,197 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/platform/httpclient"
+)
+
+// keyRecorder is a test server recording header values and failing the first attempts.
+type keyRecorder struct {
+	mu       sync.Mutex
+	header   string
+	failures int
+	keys     []string
+}
+
+func (k *keyRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys = append(k.keys, r.Header.Get(k.header))
+	if len(k.keys) <= k.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestAutoIdempotencyKey_SameKeyAcrossAttempts(t *testing.T) {
+	rec := &keyRecorder{header: "Idempotency-Key", failures: 2}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		httpclient.WithRetries(2, time.Millisecond),
+		httpclient.WithAutoIdempotencyKey("", nil),
+	)
+
+	for _, method := range []string{http.MethodPost, http.MethodPatch} {
+		rec.keys = nil
+		logs.Reset()
+		req, err := http.NewRequest(method, srv.URL, strings.NewReader("payload"))
+		require.NoError(t, err)
+
+		resp, err := c.Do(context.Background(), req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		require.Len(t, rec.keys, 3, method)
+		key := rec.keys[0]
+		assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), key)
+		assert.Equal(t, []string{key, key, key}, rec.keys, method)
+		assert.Empty(t, req.Header.Get("Idempotency-Key"), "caller request must not be modified")
+
+		// Only the key prefix is logged
+		assert.Contains(t, logs.String(), "idempotency_key_prefix="+key[:8]+"...")
+		assert.NotContains(t, logs.String(), key)
+	}
+
+	// Each call gets its own key
+	rec.keys = nil
+	rec.failures = 0
+	for range 2 {
+		req, err := http.NewRequest(http.MethodPost, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := c.Do(context.Background(), req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	require.Len(t, rec.keys, 2)
+	assert.NotEqual(t, rec.keys[0], rec.keys[1])
+}
+
+func TestAutoIdempotencyKey_CustomHeaderAndGenerator(t *testing.T) {
+	rec := &keyRecorder{header: "X-Request-Id", failures: 1}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	var n int
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+		httpclient.WithAutoIdempotencyKey("x-request-id", func() string {
+			n++
+			return "generated-key"
+		}),
+	)
+	req, err := http.NewRequest(http.MethodPost, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, []string{"generated-key", "generated-key"}, rec.keys)
+	assert.Equal(t, 1, n, "key must be generated once per call")
+}
+
+func TestAutoIdempotencyKey_KeepsCallerKey(t *testing.T) {
+	rec := &keyRecorder{header: "Idempotency-Key", failures: 1}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+		httpclient.WithAutoIdempotencyKey("", func() string { return "generated" }),
+	)
+	req, err := http.NewRequest(http.MethodPost, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Idempotency-Key", "caller")
+
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, []string{"caller", "caller"}, rec.keys)
+}
+
+func TestAutoIdempotencyKey_Methods(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		opts    []httpclient.Option
+		wantKey bool
+	}{
+		{name: "get is not covered", method: http.MethodGet},
+		{name: "put is not covered", method: http.MethodPut},
+		{
+			name:   "post already retried",
+			method: http.MethodPost,
+			opts:   []httpclient.Option{httpclient.WithRetryMethods(http.MethodPost)},
+		},
+		{
+			name:    "explicitly configured retry method",
+			method:  http.MethodPut,
+			opts:    []httpclient.Option{httpclient.WithAutoIdempotencyMethods(http.MethodPut)},
+			wantKey: true,
+		},
+		{
+			name:   "post excluded by explicit methods",
+			method: http.MethodPost,
+			opts:   []httpclient.Option{httpclient.WithAutoIdempotencyMethods(http.MethodPatch)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &keyRecorder{header: "Idempotency-Key"}
+			srv := httptest.NewServer(rec)
+			defer srv.Close()
+
+			opts := append([]httpclient.Option{
+				httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+				httpclient.WithAutoIdempotencyKey("", func() string { return "generated" }),
+			}, tt.opts...)
+			c := httpclient.New(opts...)
+			req, err := http.NewRequest(tt.method, srv.URL, nil)
+			require.NoError(t, err)
+
+			resp, err := c.Do(context.Background(), req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			require.Len(t, rec.keys, 1)
+			assert.Equal(t, tt.wantKey, rec.keys[0] == "generated")
+		})
+	}
+}
+
+func TestAutoIdempotencyKey_Disabled(t *testing.T) {
+	rec := &keyRecorder{header: "Idempotency-Key", failures: 1}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+	)
+	req, err := http.NewRequest(http.MethodPost, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	require.Error(t, err)
+	assert.Equal(t, []string{""}, rec.keys, "POST without key must not be retried")
+}
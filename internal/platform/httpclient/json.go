@@ -0,0 +1,77 @@
+package httpclient
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	stdhttp "net/http"
+	"strings"
+)
+
+// WithMaxResponseBody limits size of response body read by helpers like DoJSON (0 disables limit).
+func WithMaxResponseBody(n int64) Option {
+	return func(c *Client) { c.maxResponseBody = n }
+}
+
+// DoJSON sends request using Do and decodes JSON response into out.
+// Body is always drained and closed. Non-2xx responses are returned as *HTTPError.
+// If out is nil or response has no body, decoding is skipped.
+func (c *Client) DoJSON(ctx context.Context, req *stdhttp.Request, out any, opts ...DoOption) error {
+	o := c.resolveDoOptions(opts)
+
+	resp, err := c.Do(ctx, req, opts...)
+	if err != nil {
+		return err
+	}
+	defer drainAndClose(resp.Body)
+
+	body, err := readBody(resp, o.maxResponseBody)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet := string(body)
+		if len(snippet) > maxErrorBodySnippet {
+			snippet = snippet[:maxErrorBodySnippet]
+		}
+		return &HTTPError{
+			Method:     req.Method,
+			URL:        c.redactURL(req.URL),
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(snippet),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if out == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// readBody reads response body up to limit bytes, decompressing gzip if needed.
+func readBody(resp *stdhttp.Response, limit int64) ([]byte, error) {
+	var r io.Reader = resp.Body
+	if !resp.Uncompressed && strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("gzip response: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return body[:limit], ErrResponseTooLarge
+	}
+	return body, nil
+}
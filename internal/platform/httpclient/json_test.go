@@ -0,0 +1,122 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpclient "sttbot/internal/platform/httpclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_DoJSON_Decode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"bot","id":7}`))
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	var out struct {
+		Name string `json:"name"`
+		ID   int    `json:"id"`
+	}
+	require.NoError(t, c.DoJSON(context.Background(), req, &out))
+	require.Equal(t, "bot", out.Name)
+	require.Equal(t, 7, out.ID)
+}
+
+func TestClient_DoJSON_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(strings.Repeat("x", 2000)))
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/path?token=secret", nil)
+	require.NoError(t, err)
+
+	var out map[string]any
+	err = c.DoJSON(context.Background(), req, &out)
+	var httpErr *httpclient.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusNotFound, httpErr.StatusCode)
+	require.Equal(t, http.MethodGet, httpErr.Method)
+	require.Equal(t, srv.URL+"/path?token=secret", httpErr.URL)
+	require.Len(t, httpErr.Body, 512)
+}
+
+func TestClient_DoJSON_ResponseTooLarge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":"` + strings.Repeat("a", 100) + `"}`))
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithMaxResponseBody(32),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	var out map[string]any
+	err = c.DoJSON(context.Background(), req, &out)
+	require.True(t, errors.Is(err, httpclient.ErrResponseTooLarge))
+
+	// per-request override lifts the limit
+	req, err = http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	require.NoError(t, c.DoJSON(context.Background(), req, &out, httpclient.PerRequestMaxResponseBody(0)))
+}
+
+func TestClient_DoJSON_Gzip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte(`{"ok":true}`))
+	require.NoError(t, zw.Close())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	// explicit Accept-Encoding disables transparent decompression in transport
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	require.NoError(t, c.DoJSON(context.Background(), req, &out))
+	require.True(t, out.OK)
+}
+
+func TestClient_DoJSON_EmptyBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	req, err := http.NewRequest(http.MethodDelete, srv.URL, nil)
+	require.NoError(t, err)
+
+	var out map[string]any
+	require.NoError(t, c.DoJSON(context.Background(), req, &out))
+	require.Nil(t, out)
+}
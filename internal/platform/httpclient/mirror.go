@@ -0,0 +1,187 @@
+package httpclient
+
+import (
+	"context"
+	randv2 "math/rand/v2"
+	stdhttp "net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// maxMirrorRetries caps MirrorOptions.Retries, so shadow traffic cannot multiply load on target.
+const maxMirrorRetries = 2
+
+// DefaultMirrorStripHeaders are headers removed from mirrored requests when
+// MirrorOptions.StripHeaders is nil.
+var DefaultMirrorStripHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// MirrorOptions configures WithMirror.
+type MirrorOptions struct {
+	// Workers limits number of mirrored requests in flight (default 4).
+	// Sampled requests arriving while all workers are busy are dropped.
+	Workers int
+	// Timeout limits each mirrored request including its retries (default 5s).
+	// It does not depend on deadline of primary request.
+	Timeout time.Duration
+	// Retries is number of retries of mirrored request on network error or 5xx status
+	// (default 0, at most 2). Retries are sent immediately, without backoff.
+	Retries int
+	// StripHeaders are removed from mirrored requests (nil means DefaultMirrorStripHeaders,
+	// empty non-nil slice keeps all headers).
+	StripHeaders []string
+	// OnResult is called after each mirrored request from mirror worker goroutine (optional).
+	OnResult func(MirrorResult)
+}
+
+// MirrorResult describes outcome of mirrored request. Response body is discarded.
+type MirrorResult struct {
+	Method      string
+	RedactedURL string
+	// StatusCode is status of last attempt, 0 if it failed without response.
+	StatusCode int
+	Attempts   int
+	Duration   time.Duration
+	Err        error
+}
+
+// MirrorStats contains mirroring counters.
+type MirrorStats struct {
+	Mirrored int64 // sampled requests sent to mirror target
+	Dropped  int64 // sampled requests dropped because all workers were busy
+}
+
+// mirror sends copies of sampled requests to target.
+type mirror struct {
+	target   *url.URL
+	fraction float64
+	opts     MirrorOptions
+	strip    []string
+	slots    chan struct{}
+	mirrored atomic.Int64
+	dropped  atomic.Int64
+}
+
+// WithMirror sends asynchronous copy of fraction (0..1) of requests to target, e.g. to
+// compare new provider with production traffic. Copy has same method, headers and body,
+// with scheme and host replaced by target's and target path prepended to request path.
+// Only the first attempt of Do is mirrored, retries never are. Mirrored requests
+// never block or fail the primary request: response is reported to MirrorOptions.OnResult
+// and discarded, and sampled requests are dropped while all workers are busy.
+// Mirrored requests use client transport, but neither cookie jar nor hooks.
+func WithMirror(target *url.URL, fraction float64, opts MirrorOptions) Option {
+	return func(c *Client) {
+		if target == nil || fraction <= 0 {
+			c.mirror = nil
+			return
+		}
+		if opts.Workers <= 0 {
+			opts.Workers = 4
+		}
+		if opts.Timeout <= 0 {
+			opts.Timeout = 5 * time.Second
+		}
+		opts.Retries = min(max(opts.Retries, 0), maxMirrorRetries)
+		strip := opts.StripHeaders
+		if strip == nil {
+			strip = DefaultMirrorStripHeaders
+		}
+		c.mirror = &mirror{
+			target:   target,
+			fraction: min(fraction, 1),
+			opts:     opts,
+			strip:    strip,
+			slots:    make(chan struct{}, opts.Workers),
+		}
+	}
+}
+
+// MirrorStats returns counters of WithMirror.
+func (c *Client) MirrorStats() MirrorStats {
+	if c.mirror == nil {
+		return MirrorStats{}
+	}
+	return MirrorStats{Mirrored: c.mirror.mirrored.Load(), Dropped: c.mirror.dropped.Load()}
+}
+
+// mirrorRequest sends copy of first attempt r to mirror target if r is sampled.
+// r must be fully prepared (headers, replayable body) and is not modified.
+func (c *Client) mirrorRequest(r *stdhttp.Request) {
+	m := c.mirror
+	if m == nil || m.fraction < 1 && randv2.Float64() >= m.fraction {
+		return
+	}
+	if r.Body != nil && r.Body != stdhttp.NoBody && r.GetBody == nil {
+		return // body cannot be replayed without consuming primary request
+	}
+	select {
+	case m.slots <- struct{}{}:
+	default:
+		m.dropped.Add(1)
+		return
+	}
+	m.mirrored.Add(1)
+
+	header := r.Header.Clone()
+	for _, h := range m.strip {
+		header.Del(h)
+	}
+	u := *r.URL
+	u.Scheme = m.target.Scheme
+	u.Host = m.target.Host
+	u.User = m.target.User
+	if p := strings.TrimSuffix(m.target.Path, "/"); p != "" {
+		u.Path = p + u.Path
+		u.RawPath = ""
+	}
+	hc := &stdhttp.Client{Transport: c.hc.Transport, CheckRedirect: c.hc.CheckRedirect}
+
+	go func() {
+		defer func() { <-m.slots }()
+		result := c.sendMirror(hc, r, &u, header)
+		if m.opts.OnResult != nil {
+			m.opts.OnResult(result)
+		}
+	}()
+}
+
+// sendMirror sends mirrored request with its own timeout and retries.
+func (c *Client) sendMirror(hc *stdhttp.Client, r *stdhttp.Request, u *url.URL, header stdhttp.Header) MirrorResult {
+	m := c.mirror
+	ctx, cancel := context.WithTimeout(context.Background(), m.opts.Timeout)
+	defer cancel()
+
+	result := MirrorResult{Method: r.Method, RedactedURL: c.redactURL(u)}
+	start := time.Now()
+	for attempt := 1; attempt <= m.opts.Retries+1; attempt++ {
+		req, err := stdhttp.NewRequestWithContext(ctx, r.Method, u.String(), nil)
+		if err != nil {
+			result.Err = err
+			break
+		}
+		req.Header = header.Clone()
+		req.ContentLength = r.ContentLength
+		if r.GetBody != nil {
+			if req.Body, err = r.GetBody(); err != nil {
+				result.Err = err
+				break
+			}
+			req.GetBody = r.GetBody
+		}
+
+		resp, err := hc.Do(req)
+		result.Attempts = attempt
+		result.StatusCode, result.Err = 0, err
+		if err == nil {
+			result.StatusCode = resp.StatusCode
+			drainAndClose(resp.Body)
+		}
+		retry := err != nil && isRetryableError(err) || err == nil && resp.StatusCode >= 500
+		if !retry || ctx.Err() != nil {
+			break
+		}
+	}
+	result.Duration = time.Since(start)
+	return result
+}
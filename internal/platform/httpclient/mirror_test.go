@@ -0,0 +1,195 @@
+package httpclient_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/platform/httpclient"
+)
+
+// mirroredRequest is request received by mirror target.
+type mirroredRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   string
+}
+
+func TestClient_Mirror(t *testing.T) {
+	var primaryCalls atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryCalls.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		_, _ = io.WriteString(w, "primary:"+string(body))
+	}))
+	defer primary.Close()
+
+	received := make(chan mirroredRequest, 10)
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- mirroredRequest{method: r.Method, path: r.URL.Path, header: r.Header, body: string(body)}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer shadow.Close()
+
+	target, err := url.Parse(shadow.URL + "/v2")
+	require.NoError(t, err)
+	results := make(chan httpclient.MirrorResult, 10)
+	c := httpclient.New(httpclient.WithMirror(target, 1, httpclient.MirrorOptions{
+		OnResult: func(r httpclient.MirrorResult) { results <- r },
+	}))
+
+	req, err := http.NewRequest(http.MethodPost, primary.URL+"/recognize", strings.NewReader("audio"))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Request-ID", "42")
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "primary:audio", string(body), "primary response is unaffected")
+
+	got := <-received
+	require.Equal(t, http.MethodPost, got.method)
+	require.Equal(t, "/v2/recognize", got.path)
+	require.Equal(t, "audio", got.body, "body is replayed")
+	require.Equal(t, "42", got.header.Get("X-Request-ID"))
+	require.Empty(t, got.header.Get("Authorization"), "sensitive headers are stripped")
+
+	result := <-results
+	require.Equal(t, http.StatusAccepted, result.StatusCode)
+	require.Equal(t, 1, result.Attempts)
+	require.NoError(t, result.Err)
+	require.Equal(t, shadow.URL+"/v2/recognize", result.RedactedURL)
+	require.Equal(t, httpclient.MirrorStats{Mirrored: 1}, c.MirrorStats())
+	require.Equal(t, int32(1), primaryCalls.Load())
+}
+
+func TestClient_MirrorOnlyFirstAttempt(t *testing.T) {
+	var attempts atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer primary.Close()
+
+	var mirrored atomic.Int32
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrored.Add(1)
+	}))
+	defer shadow.Close()
+
+	target, _ := url.Parse(shadow.URL)
+	done := make(chan struct{}, 10)
+	c := httpclient.New(
+		httpclient.WithRetries(2, time.Millisecond),
+		httpclient.WithMirror(target, 1, httpclient.MirrorOptions{OnResult: func(httpclient.MirrorResult) { done <- struct{}{} }}),
+	)
+	status, _ := get(t, c, primary.URL)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, int32(3), attempts.Load())
+
+	<-done
+	require.Equal(t, int32(1), mirrored.Load(), "retries of primary request are not mirrored")
+}
+
+func TestClient_MirrorFailureDoesNotAffectPrimary(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer primary.Close()
+
+	var mirrorAttempts atomic.Int32
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mirrorAttempts.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer shadow.Close()
+
+	target, _ := url.Parse(shadow.URL)
+	results := make(chan httpclient.MirrorResult, 1)
+	c := httpclient.New(httpclient.WithMirror(target, 1, httpclient.MirrorOptions{
+		Retries:  10, // capped
+		OnResult: func(r httpclient.MirrorResult) { results <- r },
+	}))
+	status, _ := get(t, c, primary.URL)
+	require.Equal(t, http.StatusOK, status)
+
+	result := <-results
+	require.Equal(t, http.StatusInternalServerError, result.StatusCode)
+	require.Equal(t, 3, result.Attempts, "mirror retries are capped")
+	require.Equal(t, int32(3), mirrorAttempts.Load())
+}
+
+func TestClient_MirrorTimeoutAndDrop(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer primary.Close()
+
+	release := make(chan struct{})
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer shadow.Close()
+	defer close(release)
+
+	target, _ := url.Parse(shadow.URL)
+	var mu sync.Mutex
+	var results []httpclient.MirrorResult
+	finished := make(chan struct{})
+	c := httpclient.New(httpclient.WithMirror(target, 1, httpclient.MirrorOptions{
+		Workers: 1,
+		Timeout: 500 * time.Millisecond,
+		OnResult: func(r httpclient.MirrorResult) {
+			mu.Lock()
+			results = append(results, r)
+			mu.Unlock()
+			close(finished)
+		},
+	}))
+
+	start := time.Now()
+	for range 3 {
+		status, _ := get(t, c, primary.URL)
+		require.Equal(t, http.StatusOK, status)
+	}
+	require.Less(t, time.Since(start), 500*time.Millisecond, "primary requests never wait for mirror")
+	require.Equal(t, httpclient.MirrorStats{Mirrored: 1, Dropped: 2}, c.MirrorStats())
+
+	<-finished
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, results, 1)
+	require.ErrorIs(t, results[0].Err, context.DeadlineExceeded)
+}
+
+func TestClient_MirrorSampling(t *testing.T) {
+	rt := rtFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+	})
+	target, _ := url.Parse("http://shadow.example.com")
+
+	c := httpclient.New(httpclient.WithTransport(rt), httpclient.WithMirror(target, 0, httpclient.MirrorOptions{}))
+	get(t, c, "http://example.com/")
+	require.Equal(t, httpclient.MirrorStats{}, c.MirrorStats(), "zero fraction disables mirroring")
+
+	c = httpclient.New(httpclient.WithTransport(rt), httpclient.WithMirror(target, 0.5, httpclient.MirrorOptions{Workers: 1000}))
+	for range 1000 {
+		get(t, c, "http://example.com/")
+	}
+	require.InDelta(t, 500, c.MirrorStats().Mirrored, 100)
+}
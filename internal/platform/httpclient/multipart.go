@@ -0,0 +1,155 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	stdhttp "net/http"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// FilePart describes file uploaded by PostMultipart.
+// Content is taken from Open if it is set, otherwise from Data.
+type FilePart struct {
+	// Name is form field name.
+	Name string
+	// Filename is file name sent in Content-Disposition.
+	Filename string
+	// ContentType defaults to application/octet-stream.
+	ContentType string
+	// Data is in-memory file content.
+	Data []byte
+	// Open returns fresh reader of file content; it is called once per attempt.
+	Open func() (io.ReadCloser, error)
+}
+
+// quoteEscaper escapes quotes and backslashes in Content-Disposition parameters.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// PostMultipart sends multipart/form-data POST request with fields and files using Do.
+// If any file has Open, body is streamed and regenerated for each attempt without buffering;
+// otherwise body is built in memory and must fit into replay limit (see WithMaxReplayBodySize).
+// Retries follow the same rules as Do: POST is retried only with Idempotency-Key
+// (see PerRequestHeader) or PerRequestRetryNonIdempotent.
+func (c *Client) PostMultipart(ctx context.Context, url string, fields map[string]string, files []FilePart, opts ...DoOption) (*stdhttp.Response, error) {
+	for i, f := range files {
+		if f.Name == "" {
+			return nil, fmt.Errorf("multipart file %d: empty field name", i)
+		}
+	}
+
+	req, err := stdhttp.NewRequestWithContext(ctx, stdhttp.MethodPost, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	mb := &multipartBody{fields: fields, files: files, boundary: multipart.NewWriter(nil).Boundary()}
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+mb.boundary)
+
+	if mb.streaming() {
+		// Every attempt obtains body from GetBody, so initial body is never read
+		req.Body = stdhttp.NoBody
+		req.GetBody = mb.stream
+		req.ContentLength = -1
+	} else {
+		var buf bytes.Buffer
+		if err := mb.write(&buf); err != nil {
+			return nil, err
+		}
+		if c.maxReplayBody > 0 && int64(buf.Len()) > c.maxReplayBody {
+			return nil, ErrReplayBodyTooLarge
+		}
+		body := buf.Bytes()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+		req.ContentLength = int64(len(body))
+	}
+
+	return c.Do(ctx, req, opts...)
+}
+
+// multipartBody generates identical multipart body for every attempt.
+type multipartBody struct {
+	fields   map[string]string
+	files    []FilePart
+	boundary string
+}
+
+// streaming reports whether any file is read through Open.
+func (m *multipartBody) streaming() bool {
+	for _, f := range m.files {
+		if f.Open != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// stream returns reader producing body on the fly.
+// Writer goroutine stops when reader is closed.
+func (m *multipartBody) stream() (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(m.write(pw))
+	}()
+	return pr, nil
+}
+
+// write encodes fields in sorted order followed by files.
+func (m *multipartBody) write(w io.Writer) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(m.boundary); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(m.fields))
+	for k := range m.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := mw.WriteField(k, m.fields[k]); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range m.files {
+		if err := writeFilePart(mw, f); err != nil {
+			return fmt.Errorf("multipart file %q: %w", f.Name, err)
+		}
+	}
+	return mw.Close()
+}
+
+// writeFilePart writes single file part.
+func writeFilePart(mw *multipart.Writer, f FilePart) error {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(f.Name), quoteEscaper.Replace(f.Filename)))
+	ct := f.ContentType
+	if ct == "" {
+		ct = "application/octet-stream"
+	}
+	h.Set("Content-Type", ct)
+
+	pw, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	if f.Open == nil {
+		_, err = pw.Write(f.Data)
+		return err
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(pw, rc)
+	return errors.Join(err, rc.Close())
+}
@@ -0,0 +1,117 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	httpclient "sttbot/internal/platform/httpclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+// multipartServer fails first failures requests with 503 and records parsed form of the last one.
+type multipartServer struct {
+	failures int32
+	calls    atomic.Int32
+	fields   map[string]string
+	files    map[string]string
+}
+
+func (s *multipartServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if s.calls.Add(1) <= s.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	s.fields = map[string]string{}
+	for k, v := range r.MultipartForm.Value {
+		s.fields[k] = v[0]
+	}
+	s.files = map[string]string{}
+	for k, fhs := range r.MultipartForm.File {
+		f, err := fhs[0].Open()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		b, _ := io.ReadAll(f)
+		f.Close()
+		s.files[k] = fhs[0].Filename + ":" + fhs[0].Header.Get("Content-Type") + ":" + string(b)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestClient_PostMultipart_Buffered(t *testing.T) {
+	ms := &multipartServer{failures: 1}
+	srv := httptest.NewServer(ms)
+	defer srv.Close()
+
+	c := newTestClient(httpclient.WithRetries(2, 0))
+	resp, err := c.PostMultipart(context.Background(), srv.URL,
+		map[string]string{"lang": "ru"},
+		[]httpclient.FilePart{{Name: "audio", Filename: "voice.ogg", ContentType: "audio/ogg", Data: []byte("OggS")}},
+		httpclient.PerRequestHeader("Idempotency-Key", "k1"),
+	)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.EqualValues(t, 2, ms.calls.Load())
+	require.Equal(t, map[string]string{"lang": "ru"}, ms.fields)
+	require.Equal(t, map[string]string{"audio": "voice.ogg:audio/ogg:OggS"}, ms.files)
+}
+
+func TestClient_PostMultipart_StreamingReopensPerAttempt(t *testing.T) {
+	ms := &multipartServer{failures: 1}
+	srv := httptest.NewServer(ms)
+	defer srv.Close()
+
+	var opens atomic.Int32
+	file := httpclient.FilePart{
+		Name:     "audio",
+		Filename: "voice.ogg",
+		Open: func() (io.ReadCloser, error) {
+			opens.Add(1)
+			return io.NopCloser(bytes.NewReader([]byte("stream"))), nil
+		},
+	}
+
+	c := newTestClient(httpclient.WithRetries(2, 0), httpclient.WithMaxReplayBodySize(1))
+	resp, err := c.PostMultipart(context.Background(), srv.URL, nil, []httpclient.FilePart{file},
+		httpclient.PerRequestRetryNonIdempotent(true))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.EqualValues(t, 2, ms.calls.Load())
+	require.EqualValues(t, 2, opens.Load())
+	require.Equal(t, map[string]string{"audio": "voice.ogg:application/octet-stream:stream"}, ms.files)
+}
+
+func TestClient_PostMultipart_NoRetryWithoutIdempotencyKey(t *testing.T) {
+	ms := &multipartServer{failures: 1}
+	srv := httptest.NewServer(ms)
+	defer srv.Close()
+
+	c := newTestClient(httpclient.WithRetries(2, 0))
+	_, err := c.PostMultipart(context.Background(), srv.URL, map[string]string{"a": "b"}, nil)
+	var httpErr *httpclient.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	require.EqualValues(t, 1, ms.calls.Load())
+}
+
+func TestClient_PostMultipart_BufferedTooLarge(t *testing.T) {
+	c := newTestClient(httpclient.WithRetries(2, 0), httpclient.WithMaxReplayBodySize(16))
+	_, err := c.PostMultipart(context.Background(), "http://127.0.0.1", nil,
+		[]httpclient.FilePart{{Name: "audio", Data: bytes.Repeat([]byte("x"), 64)}})
+	require.ErrorIs(t, err, httpclient.ErrReplayBodyTooLarge)
+}
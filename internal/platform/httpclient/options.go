@@ -0,0 +1,77 @@
+package httpclient
+
+import (
+	stdhttp "net/http"
+	"time"
+)
+
+// DoOption configures single request and overrides client defaults only for that call.
+type DoOption func(*doOptions)
+
+// doOptions contains per-request settings resolved from client defaults and DoOption values.
+type doOptions struct {
+	retries         int
+	retryNonIdem    bool
+	timeout         time.Duration
+	maxResponseBody int64
+	noCache         bool
+	headers         map[string]string
+	noCompressBody  bool
+}
+
+// PerRequestRetries overrides number of retries for single request (0 disables retries).
+func PerRequestRetries(n int) DoOption {
+	return func(o *doOptions) { o.retries = n }
+}
+
+// PerRequestTimeout overrides per-attempt timeout for single request.
+func PerRequestTimeout(d time.Duration) DoOption {
+	return func(o *doOptions) { o.timeout = d }
+}
+
+// PerRequestRetryNonIdempotent allows or forbids retries of non-idempotent methods for single request.
+func PerRequestRetryNonIdempotent(v bool) DoOption {
+	return func(o *doOptions) { o.retryNonIdem = v }
+}
+
+// PerRequestMaxResponseBody overrides response body limit for single request (0 disables limit).
+func PerRequestMaxResponseBody(n int64) DoOption {
+	return func(o *doOptions) { o.maxResponseBody = n }
+}
+
+// PerRequestHeader sets request header for single request, for example Idempotency-Key
+// for helpers like PostMultipart that build request internally.
+func PerRequestHeader(key, value string) DoOption {
+	return func(o *doOptions) {
+		if o.headers == nil {
+			o.headers = make(map[string]string)
+		}
+		o.headers[key] = value
+	}
+}
+
+// resolveDoOptions applies per-request options on top of client defaults.
+func (c *Client) resolveDoOptions(opts []DoOption) doOptions {
+	o := doOptions{
+		retries:         c.retries,
+		retryNonIdem:    c.retryNonIdem,
+		timeout:         c.hc.Timeout,
+		maxResponseBody: c.maxResponseBody,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// httpClient returns http.Client for request, copying shared client only when timeout differs.
+func (c *Client) httpClient(o doOptions) *stdhttp.Client {
+	if o.timeout == c.hc.Timeout {
+		return c.hc
+	}
+	hc := *c.hc
+	hc.Timeout = o.timeout
+	return &hc
+}
@@ -0,0 +1,136 @@
+package httpclient_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Do_PerRequestRetries(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(3, time.Millisecond),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req, httpclient.PerRequestRetries(0))
+	require.Error(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+
+	// client defaults are not affected by previous call
+	atomic.StoreInt32(&attempts, 0)
+	_, err = c.Do(context.Background(), req)
+	require.Error(t, err)
+	require.Equal(t, int32(4), atomic.LoadInt32(&attempts))
+}
+
+func TestClient_Do_PerRequestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithTimeout(50*time.Millisecond),
+	)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	_, err = c.Do(context.Background(), req)
+	require.Error(t, err)
+
+	req, err = http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := c.Do(context.Background(), req, httpclient.PerRequestTimeout(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestClient_Do_PerRequestRetryNonIdempotent(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+	)
+	req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+
+	resp, err := c.Do(context.Background(), req, httpclient.PerRequestRetryNonIdempotent(true))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
+
+func TestClient_Do_PerRequestConcurrent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("slow") != "" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithTimeout(20*time.Millisecond),
+	)
+
+	var wg sync.WaitGroup
+	var slowOK, fastTimeouts int32
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+"?slow=1", nil)
+			resp, err := c.Do(context.Background(), req, httpclient.PerRequestTimeout(time.Second))
+			if err == nil {
+				resp.Body.Close()
+				atomic.AddInt32(&slowOK, 1)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, srv.URL+"?slow=1", nil)
+			_, err := c.Do(context.Background(), req)
+			var ue interface{ Timeout() bool }
+			if errors.As(err, &ue) && ue.Timeout() {
+				atomic.AddInt32(&fastTimeouts, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(10), slowOK)
+	require.Equal(t, int32(10), fastTimeouts)
+}
@@ -0,0 +1,129 @@
+package httpclient
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimit contains token bucket settings for host.
+type rateLimit struct {
+	rps   float64
+	burst int
+}
+
+// WithRateLimit limits attempts sent to host (without port) to rps per second with given burst.
+// Zero or negative rps disables limiting for host, even if default limit is set.
+func WithRateLimit(host string, rps float64, burst int) Option {
+	return func(c *Client) {
+		if c.hostLimits == nil {
+			c.hostLimits = make(map[string]rateLimit)
+		}
+		c.hostLimits[strings.ToLower(host)] = rateLimit{rps: rps, burst: burst}
+	}
+}
+
+// WithDefaultRateLimit limits attempts to hosts without explicit WithRateLimit.
+// Every host gets its own bucket. Zero or negative rps disables limiting.
+func WithDefaultRateLimit(rps float64, burst int) Option {
+	return func(c *Client) { c.defaultLimit = rateLimit{rps: rps, burst: burst} }
+}
+
+// tokenBucket is a token bucket shared by all requests to single host.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket creates bucket that starts full. Burst below 1 is treated as 1.
+func newTokenBucket(l rateLimit, now time.Time) *tokenBucket {
+	burst := float64(l.burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: l.rps, burst: burst, tokens: burst, last: now}
+}
+
+// reserve takes one token and returns how long caller must wait before using it.
+// Tokens may go negative, so concurrent callers queue up in reservation order.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// cancel returns token taken by reserve that was not used.
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// limiterFor returns bucket for host or nil if host is not limited.
+func (c *Client) limiterFor(host string) *tokenBucket {
+	host = strings.ToLower(host)
+	l, ok := c.hostLimits[host]
+	if !ok {
+		l = c.defaultLimit
+	}
+	if l.rps <= 0 {
+		return nil
+	}
+
+	c.limitMu.Lock()
+	defer c.limitMu.Unlock()
+	if c.limiters == nil {
+		c.limiters = make(map[string]*tokenBucket)
+	}
+	b, ok := c.limiters[host]
+	if !ok {
+		b = newTokenBucket(l, time.Now())
+		c.limiters[host] = b
+	}
+	return b
+}
+
+// reserveRateLimit reserves slot for next attempt to host and returns required wait.
+// Returned cancel gives slot back if attempt is not sent.
+func (c *Client) reserveRateLimit(host string) (time.Duration, func()) {
+	b := c.limiterFor(host)
+	if b == nil {
+		return 0, func() {}
+	}
+	return b.reserve(time.Now()), b.cancel
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,138 @@
+package httpclient_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Do_RateLimit(t *testing.T) {
+	srv := statusServer(t, http.StatusOK)
+
+	var mu sync.Mutex
+	var waits []time.Duration
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRateLimit("127.0.0.1", 20, 1),
+		httpclient.WithResponseHook(func(r *http.Request, resp *http.Response, err error, dur time.Duration, attempt int) {
+			info, ok := httpclient.AttemptInfoFromRequest(r)
+			require.True(t, ok)
+			mu.Lock()
+			defer mu.Unlock()
+			waits = append(waits, info.RateLimitWait)
+		}),
+	)
+
+	start := time.Now()
+	for range 3 {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := c.Do(context.Background(), req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+	require.Len(t, waits, 3)
+	require.Zero(t, waits[0])
+	require.Positive(t, waits[1])
+	require.Positive(t, waits[2])
+}
+
+func TestClient_Do_RateLimitShared(t *testing.T) {
+	srv := statusServer(t, http.StatusOK)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithDefaultRateLimit(50, 1),
+	)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			require.NoError(t, err)
+			resp, err := c.Do(context.Background(), req)
+			require.NoError(t, err)
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+	require.GreaterOrEqual(t, time.Since(start), 70*time.Millisecond)
+}
+
+func TestClient_Do_RateLimitDisabled(t *testing.T) {
+	srv := statusServer(t, http.StatusOK)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithDefaultRateLimit(1, 1),
+		httpclient.WithRateLimit("127.0.0.1", 0, 0),
+	)
+
+	start := time.Now()
+	for range 3 {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := c.Do(context.Background(), req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestClient_Do_RateLimitContextCancel(t *testing.T) {
+	srv := statusServer(t, http.StatusOK)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRateLimit("127.0.0.1", 1, 1),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = c.Do(ctx, req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestClient_Do_RateLimitCountsTowardMaxRetryDuration(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(3, time.Millisecond),
+		httpclient.WithMaxRetryDuration(100*time.Millisecond),
+		httpclient.WithRateLimit("127.0.0.1", 2, 1),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = c.Do(context.Background(), req)
+	require.ErrorIs(t, err, httpclient.ErrMaxRetryDurationExceeded)
+	require.Equal(t, 1, attempts)
+	require.Less(t, time.Since(start), 300*time.Millisecond)
+}
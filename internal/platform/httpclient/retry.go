@@ -0,0 +1,93 @@
+package httpclient
+
+import (
+	stdhttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// epochThreshold separates delta-seconds from Unix timestamps in rate limit headers.
+// Values above it (roughly year 2001) are treated as epoch seconds.
+const epochThreshold = 1_000_000_000
+
+// WithRetryStatuses opts additional status codes into retries (e.g. 307, 520, 522).
+// Delay is taken from Retry-After or headers set by WithRetryAfterHeader.
+func WithRetryStatuses(codes ...int) Option {
+	return func(c *Client) {
+		if c.retryStatuses == nil {
+			c.retryStatuses = make(map[int]struct{})
+		}
+		for _, code := range codes {
+			c.retryStatuses[code] = struct{}{}
+		}
+	}
+}
+
+// WithRetryAfterHeader adds header consulted for retry delay when Retry-After is absent.
+// Header value may be delta-seconds, HTTP-date or Unix epoch seconds (e.g. X-RateLimit-Reset).
+func WithRetryAfterHeader(name string) Option {
+	return func(c *Client) {
+		if name != "" {
+			c.retryAfterHeaders = append(c.retryAfterHeaders, name)
+		}
+	}
+}
+
+// classifyRetry applies opt-in retry statuses and custom delay headers on top of retry policy.
+func (c *Client) classifyRetry(resp *stdhttp.Response, err error) (time.Duration, bool) {
+	if err == nil && resp != nil {
+		if _, ok := c.retryStatuses[resp.StatusCode]; ok {
+			delay := c.retryDelayFromHeaders(resp.Header)
+			drainAndClose(resp.Body)
+			return delay, true
+		}
+	}
+	delay, retry := c.retryPolicy(resp, err)
+	if retry && delay == 0 && resp != nil && len(c.retryAfterHeaders) > 0 {
+		delay = c.retryDelayFromHeaders(resp.Header)
+	}
+	return delay, retry
+}
+
+// retryDelayFromHeaders returns delay from Retry-After or configured custom headers.
+func (c *Client) retryDelayFromHeaders(h stdhttp.Header) time.Duration {
+	if d := retryAfter(h.Get("Retry-After")); d > 0 {
+		return d
+	}
+	for _, name := range c.retryAfterHeaders {
+		if d := resetAfter(h.Get(name), time.Now()); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// resetAfter parses rate limit reset header value as delta-seconds, epoch seconds or HTTP-date.
+func resetAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		if secs >= epochThreshold {
+			d := time.Unix(0, int64(secs*float64(time.Second))).Sub(now)
+			if d < 0 {
+				return 0
+			}
+			return d
+		}
+		return time.Duration(secs * float64(time.Second))
+	}
+	if t, err := stdhttp.ParseTime(v); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			return 0
+		}
+		return d
+	}
+	return 0
+}
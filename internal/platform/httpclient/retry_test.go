@@ -0,0 +1,138 @@
+package httpclient_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+// flakyServer returns status with headers on first request and 200 afterwards.
+func flakyServer(t *testing.T, status int, headers map[string]string) (*httptest.Server, *int) {
+	t.Helper()
+	attempts := new(int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*attempts++
+		if *attempts == 1 {
+			for k, v := range headers {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, attempts
+}
+
+func TestClient_Do_NoRetry307ByDefault(t *testing.T) {
+	srv, attempts := flakyServer(t, http.StatusTemporaryRedirect, map[string]string{"Retry-After": "1"})
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode)
+	require.Equal(t, 1, *attempts)
+}
+
+func TestClient_Do_RetryStatuses307RetryAfter(t *testing.T) {
+	srv, attempts := flakyServer(t, http.StatusTemporaryRedirect, map[string]string{"Retry-After": "1"})
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+		httpclient.WithRetryStatuses(http.StatusTemporaryRedirect),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 2, *attempts)
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestClient_Do_RetryAfterHeaderFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    func() string
+		minDelay time.Duration
+	}{
+		{
+			name:     "delta seconds",
+			value:    func() string { return "0.3" },
+			minDelay: 300 * time.Millisecond,
+		},
+		{
+			name: "epoch seconds",
+			value: func() string {
+				reset := time.Now().Add(400 * time.Millisecond)
+				return fmt.Sprintf("%.3f", float64(reset.UnixNano())/float64(time.Second))
+			},
+			minDelay: 300 * time.Millisecond,
+		},
+		{
+			name:     "http date",
+			value:    func() string { return time.Now().Add(1500 * time.Millisecond).UTC().Format(http.TimeFormat) },
+			minDelay: 400 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, attempts := flakyServer(t, 522, map[string]string{"X-RateLimit-Reset": tt.value()})
+
+			c := httpclient.New(
+				httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+				httpclient.WithRetries(1, time.Millisecond),
+				httpclient.WithRetryStatuses(520, 522),
+				httpclient.WithRetryAfterHeader("X-RateLimit-Reset"),
+			)
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			require.NoError(t, err)
+
+			start := time.Now()
+			resp, err := c.Do(context.Background(), req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, 2, *attempts)
+			require.GreaterOrEqual(t, time.Since(start), tt.minDelay)
+		})
+	}
+}
+
+func TestClient_Do_RetryAfterHeaderWithDefaultPolicy(t *testing.T) {
+	srv, attempts := flakyServer(t, http.StatusTooManyRequests, map[string]string{"X-RateLimit-Reset": "0.3"})
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+		httpclient.WithRetryAfterHeader("X-RateLimit-Reset"),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 2, *attempts)
+	require.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+}
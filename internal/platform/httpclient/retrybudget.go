@@ -0,0 +1,85 @@
+package httpclient
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"sttbot/pkg/retry"
+)
+
+// ErrRetryBudgetExhausted indicates retries stopped because shared RetryBudget denied a retry.
+// Error returned by Do wraps both this sentinel and the last attempt error.
+var ErrRetryBudgetExhausted = errors.New("http: retry budget exhausted")
+
+// RetryBudgetConfig configures RetryBudget.
+type RetryBudgetConfig struct {
+	// MaxRetries is bucket capacity: number of retries allowed in burst.
+	MaxRetries int
+	// Interval is period over which MaxRetries tokens are refilled (0 disables time-based refill).
+	Interval time.Duration
+	// Ratio enables ratio-of-requests mode: every Do call sent to network deposits Ratio tokens,
+	// so retries are limited to about Ratio of requests (e.g. 0.1 for 10%).
+	// 0 disables it.
+	Ratio float64
+	// Now returns current time (for testing, defaults to time.Now).
+	Now func() time.Time
+}
+
+// RetryBudgetStats contains RetryBudget counters.
+type RetryBudgetStats struct {
+	Requests  uint64  // Do calls accounted
+	Granted   uint64  // retries allowed
+	Denied    uint64  // retries rejected
+	Available float64 // tokens currently available
+}
+
+// RetryBudget is token bucket limiting total number of retries of all clients sharing it.
+// Each retry consumes one token. Tokens are refilled over Interval and, in
+// ratio-of-requests mode, deposited by every request. Bucket starts full.
+// It is retry.Budget with request accounting, so refill rules are the same as in pkg/retry.
+// RetryBudget is safe for concurrent use.
+type RetryBudget struct {
+	budget   *retry.Budget
+	requests atomic.Uint64
+}
+
+// NewRetryBudget creates RetryBudget.
+func NewRetryBudget(cfg RetryBudgetConfig) *RetryBudget {
+	return &RetryBudget{budget: retry.NewBudget(retry.BudgetConfig{
+		MaxRetries: cfg.MaxRetries,
+		Interval:   cfg.Interval,
+		// Ratio is deposited per request rather than per success, see recordRequest
+		SuccessRatio: cfg.Ratio,
+		Now:          cfg.Now,
+	})}
+}
+
+// WithRetryBudget makes client consult b before every retry.
+// Pass the same budget to several clients to cap retries of all of them together.
+// When b denies retry, Do stops and returns last error wrapped with ErrRetryBudgetExhausted.
+func WithRetryBudget(b *RetryBudget) Option {
+	return func(c *Client) { c.retryBudget = b }
+}
+
+// Allow consumes one token and reports whether retry is permitted.
+func (b *RetryBudget) Allow() bool {
+	return b.budget.Allow()
+}
+
+// Stats returns snapshot of budget counters.
+func (b *RetryBudget) Stats() RetryBudgetStats {
+	stats := b.budget.Stats()
+	return RetryBudgetStats{
+		Requests:  b.requests.Load(),
+		Granted:   stats.Granted,
+		Denied:    stats.Denied,
+		Available: stats.Available,
+	}
+}
+
+// recordRequest accounts new request and deposits Ratio tokens.
+func (b *RetryBudget) recordRequest() {
+	b.requests.Add(1)
+	b.budget.RecordSuccess()
+}
@@ -0,0 +1,166 @@
+package httpclient_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+	"sttbot/internal/shared"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBudget_Refill(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := httpclient.NewRetryBudget(httpclient.RetryBudgetConfig{
+		MaxRetries: 2,
+		Interval:   time.Second,
+		Now:        func() time.Time { return now },
+	})
+
+	require.True(t, b.Allow())
+	require.True(t, b.Allow())
+	require.False(t, b.Allow())
+
+	// Half of interval refills one token
+	now = now.Add(500 * time.Millisecond)
+	require.True(t, b.Allow())
+	require.False(t, b.Allow())
+
+	// Refill is capped by capacity
+	now = now.Add(time.Hour)
+	stats := b.Stats()
+	require.EqualValues(t, 3, stats.Granted)
+	require.EqualValues(t, 2, stats.Denied)
+	require.InDelta(t, 2, stats.Available, 1e-9)
+}
+
+func TestRetryBudget_Concurrent(t *testing.T) {
+	b := httpclient.NewRetryBudget(httpclient.RetryBudgetConfig{MaxRetries: 50})
+
+	var granted atomic.Int64
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				if b.Allow() {
+					granted.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := b.Stats()
+	require.EqualValues(t, 50, granted.Load())
+	require.EqualValues(t, 50, stats.Granted)
+	require.EqualValues(t, 950, stats.Denied)
+	require.Zero(t, stats.Available)
+}
+
+func TestClient_Do_SharedRetryBudget(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	budget := httpclient.NewRetryBudget(httpclient.RetryBudgetConfig{MaxRetries: 3})
+	newClient := func() *httpclient.Client {
+		return httpclient.New(
+			httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+			httpclient.WithRetries(5, time.Millisecond),
+			httpclient.WithRetryBudget(budget),
+			httpclient.WithErrorClassification(true),
+		)
+	}
+	clients := []*httpclient.Client{newClient(), newClient()}
+
+	const calls = 10
+	errs := make(chan error, calls)
+	var wg sync.WaitGroup
+	for i := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			if err != nil {
+				errs <- err
+				return
+			}
+			_, err = clients[i%len(clients)].Do(context.Background(), req)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.ErrorIs(t, err, httpclient.ErrRetryBudgetExhausted)
+		var httpErr *httpclient.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+		require.Equal(t, shared.KindDependencyFailure, shared.KindOf(err))
+	}
+	// Every call made first attempt, only 3 retries were allowed in total
+	require.EqualValues(t, calls+3, attempts.Load())
+	stats := budget.Stats()
+	require.EqualValues(t, calls, stats.Requests)
+	require.EqualValues(t, 3, stats.Granted)
+	require.EqualValues(t, calls, stats.Denied)
+}
+
+func TestClient_Do_RetryBudgetRatio(t *testing.T) {
+	var fail atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	budget := httpclient.NewRetryBudget(httpclient.RetryBudgetConfig{MaxRetries: 10, Ratio: 0.5})
+	// Drain initial tokens, so only deposits of requests are left
+	for budget.Allow() {
+	}
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+		httpclient.WithRetryBudget(budget),
+	)
+	do := func() error {
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := c.Do(context.Background(), req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// Four successful requests deposit two tokens
+	for range 4 {
+		require.NoError(t, do())
+	}
+	fail.Store(true)
+	// Each failing request deposits half token and spends one on retry:
+	// tokens go 2.5->1.5, 2->1, 1.5->0.5, 1->0, then 0.5 is not enough
+	var exhausted []int
+	for i := range 5 {
+		if err := do(); errors.Is(err, httpclient.ErrRetryBudgetExhausted) {
+			exhausted = append(exhausted, i)
+		}
+	}
+	require.Equal(t, []int{4}, exhausted)
+}
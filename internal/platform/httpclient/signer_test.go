@@ -0,0 +1,144 @@
+package httpclient_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+// signedRequest is request as seen by server.
+type signedRequest struct {
+	timestamp string
+	valid     bool
+}
+
+// verifyHMAC recomputes signature on server side.
+func verifyHMAC(key []byte, r *http.Request, body []byte, headers ...string) bool {
+	sum := sha256.Sum256(body)
+	lines := []string{r.Method, r.URL.RequestURI(), r.Header.Get("X-Timestamp")}
+	for _, h := range headers {
+		lines = append(lines, strings.ToLower(h)+":"+r.Header.Get(h))
+	}
+	lines = append(lines, hex.EncodeToString(sum[:]))
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join(lines, "\n")))
+	got, err := hex.DecodeString(r.Header.Get("X-Signature"))
+	return err == nil && hmac.Equal(got, mac.Sum(nil))
+}
+
+func TestClient_RequestSigner_FreshSignaturePerAttempt(t *testing.T) {
+	key := []byte("secret")
+	var mu sync.Mutex
+	var seen []signedRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		seen = append(seen, signedRequest{
+			timestamp: r.Header.Get("X-Timestamp"),
+			valid:     string(body) == `{"text":"hi"}` && verifyHMAC(key, r, body, "Content-Type", "X-Client"),
+		})
+		attempt := len(seen)
+		mu.Unlock()
+		if attempt == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	clock := time.Unix(1_700_000_000, 0)
+	signer := httpclient.HMACSigner{
+		Key:            key,
+		IncludeHeaders: []string{"Content-Type", "X-Client"},
+		Now: func() time.Time {
+			clock = clock.Add(time.Second)
+			return clock
+		},
+	}
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+		httpclient.WithHeaders(map[string]string{"X-Client": "sttbot"}),
+		httpclient.WithRequestSigner(signer),
+	)
+
+	// Body without GetBody is buffered for replay and hashed once
+	req, err := http.NewRequest(http.MethodPut, srv.URL+"/v1/messages?chat=42", io.NopCloser(strings.NewReader(`{"text":"hi"}`)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Len(t, seen, 2)
+	require.True(t, seen[0].valid, "first attempt must be signed")
+	require.True(t, seen[1].valid, "retry must be signed")
+	require.NotEqual(t, seen[0].timestamp, seen[1].timestamp, "retry must get fresh timestamp")
+}
+
+func TestClient_RequestSigner_GetBodyAndNoBody(t *testing.T) {
+	key := []byte("secret")
+	var valid []bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		valid = append(valid, verifyHMAC(key, r, body))
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRequestSigner(httpclient.HMACSigner{Key: key}),
+	)
+
+	// strings.Reader sets GetBody, body is hashed through it
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/upload", strings.NewReader("payload"))
+	require.NoError(t, err)
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	req, err = http.NewRequest(http.MethodGet, srv.URL+"/items", nil)
+	require.NoError(t, err)
+	resp, err = c.Do(context.Background(), req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Equal(t, []bool{true, true}, valid)
+}
+
+// failingSigner always fails.
+type failingSigner struct{}
+
+func (failingSigner) Sign(*http.Request, []byte) error { return errors.New("no key") }
+
+func TestClient_RequestSigner_ErrorAbortsRequest(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { calls++ }))
+	defer srv.Close()
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(2, time.Millisecond),
+		httpclient.WithRequestSigner(failingSigner{}),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	_, err = c.Do(context.Background(), req)
+	require.ErrorContains(t, err, "sign request: no key")
+	require.Zero(t, calls)
+}
@@ -0,0 +1,127 @@
+package httpclient
+
+import (
+	"context"
+	stdhttp "net/http"
+	"time"
+)
+
+// Span attribute keys set by Client. Names follow OpenTelemetry HTTP semantic
+// conventions where they exist.
+const (
+	AttrHTTPMethod    = "http.request.method"
+	AttrURL           = "url.full"
+	AttrStatusCode    = "http.response.status_code"
+	AttrAttempt       = "http.attempt"
+	AttrAttempts      = "http.attempts"
+	AttrRateLimitWait = "http.rate_limit_wait_ms"
+	AttrRetryWait     = "http.retry_wait_ms"
+)
+
+// Spanner starts tracing spans. It mirrors the subset of OpenTelemetry tracer API
+// used by Client, so package does not depend on otel SDK and an adapter
+// over trace.Tracer takes a few lines.
+// Start must return context carrying new span, so spans started from it become children.
+type Spanner interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation started by Spanner.
+type Span interface {
+	// SetAttributes adds attributes to span. Values are string, int, int64 or bool.
+	SetAttributes(attrs map[string]any)
+	// RecordError records error and marks span as failed.
+	RecordError(err error)
+	// End finishes span. It is called exactly once.
+	End()
+}
+
+// NoopSpanner is Spanner creating spans that record nothing.
+type NoopSpanner struct{}
+
+// Start returns ctx unchanged and no-op span.
+func (NoopSpanner) Start(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(map[string]any) {}
+func (noopSpan) RecordError(error)            {}
+func (noopSpan) End()                         {}
+
+// WithSpanner enables tracing spans. Do starts span "HTTP <method>" per call and child
+// span "HTTP <method> attempt" per attempt, annotated with method, redacted URL, status,
+// attempt number and rate limit and retry waits. Attempt span context is passed to
+// transport with the request, so custom transport can inject trace headers.
+// Nil disables spans.
+func WithSpanner(s Spanner) Option {
+	return func(c *Client) { c.spanner = s }
+}
+
+// startRequestSpan starts span of logical request, or returns nil span if spans are disabled.
+func (c *Client) startRequestSpan(ctx context.Context, req *stdhttp.Request) (context.Context, Span) {
+	if c.spanner == nil {
+		return ctx, nil
+	}
+	ctx, s := c.spanner.Start(ctx, "HTTP "+req.Method)
+	s.SetAttributes(map[string]any{
+		AttrHTTPMethod: req.Method,
+		AttrURL:        c.redactURL(req.URL),
+	})
+	return ctx, s
+}
+
+// endRequestSpan annotates request span with result of Do and ends it.
+func endRequestSpan(s Span, resp *stdhttp.Response, err error, attempts int) {
+	if s == nil {
+		return
+	}
+	attrs := map[string]any{AttrAttempts: attempts}
+	if resp != nil {
+		attrs[AttrStatusCode] = resp.StatusCode
+	}
+	s.SetAttributes(attrs)
+	if err != nil {
+		s.RecordError(err)
+	}
+	s.End()
+}
+
+// startAttemptSpan starts child span of single attempt, or returns nil span if spans are disabled.
+func (c *Client) startAttemptSpan(ctx context.Context, req *stdhttp.Request, attempt int, limitWait time.Duration) (context.Context, Span) {
+	if c.spanner == nil {
+		return ctx, nil
+	}
+	ctx, s := c.spanner.Start(ctx, "HTTP "+req.Method+" attempt")
+	attrs := map[string]any{
+		AttrHTTPMethod: req.Method,
+		AttrURL:        c.redactURL(req.URL),
+		AttrAttempt:    attempt,
+	}
+	if limitWait > 0 {
+		attrs[AttrRateLimitWait] = limitWait.Milliseconds()
+	}
+	s.SetAttributes(attrs)
+	return ctx, s
+}
+
+// annotateAttempt records status and error of attempt.
+func annotateAttempt(s Span, status int, err error) {
+	if s == nil {
+		return
+	}
+	if status != 0 {
+		s.SetAttributes(map[string]any{AttrStatusCode: status})
+	}
+	if err != nil {
+		s.RecordError(err)
+	}
+}
+
+// annotateRetryWait records wait before next attempt.
+func annotateRetryWait(s Span, wait time.Duration) {
+	if s != nil {
+		s.SetAttributes(map[string]any{AttrRetryWait: wait.Milliseconds()})
+	}
+}
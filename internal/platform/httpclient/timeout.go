@@ -0,0 +1,70 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	stdhttp "net/http"
+	"time"
+)
+
+// ErrAttemptTimeout indicates single attempt exceeded timeout set by WithAttemptTimeout.
+// Such attempts are retried; the error also matches context.DeadlineExceeded.
+var ErrAttemptTimeout = errors.New("http: attempt timeout exceeded")
+
+// WithAttemptTimeout bounds each attempt, including reading its response body, with
+// context deadline d, so slow attempt does not starve the following ones. Attempt that
+// hits it fails with ErrAttemptTimeout and is retried like other timeouts, while deadline
+// of ctx passed to Do (or WithOverallTimeout) still aborts the whole call immediately.
+// Client timeout (WithTimeout) applies to each attempt as well; the shorter one wins.
+func WithAttemptTimeout(d time.Duration) Option {
+	return func(c *Client) { c.attemptTimeout = d }
+}
+
+// WithOverallTimeout bounds whole Do call, including all attempts, backoff sleeps and
+// reading response body, as if ctx had deadline d.
+func WithOverallTimeout(d time.Duration) Option {
+	return func(c *Client) { c.overallTimeout = d }
+}
+
+// withTimeout derives context with timeout d. Returned cancel is nil when d is not positive.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, nil
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// attemptTimeoutError wraps err with ErrAttemptTimeout if it was caused by deadline
+// of attempt rather than of ctx bounding the whole call.
+func (c *Client) attemptTimeoutError(ctx context.Context, err error) error {
+	if err == nil || c.attemptTimeout <= 0 || ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrAttemptTimeout, err)
+}
+
+// releaseWithBody calls cancel when response body is closed, or right away without response.
+func releaseWithBody(resp *stdhttp.Response, cancel context.CancelFunc) {
+	if cancel == nil {
+		return
+	}
+	if resp == nil || resp.Body == nil {
+		cancel()
+		return
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+}
+
+// cancelBody releases context of request when body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
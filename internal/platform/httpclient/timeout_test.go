@@ -0,0 +1,125 @@
+package httpclient_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+	"sttbot/internal/shared"
+
+	"github.com/stretchr/testify/require"
+)
+
+// hangingServer hangs first hangAttempts requests until client gives up and answers the rest.
+func hangingServer(t *testing.T, hangAttempts int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= hangAttempts {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &attempts
+}
+
+func TestClient_AttemptTimeout_RetriesSlowAttempt(t *testing.T) {
+	srv, attempts := hangingServer(t, 1)
+
+	var attemptDurations []time.Duration
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(2, time.Millisecond),
+		httpclient.WithAttemptTimeout(2*time.Second),
+		httpclient.WithOverallTimeout(5*time.Second),
+		httpclient.WithResponseHook(func(r *http.Request, resp *http.Response, err error, d time.Duration, attempt int) {
+			attemptDurations = append(attemptDurations, d)
+		}),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	require.Equal(t, int32(2), attempts.Load())
+	require.Len(t, attemptDurations, 2)
+	require.GreaterOrEqual(t, attemptDurations[0], 2*time.Second, "attempt 1 must time out at 2s")
+	require.Less(t, attemptDurations[0], 3*time.Second)
+
+	// Body of successful attempt is readable after Do returns
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "ok", string(body))
+}
+
+func TestClient_AttemptTimeout_AllAttemptsTimeOut(t *testing.T) {
+	srv, attempts := hangingServer(t, 10)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(1, time.Millisecond),
+		httpclient.WithAttemptTimeout(100*time.Millisecond),
+		httpclient.WithErrorClassification(true),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	require.ErrorIs(t, err, httpclient.ErrAttemptTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, shared.KindTimeout, shared.KindOf(err))
+	require.Equal(t, int32(2), attempts.Load())
+}
+
+func TestClient_AttemptTimeout_ParentDeadlineAborts(t *testing.T) {
+	srv, attempts := hangingServer(t, 10)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(3, time.Millisecond),
+		httpclient.WithAttemptTimeout(time.Second),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = c.Do(ctx, req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.False(t, errors.Is(err, httpclient.ErrAttemptTimeout), "parent deadline is not attempt timeout")
+	require.Less(t, time.Since(start), time.Second)
+	require.Equal(t, int32(1), attempts.Load())
+}
+
+func TestClient_OverallTimeout(t *testing.T) {
+	srv, attempts := hangingServer(t, 10)
+
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(5, time.Millisecond),
+		httpclient.WithAttemptTimeout(100*time.Millisecond),
+		httpclient.WithOverallTimeout(250*time.Millisecond),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = c.Do(context.Background(), req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+	require.Less(t, attempts.Load(), int32(6), "overall timeout must stop retries")
+}
@@ -0,0 +1,156 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	stdhttp "net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// TraceInfo contains connection timings of single attempt collected via httptrace.
+// Zero duration means phase did not happen (for example DNS and connect on reused connection).
+type TraceInfo struct {
+	Attempt     int
+	Method      string
+	RedactedURL string
+	// DNS is time spent resolving host.
+	DNS time.Duration
+	// Connect is time from first dial start to last dial completion.
+	Connect time.Duration
+	// TLSHandshake is time spent in TLS handshake.
+	TLSHandshake time.Duration
+	// FirstByte is time from attempt start to first response byte.
+	FirstByte time.Duration
+	// Total is time from attempt start to response headers or error.
+	Total      time.Duration
+	ConnReused bool
+	// StatusCode is response status, 0 if attempt failed without response.
+	StatusCode int
+	Err        error
+}
+
+// Slog returns trace as slog attributes for structured logging.
+func (t TraceInfo) Slog() []slog.Attr {
+	attrs := []slog.Attr{
+		slog.Int("attempt", t.Attempt),
+		slog.String("method", t.Method),
+		slog.String("url", t.RedactedURL),
+		slog.Duration("dns", t.DNS),
+		slog.Duration("connect", t.Connect),
+		slog.Duration("tls", t.TLSHandshake),
+		slog.Duration("first_byte", t.FirstByte),
+		slog.Duration("total", t.Total),
+		slog.Bool("conn_reused", t.ConnReused),
+	}
+	if t.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status", t.StatusCode))
+	}
+	if t.Err != nil {
+		attrs = append(attrs, slog.Any("error", t.Err))
+	}
+	return attrs
+}
+
+// WithTracing sets callback receiving TraceInfo after every attempt, including failed ones.
+// Callback is called synchronously and should not block.
+func WithTracing(cb func(TraceInfo)) Option {
+	return func(c *Client) { c.traceCallback = cb }
+}
+
+// reportTrace passes trace of finished attempt to callback.
+func (c *Client) reportTrace(t *attemptTracer, r *stdhttp.Request, resp *stdhttp.Response, err error, attempt int) {
+	info := t.info()
+	info.Attempt = attempt
+	info.Method = r.Method
+	info.RedactedURL = c.redactURL(r.URL)
+	info.Err = err
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+	}
+	c.traceCallback(info)
+}
+
+// attemptTracer collects httptrace events of single attempt.
+// Events may arrive from transport goroutines, so fields are guarded by mutex.
+// All timestamps come from time.Now and carry monotonic clock reading.
+type attemptTracer struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+	reused       bool
+}
+
+// withTracer attaches tracer to ctx if tracing is enabled.
+func (c *Client) withTracer(ctx context.Context) (context.Context, *attemptTracer) {
+	if c.traceCallback == nil {
+		return ctx, nil
+	}
+	t := &attemptTracer{start: time.Now()}
+	return httptrace.WithClientTrace(ctx, t.clientTrace()), t
+}
+
+// clientTrace returns httptrace hooks recording event times.
+func (t *attemptTracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart, false) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone, true) },
+		ConnectStart: func(string, string) {
+			t.mark(&t.connectStart, false)
+		},
+		ConnectDone: func(string, string, error) {
+			t.mark(&t.connectDone, true)
+		},
+		TLSHandshakeStart: func() { t.mark(&t.tlsStart, false) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mark(&t.tlsDone, true)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.reused = info.Reused
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() { t.mark(&t.firstByte, false) },
+	}
+}
+
+// mark stores current time in field. Start events keep the earliest time,
+// done events (last == true) keep the latest one, so parallel dials are covered.
+func (t *attemptTracer) mark(field *time.Time, last bool) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if field.IsZero() || last {
+		*field = now
+	}
+}
+
+// info builds TraceInfo from collected events.
+func (t *attemptTracer) info() TraceInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return TraceInfo{
+		DNS:          span(t.dnsStart, t.dnsDone),
+		Connect:      span(t.connectStart, t.connectDone),
+		TLSHandshake: span(t.tlsStart, t.tlsDone),
+		FirstByte:    span(t.start, t.firstByte),
+		Total:        time.Since(t.start),
+		ConnReused:   t.reused,
+	}
+}
+
+// span returns duration between start and end, or 0 if either is missing.
+func span(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start)
+}
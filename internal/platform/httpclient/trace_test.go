@@ -0,0 +1,114 @@
+package httpclient_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	httpclient "sttbot/internal/platform/httpclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+// traceRecorder collects TraceInfo passed to tracing callback.
+type traceRecorder struct {
+	mu     sync.Mutex
+	traces []httpclient.TraceInfo
+}
+
+func (r *traceRecorder) record(t httpclient.TraceInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.traces = append(r.traces, t)
+}
+
+func (r *traceRecorder) all() []httpclient.TraceInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]httpclient.TraceInfo(nil), r.traces...)
+}
+
+func TestClient_Tracing_PerAttempt(t *testing.T) {
+	var attempts int
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	rec := &traceRecorder{}
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithTransport(srv.Client().Transport),
+		httpclient.WithRetries(1, 0),
+		httpclient.WithTracing(rec.record),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	traces := rec.all()
+	require.Len(t, traces, 2)
+
+	first := traces[0]
+	require.Equal(t, 1, first.Attempt)
+	require.Equal(t, http.MethodGet, first.Method)
+	require.Equal(t, http.StatusInternalServerError, first.StatusCode)
+	require.False(t, first.ConnReused)
+	require.Positive(t, first.Connect)
+	require.Positive(t, first.TLSHandshake)
+	require.Positive(t, first.FirstByte)
+	require.GreaterOrEqual(t, first.Total, first.FirstByte)
+
+	second := traces[1]
+	require.Equal(t, 2, second.Attempt)
+	require.Equal(t, http.StatusOK, second.StatusCode)
+	require.True(t, second.ConnReused)
+	require.Zero(t, second.Connect)
+	require.Zero(t, second.TLSHandshake)
+}
+
+func TestClient_Tracing_FailedAttempt(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	rec := &traceRecorder{}
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithRetries(0, 0),
+		httpclient.WithTracing(rec.record),
+	)
+	req, err := http.NewRequest(http.MethodGet, "http://"+addr, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	require.Error(t, err)
+
+	traces := rec.all()
+	require.Len(t, traces, 1)
+	require.Error(t, traces[0].Err)
+	require.Zero(t, traces[0].StatusCode)
+	require.Zero(t, traces[0].FirstByte)
+
+	attrs := traces[0].Slog()
+	keys := make([]string, 0, len(attrs))
+	for _, a := range attrs {
+		keys = append(keys, a.Key)
+	}
+	require.Contains(t, keys, "error")
+	require.NotContains(t, keys, "status")
+}
@@ -0,0 +1,114 @@
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	stdhttp "net/http"
+	"time"
+
+	"sttbot/internal/shared"
+)
+
+// ValidationError is returned by Do when validator set by WithResponseValidator rejected
+// response of the last attempt. Response body is drained and closed.
+type ValidationError struct {
+	Method     string
+	URL        string // redacted URL
+	StatusCode int
+	Err        error // error returned by validator
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s %s: invalid response (status %d): %v", e.Method, e.URL, e.StatusCode, e.Err)
+}
+
+// Unwrap returns error returned by validator.
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// retryAfterError carries retry delay requested by validator.
+type retryAfterError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryAfterError) Error() string { return e.err.Error() }
+
+func (e *retryAfterError) Unwrap() error { return e.err }
+
+// RetryAfter marks validator error as retryable after delay d, like Retry-After header
+// of 429 response. Delay is capped by WithMaxBackoff and context deadline as usual.
+// Returns nil if err is nil.
+func RetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return shared.MarkRetryable(&retryAfterError{err: err, delay: max(d, 0)})
+}
+
+// WithResponseValidator sets f to check every response after successful round trip,
+// before retry decision. Use it for APIs reporting errors in body of 200 responses,
+// like Telegram Bot API {"ok":false,...}. If f returns error, attempt is treated as failed:
+// error is checked by WithValidationRetryable predicate, response body is drained and
+// attempt is retried or Do returns *ValidationError wrapping it.
+// Errors marked with shared.MarkRetryable (or shared kinds Timeout and DependencyFailure)
+// are retried by default; RetryAfter sets retry delay.
+//
+// f may read response body: up to max replay body size (see WithMaxReplayBodySize) is
+// buffered before the call, and caller of Do receives the whole body unconsumed.
+// Validator runs for every Do call including Download, so it should skip responses
+// it does not understand, e.g. by Content-Type.
+func WithResponseValidator(f func(*stdhttp.Response) error) Option {
+	return func(c *Client) { c.responseValidator = f }
+}
+
+// WithValidationRetryable sets predicate deciding whether error returned by response
+// validator is retried. By default retry policy (see WithRetryPolicy) is called with
+// nil response and validator error.
+func WithValidationRetryable(f func(error) bool) Option {
+	return func(c *Client) { c.validationRetryable = f }
+}
+
+// validateResponse buffers body prefix, runs validator and restores body for caller.
+// Error reading body is returned as readErr and handled like transport error.
+func (c *Client) validateResponse(resp *stdhttp.Response) (validationErr, readErr error) {
+	var buf []byte
+	orig := resp.Body
+	if orig != nil && orig != stdhttp.NoBody {
+		var r io.Reader = orig
+		if c.maxReplayBody > 0 {
+			r = io.LimitReader(orig, c.maxReplayBody)
+		}
+		var err error
+		buf, err = io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		// Caller reads buffered prefix followed by the rest of the body
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(buf), orig), orig}
+	}
+
+	validated := *resp
+	validated.Body = io.NopCloser(bytes.NewReader(buf))
+	return c.responseValidator(&validated), nil
+}
+
+// classifyValidationError returns retry delay and whether validator error should be retried.
+func (c *Client) classifyValidationError(err error) (time.Duration, bool) {
+	var retry bool
+	var delay time.Duration
+	if c.validationRetryable != nil {
+		retry = c.validationRetryable(err)
+	} else {
+		delay, retry = c.retryPolicy(nil, err)
+	}
+	var ra *retryAfterError
+	if retry && errors.As(err, &ra) && ra.delay > 0 {
+		delay = ra.delay
+	}
+	return delay, retry
+}
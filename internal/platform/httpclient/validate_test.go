@@ -0,0 +1,152 @@
+package httpclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	httpclient "sttbot/internal/platform/httpclient"
+	"sttbot/internal/shared"
+
+	"github.com/stretchr/testify/require"
+)
+
+// telegramError is error reported by Telegram Bot API in 200 response body.
+type telegramError struct {
+	Code        int
+	Description string
+}
+
+func (e *telegramError) Error() string {
+	return fmt.Sprintf("telegram: %d %s", e.Code, e.Description)
+}
+
+// telegramValidator turns {"ok":false,...} bodies into errors; 429 is retried after parameters.retry_after.
+func telegramValidator(resp *http.Response) error {
+	var body struct {
+		OK          bool   `json:"ok"`
+		ErrorCode   int    `json:"error_code"`
+		Description string `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("decode telegram response: %w", err)
+	}
+	if body.OK {
+		return nil
+	}
+	err := &telegramError{Code: body.ErrorCode, Description: body.Description}
+	if body.ErrorCode == http.StatusTooManyRequests {
+		return httpclient.RetryAfter(err, time.Duration(body.Parameters.RetryAfter)*time.Second)
+	}
+	return err
+}
+
+// telegramServer replies with bodies in order, repeating the last one.
+func telegramServer(t *testing.T, bodies ...string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(attempts.Add(1))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, bodies[min(n, len(bodies))-1])
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &attempts
+}
+
+func TestClient_ResponseValidator_TelegramRetryAfter(t *testing.T) {
+	const success = `{"ok":true,"result":{"message_id":1}}`
+	srv, attempts := telegramServer(t,
+		`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 1","parameters":{"retry_after":1}}`,
+		success,
+	)
+	c := newTestClient(httpclient.WithRetries(2, time.Millisecond), httpclient.WithResponseValidator(telegramValidator))
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+	require.Equal(t, int32(2), attempts.Load())
+
+	// Validator read body, caller still receives it whole
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, success, string(body))
+}
+
+func TestClient_ResponseValidator_NotRetried(t *testing.T) {
+	srv, attempts := telegramServer(t, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+	c := newTestClient(httpclient.WithRetries(2, time.Millisecond), httpclient.WithResponseValidator(telegramValidator), httpclient.WithErrorClassification(true))
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(context.Background(), req)
+	require.Nil(t, resp)
+	require.Equal(t, int32(1), attempts.Load())
+
+	var validationErr *httpclient.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, http.StatusOK, validationErr.StatusCode)
+	var tgErr *telegramError
+	require.ErrorAs(t, err, &tgErr)
+	require.Equal(t, 400, tgErr.Code)
+	require.Equal(t, shared.KindDependencyFailure, shared.KindOf(err))
+}
+
+func TestClient_ResponseValidator_RetriesExhausted(t *testing.T) {
+	srv, attempts := telegramServer(t, `{"ok":false,"error_code":502,"description":"Bad Gateway"}`)
+	c := newTestClient(httpclient.WithRetries(2, time.Millisecond), httpclient.WithResponseValidator(telegramValidator), httpclient.WithValidationRetryable(func(err error) bool {
+		var tgErr *telegramError
+		return errors.As(err, &tgErr) && tgErr.Code >= 500
+	}))
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = c.Do(context.Background(), req)
+	var validationErr *httpclient.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.ErrorContains(t, err, "Bad Gateway")
+	require.Equal(t, int32(3), attempts.Load())
+}
+
+func TestClient_ResponseValidator_BodyLargerThanReplayLimit(t *testing.T) {
+	large := `{"ok":true,"result":"` + strings.Repeat("x", 64) + `"}`
+	srv, _ := telegramServer(t, large)
+
+	var seen string
+	c := httpclient.New(
+		httpclient.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		httpclient.WithMaxReplayBodySize(8),
+		httpclient.WithResponseValidator(func(resp *http.Response) error {
+			b, err := io.ReadAll(resp.Body)
+			seen = string(b)
+			return err
+		}),
+	)
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := c.Do(context.Background(), req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, large[:8], seen)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, large, string(body))
+}
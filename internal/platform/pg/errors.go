@@ -0,0 +1,114 @@
+package pg
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"sttbot/internal/shared"
+)
+
+const (
+	// sqlStateUniqueViolation - SQLSTATE 23505, нарушение UNIQUE
+	sqlStateUniqueViolation = "23505"
+	// sqlStateForeignKeyViolation - SQLSTATE 23503, нарушение FOREIGN KEY
+	sqlStateForeignKeyViolation = "23503"
+	// sqlStateNotNullViolation - SQLSTATE 23502, нарушение NOT NULL
+	sqlStateNotNullViolation = "23502"
+	// sqlStateCheckViolation - SQLSTATE 23514, нарушение CHECK
+	sqlStateCheckViolation = "23514"
+	// sqlStateExclusionViolation - SQLSTATE 23P01, нарушение EXCLUDE
+	sqlStateExclusionViolation = "23P01"
+	// sqlStateQueryCanceled - SQLSTATE 57014, запрос отменён
+	sqlStateQueryCanceled = "57014"
+	// sqlStateLockNotAvailable - SQLSTATE 55P03, не дождались блокировки (lock_timeout)
+	sqlStateLockNotAvailable = "55P03"
+)
+
+// SQLState возвращает код SQLSTATE из *pgconn.PgError в цепочке ошибки.
+// Возвращает false, если ошибка не пришла от сервера PostgreSQL.
+func SQLState(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return "", false
+	}
+	return pgErr.Code, true
+}
+
+// ClassifyError помечает ошибку pgx видом из пакета shared:
+//   - нарушение UNIQUE (23505) и EXCLUDE (23P01) - shared.KindConflict
+//   - нарушение FOREIGN KEY, NOT NULL, CHECK (23503, 23502, 23514) и ошибки данных
+//     (класс 22) - shared.KindValidation
+//   - конфликт сериализации и взаимоблокировка (40001, 40P01) - shared.KindConflict
+//     с пометкой shared.MarkRetryable: транзакцию можно повторить целиком
+//   - отмена запроса на сервере (57014: statement_timeout, pg_cancel_backend) и
+//     lock_timeout (55P03) - shared.KindTimeout. Код 57014 не говорит, кто отменил
+//     запрос, поэтому context.Canceled не добавляется: отмена контекста вызывающего
+//     распознаётся только по context.Canceled, уже находящемуся в цепочке
+//   - ошибки соединения (класс 08, остановка сервера 57P01-57P03, сетевые ошибки) -
+//     shared.KindDependencyFailure
+//   - pgx.ErrNoRows - shared.KindNotFound
+//   - остальные ошибки - shared.KindInternal
+//
+// Исходная ошибка сохраняется в цепочке и доступна через errors.Is/errors.As.
+// Уже классифицированные ошибки (включая отмену и таймаут контекста) возвращаются без изменений.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if shared.KindOf(err) != shared.KindUnknown {
+		return err
+	}
+
+	marked := shared.MarkKind(err, errorKind(err))
+	if IsSerializationError(err) {
+		return shared.MarkRetryable(marked)
+	}
+	return marked
+}
+
+// errorKind определяет вид ошибки по SQLSTATE, а при его отсутствии - по типу ошибки.
+func errorKind(err error) shared.Kind {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return shared.KindNotFound
+	}
+
+	if code, ok := SQLState(err); ok {
+		switch {
+		case code == sqlStateUniqueViolation, code == sqlStateExclusionViolation:
+			return shared.KindConflict
+		case code == sqlStateForeignKeyViolation, code == sqlStateNotNullViolation,
+			code == sqlStateCheckViolation, strings.HasPrefix(code, "22"):
+			return shared.KindValidation
+		case code == sqlStateSerializationFailure, code == sqlStateDeadlockDetected:
+			return shared.KindConflict
+		case code == sqlStateQueryCanceled, code == sqlStateLockNotAvailable:
+			return shared.KindTimeout
+		case strings.HasPrefix(code, "08"), code == "57P01", code == "57P02", code == "57P03":
+			return shared.KindDependencyFailure
+		}
+		return shared.KindInternal
+	}
+
+	if isConnectionError(err) {
+		return shared.KindDependencyFailure
+	}
+	return shared.KindInternal
+}
+
+// isConnectionError проверяет, является ли ошибка ошибкой установки или потери соединения.
+func isConnectionError(err error) bool {
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, net.ErrClosed) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,129 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"sttbot/internal/shared"
+)
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		err       error
+		kind      shared.Kind
+		retryable bool
+	}{
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, kind: shared.KindConflict},
+		{name: "exclusion violation", err: &pgconn.PgError{Code: "23P01"}, kind: shared.KindConflict},
+		{name: "foreign key violation", err: &pgconn.PgError{Code: "23503"}, kind: shared.KindValidation},
+		{name: "not null violation", err: &pgconn.PgError{Code: "23502"}, kind: shared.KindValidation},
+		{name: "check violation", err: &pgconn.PgError{Code: "23514"}, kind: shared.KindValidation},
+		{name: "invalid text representation", err: &pgconn.PgError{Code: "22P02"}, kind: shared.KindValidation},
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, kind: shared.KindConflict, retryable: true},
+		{name: "deadlock", err: fmt.Errorf("update: %w", &pgconn.PgError{Code: "40P01"}), kind: shared.KindConflict, retryable: true},
+		{
+			name:      "statement timeout",
+			err:       &pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"},
+			kind:      shared.KindTimeout,
+			retryable: true,
+		},
+		{
+			name:      "canceled by user request",
+			err:       &pgconn.PgError{Code: "57014", Message: "canceling statement due to user request"},
+			kind:      shared.KindTimeout,
+			retryable: true,
+		},
+		{name: "lock timeout", err: &pgconn.PgError{Code: "55P03"}, kind: shared.KindTimeout, retryable: true},
+		{name: "connection failure", err: &pgconn.PgError{Code: "08006"}, kind: shared.KindDependencyFailure, retryable: true},
+		{name: "admin shutdown", err: &pgconn.PgError{Code: "57P01"}, kind: shared.KindDependencyFailure, retryable: true},
+		{name: "connection refused", err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, kind: shared.KindDependencyFailure, retryable: true},
+		{name: "unexpected eof", err: fmt.Errorf("read: %w", io.ErrUnexpectedEOF), kind: shared.KindDependencyFailure, retryable: true},
+		{name: "no rows", err: pgx.ErrNoRows, kind: shared.KindNotFound},
+		{name: "wrapped no rows", err: fmt.Errorf("get user: %w", pgx.ErrNoRows), kind: shared.KindNotFound},
+		{name: "syntax error", err: &pgconn.PgError{Code: "42601"}, kind: shared.KindInternal},
+		{name: "plain error", err: errors.New("boom"), kind: shared.KindInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			classified := ClassifyError(tt.err)
+			if got := shared.KindOf(classified); got != tt.kind {
+				t.Errorf("KindOf(ClassifyError(%v)) = %v, want %v", tt.err, got, tt.kind)
+			}
+			if got := shared.IsRetryable(classified); got != tt.retryable {
+				t.Errorf("IsRetryable = %v, want %v", got, tt.retryable)
+			}
+			if !errors.Is(classified, tt.err) {
+				t.Error("original error must stay in the chain")
+			}
+		})
+	}
+}
+
+func TestClassifyError_PreservesPgError(t *testing.T) {
+	t.Parallel()
+
+	orig := &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"}
+	classified := ClassifyError(fmt.Errorf("insert user: %w", orig))
+
+	var pgErr *pgconn.PgError
+	if !errors.As(classified, &pgErr) || pgErr != orig {
+		t.Fatal("errors.As must return the original *pgconn.PgError")
+	}
+	if !errors.Is(classified, shared.ErrConflict) {
+		t.Error("expected errors.Is(err, shared.ErrConflict)")
+	}
+}
+
+func TestClassifyError_AlreadyClassified(t *testing.T) {
+	t.Parallel()
+
+	if ClassifyError(nil) != nil {
+		t.Error("ClassifyError(nil) must return nil")
+	}
+
+	for _, err := range []error{
+		context.Canceled,
+		fmt.Errorf("query: %w", context.DeadlineExceeded),
+		shared.MarkKind(&pgconn.PgError{Code: "23505"}, shared.KindValidation),
+	} {
+		if got := ClassifyError(err); got != err {
+			t.Errorf("ClassifyError(%v) = %v, want unchanged", err, got)
+		}
+	}
+
+	// Отмена запроса на сервере (pg_cancel_backend) - не отмена контекста вызывающего
+	canceled := ClassifyError(&pgconn.PgError{Code: "57014", Message: "canceling statement due to user request"})
+	if errors.Is(canceled, context.Canceled) || shared.IsCanceled(canceled) {
+		t.Errorf("server-side cancel must not match context.Canceled, got %v", canceled)
+	}
+}
+
+func TestSQLState(t *testing.T) {
+	t.Parallel()
+
+	if code, ok := SQLState(fmt.Errorf("wrap: %w", &pgconn.PgError{Code: "23505"})); !ok || code != "23505" {
+		t.Errorf("SQLState = %q, %v; want 23505, true", code, ok)
+	}
+	if code, ok := SQLState(ClassifyError(&pgconn.PgError{Code: "40001"})); !ok || code != "40001" {
+		t.Errorf("SQLState of classified error = %q, %v; want 40001, true", code, ok)
+	}
+	if _, ok := SQLState(pgx.ErrNoRows); ok {
+		t.Error("SQLState(pgx.ErrNoRows) must report false")
+	}
+	if _, ok := SQLState(nil); ok {
+		t.Error("SQLState(nil) must report false")
+	}
+}
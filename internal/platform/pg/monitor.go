@@ -0,0 +1,239 @@
+package pg
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// State определяет состояние доступности БД.
+type State int
+
+const (
+	// StateHealthy - БД доступна
+	StateHealthy State = iota
+	// StateDegraded - проверки начали завершаться ошибками
+	StateDegraded
+	// StateDown - БД недоступна
+	StateDown
+)
+
+// String возвращает строковое представление состояния.
+func (s State) String() string {
+	switch s {
+	case StateHealthy:
+		return "healthy"
+	case StateDegraded:
+		return "degraded"
+	case StateDown:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
+// MonitorOptions содержит настройки фонового мониторинга БД.
+type MonitorOptions struct {
+	// HealthCheck - тайминги проверок: InitialInterval - интервал между проверками,
+	// PingTimeout - таймаут одной проверки. При ошибках интервал увеличивается
+	// согласно Strategy до MaxInterval. MaxRetries не используется.
+	HealthCheck HealthCheckOptions
+	// DegradedAfter - количество ошибок подряд для перехода в StateDegraded
+	DegradedAfter int
+	// DownAfter - количество ошибок подряд для перехода в StateDown
+	DownAfter int
+	// RecoverAfter - количество успешных проверок подряд для возврата в StateHealthy (защита от флаппинга)
+	RecoverAfter int
+	// OnStateChange - вызывается при смене состояния (необязательно)
+	OnStateChange func(old, new State, lastErr error)
+}
+
+// DefaultMonitorOptions возвращает настройки мониторинга по умолчанию.
+func DefaultMonitorOptions() MonitorOptions {
+	healthCheck := DefaultHealthCheckOptions()
+	healthCheck.InitialInterval = 10 * time.Second
+	healthCheck.MaxInterval = time.Minute
+
+	return MonitorOptions{
+		HealthCheck:   healthCheck,
+		DegradedAfter: 1,
+		DownAfter:     3,
+		RecoverAfter:  2,
+	}
+}
+
+// Monitor периодически проверяет доступность БД и отслеживает её состояние.
+// Может работать в собственной goroutine (Start/Stop) или как задача планировщика (Check).
+// Начальное состояние - StateHealthy.
+type Monitor struct {
+	pool  *pgxpool.Pool
+	opts  MonitorOptions
+	check func(ctx context.Context) error
+
+	mu        sync.Mutex
+	state     State
+	failures  int // ошибок подряд, для перехода в StateDegraded и StateDown
+	successes int // успехов подряд, для возврата в StateHealthy
+	lastErr   error
+	lastStats DBStats
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewMonitor создает монитор для пула подключений.
+// Незаданные пороги заменяются значениями из DefaultMonitorOptions.
+func NewMonitor(pool *pgxpool.Pool, opts MonitorOptions) *Monitor {
+	defaults := DefaultMonitorOptions()
+	if opts.HealthCheck.InitialInterval <= 0 {
+		opts.HealthCheck = defaults.HealthCheck
+	}
+	if opts.DegradedAfter <= 0 {
+		opts.DegradedAfter = defaults.DegradedAfter
+	}
+	if opts.DownAfter < opts.DegradedAfter {
+		opts.DownAfter = max(defaults.DownAfter, opts.DegradedAfter)
+	}
+	if opts.RecoverAfter <= 0 {
+		opts.RecoverAfter = defaults.RecoverAfter
+	}
+
+	m := &Monitor{pool: pool, opts: opts}
+	m.check = m.pingPool
+	return m
+}
+
+// Start запускает периодические проверки в отдельной goroutine.
+// Проверки прекращаются при отмене ctx или вызове Stop.
+func (m *Monitor) Start(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		return // Уже запущен
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	m.cancel = cancel
+	m.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		m.Run(ctx)
+	}(m.done)
+}
+
+// Stop останавливает проверки, запущенные через Start, и дожидается их завершения.
+func (m *Monitor) Stop() {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.cancel, m.done = nil, nil
+	m.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// Run выполняет проверки до отмены ctx. Блокирует вызывающую goroutine.
+func (m *Monitor) Run(ctx context.Context) {
+	interval := m.opts.HealthCheck.InitialInterval
+	for {
+		if err := m.Check(ctx); err != nil {
+			interval = calculateNextInterval(interval, m.opts.HealthCheck)
+		} else {
+			interval = m.opts.HealthCheck.InitialInterval
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Check выполняет одну проверку и обновляет состояние.
+// Совместим с scheduler.JobFunc для запуска по расписанию.
+func (m *Monitor) Check(ctx context.Context) error {
+	err := m.check(ctx)
+	// Отмена контекста мониторинга не говорит о состоянии БД
+	if err != nil && ctx.Err() != nil {
+		return err
+	}
+
+	m.record(err)
+	return err
+}
+
+// Current возвращает текущее состояние БД.
+func (m *Monitor) Current() State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// LastStats возвращает статистику пула на момент последней проверки.
+func (m *Monitor) LastStats() DBStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastStats
+}
+
+// LastError возвращает ошибку последней проверки (nil при успехе).
+func (m *Monitor) LastError() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastErr
+}
+
+// record обновляет счётчики и состояние по результату проверки.
+func (m *Monitor) record(err error) {
+	m.mu.Lock()
+	old := m.state
+	m.lastErr = err
+	m.lastStats = GetPoolStats(m.pool)
+
+	if err != nil {
+		m.successes = 0
+		m.failures++
+		switch {
+		case m.failures >= m.opts.DownAfter:
+			m.state = StateDown
+		case m.failures >= m.opts.DegradedAfter && m.state == StateHealthy:
+			m.state = StateDegraded
+		}
+	} else {
+		// Любой успех прерывает серию ошибок. Состояние при этом только повышается
+		// до StateHealthy после RecoverAfter успехов подряд, поэтому единичный успех
+		// не понижает StateDown до StateDegraded
+		m.failures = 0
+		m.successes++
+		if m.successes >= m.opts.RecoverAfter {
+			m.state = StateHealthy
+		}
+	}
+
+	state := m.state
+	m.mu.Unlock()
+
+	if state != old && m.opts.OnStateChange != nil {
+		m.opts.OnStateChange(old, state, err)
+	}
+}
+
+// pingPool проверяет пул через HealthCheckPool с таймаутом PingTimeout.
+func (m *Monitor) pingPool(ctx context.Context) error {
+	if m.opts.HealthCheck.PingTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.opts.HealthCheck.PingTimeout)
+		defer cancel()
+	}
+	return HealthCheckPool(ctx, m.pool)
+}
@@ -0,0 +1,230 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type stateChange struct {
+	old, new State
+	err      error
+}
+
+// newTestMonitor создает монитор с подменённой проверкой, возвращающей ошибки из results по очереди.
+func newTestMonitor(opts MonitorOptions, results ...error) (*Monitor, *[]stateChange) {
+	var changes []stateChange
+	opts.OnStateChange = func(old, new State, lastErr error) {
+		changes = append(changes, stateChange{old: old, new: new, err: lastErr})
+	}
+
+	m := NewMonitor(nil, opts)
+	i := 0
+	m.check = func(ctx context.Context) error {
+		err := results[i]
+		i++
+		return err
+	}
+	return m, &changes
+}
+
+func TestState_String(t *testing.T) {
+	t.Parallel()
+
+	tests := map[State]string{
+		StateHealthy:  "healthy",
+		StateDegraded: "degraded",
+		StateDown:     "down",
+		State(42):     "unknown",
+	}
+	for state, expected := range tests {
+		if got := state.String(); got != expected {
+			t.Errorf("State(%d).String() = %q, want %q", int(state), got, expected)
+		}
+	}
+}
+
+func TestNewMonitor_Defaults(t *testing.T) {
+	t.Parallel()
+
+	m := NewMonitor(nil, MonitorOptions{DegradedAfter: 5})
+	defaults := DefaultMonitorOptions()
+
+	if m.opts.HealthCheck != defaults.HealthCheck {
+		t.Errorf("HealthCheck = %+v, want defaults", m.opts.HealthCheck)
+	}
+	if m.opts.DownAfter < m.opts.DegradedAfter {
+		t.Errorf("DownAfter = %d must not be less than DegradedAfter = %d", m.opts.DownAfter, m.opts.DegradedAfter)
+	}
+	if m.opts.RecoverAfter != defaults.RecoverAfter {
+		t.Errorf("RecoverAfter = %d, want %d", m.opts.RecoverAfter, defaults.RecoverAfter)
+	}
+	if m.Current() != StateHealthy {
+		t.Errorf("initial state = %v, want healthy", m.Current())
+	}
+}
+
+func TestMonitor_StateTransitions(t *testing.T) {
+	t.Parallel()
+
+	errDown := errors.New("connection refused")
+	opts := MonitorOptions{DegradedAfter: 1, DownAfter: 3, RecoverAfter: 1}
+	m, changes := newTestMonitor(opts, errDown, errDown, errDown, nil)
+	ctx := context.Background()
+
+	expected := []State{StateDegraded, StateDegraded, StateDown, StateHealthy}
+	for i, want := range expected {
+		_ = m.Check(ctx)
+		if got := m.Current(); got != want {
+			t.Fatalf("after check %d state = %v, want %v", i+1, got, want)
+		}
+	}
+
+	want := []stateChange{
+		{old: StateHealthy, new: StateDegraded, err: errDown},
+		{old: StateDegraded, new: StateDown, err: errDown},
+		{old: StateDown, new: StateHealthy, err: nil},
+	}
+	if len(*changes) != len(want) {
+		t.Fatalf("state changes = %+v, want %+v", *changes, want)
+	}
+	for i := range want {
+		if (*changes)[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, (*changes)[i], want[i])
+		}
+	}
+	if m.LastError() != nil {
+		t.Errorf("LastError = %v, want nil", m.LastError())
+	}
+}
+
+func TestMonitor_FlappingProtection(t *testing.T) {
+	t.Parallel()
+
+	errDown := errors.New("timeout")
+	opts := MonitorOptions{DegradedAfter: 1, DownAfter: 2, RecoverAfter: 3}
+	// Падение, затем чередование успехов и ошибок, затем три успеха подряд
+	m, changes := newTestMonitor(opts,
+		errDown, errDown, // down
+		nil, nil, errDown, // два успеха недостаточно, ошибка сбрасывает счётчик
+		nil, errDown, // одиночный успех не понижает down до degraded
+		nil, nil, nil, // восстановление
+	)
+	ctx := context.Background()
+
+	expected := []State{
+		StateDegraded, StateDown,
+		StateDown, StateDown, StateDown,
+		StateDown, StateDown,
+		StateDown, StateDown, StateHealthy,
+	}
+	for i, want := range expected {
+		_ = m.Check(ctx)
+		if got := m.Current(); got != want {
+			t.Fatalf("after check %d state = %v, want %v", i+1, got, want)
+		}
+	}
+
+	if len(*changes) != 3 {
+		t.Errorf("state changes = %+v, want 3 changes", *changes)
+	}
+}
+
+func TestMonitor_IntermittentFailuresInDegraded(t *testing.T) {
+	t.Parallel()
+
+	errDown := errors.New("timeout")
+	opts := MonitorOptions{DegradedAfter: 1, DownAfter: 3, RecoverAfter: 2}
+	// Ошибки чередуются с успехами: трёх ошибок подряд нет, поэтому до down не доходит
+	m, changes := newTestMonitor(opts, errDown, nil, errDown, nil, errDown, errDown, errDown)
+	ctx := context.Background()
+
+	expected := []State{
+		StateDegraded, StateDegraded, StateDegraded, StateDegraded, StateDegraded,
+		StateDegraded, StateDown,
+	}
+	for i, want := range expected {
+		_ = m.Check(ctx)
+		if got := m.Current(); got != want {
+			t.Fatalf("after check %d state = %v, want %v", i+1, got, want)
+		}
+	}
+
+	if len(*changes) != 2 {
+		t.Errorf("state changes = %+v, want 2 changes", *changes)
+	}
+}
+
+func TestMonitor_CanceledCheckDoesNotChangeState(t *testing.T) {
+	t.Parallel()
+
+	m, changes := newTestMonitor(MonitorOptions{}, context.Canceled)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := m.Check(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Check() = %v, want context.Canceled", err)
+	}
+	if m.Current() != StateHealthy || len(*changes) != 0 {
+		t.Errorf("state = %v, changes = %+v; canceled check must not affect state", m.Current(), *changes)
+	}
+}
+
+func TestMonitor_StartStop(t *testing.T) {
+	t.Parallel()
+
+	opts := MonitorOptions{
+		HealthCheck: HealthCheckOptions{
+			InitialInterval: 5 * time.Millisecond,
+			MaxInterval:     10 * time.Millisecond,
+			Strategy:        ExponentialWait,
+		},
+		DegradedAfter: 1,
+		DownAfter:     2,
+	}
+
+	var mu sync.Mutex
+	var changes []State
+	opts.OnStateChange = func(old, new State, lastErr error) {
+		mu.Lock()
+		changes = append(changes, new)
+		mu.Unlock()
+	}
+
+	m := NewMonitor(nil, opts)
+	var calls atomic.Int32
+	m.check = func(ctx context.Context) error {
+		calls.Add(1)
+		return errors.New("unreachable")
+	}
+
+	m.Start(context.Background())
+	m.Start(context.Background()) // повторный запуск игнорируется
+
+	deadline := time.Now().Add(2 * time.Second)
+	for m.Current() != StateDown && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	m.Stop()
+	m.Stop() // повторная остановка безопасна
+
+	if m.Current() != StateDown {
+		t.Fatalf("state = %v, want down", m.Current())
+	}
+
+	stopped := calls.Load()
+	time.Sleep(30 * time.Millisecond)
+	if calls.Load() != stopped {
+		t.Error("checks continued after Stop")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) != 2 || changes[0] != StateDegraded || changes[1] != StateDown {
+		t.Errorf("state changes = %v, want [degraded down]", changes)
+	}
+}
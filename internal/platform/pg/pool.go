@@ -2,8 +2,11 @@ package pg
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -21,6 +24,10 @@ type PoolOptions struct {
 	MaxConnIdleTime time.Duration
 	// PingTimeout - таймаут для проверки соединения при создании пула
 	PingTimeout time.Duration
+	// HealthCheck - настройки ожидания доступности БД в NewPool (nil - DefaultHealthCheckOptions)
+	HealthCheck *HealthCheckOptions
+	// AfterConnect - вызывается для каждого нового соединения (например, SET search_path или timezone)
+	AfterConnect func(ctx context.Context, conn *pgx.Conn) error
 }
 
 // DefaultPoolOptions возвращает настройки по умолчанию, оптимизированные для Telegram-бота.
@@ -35,18 +42,77 @@ func DefaultPoolOptions() PoolOptions {
 	}
 }
 
-// NewPool создает новый пул подключений к PostgreSQL с настройками по умолчанию.
-// Параметры пула оптимизированы для типичной нагрузки Telegram-бота.
-func NewPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
-	return NewPoolWithOptions(ctx, dsn, DefaultPoolOptions())
+// withDefaults заполняет нулевые поля значениями из DefaultPoolOptions.
+// MinConns не заполняется: ноль - допустимое значение.
+func (o PoolOptions) withDefaults() PoolOptions {
+	def := DefaultPoolOptions()
+	if o.MaxConns <= 0 {
+		o.MaxConns = def.MaxConns
+	}
+	if o.HealthCheckPeriod <= 0 {
+		o.HealthCheckPeriod = def.HealthCheckPeriod
+	}
+	if o.MaxConnLifetime <= 0 {
+		o.MaxConnLifetime = def.MaxConnLifetime
+	}
+	if o.MaxConnIdleTime <= 0 {
+		o.MaxConnIdleTime = def.MaxConnIdleTime
+	}
+	if o.PingTimeout <= 0 {
+		o.PingTimeout = def.PingTimeout
+	}
+	return o
+}
+
+// NewPool создает пул подключений к PostgreSQL по структурированной конфигурации.
+// Проверяет конфигурацию через ValidateConfig и дожидается доступности БД через WaitForDB
+// с настройками opts.HealthCheck. Незаданные поля opts берутся из DefaultPoolOptions
+// (см. NewPoolWithOptions). При любой ошибке созданный пул закрывается.
+func NewPool(ctx context.Context, cfg DSNConfig, opts PoolOptions) (*pgxpool.Pool, error) {
+	if err := ValidateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("invalid database config: %w", err)
+	}
+	return waitAndConnect(ctx, BuildDSN(cfg), opts)
+}
+
+// NewPoolFromDSN создает пул подключений аналогично NewPool по строке подключения.
+// URL (postgres://...) разбирается через ParseDSN и проверяется ValidateConfig, поэтому
+// должен содержать пользователя и имя базы. Строка вида "host=... dbname=..." передаётся
+// в pgxpool.ParseConfig без проверки, как в NewPoolWithOptions, но с ожиданием доступности БД.
+func NewPoolFromDSN(ctx context.Context, dsn string, opts PoolOptions) (*pgxpool.Pool, error) {
+	if !strings.Contains(dsn, "://") {
+		return waitAndConnect(ctx, dsn, opts)
+	}
+	cfg, err := ParseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return NewPool(ctx, cfg, opts)
+}
+
+// waitAndConnect дожидается доступности БД и создает пул.
+func waitAndConnect(ctx context.Context, dsn string, opts PoolOptions) (*pgxpool.Pool, error) {
+	healthOpts := DefaultHealthCheckOptions()
+	if opts.HealthCheck != nil {
+		healthOpts = *opts.HealthCheck
+	}
+	if err := WaitForDB(ctx, dsn, healthOpts); err != nil {
+		return nil, err
+	}
+
+	return NewPoolWithOptions(ctx, dsn, opts)
 }
 
 // NewPoolWithOptions создает новый пул подключений к PostgreSQL с заданными параметрами.
+// В отличие от NewPool не проверяет конфигурацию и не ждёт доступности БД.
+// Нулевые поля opts, кроме MinConns, заменяются значениями из DefaultPoolOptions,
+// поэтому PoolOptions{} равносилен DefaultPoolOptions() с MinConns = 0.
 func NewPoolWithOptions(ctx context.Context, dsn string, opts PoolOptions) (*pgxpool.Pool, error) {
 	cfg, err := pgxpool.ParseConfig(dsn)
 	if err != nil {
 		return nil, err
 	}
+	opts = opts.withDefaults()
 
 	// Применяем настройки из опций
 	cfg.MaxConns = opts.MaxConns
@@ -54,6 +120,7 @@ func NewPoolWithOptions(ctx context.Context, dsn string, opts PoolOptions) (*pgx
 	cfg.HealthCheckPeriod = opts.HealthCheckPeriod
 	cfg.MaxConnLifetime = opts.MaxConnLifetime
 	cfg.MaxConnIdleTime = opts.MaxConnIdleTime
+	cfg.AfterConnect = opts.AfterConnect
 
 	pool, err := pgxpool.NewWithConfig(ctx, cfg)
 	if err != nil {
@@ -2,6 +2,8 @@ package pg
 
 import (
 	"context"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -123,7 +125,9 @@ func TestNewPool_ErrorCases(t *testing.T) {
 			if opts := tt.setupOpts(); opts != nil {
 				_, err = NewPoolWithOptions(ctx, tt.dsn, *opts)
 			} else {
-				_, err = NewPool(ctx, tt.dsn)
+				opts := DefaultPoolOptions()
+				opts.HealthCheck = &HealthCheckOptions{MaxRetries: 1, PingTimeout: time.Second}
+				_, err = NewPoolFromDSN(ctx, tt.dsn, opts)
 			}
 
 			if tt.expectError && err == nil {
@@ -135,6 +139,152 @@ func TestNewPool_ErrorCases(t *testing.T) {
 	}
 }
 
+func TestNewPool_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		modify func(*DSNConfig)
+	}{
+		{
+			name:   "missing_user",
+			modify: func(c *DSNConfig) { c.User = "" },
+		},
+		{
+			name:   "missing_database",
+			modify: func(c *DSNConfig) { c.Database = "" },
+		},
+		{
+			name:   "invalid_port",
+			modify: func(c *DSNConfig) { c.Port = 70000 },
+		},
+		{
+			name:   "invalid_sslmode",
+			modify: func(c *DSNConfig) { c.SSLMode = "sometimes" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := DefaultDSNConfig()
+			cfg.User = "user"
+			cfg.Database = "app"
+			tt.modify(&cfg)
+
+			start := time.Now()
+			pool, err := NewPool(context.Background(), cfg, DefaultPoolOptions())
+			if err == nil {
+				pool.Close()
+				t.Fatal("expected validation error but got nil")
+			}
+			if !strings.Contains(err.Error(), "invalid database config") {
+				t.Errorf("expected validation error, got: %v", err)
+			}
+			// Валидация должна срабатывать до попыток подключения
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("validation took %v, expected no connection attempts", elapsed)
+			}
+		})
+	}
+}
+
+func TestNewPool_UsesHealthCheckOptions(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultDSNConfig()
+	cfg.User = "user"
+	cfg.Database = "app"
+	cfg.Port = 9999
+
+	opts := DefaultPoolOptions()
+	opts.HealthCheck = &HealthCheckOptions{
+		MaxRetries:      2,
+		InitialInterval: 10 * time.Millisecond,
+		Strategy:        LinearWait,
+		PingTimeout:     time.Second,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pool, err := NewPool(ctx, cfg, opts)
+	if err == nil {
+		pool.Close()
+		t.Fatal("expected error for unreachable database")
+	}
+	if !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Errorf("expected WaitForDB error after 2 attempts, got: %v", err)
+	}
+}
+
+func TestNewPoolFromDSN_InvalidDSN(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewPoolFromDSN(context.Background(), "mysql://user@localhost/app", DefaultPoolOptions())
+	if err == nil {
+		t.Fatal("expected error for unsupported scheme")
+	}
+	if !strings.Contains(err.Error(), "unsupported scheme") {
+		t.Errorf("expected ParseDSN error, got: %v", err)
+	}
+}
+
+func TestPoolOptions_WithDefaults(t *testing.T) {
+	t.Parallel()
+
+	want := DefaultPoolOptions()
+	want.MinConns = 0
+	if got := (PoolOptions{}).withDefaults(); !reflect.DeepEqual(got, want) {
+		t.Errorf("withDefaults() = %+v, want %+v", got, want)
+	}
+
+	custom := PoolOptions{MaxConns: 5, MinConns: 1, PingTimeout: time.Second}
+	got := custom.withDefaults()
+	if got.MaxConns != 5 || got.MinConns != 1 || got.PingTimeout != time.Second {
+		t.Errorf("withDefaults() overrode explicit fields: %+v", got)
+	}
+	if got.HealthCheckPeriod != want.HealthCheckPeriod {
+		t.Errorf("HealthCheckPeriod = %v, want %v", got.HealthCheckPeriod, want.HealthCheckPeriod)
+	}
+}
+
+func TestNewPool_ZeroOptions(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultDSNConfig()
+	cfg.User = "user"
+	cfg.Database = "app"
+	cfg.Port = 9999
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Ошибка должна быть про недоступную БД, а не про нулевые MaxConns или PingTimeout
+	_, err := NewPool(ctx, cfg, PoolOptions{HealthCheck: &HealthCheckOptions{MaxRetries: 1, PingTimeout: time.Second}})
+	if err == nil {
+		t.Fatal("expected error for unreachable database")
+	}
+	if !strings.Contains(err.Error(), "after 1 attempts") {
+		t.Errorf("expected WaitForDB error, got: %v", err)
+	}
+}
+
+func TestNewPoolFromDSN_KeywordValue(t *testing.T) {
+	t.Parallel()
+
+	opts := PoolOptions{HealthCheck: &HealthCheckOptions{MaxRetries: 1, PingTimeout: time.Second}}
+	// Строка без имени базы принимается так же, как pgxpool.ParseConfig
+	_, err := NewPoolFromDSN(context.Background(), "host=localhost port=9999 user=user sslmode=disable", opts)
+	if err == nil {
+		t.Fatal("expected error for unreachable database")
+	}
+	if !strings.Contains(err.Error(), "after 1 attempts") {
+		t.Errorf("expected WaitForDB error instead of parse error, got: %v", err)
+	}
+}
+
 // Этот тест теперь включен в TestNewPool_ErrorCases
 
 // Этот тест можно запускать только при наличии реальной PostgreSQL БД
@@ -152,7 +302,7 @@ func TestNewPool_Integration(t *testing.T) {
 	// ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	// defer cancel()
 	//
-	// pool, err := NewPool(ctx, dsn)
+	// pool, err := NewPoolFromDSN(ctx, dsn, DefaultPoolOptions())
 	// if err != nil {
 	//     t.Fatalf("failed to create pool: %v", err)
 	// }
@@ -0,0 +1,82 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"sttbot/pkg/retry"
+)
+
+const (
+	// sqlStateSerializationFailure - SQLSTATE 40001, конфликт сериализуемых транзакций
+	sqlStateSerializationFailure = "40001"
+	// sqlStateDeadlockDetected - SQLSTATE 40P01, обнаружена взаимоблокировка
+	sqlStateDeadlockDetected = "40P01"
+)
+
+// RetryConfig содержит настройки повторных попыток транзакций.
+type RetryConfig struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	// Jitter - стратегия рандомизации задержек (по умолчанию без jitter)
+	Jitter retry.JitterStrategy
+	// IsRetryable определяет, нужно ли повторять транзакцию (по умолчанию IsSerializationError)
+	IsRetryable func(err error) bool
+	// OnRetry вызывается перед каждым повтором транзакции (необязательно)
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// DefaultRetryConfig возвращает настройки ретраев по умолчанию.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 10 * time.Millisecond,
+		MaxDelay:     500 * time.Millisecond,
+		Multiplier:   2.0,
+	}
+}
+
+// IsSerializationError проверяет, является ли ошибка конфликтом сериализации (40001)
+// или взаимоблокировкой (40P01). Такие транзакции безопасно повторять целиком.
+func IsSerializationError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == sqlStateSerializationFailure || pgErr.Code == sqlStateDeadlockDetected
+}
+
+// executeWithRetry выполняет транзакцию с ретраями на ошибках сериализации.
+// После исчерпания попыток возвращает *retry.RetriesExceededError,
+// который оборачивает последнюю ошибку драйвера.
+func (r *TxRunner) executeWithRetry(ctx context.Context, fn func(context.Context) error) error {
+	cfg := DefaultRetryConfig()
+	if r.RetryConfig != nil {
+		cfg = *r.RetryConfig
+	}
+	if cfg.MaxAttempts <= 1 {
+		return fn(ctx)
+	}
+	if cfg.InitialDelay <= 0 {
+		cfg.InitialDelay = DefaultRetryConfig().InitialDelay
+	}
+
+	isRetryable := cfg.IsRetryable
+	if isRetryable == nil {
+		isRetryable = IsSerializationError
+	}
+
+	return retry.DoWithRetryable(ctx, retry.Config{
+		MaxAttempts:    cfg.MaxAttempts,
+		InitialDelay:   cfg.InitialDelay,
+		MaxDelay:       cfg.MaxDelay,
+		Multiplier:     cfg.Multiplier,
+		JitterStrategy: cfg.Jitter,
+		OnRetry:        cfg.OnRetry,
+	}, fn, isRetryable)
+}
@@ -0,0 +1,135 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"sttbot/pkg/retry"
+)
+
+func TestIsSerializationError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "nil", err: nil, expected: false},
+		{name: "plain error", err: errors.New("boom"), expected: false},
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, expected: true},
+		{name: "deadlock", err: &pgconn.PgError{Code: "40P01"}, expected: true},
+		{name: "wrapped deadlock", err: fmt.Errorf("update: %w", &pgconn.PgError{Code: "40P01"}), expected: true},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsSerializationError(tt.err); got != tt.expected {
+				t.Errorf("IsSerializationError(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTxRunner_ExecuteWithRetry(t *testing.T) {
+	t.Parallel()
+
+	fastConfig := func() RetryConfig {
+		cfg := DefaultRetryConfig()
+		cfg.InitialDelay = time.Millisecond
+		cfg.MaxDelay = time.Millisecond
+		return cfg
+	}
+
+	t.Run("retries serialization failures", func(t *testing.T) {
+		t.Parallel()
+
+		var retries int
+		cfg := fastConfig()
+		cfg.OnRetry = func(attempt int, err error, delay time.Duration) { retries++ }
+		runner := NewTxRunnerWithRetry(nil, cfg)
+
+		attempts := 0
+		err := runner.executeWithRetry(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return &pgconn.PgError{Code: "40001"}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("attempts = %d, want 3", attempts)
+		}
+		if retries != 2 {
+			t.Errorf("OnRetry calls = %d, want 2", retries)
+		}
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		t.Parallel()
+
+		runner := NewTxRunnerWithRetry(nil, fastConfig())
+		uniqueErr := &pgconn.PgError{Code: "23505"}
+
+		attempts := 0
+		err := runner.executeWithRetry(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return uniqueErr
+		})
+		if !errors.Is(err, uniqueErr) {
+			t.Errorf("expected unique violation, got: %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("attempts = %d, want 1", attempts)
+		}
+	})
+
+	t.Run("returns last error after attempts exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		runner := NewTxRunnerWithRetry(nil, fastConfig())
+
+		attempts := 0
+		err := runner.executeWithRetry(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return &pgconn.PgError{Code: "40P01"}
+		})
+
+		var exceeded *retry.RetriesExceededError
+		if !errors.As(err, &exceeded) {
+			t.Fatalf("expected RetriesExceededError, got: %v", err)
+		}
+		if !IsSerializationError(err) {
+			t.Errorf("expected wrapped deadlock error, got: %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("attempts = %d, want 3", attempts)
+		}
+	})
+
+	t.Run("single attempt disables retries", func(t *testing.T) {
+		t.Parallel()
+
+		runner := &TxRunner{RetryConfig: &RetryConfig{MaxAttempts: 1}}
+
+		attempts := 0
+		_ = runner.executeWithRetry(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return &pgconn.PgError{Code: "40001"}
+		})
+		if attempts != 1 {
+			t.Errorf("attempts = %d, want 1", attempts)
+		}
+	})
+}
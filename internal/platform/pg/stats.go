@@ -0,0 +1,220 @@
+package pg
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultStatsInterval - интервал сбора статистики StatsCollector по умолчанию.
+const DefaultStatsInterval = 30 * time.Second
+
+// Имена метрик, создаваемых NewStatsMetrics.
+const (
+	// MetricPoolMaxConns - максимальное количество подключений пула.
+	MetricPoolMaxConns = "pg_pool_max_conns"
+	// MetricPoolOpenConns - количество открытых подключений.
+	MetricPoolOpenConns = "pg_pool_open_conns"
+	// MetricPoolInUseConns - количество подключений в использовании.
+	MetricPoolInUseConns = "pg_pool_in_use_conns"
+	// MetricPoolIdleConns - количество простаивающих подключений.
+	MetricPoolIdleConns = "pg_pool_idle_conns"
+	// MetricPoolWaitCount - накопленное количество ожиданий подключения.
+	MetricPoolWaitCount = "pg_pool_wait_count"
+	// MetricPoolWaitDuration - накопленное время ожидания подключения в секундах.
+	MetricPoolWaitDuration = "pg_pool_wait_duration_seconds"
+	// MetricPoolHealthy - 1, если пул здоров по IsHealthy, иначе 0.
+	MetricPoolHealthy = "pg_pool_healthy"
+)
+
+// Gauge - метрика с устанавливаемым значением, например prometheus.Gauge.
+type Gauge interface {
+	Set(value float64)
+}
+
+// StatsRegistrar регистрирует семь метрик pg_pool_* один раз в NewStatsMetrics.
+// Пул в процессе обычно один, поэтому метрики создаются без меток: для нескольких
+// пулов оберните реализацию так, чтобы она добавляла константную метку с именем пула.
+type StatsRegistrar interface {
+	Gauge(name, help string) Gauge
+}
+
+// NewStatsMetrics создаёт обработчик снимков для StatsCollector.OnSnapshot, который
+// выставляет метрики pg_pool_* из статистики пула. Метрики регистрируются один раз при вызове:
+//
+//	collector.OnSnapshot(pg.NewStatsMetrics(registrar))
+func NewStatsMetrics(reg StatsRegistrar) func(stats DBStats, healthy bool) {
+	maxConns := reg.Gauge(MetricPoolMaxConns, "Maximum number of connections in the pool.")
+	openConns := reg.Gauge(MetricPoolOpenConns, "Number of open connections.")
+	inUse := reg.Gauge(MetricPoolInUseConns, "Number of connections in use.")
+	idle := reg.Gauge(MetricPoolIdleConns, "Number of idle connections.")
+	waitCount := reg.Gauge(MetricPoolWaitCount, "Total number of waits for a connection.")
+	waitDuration := reg.Gauge(MetricPoolWaitDuration, "Total time spent waiting for a connection in seconds.")
+	healthyGauge := reg.Gauge(MetricPoolHealthy, "Whether the pool is healthy (1) or not (0).")
+
+	return func(stats DBStats, healthy bool) {
+		maxConns.Set(float64(stats.MaxConns))
+		openConns.Set(float64(stats.OpenConns))
+		inUse.Set(float64(stats.InUse))
+		idle.Set(float64(stats.Idle))
+		waitCount.Set(float64(stats.WaitCount))
+		waitDuration.Set(stats.WaitDuration.Seconds())
+		if healthy {
+			healthyGauge.Set(1)
+		} else {
+			healthyGauge.Set(0)
+		}
+	}
+}
+
+// StatsCollector периодически снимает статистику пула, логирует её и передаёт
+// обработчикам OnSnapshot. Переходы между здоровым и нездоровым состоянием
+// (по IsHealthy) логируются отдельно.
+// Может работать в собственной goroutine (Start/Stop) или как задача планировщика (Collect).
+type StatsCollector struct {
+	logger   *slog.Logger
+	interval time.Duration
+	source   func() DBStats
+
+	mu        sync.Mutex
+	handlers  []func(stats DBStats, healthy bool)
+	last      DBStats
+	healthy   bool
+	collected bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewStatsCollector создаёт сборщик статистики пула.
+// Nil logger заменяется на slog.Default(), неположительный interval - на DefaultStatsInterval.
+func NewStatsCollector(pool *pgxpool.Pool, logger *slog.Logger, interval time.Duration) *StatsCollector {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if interval <= 0 {
+		interval = DefaultStatsInterval
+	}
+	return &StatsCollector{
+		logger:   logger,
+		interval: interval,
+		source:   func() DBStats { return GetPoolStats(pool) },
+	}
+}
+
+// OnSnapshot добавляет обработчик, вызываемый после каждого снимка статистики,
+// например NewStatsMetrics. Обработчики вызываются синхронно в порядке добавления.
+func (c *StatsCollector) OnSnapshot(fn func(stats DBStats, healthy bool)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = append(c.handlers, fn)
+}
+
+// Start запускает сбор статистики в отдельной goroutine: первый снимок сразу,
+// затем каждый interval. Сбор прекращается при отмене ctx или вызове Stop.
+func (c *StatsCollector) Start(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cancel != nil {
+		return // Уже запущен
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	c.cancel = cancel
+	c.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		c.Run(ctx)
+	}(c.done)
+}
+
+// Stop останавливает сбор, запущенный через Start, и дожидается его завершения.
+func (c *StatsCollector) Stop() {
+	c.mu.Lock()
+	cancel, done := c.cancel, c.done
+	c.cancel, c.done = nil, nil
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// Run снимает статистику до отмены ctx. Блокирует вызывающую goroutine.
+func (c *StatsCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		_ = c.Collect(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect снимает, логирует и передаёт обработчикам один снимок статистики.
+// Совместим с scheduler.JobFunc для запуска по расписанию:
+//
+//	s.AddTickerJobWithOptions(time.Minute, collector.Collect, scheduler.JobOptions{Name: "pg_stats"})
+func (c *StatsCollector) Collect(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	stats := c.source()
+	healthy := IsHealthy(stats)
+
+	c.mu.Lock()
+	first, wasHealthy := !c.collected, c.healthy
+	c.last, c.healthy, c.collected = stats, healthy, true
+	handlers := c.handlers
+	c.mu.Unlock()
+
+	attrs := []any{
+		slog.Int("max_conns", int(stats.MaxConns)),
+		slog.Int("open_conns", int(stats.OpenConns)),
+		slog.Int("in_use", int(stats.InUse)),
+		slog.Int("idle", int(stats.Idle)),
+		slog.Int64("wait_count", stats.WaitCount),
+		slog.Duration("wait_duration", stats.WaitDuration),
+		slog.Bool("healthy", healthy),
+	}
+	c.logger.InfoContext(ctx, "pg pool stats", attrs...)
+	// Первый снимок сообщает только о нездоровом пуле, дальше - о каждом переходе
+	switch {
+	case !healthy && (first || wasHealthy):
+		c.logger.WarnContext(ctx, "pg pool became unhealthy", attrs...)
+	case healthy && !first && !wasHealthy:
+		c.logger.InfoContext(ctx, "pg pool recovered", attrs...)
+	}
+
+	for _, fn := range handlers {
+		fn(stats, healthy)
+	}
+	return nil
+}
+
+// Snapshot возвращает последний снимок статистики (нулевой до первого сбора).
+func (c *StatsCollector) Snapshot() DBStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// Healthy сообщает, был ли пул здоров при последнем снимке (false до первого сбора).
+func (c *StatsCollector) Healthy() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.healthy
+}
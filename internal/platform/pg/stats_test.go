@@ -0,0 +1,218 @@
+package pg
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var (
+	healthyStats   = DBStats{MaxConns: 10, OpenConns: 4, InUse: 2, Idle: 2}
+	saturatedStats = DBStats{MaxConns: 10, OpenConns: 10, InUse: 10, WaitCount: 7, WaitDuration: 1500 * time.Millisecond}
+)
+
+// syncBuffer - буфер логов, безопасный для записи из goroutine сборщика.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// newTestCollector создает сборщик с подменённым источником, возвращающим stats по очереди
+// (последний снимок повторяется).
+func newTestCollector(interval time.Duration, stats ...DBStats) (*StatsCollector, *syncBuffer) {
+	logs := &syncBuffer{}
+	c := NewStatsCollector(nil, slog.New(slog.NewTextHandler(logs, nil)), interval)
+	var i atomic.Int32
+	c.source = func() DBStats {
+		n := int(i.Add(1)) - 1
+		return stats[min(n, len(stats)-1)]
+	}
+	return c, logs
+}
+
+func TestNewStatsCollector_Defaults(t *testing.T) {
+	t.Parallel()
+
+	c := NewStatsCollector(nil, nil, 0)
+	if c.interval != DefaultStatsInterval {
+		t.Errorf("interval = %v, want %v", c.interval, DefaultStatsInterval)
+	}
+	if c.logger == nil {
+		t.Error("logger must default to slog.Default()")
+	}
+	if got := c.source(); got != (DBStats{}) {
+		t.Errorf("stats of nil pool = %+v, want zero", got)
+	}
+}
+
+func TestStatsCollector_Collect(t *testing.T) {
+	t.Parallel()
+
+	c, logs := newTestCollector(time.Minute, healthyStats, saturatedStats, saturatedStats, healthyStats)
+
+	type snapshot struct {
+		stats   DBStats
+		healthy bool
+	}
+	var got []snapshot
+	c.OnSnapshot(func(stats DBStats, healthy bool) {
+		got = append(got, snapshot{stats, healthy})
+	})
+
+	for range 4 {
+		if err := c.Collect(context.Background()); err != nil {
+			t.Fatalf("Collect() error = %v", err)
+		}
+	}
+
+	want := []snapshot{{healthyStats, true}, {saturatedStats, false}, {saturatedStats, false}, {healthyStats, true}}
+	if len(got) != len(want) {
+		t.Fatalf("handler called %d times, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("snapshot %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if c.Snapshot() != healthyStats || !c.Healthy() {
+		t.Errorf("Snapshot() = %+v, Healthy() = %v, want last healthy snapshot", c.Snapshot(), c.Healthy())
+	}
+
+	out := logs.String()
+	if n := strings.Count(out, `msg="pg pool stats"`); n != 4 {
+		t.Errorf("logged %d snapshots, want 4", n)
+	}
+	if n := strings.Count(out, "pg pool became unhealthy"); n != 1 {
+		t.Errorf("logged %d unhealthy transitions, want 1", n)
+	}
+	if n := strings.Count(out, "pg pool recovered"); n != 1 {
+		t.Errorf("logged %d recoveries, want 1", n)
+	}
+	if !strings.Contains(out, "wait_count=7 wait_duration=1.5s healthy=false") {
+		t.Errorf("snapshot log lacks stats fields:\n%s", out)
+	}
+}
+
+func TestStatsCollector_FirstSnapshotUnhealthy(t *testing.T) {
+	t.Parallel()
+
+	c, logs := newTestCollector(time.Minute, DBStats{})
+	_ = c.Collect(context.Background())
+
+	if !strings.Contains(logs.String(), "pg pool became unhealthy") {
+		t.Error("unhealthy pool must be reported on first snapshot")
+	}
+	if c.Healthy() {
+		t.Error("Healthy() = true for pool without connections")
+	}
+}
+
+func TestStatsCollector_CanceledCollect(t *testing.T) {
+	t.Parallel()
+
+	c, logs := newTestCollector(time.Minute, healthyStats)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := c.Collect(ctx); err != context.Canceled {
+		t.Errorf("Collect() error = %v, want context.Canceled", err)
+	}
+	if logs.String() != "" {
+		t.Errorf("canceled collect must not log, got:\n%s", logs.String())
+	}
+}
+
+func TestStatsCollector_StartStop(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestCollector(5*time.Millisecond, healthyStats)
+	var calls atomic.Int32
+	c.OnSnapshot(func(DBStats, bool) { calls.Add(1) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Start(ctx)
+	c.Start(ctx) // повторный запуск игнорируется
+
+	deadline := time.Now().Add(2 * time.Second)
+	for calls.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if calls.Load() < 3 {
+		t.Fatalf("collected %d snapshots, want at least 3", calls.Load())
+	}
+
+	// Отмена контекста останавливает сбор, Stop дожидается завершения
+	cancel()
+	c.Stop()
+	c.Stop() // повторная остановка безопасна
+
+	stopped := calls.Load()
+	time.Sleep(30 * time.Millisecond)
+	if calls.Load() != stopped {
+		t.Error("collection continued after Stop")
+	}
+}
+
+// fakeGauge запоминает последнее значение.
+type fakeGauge struct{ value float64 }
+
+func (g *fakeGauge) Set(value float64) { g.value = value }
+
+// fakeStatsRegistrar создаёт fakeGauge по имени.
+type fakeStatsRegistrar map[string]*fakeGauge
+
+func (r fakeStatsRegistrar) Gauge(name, help string) Gauge {
+	g := &fakeGauge{}
+	r[name] = g
+	return g
+}
+
+func TestNewStatsMetrics(t *testing.T) {
+	t.Parallel()
+
+	reg := fakeStatsRegistrar{}
+	c, _ := newTestCollector(time.Minute, saturatedStats, healthyStats)
+	c.OnSnapshot(NewStatsMetrics(reg))
+
+	_ = c.Collect(context.Background())
+	want := map[string]float64{
+		MetricPoolMaxConns:     10,
+		MetricPoolOpenConns:    10,
+		MetricPoolInUseConns:   10,
+		MetricPoolIdleConns:    0,
+		MetricPoolWaitCount:    7,
+		MetricPoolWaitDuration: 1.5,
+		MetricPoolHealthy:      0,
+	}
+	if len(reg) != len(want) {
+		t.Fatalf("registered %d gauges, want %d", len(reg), len(want))
+	}
+	for name, value := range want {
+		if got := reg[name].value; got != value {
+			t.Errorf("%s = %v, want %v", name, got, value)
+		}
+	}
+
+	_ = c.Collect(context.Background())
+	if reg[MetricPoolHealthy].value != 1 || reg[MetricPoolInUseConns].value != 2 {
+		t.Errorf("gauges not updated on next snapshot: healthy = %v, in use = %v",
+			reg[MetricPoolHealthy].value, reg[MetricPoolInUseConns].value)
+	}
+}
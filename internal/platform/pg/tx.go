@@ -28,37 +28,66 @@ var (
 
 // TxRunner предоставляет возможность выполнения кода внутри транзакции.
 // Реализует паттерн "функция обратного вызова" для гарантированного
-// коммита или отката транзакции.
+// коммита или отката транзакции, с ретраями на ошибках сериализации.
 type TxRunner struct {
-	Pool *pgxpool.Pool
+	Pool        *pgxpool.Pool
+	RetryConfig *RetryConfig
 }
 
-// NewTxRunner создает новый TxRunner с указанным пулом подключений.
+// NewTxRunner создает новый TxRunner с указанным пулом подключений и ретраями по умолчанию.
 func NewTxRunner(pool *pgxpool.Pool) *TxRunner {
-	return &TxRunner{Pool: pool}
+	return NewTxRunnerWithRetry(pool, DefaultRetryConfig())
+}
+
+// NewTxRunnerWithRetry создает новый TxRunner с указанными настройками ретраев.
+func NewTxRunnerWithRetry(pool *pgxpool.Pool, retryConfig RetryConfig) *TxRunner {
+	return &TxRunner{Pool: pool, RetryConfig: &retryConfig}
 }
 
 // WithinTx выполняет функцию fn внутри транзакции с опциями по умолчанию.
 // Если fn возвращает ошибку, транзакция откатывается.
 // Если fn выполняется успешно (возвращает nil), транзакция коммитится.
 // Транзакция доступна внутри fn через функцию PgxTx(ctx).
+// При ошибках сериализации (40001) и взаимоблокировках (40P01) fn выполняется повторно
+// в новой транзакции, поэтому fn не должна иметь побочных эффектов вне БД.
 func (r *TxRunner) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
-	return pgx.BeginFunc(ctx, r.Pool, func(tx pgx.Tx) error {
-		// Сохраняем транзакцию в контексте для доступа внутри fn
-		ctx = context.WithValue(ctx, txKey{}, tx)
-		return fn(ctx)
-	})
+	return r.WithinTxWithOptions(ctx, pgx.TxOptions{}, fn)
 }
 
 // WithinTxWithOptions выполняет функцию fn внутри транзакции с заданными опциями.
 // Если fn возвращает ошибку, транзакция откатывается.
 // Если fn выполняется успешно (возвращает nil), транзакция коммитится.
 // Транзакция доступна внутри fn через функцию PgxTx(ctx).
+// Ретраи выполняются так же, как в WithinTx.
 func (r *TxRunner) WithinTxWithOptions(ctx context.Context, txOptions pgx.TxOptions, fn func(ctx context.Context) error) error {
-	return pgx.BeginTxFunc(ctx, r.Pool, txOptions, func(tx pgx.Tx) error {
-		// Сохраняем транзакцию в контексте для доступа внутри fn
-		ctx = context.WithValue(ctx, txKey{}, tx)
-		return fn(ctx)
+	return r.executeWithRetry(ctx, func(ctx context.Context) error {
+		return pgx.BeginTxFunc(ctx, r.Pool, txOptions, func(tx pgx.Tx) error {
+			// Сохраняем транзакцию в контексте для доступа внутри fn
+			return fn(context.WithValue(ctx, txKey{}, tx))
+		})
+	})
+}
+
+// WithinTxRead выполняет функцию fn внутри read-only транзакции.
+func (r *TxRunner) WithinTxRead(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.WithinTxWithOptions(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly}, fn)
+}
+
+// WithinSavepoint выполняет функцию fn внутри savepoint.
+// Если в контексте есть активная транзакция, создаёт вложенную транзакцию (SAVEPOINT) через pgx.
+// Если активной транзакции нет, работает как WithinTx.
+// При ошибке откатывается к savepoint, при успехе - освобождает savepoint.
+// Вложенная транзакция доступна внутри fn через PgxTx(ctx).
+func (r *TxRunner) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, ok := PgxTx(ctx)
+	if !ok {
+		return r.WithinTx(ctx, fn)
+	}
+
+	// Ретраи внутри внешней транзакции бессмысленны: после ошибки сериализации
+	// PostgreSQL прерывает всю транзакцию, поэтому повторяет её внешний WithinTx
+	return pgx.BeginFunc(ctx, tx, func(savepoint pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, savepoint))
 	})
 }
 
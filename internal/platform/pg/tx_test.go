@@ -2,9 +2,14 @@ package pg
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -167,71 +172,199 @@ func TestTxRunner_WithinTxWithOptions_OptionsValidation(t *testing.T) {
 	}
 }
 
-// Интеграционные тесты для полноценной работы с транзакциями
-// требуют реальной базы данных и выходят за рамки юнит-тестирования
-func TestTxRunner_WithinTx_Integration(t *testing.T) {
+// setupTestDatabase подключается к PostgreSQL из переменной окружения PG_TEST_DSN.
+// Если переменная не задана, тест пропускается.
+func setupTestDatabase(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
 	if testing.Short() {
 		t.Skip("skipping integration test in short mode")
 	}
+	dsn := os.Getenv("PG_TEST_DSN")
+	if dsn == "" {
+		t.Skip("integration test requires PG_TEST_DSN with real PostgreSQL database")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pool, err := NewPoolWithOptions(ctx, dsn, DefaultPoolOptions())
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+// createTestTable создаёт временную таблицу с уникальным именем и удаляет её после теста.
+func createTestTable(t *testing.T, pool *pgxpool.Pool) string {
+	t.Helper()
+
+	ctx := context.Background()
+	table := fmt.Sprintf("tx_test_%d", time.Now().UnixNano())
+	if _, err := pool.Exec(ctx, "CREATE TABLE "+table+" (id SERIAL PRIMARY KEY, value TEXT NOT NULL)"); err != nil {
+		t.Fatalf("failed to create test table: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(context.Background(), "DROP TABLE IF EXISTS "+table)
+	})
+	return table
+}
+
+// selectValues возвращает значения из тестовой таблицы в порядке вставки.
+func selectValues(t *testing.T, pool *pgxpool.Pool, table string) []string {
+	t.Helper()
+
+	rows, err := pool.Query(context.Background(), "SELECT value FROM "+table+" ORDER BY id")
+	if err != nil {
+		t.Fatalf("failed to query test table: %v", err)
+	}
+	values, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		t.Fatalf("failed to collect rows: %v", err)
+	}
+	return values
+}
+
+func TestTxRunner_WithinTx_Integration(t *testing.T) {
+	pool := setupTestDatabase(t)
+	table := createTestTable(t, pool)
 
-	// TODO: Реализовать интеграционные тесты с testcontainers
-	t.Skip("integration test requires real PostgreSQL database")
-
-	// Пример структуры интеграционного теста:
-	// pool := setupTestDatabase(t)
-	// defer pool.Close()
-	//
-	// runner := NewTxRunner(pool)
-	// ctx := context.Background()
-	//
-	// err := runner.WithinTx(ctx, func(ctx context.Context) error {
-	//     tx, ok := PgxTx(ctx)
-	//     if !ok {
-	//         return errors.New("expected transaction in context")
-	//     }
-	//
-	//     // Выполняем тестовые операции с транзакцией
-	//     _, err := tx.Exec(ctx, "SELECT 1")
-	//     return err
-	// })
-	//
-	// if err != nil {
-	//     t.Fatalf("transaction failed: %v", err)
-	// }
+	runner := NewTxRunner(pool)
+	ctx := context.Background()
+
+	err := runner.WithinTx(ctx, func(ctx context.Context) error {
+		if _, ok := PgxTx(ctx); !ok {
+			return errors.New("expected transaction in context")
+		}
+		_, err := runner.GetQuerier(ctx).Exec(ctx, "INSERT INTO "+table+" (value) VALUES ($1)", "committed")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+
+	rollbackErr := errors.New("rollback")
+	err = runner.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := runner.GetQuerier(ctx).Exec(ctx, "INSERT INTO "+table+" (value) VALUES ($1)", "rolled_back"); err != nil {
+			return err
+		}
+		return rollbackErr
+	})
+	if !errors.Is(err, rollbackErr) {
+		t.Fatalf("expected rollback error, got: %v", err)
+	}
+
+	if values := selectValues(t, pool, table); len(values) != 1 || values[0] != "committed" {
+		t.Errorf("values = %v, want [committed]", values)
+	}
 }
 
 func TestTxRunner_WithinTxWithOptions_Integration(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping integration test in short mode")
+	pool := setupTestDatabase(t)
+	table := createTestTable(t, pool)
+
+	runner := NewTxRunner(pool)
+	ctx := context.Background()
+
+	opts := pgx.TxOptions{
+		IsoLevel:   pgx.ReadCommitted,
+		AccessMode: pgx.ReadWrite,
+	}
+
+	err := runner.WithinTxWithOptions(ctx, opts, func(ctx context.Context) error {
+		_, err := runner.GetQuerier(ctx).Exec(ctx, "INSERT INTO "+table+" (value) VALUES ($1)", "with_options")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("transaction with options failed: %v", err)
+	}
+
+	// Запись в read-only транзакции должна быть отклонена
+	err = runner.WithinTxRead(ctx, func(ctx context.Context) error {
+		_, err := runner.GetQuerier(ctx).Exec(ctx, "INSERT INTO "+table+" (value) VALUES ($1)", "read_only")
+		return err
+	})
+	if err == nil {
+		t.Error("expected error for write in read-only transaction")
+	}
+
+	if values := selectValues(t, pool, table); len(values) != 1 {
+		t.Errorf("values = %v, want 1 row", values)
+	}
+}
+
+func TestTxRunner_WithinSavepoint_Integration(t *testing.T) {
+	pool := setupTestDatabase(t)
+	table := createTestTable(t, pool)
+
+	runner := NewTxRunner(pool)
+	ctx := context.Background()
+	insert := func(ctx context.Context, value string) error {
+		_, err := runner.GetQuerier(ctx).Exec(ctx, "INSERT INTO "+table+" (value) VALUES ($1)", value)
+		return err
 	}
 
-	// TODO: Реализовать интеграционные тесты с testcontainers
-	t.Skip("integration test requires real PostgreSQL database")
-
-	// Пример структуры интеграционного теста с опциями:
-	// pool := setupTestDatabase(t)
-	// defer pool.Close()
-	//
-	// runner := NewTxRunner(pool)
-	// ctx := context.Background()
-	//
-	// opts := pgx.TxOptions{
-	//     IsoLevel:   pgx.ReadCommitted,
-	//     AccessMode: pgx.ReadWrite,
-	// }
-	//
-	// err := runner.WithinTxWithOptions(ctx, opts, func(ctx context.Context) error {
-	//     tx, ok := PgxTx(ctx)
-	//     if !ok {
-	//         return errors.New("expected transaction in context")
-	//     }
-	//
-	//     // Выполняем тестовые операции с транзакцией
-	//     _, err := tx.Exec(ctx, "SELECT 1")
-	//     return err
-	// })
-	//
-	// if err != nil {
-	//     t.Fatalf("transaction with options failed: %v", err)
-	// }
+	savepointErr := errors.New("savepoint failed")
+	err := runner.WithinTx(ctx, func(ctx context.Context) error {
+		if err := insert(ctx, "outer"); err != nil {
+			return err
+		}
+
+		// Успешный savepoint освобождается, данные остаются в транзакции
+		if err := runner.WithinSavepoint(ctx, func(ctx context.Context) error {
+			return insert(ctx, "released")
+		}); err != nil {
+			return err
+		}
+
+		// Неуспешный savepoint откатывается, внешняя транзакция продолжается
+		err := runner.WithinSavepoint(ctx, func(ctx context.Context) error {
+			if err := insert(ctx, "rolled_back"); err != nil {
+				return err
+			}
+			return savepointErr
+		})
+		if !errors.Is(err, savepointErr) {
+			return fmt.Errorf("expected savepoint error, got: %w", err)
+		}
+
+		return insert(ctx, "after_rollback")
+	})
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+
+	want := []string{"outer", "released", "after_rollback"}
+	if values := selectValues(t, pool, table); fmt.Sprint(values) != fmt.Sprint(want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+}
+
+func TestTxRunner_RetriesSerializationFailure_Integration(t *testing.T) {
+	pool := setupTestDatabase(t)
+	table := createTestTable(t, pool)
+
+	runner := NewTxRunner(pool)
+	ctx := context.Background()
+
+	attempts := 0
+	err := runner.WithinTx(ctx, func(ctx context.Context) error {
+		attempts++
+		if _, err := runner.GetQuerier(ctx).Exec(ctx, "INSERT INTO "+table+" (value) VALUES ($1)", fmt.Sprint(attempts)); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			return &pgconn.PgError{Code: "40001"}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction failed: %v", err)
+	}
+
+	// Первая попытка откатилась целиком, закоммичена только вторая
+	if values := selectValues(t, pool, table); len(values) != 1 || values[0] != "2" {
+		t.Errorf("values = %v, want [2]", values)
+	}
 }
@@ -0,0 +1,165 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrBackupExists возвращается, если файл назначения резервной копии уже существует.
+var ErrBackupExists = errors.New("backup destination already exists")
+
+// ErrBackupCorrupted возвращается, если резервная копия не прошла проверку целостности.
+var ErrBackupCorrupted = errors.New("backup integrity check failed")
+
+// BackupOptions содержит настройки резервного копирования.
+type BackupOptions struct {
+	// Overwrite - разрешить перезапись существующего файла назначения
+	Overwrite bool
+}
+
+// Backup создаёт онлайн-копию базы данных в destPath через VACUUM INTO.
+// Не блокирует читателей в WAL режиме. Отказывается перезаписывать существующий файл.
+func Backup(ctx context.Context, db *sql.DB, destPath string) error {
+	return BackupWithOptions(ctx, db, destPath, BackupOptions{})
+}
+
+// BackupWithOptions создаёт онлайн-копию базы данных с указанными опциями.
+// Копия сначала пишется во временный файл рядом с destPath и затем атомарно переименовывается.
+func BackupWithOptions(ctx context.Context, db *sql.DB, destPath string, opts BackupOptions) error {
+	if !opts.Overwrite {
+		if _, err := os.Stat(destPath); err == nil {
+			return fmt.Errorf("%w: %s", ErrBackupExists, destPath)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to stat backup destination %s: %w", destPath, err)
+		}
+	}
+
+	dir := filepath.Dir(destPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	// VACUUM INTO требует несуществующий файл, поэтому резервируем уникальное имя и удаляем файл
+	tmpPath, err := reserveTempPath(dir, filepath.Base(destPath))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", tmpPath); err != nil {
+		return fmt.Errorf("failed to backup database: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		return fmt.Errorf("failed to move backup to %s: %w", destPath, err)
+	}
+	return nil
+}
+
+// Restore заменяет файл базы данных dbPath резервной копией backupPath.
+// Перед заменой проверяет копию через PRAGMA integrity_check, файл заменяется атомарно.
+// Все подключения к dbPath должны быть закрыты до вызова Restore.
+func Restore(ctx context.Context, dbPath, backupPath string) error {
+	if err := checkIntegrity(ctx, backupPath); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(dbPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", dir, err)
+	}
+
+	tmpPath, err := copyToTemp(backupPath, dir, filepath.Base(dbPath))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	// Удаляем WAL и SHM файлы старой базы, иначе SQLite применит их к восстановленной копии
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dbPath + suffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %w", dbPath+suffix, err)
+		}
+	}
+
+	if err := os.Rename(tmpPath, dbPath); err != nil {
+		return fmt.Errorf("failed to replace database %s: %w", dbPath, err)
+	}
+	return nil
+}
+
+// checkIntegrity открывает файл в режиме только для чтения и выполняет PRAGMA integrity_check.
+func checkIntegrity(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("failed to stat backup %s: %w", path, err)
+	}
+
+	opts := DefaultDBOptions()
+	opts.AccessMode = AccessModeReadOnly
+	opts.WALMode = false
+	opts.MaxOpenConns = 1
+	db, err := NewDBWithOptions(ctx, path, opts)
+	if err != nil {
+		return fmt.Errorf("failed to open backup %s: %w", path, err)
+	}
+	defer db.Close()
+
+	var result string
+	if err := db.QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrBackupCorrupted, path, err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("%w: %s: %s", ErrBackupCorrupted, path, result)
+	}
+	return nil
+}
+
+// reserveTempPath возвращает уникальный путь для временного файла, не создавая его.
+func reserveTempPath(dir, base string) (string, error) {
+	f, err := os.CreateTemp(dir, base+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	path := f.Name()
+	_ = f.Close()
+	if err := os.Remove(path); err != nil {
+		return "", fmt.Errorf("failed to remove temp file: %w", err)
+	}
+	return path, nil
+}
+
+// copyToTemp копирует файл src во временный файл в dir и синхронизирует его на диск.
+func copyToTemp(src, dir, base string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", fmt.Errorf("failed to open backup %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(dir, base+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := out.Name()
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to copy backup: %w", err)
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to sync backup copy: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to close backup copy: %w", err)
+	}
+	return tmpPath, nil
+}
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "app.db")
+	backupPath := filepath.Join(dir, "backups", "app.backup.db")
+
+	db, err := NewDB(ctx, dbPath)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "CREATE TABLE notes (id INTEGER PRIMARY KEY, body TEXT)")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "INSERT INTO notes (body) VALUES ('first'), ('second')")
+	require.NoError(t, err)
+
+	// Активный читатель не должен мешать онлайн-копии в WAL режиме
+	reader, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, reader.QueryRowContext(ctx, "SELECT COUNT(*) FROM notes").Scan(&count))
+
+	require.NoError(t, Backup(ctx, db, backupPath))
+	require.NoError(t, reader.Rollback())
+
+	// Изменяем базу после копирования
+	_, err = db.ExecContext(ctx, "DELETE FROM notes WHERE body = 'first'")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "INSERT INTO notes (body) VALUES ('third')")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	require.NoError(t, Restore(ctx, dbPath, backupPath))
+
+	restored, err := NewDB(ctx, dbPath)
+	require.NoError(t, err)
+	defer restored.Close()
+
+	rows, err := restored.QueryContext(ctx, "SELECT body FROM notes ORDER BY id")
+	require.NoError(t, err)
+	defer rows.Close()
+	var bodies []string
+	for rows.Next() {
+		var body string
+		require.NoError(t, rows.Scan(&body))
+		bodies = append(bodies, body)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{"first", "second"}, bodies, "восстановленная база должна совпадать с копией")
+}
+
+func TestBackup_RefusesOverwrite(t *testing.T) {
+	ctx := context.Background()
+	testDB := NewTestDBFile(t)
+	backupPath := filepath.Join(t.TempDir(), "backup.db")
+
+	require.NoError(t, Backup(ctx, testDB.DB, backupPath))
+
+	err := Backup(ctx, testDB.DB, backupPath)
+	require.ErrorIs(t, err, ErrBackupExists)
+
+	require.NoError(t, BackupWithOptions(ctx, testDB.DB, backupPath, BackupOptions{Overwrite: true}))
+}
+
+func TestRestore_RejectsCorruptedBackup(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "app.db")
+	backupPath := filepath.Join(dir, "broken.db")
+
+	original := []byte("original")
+	require.NoError(t, os.WriteFile(dbPath, original, 0644))
+	require.NoError(t, os.WriteFile(backupPath, []byte("definitely not a sqlite database file"), 0644))
+
+	err := Restore(ctx, dbPath, backupPath)
+	require.Error(t, err)
+
+	content, err := os.ReadFile(dbPath)
+	require.NoError(t, err)
+	assert.Equal(t, original, content, "исходный файл не должен изменяться при ошибке проверки")
+}
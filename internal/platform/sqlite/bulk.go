@@ -0,0 +1,170 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"sttbot/internal/shared"
+)
+
+const (
+	// MaxBoundParams - лимит параметров в одном запросе для SQLite >= 3.32.0 (входит в драйвер).
+	MaxBoundParams = 32766
+	// MaxBoundParamsLegacy - лимит параметров для SQLite < 3.32.0.
+	MaxBoundParamsLegacy = 999
+	// DefaultBulkBatchSize - размер пачки BulkInsert по умолчанию. Очень длинные запросы
+	// SQLite разбирает дольше, чем выполняет, поэтому пачки по ~100 строк быстрее максимальных.
+	DefaultBulkBatchSize = 100
+)
+
+// ErrInvalidBulkInsert - некорректные аргументы BulkInsert.
+var ErrInvalidBulkInsert = errors.New("sqlite: invalid bulk insert")
+
+// ConflictAction определяет поведение BulkInsert при конфликте ограничений.
+type ConflictAction string
+
+const (
+	// ConflictAbort - запрос завершается ошибкой (по умолчанию).
+	ConflictAbort ConflictAction = ""
+	// ConflictIgnore - конфликтующие строки пропускаются (INSERT OR IGNORE).
+	ConflictIgnore ConflictAction = "IGNORE"
+	// ConflictReplace - конфликтующие строки заменяются (INSERT OR REPLACE).
+	ConflictReplace ConflictAction = "REPLACE"
+	// ConflictUpdate - к запросу добавляется BulkOptions.UpsertClause.
+	ConflictUpdate ConflictAction = "UPDATE"
+)
+
+// BulkOptions содержит опции BulkInsert.
+type BulkOptions struct {
+	// BatchSize - максимум строк в одном INSERT (0 - DefaultBulkBatchSize).
+	// Уменьшается, если пачка не укладывается в MaxParams.
+	BatchSize int
+	// MaxParams - лимит параметров в одном запросе (0 - MaxBoundParams).
+	// Для старых сборок SQLite укажите MaxBoundParamsLegacy.
+	MaxParams int
+	// OnConflict - поведение при конфликте ограничений.
+	OnConflict ConflictAction
+	// UpsertClause - условие для ConflictUpdate, добавляется после VALUES как есть,
+	// например "ON CONFLICT (email) DO UPDATE SET name = excluded.name".
+	UpsertClause string
+}
+
+// BulkInsert вставляет rows в таблицу table многострочными INSERT ... VALUES,
+// разбивая строки на пачки так, чтобы число параметров не превышало лимит SQLite.
+// Возвращает суммарное число затронутых строк.
+//
+// Если q - транзакция (WithinTx, WithinSavepoint), все пачки выполняются в ней
+// и откатываются вместе с ней. Иначе каждая пачка - один запрос и, значит,
+// отдельная неявная транзакция: при ошибке уже вставленные пачки остаются.
+//
+// Все строки проверяются до первого запроса: число значений должно совпадать
+// с числом колонок, иначе возвращается ErrInvalidBulkInsert с видом shared.KindValidation.
+func BulkInsert(ctx context.Context, q Querier, table string, columns []string, rows [][]any, opts BulkOptions) (int64, error) {
+	batchSize, err := validateBulkInsert(table, columns, rows, opts)
+	if err != nil {
+		return 0, shared.MarkKind(err, shared.KindValidation)
+	}
+
+	var total int64
+	for start := 0; start < len(rows); start += batchSize {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		batch := rows[start:min(start+batchSize, len(rows))]
+		args := make([]any, 0, len(batch)*len(columns))
+		for _, row := range batch {
+			args = append(args, row...)
+		}
+
+		affected, err := Exec(ctx, q, buildBulkInsert(table, columns, len(batch), opts), args...)
+		if err != nil {
+			return total, fmt.Errorf("bulk insert into %s: rows %d-%d: %w", table, start, start+len(batch)-1, err)
+		}
+		total += affected
+	}
+	return total, nil
+}
+
+// validateBulkInsert проверяет аргументы BulkInsert и возвращает размер пачки.
+func validateBulkInsert(table string, columns []string, rows [][]any, opts BulkOptions) (int, error) {
+	if table == "" {
+		return 0, fmt.Errorf("%w: table is required", ErrInvalidBulkInsert)
+	}
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("%w: no columns", ErrInvalidBulkInsert)
+	}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return 0, fmt.Errorf("%w: row %d has %d values, want %d", ErrInvalidBulkInsert, i, len(row), len(columns))
+		}
+	}
+
+	switch opts.OnConflict {
+	case ConflictAbort, ConflictIgnore, ConflictReplace:
+		if opts.UpsertClause != "" {
+			return 0, fmt.Errorf("%w: UpsertClause requires ConflictUpdate", ErrInvalidBulkInsert)
+		}
+	case ConflictUpdate:
+		if opts.UpsertClause == "" {
+			return 0, fmt.Errorf("%w: ConflictUpdate requires UpsertClause", ErrInvalidBulkInsert)
+		}
+	default:
+		return 0, fmt.Errorf("%w: unknown conflict action %q", ErrInvalidBulkInsert, opts.OnConflict)
+	}
+
+	maxParams := opts.MaxParams
+	if maxParams <= 0 {
+		maxParams = MaxBoundParams
+	}
+	if len(columns) > maxParams {
+		return 0, fmt.Errorf("%w: %d columns exceed %d parameters", ErrInvalidBulkInsert, len(columns), maxParams)
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBulkBatchSize
+	}
+	return min(batchSize, maxParams/len(columns)), nil
+}
+
+// buildBulkInsert строит INSERT для n строк.
+func buildBulkInsert(table string, columns []string, n int, opts BulkOptions) string {
+	var b strings.Builder
+	b.WriteString("INSERT ")
+	if opts.OnConflict == ConflictIgnore || opts.OnConflict == ConflictReplace {
+		b.WriteString("OR " + string(opts.OnConflict) + " ")
+	}
+	b.WriteString("INTO " + quoteIdent(table) + " (")
+	for i, column := range columns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteIdent(column))
+	}
+	b.WriteString(") VALUES ")
+
+	placeholders := "(?" + strings.Repeat(", ?", len(columns)-1) + ")"
+	for i := range n {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(placeholders)
+	}
+
+	if opts.OnConflict == ConflictUpdate {
+		b.WriteString(" " + opts.UpsertClause)
+	}
+	return b.String()
+}
+
+// quoteIdent экранирует идентификатор двойными кавычками.
+// Имя со схемой ("main.users") экранируется по частям.
+func quoteIdent(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = `"` + strings.ReplaceAll(part, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,286 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+// countingQuerier считает запросы ExecContext.
+type countingQuerier struct {
+	Querier
+	execs int
+}
+
+func (c *countingQuerier) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	c.execs++
+	return c.Querier.ExecContext(ctx, query, args...)
+}
+
+func newBulkTestDB(t *testing.T) *TestDB {
+	t.Helper()
+	testDB := NewTestDBInMemory(t)
+	testDB.MustSeedData(t, "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT NOT NULL, qty INTEGER NOT NULL DEFAULT 0)")
+	return testDB
+}
+
+func makeItemRows(from, n int) [][]any {
+	rows := make([][]any, n)
+	for i := range rows {
+		rows[i] = []any{from + i, fmt.Sprintf("item-%d", from+i)}
+	}
+	return rows
+}
+
+func countItems(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	var n int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM items").Scan(&n))
+	return n
+}
+
+func TestBulkInsert_Batches(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      int
+		opts      BulkOptions
+		wantExecs int
+	}{
+		{name: "default batch size", rows: 2500, wantExecs: 25},
+		{name: "batch size", rows: 2500, opts: BulkOptions{BatchSize: 1000}, wantExecs: 3},
+		{name: "legacy limit", rows: 2500, opts: BulkOptions{BatchSize: 1000, MaxParams: MaxBoundParamsLegacy}, wantExecs: 6}, // 499 строк на пачку
+		{name: "batch size above limit", rows: 100, opts: BulkOptions{BatchSize: 100, MaxParams: 20}, wantExecs: 10},
+		{name: "no rows", rows: 0, wantExecs: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDB := newBulkTestDB(t)
+			q := &countingQuerier{Querier: testDB.DB}
+
+			n, err := BulkInsert(context.Background(), q, "items", []string{"id", "name"}, makeItemRows(1, tt.rows), tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, int64(tt.rows), n)
+			assert.Equal(t, tt.wantExecs, q.execs)
+			assert.Equal(t, tt.rows, countItems(t, testDB.DB))
+		})
+	}
+}
+
+func TestBulkInsert_OnConflict(t *testing.T) {
+	ctx := context.Background()
+	columns := []string{"id", "name", "qty"}
+	rows := [][]any{{1, "apple", 5}, {3, "cherry", 7}}
+
+	tests := []struct {
+		name         string
+		opts         BulkOptions
+		wantAffected int64
+		want         map[int64]string
+	}{
+		{
+			name:         "ignore",
+			opts:         BulkOptions{OnConflict: ConflictIgnore},
+			wantAffected: 1,
+			want:         map[int64]string{1: "apple/1", 2: "banana/2", 3: "cherry/7"},
+		},
+		{
+			name:         "replace",
+			opts:         BulkOptions{OnConflict: ConflictReplace},
+			wantAffected: 2,
+			want:         map[int64]string{1: "apple/5", 2: "banana/2", 3: "cherry/7"},
+		},
+		{
+			name: "upsert",
+			opts: BulkOptions{
+				OnConflict:   ConflictUpdate,
+				UpsertClause: "ON CONFLICT (id) DO UPDATE SET qty = qty + excluded.qty",
+			},
+			wantAffected: 2,
+			want:         map[int64]string{1: "apple/6", 2: "banana/2", 3: "cherry/7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDB := newBulkTestDB(t)
+			testDB.MustSeedData(t, "INSERT INTO items (id, name, qty) VALUES (1, 'apple', 1), (2, 'banana', 2)")
+
+			n, err := BulkInsert(ctx, testDB.DB, "items", columns, rows, tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAffected, n)
+
+			got, err := QueryMany(ctx, testDB.DB, func(rows *sql.Rows) (string, error) {
+				var (
+					id   int64
+					name string
+					qty  int
+				)
+				err := rows.Scan(&id, &name, &qty)
+				return fmt.Sprintf("%d:%s/%d", id, name, qty), err
+			}, "SELECT id, name, qty FROM items ORDER BY id")
+			require.NoError(t, err)
+			want := make([]string, 0, len(tt.want))
+			for id := int64(1); id <= int64(len(tt.want)); id++ {
+				want = append(want, fmt.Sprintf("%d:%s", id, tt.want[id]))
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+
+	// Без OnConflict конфликт - ошибка
+	testDB := newBulkTestDB(t)
+	testDB.MustSeedData(t, "INSERT INTO items (id, name) VALUES (1, 'apple')")
+	_, err := BulkInsert(ctx, testDB.DB, "items", columns, rows, BulkOptions{})
+	require.Error(t, err)
+	assert.True(t, shared.IsConflict(ClassifyError(err)))
+}
+
+func TestBulkInsert_Validation(t *testing.T) {
+	testDB := newBulkTestDB(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		table   string
+		columns []string
+		rows    [][]any
+		opts    BulkOptions
+	}{
+		{name: "no table", columns: []string{"id"}, rows: [][]any{{1}}},
+		{name: "no columns", table: "items", rows: [][]any{{1}}},
+		{name: "row too short", table: "items", columns: []string{"id", "name"}, rows: [][]any{{1, "a"}, {2}}},
+		{name: "row too long", table: "items", columns: []string{"id", "name"}, rows: [][]any{{1, "a", 3}}},
+		{name: "upsert without clause", table: "items", columns: []string{"id"}, rows: [][]any{{1}}, opts: BulkOptions{OnConflict: ConflictUpdate}},
+		{name: "clause without upsert", table: "items", columns: []string{"id"}, rows: [][]any{{1}}, opts: BulkOptions{UpsertClause: "ON CONFLICT DO NOTHING"}},
+		{name: "unknown action", table: "items", columns: []string{"id"}, rows: [][]any{{1}}, opts: BulkOptions{OnConflict: "FAIL"}},
+		{name: "too many columns", table: "items", columns: []string{"id", "name"}, rows: [][]any{{1, "a"}}, opts: BulkOptions{MaxParams: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BulkInsert(ctx, testDB.DB, tt.table, tt.columns, tt.rows, tt.opts)
+			require.ErrorIs(t, err, ErrInvalidBulkInsert)
+			assert.True(t, shared.IsValidation(err))
+		})
+	}
+
+	// Ни одна строка не вставлена, даже если некорректна только последняя
+	rows := append(makeItemRows(1, 10), []any{11})
+	_, err := BulkInsert(ctx, testDB.DB, "items", []string{"id", "name"}, rows, BulkOptions{BatchSize: 2})
+	require.ErrorIs(t, err, ErrInvalidBulkInsert)
+	assert.Contains(t, err.Error(), "row 10")
+	assert.Zero(t, countItems(t, testDB.DB))
+}
+
+func TestBulkInsert_Transactions(t *testing.T) {
+	ctx := context.Background()
+	columns := []string{"id", "name"}
+	errRollback := errors.New("rollback")
+
+	t.Run("within tx rolls back all batches", func(t *testing.T) {
+		testDB := newBulkTestDB(t)
+		err := testDB.TxRunner.WithinTx(ctx, func(ctx context.Context) error {
+			n, err := BulkInsert(ctx, testDB.TxRunner.GetQuerier(ctx), "items", columns, makeItemRows(1, 50), BulkOptions{BatchSize: 10})
+			require.NoError(t, err)
+			assert.Equal(t, int64(50), n)
+			return errRollback
+		})
+		require.ErrorIs(t, err, errRollback)
+		assert.Zero(t, countItems(t, testDB.DB))
+	})
+
+	t.Run("within savepoint rolls back to savepoint", func(t *testing.T) {
+		testDB := newBulkTestDB(t)
+		err := testDB.TxRunner.WithinTx(ctx, func(ctx context.Context) error {
+			q := testDB.TxRunner.GetQuerier(ctx)
+			if _, err := BulkInsert(ctx, q, "items", columns, makeItemRows(1, 5), BulkOptions{}); err != nil {
+				return err
+			}
+			spErr := testDB.TxRunner.WithinSavepoint(ctx, func(ctx context.Context) error {
+				_, err := BulkInsert(ctx, testDB.TxRunner.GetQuerier(ctx), "items", columns, makeItemRows(6, 20), BulkOptions{BatchSize: 3})
+				require.NoError(t, err)
+				return errRollback
+			})
+			assert.ErrorIs(t, spErr, errRollback)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 5, countItems(t, testDB.DB))
+	})
+
+	t.Run("without tx each batch commits on its own", func(t *testing.T) {
+		testDB := newBulkTestDB(t)
+		rows := makeItemRows(1, 10)
+		rows[7][0] = 1 // дубликат первичного ключа в третьей пачке
+
+		n, err := BulkInsert(ctx, testDB.DB, "items", columns, rows, BulkOptions{BatchSize: 3})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rows 6-8")
+		assert.Equal(t, int64(6), n)
+		assert.Equal(t, 6, countItems(t, testDB.DB), "первые две пачки остаются, третья откатывается целиком")
+	})
+}
+
+func TestBuildBulkInsert(t *testing.T) {
+	query := buildBulkInsert(`main.my "table"`, []string{"id", "name"}, 2, BulkOptions{OnConflict: ConflictIgnore})
+	assert.Equal(t, `INSERT OR IGNORE INTO "main"."my ""table""" ("id", "name") VALUES (?, ?), (?, ?)`, query)
+
+	query = buildBulkInsert("items", []string{"id"}, 1, BulkOptions{OnConflict: ConflictUpdate, UpsertClause: "ON CONFLICT DO NOTHING"})
+	assert.Equal(t, `INSERT INTO "items" ("id") VALUES (?) ON CONFLICT DO NOTHING`, query)
+}
+
+// BenchmarkInsert сравнивает BulkInsert с построчными INSERT в одной транзакции.
+func BenchmarkInsert(b *testing.B) {
+	const rowsPerOp = 1000
+	ctx := context.Background()
+	columns := []string{"id", "name"}
+
+	run := func(b *testing.B, insert func(ctx context.Context, q Querier, rows [][]any) error) {
+		db, err := NewInMemoryDB(ctx)
+		require.NoError(b, err)
+		b.Cleanup(func() { _ = db.Close() })
+		_, err = db.ExecContext(ctx, "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT NOT NULL)")
+		require.NoError(b, err)
+		runner := NewTxRunner(db)
+
+		b.ResetTimer()
+		for i := range b.N {
+			rows := makeItemRows(i*rowsPerOp, rowsPerOp)
+			err := runner.WithinTx(ctx, func(ctx context.Context) error {
+				return insert(ctx, runner.GetQuerier(ctx), rows)
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.Run("row-at-a-time", func(b *testing.B) {
+		query := `INSERT INTO items (id, name) VALUES (?, ?)`
+		run(b, func(ctx context.Context, q Querier, rows [][]any) error {
+			for _, row := range rows {
+				if _, err := q.ExecContext(ctx, query, row...); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+
+	for _, batch := range []int{10, DefaultBulkBatchSize, rowsPerOp} {
+		b.Run(fmt.Sprintf("bulk/batch=%d", batch), func(b *testing.B) {
+			run(b, func(ctx context.Context, q Querier, rows [][]any) error {
+				_, err := BulkInsert(ctx, q, "items", columns, rows, BulkOptions{BatchSize: batch})
+				return err
+			})
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"sttbot/internal/shared"
+)
+
+// ConnectionInitFunc настраивает новое соединение пула до его первого использования.
+// conn привязан к одному физическому соединению; закрывать его не нужно.
+type ConnectionInitFunc func(ctx context.Context, conn *sql.Conn) error
+
+// initConnector открывает соединения драйвером и пропускает каждое через ConnectionInit.
+type initConnector struct {
+	driver driver.Driver
+	dsn    string
+	init   ConnectionInitFunc
+}
+
+var _ driver.Connector = (*initConnector)(nil)
+
+// Connect открывает соединение и выполняет на нём хук, если он задан. При ошибке хука
+// соединение закрывается, а ошибка помечается shared.KindDependencyFailure.
+func (c *initConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil || c.init == nil {
+		return conn, err
+	}
+	if err := runConnectionInit(ctx, conn, c.init); err != nil {
+		_ = conn.Close()
+		return nil, shared.MarkKind(fmt.Errorf("connection init: %w", err), shared.KindDependencyFailure)
+	}
+	return conn, nil
+}
+
+// Driver возвращает драйвер, которым открываются соединения.
+func (c *initConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// runConnectionInit выполняет хук через временный пул из одного соединения:
+// так хук получает обычный *sql.Conn, а физическое соединение остаётся открытым.
+func runConnectionInit(ctx context.Context, conn driver.Conn, init ConnectionInitFunc) error {
+	db := sql.OpenDB(singleConnector{conn: initConn{Conn: conn}})
+	db.SetMaxOpenConns(1)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	sqlConn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = sqlConn.Close()
+	}()
+	return init(ctx, sqlConn)
+}
+
+// singleConnector всегда возвращает одно и то же соединение.
+type singleConnector struct {
+	conn driver.Conn
+}
+
+func (c singleConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.conn, nil
+}
+
+func (c singleConnector) Driver() driver.Driver {
+	return nil
+}
+
+// initConn передаёт вызовы соединению драйвера, но не закрывает его:
+// закрытие временного пула не должно закрывать соединение основного пула.
+type initConn struct {
+	driver.Conn
+}
+
+func (c initConn) Close() error {
+	return nil
+}
+
+func (c initConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := c.Conn.(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c initConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := c.Conn.(driver.QueryerContext); ok {
+		return queryer.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c initConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c initConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+func TestConnectionInit_EveryPooledConnection(t *testing.T) {
+	ctx := context.Background()
+
+	var calls atomic.Int32
+	opts := DefaultDBOptions()
+	opts.MaxOpenConns = 4
+	opts.MaxIdleConns = 4
+	opts.ConnectionInit = func(ctx context.Context, conn *sql.Conn) error {
+		calls.Add(1)
+		// cache_size действует в пределах соединения
+		_, err := conn.ExecContext(ctx, "PRAGMA cache_size = -4321")
+		return err
+	}
+	db, err := NewDBWithOptions(ctx, filepath.Join(t.TempDir(), "init.db"), opts)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.ExecContext(ctx, "CREATE TABLE items (id INTEGER PRIMARY KEY)")
+	require.NoError(t, err)
+
+	// Нагрузка заставляет пул открывать новые соединения после создания БД
+	var wg sync.WaitGroup
+	for range 32 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var n int
+			if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM items").Scan(&n); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Удерживаем все соединения одновременно и проверяем каждое
+	conns := make([]*sql.Conn, opts.MaxOpenConns)
+	for i := range conns {
+		conns[i], err = db.Conn(ctx)
+		require.NoError(t, err)
+		defer conns[i].Close()
+	}
+	for i, conn := range conns {
+		var cacheSize int
+		require.NoError(t, conn.QueryRowContext(ctx, "PRAGMA cache_size").Scan(&cacheSize))
+		assert.Equal(t, -4321, cacheSize, "conn %d", i)
+
+		// Настройки из DSN применяются и вместе с хуком
+		var foreignKeys int
+		require.NoError(t, conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys))
+		assert.Equal(t, 1, foreignKeys, "conn %d", i)
+	}
+	assert.Equal(t, int32(db.Stats().OpenConnections), calls.Load())
+}
+
+func TestConnectionInit_Failure(t *testing.T) {
+	ctx := context.Background()
+	errKey := errors.New("wrong key")
+
+	t.Run("при создании БД", func(t *testing.T) {
+		opts := DefaultDBOptions()
+		opts.ConnectionInit = func(ctx context.Context, conn *sql.Conn) error {
+			return errKey
+		}
+		db, err := NewDBWithOptions(ctx, filepath.Join(t.TempDir(), "fail.db"), opts)
+		require.Error(t, err)
+		assert.Nil(t, db)
+		assert.ErrorIs(t, err, errKey)
+		assert.Equal(t, shared.KindDependencyFailure, shared.KindOf(err))
+	})
+
+	t.Run("на новом соединении", func(t *testing.T) {
+		var calls atomic.Int32
+		opts := DefaultDBOptions()
+		opts.ConnectionInit = func(ctx context.Context, conn *sql.Conn) error {
+			if calls.Add(1) > 1 {
+				return errKey
+			}
+			return nil
+		}
+		db, err := NewDBWithOptions(ctx, filepath.Join(t.TempDir(), "fail.db"), opts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		first, err := db.Conn(ctx)
+		require.NoError(t, err)
+		defer first.Close()
+
+		// Первое соединение занято, пул открывает второе, и хук отклоняет его
+		_, err = db.Conn(ctx)
+		assert.ErrorIs(t, err, errKey)
+		assert.Equal(t, shared.KindDependencyFailure, shared.KindOf(err))
+		assert.Equal(t, 1, db.Stats().OpenConnections)
+
+		// Сломанное соединение не попало в пул, первое продолжает работать
+		var one int
+		require.NoError(t, first.QueryRowContext(ctx, "SELECT 1").Scan(&one))
+	})
+}
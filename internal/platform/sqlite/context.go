@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+
+	"sttbot/internal/shared"
+)
+
+// ErrNoRunner возвращается, если в контексте нет TxRunner.
+var ErrNoRunner = errors.New("sqlite: no TxRunner in context")
+
+// runnerKey используется как ключ для хранения TxRunner в context.Context
+type runnerKey struct{}
+
+// WithRunner сохраняет TxRunner в контексте. Обычно вызывается один раз
+// при обработке запроса или в middleware, после чего репозитории получают
+// доступ к БД через QuerierFrom, а сервисы - к транзакциям через WithinTx.
+func WithRunner(ctx context.Context, r *TxRunner) context.Context {
+	return context.WithValue(ctx, runnerKey{}, r)
+}
+
+// RunnerFrom извлекает TxRunner из контекста.
+func RunnerFrom(ctx context.Context) (*TxRunner, bool) {
+	r, ok := ctx.Value(runnerKey{}).(*TxRunner)
+	return r, ok && r != nil
+}
+
+// WithinTx выполняет fn в транзакции TxRunner из контекста (см. TxRunner.WithinTx).
+// Если в контексте нет TxRunner, возвращает ErrNoRunner с видом shared.KindInternal.
+func WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	r, ok := RunnerFrom(ctx)
+	if !ok {
+		return shared.MarkKind(ErrNoRunner, shared.KindInternal)
+	}
+	return r.WithinTx(ctx, fn)
+}
+
+// QuerierFrom возвращает объект для выполнения запросов из контекста:
+// активную транзакцию, если она есть, иначе TxRunner.GetQuerier для TxRunner из контекста.
+// Паникует, если в контексте нет ни транзакции, ни TxRunner: это ошибка сборки зависимостей.
+func QuerierFrom(ctx context.Context) Querier {
+	if r, ok := RunnerFrom(ctx); ok {
+		// GetQuerier сам отдаёт транзакцию из контекста и учитывает кэш выражений
+		return r.GetQuerier(ctx)
+	}
+	if querier, ok := GetTxQuerier(ctx); ok {
+		return querier
+	}
+	panic(ErrNoRunner)
+}
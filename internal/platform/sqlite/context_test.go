@@ -0,0 +1,140 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+// accountRepo и auditRepo - репозитории, которые знают только о контексте.
+type accountRepo struct{}
+
+func (accountRepo) Create(ctx context.Context, name string) error {
+	_, err := QuerierFrom(ctx).ExecContext(ctx, "INSERT INTO accounts (name) VALUES (?)", name)
+	return err
+}
+
+type auditRepo struct{}
+
+func (auditRepo) Log(ctx context.Context, event string) error {
+	_, err := QuerierFrom(ctx).ExecContext(ctx, "INSERT INTO audit (event) VALUES (?)", event)
+	return err
+}
+
+// newRepoTestDB создаёт БД с таблицами accounts и audit.
+func newRepoTestDB(t *testing.T) *TxRunner {
+	t.Helper()
+	ctx := context.Background()
+	db, err := NewInMemoryDB(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE accounts (name TEXT NOT NULL UNIQUE);
+		CREATE TABLE audit (event TEXT NOT NULL);
+	`)
+	require.NoError(t, err)
+	return NewTxRunner(db)
+}
+
+func TestWithinTx_ReposShareTransaction(t *testing.T) {
+	runner := newRepoTestDB(t)
+	ctx := WithRunner(context.Background(), runner)
+	accounts, audit := accountRepo{}, auditRepo{}
+
+	err := WithinTx(ctx, func(ctx context.Context) error {
+		if err := accounts.Create(ctx, "alice"); err != nil {
+			return err
+		}
+		return audit.Log(ctx, "account created")
+	})
+	require.NoError(t, err)
+
+	// Ошибка второго репозитория откатывает запись первого
+	err = WithinTx(ctx, func(ctx context.Context) error {
+		if err := audit.Log(ctx, "duplicate attempt"); err != nil {
+			return err
+		}
+		return accounts.Create(ctx, "alice")
+	})
+	require.Error(t, err)
+
+	var accountsCount, auditCount int
+	require.NoError(t, QuerierFrom(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM accounts").Scan(&accountsCount))
+	require.NoError(t, QuerierFrom(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM audit").Scan(&auditCount))
+	assert.Equal(t, 1, accountsCount)
+	assert.Equal(t, 1, auditCount)
+}
+
+func TestRunnerFrom(t *testing.T) {
+	runner := newRepoTestDB(t)
+
+	_, ok := RunnerFrom(context.Background())
+	assert.False(t, ok)
+
+	got, ok := RunnerFrom(WithRunner(context.Background(), runner))
+	assert.True(t, ok)
+	assert.Same(t, runner, got)
+}
+
+func TestWithinTx_NoRunner(t *testing.T) {
+	err := WithinTx(context.Background(), func(ctx context.Context) error { return nil })
+	require.ErrorIs(t, err, ErrNoRunner)
+	assert.True(t, shared.IsInternal(err))
+}
+
+func TestQuerierFrom(t *testing.T) {
+	runner := newRepoTestDB(t)
+	ctx := WithRunner(context.Background(), runner)
+
+	assert.Equal(t, runner.DB, QuerierFrom(ctx))
+
+	err := runner.WithinTx(context.Background(), func(txCtx context.Context) error {
+		// Транзакция в контексте используется и без TxRunner
+		tx, ok := SqlTx(txCtx)
+		require.True(t, ok)
+		assert.Equal(t, tx, QuerierFrom(txCtx))
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.PanicsWithValue(t, ErrNoRunner, func() { QuerierFrom(context.Background()) })
+}
+
+func ExampleWithinTx() {
+	db, err := NewInMemoryDB(context.Background())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer db.Close()
+	_, _ = db.Exec("CREATE TABLE accounts (name TEXT); CREATE TABLE audit (event TEXT)")
+
+	// Runner кладётся в контекст один раз, репозитории его не хранят
+	ctx := WithRunner(context.Background(), NewTxRunner(db))
+	accounts, audit := accountRepo{}, auditRepo{}
+
+	err = WithinTx(ctx, func(ctx context.Context) error {
+		if err := accounts.Create(ctx, "bob"); err != nil {
+			return err
+		}
+		if err := audit.Log(ctx, "account created"); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	fmt.Println(err)
+
+	var n int
+	_ = QuerierFrom(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM accounts").Scan(&n)
+	fmt.Println("accounts after rollback:", n)
+	// Output:
+	// abort
+	// accounts after rollback: 0
+}
@@ -3,6 +3,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -60,8 +61,35 @@ type DBOptions struct {
 	EnableWriteQueue bool
 	// WriteQueueSize - размер буфера очереди записи (по умолчанию 100)
 	WriteQueueSize int
+	// WriteQueueMaxWait - максимальное время ожидания места в очереди записи (0 - без ограничения,
+	// ждать до отмены контекста). Не принятый за это время запрос завершается
+	// ErrWriteQueueTimeout с shared.KindTimeout, не дожидаясь дедлайна контекста.
+	WriteQueueMaxWait time.Duration
 	// AccessMode - режим доступа к базе данных
 	AccessMode AccessMode
+	// RetryConfig - настройки ретраев транзакций на SQLITE_BUSY (nil - DefaultRetryConfig)
+	RetryConfig *RetryConfig
+	// ClassifyErrors - помечать ошибки TxRunner видами из пакета shared через ClassifyError
+	ClassifyErrors bool
+	// StmtCacheSize - размер LRU-кэша подготовленных выражений TxRunner.GetQuerier (0 - без кэша)
+	StmtCacheSize int
+	// QueryHook - хук, вызываемый после каждого запроса через TxRunner.GetQuerier (nil - без хука).
+	// См. SlowQueryLogger
+	QueryHook QueryHook
+	// QueryHookMaxSQL - максимальная длина QueryInfo.SQL в байтах (0 - без усечения)
+	QueryHookMaxSQL int
+	// ConnectionInit - хук, выполняемый на каждом новом соединении пула до его использования
+	// (nil - без хука), например PRAGMA key для сборок с расширением шифрования.
+	// Выполняется после PRAGMA из DSN (busy_timeout, foreign_keys и т.д.), но до
+	// journal_mode = WAL. Ошибка хука закрывает соединение и возвращается помеченной
+	// shared.KindDependencyFailure.
+	ConnectionInit ConnectionInitFunc
+	// ExclusiveOwner - захватить при открытии advisory-блокировку ExclusiveOwnerLock и держать
+	// её до закрытия БД. Если базу уже открыл другой владелец (например, второй экземпляр бота),
+	// NewDBWithOptions сразу возвращает ErrDatabaseOwned вместо SQLITE_BUSY при записи.
+	// Блокировка хранится в LocksTable; после аварийного завершения владельца она истекает
+	// через ExclusiveOwnerTTL. Требует режима чтения и записи.
+	ExclusiveOwner bool
 }
 
 // DefaultDBOptions возвращает настройки по умолчанию, оптимизированные для embedded использования.
@@ -130,7 +158,8 @@ func NewDBFromDSN(ctx context.Context, dsn string) (*sql.DB, error) {
 	}
 
 	// Примечание: PRAGMA настройки не применяются автоматически при использовании DSN.
-	// Если нужны PRAGMA настройки, используйте NewDBWithOptions().
+	// Если нужны PRAGMA настройки, используйте NewDBWithOptions() или параметры
+	// _pragma=name(value) в DSN - драйвер применяет их к каждому соединению.
 
 	return db, nil
 }
@@ -151,6 +180,18 @@ func NewDBWithOptions(ctx context.Context, dbPath string, opts DBOptions) (*sql.
 	if err != nil {
 		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
 	}
+	var owner *ownerLock
+	if opts.ConnectionInit != nil || opts.ExclusiveOwner {
+		// sql.Open не открывает соединений, поэтому пул можно сразу заменить
+		// пулом с тем же драйвером, пропускающим соединения через хук
+		var connector driver.Connector = &initConnector{driver: db.Driver(), dsn: dsn, init: opts.ConnectionInit}
+		if opts.ExclusiveOwner {
+			owner = &ownerLock{connector: connector}
+			connector = &ownerConnector{Connector: connector, owner: owner}
+		}
+		_ = db.Close()
+		db = sql.OpenDB(connector)
+	}
 
 	// Применяем настройки соединения
 	db.SetConnMaxLifetime(opts.ConnMaxLifetime)
@@ -166,17 +207,26 @@ func NewDBWithOptions(ctx context.Context, dbPath string, opts DBOptions) (*sql.
 		return nil, fmt.Errorf("failed to ping sqlite database: %w", err)
 	}
 
-	// Применяем PRAGMA настройки после открытия соединения
+	// Применяем настройки уровня файла БД; настройки соединений заданы в DSN
 	if err := applyPragmaSettings(ctx, db, opts); err != nil {
 		_ = db.Close()
 		return nil, fmt.Errorf("failed to apply PRAGMA settings: %w", err)
 	}
 
+	if owner != nil {
+		if err := owner.acquire(ctx, dbPath); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+
 	return db, nil
 }
 
-// buildDSN строит DSN строку для SQLite с минимальными параметрами.
-// Большинство настроек теперь применяется через PRAGMA после открытия.
+// buildDSN строит DSN строку для SQLite.
+// Настройки, действующие в пределах соединения (busy_timeout, foreign_keys, synchronous),
+// передаются параметрами _pragma: драйвер выполняет их при открытии каждого
+// соединения пула, а не только первого.
 func buildDSN(dbPath string, opts DBOptions) string {
 	params := []string{}
 
@@ -185,18 +235,21 @@ func buildDSN(dbPath string, opts DBOptions) string {
 		params = append(params, fmt.Sprintf("mode=%s", opts.AccessMode))
 	}
 
-	// Добавляем только базовые параметры через DSN
+	// Устанавливаем busy timeout если указан
 	if opts.BusyTimeout > 0 {
 		timeoutMs := int(opts.BusyTimeout.Milliseconds())
-		params = append(params, fmt.Sprintf("_busy_timeout=%d", timeoutMs))
+		params = append(params, fmt.Sprintf("_pragma=busy_timeout(%d)", timeoutMs))
 	}
 
-	// Если есть параметры - добавляем их к пути
-	if len(params) > 0 {
-		return dbPath + "?" + strings.Join(params, "&")
+	// Включаем проверку внешних ключей
+	if opts.ForeignKeys {
+		params = append(params, "_pragma=foreign_keys(1)")
 	}
 
-	return dbPath
+	// Устанавливаем уровень синхронизации
+	params = append(params, "_pragma=synchronous(NORMAL)")
+
+	return dbPath + "?" + strings.Join(params, "&")
 }
 
 // NewInMemoryDB создает in-memory SQLite базу данных для тестов.
@@ -242,34 +295,13 @@ func CleanupTestDB(db *sql.DB, dbPath string) error {
 	return nil
 }
 
-// applyPragmaSettings применяет PRAGMA настройки к открытому соединению.
-// Это обеспечивает надёжность применения настроек независимо от драйвера.
+// applyPragmaSettings применяет PRAGMA настройки уровня файла БД.
+// journal_mode=WAL сохраняется в самом файле, поэтому достаточно выполнить его один раз;
+// настройки соединений применяются драйвером по DSN (см. buildDSN).
 func applyPragmaSettings(ctx context.Context, db *sql.DB, opts DBOptions) error {
-	pragmas := make([]string, 0, 5)
-
-	// Включаем проверку внешних ключей
-	if opts.ForeignKeys {
-		pragmas = append(pragmas, "PRAGMA foreign_keys = ON")
-	}
-
-	// Устанавливаем режим журнала
 	if opts.WALMode {
-		pragmas = append(pragmas, "PRAGMA journal_mode = WAL")
-	}
-
-	// Устанавливаем уровень синхронизации
-	pragmas = append(pragmas, "PRAGMA synchronous = NORMAL")
-
-	// Устанавливаем busy timeout если указан
-	if opts.BusyTimeout > 0 {
-		timeoutMs := int(opts.BusyTimeout.Milliseconds())
-		pragmas = append(pragmas, fmt.Sprintf("PRAGMA busy_timeout = %d", timeoutMs))
-	}
-
-	// Применяем все PRAGMA настройки
-	for _, pragma := range pragmas {
-		if _, err := db.ExecContext(ctx, pragma); err != nil {
-			return fmt.Errorf("failed to execute %s: %w", pragma, err)
+		if _, err := db.ExecContext(ctx, "PRAGMA journal_mode = WAL"); err != nil {
+			return fmt.Errorf("failed to execute PRAGMA journal_mode = WAL: %w", err)
 		}
 	}
 
@@ -2,9 +2,11 @@ package sqlite
 
 import (
 	"context"
+	"database/sql"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -40,7 +42,7 @@ func TestBuildDSN(t *testing.T) {
 			name:     "default options",
 			dbPath:   "/tmp/test.db",
 			opts:     DefaultDBOptions(),
-			expected: "/tmp/test.db?_busy_timeout=5000",
+			expected: "/tmp/test.db?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_pragma=synchronous(NORMAL)",
 		},
 		{
 			name:   "without busy timeout",
@@ -48,7 +50,7 @@ func TestBuildDSN(t *testing.T) {
 			opts: DBOptions{
 				BusyTimeout: 0,
 			},
-			expected: ":memory:",
+			expected: ":memory:?_pragma=synchronous(NORMAL)",
 		},
 		{
 			name:   "custom busy timeout",
@@ -56,7 +58,7 @@ func TestBuildDSN(t *testing.T) {
 			opts: DBOptions{
 				BusyTimeout: 10 * time.Second,
 			},
-			expected: "test.db?_busy_timeout=10000",
+			expected: "test.db?_pragma=busy_timeout(10000)&_pragma=synchronous(NORMAL)",
 		},
 		{
 			name:   "read only mode",
@@ -64,7 +66,7 @@ func TestBuildDSN(t *testing.T) {
 			opts: DBOptions{
 				AccessMode: AccessModeReadOnly,
 			},
-			expected: "test.db?mode=ro",
+			expected: "test.db?mode=ro&_pragma=synchronous(NORMAL)",
 		},
 		{
 			name:   "read write create mode with timeout",
@@ -73,7 +75,7 @@ func TestBuildDSN(t *testing.T) {
 				AccessMode:  AccessModeReadWriteCreate,
 				BusyTimeout: 2 * time.Second,
 			},
-			expected: "test.db?mode=rwc&_busy_timeout=2000",
+			expected: "test.db?mode=rwc&_pragma=busy_timeout(2000)&_pragma=synchronous(NORMAL)",
 		},
 	}
 
@@ -278,6 +280,46 @@ func TestPragmaSettings(t *testing.T) {
 	assert.Equal(t, "1", synchronous)
 }
 
+func TestPragmaSettings_EveryPooledConnection(t *testing.T) {
+	ctx := context.Background()
+
+	opts := DefaultDBOptions()
+	opts.MaxOpenConns = 4
+	opts.MaxIdleConns = 4
+	db, err := NewDBWithOptions(ctx, filepath.Join(t.TempDir(), "pool.db"), opts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Удерживаем все соединения одновременно, чтобы пул открыл новые
+	conns := make([]*sql.Conn, opts.MaxOpenConns)
+	for i := range conns {
+		conns[i], err = db.Conn(ctx)
+		require.NoError(t, err)
+		defer conns[i].Close()
+	}
+	assert.Equal(t, opts.MaxOpenConns, db.Stats().OpenConnections)
+
+	var wg sync.WaitGroup
+	for i, conn := range conns {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var foreignKeys, busyTimeout int
+			if err := conn.QueryRowContext(ctx, "PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+				t.Errorf("conn %d: %v", i, err)
+				return
+			}
+			if err := conn.QueryRowContext(ctx, "PRAGMA busy_timeout").Scan(&busyTimeout); err != nil {
+				t.Errorf("conn %d: %v", i, err)
+				return
+			}
+			assert.Equal(t, 1, foreignKeys, "conn %d", i)
+			assert.Equal(t, 5000, busyTimeout, "conn %d", i)
+		}()
+	}
+	wg.Wait()
+}
+
 func TestNewReadOnlyDB(t *testing.T) {
 	ctx := context.Background()
 
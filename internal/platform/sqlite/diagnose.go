@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// diagnoseBusyTimeout - busy_timeout пробного соединения DiagnoseLock: проба должна
+// отличить занятую блокировку записи от свободной, а не дождаться её.
+const diagnoseBusyTimeout = 100 * time.Millisecond
+
+// recentWALWrite - насколько свежим должно быть изменение WAL файла, чтобы
+// DiagnoseLock считал, что в базу недавно писал другой процесс.
+const recentWALWrite = time.Minute
+
+// LockReport содержит результаты диагностики блокировок файла БД (см. DiagnoseLock).
+type LockReport struct {
+	// Path - путь к файлу базы
+	Path string `json:"path"`
+	// JournalMode - режим журнала (wal, delete, ...)
+	JournalMode string `json:"journal_mode"`
+	// HasWriter - BEGIN IMMEDIATE не удался: другое соединение держит блокировку записи
+	HasWriter bool `json:"has_writer"`
+	// ProbeWait - сколько проба BEGIN IMMEDIATE ждала блокировку
+	ProbeWait time.Duration `json:"probe_wait"`
+	// CheckpointBusy - PRAGMA wal_checkpoint(PASSIVE) не смог перенести WAL целиком
+	// из-за читателей или писателей
+	CheckpointBusy bool `json:"checkpoint_busy"`
+	// WALFrames - количество кадров в WAL по данным wal_checkpoint (-1 вне WAL режима)
+	WALFrames int64 `json:"wal_frames"`
+	// CheckpointedFrames - количество перенесённых в базу кадров WAL (-1 вне WAL режима)
+	CheckpointedFrames int64 `json:"checkpointed_frames"`
+	// WALExists - WAL файл существовал до открытия пробного соединения,
+	// то есть базу держит открытой другое соединение или процесс завершился аварийно
+	WALExists bool `json:"wal_exists"`
+	// WALSize - размер WAL файла в байтах до открытия пробного соединения
+	WALSize int64 `json:"wal_size"`
+	// WALModTime - время последнего изменения WAL файла (нулевое, если файла нет)
+	WALModTime time.Time `json:"wal_mod_time,omitzero"`
+	// Hints - рекомендации по найденным проблемам
+	Hints []string `json:"hints,omitempty"`
+}
+
+// DiagnoseLock проверяет, не работает ли с файлом БД другой процесс, например
+// второй экземпляр бота, из-за которого запросы получают SQLITE_BUSY.
+// Открывает короткоживущее соединение с busy_timeout 100мс, пробует BEGIN IMMEDIATE,
+// читает режим журнала и состояние wal_checkpoint(PASSIVE), а также размер и время
+// изменения WAL файла. Проверка WAL файла приблизительна: файл остаётся и после
+// аварийного завершения процесса.
+//
+// Транзакция пробы сразу откатывается, но PASSIVE checkpoint переносит в базу
+// кадры WAL, которые не заняты читателями. Файл базы должен существовать.
+func DiagnoseLock(ctx context.Context, dbPath string) (LockReport, error) {
+	report := LockReport{Path: dbPath, WALFrames: -1, CheckpointedFrames: -1}
+
+	if _, err := os.Stat(dbPath); err != nil {
+		return report, fmt.Errorf("failed to stat database file: %w", err)
+	}
+	// WAL файл проверяется до открытия соединения: закрытие последнего
+	// соединения выполняет checkpoint и удаляет WAL
+	if info, err := os.Stat(dbPath + "-wal"); err == nil {
+		report.WALExists = true
+		report.WALSize = info.Size()
+		report.WALModTime = info.ModTime()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return report, fmt.Errorf("failed to stat WAL file: %w", err)
+	}
+
+	dsn := fmt.Sprintf("%s?mode=rw&_pragma=busy_timeout(%d)", dbPath, diagnoseBusyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return report, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	defer db.Close()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&report.JournalMode); err != nil {
+		return report, fmt.Errorf("failed to read journal_mode: %w", err)
+	}
+
+	start := time.Now()
+	_, err = conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+	report.ProbeWait = time.Since(start)
+	switch {
+	case err == nil:
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "ROLLBACK"); err != nil {
+			return report, fmt.Errorf("failed to rollback probe transaction: %w", err)
+		}
+	case IsBusyError(err):
+		report.HasWriter = true
+	default:
+		return report, fmt.Errorf("failed to probe write lock: %w", err)
+	}
+
+	if report.JournalMode == "wal" {
+		var busy int
+		err := conn.QueryRowContext(ctx, "PRAGMA wal_checkpoint(PASSIVE)").
+			Scan(&busy, &report.WALFrames, &report.CheckpointedFrames)
+		if err != nil {
+			return report, fmt.Errorf("failed to read wal_checkpoint: %w", err)
+		}
+		report.CheckpointBusy = busy != 0 || report.CheckpointedFrames < report.WALFrames
+	}
+
+	report.Hints = lockHints(report, time.Now())
+	return report, nil
+}
+
+// lockHints формирует рекомендации по отчёту DiagnoseLock.
+func lockHints(r LockReport, now time.Time) []string {
+	var hints []string
+	if r.HasWriter {
+		hints = append(hints, fmt.Sprintf("another connection holds the write lock; if no writer should be running, "+
+			"check for a second bot instance using this file (lsof %s) or enable DBOptions.ExclusiveOwner", r.Path))
+	}
+	if r.CheckpointBusy {
+		hints = append(hints, "WAL checkpoint could not complete: another connection keeps a read or write "+
+			"transaction open; long-running transactions prevent WAL from being reset")
+	}
+	if r.WALSize > DefaultMaxWALSize {
+		hints = append(hints, fmt.Sprintf("WAL file is %d bytes: checkpoints do not keep up with writes, "+
+			"run Maintain with CheckpointRestart when the database is idle", r.WALSize))
+	}
+	if r.WALExists && !r.HasWriter && now.Sub(r.WALModTime) < recentWALWrite {
+		hints = append(hints, fmt.Sprintf("WAL file was modified %s ago: another process is likely "+
+			"using the database", now.Sub(r.WALModTime).Round(time.Second)))
+	}
+	if r.JournalMode != "" && r.JournalMode != "wal" {
+		hints = append(hints, fmt.Sprintf("journal mode is %q: readers block the writer, enable DBOptions.WALMode", r.JournalMode))
+	}
+	return hints
+}
@@ -0,0 +1,75 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnoseLock_Idle(t *testing.T) {
+	testDB := NewTestDBFile(t)
+
+	report, err := DiagnoseLock(context.Background(), testDB.Path)
+	require.NoError(t, err)
+	assert.Equal(t, testDB.Path, report.Path)
+	assert.Equal(t, "wal", report.JournalMode)
+	assert.False(t, report.HasWriter)
+	assert.False(t, report.CheckpointBusy)
+	assert.GreaterOrEqual(t, report.WALFrames, int64(0))
+	assert.Less(t, report.ProbeWait, diagnoseBusyTimeout)
+}
+
+func TestDiagnoseLock_WriterHeld(t *testing.T) {
+	ctx := context.Background()
+	testDB := NewTestDBFile(t)
+
+	// Другой "процесс" держит открытую транзакцию записи
+	conn, err := testDB.DB.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "CREATE TABLE busy (id INTEGER)")
+	require.NoError(t, err)
+	defer func() { _, _ = conn.ExecContext(ctx, "ROLLBACK") }()
+
+	report, err := DiagnoseLock(ctx, testDB.Path)
+	require.NoError(t, err)
+	assert.True(t, report.HasWriter)
+	assert.GreaterOrEqual(t, report.ProbeWait, diagnoseBusyTimeout/2, "проба ждала busy_timeout")
+	assert.True(t, report.WALExists)
+	require.NotEmpty(t, report.Hints)
+	assert.Contains(t, report.Hints[0], "second bot instance")
+}
+
+func TestDiagnoseLock_MissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.db")
+
+	_, err := DiagnoseLock(context.Background(), path)
+	require.Error(t, err)
+	assert.NoFileExists(t, path, "диагностика не создаёт базу")
+}
+
+func TestLockHints(t *testing.T) {
+	now := time.Now()
+
+	assert.Empty(t, lockHints(LockReport{JournalMode: "wal"}, now))
+
+	hints := lockHints(LockReport{
+		JournalMode:    "delete",
+		CheckpointBusy: true,
+		WALExists:      true,
+		WALSize:        DefaultMaxWALSize + 1,
+		WALModTime:     now.Add(-5 * time.Second),
+	}, now)
+	require.Len(t, hints, 4)
+	assert.Contains(t, hints[0], "checkpoint could not complete")
+	assert.Contains(t, hints[1], "checkpoints do not keep up")
+	assert.Contains(t, hints[2], "modified 5s ago")
+	assert.True(t, strings.HasPrefix(hints[3], `journal mode is "delete"`))
+}
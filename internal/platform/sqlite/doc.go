@@ -3,9 +3,14 @@
 // Основные возможности:
 // - Инициализация БД с оптимизированными настройками
 // - Управление транзакциями с поддержкой savepoints
+// - Массовая вставка с разбиением на пачки (BulkInsert)
 // - Система миграций с кроссплатформенной поддержкой
 // - Управление конкуренцией записи (ретраи, очереди, блокировки)
 // - Режимы доступа (read-only, read-write-create)
+// - Онлайн-резервное копирование и восстановление
+// - Проверка целостности и состояния базы (HealthCheck)
+// - Уведомления об изменениях строк таблицы (Watch)
+// - Advisory-блокировки между экземплярами приложения (Lock, LockedJob)
 // - Тестовые хелперы для удобного тестирования
 //
 // # Быстрый старт
@@ -30,6 +35,33 @@
 //		return err
 //	})
 //
+// Типизированные хелперы для запросов (работают с *sql.DB, *sql.Tx и транзакцией из контекста):
+//
+//	user, err := sqlite.QueryOne(ctx, runner.GetQuerier(ctx), scanUser, "SELECT id, name FROM users WHERE id = ?", id)
+//	if shared.IsNotFound(err) { ... }
+//	users, err := sqlite.QueryMany(ctx, runner.GetQuerier(ctx), scanUsers, "SELECT id, name FROM users")
+//	affected, err := sqlite.Exec(ctx, runner.GetQuerier(ctx), "DELETE FROM users WHERE id = ?", id)
+//
+// Массовая вставка многострочными INSERT с разбиением на пачки (внутри транзакции
+// все пачки откатываются вместе, без неё каждая пачка коммитится отдельно):
+//
+//	rows := [][]any{{1, "alice"}, {2, "bob"}}
+//	n, err := sqlite.BulkInsert(ctx, runner.GetQuerier(ctx), "users", []string{"id", "name"}, rows, sqlite.BulkOptions{
+//		OnConflict:   sqlite.ConflictUpdate,
+//		UpsertClause: "ON CONFLICT (id) DO UPDATE SET name = excluded.name",
+//	})
+//
+// Транзакции через несколько репозиториев: TxRunner кладётся в контекст,
+// репозитории получают Querier через QuerierFrom и не хранят ссылку на TxRunner:
+//
+//	ctx = sqlite.WithRunner(ctx, runner)
+//	err = sqlite.WithinTx(ctx, func(ctx context.Context) error {
+//		if err := users.Create(ctx, user); err != nil { // внутри sqlite.QuerierFrom(ctx)
+//			return err
+//		}
+//		return audit.Log(ctx, "user created")
+//	})
+//
 // Savepoints для вложенных транзакций:
 //
 //	err = runner.WithinTx(ctx, func(outerCtx context.Context) error {
@@ -55,8 +87,61 @@
 //	opts := sqlite.DefaultDBOptions()
 //	opts.EnableWriteQueue = true
 //	opts.TxLockMode = sqlite.TxLockImmediate  // Ранний захват блокировок
+//	opts.WriteQueueMaxWait = time.Second      // Не ждать места в переполненной очереди дольше секунды
 //	db, err := sqlite.NewDBWithOptions(ctx, "app.db", opts)
 //
+// Контекст запроса проверяется непосредственно перед выполнением: запрос, дедлайн
+// которого истёк в очереди, не выполняется. Запрос, не принятый в очередь за
+// WriteQueueMaxWait, завершается ErrWriteQueueTimeout (shared.KindTimeout).
+// Счётчики доступны через runner.QueueStats().
+//
+// Режим блокировки можно переопределить для отдельного вызова, а read-only
+// транзакции выполняются в обход очереди записи и отклоняют запись:
+//
+//	err = runner.WithinTxOptions(ctx, sqlite.TxOptions{LockMode: sqlite.TxLockImmediate}, fn)
+//	err = runner.WithinTxOptions(ctx, sqlite.TxOptions{ReadOnly: true}, fn)
+//
+// Ретраи транзакций на SQLITE_BUSY выполняются через pkg/retry и настраиваются через DBOptions:
+//
+//	opts.RetryConfig = &sqlite.RetryConfig{
+//		MaxAttempts:  5,
+//		InitialDelay: 10 * time.Millisecond,
+//		MaxDelay:     time.Second,
+//		Jitter:       retry.JitterDecorrelated,
+//		OnRetry: func(attempt int, err error, delay time.Duration) {
+//			logger.Warn("tx retried due to busy", "attempt", attempt, "error", err)
+//		},
+//	}
+//	runner := sqlite.NewTxRunnerWithOptions(db, opts)
+//
+// # Классификация ошибок
+//
+// ClassifyError помечает ошибки драйвера видами из пакета shared
+// (UNIQUE - KindConflict, FOREIGN KEY/NOT NULL/CHECK - KindValidation, BUSY - KindTimeout):
+//
+//	if shared.IsConflict(sqlite.ClassifyError(err)) { ... }
+//
+// С DBOptions.ClassifyErrors TxRunner возвращает уже классифицированные ошибки.
+//
+// # Кэш подготовленных выражений
+//
+// При DBOptions.StmtCacheSize > 0 GetQuerier выполняет запросы через LRU-кэш *sql.Stmt,
+// внутри транзакции выражение привязывается к ней через tx.StmtContext:
+//
+//	opts := sqlite.DefaultDBOptions()
+//	opts.StmtCacheSize = 64
+//	runner := sqlite.NewTxRunnerWithOptions(db, opts)
+//	defer runner.Close() // закрывает закэшированные выражения
+//	stats := runner.CacheStats()
+//
+// # Замер запросов
+//
+// DBOptions.QueryHook вызывается после каждого запроса через GetQuerier, в том числе
+// внутри транзакций. SlowQueryLogger логирует только медленные запросы:
+//
+//	opts.QueryHook = sqlite.SlowQueryLogger(logger, 100*time.Millisecond)
+//	opts.QueryHookMaxSQL = 500
+//
 // # Режимы доступа
 //
 // Read-only база данных:
@@ -67,12 +152,136 @@
 //
 //	db, err := sqlite.NewDBWithMode(ctx, "app.db", sqlite.AccessModeReadWriteCreate)
 //
+// # Настройка соединений
+//
+// DBOptions.ConnectionInit выполняется на каждом новом соединении пула до его использования,
+// например для ключа шифрования в сборках с расширением шифрования SQLite:
+//
+//	opts := sqlite.DefaultDBOptions()
+//	opts.ConnectionInit = func(ctx context.Context, conn *sql.Conn) error {
+//		_, err := conn.ExecContext(ctx, "PRAGMA key = '"+key+"'")
+//		return err
+//	}
+//	db, err := sqlite.NewDBWithOptions(ctx, "app.db", opts)
+//
+// # Резервное копирование
+//
+// Онлайн-копия через VACUUM INTO (не блокирует читателей в WAL режиме):
+//
+//	err = sqlite.Backup(ctx, db, "backups/app-2024-01-01.db")
+//
+// Восстановление с проверкой целостности (подключения к app.db должны быть закрыты):
+//
+//	err = sqlite.Restore(ctx, "app.db", "backups/app-2024-01-01.db")
+//
+// # Обслуживание
+//
+// Проверка целостности и "раздутости" базы для health endpoint:
+//
+//	report, err := sqlite.HealthCheck(ctx, db, sqlite.HealthCheckOptions{DBPath: "app.db"})
+//	if errors.Is(err, sqlite.ErrDatabaseCorrupted) { ... } // отчёт при этом заполнен
+//	json.NewEncoder(w).Encode(report)
+//
+//	// Периодическая проверка с сохранением последнего отчёта
+//	job := sqlite.HealthCheckJob(db, opts, func(r sqlite.HealthReport, err error) { lastReport.Store(&r) })
+//
+// Статистика пула и SQLite для частого опроса (поля пула совпадают с pg.DBStats):
+//
+//	stats, err := sqlite.Stats(ctx, db)
+//	if err == nil && !sqlite.IsHealthy(stats) { ... }
+//
+// WAL checkpoint, PRAGMA optimize и incremental vacuum одной задачей планировщика:
+//
+//	job := sqlite.MaintenanceJob(db, sqlite.MaintenanceOptions{
+//		Checkpoint:             sqlite.CheckpointTruncate,
+//		Analyze:                true,
+//		IncrementalVacuumPages: 100,
+//		TxRunner:               runner, // выполнять через очередь записи
+//	})
+//	_, err = sched.AddCronJobWithOptions("0 4 * * *", job, scheduler.JobOptions{Name: "sqlite-maintenance"})
+//
+// Хранилище задач планировщика (scheduler.Config.JobStore) поверх TxRunner находится
+// в пакете internal/adapter/scheduler/sqlitestore, чтобы этот пакет не зависел от планировщика.
+//
+// # Отслеживание изменений
+//
+// Watch уведомляет об изменениях строк таблицы, сделанных любым соединением,
+// например для сброса кэша настроек. Изменения между опросами объединяются в один вызов:
+//
+//	stop, err := sqlite.WatchWithOptions(ctx, db, "settings", sqlite.WatchOptions{
+//		Interval:     time.Second,
+//		ChangeColumn: "updated_at", // без колонки замечаются только вставки и удаления
+//	}, func(cs sqlite.ChangeSet) { settingsCache.Invalidate() })
+//	defer stop()
+//
+// # Блокировки между экземплярами
+//
+// Lock захватывает именованную блокировку в таблице app_locks с TTL, чтобы задачу
+// выполнял только один из экземпляров, работающих с одним файлом:
+//
+//	release, acquired, err := sqlite.Lock(ctx, runner, "daily-digest", 10*time.Minute)
+//	if err != nil || !acquired {
+//		return err // блокировку держит другой экземпляр
+//	}
+//	defer release()
+//
+// Задача планировщика, пропускающая запуск, если блокировку держит другой экземпляр
+// (для долгих задач используйте AcquireLock и AppLock.Renew):
+//
+//	job := sqlite.LockedJob(runner, "daily-digest", 10*time.Minute, sendDigest)
+//	_, err = sched.AddCronJobWithOptions("0 0 9 * * *", job, scheduler.JobOptions{Name: "daily-digest"})
+//
+// DBOptions.ExclusiveOwner не даёт открыть базу второму экземпляру бота: блокировка
+// владельца захватывается в NewDBWithOptions и снимается при db.Close():
+//
+//	opts.ExclusiveOwner = true
+//	db, err := sqlite.NewDBWithOptions(ctx, "app.db", opts)
+//	if errors.Is(err, sqlite.ErrDatabaseOwned) { ... } // база уже открыта другим экземпляром
+//
+// Если запросы неожиданно получают SQLITE_BUSY, DiagnoseLock покажет, держит ли
+// кто-то блокировку записи, состояние WAL и рекомендации:
+//
+//	report, err := sqlite.DiagnoseLock(ctx, "app.db")
+//	for _, hint := range report.Hints {
+//		logger.Warn("sqlite lock", "hint", hint, "has_writer", report.HasWriter)
+//	}
+//
 // # Миграции
 //
 // Применение миграций из директории:
 //
 //	err = sqlite.ApplyMigrations("app.db", "file://migrations/sqlite")
 //
+// Применение встроенных в бинарник миграций:
+//
+//	//go:embed migrations/sqlite/*.sql
+//	var migrationsFS embed.FS
+//
+//	err = sqlite.ApplyMigrationsFS("app.db", migrationsFS, "migrations/sqlite")
+//
+// Проверка перед деплоем без изменения базы: список ожидающих миграций и их
+// выполнение на копии схемы в памяти:
+//
+//	pending, err := sqlite.ApplyMigrationsDryRun("app.db", "file://migrations/sqlite")
+//	for _, m := range pending {
+//		log.Printf("pending %d %s", m.Version, m.Name)
+//	}
+//
+// Восстановление после неудачной миграции (ErrDirtyMigration): исправьте схему
+// вручную и выставьте последнюю корректную версию:
+//
+//	if errors.Is(err, sqlite.ErrDirtyMigration) {
+//		err = sqlite.ForceVersion("app.db", "file://migrations/sqlite", 2)
+//	}
+//
+// Сравнение фактической схемы с ожидаемой по миграциям (например, для отладочной команды):
+//
+//	expected, _ := sqlite.Schema(ctx, migratedInMemoryDB)
+//	actual, err := sqlite.Schema(ctx, db)
+//	if diff := sqlite.DiffSchema(expected, actual); !diff.Empty() {
+//		log.Printf("schema drift: %+v", diff)
+//	}
+//
 // # Тестирование
 //
 // In-memory база для тестов:
@@ -90,4 +299,23 @@
 //		testDB.ApplyTestMigrations(t, "file://migrations")
 //		// Работаем с настоящей БД, автоматическая очистка
 //	}
+//
+// Шаблон с применёнными миграциями вместо миграций в каждом тесте (строится один раз
+// на тестовый бинарник, каждый тест получает собственную копию файла):
+//
+//	func TestRepository(t *testing.T) {
+//		template := sqlite.BuildTemplate(t, "file://migrations")
+//		testDB := sqlite.NewTestDBFromTemplate(t, template)
+//		// Копия удаляется после теста, шаблон - в TestMain через sqlite.CleanupTemplates()
+//	}
+//
+// Фикстуры и проверки (ошибки содержат выполненный SQL):
+//
+//	//go:embed testdata/fixtures/*.sql
+//	var fixtures embed.FS
+//
+//	testDB.SeedFromFS(t, fixtures, "testdata/fixtures/*.sql") // в лексическом порядке, одной транзакцией
+//	testDB.Seed(t, "INSERT INTO users (name) VALUES ('alice')")
+//	n := testDB.MustCount(t, "users", "name = ?", "alice")
+//	testDB.AssertRowExists(t, "users", "name = ?", "alice")
 package sqlite
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"sttbot/internal/shared"
+)
+
+// ClassifyError помечает ошибку драйвера SQLite видом из пакета shared:
+//   - нарушение UNIQUE/PRIMARY KEY - shared.KindConflict
+//   - нарушение FOREIGN KEY, NOT NULL, CHECK - shared.KindValidation
+//   - SQLITE_BUSY/SQLITE_LOCKED - shared.KindTimeout
+//   - sql.ErrNoRows - shared.KindNotFound
+//   - остальные ошибки - shared.KindInternal
+//
+// Исходная ошибка сохраняется в цепочке и доступна через errors.Is/errors.As.
+// Уже классифицированные ошибки (включая отмену и таймаут контекста) возвращаются без изменений.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if shared.KindOf(err) != shared.KindUnknown {
+		return err
+	}
+	return shared.MarkKind(err, errorKind(err))
+}
+
+// errorKind определяет вид ошибки по коду драйвера, а при его отсутствии - по тексту ошибки.
+func errorKind(err error) shared.Kind {
+	if errors.Is(err, sql.ErrNoRows) {
+		return shared.KindNotFound
+	}
+	if IsBusyError(err) {
+		return shared.KindTimeout
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return shared.KindConflict
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY, sqlite3.SQLITE_CONSTRAINT_NOTNULL, sqlite3.SQLITE_CONSTRAINT_CHECK:
+			return shared.KindValidation
+		}
+	}
+
+	errStr := err.Error()
+	switch {
+	case strings.Contains(errStr, "UNIQUE constraint failed"):
+		return shared.KindConflict
+	case strings.Contains(errStr, "FOREIGN KEY constraint failed"),
+		strings.Contains(errStr, "NOT NULL constraint failed"),
+		strings.Contains(errStr, "CHECK constraint failed"):
+		return shared.KindValidation
+	}
+	return shared.KindInternal
+}
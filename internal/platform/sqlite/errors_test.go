@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+func TestClassifyError(t *testing.T) {
+	ctx := context.Background()
+	testDB := NewTestDBInMemory(t)
+	testDB.MustSeedData(t,
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE, age INTEGER CHECK (age >= 0))",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users(id))",
+		"INSERT INTO users (id, email, age) VALUES (1, 'a@example.com', 30)",
+	)
+
+	tests := []struct {
+		name  string
+		query string
+		kind  shared.Kind
+	}{
+		{
+			name:  "unique violation",
+			query: "INSERT INTO users (email) VALUES ('a@example.com')",
+			kind:  shared.KindConflict,
+		},
+		{
+			name:  "primary key violation",
+			query: "INSERT INTO users (id, email) VALUES (1, 'b@example.com')",
+			kind:  shared.KindConflict,
+		},
+		{
+			name:  "foreign key violation",
+			query: "INSERT INTO posts (user_id) VALUES (42)",
+			kind:  shared.KindValidation,
+		},
+		{
+			name:  "not null violation",
+			query: "INSERT INTO users (email) VALUES (NULL)",
+			kind:  shared.KindValidation,
+		},
+		{
+			name:  "check violation",
+			query: "INSERT INTO users (email, age) VALUES ('c@example.com', -1)",
+			kind:  shared.KindValidation,
+		},
+		{
+			name:  "syntax error",
+			query: "INSERT INTO missing_table VALUES (1)",
+			kind:  shared.KindInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, driverErr := testDB.DB.ExecContext(ctx, tt.query)
+			require.Error(t, driverErr)
+
+			err := ClassifyError(fmt.Errorf("repository: %w", driverErr))
+			assert.Equal(t, tt.kind, shared.KindOf(err), "ошибка: %v", driverErr)
+			assert.ErrorIs(t, err, driverErr)
+		})
+	}
+
+	t.Run("no rows", func(t *testing.T) {
+		var id int
+		scanErr := testDB.DB.QueryRowContext(ctx, "SELECT id FROM users WHERE id = 100").Scan(&id)
+
+		err := ClassifyError(scanErr)
+		assert.True(t, shared.IsNotFound(err))
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("busy", func(t *testing.T) {
+		busyErr := errors.New("database is locked (5) (SQLITE_BUSY)")
+		err := ClassifyError(busyErr)
+		assert.True(t, shared.IsTimeout(err))
+		assert.ErrorIs(t, err, busyErr)
+	})
+
+	t.Run("already classified", func(t *testing.T) {
+		assert.Nil(t, ClassifyError(nil))
+		assert.Equal(t, context.Canceled, ClassifyError(context.Canceled))
+
+		marked := shared.MarkKind(errors.New("boom"), shared.KindForbidden)
+		assert.Equal(t, marked, ClassifyError(marked))
+	})
+}
+
+func TestTxRunner_ClassifyErrors(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewInMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, "CREATE TABLE test (id INTEGER PRIMARY KEY, value TEXT UNIQUE)")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "INSERT INTO test (value) VALUES ('dup')")
+	require.NoError(t, err)
+
+	insertDup := func(runner *TxRunner) error {
+		return runner.WithinTx(ctx, func(ctx context.Context) error {
+			_, err := runner.GetQuerier(ctx).ExecContext(ctx, "INSERT INTO test (value) VALUES ('dup')")
+			return err
+		})
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		err := insertDup(NewTxRunner(db))
+		require.Error(t, err)
+		assert.Equal(t, shared.KindUnknown, shared.KindOf(err))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		opts := DefaultDBOptions()
+		opts.ClassifyErrors = true
+		runner := NewTxRunnerWithOptions(db, opts)
+
+		err := insertDup(runner)
+		require.Error(t, err)
+		assert.True(t, shared.IsConflict(err))
+
+		err = runner.WithinSavepoint(ctx, func(ctx context.Context) error {
+			_, err := runner.GetQuerier(ctx).ExecContext(ctx, "INSERT INTO test (id) VALUES (NULL), (1)")
+			return err
+		})
+		require.Error(t, err)
+		assert.True(t, shared.IsConflict(err))
+	})
+}
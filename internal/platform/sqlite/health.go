@@ -0,0 +1,175 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"sttbot/internal/shared"
+)
+
+// ErrDatabaseCorrupted возвращается, если база не прошла проверку целостности.
+var ErrDatabaseCorrupted = errors.New("database integrity check failed")
+
+// ErrDatabaseUnhealthy возвращается задачей HealthCheckJob, если превышены пороги отчёта.
+var ErrDatabaseUnhealthy = errors.New("database is unhealthy")
+
+// DefaultMaxFreelistRatio - допустимая доля свободных страниц по умолчанию.
+const DefaultMaxFreelistRatio = 0.25
+
+// HealthCheckOptions содержит настройки проверки состояния базы данных.
+type HealthCheckOptions struct {
+	// Full - выполнить полный PRAGMA integrity_check вместо быстрого quick_check
+	Full bool
+	// DBPath - путь к файлу базы для определения размера WAL файла (пусто - не проверять)
+	DBPath string
+	// MaxFreelistRatio - допустимая доля свободных страниц от page_count
+	// (0 - DefaultMaxFreelistRatio, 1 - не проверять)
+	MaxFreelistRatio float64
+	// MaxWALSize - допустимый размер WAL файла в байтах (0 - не проверять)
+	MaxWALSize int64
+}
+
+// HealthReport содержит результаты проверки состояния базы данных.
+type HealthReport struct {
+	// Healthy - целостность не нарушена и пороги не превышены
+	Healthy bool `json:"healthy"`
+	// IntegrityOK - проверка целостности прошла успешно
+	IntegrityOK bool `json:"integrity_ok"`
+	// IntegrityErrors - найденные нарушения целостности
+	IntegrityErrors []string `json:"integrity_errors,omitempty"`
+	// PageCount - общее количество страниц
+	PageCount int64 `json:"page_count"`
+	// FreelistCount - количество свободных страниц
+	FreelistCount int64 `json:"freelist_count"`
+	// FreelistRatio - доля свободных страниц
+	FreelistRatio float64 `json:"freelist_ratio"`
+	// FreelistExceeded - доля свободных страниц превышает MaxFreelistRatio
+	FreelistExceeded bool `json:"freelist_exceeded"`
+	// JournalMode - режим журнала (wal, delete, memory, ...)
+	JournalMode string `json:"journal_mode"`
+	// WALSize - размер WAL файла в байтах (-1, если DBPath не указан)
+	WALSize int64 `json:"wal_size"`
+	// WALSizeExceeded - размер WAL файла превышает MaxWALSize
+	WALSizeExceeded bool `json:"wal_size_exceeded"`
+	// CheckedAt - время проверки
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// HealthCheck проверяет целостность базы и собирает показатели её состояния.
+// При нарушении целостности отчёт заполняется полностью, а ошибка ErrDatabaseCorrupted
+// помечается видом shared.KindInternal. Превышение порогов ошибкой не считается,
+// оно отражается в HealthReport.Healthy.
+func HealthCheck(ctx context.Context, db *sql.DB, opts HealthCheckOptions) (HealthReport, error) {
+	report := HealthReport{WALSize: -1, CheckedAt: time.Now()}
+
+	check := "PRAGMA quick_check"
+	if opts.Full {
+		check = "PRAGMA integrity_check"
+	}
+	problems, err := integrityProblems(ctx, db, check)
+	if err != nil {
+		return report, err
+	}
+	report.IntegrityErrors = problems
+	report.IntegrityOK = len(problems) == 0
+
+	if err := db.QueryRowContext(ctx, "PRAGMA page_count").Scan(&report.PageCount); err != nil {
+		return report, fmt.Errorf("failed to read page_count: %w", err)
+	}
+	if err := db.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&report.FreelistCount); err != nil {
+		return report, fmt.Errorf("failed to read freelist_count: %w", err)
+	}
+	if err := db.QueryRowContext(ctx, "PRAGMA journal_mode").Scan(&report.JournalMode); err != nil {
+		return report, fmt.Errorf("failed to read journal_mode: %w", err)
+	}
+
+	if report.PageCount > 0 {
+		report.FreelistRatio = float64(report.FreelistCount) / float64(report.PageCount)
+	}
+	maxRatio := opts.MaxFreelistRatio
+	if maxRatio <= 0 {
+		maxRatio = DefaultMaxFreelistRatio
+	}
+	report.FreelistExceeded = report.FreelistRatio > maxRatio
+
+	if opts.DBPath != "" {
+		size, err := walFileSize(opts.DBPath)
+		if err != nil {
+			return report, err
+		}
+		report.WALSize = size
+		report.WALSizeExceeded = opts.MaxWALSize > 0 && size > opts.MaxWALSize
+	}
+
+	report.Healthy = report.IntegrityOK && !report.FreelistExceeded && !report.WALSizeExceeded
+
+	if !report.IntegrityOK {
+		return report, shared.MarkKind(
+			fmt.Errorf("%w: %s", ErrDatabaseCorrupted, strings.Join(problems, "; ")),
+			shared.KindInternal,
+		)
+	}
+	return report, nil
+}
+
+// HealthCheckJob возвращает задачу проверки состояния для планировщика.
+// Результат совместим с scheduler.JobFunc. Задача завершается ошибкой при нарушении
+// целостности или превышении порогов (ErrDatabaseUnhealthy).
+// onReport, если указан, получает отчёт каждой проверки, например для health endpoint.
+func HealthCheckJob(db *sql.DB, opts HealthCheckOptions, onReport func(HealthReport, error)) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		report, err := HealthCheck(ctx, db, opts)
+		if onReport != nil {
+			onReport(report, err)
+		}
+		if err != nil {
+			return err
+		}
+		if !report.Healthy {
+			return fmt.Errorf("%w: freelist ratio %.2f, wal size %d bytes",
+				ErrDatabaseUnhealthy, report.FreelistRatio, report.WALSize)
+		}
+		return nil
+	}
+}
+
+// integrityProblems выполняет проверку целостности и возвращает найденные нарушения.
+func integrityProblems(ctx context.Context, db *sql.DB, query string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run integrity check: %w", err)
+	}
+	defer rows.Close()
+
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("failed to read integrity check result: %w", err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read integrity check result: %w", err)
+	}
+	return problems, nil
+}
+
+// walFileSize возвращает размер WAL файла базы (0, если файла нет).
+func walFileSize(dbPath string) (int64, error) {
+	info, err := os.Stat(dbPath + "-wal")
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat WAL file: %w", err)
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+func TestHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	path, testDB := newMaintenanceTestDB(t, DefaultDBOptions())
+	testDB.MustSeedData(t, "CREATE TABLE items (id INTEGER PRIMARY KEY, data BLOB)")
+	for i := 0; i < 50; i++ {
+		testDB.Exec(t, "INSERT INTO items (data) VALUES (randomblob(4096))")
+	}
+
+	report, err := HealthCheck(ctx, testDB.DB, HealthCheckOptions{DBPath: path})
+	require.NoError(t, err)
+	assert.True(t, report.Healthy)
+	assert.True(t, report.IntegrityOK)
+	assert.Empty(t, report.IntegrityErrors)
+	assert.Positive(t, report.PageCount)
+	assert.Equal(t, "wal", report.JournalMode)
+	assert.Positive(t, report.WALSize)
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"healthy":true`)
+
+	// Удаление всех строк оставляет большую часть страниц свободными
+	testDB.Exec(t, "DELETE FROM items")
+	report, err = HealthCheck(ctx, testDB.DB, HealthCheckOptions{Full: true, DBPath: path, MaxWALSize: 1})
+	require.NoError(t, err)
+	assert.False(t, report.Healthy)
+	assert.True(t, report.IntegrityOK)
+	assert.True(t, report.FreelistExceeded)
+	assert.True(t, report.WALSizeExceeded)
+
+	job := HealthCheckJob(testDB.DB, HealthCheckOptions{}, nil)
+	require.ErrorIs(t, job(ctx), ErrDatabaseUnhealthy)
+}
+
+func TestHealthCheck_NoPath(t *testing.T) {
+	testDB := NewTestDBInMemory(t)
+
+	var got HealthReport
+	job := HealthCheckJob(testDB.DB, HealthCheckOptions{}, func(r HealthReport, err error) { got = r })
+	require.NoError(t, job(context.Background()))
+	assert.True(t, got.Healthy)
+	assert.Equal(t, int64(-1), got.WALSize)
+}
+
+func TestHealthCheck_Corrupted(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "corrupted.db")
+
+	db, err := NewDB(ctx, path)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `
+		CREATE TABLE t (a INTEGER, b INTEGER);
+		CREATE INDEX t_a ON t (a);
+		INSERT INTO t VALUES (1, 100), (2, 200), (3, 300);
+	`)
+	require.NoError(t, err)
+
+	// Подменяем определение индекса: его содержимое перестаёт соответствовать таблице
+	_, err = db.ExecContext(ctx, `
+		PRAGMA writable_schema = ON;
+		UPDATE sqlite_schema SET sql = 'CREATE INDEX t_a ON t (b)' WHERE name = 't_a';
+		PRAGMA writable_schema = OFF;
+	`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	db, err = NewDB(ctx, path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	report, err := HealthCheck(ctx, db, HealthCheckOptions{Full: true, DBPath: path})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrDatabaseCorrupted))
+	assert.True(t, shared.IsInternal(err))
+	assert.False(t, report.Healthy)
+	assert.False(t, report.IntegrityOK)
+	assert.NotEmpty(t, report.IntegrityErrors)
+	assert.Positive(t, report.PageCount, "отчёт заполняется и при нарушении целостности")
+}
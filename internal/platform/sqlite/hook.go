@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+)
+
+// Методы Querier, передаваемые в QueryInfo.Method.
+const (
+	QueryMethodExec     = "exec"
+	QueryMethodQuery    = "query"
+	QueryMethodQueryRow = "query_row"
+)
+
+// QueryInfo описывает выполненный запрос для QueryHook.
+// Значения аргументов не передаются, чтобы персональные данные не попадали в логи.
+type QueryInfo struct {
+	// Method - метод Querier (QueryMethodExec, QueryMethodQuery, QueryMethodQueryRow)
+	Method string
+	// SQL - текст запроса, усечённый до DBOptions.QueryHookMaxSQL
+	SQL string
+	// Args - количество аргументов запроса
+	Args int
+	// Duration - время выполнения. Для QueryContext и QueryRowContext не включает чтение строк
+	Duration time.Duration
+	// RowsAffected - число изменённых строк для ExecContext, -1 если неизвестно
+	RowsAffected int64
+	// Err - ошибка запроса
+	Err error
+}
+
+// QueryHook вызывается после каждого запроса через Querier, полученный из TxRunner.GetQuerier.
+// Вызывается синхронно, поэтому должен быть быстрым.
+type QueryHook func(ctx context.Context, info QueryInfo)
+
+// SlowQueryLogger возвращает QueryHook, логирующий запросы, выполнявшиеся threshold и дольше.
+func SlowQueryLogger(logger *slog.Logger, threshold time.Duration) QueryHook {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(ctx context.Context, info QueryInfo) {
+		if info.Duration < threshold {
+			return
+		}
+		attrs := []slog.Attr{
+			slog.String("method", info.Method),
+			slog.String("sql", info.SQL),
+			slog.Int("args", info.Args),
+			slog.Duration("duration", info.Duration),
+		}
+		if info.RowsAffected >= 0 {
+			attrs = append(attrs, slog.Int64("rows_affected", info.RowsAffected))
+		}
+		if info.Err != nil {
+			attrs = append(attrs, slog.Any("error", info.Err))
+		}
+		logger.LogAttrs(ctx, slog.LevelWarn, "slow sqlite query", attrs...)
+	}
+}
+
+// hookedQuerier замеряет время запросов base и передаёт их в hook.
+// Оборачивает любой Querier (БД, транзакции, кэш выражений), не меняя его семантики.
+type hookedQuerier struct {
+	base   Querier
+	hook   QueryHook
+	maxSQL int
+}
+
+// report вызывает hook для завершённого запроса.
+func (q *hookedQuerier) report(ctx context.Context, method, query string, args int, start time.Time, rows int64, err error) {
+	if q.maxSQL > 0 && len(query) > q.maxSQL {
+		query = query[:q.maxSQL] + "..."
+	}
+	q.hook(ctx, QueryInfo{
+		Method:       method,
+		SQL:          query,
+		Args:         args,
+		Duration:     time.Since(start),
+		RowsAffected: rows,
+		Err:          err,
+	})
+}
+
+func (q *hookedQuerier) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	res, err := q.base.ExecContext(ctx, query, args...)
+	rows := int64(-1)
+	if err == nil {
+		if n, rowsErr := res.RowsAffected(); rowsErr == nil {
+			rows = n
+		}
+	}
+	q.report(ctx, QueryMethodExec, query, len(args), start, rows, err)
+	return res, err
+}
+
+func (q *hookedQuerier) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := q.base.QueryContext(ctx, query, args...)
+	q.report(ctx, QueryMethodQuery, query, len(args), start, -1, err)
+	return rows, err
+}
+
+func (q *hookedQuerier) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	row := q.base.QueryRowContext(ctx, query, args...)
+	q.report(ctx, QueryMethodQueryRow, query, len(args), start, -1, row.Err())
+	return row
+}
+
+// PrepareContext передаётся без замера: подготовленные выражения выполняются в обход хука.
+func (q *hookedQuerier) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return q.base.PrepareContext(ctx, query)
+}
@@ -0,0 +1,215 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hookRecorder собирает QueryInfo всех запросов.
+type hookRecorder struct {
+	mu    sync.Mutex
+	infos []QueryInfo
+}
+
+func (h *hookRecorder) hook(_ context.Context, info QueryInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.infos = append(h.infos, info)
+}
+
+func (h *hookRecorder) reset() []QueryInfo {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	infos := h.infos
+	h.infos = nil
+	return infos
+}
+
+// newHookedRunner создаёт in-memory БД с таблицей test и TxRunner с хуком запросов.
+func newHookedRunner(t testing.TB, hook QueryHook, modify func(*DBOptions)) *TxRunner {
+	t.Helper()
+	ctx := context.Background()
+	db, err := NewInMemoryDB(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.ExecContext(ctx, "CREATE TABLE test (id INTEGER PRIMARY KEY, value TEXT)")
+	require.NoError(t, err)
+
+	opts := DefaultDBOptions()
+	opts.QueryHook = hook
+	if modify != nil {
+		modify(&opts)
+	}
+	runner := NewTxRunnerWithOptions(db, opts)
+	t.Cleanup(func() { _ = runner.Close() })
+	return runner
+}
+
+func TestQueryHook(t *testing.T) {
+	ctx := context.Background()
+	rec := &hookRecorder{}
+	runner := newHookedRunner(t, rec.hook, nil)
+
+	q := runner.GetQuerier(ctx)
+	_, err := q.ExecContext(ctx, "INSERT INTO test (id, value) VALUES (?, ?), (?, ?)", 1, "secret", 2, "secret")
+	require.NoError(t, err)
+
+	rows, err := q.QueryContext(ctx, "SELECT id FROM test")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+
+	var count int
+	require.NoError(t, q.QueryRowContext(ctx, "SELECT COUNT(*) FROM test WHERE value = ?", "secret").Scan(&count))
+	assert.Equal(t, 2, count)
+
+	_, err = q.ExecContext(ctx, "INSERT INTO missing VALUES (1)")
+	require.Error(t, err)
+
+	infos := rec.reset()
+	require.Len(t, infos, 4)
+	assert.Equal(t, QueryMethodExec, infos[0].Method)
+	assert.Equal(t, 4, infos[0].Args)
+	assert.EqualValues(t, 2, infos[0].RowsAffected)
+	assert.NoError(t, infos[0].Err)
+	assert.Positive(t, infos[0].Duration)
+	assert.Equal(t, QueryInfo{Method: QueryMethodQuery, SQL: "SELECT id FROM test", RowsAffected: -1}, withoutDuration(infos[1]))
+	assert.Equal(t, QueryMethodQueryRow, infos[2].Method)
+	assert.Equal(t, 1, infos[2].Args)
+	assert.EqualValues(t, -1, infos[2].RowsAffected)
+	assert.Error(t, infos[3].Err)
+	assert.EqualValues(t, -1, infos[3].RowsAffected)
+	for _, info := range infos {
+		assert.NotContains(t, info.SQL, "secret", "значения аргументов не передаются")
+	}
+}
+
+func TestQueryHook_Transactions(t *testing.T) {
+	for _, mode := range []TxLockMode{TxLockDeferred, TxLockImmediate} {
+		t.Run(string(mode), func(t *testing.T) {
+			ctx := context.Background()
+			rec := &hookRecorder{}
+			runner := newHookedRunner(t, rec.hook, func(o *DBOptions) {
+				o.TxLockMode = mode
+				o.StmtCacheSize = 8
+			})
+
+			// Откат транзакции отменяет запросы, выполненные через обёртку
+			err := runner.WithinTx(ctx, func(ctx context.Context) error {
+				_, err := runner.GetQuerier(ctx).ExecContext(ctx, "INSERT INTO test (id, value) VALUES (1, 'a')")
+				require.NoError(t, err)
+				return assert.AnError
+			})
+			require.ErrorIs(t, err, assert.AnError)
+
+			require.NoError(t, runner.WithinTx(ctx, func(ctx context.Context) error {
+				_, err := runner.GetQuerier(ctx).ExecContext(ctx, "INSERT INTO test (id, value) VALUES (2, 'b')")
+				return err
+			}))
+
+			var ids []int
+			rows, err := runner.GetQuerier(ctx).QueryContext(ctx, "SELECT id FROM test")
+			require.NoError(t, err)
+			for rows.Next() {
+				var id int
+				require.NoError(t, rows.Scan(&id))
+				ids = append(ids, id)
+			}
+			require.NoError(t, rows.Close())
+			assert.Equal(t, []int{2}, ids)
+			assert.Len(t, rec.reset(), 3)
+		})
+	}
+
+	t.Run("read-only", func(t *testing.T) {
+		ctx := context.Background()
+		rec := &hookRecorder{}
+		runner := newHookedRunner(t, rec.hook, nil)
+
+		err := runner.WithinTxOptions(ctx, TxOptions{ReadOnly: true}, func(ctx context.Context) error {
+			_, err := runner.GetQuerier(ctx).ExecContext(ctx, "INSERT INTO test (id) VALUES (1)")
+			return err
+		})
+		require.ErrorIs(t, err, ErrReadOnlyTx)
+		infos := rec.reset()
+		require.Len(t, infos, 1)
+		assert.ErrorIs(t, infos[0].Err, ErrReadOnlyTx)
+	})
+}
+
+func TestQueryHook_MaxSQL(t *testing.T) {
+	ctx := context.Background()
+	rec := &hookRecorder{}
+	runner := newHookedRunner(t, rec.hook, func(o *DBOptions) { o.QueryHookMaxSQL = 10 })
+
+	_, err := runner.GetQuerier(ctx).ExecContext(ctx, "SELECT 1 -- long comment")
+	require.NoError(t, err)
+	infos := rec.reset()
+	require.Len(t, infos, 1)
+	assert.Equal(t, "SELECT 1 -...", infos[0].SQL)
+}
+
+func TestQueryHook_NilHookReturnsPlainQuerier(t *testing.T) {
+	runner := newHookedRunner(t, nil, nil)
+	assert.IsType(t, &sql.DB{}, runner.GetQuerier(context.Background()))
+}
+
+func TestSlowQueryLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	hook := SlowQueryLogger(logger, 50*time.Millisecond)
+	ctx := context.Background()
+
+	hook(ctx, QueryInfo{Method: QueryMethodQuery, SQL: "SELECT 1", Duration: 10 * time.Millisecond, RowsAffected: -1})
+	assert.Empty(t, buf.String())
+
+	hook(ctx, QueryInfo{Method: QueryMethodExec, SQL: "UPDATE test SET value = ?", Args: 1, Duration: 80 * time.Millisecond, RowsAffected: 3})
+	out := buf.String()
+	assert.Equal(t, 1, strings.Count(out, "slow sqlite query"))
+	assert.Contains(t, out, `sql="UPDATE test SET value = ?"`)
+	assert.Contains(t, out, "args=1")
+	assert.Contains(t, out, "duration=80ms")
+	assert.Contains(t, out, "rows_affected=3")
+}
+
+// withoutDuration обнуляет Duration для сравнения QueryInfo целиком.
+func withoutDuration(info QueryInfo) QueryInfo {
+	info.Duration = 0
+	return info
+}
+
+func BenchmarkQueryHook(b *testing.B) {
+	ctx := context.Background()
+	benchmarks := []struct {
+		name string
+		hook QueryHook
+	}{
+		{"no hook", nil},
+		{"noop hook", func(context.Context, QueryInfo) {}},
+		{"slow query logger", SlowQueryLogger(slog.Default(), time.Hour)},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			runner := newHookedRunner(b, bm.hook, nil)
+			_, err := runner.DB.ExecContext(ctx, "INSERT INTO test (id, value) VALUES (1, 'a')")
+			require.NoError(b, err)
+
+			var value string
+			b.ResetTimer()
+			for range b.N {
+				if err := runner.GetQuerier(ctx).QueryRowContext(ctx, "SELECT value FROM test WHERE id = ?", 1).Scan(&value); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocksTable - таблица advisory-блокировок, создаётся при первом захвате.
+const LocksTable = "app_locks"
+
+// ErrInvalidLock возвращается при пустом имени блокировки или неположительном TTL.
+var ErrInvalidLock = errors.New("sqlite: invalid lock")
+
+// ErrLockNotHeld означает, что блокировка истекла и захвачена другим владельцем
+// (или удалена), поэтому продлить или освободить её нельзя.
+var ErrLockNotHeld = errors.New("sqlite: lock is not held")
+
+// AppLock - захваченная advisory-блокировка уровня приложения.
+// Методы безопасны для конкурентного использования.
+type AppLock struct {
+	runner *TxRunner
+	name   string
+	owner  string
+
+	mu       sync.Mutex
+	released bool
+}
+
+// Lock пытается захватить блокировку name на время ttl для взаимного исключения
+// между экземплярами бота, работающими с одним файлом БД (например, "отправить
+// дайджест ровно один раз"). Если блокировку держит другой владелец и её TTL
+// не истёк, возвращает acquired = false без ошибки.
+//
+// release освобождает блокировку, только если она всё ещё принадлежит этому
+// владельцу; повторные вызовы возвращают nil. Для продления используйте AcquireLock
+// и AppLock.Renew.
+//
+// Истечение TTL сравнивается с локальными часами процесса, поэтому часы экземпляров
+// должны быть синхронизированы с точностью много меньше ttl.
+func Lock(ctx context.Context, runner *TxRunner, name string, ttl time.Duration) (release func() error, acquired bool, err error) {
+	lock, acquired, err := AcquireLock(ctx, runner, name, ttl)
+	if err != nil || !acquired {
+		return nil, false, err
+	}
+	return lock.Release, true, nil
+}
+
+// AcquireLock захватывает блокировку как Lock, но возвращает AppLock с методом Renew
+// для долгих задач. Если блокировка занята, возвращает nil и acquired = false.
+func AcquireLock(ctx context.Context, runner *TxRunner, name string, ttl time.Duration) (*AppLock, bool, error) {
+	if name == "" {
+		return nil, false, fmt.Errorf("%w: empty name", ErrInvalidLock)
+	}
+	if ttl <= 0 {
+		return nil, false, fmt.Errorf("%w: ttl must be positive, got %s", ErrInvalidLock, ttl)
+	}
+
+	owner := uuid.NewString()
+	acquired, err := tryLock(ctx, runner, name, owner, ttl)
+	if err != nil {
+		return nil, false, runner.classify(fmt.Errorf("failed to acquire lock %q: %w", name, err))
+	}
+	if !acquired {
+		return nil, false, nil
+	}
+	return &AppLock{runner: runner, name: name, owner: owner}, true, nil
+}
+
+// tryLock выполняет compare-and-set в IMMEDIATE-транзакции: запись вставляется,
+// если блокировки нет, или перезаписывается, если её TTL истёк.
+// Транзакция открывается на выделенном соединении пула, чтобы BEGIN, запись
+// и COMMIT гарантированно выполнились на одном соединении.
+func tryLock(ctx context.Context, runner *TxRunner, name, owner string, ttl time.Duration) (bool, error) {
+	conn, err := runner.DB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+LocksTable+` (
+		name       TEXT PRIMARY KEY,
+		owner      TEXT NOT NULL,
+		expires_at INTEGER NOT NULL
+	)`); err != nil {
+		return false, fmt.Errorf("failed to create %s table: %w", LocksTable, err)
+	}
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return false, err
+	}
+	now := time.Now()
+	res, err := conn.ExecContext(ctx, `INSERT INTO `+LocksTable+` (name, owner, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at
+		WHERE `+LocksTable+`.expires_at <= ?`,
+		name, owner, now.Add(ttl).UnixNano(), now.UnixNano())
+	if err != nil {
+		// Откат без учёта отмены ctx, иначе соединение вернётся в пул с открытой транзакцией
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "ROLLBACK")
+		return false, err
+	}
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "ROLLBACK")
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// Name возвращает имя блокировки.
+func (l *AppLock) Name() string {
+	return l.name
+}
+
+// Renew продлевает блокировку на ttl от текущего момента.
+// Возвращает ErrLockNotHeld, если блокировка уже освобождена или перехвачена
+// после истечения TTL.
+func (l *AppLock) Renew(ctx context.Context, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("%w: ttl must be positive, got %s", ErrInvalidLock, ttl)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.released {
+		return fmt.Errorf("%w: %q was released", ErrLockNotHeld, l.name)
+	}
+
+	res, err := l.runner.GetQuerier(ctx).ExecContext(ctx,
+		`UPDATE `+LocksTable+` SET expires_at = ? WHERE name = ? AND owner = ?`,
+		time.Now().Add(ttl).UnixNano(), l.name, l.owner)
+	if err != nil {
+		return l.runner.classify(fmt.Errorf("failed to renew lock %q: %w", l.name, err))
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return l.runner.classify(err)
+	} else if n == 0 {
+		return fmt.Errorf("%w: %q", ErrLockNotHeld, l.name)
+	}
+	return nil
+}
+
+// Release освобождает блокировку, если она всё ещё принадлежит этому владельцу.
+// Возвращает ErrLockNotHeld, если блокировку успел перехватить другой владелец;
+// повторные вызовы после первого возвращают nil.
+func (l *AppLock) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.released {
+		return nil
+	}
+
+	ctx := context.Background()
+	res, err := l.runner.GetQuerier(ctx).ExecContext(ctx,
+		`DELETE FROM `+LocksTable+` WHERE name = ? AND owner = ?`, l.name, l.owner)
+	if err != nil {
+		return l.runner.classify(fmt.Errorf("failed to release lock %q: %w", l.name, err))
+	}
+	l.released = true
+	if n, err := res.RowsAffected(); err != nil {
+		return l.runner.classify(err)
+	} else if n == 0 {
+		return fmt.Errorf("%w: %q", ErrLockNotHeld, l.name)
+	}
+	return nil
+}
+
+// LockedJob оборачивает задачу планировщика так, что она выполняется, только если
+// удалось захватить блокировку name; иначе запуск пропускается без ошибки.
+// Блокировка освобождается после завершения задачи, ttl должен превышать
+// её максимальную длительность. Результат совместим с scheduler.JobFunc.
+func LockedJob(runner *TxRunner, name string, ttl time.Duration, job func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		release, acquired, err := Lock(ctx, runner, name, ttl)
+		if err != nil || !acquired {
+			return err
+		}
+		jobErr := job(ctx)
+		if err := release(); err != nil {
+			return errors.Join(jobErr, err)
+		}
+		return jobErr
+	}
+}
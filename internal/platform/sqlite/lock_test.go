@@ -0,0 +1,177 @@
+package sqlite
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLockRunners возвращает два TxRunner с отдельными пулами на одном файле БД,
+// как у двух экземпляров бота.
+func newLockRunners(t *testing.T) (*TxRunner, *TxRunner) {
+	t.Helper()
+
+	testDB := NewTestDBFile(t)
+	db, err := NewDB(context.Background(), testDB.Path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return testDB.TxRunner, NewTxRunner(db)
+}
+
+func TestLock_MutualExclusion(t *testing.T) {
+	ctx := context.Background()
+	first, second := newLockRunners(t)
+
+	release, acquired, err := Lock(ctx, first, "digest", time.Hour)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	other, acquired, err := Lock(ctx, second, "digest", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, acquired, "блокировку держит первый экземпляр")
+	assert.Nil(t, other)
+
+	_, acquired, err = Lock(ctx, second, "cleanup", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired, "блокировки с разными именами независимы")
+
+	require.NoError(t, release())
+	require.NoError(t, release(), "повторное освобождение не ошибка")
+
+	_, acquired, err = Lock(ctx, second, "digest", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestLock_TTLExpiry(t *testing.T) {
+	ctx := context.Background()
+	first, second := newLockRunners(t)
+
+	stale, acquired, err := AcquireLock(ctx, first, "digest", 50*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	_, acquired, err = AcquireLock(ctx, second, "digest", time.Hour)
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	time.Sleep(60 * time.Millisecond)
+	fresh, acquired, err := AcquireLock(ctx, second, "digest", time.Hour)
+	require.NoError(t, err)
+	require.True(t, acquired, "истёкшая блокировка перехватывается")
+
+	// Прежний владелец не может ни продлить, ни снять чужую блокировку
+	assert.ErrorIs(t, stale.Renew(ctx, time.Hour), ErrLockNotHeld)
+	assert.ErrorIs(t, stale.Release(), ErrLockNotHeld)
+	assert.NoError(t, stale.Release())
+
+	_, acquired, err = AcquireLock(ctx, first, "digest", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, acquired, "блокировка нового владельца сохранилась")
+
+	require.NoError(t, fresh.Release())
+}
+
+func TestLock_Renew(t *testing.T) {
+	ctx := context.Background()
+	first, second := newLockRunners(t)
+
+	lock, acquired, err := AcquireLock(ctx, first, "report", 50*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	assert.Equal(t, "report", lock.Name())
+
+	require.NoError(t, lock.Renew(ctx, time.Hour))
+	time.Sleep(60 * time.Millisecond)
+
+	_, acquired, err = AcquireLock(ctx, second, "report", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, acquired, "продлённая блокировка не истекла")
+
+	require.NoError(t, lock.Release())
+	assert.ErrorIs(t, lock.Renew(ctx, time.Hour), ErrLockNotHeld)
+	assert.ErrorIs(t, lock.Renew(ctx, 0), ErrInvalidLock)
+}
+
+func TestLock_Invalid(t *testing.T) {
+	ctx := context.Background()
+	runner := NewTestDBFile(t).TxRunner
+
+	_, _, err := Lock(ctx, runner, "", time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidLock)
+	_, _, err = Lock(ctx, runner, "digest", 0)
+	assert.ErrorIs(t, err, ErrInvalidLock)
+}
+
+func TestLock_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	first, second := newLockRunners(t)
+	runners := []*TxRunner{first, second}
+
+	const attempts = 20
+	var acquiredCount atomic.Int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make(chan error, attempts)
+	for i := range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, acquired, err := Lock(ctx, runners[i%len(runners)], "digest", time.Hour)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if acquired {
+				acquiredCount.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), acquiredCount.Load(), "блокировку захватывает ровно один")
+}
+
+func TestLockedJob(t *testing.T) {
+	ctx := context.Background()
+	first, second := newLockRunners(t)
+
+	var runs atomic.Int32
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	job := func(ctx context.Context) error {
+		if runs.Add(1) == 1 {
+			close(started)
+			<-finish
+		}
+		return nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- LockedJob(first, "digest", time.Hour, job)(ctx)
+	}()
+	<-started
+
+	// Второй экземпляр пропускает запуск, пока первый выполняется
+	require.NoError(t, LockedJob(second, "digest", time.Hour, job)(ctx))
+	assert.Equal(t, int32(1), runs.Load())
+
+	close(finish)
+	require.NoError(t, <-done)
+
+	// После завершения блокировка освобождена
+	require.NoError(t, LockedJob(second, "digest", time.Hour, job)(ctx))
+	assert.Equal(t, int32(2), runs.Load())
+}
@@ -0,0 +1,145 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"sttbot/internal/shared"
+)
+
+// CheckpointMode определяет режим PRAGMA wal_checkpoint
+type CheckpointMode string
+
+const (
+	// CheckpointNone - не выполнять checkpoint
+	CheckpointNone CheckpointMode = ""
+	// CheckpointPassive - переносит столько страниц, сколько возможно без ожидания блокировок
+	CheckpointPassive CheckpointMode = "PASSIVE"
+	// CheckpointFull - ждёт завершения писателей и переносит все страницы
+	CheckpointFull CheckpointMode = "FULL"
+	// CheckpointRestart - как FULL, дополнительно ждёт читателей для перезапуска WAL
+	CheckpointRestart CheckpointMode = "RESTART"
+	// CheckpointTruncate - как RESTART, дополнительно обрезает WAL файл до нуля
+	CheckpointTruncate CheckpointMode = "TRUNCATE"
+)
+
+// MaintenanceOptions содержит настройки обслуживания базы данных.
+type MaintenanceOptions struct {
+	// Checkpoint - режим WAL checkpoint (пусто - не выполнять)
+	Checkpoint CheckpointMode
+	// Analyze - выполнить PRAGMA optimize для обновления статистики планировщика
+	Analyze bool
+	// IncrementalVacuumPages - количество страниц для PRAGMA incremental_vacuum (0 - не выполнять).
+	// Работает только для баз с auto_vacuum = INCREMENTAL.
+	IncrementalVacuumPages int
+	// TxRunner - если указан и у него включена очередь записи, обслуживание выполняется через неё
+	TxRunner *TxRunner
+}
+
+// MaintenanceReport содержит результаты обслуживания базы данных.
+type MaintenanceReport struct {
+	// CheckpointBusy - checkpoint не смог завершиться из-за блокировок
+	CheckpointBusy bool
+	// WALPages - количество страниц в WAL файле (-1, если база не в WAL режиме)
+	WALPages int
+	// CheckpointedPages - количество перенесённых в базу страниц (-1, если база не в WAL режиме)
+	CheckpointedPages int
+	// FreelistBefore - количество свободных страниц до обслуживания
+	FreelistBefore int64
+	// FreelistAfter - количество свободных страниц после обслуживания
+	FreelistAfter int64
+	// Duration - длительность обслуживания
+	Duration time.Duration
+}
+
+// Maintain выполняет обслуживание базы данных: WAL checkpoint, PRAGMA optimize и incremental vacuum.
+// Для read-only баз возвращает ошибку вида shared.KindValidation.
+func Maintain(ctx context.Context, db *sql.DB, opts MaintenanceOptions) (MaintenanceReport, error) {
+	var report MaintenanceReport
+	start := time.Now()
+
+	run := func(ctx context.Context) error {
+		return maintain(ctx, db, opts, &report)
+	}
+
+	var err error
+	if opts.TxRunner != nil {
+		err = opts.TxRunner.runWithoutTx(ctx, run)
+	} else {
+		err = run(ctx)
+	}
+	report.Duration = time.Since(start)
+
+	if err != nil && isReadOnlyError(err) {
+		return report, shared.MarkKind(err, shared.KindValidation)
+	}
+	return report, err
+}
+
+// MaintenanceJob возвращает задачу обслуживания для планировщика.
+// Результат совместим с scheduler.JobFunc.
+func MaintenanceJob(db *sql.DB, opts MaintenanceOptions) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := Maintain(ctx, db, opts)
+		return err
+	}
+}
+
+// maintain выполняет шаги обслуживания и заполняет отчёт.
+func maintain(ctx context.Context, db *sql.DB, opts MaintenanceOptions, report *MaintenanceReport) error {
+	var queryOnly bool
+	if err := db.QueryRowContext(ctx, "PRAGMA query_only").Scan(&queryOnly); err != nil {
+		return fmt.Errorf("failed to check query_only: %w", err)
+	}
+	if queryOnly {
+		return shared.MarkKind(errors.New("maintenance is not allowed for read-only database"), shared.KindValidation)
+	}
+
+	if err := db.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&report.FreelistBefore); err != nil {
+		return fmt.Errorf("failed to read freelist_count: %w", err)
+	}
+
+	if opts.IncrementalVacuumPages > 0 {
+		query := fmt.Sprintf("PRAGMA incremental_vacuum(%d)", opts.IncrementalVacuumPages)
+		if _, err := db.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to run incremental vacuum: %w", err)
+		}
+	}
+
+	if opts.Analyze {
+		if _, err := db.ExecContext(ctx, "PRAGMA optimize"); err != nil {
+			return fmt.Errorf("failed to optimize: %w", err)
+		}
+	}
+
+	// Checkpoint выполняется последним, чтобы перенести в базу и изменения vacuum/optimize
+	if opts.Checkpoint != CheckpointNone {
+		query := fmt.Sprintf("PRAGMA wal_checkpoint(%s)", opts.Checkpoint)
+		var busy int
+		if err := db.QueryRowContext(ctx, query).Scan(&busy, &report.WALPages, &report.CheckpointedPages); err != nil {
+			return fmt.Errorf("failed to checkpoint WAL: %w", err)
+		}
+		report.CheckpointBusy = busy != 0
+	}
+
+	if err := db.QueryRowContext(ctx, "PRAGMA freelist_count").Scan(&report.FreelistAfter); err != nil {
+		return fmt.Errorf("failed to read freelist_count: %w", err)
+	}
+	return nil
+}
+
+// isReadOnlyError проверяет, является ли ошибка SQLITE_READONLY.
+func isReadOnlyError(err error) bool {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.Code()&0xff == sqlite3.SQLITE_READONLY {
+		return true
+	}
+	return strings.Contains(err.Error(), "readonly database")
+}
@@ -0,0 +1,104 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+func newMaintenanceTestDB(t *testing.T, opts DBOptions) (string, *TestDB) {
+	t.Helper()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "app.db")
+
+	db, err := NewDBWithOptions(ctx, path, opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	testDB := &TestDB{DB: db, Path: path, TxRunner: NewTxRunnerWithOptions(db, opts)}
+	t.Cleanup(func() { _ = testDB.TxRunner.Close() })
+	return path, testDB
+}
+
+func TestMaintain(t *testing.T) {
+	ctx := context.Background()
+	path, testDB := newMaintenanceTestDB(t, DefaultDBOptions())
+
+	testDB.MustSeedData(t,
+		"PRAGMA auto_vacuum = INCREMENTAL",
+		"VACUUM",
+		"CREATE TABLE blobs (id INTEGER PRIMARY KEY, data BLOB)",
+	)
+	for i := 0; i < 200; i++ {
+		testDB.Exec(t, "INSERT INTO blobs (data) VALUES (randomblob(4096))")
+	}
+	testDB.Exec(t, "DELETE FROM blobs")
+
+	walInfo, err := os.Stat(path + "-wal")
+	require.NoError(t, err)
+	require.Positive(t, walInfo.Size())
+
+	report, err := Maintain(ctx, testDB.DB, MaintenanceOptions{
+		Checkpoint:             CheckpointTruncate,
+		Analyze:                true,
+		IncrementalVacuumPages: 1000,
+	})
+	require.NoError(t, err)
+
+	assert.False(t, report.CheckpointBusy)
+	assert.Positive(t, report.FreelistBefore)
+	assert.Less(t, report.FreelistAfter, report.FreelistBefore, "incremental vacuum должен освобождать страницы")
+	assert.Positive(t, report.Duration)
+
+	walInfo, err = os.Stat(path + "-wal")
+	require.NoError(t, err)
+	assert.Zero(t, walInfo.Size(), "TRUNCATE должен обрезать WAL файл")
+}
+
+func TestMaintain_WithWriteQueue(t *testing.T) {
+	opts := DefaultDBOptions()
+	opts.EnableWriteQueue = true
+	_, testDB := newMaintenanceTestDB(t, opts)
+	testDB.MustSeedData(t, "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errCh := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		go func(i int) {
+			errCh <- testDB.TxRunner.WithinTxWrite(ctx, func(ctx context.Context) error {
+				_, err := testDB.TxRunner.GetQuerier(ctx).ExecContext(ctx, "INSERT INTO items (name) VALUES (?)", fmt.Sprint(i))
+				return err
+			})
+		}(i)
+	}
+
+	job := MaintenanceJob(testDB.DB, MaintenanceOptions{Checkpoint: CheckpointPassive, TxRunner: testDB.TxRunner})
+	require.NoError(t, job(ctx))
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, <-errCh)
+	}
+}
+
+func TestMaintain_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	path, _ := newMaintenanceTestDB(t, DefaultDBOptions())
+
+	roDB, err := NewDBFromDSN(ctx, path+"?_pragma=query_only(1)")
+	require.NoError(t, err)
+	defer roDB.Close()
+
+	_, err = Maintain(ctx, roDB, MaintenanceOptions{Checkpoint: CheckpointTruncate, Analyze: true, IncrementalVacuumPages: 10})
+	require.Error(t, err)
+	assert.True(t, shared.IsValidation(err), "для read-only базы нужна ошибка KindValidation, получено: %v", err)
+}
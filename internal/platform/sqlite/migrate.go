@@ -3,6 +3,7 @@ package sqlite
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	migrate "github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
 // BuildMigrateURL строит корректный URL для golang-migrate с учётом особенностей ОС.
@@ -51,40 +53,134 @@ func BuildMigrateURL(dbPath string) (string, error) {
 func ApplyMigrations(dbPath, migrationsPath string) error {
 	// Создаем отдельное соединение для миграций
 	// golang-migrate может безопасно закрыть это соединение
-	databaseURL, err := BuildMigrateURL(dbPath)
+	m, err := newMigrate(dbPath, migrationsPath)
 	if err != nil {
-		return fmt.Errorf("failed to build database URL: %w", err)
+		return err
 	}
+	return applyMigrations(m)
+}
 
-	m, err := migrate.New(migrationsPath, databaseURL)
+// ApplyMigrationsFS применяет миграции из директории dir файловой системы fsys
+// (например, embed.FS). Поведение совпадает с ApplyMigrations.
+func ApplyMigrationsFS(dbPath string, fsys fs.FS, dir string) error {
+	m, err := newMigrateFS(dbPath, fsys, dir)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
-	}
-	defer func() {
-		// Закрываем ресурсы migrate, игнорируя ошибки закрытия
-		_, _ = m.Close()
-	}()
-
-	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return fmt.Errorf("failed to apply migrations: %w", err)
+		return err
 	}
-
-	return nil
+	return applyMigrations(m)
 }
 
 // GetMigrationVersion возвращает текущую версию примененных миграций.
 // Полезно для логирования и отладки.
 func GetMigrationVersion(dbPath, migrationsPath string) (uint, bool, error) {
 	// Создаем отдельное соединение для проверки версии миграций
+	m, err := newMigrate(dbPath, migrationsPath)
+	if err != nil {
+		return 0, false, err
+	}
+	return migrationVersion(m)
+}
+
+// GetMigrationVersionFS возвращает текущую версию миграций из файловой системы fsys.
+func GetMigrationVersionFS(dbPath string, fsys fs.FS, dir string) (uint, bool, error) {
+	m, err := newMigrateFS(dbPath, fsys, dir)
+	if err != nil {
+		return 0, false, err
+	}
+	return migrationVersion(m)
+}
+
+// DowngradeToVersion откатывает миграции до указанной версии.
+// Используется для тестирования или отката проблемных миграций.
+func DowngradeToVersion(dbPath, migrationsPath string, version uint) error {
+	// Создаем отдельное соединение для отката миграций
+	m, err := newMigrate(dbPath, migrationsPath)
+	if err != nil {
+		return err
+	}
+	return downgradeToVersion(m, version)
+}
+
+// DowngradeToVersionFS откатывает миграции из файловой системы fsys до указанной версии.
+func DowngradeToVersionFS(dbPath string, fsys fs.FS, dir string, version uint) error {
+	m, err := newMigrateFS(dbPath, fsys, dir)
+	if err != nil {
+		return err
+	}
+	return downgradeToVersion(m, version)
+}
+
+// ResetMigrations откатывает все миграции (опасная операция!).
+// Используется только в тестах или при необходимости полного сброса схемы.
+func ResetMigrations(dbPath, migrationsPath string) error {
+	// Создаем отдельное соединение для сброса миграций
+	m, err := newMigrate(dbPath, migrationsPath)
+	if err != nil {
+		return err
+	}
+	return resetMigrations(m)
+}
+
+// ResetMigrationsFS откатывает все миграции из файловой системы fsys (опасная операция!).
+func ResetMigrationsFS(dbPath string, fsys fs.FS, dir string) error {
+	m, err := newMigrateFS(dbPath, fsys, dir)
+	if err != nil {
+		return err
+	}
+	return resetMigrations(m)
+}
+
+// newMigrate создаёт экземпляр migrate для миграций по URL источника.
+func newMigrate(dbPath, migrationsPath string) (*migrate.Migrate, error) {
 	databaseURL, err := BuildMigrateURL(dbPath)
 	if err != nil {
-		return 0, false, fmt.Errorf("failed to build database URL: %w", err)
+		return nil, fmt.Errorf("failed to build database URL: %w", err)
 	}
 
 	m, err := migrate.New(migrationsPath, databaseURL)
 	if err != nil {
-		return 0, false, fmt.Errorf("failed to create migrate instance: %w", err)
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+	return m, nil
+}
+
+// newMigrateFS создаёт экземпляр migrate для миграций из fs.FS через драйвер iofs.
+// Пути внутри fs.FS всегда разделяются "/", поэтому особенности ОС не учитываются.
+func newMigrateFS(dbPath string, fsys fs.FS, dir string) (*migrate.Migrate, error) {
+	databaseURL, err := BuildMigrateURL(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build database URL: %w", err)
+	}
+
+	source, err := iofs.New(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migrations source %s: %w", dir, err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", source, databaseURL)
+	if err != nil {
+		_ = source.Close()
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
 	}
+	return m, nil
+}
+
+// applyMigrations применяет все миграции и закрывает m.
+func applyMigrations(m *migrate.Migrate) error {
+	defer func() {
+		// Закрываем ресурсы migrate, игнорируя ошибки закрытия
+		_, _ = m.Close()
+	}()
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("failed to apply migrations: %w", wrapDirty(err))
+	}
+
+	return nil
+}
+
+// migrationVersion возвращает текущую версию миграций и закрывает m.
+func migrationVersion(m *migrate.Migrate) (uint, bool, error) {
 	defer func() {
 		_, _ = m.Close()
 	}()
@@ -101,49 +197,27 @@ func GetMigrationVersion(dbPath, migrationsPath string) (uint, bool, error) {
 	return version, dirty, nil
 }
 
-// DowngradeToVersion откатывает миграции до указанной версии.
-// Используется для тестирования или отката проблемных миграций.
-func DowngradeToVersion(dbPath, migrationsPath string, version uint) error {
-	// Создаем отдельное соединение для отката миграций
-	databaseURL, err := BuildMigrateURL(dbPath)
-	if err != nil {
-		return fmt.Errorf("failed to build database URL: %w", err)
-	}
-
-	m, err := migrate.New(migrationsPath, databaseURL)
-	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
-	}
+// downgradeToVersion откатывает миграции до версии version и закрывает m.
+func downgradeToVersion(m *migrate.Migrate, version uint) error {
 	defer func() {
 		_, _ = m.Close()
 	}()
 
 	if err := m.Migrate(version); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return fmt.Errorf("failed to downgrade to version %d: %w", version, err)
+		return fmt.Errorf("failed to downgrade to version %d: %w", version, wrapDirty(err))
 	}
 
 	return nil
 }
 
-// ResetMigrations откатывает все миграции (опасная операция!).
-// Используется только в тестах или при необходимости полного сброса схемы.
-func ResetMigrations(dbPath, migrationsPath string) error {
-	// Создаем отдельное соединение для сброса миграций
-	databaseURL, err := BuildMigrateURL(dbPath)
-	if err != nil {
-		return fmt.Errorf("failed to build database URL: %w", err)
-	}
-
-	m, err := migrate.New(migrationsPath, databaseURL)
-	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
-	}
+// resetMigrations откатывает все миграции и закрывает m.
+func resetMigrations(m *migrate.Migrate) error {
 	defer func() {
 		_, _ = m.Close()
 	}()
 
 	if err := m.Down(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return fmt.Errorf("failed to reset migrations: %w", err)
+		return fmt.Errorf("failed to reset migrations: %w", wrapDirty(err))
 	}
 
 	return nil
@@ -0,0 +1,373 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	migrate "github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// ErrDirtyMigration означает, что предыдущая миграция завершилась с ошибкой и база
+// осталась в «грязном» состоянии. Дальнейшие миграции невозможны, пока схема не
+// исправлена вручную и версия не выставлена через ForceVersion.
+// Ошибка также содержит migrate.ErrDirty с номером версии.
+var ErrDirtyMigration = errors.New("sqlite: database is in dirty migration state")
+
+// MigrationInfo описывает одну миграцию из источника.
+type MigrationInfo struct {
+	Version uint   // Версия миграции
+	Name    string // Имя миграции без версии и суффикса (например, "create_users")
+	UpSQL   string // SQL up-миграции (пусто, если up-файла нет)
+}
+
+// PendingMigrations возвращает миграции из sourceURL с версией выше текущей,
+// не изменяя базу данных. Несуществующая база считается пустой.
+// Для базы в «грязном» состоянии возвращается ошибка ErrDirtyMigration.
+func PendingMigrations(dbPath, sourceURL string) ([]MigrationInfo, error) {
+	src, err := source.Open(sourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migrations source: %w", err)
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	return pendingMigrations(dbPath, src)
+}
+
+// PendingMigrationsFS возвращает ожидающие миграции из директории dir файловой системы fsys.
+func PendingMigrationsFS(dbPath string, fsys fs.FS, dir string) ([]MigrationInfo, error) {
+	src, err := iofs.New(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migrations source %s: %w", dir, err)
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	return pendingMigrations(dbPath, src)
+}
+
+// ApplyMigrationsDryRun проверяет ожидающие миграции без изменения базы данных
+// и возвращает их список. Проверяется, что:
+//   - база не находится в «грязном» состоянии;
+//   - текущая версия базы есть в источнике, то есть ожидающие миграции продолжают её без пропусков;
+//   - у каждой ожидающей миграции есть пара up/down файлов;
+//   - SQL каждой миграции выполняется на копии схемы в памяти (без данных).
+//
+// Ошибки, зависящие от данных (например, нарушение ограничений), dry-run не обнаружит.
+func ApplyMigrationsDryRun(dbPath, sourceURL string) ([]MigrationInfo, error) {
+	src, err := source.Open(sourceURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migrations source: %w", err)
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	return dryRunMigrations(dbPath, src)
+}
+
+// ApplyMigrationsDryRunFS проверяет ожидающие миграции из файловой системы fsys.
+// Поведение совпадает с ApplyMigrationsDryRun.
+func ApplyMigrationsDryRunFS(dbPath string, fsys fs.FS, dir string) ([]MigrationInfo, error) {
+	src, err := iofs.New(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open migrations source %s: %w", dir, err)
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	return dryRunMigrations(dbPath, src)
+}
+
+// ForceVersion выставляет версию миграций без выполнения SQL и снимает флаг «грязного»
+// состояния. Используется для восстановления после неудачной миграции: сначала схема
+// исправляется вручную, затем версия выставляется в последнюю корректно применённую.
+func ForceVersion(dbPath, sourceURL string, version uint) error {
+	m, err := newMigrate(dbPath, sourceURL)
+	if err != nil {
+		return err
+	}
+	return forceVersion(m, version)
+}
+
+// ForceVersionFS выставляет версию миграций для источника из файловой системы fsys.
+func ForceVersionFS(dbPath string, fsys fs.FS, dir string, version uint) error {
+	m, err := newMigrateFS(dbPath, fsys, dir)
+	if err != nil {
+		return err
+	}
+	return forceVersion(m, version)
+}
+
+// forceVersion выставляет версию version и закрывает m.
+func forceVersion(m *migrate.Migrate, version uint) error {
+	defer func() {
+		_, _ = m.Close()
+	}()
+
+	if err := m.Force(int(version)); err != nil {
+		return fmt.Errorf("failed to force version %d: %w", version, err)
+	}
+	return nil
+}
+
+// wrapDirty добавляет ErrDirtyMigration к ошибке golang-migrate о «грязном» состоянии.
+func wrapDirty(err error) error {
+	var dirty migrate.ErrDirty
+	if errors.As(err, &dirty) {
+		return fmt.Errorf("%w: %w", ErrDirtyMigration, err)
+	}
+	return err
+}
+
+// pendingMigrations читает состояние базы и возвращает миграции src выше текущей версии.
+func pendingMigrations(dbPath string, src source.Driver) ([]MigrationInfo, error) {
+	version, dirty, err := readMigrationState(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, wrapDirty(migrate.ErrDirty{Version: int(version)})
+	}
+	return listPending(src, version)
+}
+
+// listPending возвращает миграции src с версией выше current.
+// Возвращает ошибку, если current не равна нулю и отсутствует в источнике.
+func listPending(src source.Driver, current uint) ([]MigrationInfo, error) {
+	var pending []MigrationInfo
+	foundCurrent := current == 0
+
+	version, err := src.First()
+	for err == nil {
+		if version == current {
+			foundCurrent = true
+		}
+		if version > current {
+			info, readErr := readMigration(src, version)
+			if readErr != nil {
+				return nil, readErr
+			}
+			pending = append(pending, info)
+		}
+		version, err = src.Next(version)
+	}
+	// Источник без миграций или конец списка
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	if !foundCurrent {
+		return nil, fmt.Errorf("current version %d not found in migrations source", current)
+	}
+	return pending, nil
+}
+
+// readMigration читает up-миграцию версии version.
+func readMigration(src source.Driver, version uint) (MigrationInfo, error) {
+	info := MigrationInfo{Version: version}
+
+	r, identifier, err := src.ReadUp(version)
+	if errors.Is(err, os.ErrNotExist) {
+		// Есть только down-файл: golang-migrate просто поднимет версию
+		_, info.Name, _ = readDownIdentifier(src, version)
+		return info, nil
+	}
+	if err != nil {
+		return info, fmt.Errorf("failed to read migration %d: %w", version, err)
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return info, fmt.Errorf("failed to read migration %d: %w", version, err)
+	}
+	info.Name = identifier
+	info.UpSQL = string(body)
+	return info, nil
+}
+
+// readDownIdentifier проверяет наличие down-файла версии version и возвращает его имя.
+func readDownIdentifier(src source.Driver, version uint) (bool, string, error) {
+	r, identifier, err := src.ReadDown(version)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, "", nil
+	}
+	if err != nil {
+		return false, "", fmt.Errorf("failed to read down migration %d: %w", version, err)
+	}
+	_ = r.Close()
+	return true, identifier, nil
+}
+
+// dryRunMigrations проверяет ожидающие миграции на копии схемы в памяти.
+func dryRunMigrations(dbPath string, src source.Driver) ([]MigrationInfo, error) {
+	pending, err := pendingMigrations(dbPath, src)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return pending, nil
+	}
+
+	// Проверяем пары up/down файлов
+	for _, info := range pending {
+		if info.UpSQL == "" {
+			return pending, fmt.Errorf("migration %d (%s): up file is missing or empty", info.Version, info.Name)
+		}
+		hasDown, _, err := readDownIdentifier(src, info.Version)
+		if err != nil {
+			return pending, err
+		}
+		if !hasDown {
+			return pending, fmt.Errorf("migration %d (%s): down file is missing", info.Version, info.Name)
+		}
+	}
+
+	ctx := context.Background()
+	schema, err := readSchema(ctx, dbPath)
+	if err != nil {
+		return pending, err
+	}
+
+	// Временная база в памяти со схемой без данных
+	scratch, err := NewInMemoryDB(ctx)
+	if err != nil {
+		return pending, fmt.Errorf("failed to open scratch database: %w", err)
+	}
+	defer func() {
+		_ = scratch.Close()
+	}()
+
+	for _, stmt := range schema {
+		if _, err := scratch.ExecContext(ctx, stmt); err != nil {
+			return pending, fmt.Errorf("failed to copy schema: %w", err)
+		}
+	}
+	for _, info := range pending {
+		if _, err := scratch.ExecContext(ctx, info.UpSQL); err != nil {
+			return pending, fmt.Errorf("migration %d (%s) failed: %w", info.Version, info.Name, err)
+		}
+	}
+
+	return pending, nil
+}
+
+// openReadOnly открывает существующую базу только для чтения без изменения режима журнала.
+// Для несуществующего файла возвращает nil без ошибки, чтобы не создавать его.
+func openReadOnly(ctx context.Context, dbPath string) (*sql.DB, error) {
+	if _, err := os.Stat(dbPath); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	opts := DefaultDBOptions()
+	opts.AccessMode = AccessModeReadOnly
+	opts.WALMode = false // не меняем режим журнала чужой базы
+	return NewDBWithOptions(ctx, dbPath, opts)
+}
+
+// readMigrationState читает версию и флаг «грязного» состояния из таблицы golang-migrate,
+// не создавая её. Несуществующая база или таблица означает версию 0.
+func readMigrationState(dbPath string) (uint, bool, error) {
+	ctx := context.Background()
+	db, err := openReadOnly(ctx, dbPath)
+	if err != nil || db == nil {
+		return 0, false, err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	var tables int
+	err = db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?",
+		migratesqlite.DefaultMigrationsTable).Scan(&tables)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to check migrations table: %w", err)
+	}
+	if tables == 0 {
+		return 0, false, nil
+	}
+
+	var version int64
+	var dirty bool
+	err = db.QueryRowContext(ctx,
+		"SELECT version, dirty FROM "+migratesqlite.DefaultMigrationsTable+" LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get migration version: %w", err)
+	}
+	// golang-migrate хранит -1, если ни одна миграция не применена
+	if version < 0 {
+		version = 0
+	}
+	return uint(version), dirty, nil
+}
+
+// readSchema возвращает DDL всех пользовательских объектов базы в порядке создания:
+// сначала таблицы, затем индексы, представления и триггеры.
+// Служебные таблицы SQLite, golang-migrate и теневые таблицы виртуальных таблиц пропускаются.
+func readSchema(ctx context.Context, dbPath string) ([]string, error) {
+	db, err := openReadOnly(ctx, dbPath)
+	if err != nil || db == nil {
+		return nil, err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT type, name, sql FROM sqlite_master
+		WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%' AND tbl_name != ?
+		ORDER BY CASE type WHEN 'table' THEN 0 WHEN 'index' THEN 1 ELSE 2 END, rowid`,
+		migratesqlite.DefaultMigrationsTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var schema []string
+	var virtualTables []string
+	for rows.Next() {
+		var typ, name, stmt string
+		if err := rows.Scan(&typ, &name, &stmt); err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+		if isShadowTable(name, virtualTables) {
+			continue
+		}
+		if typ == "table" && strings.HasPrefix(strings.ToUpper(stmt), "CREATE VIRTUAL TABLE") {
+			virtualTables = append(virtualTables, name)
+		}
+		schema = append(schema, stmt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	return schema, nil
+}
+
+// isShadowTable проверяет, является ли таблица теневой таблицей одной из виртуальных
+// (например, docs_content для FTS-таблицы docs). Их создаёт сама виртуальная таблица.
+func isShadowTable(name string, virtualTables []string) bool {
+	for _, vt := range virtualTables {
+		if strings.HasPrefix(name, vt+"_") {
+			return true
+		}
+	}
+	return false
+}
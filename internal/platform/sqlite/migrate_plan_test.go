@@ -0,0 +1,205 @@
+package sqlite
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	migrate "github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingMigrations(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "app.db")
+	fsys := testMigrationsFS()
+
+	// Несуществующая база: все миграции ожидают применения, файл не создаётся
+	pending, err := PendingMigrationsFS(dbPath, fsys, "migrations")
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, MigrationInfo{
+		Version: 1,
+		Name:    "create_test1",
+		UpSQL:   "CREATE TABLE test1 (id INTEGER PRIMARY KEY);",
+	}, pending[0])
+	assert.Equal(t, uint(2), pending[1].Version)
+	_, err = os.Stat(dbPath)
+	assert.True(t, errors.Is(err, os.ErrNotExist), "PendingMigrations must not create the database")
+
+	require.NoError(t, DowngradeToVersionFS(dbPath, fsys, "migrations", 1))
+	pending, err = PendingMigrationsFS(dbPath, fsys, "migrations")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "create_test2", pending[0].Name)
+
+	require.NoError(t, ApplyMigrationsFS(dbPath, fsys, "migrations"))
+	pending, err = PendingMigrationsFS(dbPath, fsys, "migrations")
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestPendingMigrations_URL(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "001_init.up.sql"), []byte("CREATE TABLE a (id INTEGER);"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "001_init.down.sql"), []byte("DROP TABLE a;"), 0644))
+	dbPath := filepath.Join(t.TempDir(), "app.db")
+
+	pending, err := PendingMigrations(dbPath, "file://"+filepath.ToSlash(dir))
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "init", pending[0].Name)
+
+	pending, err = ApplyMigrationsDryRun(dbPath, "file://"+filepath.ToSlash(dir))
+	require.NoError(t, err)
+	assert.Len(t, pending, 1)
+}
+
+func TestPendingMigrations_UnknownCurrentVersion(t *testing.T) {
+	testDB := NewTestDBFile(t)
+	require.NoError(t, ApplyMigrationsFS(testDB.Path, testMigrationsFS(), "migrations"))
+
+	// Источник, в котором нет применённой версии 2
+	fsys := fstest.MapFS{
+		"migrations/001_create_test1.up.sql":   {Data: []byte("CREATE TABLE test1 (id INTEGER PRIMARY KEY);")},
+		"migrations/001_create_test1.down.sql": {Data: []byte("DROP TABLE test1;")},
+		"migrations/003_create_test3.up.sql":   {Data: []byte("CREATE TABLE test3 (id INTEGER PRIMARY KEY);")},
+		"migrations/003_create_test3.down.sql": {Data: []byte("DROP TABLE test3;")},
+	}
+	_, err := PendingMigrationsFS(testDB.Path, fsys, "migrations")
+	assert.ErrorContains(t, err, "current version 2 not found")
+}
+
+func TestApplyMigrationsDryRun(t *testing.T) {
+	testDB := NewTestDBFile(t)
+	fsys := testMigrationsFS()
+	require.NoError(t, DowngradeToVersionFS(testDB.Path, fsys, "migrations", 1))
+
+	// Новые миграции опираются на существующую схему
+	fsys["migrations/003_add_name.up.sql"] = &fstest.MapFile{Data: []byte(
+		"ALTER TABLE test1 ADD COLUMN name TEXT;\nCREATE INDEX idx_test1_name ON test1(name);")}
+	fsys["migrations/003_add_name.down.sql"] = &fstest.MapFile{Data: []byte("DROP INDEX idx_test1_name;")}
+
+	pending, err := ApplyMigrationsDryRunFS(testDB.Path, fsys, "migrations")
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, []uint{2, 3}, []uint{pending[0].Version, pending[1].Version})
+
+	// База не изменилась
+	version, dirty, err := GetMigrationVersionFS(testDB.Path, fsys, "migrations")
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), version)
+	assert.False(t, dirty)
+	var count int
+	require.NoError(t, testDB.DB.QueryRow(
+		"SELECT COUNT(*) FROM sqlite_master WHERE name IN ('test2', 'idx_test1_name')").Scan(&count))
+	assert.Zero(t, count)
+}
+
+func TestApplyMigrationsDryRun_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name: "invalid sql",
+			files: map[string]string{
+				"003_broken.up.sql":   "CREATE TABLE broken (id INTEGER PRIMARY KEY",
+				"003_broken.down.sql": "DROP TABLE broken;",
+			},
+			wantErr: "migration 3 (broken) failed",
+		},
+		{
+			name: "unknown table",
+			files: map[string]string{
+				"003_alter.up.sql":   "ALTER TABLE missing ADD COLUMN name TEXT;",
+				"003_alter.down.sql": "SELECT 1;",
+			},
+			wantErr: "migration 3 (alter) failed",
+		},
+		{
+			name: "missing down file",
+			files: map[string]string{
+				"003_no_down.up.sql": "CREATE TABLE t3 (id INTEGER);",
+			},
+			wantErr: "down file is missing",
+		},
+		{
+			name: "missing up file",
+			files: map[string]string{
+				"003_no_up.down.sql": "SELECT 1;",
+			},
+			wantErr: "up file is missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testDB := NewTestDBFile(t)
+			fsys := testMigrationsFS()
+			require.NoError(t, ApplyMigrationsFS(testDB.Path, fsys, "migrations"))
+			for name, data := range tt.files {
+				fsys["migrations/"+name] = &fstest.MapFile{Data: []byte(data)}
+			}
+
+			pending, err := ApplyMigrationsDryRunFS(testDB.Path, fsys, "migrations")
+			assert.ErrorContains(t, err, tt.wantErr)
+			assert.Len(t, pending, 1)
+		})
+	}
+}
+
+func TestForceVersion_RecoversDirtyState(t *testing.T) {
+	testDB := NewTestDBFile(t)
+	fsys := testMigrationsFS()
+	fsys["migrations/003_broken.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE broken (")}
+	fsys["migrations/003_broken.down.sql"] = &fstest.MapFile{Data: []byte("DROP TABLE broken;")}
+
+	// Неудачная миграция оставляет базу в грязном состоянии
+	require.Error(t, ApplyMigrationsFS(testDB.Path, fsys, "migrations"))
+
+	version, dirty, err := GetMigrationVersionFS(testDB.Path, fsys, "migrations")
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), version)
+	assert.True(t, dirty)
+
+	// Грязное состояние видно до любых изменений
+	_, err = PendingMigrationsFS(testDB.Path, fsys, "migrations")
+	require.ErrorIs(t, err, ErrDirtyMigration)
+	var dirtyErr migrate.ErrDirty
+	require.ErrorAs(t, err, &dirtyErr)
+	assert.Equal(t, 3, dirtyErr.Version)
+
+	_, err = ApplyMigrationsDryRunFS(testDB.Path, fsys, "migrations")
+	assert.ErrorIs(t, err, ErrDirtyMigration)
+
+	err = ApplyMigrationsFS(testDB.Path, fsys, "migrations")
+	assert.ErrorIs(t, err, ErrDirtyMigration)
+
+	// Откатываемся к последней корректной версии
+	require.NoError(t, ForceVersionFS(testDB.Path, fsys, "migrations", 2))
+	version, dirty, err = GetMigrationVersionFS(testDB.Path, fsys, "migrations")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), version)
+	assert.False(t, dirty)
+
+	pending, err := PendingMigrationsFS(testDB.Path, fsys, "migrations")
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "broken", pending[0].Name)
+}
+
+func TestForceVersion_InvalidPath(t *testing.T) {
+	testDB := NewTestDBFile(t)
+
+	assert.Error(t, ForceVersion(testDB.Path, "file:///nonexistent/path", 1))
+	assert.Error(t, ForceVersionFS(testDB.Path, testMigrationsFS(), "nonexistent", 1))
+
+	_, err := PendingMigrations(testDB.Path, "file:///nonexistent/path")
+	assert.Error(t, err)
+	_, err = ApplyMigrationsDryRun(testDB.Path, "file:///nonexistent/path")
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -367,3 +368,68 @@ func TestMigrations_InvalidPath(t *testing.T) {
 	err = ResetMigrations(dbPath, invalidPath)
 	assert.Error(t, err)
 }
+
+func testMigrationsFS() fstest.MapFS {
+	return fstest.MapFS{
+		"migrations/001_create_test1.up.sql":   {Data: []byte("CREATE TABLE test1 (id INTEGER PRIMARY KEY);")},
+		"migrations/001_create_test1.down.sql": {Data: []byte("DROP TABLE test1;")},
+		"migrations/002_create_test2.up.sql":   {Data: []byte("CREATE TABLE test2 (id INTEGER PRIMARY KEY);")},
+		"migrations/002_create_test2.down.sql": {Data: []byte("DROP TABLE test2;")},
+	}
+}
+
+func TestMigrationsFS(t *testing.T) {
+	ctx := context.Background()
+	testDB := NewTestDBFile(t)
+	fsys := testMigrationsFS()
+
+	tableExists := func(name string) bool {
+		var count int
+		err := testDB.DB.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name=?", name).Scan(&count)
+		require.NoError(t, err)
+		return count == 1
+	}
+
+	version, dirty, err := GetMigrationVersionFS(testDB.Path, fsys, "migrations")
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), version)
+	assert.False(t, dirty)
+
+	require.NoError(t, ApplyMigrationsFS(testDB.Path, fsys, "migrations"))
+	// Повторное применение не считается ошибкой
+	require.NoError(t, ApplyMigrationsFS(testDB.Path, fsys, "migrations"))
+	assert.True(t, tableExists("test1"))
+	assert.True(t, tableExists("test2"))
+
+	version, _, err = GetMigrationVersionFS(testDB.Path, fsys, "migrations")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), version)
+
+	require.NoError(t, DowngradeToVersionFS(testDB.Path, fsys, "migrations", 1))
+	assert.True(t, tableExists("test1"))
+	assert.False(t, tableExists("test2"))
+
+	require.NoError(t, ResetMigrationsFS(testDB.Path, fsys, "migrations"))
+	assert.False(t, tableExists("test1"))
+}
+
+func TestMigrationsFS_InvalidDir(t *testing.T) {
+	testDB := NewTestDBFile(t)
+	fsys := testMigrationsFS()
+
+	assert.Error(t, ApplyMigrationsFS(testDB.Path, fsys, "nonexistent"))
+
+	_, _, err := GetMigrationVersionFS(testDB.Path, fsys, "nonexistent")
+	assert.Error(t, err)
+
+	assert.Error(t, DowngradeToVersionFS(testDB.Path, fsys, "nonexistent", 1))
+	assert.Error(t, ResetMigrationsFS(testDB.Path, fsys, "nonexistent"))
+}
+
+func TestTestDB_ApplyTestMigrationsFS(t *testing.T) {
+	testDB := NewTestDBFile(t)
+	testDB.ApplyTestMigrationsFS(t, testMigrationsFS(), "migrations")
+
+	testDB.Exec(t, "INSERT INTO test2 (id) VALUES (1)")
+}
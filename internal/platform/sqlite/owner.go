@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ExclusiveOwnerLock - имя advisory-блокировки, которую держит владелец при DBOptions.ExclusiveOwner.
+const ExclusiveOwnerLock = "sqlite.exclusive_owner"
+
+// ExclusiveOwnerTTL - TTL блокировки владельца. Блокировка продлевается каждую треть TTL
+// и снимается при закрытии БД; после аварийного завершения владельца она истекает через TTL.
+const ExclusiveOwnerTTL = 30 * time.Second
+
+// ErrDatabaseOwned возвращается NewDBWithOptions с DBOptions.ExclusiveOwner,
+// если базу уже открыл другой владелец.
+var ErrDatabaseOwned = errors.New("sqlite: database is owned by another instance")
+
+// ownerConnector открывает соединения базовым коннектором и снимает блокировку
+// владельца при закрытии пула: DB.Close вызывает Close коннектора, реализующего io.Closer.
+type ownerConnector struct {
+	driver.Connector
+	owner *ownerLock
+}
+
+// Close снимает блокировку владельца.
+func (c *ownerConnector) Close() error {
+	return c.owner.release()
+}
+
+// ownerLock держит блокировку владельца через отдельный пул из одного соединения,
+// чтобы её можно было снять после закрытия основного пула.
+type ownerLock struct {
+	connector driver.Connector
+
+	mu   sync.Mutex
+	db   *sql.DB
+	lock *AppLock
+	stop chan struct{}
+	done chan struct{}
+}
+
+// acquire захватывает блокировку владельца и запускает её продление.
+func (o *ownerLock) acquire(ctx context.Context, dbPath string) error {
+	db := sql.OpenDB(o.connector)
+	db.SetMaxOpenConns(1)
+
+	lock, acquired, err := AcquireLock(ctx, NewTxRunner(db), ExclusiveOwnerLock, ExclusiveOwnerTTL)
+	if err != nil {
+		_ = db.Close()
+		return fmt.Errorf("failed to acquire exclusive owner lock: %w", err)
+	}
+	if !acquired {
+		_ = db.Close()
+		return fmt.Errorf("%w: %s; the lock is released when the owner closes the database "+
+			"or expires %s after it stops (use DiagnoseLock to inspect)", ErrDatabaseOwned, dbPath, ExclusiveOwnerTTL)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.db, o.lock = db, lock
+	o.stop, o.done = make(chan struct{}), make(chan struct{})
+	go o.renew(o.stop, o.done)
+	return nil
+}
+
+// renew продлевает блокировку до остановки или её потери.
+func (o *ownerLock) renew(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(ExclusiveOwnerTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		// Временные ошибки (SQLITE_BUSY) повторяются на следующем тике
+		if err := o.lock.Renew(context.Background(), ExclusiveOwnerTTL); errors.Is(err, ErrLockNotHeld) {
+			return
+		}
+	}
+}
+
+// release останавливает продление и снимает блокировку. Повторные вызовы возвращают nil.
+func (o *ownerLock) release() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.db == nil {
+		return nil
+	}
+
+	close(o.stop)
+	<-o.done
+	err := o.lock.Release()
+	if errors.Is(err, ErrLockNotHeld) {
+		err = nil // Блокировка уже истекла, снимать нечего
+	}
+	err = errors.Join(err, o.db.Close())
+	o.db, o.lock = nil, nil
+	return err
+}
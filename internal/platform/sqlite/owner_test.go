@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExclusiveOwner(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "bot.db")
+	opts := DefaultDBOptions()
+	opts.ExclusiveOwner = true
+
+	first, err := NewDBWithOptions(ctx, path, opts)
+	require.NoError(t, err)
+
+	// Второй экземпляр сразу получает понятную ошибку
+	second, err := NewDBWithOptions(ctx, path, opts)
+	require.ErrorIs(t, err, ErrDatabaseOwned)
+	assert.Nil(t, second)
+	assert.Contains(t, err.Error(), path)
+
+	// Без ExclusiveOwner база открывается, владелец продолжает работать
+	shared, err := NewDBWithOptions(ctx, path, DefaultDBOptions())
+	require.NoError(t, err)
+	defer shared.Close()
+	_, err = first.ExecContext(ctx, "CREATE TABLE t (id INTEGER)")
+	require.NoError(t, err)
+
+	// Закрытие БД снимает блокировку
+	require.NoError(t, first.Close())
+	var locks int
+	require.NoError(t, shared.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+LocksTable).Scan(&locks))
+	assert.Zero(t, locks)
+
+	third, err := NewDBWithOptions(ctx, path, opts)
+	require.NoError(t, err)
+	require.NoError(t, third.Close())
+}
+
+func TestExclusiveOwner_WithConnectionInit(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "bot.db")
+	opts := DefaultDBOptions()
+	opts.ExclusiveOwner = true
+	var inits int
+	opts.ConnectionInit = func(ctx context.Context, conn *sql.Conn) error {
+		inits++
+		return nil
+	}
+
+	db, err := NewDBWithOptions(ctx, path, opts)
+	require.NoError(t, err)
+	defer db.Close()
+	assert.Positive(t, inits, "хук выполняется и для соединений блокировки владельца")
+
+	_, err = NewDBWithOptions(ctx, path, opts)
+	require.ErrorIs(t, err, ErrDatabaseOwned)
+}
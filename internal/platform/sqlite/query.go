@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"sttbot/internal/shared"
+)
+
+// QueryOne выполняет запрос, возвращающий одну строку, и сканирует её через scan.
+// sql.ErrNoRows помечается как shared.KindNotFound, при этом errors.Is(err, sql.ErrNoRows) продолжает работать.
+// q может быть *sql.DB, *sql.Tx или транзакцией из контекста (см. TxRunner.GetQuerier).
+func QueryOne[T any](ctx context.Context, q Querier, scan func(*sql.Row) (T, error), query string, args ...any) (T, error) {
+	v, err := scan(q.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		var zero T
+		if errors.Is(err, sql.ErrNoRows) {
+			return zero, shared.MarkKind(err, shared.KindNotFound)
+		}
+		return zero, err
+	}
+	return v, nil
+}
+
+// QueryMany выполняет запрос и сканирует каждую строку через scan.
+// Закрывает rows и возвращает ошибку rows.Err(). Для пустого результата возвращает пустой срез.
+func QueryMany[T any](ctx context.Context, q Querier, scan func(*sql.Rows) (T, error), query string, args ...any) ([]T, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]T, 0)
+	for rows.Next() {
+		v, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Exec выполняет запрос без результата и возвращает количество затронутых строк.
+func Exec(ctx context.Context, q Querier, query string, args ...any) (int64, error) {
+	res, err := q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+type queryTestUser struct {
+	ID   int64
+	Name string
+}
+
+func scanUserRow(row *sql.Row) (queryTestUser, error) {
+	var u queryTestUser
+	err := row.Scan(&u.ID, &u.Name)
+	return u, err
+}
+
+func scanUserRows(rows *sql.Rows) (queryTestUser, error) {
+	var u queryTestUser
+	err := rows.Scan(&u.ID, &u.Name)
+	return u, err
+}
+
+func newQueryTestDB(t *testing.T) *TestDB {
+	t.Helper()
+	testDB := NewTestDBInMemory(t)
+	testDB.MustSeedData(t,
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL)",
+		"INSERT INTO users (name) VALUES ('alice'), ('bob')",
+	)
+	return testDB
+}
+
+func TestQueryOne(t *testing.T) {
+	testDB := newQueryTestDB(t)
+	ctx := context.Background()
+
+	u, err := QueryOne(ctx, testDB.DB, scanUserRow, "SELECT id, name FROM users WHERE name = ?", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", u.Name)
+
+	_, err = QueryOne(ctx, testDB.DB, scanUserRow, "SELECT id, name FROM users WHERE name = ?", "carol")
+	require.Error(t, err)
+	assert.True(t, shared.IsNotFound(err), "sql.ErrNoRows должен помечаться как KindNotFound")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestQueryMany(t *testing.T) {
+	testDB := newQueryTestDB(t)
+	ctx := context.Background()
+
+	users, err := QueryMany(ctx, testDB.DB, scanUserRows, "SELECT id, name FROM users ORDER BY id")
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, "alice", users[0].Name)
+	assert.Equal(t, "bob", users[1].Name)
+
+	users, err = QueryMany(ctx, testDB.DB, scanUserRows, "SELECT id, name FROM users WHERE name = ?", "carol")
+	require.NoError(t, err)
+	assert.Empty(t, users)
+	assert.NotNil(t, users)
+
+	scanErr := errors.New("scan failed")
+	_, err = QueryMany(ctx, testDB.DB, func(*sql.Rows) (queryTestUser, error) {
+		return queryTestUser{}, scanErr
+	}, "SELECT id, name FROM users")
+	assert.ErrorIs(t, err, scanErr)
+}
+
+func TestExec(t *testing.T) {
+	testDB := newQueryTestDB(t)
+
+	n, err := Exec(context.Background(), testDB.DB, "UPDATE users SET name = name || '!'")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+}
+
+func TestQueryHelpers_WithinTx(t *testing.T) {
+	for _, mode := range []TxLockMode{TxLockDeferred, TxLockImmediate} {
+		t.Run(string(mode), func(t *testing.T) {
+			testDB := newQueryTestDB(t)
+			opts := DefaultDBOptions()
+			opts.TxLockMode = mode
+			runner := NewTxRunnerWithOptions(testDB.DB, opts)
+
+			err := runner.WithinTx(context.Background(), func(ctx context.Context) error {
+				q := runner.GetQuerier(ctx)
+
+				n, err := Exec(ctx, q, "INSERT INTO users (name) VALUES (?)", "carol")
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), n)
+
+				u, err := QueryOne(ctx, q, scanUserRow, "SELECT id, name FROM users WHERE name = ?", "carol")
+				require.NoError(t, err)
+				assert.Equal(t, "carol", u.Name)
+
+				users, err := QueryMany(ctx, q, scanUserRows, "SELECT id, name FROM users")
+				require.NoError(t, err)
+				assert.Len(t, users, 3)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 3, testDB.CountRows(t, "users"))
+		})
+	}
+}
+
+func TestQueryHelpers_WithinSavepoint(t *testing.T) {
+	testDB := newQueryTestDB(t)
+	runner := testDB.TxRunner
+	errRollback := errors.New("rollback")
+
+	err := runner.WithinTx(context.Background(), func(ctx context.Context) error {
+		err := runner.WithinSavepoint(ctx, func(ctx context.Context) error {
+			q := runner.GetQuerier(ctx)
+			_, err := Exec(ctx, q, "INSERT INTO users (name) VALUES (?)", "dave")
+			require.NoError(t, err)
+
+			_, err = QueryOne(ctx, q, scanUserRow, "SELECT id, name FROM users WHERE name = ?", "dave")
+			require.NoError(t, err)
+			return errRollback
+		})
+		require.ErrorIs(t, err, errRollback)
+
+		_, err = QueryOne(ctx, runner.GetQuerier(ctx), scanUserRow, "SELECT id, name FROM users WHERE name = ?", "dave")
+		assert.True(t, shared.IsNotFound(err), "строка из отменённого savepoint не должна быть видна")
+		return nil
+	})
+	require.NoError(t, err)
+}
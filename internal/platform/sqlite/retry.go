@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"sttbot/pkg/retry"
+)
+
+// RetryConfig содержит настройки для повторных попыток.
+type RetryConfig struct {
+	MaxAttempts  int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	// Jitter - стратегия рандомизации задержек (по умолчанию без jitter)
+	Jitter retry.JitterStrategy
+	// IsRetryable определяет, нужно ли повторять транзакцию (по умолчанию IsBusyError)
+	IsRetryable func(err error) bool
+	// OnRetry вызывается перед каждым повтором транзакции (необязательно)
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// DefaultRetryConfig возвращает настройки ретраев по умолчанию.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: 10 * time.Millisecond,
+		MaxDelay:     500 * time.Millisecond,
+		Multiplier:   2.0,
+	}
+}
+
+// IsBusyError проверяет, является ли ошибка SQLITE_BUSY или SQLITE_LOCKED.
+// Сначала проверяется код ошибки драйвера (включая расширенные коды),
+// затем текст ошибки для совместимости с обёрнутыми ошибками без кода.
+func IsBusyError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() & 0xff {
+		case sqlite3.SQLITE_BUSY, sqlite3.SQLITE_LOCKED:
+			return true
+		}
+	}
+
+	errStr := err.Error()
+	return strings.Contains(errStr, "database is locked") ||
+		strings.Contains(errStr, "SQLITE_BUSY") ||
+		strings.Contains(errStr, "database table is locked")
+}
+
+// executeWithRetry выполняет транзакцию с ретраями на SQLITE_BUSY.
+// После исчерпания попыток возвращает *retry.RetriesExceededError,
+// который оборачивает последнюю ошибку драйвера.
+func (r *TxRunner) executeWithRetry(ctx context.Context, opts TxOptions, fn func(context.Context) error) error {
+	cfg := DefaultRetryConfig()
+	if r.RetryConfig != nil {
+		cfg = *r.RetryConfig
+	}
+	if cfg.MaxAttempts <= 1 {
+		return r.executeTx(ctx, opts, fn)
+	}
+	if cfg.InitialDelay <= 0 {
+		cfg.InitialDelay = DefaultRetryConfig().InitialDelay
+	}
+
+	isRetryable := cfg.IsRetryable
+	if isRetryable == nil {
+		isRetryable = IsBusyError
+	}
+
+	return retry.DoWithRetryable(ctx, retry.Config{
+		MaxAttempts:    cfg.MaxAttempts,
+		InitialDelay:   cfg.InitialDelay,
+		MaxDelay:       cfg.MaxDelay,
+		Multiplier:     cfg.Multiplier,
+		JitterStrategy: cfg.Jitter,
+		OnRetry:        cfg.OnRetry,
+	}, func(ctx context.Context) error {
+		return r.executeTx(ctx, opts, fn)
+	}, isRetryable)
+}
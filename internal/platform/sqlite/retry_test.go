@@ -0,0 +1,161 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"modernc.org/sqlite"
+
+	"sttbot/pkg/retry"
+)
+
+// openLockedDB создаёт файловую БД, в которой другое подключение удерживает блокировку записи.
+// Возвращает второе подключение без busy timeout и функцию снятия блокировки.
+func openLockedDB(t *testing.T) (*sql.DB, func()) {
+	t.Helper()
+	ctx := context.Background()
+
+	opts := DefaultDBOptions()
+	opts.BusyTimeout = 0
+	path := filepath.Join(t.TempDir(), "busy.db")
+
+	holder, err := NewDBWithOptions(ctx, path, opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = holder.Close() })
+	_, err = holder.ExecContext(ctx, "CREATE TABLE test (id INTEGER PRIMARY KEY, value TEXT)")
+	require.NoError(t, err)
+
+	contender, err := NewDBWithOptions(ctx, path, opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = contender.Close() })
+
+	conn, err := holder.Conn(ctx)
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+	require.NoError(t, err)
+
+	unlock := func() {
+		_, _ = conn.ExecContext(ctx, "ROLLBACK")
+		_ = conn.Close()
+	}
+	t.Cleanup(unlock)
+	return contender, unlock
+}
+
+func TestIsBusyError(t *testing.T) {
+	db, _ := openLockedDB(t)
+
+	_, err := db.ExecContext(context.Background(), "INSERT INTO test (value) VALUES (?)", "x")
+	require.Error(t, err)
+
+	var sqliteErr *sqlite.Error
+	require.ErrorAs(t, err, &sqliteErr, "ошибка должна приходить от драйвера")
+	assert.True(t, IsBusyError(err))
+	assert.True(t, IsBusyError(fmt.Errorf("wrapped: %w", err)))
+
+	assert.True(t, IsBusyError(errors.New("database is locked")))
+	assert.False(t, IsBusyError(errors.New("no such table")))
+	assert.False(t, IsBusyError(nil))
+}
+
+func TestTxRunner_RetryReturnsLastDriverError(t *testing.T) {
+	db, _ := openLockedDB(t)
+
+	var retries int
+	opts := DefaultDBOptions()
+	opts.RetryConfig = &RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     5 * time.Millisecond,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			retries++
+		},
+	}
+	runner := NewTxRunnerWithOptions(db, opts)
+
+	err := runner.WithinTx(context.Background(), func(ctx context.Context) error {
+		_, err := runner.GetQuerier(ctx).ExecContext(ctx, "INSERT INTO test (value) VALUES (?)", "x")
+		return err
+	})
+	require.Error(t, err)
+
+	var sqliteErr *sqlite.Error
+	assert.ErrorAs(t, err, &sqliteErr, "должна сохраняться последняя ошибка драйвера")
+	var exceeded *retry.RetriesExceededError
+	require.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, 3, exceeded.Attempts)
+	assert.Equal(t, 2, retries)
+}
+
+func TestTxRunner_RetrySucceedsAfterUnlock(t *testing.T) {
+	db, unlock := openLockedDB(t)
+
+	opts := DefaultDBOptions()
+	opts.RetryConfig = &RetryConfig{
+		MaxAttempts:  5,
+		InitialDelay: 10 * time.Millisecond,
+		MaxDelay:     20 * time.Millisecond,
+		Jitter:       retry.JitterEqual,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			if attempt == 1 {
+				unlock()
+			}
+		},
+	}
+	runner := NewTxRunnerWithOptions(db, opts)
+
+	err := runner.WithinTx(context.Background(), func(ctx context.Context) error {
+		_, err := runner.GetQuerier(ctx).ExecContext(ctx, "INSERT INTO test (value) VALUES (?)", "x")
+		return err
+	})
+	require.NoError(t, err)
+}
+
+func TestTxRunner_CustomIsRetryable(t *testing.T) {
+	testDB := NewTestDBInMemory(t)
+	errTransient := errors.New("transient")
+
+	opts := DefaultDBOptions()
+	opts.RetryConfig = &RetryConfig{
+		MaxAttempts:  3,
+		InitialDelay: time.Millisecond,
+		IsRetryable: func(err error) bool {
+			return errors.Is(err, errTransient)
+		},
+	}
+	runner := NewTxRunnerWithOptions(testDB.DB, opts)
+
+	var attempts int
+	err := runner.WithinTx(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errTransient
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	err = runner.WithinTx(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errors.New("permanent")
+	})
+	require.Error(t, err)
+	assert.Equal(t, 1, attempts, "неретраибельная ошибка не должна повторяться")
+}
+
+func TestNewTxRunnerWithOptions_DefaultRetryConfig(t *testing.T) {
+	testDB := NewTestDBInMemory(t)
+
+	runner := NewTxRunnerWithOptions(testDB.DB, DefaultDBOptions())
+	require.NotNil(t, runner.RetryConfig)
+	assert.Equal(t, DefaultRetryConfig().MaxAttempts, runner.RetryConfig.MaxAttempts)
+}
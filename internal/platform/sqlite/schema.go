@@ -0,0 +1,343 @@
+package sqlite
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+)
+
+// SchemaInfo описывает структуру базы данных, прочитанную через Schema.
+// Все списки отсортированы, поэтому результат детерминирован и пригоден для сравнения и JSON.
+type SchemaInfo struct {
+	// Tables - пользовательские таблицы, отсортированные по имени
+	Tables []TableInfo `json:"tables"`
+}
+
+// TableInfo описывает таблицу.
+type TableInfo struct {
+	Name string `json:"name"`
+	// Columns - колонки в порядке объявления
+	Columns []ColumnInfo `json:"columns"`
+	// Indexes - индексы, отсортированные по имени, включая автоматические
+	// индексы UNIQUE и PRIMARY KEY (sqlite_autoindex_*)
+	Indexes []IndexInfo `json:"indexes"`
+	// ForeignKeys - внешние ключи, отсортированные по колонкам и целевой таблице
+	ForeignKeys []ForeignKeyInfo `json:"foreign_keys"`
+}
+
+// ColumnInfo описывает колонку таблицы (PRAGMA table_info).
+type ColumnInfo struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	NotNull bool   `json:"not_null"`
+	// Default - выражение значения по умолчанию как в DDL, nil если не задано
+	Default *string `json:"default"`
+	// PK - позиция колонки в первичном ключе, начиная с 1 (0 - не входит в ключ)
+	PK int `json:"pk"`
+}
+
+// IndexInfo описывает индекс таблицы (PRAGMA index_list и index_info).
+type IndexInfo struct {
+	Name   string `json:"name"`
+	Unique bool   `json:"unique"`
+	// Origin - источник индекса: "c" (CREATE INDEX), "u" (UNIQUE) или "pk" (PRIMARY KEY)
+	Origin  string `json:"origin"`
+	Partial bool   `json:"partial"`
+	// Columns - колонки индекса по порядку, выражения обозначаются как "<expr>"
+	Columns []string `json:"columns"`
+}
+
+// ForeignKeyInfo описывает внешний ключ (PRAGMA foreign_key_list).
+type ForeignKeyInfo struct {
+	Table    string   `json:"table"`
+	From     []string `json:"from"`
+	To       []string `json:"to"`
+	OnUpdate string   `json:"on_update"`
+	OnDelete string   `json:"on_delete"`
+}
+
+// SchemaDiff содержит различия между ожидаемой и фактической схемой.
+// Колонки обозначаются как "таблица.колонка", индексы - по имени.
+type SchemaDiff struct {
+	// MissingTables - таблицы, которые есть только в ожидаемой схеме
+	MissingTables []string `json:"missing_tables,omitempty"`
+	// ExtraTables - таблицы, которые есть только в фактической схеме
+	ExtraTables []string `json:"extra_tables,omitempty"`
+	// MissingColumns - колонки общих таблиц, которые есть только в ожидаемой схеме
+	MissingColumns []string `json:"missing_columns,omitempty"`
+	// ExtraColumns - колонки общих таблиц, которые есть только в фактической схеме
+	ExtraColumns []string `json:"extra_columns,omitempty"`
+	// ChangedColumns - колонки общих таблиц с разным типом, NOT NULL, значением по умолчанию или PK
+	ChangedColumns []string `json:"changed_columns,omitempty"`
+	// MissingIndexes - индексы общих таблиц, которые есть только в ожидаемой схеме
+	MissingIndexes []string `json:"missing_indexes,omitempty"`
+	// ExtraIndexes - индексы общих таблиц, которые есть только в фактической схеме
+	ExtraIndexes []string `json:"extra_indexes,omitempty"`
+}
+
+// Empty возвращает true, если схемы не различаются.
+func (d SchemaDiff) Empty() bool {
+	return len(d.MissingTables) == 0 && len(d.ExtraTables) == 0 &&
+		len(d.MissingColumns) == 0 && len(d.ExtraColumns) == 0 && len(d.ChangedColumns) == 0 &&
+		len(d.MissingIndexes) == 0 && len(d.ExtraIndexes) == 0
+}
+
+// Schema читает структуру пользовательских таблиц из sqlite_master и PRAGMA table_info,
+// index_list, index_info и foreign_key_list. Служебные таблицы SQLite, таблица
+// golang-migrate и теневые таблицы виртуальных таблиц пропускаются.
+// Работает и с *sql.DB, и внутри транзакции.
+func Schema(ctx context.Context, q Querier) (SchemaInfo, error) {
+	names, err := schemaTables(ctx, q)
+	if err != nil {
+		return SchemaInfo{}, err
+	}
+
+	info := SchemaInfo{Tables: make([]TableInfo, 0, len(names))}
+	for _, name := range names {
+		table, err := schemaTable(ctx, q, name)
+		if err != nil {
+			return SchemaInfo{}, err
+		}
+		info.Tables = append(info.Tables, table)
+	}
+	return info, nil
+}
+
+// DiffSchema сравнивает ожидаемую схему expected (например, полученную применением миграций
+// к пустой базе) с фактической actual. Колонки и индексы сравниваются только у общих таблиц.
+func DiffSchema(expected, actual SchemaInfo) SchemaDiff {
+	var diff SchemaDiff
+	expectedTables := tablesByName(expected)
+	actualTables := tablesByName(actual)
+
+	for _, name := range slices.Sorted(maps.Keys(expectedTables)) {
+		want := expectedTables[name]
+		got, ok := actualTables[name]
+		if !ok {
+			diff.MissingTables = append(diff.MissingTables, name)
+			continue
+		}
+
+		wantColumns := columnsByName(want)
+		gotColumns := columnsByName(got)
+		for _, column := range want.Columns {
+			gotColumn, ok := gotColumns[column.Name]
+			switch {
+			case !ok:
+				diff.MissingColumns = append(diff.MissingColumns, name+"."+column.Name)
+			case !sameColumn(column, gotColumn):
+				diff.ChangedColumns = append(diff.ChangedColumns, name+"."+column.Name)
+			}
+		}
+		for _, column := range got.Columns {
+			if _, ok := wantColumns[column.Name]; !ok {
+				diff.ExtraColumns = append(diff.ExtraColumns, name+"."+column.Name)
+			}
+		}
+
+		diff.MissingIndexes = append(diff.MissingIndexes, missingIndexes(want, got)...)
+		diff.ExtraIndexes = append(diff.ExtraIndexes, missingIndexes(got, want)...)
+	}
+	for _, name := range slices.Sorted(maps.Keys(actualTables)) {
+		if _, ok := expectedTables[name]; !ok {
+			diff.ExtraTables = append(diff.ExtraTables, name)
+		}
+	}
+	return diff
+}
+
+// schemaTables возвращает отсортированные имена пользовательских таблиц.
+func schemaTables(ctx context.Context, q Querier) ([]string, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT name, sql FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != ?
+		ORDER BY name`,
+		migratesqlite.DefaultMigrationsTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tables: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var names []string
+	var virtualTables []string
+	for rows.Next() {
+		var name string
+		var stmt sql.NullString
+		if err := rows.Scan(&name, &stmt); err != nil {
+			return nil, fmt.Errorf("failed to read tables: %w", err)
+		}
+		if strings.HasPrefix(strings.ToUpper(stmt.String), "CREATE VIRTUAL TABLE") {
+			virtualTables = append(virtualTables, name)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tables: %w", err)
+	}
+
+	// Теневые таблицы могут идти в алфавитном порядке раньше своей виртуальной таблицы,
+	// поэтому отфильтровываются после чтения всего списка
+	return slices.DeleteFunc(names, func(name string) bool {
+		return isShadowTable(name, virtualTables)
+	}), nil
+}
+
+// schemaTable читает колонки, индексы и внешние ключи таблицы.
+// Запросы выполняются последовательно: внутри транзакции доступно одно подключение.
+func schemaTable(ctx context.Context, q Querier, name string) (TableInfo, error) {
+	table := TableInfo{
+		Name:        name,
+		Columns:     []ColumnInfo{},
+		Indexes:     []IndexInfo{},
+		ForeignKeys: []ForeignKeyInfo{},
+	}
+
+	err := queryEach(ctx, q, `
+		SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid`,
+		[]any{name}, func(rows *sql.Rows) error {
+			var column ColumnInfo
+			var def sql.NullString
+			if err := rows.Scan(&column.Name, &column.Type, &column.NotNull, &def, &column.PK); err != nil {
+				return err
+			}
+			if def.Valid {
+				column.Default = &def.String
+			}
+			table.Columns = append(table.Columns, column)
+			return nil
+		})
+	if err != nil {
+		return TableInfo{}, fmt.Errorf("failed to read columns of %s: %w", name, err)
+	}
+
+	err = queryEach(ctx, q, `
+		SELECT name, "unique", origin, partial FROM pragma_index_list(?) ORDER BY name`,
+		[]any{name}, func(rows *sql.Rows) error {
+			var index IndexInfo
+			if err := rows.Scan(&index.Name, &index.Unique, &index.Origin, &index.Partial); err != nil {
+				return err
+			}
+			table.Indexes = append(table.Indexes, index)
+			return nil
+		})
+	if err != nil {
+		return TableInfo{}, fmt.Errorf("failed to read indexes of %s: %w", name, err)
+	}
+	for i := range table.Indexes {
+		index := &table.Indexes[i]
+		index.Columns = []string{}
+		err := queryEach(ctx, q, `SELECT name FROM pragma_index_info(?) ORDER BY seqno`,
+			[]any{index.Name}, func(rows *sql.Rows) error {
+				var column sql.NullString
+				if err := rows.Scan(&column); err != nil {
+					return err
+				}
+				if !column.Valid {
+					column.String = "<expr>"
+				}
+				index.Columns = append(index.Columns, column.String)
+				return nil
+			})
+		if err != nil {
+			return TableInfo{}, fmt.Errorf("failed to read index %s: %w", index.Name, err)
+		}
+	}
+
+	// Составной ключ занимает несколько строк с общим id, по одной на колонку
+	keys := make(map[int]*ForeignKeyInfo)
+	err = queryEach(ctx, q, `
+		SELECT id, "table", "from", "to", on_update, on_delete FROM pragma_foreign_key_list(?) ORDER BY id, seq`,
+		[]any{name}, func(rows *sql.Rows) error {
+			var id int
+			var target, from, onUpdate, onDelete string
+			var to sql.NullString
+			if err := rows.Scan(&id, &target, &from, &to, &onUpdate, &onDelete); err != nil {
+				return err
+			}
+			key, ok := keys[id]
+			if !ok {
+				key = &ForeignKeyInfo{Table: target, From: []string{}, To: []string{}, OnUpdate: onUpdate, OnDelete: onDelete}
+				keys[id] = key
+			}
+			key.From = append(key.From, from)
+			// NULL означает ссылку на первичный ключ целевой таблицы
+			key.To = append(key.To, to.String)
+			return nil
+		})
+	if err != nil {
+		return TableInfo{}, fmt.Errorf("failed to read foreign keys of %s: %w", name, err)
+	}
+	for _, key := range keys {
+		table.ForeignKeys = append(table.ForeignKeys, *key)
+	}
+	slices.SortFunc(table.ForeignKeys, func(a, b ForeignKeyInfo) int {
+		return cmp.Or(
+			slices.Compare(a.From, b.From),
+			strings.Compare(a.Table, b.Table),
+			slices.Compare(a.To, b.To),
+		)
+	})
+	return table, nil
+}
+
+// queryEach выполняет запрос и вызывает fn для каждой строки результата.
+func queryEach(ctx context.Context, q Querier, query string, args []any, fn func(rows *sql.Rows) error) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// tablesByName индексирует таблицы схемы по имени.
+func tablesByName(info SchemaInfo) map[string]TableInfo {
+	tables := make(map[string]TableInfo, len(info.Tables))
+	for _, table := range info.Tables {
+		tables[table.Name] = table
+	}
+	return tables
+}
+
+// columnsByName индексирует колонки таблицы по имени.
+func columnsByName(table TableInfo) map[string]ColumnInfo {
+	columns := make(map[string]ColumnInfo, len(table.Columns))
+	for _, column := range table.Columns {
+		columns[column.Name] = column
+	}
+	return columns
+}
+
+// sameColumn сравнивает определения колонок. Тип сравнивается без учёта регистра.
+func sameColumn(a, b ColumnInfo) bool {
+	sameDefault := (a.Default == nil) == (b.Default == nil) &&
+		(a.Default == nil || *a.Default == *b.Default)
+	return strings.EqualFold(a.Type, b.Type) && a.NotNull == b.NotNull && a.PK == b.PK && sameDefault
+}
+
+// missingIndexes возвращает имена индексов таблицы a, которых нет в таблице b.
+func missingIndexes(a, b TableInfo) []string {
+	var missing []string
+	for _, index := range a.Indexes {
+		if !slices.ContainsFunc(b.Indexes, func(other IndexInfo) bool { return other.Name == index.Name }) {
+			missing = append(missing, index.Name)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,156 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func schemaMigrationsFS() fstest.MapFS {
+	return fstest.MapFS{
+		"migrations/001_users.up.sql": {Data: []byte(`
+			CREATE TABLE users (
+				id INTEGER PRIMARY KEY,
+				email TEXT NOT NULL UNIQUE,
+				name TEXT,
+				created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);`)},
+		"migrations/001_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"migrations/002_orders.up.sql": {Data: []byte(`
+			CREATE TABLE orders (
+				id INTEGER PRIMARY KEY,
+				user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+				status TEXT NOT NULL DEFAULT 'new'
+			);
+			CREATE INDEX idx_orders_user_status ON orders(user_id, status);
+			CREATE INDEX idx_orders_lower_status ON orders(lower(status)) WHERE status != 'new';
+			CREATE VIRTUAL TABLE notes USING fts5(body);`)},
+		"migrations/002_orders.down.sql": {Data: []byte("DROP TABLE notes; DROP TABLE orders;")},
+	}
+}
+
+func TestSchema(t *testing.T) {
+	ctx := context.Background()
+	testDB := NewTestDBFile(t)
+	testDB.ApplyTestMigrationsFS(t, schemaMigrationsFS(), "migrations")
+
+	info, err := Schema(ctx, testDB.DB)
+	require.NoError(t, err)
+
+	// Таблица golang-migrate и теневые таблицы FTS пропускаются, имена отсортированы
+	names := make([]string, 0, len(info.Tables))
+	for _, table := range info.Tables {
+		names = append(names, table.Name)
+	}
+	assert.Equal(t, []string{"notes", "orders", "users"}, names)
+
+	def := func(s string) *string { return &s }
+	users := info.Tables[2]
+	assert.Equal(t, []ColumnInfo{
+		{Name: "id", Type: "INTEGER", PK: 1},
+		{Name: "email", Type: "TEXT", NotNull: true},
+		{Name: "name", Type: "TEXT"},
+		{Name: "created_at", Type: "DATETIME", NotNull: true, Default: def("CURRENT_TIMESTAMP")},
+	}, users.Columns)
+	assert.Equal(t, []IndexInfo{
+		{Name: "sqlite_autoindex_users_1", Unique: true, Origin: "u", Columns: []string{"email"}},
+	}, users.Indexes)
+	assert.Empty(t, users.ForeignKeys)
+
+	orders := info.Tables[1]
+	assert.Equal(t, ColumnInfo{Name: "status", Type: "TEXT", NotNull: true, Default: def("'new'")}, orders.Columns[2])
+	assert.Equal(t, []IndexInfo{
+		{Name: "idx_orders_lower_status", Origin: "c", Partial: true, Columns: []string{"<expr>"}},
+		{Name: "idx_orders_user_status", Origin: "c", Columns: []string{"user_id", "status"}},
+	}, orders.Indexes)
+	assert.Equal(t, []ForeignKeyInfo{
+		{Table: "users", From: []string{"user_id"}, To: []string{"id"}, OnUpdate: "NO ACTION", OnDelete: "CASCADE"},
+	}, orders.ForeignKeys)
+
+	t.Run("в транзакции", func(t *testing.T) {
+		tx, err := testDB.DB.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		defer func() { _ = tx.Rollback() }()
+
+		txInfo, err := Schema(ctx, tx)
+		require.NoError(t, err)
+		assert.Equal(t, info, txInfo)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(info)
+		require.NoError(t, err)
+		again, err := json.Marshal(info)
+		require.NoError(t, err)
+		assert.Equal(t, string(data), string(again))
+		// Пустые списки сериализуются как [], а не null
+		assert.Contains(t, string(data), `"name":"users","columns":[`)
+		assert.Contains(t, string(data), `"foreign_keys":[]`)
+
+		var decoded SchemaInfo
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, info, decoded)
+	})
+}
+
+func TestSchema_Empty(t *testing.T) {
+	testDB := NewTestDBInMemory(t)
+
+	info, err := Schema(context.Background(), testDB.DB)
+	require.NoError(t, err)
+	assert.Empty(t, info.Tables)
+
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tables":[]}`, string(data))
+}
+
+func TestDiffSchema(t *testing.T) {
+	ctx := context.Background()
+	expectedDB := NewTestDBFile(t)
+	expectedDB.ApplyTestMigrationsFS(t, schemaMigrationsFS(), "migrations")
+	expected, err := Schema(ctx, expectedDB.DB)
+	require.NoError(t, err)
+
+	// Копия схемы с ручными изменениями
+	actualDB := NewTestDBFile(t)
+	actualDB.ApplyTestMigrationsFS(t, schemaMigrationsFS(), "migrations")
+	actualDB.MustSeedData(t,
+		"DROP TABLE notes",
+		"CREATE TABLE audit (id INTEGER PRIMARY KEY)",
+		"ALTER TABLE users DROP COLUMN name",
+		"ALTER TABLE users ADD COLUMN phone TEXT",
+		"DROP INDEX idx_orders_user_status",
+		"CREATE INDEX idx_orders_status ON orders(status)",
+	)
+	actual, err := Schema(ctx, actualDB.DB)
+	require.NoError(t, err)
+
+	assert.True(t, DiffSchema(expected, expected).Empty())
+
+	diff := DiffSchema(expected, actual)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, SchemaDiff{
+		MissingTables:  []string{"notes"},
+		ExtraTables:    []string{"audit"},
+		MissingColumns: []string{"users.name"},
+		ExtraColumns:   []string{"users.phone"},
+		MissingIndexes: []string{"idx_orders_user_status"},
+		ExtraIndexes:   []string{"idx_orders_status"},
+	}, diff)
+
+	// Изменённое определение колонки
+	changed := DiffSchema(expected, func() SchemaInfo {
+		data, err := json.Marshal(expected)
+		require.NoError(t, err)
+		var copied SchemaInfo
+		require.NoError(t, json.Unmarshal(data, &copied))
+		copied.Tables[1].Columns[2].NotNull = false
+		return copied
+	}())
+	assert.Equal(t, SchemaDiff{ChangedColumns: []string{"orders.status"}}, changed)
+}
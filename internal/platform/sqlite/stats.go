@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DefaultMaxWALSize - размер WAL файла, выше которого IsHealthy считает базу нездоровой:
+// такой WAL означает, что checkpoint не успевает за записью.
+const DefaultMaxWALSize = 64 << 20
+
+// walHeaderSize и walFrameHeaderSize - размеры заголовков WAL файла и кадра.
+const (
+	walHeaderSize      = 32
+	walFrameHeaderSize = 24
+)
+
+// DBStats содержит статистику пула подключений и состояния SQLite.
+// Поля пула совпадают с pg.DBStats, чтобы обе базы отображались одинаково.
+type DBStats struct {
+	MaxConns        int32         // Максимальное количество подключений (0 - без ограничения)
+	OpenConns       int32         // Текущее количество открытых подключений
+	InUse           int32         // Количество подключений в использовании
+	Idle            int32         // Количество простаивающих подключений
+	WaitCount       int64         // Количество ожиданий подключения
+	WaitDuration    time.Duration // Общее время ожидания
+	MaxIdleDestroys int64         // Количество закрытых idle подключений
+	MaxLifeCloses   int64         // Количество закрытых подключений по lifetime
+
+	PageCount     int64   // Общее количество страниц
+	PageSize      int64   // Размер страницы в байтах
+	FreelistCount int64   // Количество свободных страниц
+	FreelistRatio float64 // Доля свободных страниц
+	CacheSize     int64   // PRAGMA cache_size: страницы, либо KiB при отрицательном значении
+	JournalMode   string  // Режим журнала (wal, delete, memory, ...)
+	WALSize       int64   // Размер WAL файла в байтах (-1 для in-memory БД)
+	WALPages      int64   // Количество кадров в WAL файле (-1 для in-memory БД)
+}
+
+// Stats собирает статистику пула и показатели SQLite для health endpoint.
+// Использует только читающие PRAGMA и размер WAL файла, не берёт блокировку записи
+// и не выполняет checkpoint, поэтому подходит для частого опроса.
+func Stats(ctx context.Context, db *sql.DB) (DBStats, error) {
+	stats := DBStats{WALSize: -1, WALPages: -1}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return stats, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	err = readSQLiteStats(ctx, conn, &stats)
+	_ = conn.Close()
+	if err != nil {
+		return stats, err
+	}
+
+	// Статистика пула снимается после возврата соединения, чтобы не учитывать сам запрос
+	pool := db.Stats()
+	stats.MaxConns = int32(pool.MaxOpenConnections)
+	stats.OpenConns = int32(pool.OpenConnections)
+	stats.InUse = int32(pool.InUse)
+	stats.Idle = int32(pool.Idle)
+	stats.WaitCount = pool.WaitCount
+	stats.WaitDuration = pool.WaitDuration
+	stats.MaxIdleDestroys = pool.MaxIdleClosed
+	stats.MaxLifeCloses = pool.MaxLifetimeClosed
+	return stats, nil
+}
+
+// readSQLiteStats заполняет показатели SQLite из PRAGMA и размера WAL файла.
+func readSQLiteStats(ctx context.Context, conn *sql.Conn, stats *DBStats) error {
+	pragmas := []struct {
+		name string
+		dest any
+	}{
+		{"page_count", &stats.PageCount},
+		{"page_size", &stats.PageSize},
+		{"freelist_count", &stats.FreelistCount},
+		{"cache_size", &stats.CacheSize},
+		{"journal_mode", &stats.JournalMode},
+	}
+	for _, p := range pragmas {
+		if err := conn.QueryRowContext(ctx, "PRAGMA "+p.name).Scan(p.dest); err != nil {
+			return fmt.Errorf("failed to read %s: %w", p.name, err)
+		}
+	}
+	if stats.PageCount > 0 {
+		stats.FreelistRatio = float64(stats.FreelistCount) / float64(stats.PageCount)
+	}
+
+	var path string
+	if err := conn.QueryRowContext(ctx, "SELECT file FROM pragma_database_list WHERE name = 'main'").Scan(&path); err != nil {
+		return fmt.Errorf("failed to read database path: %w", err)
+	}
+	if path == "" {
+		return nil // in-memory БД
+	}
+	size, err := walFileSize(path)
+	if err != nil {
+		return err
+	}
+	stats.WALSize = size
+	stats.WALPages = 0
+	if size > walHeaderSize && stats.PageSize > 0 {
+		stats.WALPages = (size - walHeaderSize) / (walFrameHeaderSize + stats.PageSize)
+	}
+	return nil
+}
+
+// IsHealthy проверяет статистику, полученную Stats. База считается нездоровой, если:
+//   - занято более 90% подключений пула (как в pg.IsHealthy; не проверяется без ограничения пула)
+//   - доля свободных страниц превышает DefaultMaxFreelistRatio
+//   - WAL файл больше DefaultMaxWALSize
+func IsHealthy(stats DBStats) bool {
+	if stats.MaxConns > 0 {
+		utilizationPercent := float64(stats.InUse) / float64(stats.MaxConns) * 100
+		if utilizationPercent > 90 {
+			return false // Слишком высокая нагрузка
+		}
+	}
+
+	if stats.FreelistRatio > DefaultMaxFreelistRatio {
+		return false // База сильно "раздута"
+	}
+
+	if stats.WALSize > DefaultMaxWALSize {
+		return false // Checkpoint не успевает за записью
+	}
+
+	return true
+}
@@ -0,0 +1,98 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	ctx := context.Background()
+	opts := DefaultDBOptions()
+	_, testDB := newMaintenanceTestDB(t, opts)
+	testDB.MustSeedData(t, "CREATE TABLE items (id INTEGER PRIMARY KEY, data TEXT)")
+	for i := 0; i < 50; i++ {
+		testDB.Exec(t, "INSERT INTO items (data) VALUES (?)", "payload")
+	}
+
+	stats, err := Stats(ctx, testDB.DB)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(opts.MaxOpenConns), stats.MaxConns)
+	assert.Positive(t, stats.OpenConns)
+	assert.Zero(t, stats.InUse, "connection of Stats itself is not counted")
+	assert.Equal(t, stats.OpenConns, stats.Idle)
+
+	assert.Positive(t, stats.PageCount)
+	assert.Positive(t, stats.PageSize)
+	assert.NotZero(t, stats.CacheSize)
+	assert.Equal(t, "wal", stats.JournalMode)
+	assert.Positive(t, stats.WALSize)
+	assert.Positive(t, stats.WALPages)
+	assert.LessOrEqual(t, stats.WALPages*stats.PageSize, stats.WALSize)
+	assert.True(t, IsHealthy(stats))
+}
+
+func TestStats_InMemory(t *testing.T) {
+	testDB := NewTestDBInMemory(t)
+
+	stats, err := Stats(context.Background(), testDB.DB)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), stats.MaxConns)
+	assert.Equal(t, "memory", stats.JournalMode)
+	assert.Equal(t, int64(-1), stats.WALSize)
+	assert.Equal(t, int64(-1), stats.WALPages)
+	assert.True(t, IsHealthy(stats), "single idle connection is healthy")
+}
+
+func TestStats_NoWriteLock(t *testing.T) {
+	ctx := context.Background()
+	_, testDB := newMaintenanceTestDB(t, DefaultDBOptions())
+	testDB.MustSeedData(t, "CREATE TABLE items (id INTEGER PRIMARY KEY)")
+
+	// Открытая пишущая транзакция не должна блокировать Stats
+	conn, err := testDB.DB.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "INSERT INTO items (id) VALUES (1)")
+	require.NoError(t, err)
+	defer func() {
+		_, _ = conn.ExecContext(ctx, "ROLLBACK")
+	}()
+
+	statsCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	stats, err := Stats(statsCtx, testDB.DB)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), stats.InUse)
+}
+
+func TestIsHealthy(t *testing.T) {
+	healthy := DBStats{MaxConns: 10, OpenConns: 2, Idle: 2, PageCount: 100, FreelistRatio: 0.1, WALSize: 1 << 20}
+
+	tests := []struct {
+		name     string
+		mutate   func(s *DBStats)
+		expected bool
+	}{
+		{name: "здоровая база", mutate: func(s *DBStats) {}, expected: true},
+		{name: "пул без ограничения", mutate: func(s *DBStats) { s.MaxConns = 0; s.InUse = 100 }, expected: true},
+		{name: "90% пула занято", mutate: func(s *DBStats) { s.InUse = 9 }, expected: true},
+		{name: "пул почти исчерпан", mutate: func(s *DBStats) { s.InUse = 10 }, expected: false},
+		{name: "раздутая база", mutate: func(s *DBStats) { s.FreelistRatio = 0.5 }, expected: false},
+		{name: "большой WAL", mutate: func(s *DBStats) { s.WALSize = DefaultMaxWALSize + 1 }, expected: false},
+		{name: "in-memory", mutate: func(s *DBStats) { s.WALSize = -1 }, expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := healthy
+			tt.mutate(&stats)
+			assert.Equal(t, tt.expected, IsHealthy(stats))
+		})
+	}
+}
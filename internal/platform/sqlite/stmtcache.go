@@ -0,0 +1,221 @@
+package sqlite
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// StmtCacheStats содержит статистику кэша подготовленных выражений.
+type StmtCacheStats struct {
+	// Hits - количество запросов, выполненных через уже подготовленное выражение
+	Hits int64
+	// Misses - количество запросов, не найденных в кэше
+	Misses int64
+	// Size - текущее количество выражений в кэше
+	Size int
+}
+
+// stmtCache кэширует *sql.Stmt, подготовленные на уровне БД, по тексту запроса
+// с вытеснением давно не использованных выражений (LRU).
+type stmtCache struct {
+	db       *sql.DB
+	capacity int
+
+	mu     sync.Mutex
+	ll     *list.List
+	items  map[string]*list.Element
+	hits   int64
+	misses int64
+	closed bool
+}
+
+// stmtEntry - выражение в кэше со счётчиком активных использований.
+// Вытесненное выражение закрывается, когда его перестают использовать.
+type stmtEntry struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// newStmtCache создаёт кэш на capacity выражений.
+func newStmtCache(db *sql.DB, capacity int) *stmtCache {
+	return &stmtCache{
+		db:       db,
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// acquire возвращает подготовленное выражение для query, подготавливая его при промахе,
+// если prepare == true. После использования выражение нужно вернуть через release.
+// Возвращает nil без ошибки, если кэш закрыт или выражения нет, а prepare == false.
+func (c *stmtCache) acquire(ctx context.Context, query string, prepare bool) (*stmtEntry, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, nil
+	}
+	if el, ok := c.items[query]; ok {
+		c.hits++
+		c.ll.MoveToFront(el)
+		e := el.Value.(*stmtEntry)
+		e.refs++
+		c.mu.Unlock()
+		return e, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	if !prepare {
+		return nil, nil
+	}
+
+	// Подготавливаем вне блокировки: ожидание свободного соединения под мьютексом
+	// могло бы заблокировать транзакции, которым нужен кэш
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		_ = stmt.Close()
+		return nil, nil
+	}
+	// Выражение могли подготовить параллельно - используем уже закэшированное
+	if el, ok := c.items[query]; ok {
+		_ = stmt.Close()
+		c.ll.MoveToFront(el)
+		e := el.Value.(*stmtEntry)
+		e.refs++
+		return e, nil
+	}
+
+	e := &stmtEntry{query: query, stmt: stmt, refs: 1}
+	c.items[query] = c.ll.PushFront(e)
+	for c.ll.Len() > c.capacity {
+		c.evict(c.ll.Back())
+	}
+	return e, nil
+}
+
+// release освобождает выражение, полученное через acquire.
+func (c *stmtCache) release(e *stmtEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e.refs--
+	if e.evicted && e.refs == 0 {
+		_ = e.stmt.Close()
+	}
+}
+
+// evict удаляет выражение из кэша. Вызывается под c.mu.
+func (c *stmtCache) evict(el *list.Element) {
+	e := el.Value.(*stmtEntry)
+	c.ll.Remove(el)
+	delete(c.items, e.query)
+	e.evicted = true
+	if e.refs == 0 {
+		_ = e.stmt.Close()
+	}
+}
+
+// stats возвращает статистику кэша.
+func (c *stmtCache) stats() StmtCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return StmtCacheStats{Hits: c.hits, Misses: c.misses, Size: c.ll.Len()}
+}
+
+// close закрывает все выражения. Последующие запросы выполняются без кэша.
+func (c *stmtCache) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	var errs []error
+	for el := c.ll.Front(); el != nil; el = c.ll.Front() {
+		e := el.Value.(*stmtEntry)
+		c.ll.Remove(el)
+		delete(c.items, e.query)
+		e.evicted = true
+		if e.refs == 0 {
+			errs = append(errs, e.stmt.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cachedQuerier выполняет запросы через кэш подготовленных выражений.
+// Внутри транзакции закэшированное выражение привязывается к ней через tx.StmtContext.
+// Промах внутри *sql.Tx выполняется напрямую без подготовки: подготовка на уровне БД
+// потребовала бы второго соединения, которого может не быть (MaxOpenConns = 1).
+type cachedQuerier struct {
+	cache *stmtCache
+	base  Querier
+	tx    *sql.Tx // nil вне *sql.Tx транзакции
+}
+
+// stmt возвращает выражение для выполнения запроса и функцию его освобождения.
+// Возвращает nil, если запрос нужно выполнить напрямую через base.
+func (q *cachedQuerier) stmt(ctx context.Context, query string) (*sql.Stmt, func()) {
+	e, err := q.cache.acquire(ctx, query, q.tx == nil)
+	if err != nil || e == nil {
+		// Ошибку подготовки вернёт прямой вызов
+		return nil, nil
+	}
+	release := func() { q.cache.release(e) }
+	if q.tx != nil {
+		return q.tx.StmtContext(ctx, e.stmt), release
+	}
+	return e.stmt, release
+}
+
+func (q *cachedQuerier) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	stmt, release := q.stmt(ctx, query)
+	if stmt == nil {
+		return q.base.ExecContext(ctx, query, args...)
+	}
+	defer release()
+	return stmt.ExecContext(ctx, args...)
+}
+
+func (q *cachedQuerier) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	stmt, release := q.stmt(ctx, query)
+	if stmt == nil {
+		return q.base.QueryContext(ctx, query, args...)
+	}
+	// Открытые *sql.Rows удерживают выражение, поэтому release безопасен сразу после запроса
+	defer release()
+	return stmt.QueryContext(ctx, args...)
+}
+
+func (q *cachedQuerier) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	stmt, release := q.stmt(ctx, query)
+	if stmt == nil {
+		return q.base.QueryRowContext(ctx, query, args...)
+	}
+	defer release()
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+func (q *cachedQuerier) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return q.base.PrepareContext(ctx, query)
+}
+
+// CacheStats возвращает статистику кэша подготовленных выражений.
+// Если кэш отключён (DBOptions.StmtCacheSize == 0), возвращает нулевые значения.
+func (r *TxRunner) CacheStats() StmtCacheStats {
+	if r.stmtCache == nil {
+		return StmtCacheStats{}
+	}
+	return r.stmtCache.stats()
+}
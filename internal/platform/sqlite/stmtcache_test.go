@@ -0,0 +1,182 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCachedRunner создаёт in-memory БД с таблицей test и TxRunner с кэшем выражений.
+func newCachedRunner(t testing.TB, size int) (*sql.DB, *TxRunner) {
+	t.Helper()
+	ctx := context.Background()
+	db, err := NewInMemoryDB(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.ExecContext(ctx, "CREATE TABLE test (id INTEGER PRIMARY KEY, value TEXT)")
+	require.NoError(t, err)
+
+	opts := DefaultDBOptions()
+	opts.StmtCacheSize = size
+	runner := NewTxRunnerWithOptions(db, opts)
+	t.Cleanup(func() { _ = runner.Close() })
+	return db, runner
+}
+
+func TestStmtCache_HitsAndMisses(t *testing.T) {
+	ctx := context.Background()
+	_, runner := newCachedRunner(t, 4)
+
+	q := runner.GetQuerier(ctx)
+	for i := range 3 {
+		_, err := q.ExecContext(ctx, "INSERT INTO test (id, value) VALUES (?, ?)", i, "v")
+		require.NoError(t, err)
+	}
+
+	var count int
+	require.NoError(t, q.QueryRowContext(ctx, "SELECT COUNT(*) FROM test").Scan(&count))
+	assert.Equal(t, 3, count)
+
+	rows, err := q.QueryContext(ctx, "SELECT id FROM test ORDER BY id")
+	require.NoError(t, err)
+	var ids []int
+	for rows.Next() {
+		var id int
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	require.NoError(t, rows.Close())
+	assert.Equal(t, []int{0, 1, 2}, ids)
+
+	assert.Equal(t, StmtCacheStats{Hits: 2, Misses: 3, Size: 3}, runner.CacheStats())
+}
+
+func TestStmtCache_Eviction(t *testing.T) {
+	ctx := context.Background()
+	_, runner := newCachedRunner(t, 2)
+
+	q := runner.GetQuerier(ctx)
+	for i := range 5 {
+		var v int
+		require.NoError(t, q.QueryRowContext(ctx, fmt.Sprintf("SELECT %d", i)).Scan(&v))
+		assert.Equal(t, i, v)
+	}
+
+	stats := runner.CacheStats()
+	assert.Equal(t, 2, stats.Size)
+	assert.Equal(t, int64(5), stats.Misses)
+
+	// Самое старое выражение вытеснено и готовится заново
+	var v int
+	require.NoError(t, q.QueryRowContext(ctx, "SELECT 0").Scan(&v))
+	assert.Equal(t, int64(6), runner.CacheStats().Misses)
+}
+
+func TestStmtCache_WithinTx(t *testing.T) {
+	ctx := context.Background()
+	db, runner := newCachedRunner(t, 4)
+
+	const insert = "INSERT INTO test (id, value) VALUES (?, ?)"
+	_, err := runner.GetQuerier(ctx).ExecContext(ctx, insert, 1, "outside")
+	require.NoError(t, err)
+
+	err = runner.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := runner.GetQuerier(ctx).ExecContext(ctx, insert, 2, "inside")
+		return err
+	})
+	require.NoError(t, err)
+
+	// Откат транзакции отменяет запись, выполненную через закэшированное выражение
+	err = runner.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := runner.GetQuerier(ctx).ExecContext(ctx, insert, 3, "rolled back"); err != nil {
+			return err
+		}
+		return fmt.Errorf("rollback")
+	})
+	require.Error(t, err)
+
+	var count int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM test").Scan(&count))
+	assert.Equal(t, 2, count)
+	assert.Equal(t, int64(2), runner.CacheStats().Hits)
+}
+
+func TestStmtCache_ReadOnlyTxBypassesCache(t *testing.T) {
+	ctx := context.Background()
+	_, runner := newCachedRunner(t, 4)
+
+	err := runner.WithinTxOptions(ctx, TxOptions{ReadOnly: true}, func(ctx context.Context) error {
+		_, err := runner.GetQuerier(ctx).ExecContext(ctx, "INSERT INTO test (id, value) VALUES (1, 'x')")
+		return err
+	})
+	require.ErrorIs(t, err, ErrReadOnlyTx)
+	assert.Equal(t, StmtCacheStats{}, runner.CacheStats())
+}
+
+func TestStmtCache_Close(t *testing.T) {
+	ctx := context.Background()
+	_, runner := newCachedRunner(t, 4)
+
+	q := runner.GetQuerier(ctx)
+	var v int
+	require.NoError(t, q.QueryRowContext(ctx, "SELECT 1").Scan(&v))
+	require.NoError(t, runner.Close())
+	assert.Equal(t, 0, runner.CacheStats().Size)
+
+	// После закрытия запросы выполняются напрямую
+	require.NoError(t, q.QueryRowContext(ctx, "SELECT 1").Scan(&v))
+	assert.Equal(t, 1, v)
+}
+
+func TestStmtCache_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	_, runner := newCachedRunner(t, 2)
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q := runner.GetQuerier(ctx)
+			for i := range 50 {
+				var v int
+				query := fmt.Sprintf("SELECT %d", (g+i)%4)
+				if err := q.QueryRowContext(ctx, query).Scan(&v); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := runner.CacheStats()
+	assert.Equal(t, int64(400), stats.Hits+stats.Misses)
+	assert.LessOrEqual(t, stats.Size, 2)
+}
+
+func BenchmarkStmtCache_Select(b *testing.B) {
+	for _, size := range []int{0, 16} {
+		b.Run(fmt.Sprintf("cache=%d", size), func(b *testing.B) {
+			ctx := context.Background()
+			_, runner := newCachedRunner(b, size)
+			q := runner.GetQuerier(ctx)
+			_, err := q.ExecContext(ctx, "INSERT INTO test (id, value) VALUES (1, 'v')")
+			require.NoError(b, err)
+
+			b.ResetTimer()
+			for range b.N {
+				var value string
+				if err := q.QueryRowContext(ctx, "SELECT value FROM test WHERE id = ?", 1).Scan(&value); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// templateEntry - шаблонная БД, построенная BuildTemplate для одного URL миграций.
+type templateEntry struct {
+	once sync.Once
+	dir  string
+	path string
+	err  error
+}
+
+var (
+	// templates кэширует шаблоны по URL миграций на время жизни тестового бинарника
+	templates sync.Map // map[string]*templateEntry
+	// templateCopyMu не даёт копировать шаблон во время его checkpoint из другого теста
+	templateCopyMu sync.Mutex
+)
+
+// BuildTemplate один раз на тестовый бинарник создаёт файловую БД с применёнными
+// миграциями migrationsURL (например, "file://migrations/sqlite") и возвращает путь к ней.
+// Повторные вызовы с тем же URL возвращают тот же файл, ошибка построения тоже кэшируется.
+// Шаблон не удаляется очисткой тестов; чтобы удалить его, вызовите CleanupTemplates
+// в TestMain после m.Run.
+func BuildTemplate(tb testing.TB, migrationsURL string) string {
+	tb.Helper()
+
+	v, _ := templates.LoadOrStore(migrationsURL, &templateEntry{})
+	entry := v.(*templateEntry)
+	entry.once.Do(func() {
+		entry.dir, entry.path, entry.err = buildTemplate(migrationsURL)
+	})
+	if entry.err != nil {
+		tb.Fatalf("Failed to build template DB for %s: %v", migrationsURL, entry.err)
+	}
+	return entry.path
+}
+
+// CleanupTemplates удаляет все шаблоны, созданные BuildTemplate.
+func CleanupTemplates() {
+	templates.Range(func(key, v any) bool {
+		entry := v.(*templateEntry)
+		if entry.dir != "" {
+			_ = os.RemoveAll(entry.dir)
+		}
+		templates.Delete(key)
+		return true
+	})
+}
+
+// buildTemplate создаёт шаблон во временной директории и применяет к нему миграции.
+func buildTemplate(migrationsURL string) (string, string, error) {
+	dir, err := os.MkdirTemp("", "sqlite_template_*")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create template dir: %w", err)
+	}
+	path := filepath.Join(dir, "template.sqlite")
+
+	// Создаём файл со стандартными настройками, чтобы копии открывались в том же режиме журнала
+	ctx := context.Background()
+	db, err := NewDB(ctx, path)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return "", "", err
+	}
+	_ = db.Close()
+
+	if err := ApplyMigrations(path, migrationsURL); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", "", err
+	}
+	if err := checkpointTemplate(ctx, path); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", "", err
+	}
+	return dir, path, nil
+}
+
+// NewTestDBFromTemplate копирует шаблонную БД templatePath (например, из BuildTemplate)
+// во временный файл и открывает копию со стандартными настройками. Перед копированием
+// WAL шаблона переносится в основной файл; оставшиеся -wal и -shm копируются вместе с ним.
+// Копия удаляется после завершения теста, шаблон остаётся нетронутым.
+//
+// Копирование файла обычно намного быстрее повторного применения всех миграций в каждом тесте.
+func NewTestDBFromTemplate(tb testing.TB, templatePath string) *TestDB {
+	tb.Helper()
+
+	ctx := context.Background()
+	path, err := copyTemplate(ctx, templatePath)
+	if err != nil {
+		tb.Fatalf("Failed to copy template DB %s: %v", templatePath, err)
+	}
+
+	db, err := NewDB(ctx, path)
+	if err != nil {
+		removeDBFiles(path)
+		tb.Fatalf("Failed to open template copy: %v", err)
+	}
+
+	testDB := &TestDB{
+		DB:       db,
+		Path:     path,
+		TxRunner: NewTxRunner(db),
+	}
+
+	tb.Cleanup(func() {
+		_ = db.Close()
+		removeDBFiles(path)
+	})
+
+	return testDB
+}
+
+// copyTemplate выполняет checkpoint шаблона и копирует его файлы во временный файл.
+func copyTemplate(ctx context.Context, templatePath string) (string, error) {
+	if _, err := os.Stat(templatePath); err != nil {
+		return "", fmt.Errorf("failed to stat template: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp("", "test_db_*.sqlite")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	path := tmpFile.Name()
+	_ = tmpFile.Close()
+
+	templateCopyMu.Lock()
+	defer templateCopyMu.Unlock()
+
+	if err := checkpointTemplate(ctx, templatePath); err != nil {
+		removeDBFiles(path)
+		return "", err
+	}
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		err := copyFile(templatePath+suffix, path+suffix)
+		if suffix != "" && errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			removeDBFiles(path)
+			return "", err
+		}
+	}
+	return path, nil
+}
+
+// checkpointTemplate переносит WAL шаблона в основной файл, не меняя режим журнала.
+func checkpointTemplate(ctx context.Context, path string) error {
+	opts := DefaultDBOptions()
+	opts.WALMode = false
+	opts.MaxOpenConns = 1
+	db, err := NewDBWithOptions(ctx, path, opts)
+	if err != nil {
+		return fmt.Errorf("failed to open template: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		return fmt.Errorf("failed to checkpoint template: %w", err)
+	}
+	return nil
+}
+
+// copyFile копирует содержимое src в dst, перезаписывая dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", dst, err)
+	}
+	return nil
+}
+
+// removeDBFiles удаляет файл БД вместе с -wal и -shm.
+func removeDBFiles(path string) {
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		_ = os.Remove(path + suffix)
+	}
+}
@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTemplateMigrations создаёт n миграций, каждая добавляет таблицу с индексом.
+func writeTemplateMigrations(tb testing.TB, n int) string {
+	tb.Helper()
+
+	dir := tb.TempDir()
+	for i := 1; i <= n; i++ {
+		up := fmt.Sprintf(`CREATE TABLE table_%d (id INTEGER PRIMARY KEY, name TEXT NOT NULL, created_at TEXT);
+CREATE INDEX idx_table_%d_name ON table_%d(name);`, i, i, i)
+		name := fmt.Sprintf("%03d_create_table_%d.up.sql", i, i)
+		require.NoError(tb, os.WriteFile(filepath.Join(dir, name), []byte(up), 0644))
+	}
+	return "file://" + filepath.ToSlash(dir)
+}
+
+func TestBuildTemplate(t *testing.T) {
+	migrationsURL := writeTemplateMigrations(t, 3)
+	t.Cleanup(CleanupTemplates)
+
+	template := BuildTemplate(t, migrationsURL)
+	assert.Equal(t, template, BuildTemplate(t, migrationsURL), "шаблон строится один раз на URL")
+
+	version, dirty, err := GetMigrationVersion(template, migrationsURL)
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), version)
+	assert.False(t, dirty)
+	_, err = os.Stat(template + "-wal")
+	assert.True(t, os.IsNotExist(err) || fileSize(t, template+"-wal") == 0, "WAL шаблона перенесён в основной файл")
+
+	var copyPath string
+	t.Run("copy", func(t *testing.T) {
+		first := NewTestDBFromTemplate(t, template)
+		second := NewTestDBFromTemplate(t, template)
+		copyPath = first.Path
+
+		assert.NotEqual(t, template, first.Path)
+		for i := 1; i <= 3; i++ {
+			assert.True(t, first.TableExists(t, fmt.Sprintf("table_%d", i)))
+		}
+
+		first.Exec(t, "INSERT INTO table_1 (name) VALUES ('alice')")
+		assert.Equal(t, 1, first.CountRows(t, "table_1"))
+		assert.Zero(t, second.CountRows(t, "table_1"), "копии независимы")
+	})
+
+	_, err = os.Stat(copyPath)
+	assert.True(t, os.IsNotExist(err), "копия удаляется после теста")
+	_, err = os.Stat(template)
+	assert.NoError(t, err, "шаблон остаётся")
+
+	fresh := NewTestDBFromTemplate(t, template)
+	assert.Zero(t, fresh.CountRows(t, "table_1"), "изменения копий не попадают в шаблон")
+}
+
+func TestBuildTemplate_CleanupTemplates(t *testing.T) {
+	migrationsURL := writeTemplateMigrations(t, 1)
+
+	template := BuildTemplate(t, migrationsURL)
+	CleanupTemplates()
+	_, err := os.Stat(template)
+	assert.True(t, os.IsNotExist(err))
+
+	rebuilt := BuildTemplate(t, migrationsURL)
+	t.Cleanup(CleanupTemplates)
+	assert.NotEqual(t, template, rebuilt, "после очистки шаблон строится заново")
+	_, err = os.Stat(rebuilt)
+	assert.NoError(t, err)
+}
+
+func TestNewTestDBFromTemplate_WALNotCheckpointed(t *testing.T) {
+	ctx := context.Background()
+	template := filepath.Join(t.TempDir(), "template.sqlite")
+
+	// Шаблон открыт, свежие записи лежат в -wal
+	db, err := NewDB(ctx, template)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.ExecContext(ctx, "CREATE TABLE items (id INTEGER PRIMARY KEY)")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "INSERT INTO items (id) VALUES (1), (2)")
+	require.NoError(t, err)
+
+	testDB := NewTestDBFromTemplate(t, template)
+	assert.Equal(t, 2, testDB.CountRows(t, "items"))
+
+	var mode string
+	require.NoError(t, testDB.QueryRow(t, "PRAGMA journal_mode").Scan(&mode))
+	assert.Equal(t, "wal", mode)
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	return info.Size()
+}
+
+// BenchmarkTestDBSetup сравнивает применение миграций в каждом тесте с копированием шаблона.
+func BenchmarkTestDBSetup(b *testing.B) {
+	migrationsURL := writeTemplateMigrations(b, 20)
+	b.Cleanup(CleanupTemplates)
+
+	b.Run("migrations", func(b *testing.B) {
+		ctx := context.Background()
+		for i := 0; i < b.N; i++ {
+			db, path, err := NewTestDB(ctx)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := ApplyMigrations(path, migrationsURL); err != nil {
+				b.Fatal(err)
+			}
+			_ = CleanupTestDB(db, path)
+		}
+	})
+
+	b.Run("template", func(b *testing.B) {
+		template := BuildTemplate(b, migrationsURL)
+		ctx := context.Background()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			// То же, что NewTestDBFromTemplate, но с очисткой на каждой итерации
+			path, err := copyTemplate(ctx, template)
+			if err != nil {
+				b.Fatal(err)
+			}
+			db, err := NewDB(ctx, path)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_ = db.Close()
+			removeDBFiles(path)
+		}
+	})
+}
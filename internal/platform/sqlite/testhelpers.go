@@ -3,6 +3,10 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -73,6 +77,15 @@ func (tdb *TestDB) ApplyTestMigrations(t *testing.T, migrationsPath string) {
 	}
 }
 
+// ApplyTestMigrationsFS применяет миграции из директории dir файловой системы fsys (например, embed.FS).
+func (tdb *TestDB) ApplyTestMigrationsFS(t *testing.T, fsys fs.FS, dir string) {
+	t.Helper()
+
+	if err := ApplyMigrationsFS(tdb.Path, fsys, dir); err != nil {
+		t.Fatalf("Failed to apply test migrations: %v", err)
+	}
+}
+
 // Exec выполняет SQL команду и проверяет отсутствие ошибок.
 func (tdb *TestDB) Exec(t *testing.T, query string, args ...any) sql.Result {
 	t.Helper()
@@ -177,3 +190,107 @@ func (tdb *TestDB) TableExists(t *testing.T, tableName string) bool {
 	}
 	return count > 0
 }
+
+// seedStatement - SQL фикстуры и её источник для сообщений об ошибках.
+type seedStatement struct {
+	source string
+	query  string
+}
+
+// Seed выполняет SQL-выражения в одной транзакции и падает при первой ошибке,
+// указывая выполненный SQL. При ошибке ни одно выражение не применяется.
+func (tdb *TestDB) Seed(t testing.TB, statements ...string) {
+	t.Helper()
+
+	seed := make([]seedStatement, len(statements))
+	for i, query := range statements {
+		seed[i] = seedStatement{source: fmt.Sprintf("statement %d", i+1), query: query}
+	}
+	tdb.seed(t, seed)
+}
+
+// SeedFromFS выполняет .sql файлы фикстур из fsys, подходящие под glob (см. fs.Glob),
+// в лексическом порядке имён в одной транзакции. Файл может содержать несколько выражений.
+// Падает, если ни один файл не найден.
+func (tdb *TestDB) SeedFromFS(t testing.TB, fsys fs.FS, glob string) {
+	t.Helper()
+
+	names, err := fs.Glob(fsys, glob)
+	if err != nil {
+		t.Fatalf("Failed to list fixtures %q: %v", glob, err)
+	}
+	sort.Strings(names)
+
+	var seed []seedStatement
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			t.Fatalf("Failed to read fixture %s: %v", name, err)
+		}
+		seed = append(seed, seedStatement{source: name, query: string(data)})
+	}
+	if len(seed) == 0 {
+		t.Fatalf("No .sql fixtures match %q", glob)
+	}
+	tdb.seed(t, seed)
+}
+
+// seed выполняет выражения фикстур в одной транзакции.
+func (tdb *TestDB) seed(t testing.TB, statements []seedStatement) {
+	t.Helper()
+
+	ctx := context.Background()
+	tx, err := tdb.DB.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("Failed to begin seed transaction: %v", err)
+	}
+	for _, st := range statements {
+		if _, err := tx.ExecContext(ctx, st.query); err != nil {
+			_ = tx.Rollback()
+			t.Fatalf("Failed to seed %s: %v\nSQL: %s", st.source, err, st.query)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Failed to commit seed transaction: %v", err)
+	}
+}
+
+// MustCount возвращает количество строк таблицы, удовлетворяющих условию where
+// (пустое условие - все строки). Падает при ошибке запроса, указывая выполненный SQL.
+//
+//	n := testDB.MustCount(t, "users", "status = ?", "active")
+func (tdb *TestDB) MustCount(t testing.TB, table string, where string, args ...any) int {
+	t.Helper()
+
+	query := countQuery(table, where)
+	var count int
+	if err := tdb.DB.QueryRowContext(context.Background(), query, args...).Scan(&count); err != nil {
+		t.Fatalf("Failed to count rows: %v\nSQL: %s\nArgs: %v", err, query, args)
+	}
+	return count
+}
+
+// AssertRowExists проверяет, что в таблице есть хотя бы одна строка, удовлетворяющая
+// условию where. В отличие от MustCount, при отсутствии строки тест помечается
+// проваленным, но продолжается. Возвращает результат проверки.
+func (tdb *TestDB) AssertRowExists(t testing.TB, table string, where string, args ...any) bool {
+	t.Helper()
+
+	if tdb.MustCount(t, table, where, args...) == 0 {
+		t.Errorf("Expected row to exist in %s\nSQL: %s\nArgs: %v", table, countQuery(table, where), args)
+		return false
+	}
+	return true
+}
+
+// countQuery строит запрос количества строк таблицы с необязательным условием.
+func countQuery(table, where string) string {
+	query := "SELECT COUNT(*) FROM " + table
+	if where != "" {
+		query += " WHERE " + where
+	}
+	return query
+}
@@ -2,9 +2,13 @@ package sqlite
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sync"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -247,3 +251,127 @@ func TestTestDB_Cleanup(t *testing.T) {
 		// Cleanup будет вызван автоматически в конце теста
 	})
 }
+
+// recordingTB перехватывает ошибки хелперов, чтобы проверить их сообщения.
+type recordingTB struct {
+	testing.TB
+	mu     sync.Mutex
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+	runtime.Goexit()
+}
+
+// run выполняет fn в отдельной горутине, чтобы Fatalf мог её завершить.
+func (r *recordingTB) run(fn func(tb testing.TB)) []string {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(r)
+	}()
+	<-done
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.errors
+}
+
+func TestTestDB_Seed(t *testing.T) {
+	constructors := map[string]func(t *testing.T) *TestDB{
+		"in-memory": NewTestDBInMemory,
+		"file":      NewTestDBFile,
+	}
+	for name, newDB := range constructors {
+		t.Run(name, func(t *testing.T) {
+			testDB := newDB(t)
+			testDB.Seed(t,
+				"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, active INTEGER)",
+				"INSERT INTO users (name, active) VALUES ('alice', 1), ('bob', 0)",
+			)
+
+			assert.Equal(t, 2, testDB.MustCount(t, "users", ""))
+			assert.Equal(t, 1, testDB.MustCount(t, "users", "active = ?", 1))
+			assert.True(t, testDB.AssertRowExists(t, "users", "name = ?", "alice"))
+		})
+	}
+}
+
+func TestTestDB_SeedRollsBackOnError(t *testing.T) {
+	testDB := NewTestDBInMemory(t)
+
+	errs := (&recordingTB{}).run(func(tb testing.TB) {
+		testDB.Seed(tb,
+			"CREATE TABLE users (id INTEGER PRIMARY KEY)",
+			"INSERT INTO missing (id) VALUES (1)",
+		)
+	})
+
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "statement 2")
+	assert.Contains(t, errs[0], "SQL: INSERT INTO missing (id) VALUES (1)")
+	assert.False(t, testDB.TableExists(t, "users"), "seed должен быть атомарным")
+}
+
+func TestTestDB_SeedFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"fixtures/02_users.sql": {Data: []byte(`
+			INSERT INTO users (name) VALUES ('alice');
+			INSERT INTO users (name) VALUES ('bob');
+		`)},
+		"fixtures/01_schema.sql": {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);")},
+		"fixtures/README.md":     {Data: []byte("not sql")},
+	}
+
+	testDB := NewTestDBInMemory(t)
+	testDB.SeedFromFS(t, fsys, "fixtures/*")
+
+	assert.Equal(t, 2, testDB.MustCount(t, "users", ""))
+	testDB.AssertRowExists(t, "users", "name = ?", "bob")
+
+	t.Run("no fixtures", func(t *testing.T) {
+		errs := (&recordingTB{}).run(func(tb testing.TB) {
+			testDB.SeedFromFS(tb, fsys, "missing/*.sql")
+		})
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0], "missing/*.sql")
+	})
+
+	t.Run("broken fixture", func(t *testing.T) {
+		broken := fstest.MapFS{"01_bad.sql": {Data: []byte("INSERT INTO nowhere VALUES (1)")}}
+		errs := (&recordingTB{}).run(func(tb testing.TB) {
+			testDB.SeedFromFS(tb, broken, "*.sql")
+		})
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0], "01_bad.sql")
+		assert.Contains(t, errs[0], "SQL: INSERT INTO nowhere VALUES (1)")
+	})
+}
+
+func TestTestDB_AssertRowExists(t *testing.T) {
+	testDB := NewTestDBInMemory(t)
+	testDB.Seed(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+
+	var ok bool
+	errs := (&recordingTB{}).run(func(tb testing.TB) {
+		ok = testDB.AssertRowExists(tb, "users", "name = ?", "carol")
+	})
+	assert.False(t, ok)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "SQL: SELECT COUNT(*) FROM users WHERE name = ?")
+	assert.Contains(t, errs[0], "carol")
+
+	errs = (&recordingTB{}).run(func(tb testing.TB) {
+		testDB.MustCount(tb, "missing", "")
+	})
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "SQL: SELECT COUNT(*) FROM missing")
+}
@@ -3,11 +3,22 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
+
+	"sttbot/internal/shared"
 )
 
+// ErrReadOnlyTx возвращается при попытке записи внутри read-only транзакции.
+var ErrReadOnlyTx = errors.New("write operation is not allowed in read-only transaction")
+
+// ErrWriteQueueTimeout возвращается, если очередь записи не приняла запрос
+// за DBOptions.WriteQueueMaxWait. Помечается shared.KindTimeout.
+var ErrWriteQueueTimeout = errors.New("write queue is full: request not accepted within WriteQueueMaxWait")
+
 // txKey используется как ключ для хранения транзакции в context.Context
 type txKey struct{}
 
@@ -26,13 +37,44 @@ var (
 	_ Querier = (*sql.DB)(nil)
 	_ Querier = (*sql.Tx)(nil)
 	_ Querier = (*manualTx)(nil)
+	_ Querier = (*readOnlyTx)(nil)
+	_ Querier = (*cachedQuerier)(nil)
+	_ Querier = (*hookedQuerier)(nil)
 )
 
+// TxOptions переопределяет настройки TxRunner для одной транзакции.
+type TxOptions struct {
+	// LockMode - режим блокировки (пусто - режим TxRunner). Игнорируется для ReadOnly.
+	LockMode TxLockMode
+	// ReadOnly - транзакция только для чтения: выполняется в обход очереди записи,
+	// запись через ExecContext и PrepareContext отклоняется с ErrReadOnlyTx, остальные попытки записи
+	// отклоняет сам SQLite через PRAGMA query_only.
+	ReadOnly bool
+	// Isolation - уровень изоляции, передаётся драйверу в sql.TxOptions.
+	// SQLite всегда обеспечивает SERIALIZABLE, другие уровни драйвер игнорирует.
+	Isolation sql.IsolationLevel
+}
+
 // writeRequest представляет запрос на выполнение операции записи в очереди
 type writeRequest struct {
 	fn       func(context.Context) error
 	resultCh chan error
 	ctx      context.Context
+	opts     TxOptions
+	noTx     bool // выполнить fn без транзакции и ретраев
+}
+
+// WriteQueueStats содержит статистику очереди записи.
+type WriteQueueStats struct {
+	// Executed - количество запросов, выполненных очередью
+	Executed int64
+	// ExpiredInQueue - количество запросов, контекст которых истёк или был отменён
+	// до начала выполнения; такие запросы не выполняются
+	ExpiredInQueue int64
+	// Rejected - количество запросов, не принятых в очередь за DBOptions.WriteQueueMaxWait
+	Rejected int64
+	// Pending - текущее количество запросов в буфере очереди
+	Pending int
 }
 
 // TxRunner предоставляет возможность выполнения кода внутри транзакции.
@@ -45,6 +87,14 @@ type TxRunner struct {
 	writeQueue     chan writeRequest
 	writeQueueDone chan struct{}
 	enableQueue    bool
+	queueMaxWait   time.Duration
+	queueExecuted  atomic.Int64
+	queueExpired   atomic.Int64
+	queueRejected  atomic.Int64
+	classifyErrors bool
+	stmtCache      *stmtCache
+	queryHook      QueryHook
+	queryHookSQL   int
 }
 
 // NewTxRunner создает новый TxRunner с указанным подключением к БД и настройками по умолчанию.
@@ -52,26 +102,26 @@ func NewTxRunner(db *sql.DB) *TxRunner {
 	return NewTxRunnerWithOptions(db, DefaultDBOptions())
 }
 
-// RetryConfig содержит настройки для повторных попыток.
-type RetryConfig struct {
-	MaxAttempts  int
-	InitialDelay time.Duration
-	MaxDelay     time.Duration
-	Multiplier   float64
-}
-
 // NewTxRunnerWithOptions создает новый TxRunner с указанными опциями.
 func NewTxRunnerWithOptions(db *sql.DB, opts DBOptions) *TxRunner {
+	retryConfig := DefaultRetryConfig()
+	if opts.RetryConfig != nil {
+		retryConfig = *opts.RetryConfig
+	}
+
 	runner := &TxRunner{
-		DB:         db,
-		TxLockMode: opts.TxLockMode,
-		RetryConfig: &RetryConfig{
-			MaxAttempts:  3,
-			InitialDelay: 10 * time.Millisecond,
-			MaxDelay:     500 * time.Millisecond,
-			Multiplier:   2.0,
-		},
-		enableQueue: opts.EnableWriteQueue,
+		DB:             db,
+		TxLockMode:     opts.TxLockMode,
+		RetryConfig:    &retryConfig,
+		enableQueue:    opts.EnableWriteQueue,
+		queueMaxWait:   opts.WriteQueueMaxWait,
+		classifyErrors: opts.ClassifyErrors,
+		queryHook:      opts.QueryHook,
+		queryHookSQL:   opts.QueryHookMaxSQL,
+	}
+
+	if opts.StmtCacheSize > 0 {
+		runner.stmtCache = newStmtCache(db, opts.StmtCacheSize)
 	}
 
 	// Запускаем очередь записи если включена
@@ -84,12 +134,16 @@ func NewTxRunnerWithOptions(db *sql.DB, opts DBOptions) *TxRunner {
 	return runner
 }
 
-// Close закрывает TxRunner и очередь записи если она активна.
+// Close закрывает TxRunner, очередь записи если она активна
+// и закэшированные подготовленные выражения.
 func (r *TxRunner) Close() error {
 	if r.enableQueue && r.writeQueue != nil {
 		close(r.writeQueue)
 		<-r.writeQueueDone
 	}
+	if r.stmtCache != nil {
+		return r.stmtCache.close()
+	}
 	return nil
 }
 
@@ -98,14 +152,24 @@ func (r *TxRunner) Close() error {
 // Если fn выполняется успешно (возвращает nil), транзакция коммитится.
 // Транзакция доступна внутри fn через функцию SqlTx(ctx).
 // Поддерживает очередь записи и ретраи на SQLITE_BUSY.
+// При DBOptions.ClassifyErrors ошибка помечается через ClassifyError.
 func (r *TxRunner) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
 	// Если включена очередь записи - направляем в неё
-	if r.enableQueue {
-		return r.enqueueWrite(ctx, fn)
+	return r.WithinTxOptions(ctx, TxOptions{}, fn)
+}
+
+// WithinTxOptions выполняет функцию fn внутри транзакции с настройками opts,
+// переопределяющими настройки TxRunner для этого вызова.
+// Read-only транзакции никогда не проходят через очередь записи.
+// Внутри read-only транзакции SqlTx(ctx) возвращает false, используйте GetQuerier.
+func (r *TxRunner) WithinTxOptions(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error {
+	// Если включена очередь записи - направляем в неё
+	if r.enableQueue && !opts.ReadOnly {
+		return r.classify(r.enqueue(ctx, writeRequest{fn: fn, ctx: ctx, opts: opts}))
 	}
 
 	// Иначе выполняем напрямую с ретраями
-	return r.executeWithRetry(ctx, fn)
+	return r.classify(r.executeWithRetry(ctx, opts, fn))
 }
 
 // WithinTxWrite выполняет операцию записи внутри транзакции.
@@ -117,7 +181,7 @@ func (r *TxRunner) WithinTxWrite(ctx context.Context, fn func(ctx context.Contex
 // WithinTxRead выполняет операцию чтения внутри транзакции.
 // Игнорирует очередь записи и выполняет напрямую.
 func (r *TxRunner) WithinTxRead(ctx context.Context, fn func(ctx context.Context) error) error {
-	return r.executeWithRetry(ctx, fn)
+	return r.classify(r.executeWithRetry(ctx, TxOptions{}, fn))
 }
 
 // WithinSavepoint выполняет функцию fn внутри savepoint.
@@ -128,14 +192,23 @@ func (r *TxRunner) WithinSavepoint(ctx context.Context, fn func(ctx context.Cont
 	// Проверяем, есть ли уже активная транзакция
 	if existingQuerier, hasActiveTx := GetTxQuerier(ctx); hasActiveTx {
 		// Если есть активная транзакция - создаём savepoint внутри неё
-		return r.executeSavepoint(ctx, existingQuerier, fn)
+		return r.classify(r.executeSavepoint(ctx, existingQuerier, fn))
 	}
 
 	// Если нет активной транзакции - создаём новую транзакцию и savepoint внутри неё
-	return r.executeWithRetry(ctx, func(txCtx context.Context) error {
-		querier := r.GetQuerier(txCtx)
+	return r.classify(r.executeWithRetry(ctx, TxOptions{}, func(txCtx context.Context) error {
+		// Берём транзакцию напрямую: уникальные имена savepoint не должны попадать в кэш выражений
+		querier, _ := GetTxQuerier(txCtx)
 		return r.executeSavepoint(txCtx, querier, fn)
-	})
+	}))
+}
+
+// classify помечает ошибку через ClassifyError, если это включено в настройках.
+func (r *TxRunner) classify(err error) error {
+	if !r.classifyErrors {
+		return err
+	}
+	return ClassifyError(err)
 }
 
 // SqlTx извлекает активную транзакцию из контекста.
@@ -160,12 +233,39 @@ func GetTxQuerier(ctx context.Context) (Querier, bool) {
 // GetQuerier возвращает объект для выполнения запросов.
 // Если в контексте есть активная транзакция - возвращает её,
 // иначе возвращает основное подключение к БД.
+// При DBOptions.StmtCacheSize > 0 запросы выполняются через кэш подготовленных
+// выражений (кроме read-only транзакций).
+// При DBOptions.QueryHook каждый запрос замеряется и передаётся в хук.
 // Возвращаемый объект реализует интерфейс Querier.
 func (r *TxRunner) GetQuerier(ctx context.Context) Querier {
-	if querier, ok := GetTxQuerier(ctx); ok {
+	querier := r.cachedQuerier(ctx)
+	if r.queryHook == nil {
 		return querier
 	}
-	return r.DB
+	return &hookedQuerier{base: querier, hook: r.queryHook, maxSQL: r.queryHookSQL}
+}
+
+// cachedQuerier возвращает транзакцию из контекста или БД, при включённом кэше
+// выражений обёрнутые в cachedQuerier.
+func (r *TxRunner) cachedQuerier(ctx context.Context) Querier {
+	querier, ok := GetTxQuerier(ctx)
+	if !ok {
+		querier = r.DB
+	}
+	if r.stmtCache == nil {
+		return querier
+	}
+
+	switch q := querier.(type) {
+	case *sql.Tx:
+		return &cachedQuerier{cache: r.stmtCache, base: q, tx: q}
+	case *readOnlyTx:
+		// Кэш обошёл бы проверку записи в readOnlyTx.ExecContext
+		return q
+	default:
+		// *sql.DB и manualTx выполняют запросы через пул БД
+		return &cachedQuerier{cache: r.stmtCache, base: q}
+	}
 }
 
 // BeginTx начинает новую транзакцию с заданными опциями и сохраняет её в контексте.
@@ -186,23 +286,46 @@ func (r *TxRunner) runWriteQueue() {
 	defer close(r.writeQueueDone)
 
 	for req := range r.writeQueue {
-		select {
-		case <-req.ctx.Done():
-			req.resultCh <- req.ctx.Err()
-		default:
-			err := r.executeWithRetry(req.ctx, req.fn)
-			req.resultCh <- err
-		}
+		req.resultCh <- r.executeQueued(req)
 		close(req.resultCh)
 	}
 }
 
-// enqueueWrite добавляет операцию записи в очередь.
-func (r *TxRunner) enqueueWrite(ctx context.Context, fn func(context.Context) error) error {
-	req := writeRequest{
-		fn:       fn,
-		resultCh: make(chan error, 1),
-		ctx:      ctx,
+// executeQueued выполняет запрос из очереди записи.
+// Контекст проверяется непосредственно перед выполнением: запрос, простоявший
+// в очереди дольше своего дедлайна, не выполняется, даже если вызывающий ещё ждёт.
+func (r *TxRunner) executeQueued(req writeRequest) error {
+	if err := req.ctx.Err(); err != nil {
+		r.queueExpired.Add(1)
+		return err
+	}
+	r.queueExecuted.Add(1)
+	if req.noTx {
+		return req.fn(req.ctx)
+	}
+	return r.executeWithRetry(req.ctx, req.opts, req.fn)
+}
+
+// runWithoutTx выполняет fn вне транзакции, но в порядке очереди записи, если она включена.
+// Используется для операций, которые нельзя выполнять внутри транзакции (checkpoint, VACUUM).
+func (r *TxRunner) runWithoutTx(ctx context.Context, fn func(context.Context) error) error {
+	if !r.enableQueue {
+		return fn(ctx)
+	}
+	return r.enqueue(ctx, writeRequest{fn: fn, ctx: ctx, noTx: true})
+}
+
+// enqueue отправляет запрос в очередь записи и ждёт результата.
+// При DBOptions.WriteQueueMaxWait > 0 возвращает ErrWriteQueueTimeout, если очередь
+// не приняла запрос за это время.
+func (r *TxRunner) enqueue(ctx context.Context, req writeRequest) error {
+	req.resultCh = make(chan error, 1)
+
+	var timeout <-chan time.Time
+	if r.queueMaxWait > 0 {
+		timer := time.NewTimer(r.queueMaxWait)
+		defer timer.Stop()
+		timeout = timer.C
 	}
 
 	select {
@@ -213,58 +336,51 @@ func (r *TxRunner) enqueueWrite(ctx context.Context, fn func(context.Context) er
 		case <-ctx.Done():
 			return ctx.Err()
 		}
+	case <-timeout:
+		r.queueRejected.Add(1)
+		return shared.MarkKind(ErrWriteQueueTimeout, shared.KindTimeout)
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 }
 
-// executeWithRetry выполняет транзакцию с ретраями на SQLITE_BUSY.
-func (r *TxRunner) executeWithRetry(ctx context.Context, fn func(context.Context) error) error {
-	delay := r.RetryConfig.InitialDelay
-
-	for attempt := 1; attempt <= r.RetryConfig.MaxAttempts; attempt++ {
-		err := r.executeTx(ctx, fn)
-
-		// Если ошибки нет или это последняя попытка - возвращаем результат
-		if err == nil || attempt == r.RetryConfig.MaxAttempts {
-			return err
-		}
-
-		// Проверяем, является ли ошибка retryable
-		if !r.isSQLiteBusyError(err) {
-			return err
-		}
-
-		// Ожидаем перед следующей попыткой
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-time.After(delay):
-			// Увеличиваем задержку для следующей попытки
-			delay = time.Duration(float64(delay) * r.RetryConfig.Multiplier)
-			if delay > r.RetryConfig.MaxDelay {
-				delay = r.RetryConfig.MaxDelay
-			}
-		}
+// QueueStats возвращает статистику очереди записи.
+// Если очередь отключена (DBOptions.EnableWriteQueue == false), возвращает нулевые значения.
+func (r *TxRunner) QueueStats() WriteQueueStats {
+	if r.writeQueue == nil {
+		return WriteQueueStats{}
+	}
+	return WriteQueueStats{
+		Executed:       r.queueExecuted.Load(),
+		ExpiredInQueue: r.queueExpired.Load(),
+		Rejected:       r.queueRejected.Load(),
+		Pending:        len(r.writeQueue),
 	}
-
-	return fmt.Errorf("max retry attempts exceeded")
 }
 
 // executeTx выполняет одну попытку транзакции.
-func (r *TxRunner) executeTx(ctx context.Context, fn func(context.Context) error) error {
+func (r *TxRunner) executeTx(ctx context.Context, opts TxOptions, fn func(context.Context) error) error {
 	// Проверяем, есть ли уже активная транзакция в контексте
 	if _, existingTx := GetTxQuerier(ctx); existingTx {
 		return fmt.Errorf("nested transactions are not supported by SQLite")
 	}
 
+	if opts.ReadOnly {
+		return r.executeReadOnlyTx(ctx, opts, fn)
+	}
+
+	lockMode := r.TxLockMode
+	if opts.LockMode != "" {
+		lockMode = opts.LockMode
+	}
+
 	// Для SQLite нужно использовать специальный BEGIN с режимом блокировки
-	if r.TxLockMode != TxLockDeferred {
-		return r.executeTxWithLockMode(ctx, fn)
+	if lockMode != TxLockDeferred {
+		return r.executeTxWithLockMode(ctx, lockMode, fn)
 	}
 
 	// Стандартная DEFERRED транзакция
-	tx, err := r.DB.BeginTx(ctx, nil)
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{Isolation: opts.Isolation})
 	if err != nil {
 		return err
 	}
@@ -282,9 +398,9 @@ func (r *TxRunner) executeTx(ctx context.Context, fn func(context.Context) error
 }
 
 // executeTxWithLockMode выполняет транзакцию с указанным режимом блокировки.
-func (r *TxRunner) executeTxWithLockMode(ctx context.Context, fn func(context.Context) error) error {
+func (r *TxRunner) executeTxWithLockMode(ctx context.Context, lockMode TxLockMode, fn func(context.Context) error) error {
 	// Начинаем транзакцию с указанным режимом блокировки
-	beginQuery := fmt.Sprintf("BEGIN %s", r.TxLockMode)
+	beginQuery := fmt.Sprintf("BEGIN %s", lockMode)
 	_, err := r.DB.ExecContext(ctx, beginQuery)
 	if err != nil {
 		return err
@@ -307,6 +423,78 @@ func (r *TxRunner) executeTxWithLockMode(ctx context.Context, fn func(context.Co
 	return err
 }
 
+// executeReadOnlyTx выполняет read-only транзакцию.
+// На время транзакции соединение переводится в PRAGMA query_only, исходное значение
+// восстанавливается перед возвратом соединения в пул.
+func (r *TxRunner) executeReadOnlyTx(ctx context.Context, opts TxOptions, fn func(context.Context) error) error {
+	tx, err := r.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true, Isolation: opts.Isolation})
+	if err != nil {
+		return err
+	}
+
+	// Read-only транзакции нечего коммитить, поэтому всегда завершаем её откатом
+	defer func() { _ = tx.Rollback() }()
+
+	var queryOnly bool
+	if err := tx.QueryRowContext(ctx, "PRAGMA query_only").Scan(&queryOnly); err != nil {
+		return fmt.Errorf("failed to read query_only: %w", err)
+	}
+	if !queryOnly {
+		if _, err := tx.ExecContext(ctx, "PRAGMA query_only = 1"); err != nil {
+			return fmt.Errorf("failed to enable query_only: %w", err)
+		}
+		// Восстанавливаем без учёта отмены ctx, иначе соединение вернётся в пул read-only
+		defer func() {
+			_, _ = tx.ExecContext(context.WithoutCancel(ctx), "PRAGMA query_only = 0")
+		}()
+	}
+
+	ctx = context.WithValue(ctx, txKey{}, &readOnlyTx{tx: tx})
+	return fn(ctx)
+}
+
+// readOnlyTx оборачивает транзакцию и отклоняет запросы на запись через ExecContext.
+type readOnlyTx struct {
+	tx *sql.Tx
+}
+
+func (t *readOnlyTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if isWriteQuery(query) {
+		return nil, shared.MarkKind(ErrReadOnlyTx, shared.KindValidation)
+	}
+	return t.tx.ExecContext(ctx, query, args...)
+}
+
+func (t *readOnlyTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return t.tx.QueryContext(ctx, query, args...)
+}
+
+func (t *readOnlyTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return t.tx.QueryRowContext(ctx, query, args...)
+}
+
+// PrepareContext отклоняет запись так же, как ExecContext: подготовленный *sql.Stmt
+// выполняется мимо обёртки, поэтому запрос проверяется при подготовке.
+func (t *readOnlyTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	if isWriteQuery(query) {
+		return nil, shared.MarkKind(ErrReadOnlyTx, shared.KindValidation)
+	}
+	return t.tx.PrepareContext(ctx, query)
+}
+
+// isWriteQuery проверяет, начинается ли запрос с INSERT, UPDATE, DELETE или REPLACE.
+func isWriteQuery(query string) bool {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "INSERT", "UPDATE", "DELETE", "REPLACE":
+		return true
+	}
+	return false
+}
+
 // manualTx представляет ручную транзакцию для поддержки IMMEDIATE/EXCLUSIVE режимов.
 type manualTx struct {
 	db  *sql.DB
@@ -329,18 +517,6 @@ func (m *manualTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt,
 	return m.db.PrepareContext(ctx, query)
 }
 
-// isSQLiteBusyError проверяет, является ли ошибка SQLITE_BUSY.
-func (r *TxRunner) isSQLiteBusyError(err error) bool {
-	if err == nil {
-		return false
-	}
-
-	errStr := err.Error()
-	return strings.Contains(errStr, "database is locked") ||
-		strings.Contains(errStr, "SQLITE_BUSY") ||
-		strings.Contains(errStr, "database table is locked")
-}
-
 // executeSavepoint выполняет функцию внутри savepoint.
 func (r *TxRunner) executeSavepoint(ctx context.Context, querier Querier, fn func(context.Context) error) error {
 	// Генерируем уникальное имя savepoint
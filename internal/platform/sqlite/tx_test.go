@@ -5,10 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
 )
 
 func TestNewTxRunner(t *testing.T) {
@@ -498,3 +502,225 @@ func TestTxRunner_NestedTransactions(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 1, count)
 }
+
+func TestTxRunner_WithinTxOptions_LockModeOverride(t *testing.T) {
+	ctx := context.Background()
+	testDB := NewTestDBFile(t)
+	testDB.MustSeedData(t, "CREATE TABLE test (id INTEGER PRIMARY KEY, value TEXT)")
+
+	runner := NewTxRunnerWithOptions(testDB.DB, DBOptions{TxLockMode: TxLockDeferred})
+
+	err := runner.WithinTxOptions(ctx, TxOptions{LockMode: TxLockImmediate}, func(ctx context.Context) error {
+		// IMMEDIATE транзакция выполняется через manualTx, а не *sql.Tx
+		_, ok := SqlTx(ctx)
+		assert.False(t, ok)
+
+		_, err := runner.GetQuerier(ctx).ExecContext(ctx, "INSERT INTO test (value) VALUES (?)", "immediate")
+		return err
+	})
+	require.NoError(t, err)
+
+	var count int
+	require.NoError(t, testDB.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM test").Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func TestTxRunner_WithinTxOptions_ReadOnly(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewInMemoryDB(ctx)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, "CREATE TABLE test (id INTEGER PRIMARY KEY, value TEXT)")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "INSERT INTO test (value) VALUES ('existing')")
+	require.NoError(t, err)
+
+	runner := NewTxRunner(db)
+	readOnly := TxOptions{ReadOnly: true}
+
+	t.Run("reads are allowed", func(t *testing.T) {
+		var value string
+		err := runner.WithinTxOptions(ctx, readOnly, func(ctx context.Context) error {
+			return runner.GetQuerier(ctx).QueryRowContext(ctx, "SELECT value FROM test").Scan(&value)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "existing", value)
+	})
+
+	t.Run("exec writes are rejected", func(t *testing.T) {
+		err := runner.WithinTxOptions(ctx, readOnly, func(ctx context.Context) error {
+			_, err := runner.GetQuerier(ctx).ExecContext(ctx, "  insert INTO test (value) VALUES ('new')")
+			return err
+		})
+		require.ErrorIs(t, err, ErrReadOnlyTx)
+		assert.True(t, shared.IsValidation(err))
+	})
+
+	t.Run("prepared writes are rejected", func(t *testing.T) {
+		err := runner.WithinTxOptions(ctx, readOnly, func(ctx context.Context) error {
+			stmt, err := runner.GetQuerier(ctx).PrepareContext(ctx, "UPDATE test SET value = 'changed'")
+			if err != nil {
+				return err
+			}
+			defer stmt.Close()
+			_, err = stmt.ExecContext(ctx)
+			return err
+		})
+		require.ErrorIs(t, err, ErrReadOnlyTx)
+	})
+
+	t.Run("other writes are rejected by sqlite", func(t *testing.T) {
+		err := runner.WithinTxOptions(ctx, readOnly, func(ctx context.Context) error {
+			_, err := runner.GetQuerier(ctx).ExecContext(ctx, "CREATE TABLE other (id INTEGER)")
+			return err
+		})
+		require.Error(t, err)
+		assert.True(t, isReadOnlyError(err))
+	})
+
+	t.Run("connection is writable afterwards", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, "INSERT INTO test (value) VALUES ('after')")
+		require.NoError(t, err)
+	})
+
+	t.Run("nested transaction is rejected", func(t *testing.T) {
+		err := runner.WithinTxOptions(ctx, readOnly, func(ctx context.Context) error {
+			return runner.WithinTx(ctx, func(ctx context.Context) error { return nil })
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "nested transactions are not supported")
+	})
+}
+
+func TestTxRunner_WithinTxOptions_ReadOnlyBypassesWriteQueue(t *testing.T) {
+	ctx := context.Background()
+	testDB := NewTestDBFile(t)
+	testDB.MustSeedData(t, "CREATE TABLE test (id INTEGER PRIMARY KEY, value TEXT)")
+
+	opts := DefaultDBOptions()
+	opts.EnableWriteQueue = true
+	opts.WriteQueueSize = 1
+	runner := NewTxRunnerWithOptions(testDB.DB, opts)
+	defer runner.Close()
+
+	// Занимаем очередь записи до завершения read-only транзакции
+	started := make(chan struct{})
+	release := make(chan struct{})
+	writeDone := make(chan error, 1)
+	go func() {
+		writeDone <- runner.WithinTx(ctx, func(ctx context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	var count int
+	err := runner.WithinTxOptions(ctx, TxOptions{ReadOnly: true}, func(ctx context.Context) error {
+		return runner.GetQuerier(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM test").Scan(&count)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	close(release)
+	require.NoError(t, <-writeDone)
+}
+
+// blockWriteQueue занимает очередь записи медленной операцией и возвращает функцию,
+// которая её завершает. Возвращается после того, как очередь начала выполнение.
+func blockWriteQueue(t *testing.T, runner *TxRunner) func() {
+	t.Helper()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- runner.WithinTxWrite(context.Background(), func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	return func() {
+		close(release)
+		require.NoError(t, <-done)
+	}
+}
+
+func TestTxRunner_WriteQueue_ExpiredRequestNotExecuted(t *testing.T) {
+	db, err := NewInMemoryDB(context.Background())
+	require.NoError(t, err)
+	defer db.Close()
+
+	opts := DefaultDBOptions()
+	opts.EnableWriteQueue = true
+	runner := NewTxRunnerWithOptions(db, opts)
+	defer runner.Close()
+
+	release := blockWriteQueue(t, runner)
+
+	// Запрос с коротким дедлайном ждёт в очереди за медленной операцией
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var executed atomic.Bool
+	err = runner.WithinTxWrite(ctx, func(context.Context) error {
+		executed.Store(true)
+		return nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, runner.QueueStats().Pending)
+
+	release()
+	require.NoError(t, runner.WithinTxWrite(context.Background(), func(context.Context) error { return nil }))
+
+	assert.False(t, executed.Load(), "expired request must not be executed")
+	assert.Equal(t, WriteQueueStats{Executed: 2, ExpiredInQueue: 1}, runner.QueueStats())
+}
+
+func TestTxRunner_WriteQueue_MaxWait(t *testing.T) {
+	db, err := NewInMemoryDB(context.Background())
+	require.NoError(t, err)
+	defer db.Close()
+
+	opts := DefaultDBOptions()
+	opts.EnableWriteQueue = true
+	opts.WriteQueueSize = 1
+	opts.WriteQueueMaxWait = 20 * time.Millisecond
+	runner := NewTxRunnerWithOptions(db, opts)
+	defer runner.Close()
+
+	release := blockWriteQueue(t, runner)
+
+	// Заполняем буфер очереди
+	queued := make(chan error, 1)
+	go func() {
+		queued <- runner.WithinTxWrite(context.Background(), func(context.Context) error { return nil })
+	}()
+	require.Eventually(t, func() bool { return runner.QueueStats().Pending == 1 }, time.Second, time.Millisecond)
+
+	start := time.Now()
+	err = runner.WithinTxWrite(context.Background(), func(context.Context) error {
+		t.Error("rejected request must not be executed")
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrWriteQueueTimeout)
+	assert.True(t, shared.IsTimeout(err))
+	assert.Less(t, time.Since(start), time.Second)
+
+	release()
+	require.NoError(t, <-queued)
+	assert.Equal(t, WriteQueueStats{Executed: 2, Rejected: 1}, runner.QueueStats())
+}
+
+func TestTxRunner_QueueStats_Disabled(t *testing.T) {
+	db, err := NewInMemoryDB(context.Background())
+	require.NoError(t, err)
+	defer db.Close()
+
+	runner := NewTxRunner(db)
+	defer runner.Close()
+	require.NoError(t, runner.WithinTxWrite(context.Background(), func(context.Context) error { return nil }))
+	assert.Equal(t, WriteQueueStats{}, runner.QueueStats())
+}
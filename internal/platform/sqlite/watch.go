@@ -0,0 +1,263 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"sttbot/internal/shared"
+)
+
+// DefaultWatchInterval - период опроса Watch по умолчанию.
+const DefaultWatchInterval = time.Second
+
+// ErrInvalidWatch - некорректные аргументы Watch или отслеживаемая таблица недоступна.
+var ErrInvalidWatch = errors.New("sqlite: invalid watch")
+
+// WatchOptions содержит настройки WatchWithOptions.
+type WatchOptions struct {
+	// Interval - период опроса (0 - DefaultWatchInterval)
+	Interval time.Duration
+	// ChangeColumn - колонка, по максимуму которой обнаруживаются изменения строк,
+	// например updated_at или version. Без неё замечаются только вставки и удаления:
+	// UPDATE не меняет ни число строк, ни max(rowid)
+	ChangeColumn string
+	// Debounce - сколько изменения должны отсутствовать, прежде чем вызвать cb
+	// (0 - вызывать на первом опросе, заметившем изменение)
+	Debounce time.Duration
+	// OnError получает ошибки опроса; наблюдение при этом продолжается (nil - игнорировать)
+	OnError func(error)
+}
+
+// ChangeSet описывает изменения таблицы, накопленные с предыдущего вызова cb.
+type ChangeSet struct {
+	// Table - отслеживаемая таблица
+	Table string
+	// Rows - число строк после изменений
+	Rows int64
+	// MaxRowID - max(rowid) после изменений
+	MaxRowID int64
+	// LastChange - max(WatchOptions.ChangeColumn) в текстовом виде (пусто без колонки)
+	LastChange string
+	// Changes - сколько опросов заметили изменения, объединённые в этот вызов
+	Changes int
+	// DetectedAt - время опроса, заметившего последнее изменение
+	DetectedAt time.Time
+}
+
+// changeSource обнаруживает изменения таблицы. Сейчас единственная реализация -
+// опрос (pollSource); если драйвер получит update hooks, их реализация встанет сюда
+// без изменения API Watch.
+type changeSource interface {
+	// poll возвращает текущее состояние таблицы и признак его изменения с прошлого вызова
+	poll(ctx context.Context) (ChangeSet, bool, error)
+	close() error
+}
+
+// Watch отслеживает изменения строк таблицы table и вызывает cb с периодом опроса interval.
+// Эквивалентно WatchWithOptions с WatchOptions{Interval: interval}.
+func Watch(ctx context.Context, db *sql.DB, table string, interval time.Duration, cb func(ChangeSet)) (stop func(), err error) {
+	return WatchWithOptions(ctx, db, table, WatchOptions{Interval: interval}, cb)
+}
+
+// WatchWithOptions отслеживает изменения строк таблицы table и вызывает cb, когда они замечены.
+//
+// Опрос дешёвый: на выделенном соединении читается PRAGMA data_version, и только если
+// в базе были коммиты других соединений, выполняется запрос count(*), max(rowid) и
+// max(ChangeColumn) к таблице. Выделенное соединение занимает одно место в пуле; если
+// пул ограничен одним соединением (in-memory БД), data_version не используется и
+// таблица опрашивается каждый период.
+//
+// Изменения между опросами и во время работы cb объединяются в один вызов.
+// cb вызывается из одной горутины и никогда не выполняется параллельно с собой.
+//
+// Наблюдение прекращается при отмене ctx или вызове stop. stop ждёт завершения
+// текущего вызова cb, поэтому вызывать его из cb нельзя - отмените ctx.
+// Ошибки аргументов и отсутствие таблицы или колонки возвращаются сразу как
+// ErrInvalidWatch с видом shared.KindValidation.
+func WatchWithOptions(ctx context.Context, db *sql.DB, table string, opts WatchOptions, cb func(ChangeSet)) (stop func(), err error) {
+	if table == "" {
+		return nil, shared.MarkKind(fmt.Errorf("%w: table is required", ErrInvalidWatch), shared.KindValidation)
+	}
+	if cb == nil {
+		return nil, shared.MarkKind(fmt.Errorf("%w: callback is required", ErrInvalidWatch), shared.KindValidation)
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchInterval
+	}
+
+	src, err := newPollSource(ctx, db, table, opts.ChangeColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			_ = src.close()
+		}()
+		runWatch(ctx, src, opts, cb)
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(cancel)
+		<-done
+	}, nil
+}
+
+// runWatch опрашивает src до отмены ctx и вызывает cb для накопленных изменений.
+func runWatch(ctx context.Context, src changeSource, opts WatchOptions, cb func(ChangeSet)) {
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	var pending *ChangeSet
+	var lastChange time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cs, changed, err := src.poll(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if opts.OnError != nil {
+				opts.OnError(err)
+			}
+			continue
+		}
+
+		now := time.Now()
+		if changed {
+			cs.Changes = 1
+			if pending != nil {
+				cs.Changes += pending.Changes
+			}
+			cs.DetectedAt = now
+			pending = &cs
+			lastChange = now
+		}
+		if pending != nil && now.Sub(lastChange) >= opts.Debounce {
+			cb(*pending)
+			pending = nil
+		}
+	}
+}
+
+// pollSource обнаруживает изменения по PRAGMA data_version и снимку таблицы.
+type pollSource struct {
+	db    *sql.DB
+	conn  *sql.Conn // выделенное соединение для data_version, nil - опрашивать таблицу всегда
+	query string
+
+	version int64
+	state   ChangeSet
+}
+
+// newPollSource проверяет таблицу и запоминает её начальное состояние.
+func newPollSource(ctx context.Context, db *sql.DB, table, column string) (*pollSource, error) {
+	maxChange := "NULL"
+	if column != "" {
+		maxChange = "CAST(max(" + quoteIdent(column) + ") AS TEXT)"
+	}
+	s := &pollSource{
+		db:    db,
+		query: "SELECT count(*), coalesce(max(rowid), 0), " + maxChange + " FROM " + quoteIdent(table),
+	}
+	s.state.Table = table
+
+	// SQLite считает неизвестный идентификатор в кавычках строкой, поэтому колонку проверяем явно
+	if column != "" {
+		var found int
+		err := db.QueryRowContext(ctx, "SELECT count(*) FROM pragma_table_info(?) WHERE name = ?", table, column).Scan(&found)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check watch column: %w", err)
+		}
+		if found == 0 {
+			return nil, shared.MarkKind(fmt.Errorf("%w: no column %s in %s", ErrInvalidWatch, column, table), shared.KindValidation)
+		}
+	}
+
+	// data_version меняется только от коммитов других соединений,
+	// поэтому читать его нужно всегда на одном и том же соединении
+	if db.Stats().MaxOpenConnections != 1 {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire watch connection: %w", err)
+		}
+		s.conn = conn
+		if s.version, err = s.dataVersion(ctx); err != nil {
+			_ = s.close()
+			return nil, err
+		}
+	}
+
+	if err := s.scan(ctx, &s.state); err != nil {
+		_ = s.close()
+		return nil, shared.MarkKind(fmt.Errorf("%w: %s: %w", ErrInvalidWatch, table, err), shared.KindValidation)
+	}
+	return s, nil
+}
+
+func (s *pollSource) poll(ctx context.Context) (ChangeSet, bool, error) {
+	var version int64
+	if s.conn != nil {
+		var err error
+		if version, err = s.dataVersion(ctx); err != nil {
+			return ChangeSet{}, false, err
+		}
+		if version == s.version {
+			return s.state, false, nil
+		}
+	}
+
+	next := ChangeSet{Table: s.state.Table}
+	if err := s.scan(ctx, &next); err != nil {
+		return ChangeSet{}, false, fmt.Errorf("failed to poll %s: %w", s.state.Table, err)
+	}
+	// Версия запоминается только после успешного чтения, чтобы ошибка не скрыла изменения
+	s.version = version
+	changed := next.Rows != s.state.Rows || next.MaxRowID != s.state.MaxRowID || next.LastChange != s.state.LastChange
+	s.state = next
+	return next, changed, nil
+}
+
+// scan читает снимок таблицы в cs.
+func (s *pollSource) scan(ctx context.Context, cs *ChangeSet) error {
+	var row *sql.Row
+	if s.conn != nil {
+		row = s.conn.QueryRowContext(ctx, s.query)
+	} else {
+		row = s.db.QueryRowContext(ctx, s.query)
+	}
+	var lastChange sql.NullString
+	if err := row.Scan(&cs.Rows, &cs.MaxRowID, &lastChange); err != nil {
+		return err
+	}
+	cs.LastChange = lastChange.String
+	return nil
+}
+
+func (s *pollSource) dataVersion(ctx context.Context) (int64, error) {
+	var version int64
+	if err := s.conn.QueryRowContext(ctx, "PRAGMA data_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read data_version: %w", err)
+	}
+	return version, nil
+}
+
+func (s *pollSource) close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
@@ -0,0 +1,270 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+const watchInterval = 10 * time.Millisecond
+
+func newWatchTestDB(t *testing.T) *TestDB {
+	t.Helper()
+	_, testDB := newMaintenanceTestDB(t, DefaultDBOptions())
+	testDB.MustSeedData(t,
+		"CREATE TABLE settings (id INTEGER PRIMARY KEY, key TEXT, value TEXT, updated_at INTEGER NOT NULL DEFAULT 0)",
+		"CREATE TABLE other (id INTEGER PRIMARY KEY)",
+	)
+	return testDB
+}
+
+// collect возвращает cb, пересылающий ChangeSet в канал.
+func collect() (func(ChangeSet), chan ChangeSet) {
+	ch := make(chan ChangeSet, 100)
+	return func(cs ChangeSet) { ch <- cs }, ch
+}
+
+func receive(t *testing.T, ch chan ChangeSet) ChangeSet {
+	t.Helper()
+	select {
+	case cs := <-ch:
+		return cs
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change notification")
+		return ChangeSet{}
+	}
+}
+
+func assertNoChange(t *testing.T, ch chan ChangeSet) {
+	t.Helper()
+	select {
+	case cs := <-ch:
+		t.Fatalf("unexpected change notification: %+v", cs)
+	case <-time.After(10 * watchInterval):
+	}
+}
+
+func TestWatch_Insert(t *testing.T) {
+	ctx := context.Background()
+	testDB := newWatchTestDB(t)
+	testDB.Exec(t, "INSERT INTO settings (key, value) VALUES ('lang', 'ru')")
+
+	cb, ch := collect()
+	stop, err := Watch(ctx, testDB.DB, "settings", watchInterval, cb)
+	require.NoError(t, err)
+	defer stop()
+
+	// Начальное состояние не считается изменением
+	assertNoChange(t, ch)
+
+	testDB.Exec(t, "INSERT INTO settings (key, value) VALUES ('tz', 'UTC')")
+	cs := receive(t, ch)
+	assert.Equal(t, "settings", cs.Table)
+	assert.Equal(t, int64(2), cs.Rows)
+	assert.Equal(t, int64(2), cs.MaxRowID)
+	assert.Equal(t, 1, cs.Changes)
+	assert.False(t, cs.DetectedAt.IsZero())
+
+	// Изменения другой таблицы не уведомляют
+	testDB.Exec(t, "INSERT INTO other (id) VALUES (1)")
+	assertNoChange(t, ch)
+
+	testDB.Exec(t, "DELETE FROM settings WHERE key = 'tz'")
+	cs = receive(t, ch)
+	assert.Equal(t, int64(1), cs.Rows)
+}
+
+func TestWatch_ChangeColumn(t *testing.T) {
+	ctx := context.Background()
+	testDB := newWatchTestDB(t)
+	testDB.Exec(t, "INSERT INTO settings (key, value, updated_at) VALUES ('lang', 'ru', 1)")
+
+	cb, ch := collect()
+	stop, err := WatchWithOptions(ctx, testDB.DB, "settings", WatchOptions{
+		Interval:     watchInterval,
+		ChangeColumn: "updated_at",
+	}, cb)
+	require.NoError(t, err)
+	defer stop()
+
+	testDB.Exec(t, "UPDATE settings SET value = 'en', updated_at = 2 WHERE key = 'lang'")
+	cs := receive(t, ch)
+	assert.Equal(t, int64(1), cs.Rows)
+	assert.Equal(t, "2", cs.LastChange)
+}
+
+func TestWatch_CoalescesAndSerializes(t *testing.T) {
+	ctx := context.Background()
+	testDB := newWatchTestDB(t)
+
+	var running, overlaps atomic.Int32
+	release := make(chan struct{})
+	ch := make(chan ChangeSet, 100)
+	stop, err := Watch(ctx, testDB.DB, "settings", watchInterval, func(cs ChangeSet) {
+		if running.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		defer running.Add(-1)
+		ch <- cs
+		if cs.Rows == 1 {
+			<-release
+		}
+	})
+	require.NoError(t, err)
+	defer stop()
+
+	testDB.Exec(t, "INSERT INTO settings (key) VALUES ('first')")
+	first := receive(t, ch)
+	assert.Equal(t, int64(1), first.Rows)
+
+	// Пока cb занят, пачка изменений накапливается
+	for range 20 {
+		testDB.Exec(t, "INSERT INTO settings (key) VALUES ('burst')")
+	}
+	close(release)
+
+	second := receive(t, ch)
+	assert.Equal(t, int64(21), second.Rows)
+	assertNoChange(t, ch)
+	assert.Zero(t, overlaps.Load(), "callback must not run concurrently with itself")
+}
+
+func TestWatch_Debounce(t *testing.T) {
+	ctx := context.Background()
+	testDB := newWatchTestDB(t)
+
+	cb, ch := collect()
+	stop, err := WatchWithOptions(ctx, testDB.DB, "settings", WatchOptions{
+		Interval: watchInterval,
+		Debounce: 20 * watchInterval,
+	}, cb)
+	require.NoError(t, err)
+	defer stop()
+
+	// Изменения чаще Debounce сливаются в один вызов
+	for range 5 {
+		testDB.Exec(t, "INSERT INTO settings (key) VALUES ('k')")
+		time.Sleep(3 * watchInterval)
+	}
+	cs := receive(t, ch)
+	assert.Equal(t, int64(5), cs.Rows)
+	assert.Greater(t, cs.Changes, 1)
+	assertNoChange(t, ch)
+}
+
+func TestWatch_Stop(t *testing.T) {
+	testDB := newWatchTestDB(t)
+
+	t.Run("stop", func(t *testing.T) {
+		cb, ch := collect()
+		stop, err := Watch(context.Background(), testDB.DB, "settings", watchInterval, cb)
+		require.NoError(t, err)
+		stop()
+		stop() // повторный вызов безопасен
+
+		testDB.Exec(t, "INSERT INTO settings (key) VALUES ('after stop')")
+		assertNoChange(t, ch)
+	})
+
+	t.Run("отмена контекста", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cb, ch := collect()
+		stop, err := Watch(ctx, testDB.DB, "settings", watchInterval, cb)
+		require.NoError(t, err)
+		cancel()
+
+		stopped := make(chan struct{})
+		go func() {
+			stop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			t.Fatal("watcher did not stop on context cancellation")
+		}
+
+		testDB.Exec(t, "INSERT INTO settings (key) VALUES ('after cancel')")
+		assertNoChange(t, ch)
+	})
+
+	// Выделенное соединение возвращено в пул
+	assert.Zero(t, testDB.DB.Stats().InUse)
+}
+
+func TestWatch_InMemory(t *testing.T) {
+	testDB := NewTestDBInMemory(t)
+	testDB.Exec(t, "CREATE TABLE settings (id INTEGER PRIMARY KEY, key TEXT)")
+
+	cb, ch := collect()
+	stop, err := Watch(context.Background(), testDB.DB, "settings", watchInterval, cb)
+	require.NoError(t, err)
+	defer stop()
+
+	// Единственное соединение пула не занято наблюдателем
+	testDB.Exec(t, "INSERT INTO settings (key) VALUES ('lang')")
+	cs := receive(t, ch)
+	assert.Equal(t, int64(1), cs.Rows)
+}
+
+func TestWatch_PollErrors(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDBWithOptions(ctx, filepath.Join(t.TempDir(), "app.db"), DefaultDBOptions())
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.ExecContext(ctx, "CREATE TABLE settings (id INTEGER PRIMARY KEY)")
+	require.NoError(t, err)
+
+	errs := make(chan error, 100)
+	cb, _ := collect()
+	stop, err := WatchWithOptions(ctx, db, "settings", WatchOptions{
+		Interval: watchInterval,
+		OnError:  func(err error) { errs <- err },
+	}, cb)
+	require.NoError(t, err)
+	defer stop()
+
+	_, err = db.ExecContext(ctx, "DROP TABLE settings")
+	require.NoError(t, err)
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "no such table")
+	case <-time.After(5 * time.Second):
+		t.Fatal("poll error was not reported")
+	}
+}
+
+func TestWatch_Invalid(t *testing.T) {
+	ctx := context.Background()
+	testDB := newWatchTestDB(t)
+	cb, _ := collect()
+
+	tests := []struct {
+		name  string
+		table string
+		opts  WatchOptions
+		cb    func(ChangeSet)
+	}{
+		{name: "пустая таблица", table: "", cb: cb},
+		{name: "без callback", table: "settings"},
+		{name: "нет таблицы", table: "missing", cb: cb},
+		{name: "нет колонки", table: "settings", opts: WatchOptions{ChangeColumn: "missing"}, cb: cb},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stop, err := WatchWithOptions(ctx, testDB.DB, tt.table, tt.opts, tt.cb)
+			assert.Nil(t, stop)
+			assert.True(t, errors.Is(err, ErrInvalidWatch), "got %v", err)
+			assert.Equal(t, shared.KindValidation, shared.KindOf(err))
+		})
+	}
+	assert.Zero(t, testDB.DB.Stats().InUse, "connection must be released on error")
+}
@@ -0,0 +1,87 @@
+package shared
+
+import "errors"
+
+// Check collects business rule violations so that all of them are reported at once
+// instead of returning on the first failed Invariant.
+// The zero value is ready to use; nothing is allocated while every check passes.
+// Check is not safe for concurrent use.
+//
+// Example:
+//
+//	c := shared.NewCheck()
+//	c.Require(order.Customer != "", "customer is required") // the rest is meaningless without it
+//	c.NotEmpty("product", order.Product)
+//	c.InRange("quantity", order.Quantity, 1, 100)
+//	c.Thatf(order.Total >= minTotal, "total must be at least %d", minTotal)
+//	if err := c.Err(); err != nil {
+//	    return err // KindOf(err) == KindInvariantViolated
+//	}
+type Check struct {
+	failures []error
+	stopped  bool
+}
+
+// NewCheck returns an empty Check.
+func NewCheck() Check {
+	return Check{}
+}
+
+// That records a violation with the given message if condition is false.
+func (c *Check) That(condition bool, message string) {
+	if condition || c.stopped {
+		return
+	}
+	c.fail(Invariant(false, message))
+}
+
+// Thatf records a violation with a formatted message if condition is false.
+// The message is formatted only on failure.
+func (c *Check) Thatf(condition bool, format string, args ...interface{}) {
+	if condition || c.stopped {
+		return
+	}
+	c.fail(InvariantF(false, format, args...))
+}
+
+// NotEmpty records a violation if value is empty.
+func (c *Check) NotEmpty(name, value string) {
+	if value != "" || c.stopped {
+		return
+	}
+	c.fail(InvariantF(false, "%s must not be empty", name))
+}
+
+// InRange records a violation if v is outside [lo, hi].
+func (c *Check) InRange(name string, v, lo, hi int) {
+	if (v >= lo && v <= hi) || c.stopped {
+		return
+	}
+	c.fail(InvariantF(false, "%s must be between %d and %d, got %d", name, lo, hi, v))
+}
+
+// Require records a violation like That and, if condition is false, skips all
+// subsequent checks. Use it for preconditions the following checks depend on.
+// Arguments of later calls are still evaluated by Go, so Require cannot guard
+// against nil dereferences in them.
+func (c *Check) Require(condition bool, message string) {
+	if condition || c.stopped {
+		return
+	}
+	c.fail(Invariant(false, message))
+	c.stopped = true
+}
+
+// Err returns nil if every check passed. Otherwise it returns the violations joined
+// with errors.Join; each of them matches ErrInvariantViolated and can be retrieved
+// with UnwrapAll or errors.As. The returned error is a snapshot: later checks do not affect it.
+func (c *Check) Err() error {
+	if len(c.failures) == 0 {
+		return nil
+	}
+	return errors.Join(c.failures...)
+}
+
+func (c *Check) fail(err error) {
+	c.failures = append(c.failures, err)
+}
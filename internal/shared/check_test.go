@@ -0,0 +1,132 @@
+package shared_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+func TestCheck_AllPass(t *testing.T) {
+	c := shared.NewCheck()
+	c.That(true, "unused")
+	c.Thatf(true, "unused %d", 1)
+	c.NotEmpty("name", "bob")
+	c.InRange("age", 18, 18, 120)
+	c.Require(true, "unused")
+
+	assert.NoError(t, c.Err())
+}
+
+func TestCheck_ZeroValue(t *testing.T) {
+	var c shared.Check
+	assert.NoError(t, c.Err())
+
+	c.That(false, "broken")
+	assert.Error(t, c.Err())
+}
+
+func TestCheck_CollectsAllViolations(t *testing.T) {
+	c := shared.NewCheck()
+	c.That(false, "balance must not be negative")
+	c.Thatf(false, "at most %d items allowed", 10)
+	c.NotEmpty("name", "")
+	c.InRange("age", 150, 0, 120)
+	c.That(true, "passes")
+
+	err := c.Err()
+	require.Error(t, err)
+	assert.Equal(t, shared.KindInvariantViolated, shared.KindOf(err))
+	assert.True(t, shared.IsInvariantViolated(err))
+	assert.Equal(t,
+		"invariant violated: balance must not be negative\n"+
+			"invariant violated: at most 10 items allowed\n"+
+			"invariant violated: name must not be empty\n"+
+			"invariant violated: age must be between 0 and 120, got 150",
+		err.Error())
+
+	// Each violation is a separate error in the chain
+	var violations []string
+	for _, e := range shared.UnwrapAll(err)[1:] {
+		if e != shared.ErrInvariantViolated {
+			violations = append(violations, e.Error())
+		}
+	}
+	assert.Equal(t, []string{
+		"invariant violated: balance must not be negative",
+		"invariant violated: at most 10 items allowed",
+		"invariant violated: name must not be empty",
+		"invariant violated: age must be between 0 and 120, got 150",
+	}, violations)
+}
+
+func TestCheck_InRangeBounds(t *testing.T) {
+	tests := []struct {
+		name  string
+		value int
+		ok    bool
+	}{
+		{name: "below", value: 0, ok: false},
+		{name: "lower bound", value: 1, ok: true},
+		{name: "inside", value: 5, ok: true},
+		{name: "upper bound", value: 10, ok: true},
+		{name: "above", value: 11, ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := shared.NewCheck()
+			c.InRange("quantity", tt.value, 1, 10)
+			assert.Equal(t, tt.ok, c.Err() == nil)
+		})
+	}
+}
+
+func TestCheck_RequireStops(t *testing.T) {
+	c := shared.NewCheck()
+	c.That(false, "first")
+	c.Require(false, "email is required")
+	c.That(false, "email must contain @")
+	c.NotEmpty("name", "")
+	c.Require(false, "second require")
+
+	err := c.Err()
+	require.Error(t, err)
+	assert.Equal(t, "invariant violated: first\ninvariant violated: email is required", err.Error())
+}
+
+func TestCheck_ErrIsSnapshot(t *testing.T) {
+	c := shared.NewCheck()
+	c.That(false, "first")
+	err := c.Err()
+
+	c.That(false, "second")
+	assert.Equal(t, "invariant violated: first", err.Error())
+	assert.Len(t, c.Err().(interface{ Unwrap() []error }).Unwrap(), 2)
+}
+
+func TestCheck_NoAllocationsOnSuccess(t *testing.T) {
+	name := "bob"
+	allocs := testing.AllocsPerRun(100, func() {
+		c := shared.NewCheck()
+		c.That(len(name) > 0, "name is required")
+		c.NotEmpty("name", name)
+		c.InRange("length", len(name), 1, 64)
+		c.Require(name != "admin", "reserved name")
+		if err := c.Err(); err != nil {
+			panic(err)
+		}
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestCheck_ErrorsAs(t *testing.T) {
+	c := shared.NewCheck()
+	c.NotEmpty("title", "")
+
+	err := shared.Wrap(c.Err(), "new task")
+	assert.True(t, errors.Is(err, shared.ErrInvariantViolated))
+	assert.Equal(t, "new task: invariant violated: title must not be empty", err.Error())
+}
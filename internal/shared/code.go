@@ -0,0 +1,150 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// codePattern matches short snake_case identifiers such as "email_taken".
+var codePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// codeRegistry maps registered error codes to their kinds.
+var codeRegistry = struct {
+	sync.RWMutex
+	kinds map[string]Kind
+}{kinds: make(map[string]Kind)}
+
+// codeError attaches a machine-readable code to an error without changing its message.
+type codeError struct {
+	err  error
+	code string
+}
+
+// Error returns the message of the wrapped error unchanged.
+func (e *codeError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the coded error, so KindOf sees the kind the code was attached to.
+// Codes of inner errors stay reachable but are shadowed: CodeOf stops at this one.
+func (e *codeError) Unwrap() error {
+	return e.err
+}
+
+// WithCode attaches a stable machine-readable code (snake_case, e.g. "email_taken") to an error.
+// The error message and kind are not changed; the code can be extracted with CodeOf.
+// If err is nil, WithCode returns nil.
+// If code is empty, returns the original error.
+//
+// Example:
+//
+//	return shared.WithCode(shared.MarkKind(err, shared.KindConflict), "email_taken")
+func WithCode(err error, code string) error {
+	if err == nil {
+		return nil
+	}
+	if code == "" {
+		return err
+	}
+	return &codeError{err: err, code: code}
+}
+
+// RegisterCode registers an error code with its kind for use with NewCoded.
+// It is intended to be called from package init or var declarations.
+// RegisterCode panics if the code is not snake_case or is already registered with another kind.
+//
+// Example:
+//
+//	var _ = shared.RegisterCode("email_taken", shared.KindConflict)
+func RegisterCode(code string, kind Kind) string {
+	if !codePattern.MatchString(code) {
+		panic(fmt.Sprintf("shared: invalid error code %q: must be snake_case", code))
+	}
+
+	codeRegistry.Lock()
+	defer codeRegistry.Unlock()
+
+	if existing, ok := codeRegistry.kinds[code]; ok && existing != kind {
+		panic(fmt.Sprintf("shared: error code %q already registered with kind %s", code, existing))
+	}
+	codeRegistry.kinds[code] = kind
+	return code
+}
+
+// RegisteredKind returns the kind registered for the code with RegisterCode.
+func RegisteredKind(code string) (Kind, bool) {
+	codeRegistry.RLock()
+	defer codeRegistry.RUnlock()
+
+	kind, ok := codeRegistry.kinds[code]
+	return kind, ok
+}
+
+// NewCoded creates an error with the given code and message.
+// If the code is registered with RegisterCode, the error is marked with the registered kind;
+// otherwise it has KindUnknown.
+//
+// Example:
+//
+//	var CodeEmailTaken = shared.RegisterCode("email_taken", shared.KindConflict)
+//
+//	return shared.NewCoded(CodeEmailTaken, "email is already taken")
+func NewCoded(code string, msg string) error {
+	err := errors.New(msg)
+	if kind, ok := RegisteredKind(code); ok {
+		err = MarkKind(err, kind)
+	}
+	return WithCode(err, code)
+}
+
+// CodeOf returns the code attached to the error chain.
+// In a wrap chain the outermost code wins.
+// For errors.Join (and other multi-error wrappers), the code of the branch with the
+// highest-priority kind (see KindOf) is returned; ties are resolved by branch order.
+// Returns false if no code is attached or err is nil.
+func CodeOf(err error) (string, bool) {
+	for err != nil {
+		if ce, ok := err.(*codeError); ok {
+			return ce.code, true
+		}
+
+		if multi, ok := err.(interface{ Unwrap() []error }); ok {
+			return codeOfBranches(multi.Unwrap())
+		}
+		err = errors.Unwrap(err)
+	}
+	return "", false
+}
+
+// codeOfBranches selects the code of the branch with the highest-priority kind.
+func codeOfBranches(branches []error) (string, bool) {
+	var (
+		bestCode string
+		bestRank int
+		found    bool
+	)
+	for _, branch := range branches {
+		code, ok := CodeOf(branch)
+		if !ok {
+			continue
+		}
+		rank := kindRank(KindOf(branch))
+		if !found || rank < bestRank {
+			bestCode, bestRank, found = code, rank, true
+		}
+	}
+	return bestCode, found
+}
+
+// kindRank returns the position of the kind in the KindOf priority order (lower is higher priority).
+// KindUnknown has the lowest priority.
+func kindRank(kind Kind) int {
+	for i, priority := range kindPriorities {
+		if priority.kind == kind {
+			return i
+		}
+	}
+	return len(kindPriorities)
+}
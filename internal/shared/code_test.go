@@ -0,0 +1,138 @@
+package shared_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+func TestWithCode(t *testing.T) {
+	t.Run("nil error", func(t *testing.T) {
+		assert.Nil(t, shared.WithCode(nil, "email_taken"))
+	})
+
+	t.Run("empty code returns original", func(t *testing.T) {
+		base := errors.New("original")
+		assert.Same(t, base, shared.WithCode(base, ""))
+	})
+
+	t.Run("message, kind and chain preserved", func(t *testing.T) {
+		base := errors.New("email exists")
+		err := shared.WithCode(shared.MarkKind(base, shared.KindConflict), "email_taken")
+
+		assert.Equal(t, "conflict: email exists", err.Error())
+		assert.ErrorIs(t, err, base)
+		assert.True(t, shared.IsConflict(err))
+	})
+}
+
+func TestCodeOf(t *testing.T) {
+	base := errors.New("boom")
+
+	t.Run("no code", func(t *testing.T) {
+		_, ok := shared.CodeOf(base)
+		assert.False(t, ok)
+
+		_, ok = shared.CodeOf(nil)
+		assert.False(t, ok)
+	})
+
+	t.Run("survives wrap and mark kind", func(t *testing.T) {
+		err := shared.WithCode(base, "username_taken")
+		err = shared.MarkKind(shared.Wrap(err, "register"), shared.KindConflict)
+		err = shared.WithFields(err, shared.Fields{"user_id": 1})
+
+		code, ok := shared.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, "username_taken", code)
+	})
+
+	t.Run("outermost code wins", func(t *testing.T) {
+		err := shared.WithCode(shared.Wrap(shared.WithCode(base, "inner_code"), "ctx"), "outer_code")
+
+		code, _ := shared.CodeOf(err)
+		assert.Equal(t, "outer_code", code)
+	})
+
+	t.Run("joined errors use highest priority kind", func(t *testing.T) {
+		internal := shared.WithCode(shared.MarkKind(errors.New("disk full"), shared.KindInternal), "disk_full")
+		validation := shared.WithCode(shared.MarkKind(errors.New("bad email"), shared.KindValidation), "invalid_email")
+		unknown := shared.WithCode(errors.New("unclassified"), "unclassified")
+
+		for _, err := range []error{
+			errors.Join(internal, validation, unknown),
+			errors.Join(unknown, validation, internal),
+			shared.Wrap(errors.Join(unknown, internal, validation), "batch"),
+		} {
+			code, ok := shared.CodeOf(err)
+			require.True(t, ok)
+			assert.Equal(t, "invalid_email", code)
+		}
+	})
+
+	t.Run("joined errors with equal kinds use branch order", func(t *testing.T) {
+		first := shared.WithCode(shared.MarkKind(errors.New("a"), shared.KindConflict), "first_code")
+		second := shared.WithCode(shared.MarkKind(errors.New("b"), shared.KindConflict), "second_code")
+
+		code, _ := shared.CodeOf(errors.Join(first, second))
+		assert.Equal(t, "first_code", code)
+	})
+
+	t.Run("joined errors skip branches without code", func(t *testing.T) {
+		err := errors.Join(
+			shared.MarkKind(errors.New("not found"), shared.KindNotFound),
+			shared.WithCode(errors.New("x"), "only_code"),
+		)
+
+		code, ok := shared.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, "only_code", code)
+	})
+}
+
+func TestRegisterCode(t *testing.T) {
+	t.Run("new coded with registered kind", func(t *testing.T) {
+		code := shared.RegisterCode("test_email_taken", shared.KindConflict)
+
+		err := shared.NewCoded(code, "email is already taken")
+
+		assert.Equal(t, "conflict: email is already taken", err.Error())
+		assert.True(t, shared.IsConflict(err))
+		got, ok := shared.CodeOf(err)
+		require.True(t, ok)
+		assert.Equal(t, "test_email_taken", got)
+
+		kind, ok := shared.RegisteredKind(code)
+		require.True(t, ok)
+		assert.Equal(t, shared.KindConflict, kind)
+	})
+
+	t.Run("new coded without registration", func(t *testing.T) {
+		err := shared.NewCoded("test_unregistered", "something odd")
+
+		assert.Equal(t, "something odd", err.Error())
+		assert.Equal(t, shared.KindUnknown, shared.KindOf(err))
+		code, _ := shared.CodeOf(err)
+		assert.Equal(t, "test_unregistered", code)
+	})
+
+	t.Run("re-registration with same kind is allowed", func(t *testing.T) {
+		shared.RegisterCode("test_same_kind", shared.KindValidation)
+		assert.NotPanics(t, func() { shared.RegisterCode("test_same_kind", shared.KindValidation) })
+	})
+
+	t.Run("conflicting registration panics", func(t *testing.T) {
+		shared.RegisterCode("test_conflicting", shared.KindValidation)
+		assert.Panics(t, func() { shared.RegisterCode("test_conflicting", shared.KindConflict) })
+	})
+
+	t.Run("invalid code panics", func(t *testing.T) {
+		for _, code := range []string{"", "EmailTaken", "email-taken", "_email", "email__taken", "1email"} {
+			assert.Panics(t, func() { shared.RegisterCode(code, shared.KindConflict) }, code)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package shared
+
+import (
+	"log/slog"
+	"sync/atomic"
+)
+
+// numKinds is the number of defined Kind values.
+const numKinds = int(KindCanceled) + 1
+
+// Counter counts observed errors by kind.
+// The zero value is ready to use. Counter is safe for concurrent use.
+type Counter struct {
+	total atomic.Uint64
+	nils  atomic.Uint64
+	kinds [numKinds]atomic.Uint64
+}
+
+// DefaultCounter is the Counter used by the package-level Observe.
+var DefaultCounter = &Counter{}
+
+// Observe records err in DefaultCounter.
+func Observe(err error) {
+	DefaultCounter.Observe(err)
+}
+
+// Observe records err: nil errors increment the nil count, other errors the
+// count of their KindOf. Both increment the total.
+//
+// Example:
+//
+//	if err := job(ctx); err != nil {
+//	    shared.Observe(err)
+//	}
+func (c *Counter) Observe(err error) {
+	c.total.Add(1)
+	if err == nil {
+		c.nils.Add(1)
+		return
+	}
+	kind := KindOf(err)
+	if kind < 0 || int(kind) >= numKinds {
+		kind = KindUnknown
+	}
+	c.kinds[kind].Add(1)
+}
+
+// Snapshot returns non-zero counts of non-nil errors by kind.
+// Unclassified errors are counted under KindUnknown.
+func (c *Counter) Snapshot() map[Kind]uint64 {
+	snapshot := make(map[Kind]uint64)
+	for kind := range c.kinds {
+		if n := c.kinds[kind].Load(); n > 0 {
+			snapshot[Kind(kind)] = n
+		}
+	}
+	return snapshot
+}
+
+// Total returns the number of observed values, including nil errors.
+func (c *Counter) Total() uint64 {
+	return c.total.Load()
+}
+
+// Nil returns the number of observed nil errors.
+func (c *Counter) Nil() uint64 {
+	return c.nils.Load()
+}
+
+// Reset sets all counts to zero. Counts observed concurrently with Reset may be lost.
+func (c *Counter) Reset() {
+	c.total.Store(0)
+	c.nils.Store(0)
+	for kind := range c.kinds {
+		c.kinds[kind].Store(0)
+	}
+}
+
+// Attrs renders the counts as slog attributes: "total", "nil" and one attribute
+// per non-zero kind named by Kind.String, in Kind order.
+//
+// Example:
+//
+//	logger.LogAttrs(ctx, slog.LevelInfo, "error stats", shared.DefaultCounter.Attrs()...)
+func (c *Counter) Attrs() []slog.Attr {
+	attrs := []slog.Attr{
+		slog.Uint64("total", c.total.Load()),
+		slog.Uint64("nil", c.nils.Load()),
+	}
+	for kind := range c.kinds {
+		if n := c.kinds[kind].Load(); n > 0 {
+			attrs = append(attrs, slog.Uint64(Kind(kind).String(), n))
+		}
+	}
+	return attrs
+}
@@ -0,0 +1,79 @@
+package shared_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sttbot/internal/shared"
+)
+
+func TestCounter(t *testing.T) {
+	var c shared.Counter
+
+	c.Observe(nil)
+	c.Observe(shared.ErrNotFound)
+	c.Observe(shared.Wrap(shared.ErrNotFound, "lookup"))
+	c.Observe(context.Canceled)
+	c.Observe(errors.New("plain"))
+
+	assert.Equal(t, uint64(5), c.Total())
+	assert.Equal(t, uint64(1), c.Nil())
+	assert.Equal(t, map[shared.Kind]uint64{
+		shared.KindNotFound: 2,
+		shared.KindCanceled: 1,
+		shared.KindUnknown:  1,
+	}, c.Snapshot())
+
+	attrs := c.Attrs()
+	assert.Equal(t, []slog.Attr{
+		slog.Uint64("total", 5),
+		slog.Uint64("nil", 1),
+		slog.Uint64("Unknown", 1),
+		slog.Uint64("NotFound", 2),
+		slog.Uint64("Canceled", 1),
+	}, attrs)
+
+	c.Reset()
+	assert.Zero(t, c.Total())
+	assert.Zero(t, c.Nil())
+	assert.Empty(t, c.Snapshot())
+}
+
+func TestCounter_Concurrent(t *testing.T) {
+	var c shared.Counter
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				if (i+j)%2 == 0 {
+					c.Observe(nil)
+				} else {
+					c.Observe(shared.ErrTimeout)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, uint64(1000), c.Total())
+	assert.Equal(t, uint64(500), c.Nil())
+	assert.Equal(t, map[shared.Kind]uint64{shared.KindTimeout: 500}, c.Snapshot())
+}
+
+func TestObserve_DefaultCounter(t *testing.T) {
+	shared.DefaultCounter.Reset()
+	t.Cleanup(shared.DefaultCounter.Reset)
+
+	shared.Observe(shared.ErrConflict)
+	shared.Observe(nil)
+
+	assert.Equal(t, uint64(2), shared.DefaultCounter.Total())
+	assert.Equal(t, map[shared.Kind]uint64{shared.KindConflict: 1}, shared.DefaultCounter.Snapshot())
+}
@@ -47,7 +47,8 @@
 //
 // # Kind Priority Table
 //
-// When multiple error kinds are present (e.g., with errors.Join), KindOf returns the highest priority kind:
+// When multiple error kinds are present (e.g., with errors.Join), KindOf returns the highest priority kind.
+// KindsOf returns all of them in this order, and HasAnyKind checks for any of the given kinds:
 //
 //	Priority | Kind                  | Description
 //	---------|----------------------|--------------------
@@ -76,6 +77,43 @@
 //	    return shared.Wrapf(err, "failed to get user %d", id)
 //	}
 //
+// Use WrapTrace to additionally capture the call stack (once per chain):
+//
+//	if err := repo.GetUser(id); err != nil {
+//	    return shared.WrapTrace(err, "failed to get user")
+//	}
+//
+//	frames := shared.StackOf(err) // []Frame with Function/File/Line
+//
+// # Recovering Panics
+//
+// Convert panics into KindInternal errors carrying the panic site stack:
+//
+//	func (h *Handler) Handle(ctx context.Context) (err error) {
+//	    defer shared.Recover(&err)
+//	    ...
+//	}
+//
+//	err := shared.Safe(func() error { return job(ctx) })
+//	user, err := shared.SafeValue(func() (User, error) { return repo.GetUser(ctx, id) })
+//
+// # Error Statistics
+//
+// Counter counts observed errors by Kind; the package-level Observe records into DefaultCounter:
+//
+//	shared.Observe(err) // nil errors are counted separately
+//	logger.LogAttrs(ctx, slog.LevelInfo, "error stats", shared.DefaultCounter.Attrs()...)
+//
+// # Structured Fields
+//
+// Attach key/value metadata to errors and extract it at the adapter layer:
+//
+//	err = shared.WithFields(err, shared.Fields{"user_id": 123, "op": "create"})
+//
+//	for k, v := range shared.FieldsOf(err) {
+//	    attrs = append(attrs, slog.Any(k, v))
+//	}
+//
 // # Error Marking
 //
 // Mark errors with specific kinds while preserving the original error:
@@ -89,6 +127,63 @@
 //	// shared.IsNotFound(markedErr) == true
 //	// errors.Is(markedErr, sql.ErrNoRows) == true
 //
+// # Error Codes
+//
+// Kinds are coarse; attach stable snake_case codes for API consumers:
+//
+//	var CodeEmailTaken = shared.RegisterCode("email_taken", shared.KindConflict)
+//
+//	return shared.NewCoded(CodeEmailTaken, "email is already taken")
+//
+//	code, _ := shared.CodeOf(err) // "email_taken", KindOf(err) == KindConflict
+//
+// Use WithCode to attach a code to an existing error.
+//
+// # User-Facing Messages
+//
+// Attach a friendly message for the end user while keeping the detailed error for logs:
+//
+//	err = shared.WithUserMessage(err, "Audio is too long")
+//
+//	logger.Error("transcription failed", "error", err) // internal text only
+//	reply := shared.UserMessageOr(err, "Something went wrong")
+//
+// # Error Envelopes
+//
+// Render errors in one JSON shape for API and bot responses; Causes lists only
+// root cause messages. For external clients WithoutCauses drops Causes and replaces
+// the full Message with the user message or the kind's generic text:
+//
+//	_ = json.NewEncoder(w).Encode(shared.Envelope(err).WithoutCauses())
+//	// {"kind":"Conflict","code":"email_taken","message":"Email is taken","user_message":"Email is taken"}
+//
+// FromEnvelope turns a received envelope back into an error with the same kind and code:
+//
+//	err := shared.FromEnvelope(env) // shared.KindOf(err), shared.CodeOf(err) as on the server
+//
+// # Redacting Sensitive Data
+//
+// Scrub tokens and credentials from error text before it reaches logs or users,
+// keeping the chain intact for errors.Is/As and KindOf:
+//
+//	err = shared.Redact(err) // DefaultRedactions: bearer tokens, bot tokens, password=...
+//	err = shared.Redact(err, regexp.MustCompile(`chat_id=(\d+)`))
+//
+//	logger.Warn("request failed", "error", shared.SafeError(err))
+//
+// # Retryable Errors
+//
+// Signal that an error is safe to retry without depending on pkg/retry:
+//
+//	return shared.MarkRetryable(err)
+//
+//	if shared.IsRetryable(err) {
+//	    // marked errors, KindTimeout and KindDependencyFailure
+//	}
+//
+// Use IsExplicitlyRetryable to check only for the MarkRetryable marker.
+// pkg/retry and the HTTP client consult IsRetryable in their default predicates.
+//
 // # Business Rule Validation
 //
 // Use Invariant functions for business rule validation:
@@ -102,12 +197,47 @@
 //	    return err
 //	}
 //
+// Domain constructors validate themselves with Check, which reports every
+// violation at once and allocates nothing when all checks pass:
+//
+//	c := shared.NewCheck()
+//	c.Require(chatID != 0, "chat id is required") // skips the remaining checks on failure
+//	c.NotEmpty("text", text)
+//	c.InRange("delay", delayMinutes, 1, 1440)
+//	c.Thatf(len(text) <= maxLen, "text must be at most %d characters", maxLen)
+//	if err := c.Err(); err != nil {
+//	    return nil, err // violations joined with errors.Join, KindInvariantViolated
+//	}
+//
+// # Aggregating Errors
+//
+// Use ErrorList to collect several validation errors into a single error:
+//
+//	var errs shared.ErrorList
+//	errs.AddField("email", shared.MarkKind(errors.New("invalid format"), shared.KindValidation))
+//	errs.AddField("age", shared.MarkKind(errors.New("must be positive"), shared.KindValidation))
+//	if err := errs.Err(); err != nil {
+//	    return err // KindOf(err) == KindValidation
+//	}
+//
+// The HTTP adapter can build per-field responses with FieldErrors():
+//
+//	var list *shared.ErrorList
+//	if errors.As(err, &list) {
+//	    fields := list.FieldErrors() // map[string][]error
+//	}
+//
 // # Error Unwrapping and Root Causes
 //
 // Get the root cause of wrapped errors:
 //
 //	rootErr := shared.Cause(err)
 //
+// For errors.Join, Cause returns the root cause of the first joined error;
+// get all root causes in the same depth-first order:
+//
+//	rootErrs := shared.CausesOf(err)
+//
 // Get all errors in the chain (supports both fmt.Errorf %w and errors.Join):
 //
 //	allErrors := shared.UnwrapAll(err)
@@ -120,9 +250,9 @@
 // 4. Use predicate functions (IsNotFound, etc.) or HasKind for readable error checking
 // 5. Don't expose infrastructure details (database errors, HTTP status codes) in error messages
 // 6. Keep error messages lowercase and without punctuation for easy composition
-// 7. Use Invariant functions for business rule validation
-// 8. Map Kind to HTTP/GRPC codes in adapter layers, not in shared package
-// 9. Prefer SentinelOf over ErrorOf for better API clarity
+// 7. Use Check in domain constructors and Invariant for single business rule checks
+// 8. Map Kind to HTTP/GRPC codes in adapter layers (see the shared/httpmap subpackage)
+// 9. ErrorOf and SentinelOf are equivalent; use one of them consistently
 //
 // # Error Message Style Guide
 //
@@ -0,0 +1,146 @@
+package shared
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrorEnvelope is a transport-neutral, JSON-serializable description of an error
+// for API and bot responses. Build it with Envelope and turn it back into an error
+// with FromEnvelope.
+type ErrorEnvelope struct {
+	Kind        string         `json:"kind,omitempty"`         // Kind.String(), e.g. "NotFound"
+	Code        string         `json:"code,omitempty"`         // see CodeOf
+	Message     string         `json:"message,omitempty"`      // Error() of the error, see WithoutCauses
+	UserMessage string         `json:"user_message,omitempty"` // see UserMessage
+	Fields      map[string]any `json:"fields,omitempty"`       // see FieldsOf
+	Causes      []string       `json:"causes,omitempty"`       // messages of root causes, see CausesOf
+}
+
+// Envelope describes err with KindOf, CodeOf, UserMessage, FieldsOf and the messages
+// of its root causes (CausesOf), so every adapter renders errors the same way.
+// Only leaf messages are included in Causes, not intermediate wrap contexts.
+// Use WithoutCauses for external responses. If err is nil, returns a zero envelope.
+//
+// Example:
+//
+//	w.WriteHeader(httpmap.StatusOf(err))
+//	_ = json.NewEncoder(w).Encode(shared.Envelope(err).WithoutCauses())
+func Envelope(err error) ErrorEnvelope {
+	if err == nil {
+		return ErrorEnvelope{}
+	}
+
+	e := ErrorEnvelope{
+		Kind:    KindOf(err).String(),
+		Message: err.Error(),
+	}
+	e.Code, _ = CodeOf(err)
+	e.UserMessage, _ = UserMessage(err)
+	if fields := FieldsOf(err); len(fields) > 0 {
+		e.Fields = fields
+	}
+	for _, cause := range CausesOf(err) {
+		e.Causes = append(e.Causes, cause.Error())
+	}
+	return e
+}
+
+// WithoutCauses returns a copy of the envelope for external clients. Causes and the
+// full Message may reveal infrastructure details (database or network errors), so
+// Causes are dropped and Message is replaced with UserMessage, or with the message
+// of the kind's sentinel ("not found", "internal error" for unknown kinds, ...).
+func (e ErrorEnvelope) WithoutCauses() ErrorEnvelope {
+	e.Causes = nil
+	e.Message = e.UserMessage
+	if e.Message == "" {
+		e.Message = kindMessage(kindByName(e.Kind))
+	}
+	return e
+}
+
+// kindMessage returns the message of the kind's sentinel error, safe to show externally.
+func kindMessage(kind Kind) string {
+	if kind == KindCanceled {
+		return context.Canceled.Error()
+	}
+	if sentinel := ErrorOf(kind); sentinel != nil {
+		return sentinel.Error()
+	}
+	return ErrInternal.Error()
+}
+
+// FromEnvelope reconstructs an error from an envelope, e.g. received from another service.
+// The error reports Message from Error(), and keeps the kind, code, user message, fields
+// and causes, so Envelope(FromEnvelope(e)) reproduces kind and code exactly
+// (KindCanceled included). When Kind is empty or unknown, the kind registered for Code
+// with RegisterCode is used. If Kind and Message are both empty, FromEnvelope returns nil.
+//
+// Example:
+//
+//	var e shared.ErrorEnvelope
+//	if err := json.NewDecoder(resp.Body).Decode(&e); err == nil {
+//	    return shared.FromEnvelope(e) // shared.IsNotFound(err) works as for a local error
+//	}
+func FromEnvelope(e ErrorEnvelope) error {
+	if e.Kind == "" && e.Message == "" {
+		return nil
+	}
+
+	kind := kindByName(e.Kind)
+	if kind == KindUnknown {
+		if registered, ok := RegisteredKind(e.Code); ok {
+			kind = registered
+		}
+	}
+
+	msg := e.Message
+	if msg == "" {
+		msg = e.Kind
+	}
+	envErr := &envelopeError{msg: msg, kind: kind}
+	for _, cause := range e.Causes {
+		envErr.causes = append(envErr.causes, errors.New(cause))
+	}
+
+	var err error = envErr
+	err = WithFields(err, e.Fields)
+	err = WithUserMessage(err, e.UserMessage)
+	return WithCode(err, e.Code)
+}
+
+// envelopeError is an error reconstructed by FromEnvelope.
+type envelopeError struct {
+	msg    string
+	kind   Kind
+	causes []error
+}
+
+// Error returns the original message unchanged.
+func (e *envelopeError) Error() string {
+	return e.msg
+}
+
+// Is reports the kind of the original error, so KindOf and the Is* predicates work.
+func (e *envelopeError) Is(target error) bool {
+	if e.kind == KindCanceled {
+		return target == context.Canceled
+	}
+	sentinel := ErrorOf(e.kind)
+	return sentinel != nil && target == sentinel
+}
+
+// Unwrap returns the reconstructed root causes.
+func (e *envelopeError) Unwrap() []error {
+	return e.causes
+}
+
+// kindByName returns the Kind whose String() equals name, or KindUnknown.
+func kindByName(name string) Kind {
+	for kind := range Kind(numKinds) {
+		if kind.String() == name {
+			return kind
+		}
+	}
+	return KindUnknown
+}
@@ -0,0 +1,171 @@
+package shared_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+var codeChatNotFound = shared.RegisterCode("chat_not_found", shared.KindNotFound)
+
+func TestEnvelope(t *testing.T) {
+	t.Run("nil error", func(t *testing.T) {
+		assert.Equal(t, shared.ErrorEnvelope{}, shared.Envelope(nil))
+	})
+
+	t.Run("all facilities", func(t *testing.T) {
+		dbErr := errors.New("pq: relation \"chats\" does not exist")
+		err := shared.NewCoded(codeChatNotFound, "chat not found")
+		err = shared.WithUserMessage(err, "Chat is not registered")
+		err = shared.WithFields(err, shared.Fields{"chat_id": 42})
+		err = shared.Wrap(errors.Join(err, dbErr), "load chat")
+
+		env := shared.Envelope(err)
+		assert.Equal(t, "NotFound", env.Kind)
+		assert.Equal(t, "chat_not_found", env.Code)
+		assert.Equal(t, err.Error(), env.Message)
+		assert.Equal(t, "Chat is not registered", env.UserMessage)
+		assert.Equal(t, map[string]any{"chat_id": 42}, env.Fields)
+		assert.Equal(t, []string{"chat not found", dbErr.Error()}, env.Causes, "only leaf messages, no wrap context")
+	})
+
+	t.Run("unclassified error", func(t *testing.T) {
+		env := shared.Envelope(errors.New("boom"))
+		assert.Equal(t, shared.ErrorEnvelope{Kind: "Unknown", Message: "boom", Causes: []string{"boom"}}, env)
+	})
+}
+
+func TestEnvelope_JSON(t *testing.T) {
+	err := shared.WithCode(shared.MarkKind(errors.New("email is taken"), shared.KindConflict), "email_taken")
+
+	data, jsonErr := json.Marshal(shared.Envelope(err))
+	require.NoError(t, jsonErr)
+	assert.JSONEq(t, `{
+		"kind": "Conflict",
+		"code": "email_taken",
+		"message": "conflict: email is taken",
+		"causes": ["email is taken"]
+	}`, string(data))
+
+	data, jsonErr = json.Marshal(shared.Envelope(err).WithoutCauses())
+	require.NoError(t, jsonErr)
+	assert.JSONEq(t, `{"kind": "Conflict", "code": "email_taken", "message": "conflict"}`, string(data))
+}
+
+func TestEnvelope_WithoutCauses(t *testing.T) {
+	dbErr := errors.New(`pq: relation "chats" does not exist`)
+
+	t.Run("no leaf text of wrapped errors", func(t *testing.T) {
+		err := shared.Wrap(shared.MarkKind(dbErr, shared.KindDependencyFailure), "load chat")
+
+		env := shared.Envelope(err).WithoutCauses()
+		assert.Equal(t, "dependency failure", env.Message)
+		assert.Empty(t, env.Causes)
+		data, jsonErr := json.Marshal(env)
+		require.NoError(t, jsonErr)
+		assert.NotContains(t, string(data), "chats")
+		assert.NotContains(t, string(data), "load chat")
+	})
+
+	t.Run("user message", func(t *testing.T) {
+		err := shared.WithUserMessage(shared.Wrap(dbErr, "load chat"), "Chat is not registered")
+		assert.Equal(t, "Chat is not registered", shared.Envelope(err).WithoutCauses().Message)
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		assert.Equal(t, "internal error", shared.Envelope(dbErr).WithoutCauses().Message)
+	})
+}
+
+func TestFromEnvelope_RoundTrip(t *testing.T) {
+	kinds := []shared.Kind{
+		shared.KindUnknown,
+		shared.KindNotFound,
+		shared.KindValidation,
+		shared.KindUnauthorized,
+		shared.KindForbidden,
+		shared.KindConflict,
+		shared.KindInternal,
+		shared.KindTimeout,
+		shared.KindInvariantViolated,
+		shared.KindDependencyFailure,
+		shared.KindCanceled,
+	}
+	for _, kind := range kinds {
+		t.Run(kind.String(), func(t *testing.T) {
+			original := shared.ErrorEnvelope{
+				Kind:        kind.String(),
+				Code:        "some_code",
+				Message:     "request failed",
+				UserMessage: "Try again later",
+				Fields:      map[string]any{"attempt": 2.0},
+				Causes:      []string{"dial tcp: connection refused", "read: EOF"},
+			}
+
+			// Pass through JSON as between services
+			data, err := json.Marshal(original)
+			require.NoError(t, err)
+			var decoded shared.ErrorEnvelope
+			require.NoError(t, json.Unmarshal(data, &decoded))
+
+			rehydrated := shared.FromEnvelope(decoded)
+			require.Error(t, rehydrated)
+			assert.Equal(t, kind, shared.KindOf(rehydrated))
+			code, ok := shared.CodeOf(rehydrated)
+			assert.True(t, ok)
+			assert.Equal(t, "some_code", code)
+			assert.Equal(t, "request failed", rehydrated.Error())
+
+			assert.Equal(t, original, shared.Envelope(rehydrated))
+		})
+	}
+}
+
+func TestFromEnvelope(t *testing.T) {
+	t.Run("empty envelope", func(t *testing.T) {
+		assert.NoError(t, shared.FromEnvelope(shared.ErrorEnvelope{}))
+	})
+
+	t.Run("predicates and wrapping", func(t *testing.T) {
+		err := shared.FromEnvelope(shared.ErrorEnvelope{Kind: "NotFound", Message: "user not found"})
+		assert.True(t, shared.IsNotFound(err))
+		assert.ErrorIs(t, err, shared.ErrNotFound)
+
+		wrapped := fmt.Errorf("call users service: %w", err)
+		assert.Equal(t, shared.KindNotFound, shared.KindOf(wrapped))
+		assert.Equal(t, []shared.Kind{shared.KindNotFound}, shared.KindsOf(wrapped))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		err := shared.FromEnvelope(shared.ErrorEnvelope{Kind: "Canceled", Message: "context canceled"})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.True(t, shared.IsCanceled(err))
+	})
+
+	t.Run("kind from registered code", func(t *testing.T) {
+		err := shared.FromEnvelope(shared.ErrorEnvelope{Code: codeChatNotFound, Message: "chat not found"})
+		assert.Equal(t, shared.KindNotFound, shared.KindOf(err))
+	})
+
+	t.Run("unknown kind name", func(t *testing.T) {
+		err := shared.FromEnvelope(shared.ErrorEnvelope{Kind: "Teapot", Message: "short and stout"})
+		assert.Equal(t, shared.KindUnknown, shared.KindOf(err))
+		assert.Equal(t, "short and stout", err.Error())
+	})
+
+	t.Run("causes", func(t *testing.T) {
+		err := shared.FromEnvelope(shared.ErrorEnvelope{
+			Kind:    "DependencyFailure",
+			Message: "send message: dial tcp: connection refused",
+			Causes:  []string{"dial tcp: connection refused"},
+		})
+		assert.Equal(t, "dial tcp: connection refused", shared.Cause(err).Error())
+	})
+}
@@ -0,0 +1,122 @@
+package shared
+
+import (
+	"errors"
+	"strings"
+)
+
+// errorListEntry holds a single collected error and its optional field name.
+type errorListEntry struct {
+	field string
+	err   error
+}
+
+// ErrorList collects multiple errors, typically field validation errors,
+// and presents them as a single error.
+// The zero value is ready to use. ErrorList is not safe for concurrent use.
+//
+// Example:
+//
+//	var errs shared.ErrorList
+//	if user.Name == "" {
+//	    errs.AddField("name", shared.MarkKind(errors.New("must not be empty"), shared.KindValidation))
+//	}
+//	if user.Age < 18 {
+//	    errs.AddField("age", shared.MarkKind(errors.New("must be 18 or older"), shared.KindValidation))
+//	}
+//	if err := errs.Err(); err != nil {
+//	    return err // "name: validation failed: must not be empty; age: ..."
+//	}
+type ErrorList struct {
+	entries []errorListEntry
+}
+
+// Add appends an error without a field name. Nil errors are ignored.
+func (l *ErrorList) Add(err error) {
+	if err == nil {
+		return
+	}
+	l.entries = append(l.entries, errorListEntry{err: err})
+}
+
+// AddField appends an error associated with the given field. Nil errors are ignored.
+// The entry formats as "field: err".
+func (l *ErrorList) AddField(field string, err error) {
+	if err == nil {
+		return
+	}
+	l.entries = append(l.entries, errorListEntry{field: field, err: err})
+}
+
+// Len returns the number of collected errors.
+func (l *ErrorList) Len() int {
+	return len(l.entries)
+}
+
+// Err returns the collected errors as a single error, or nil if the list is empty.
+// If any entry has KindValidation, KindOf reports KindValidation for the whole list,
+// even if other entries have kinds of higher priority such as KindNotFound.
+// The returned error is a snapshot: later calls to Add do not affect it.
+// It can be unpacked with errors.As into *ErrorList to access individual entries.
+func (l *ErrorList) Err() error {
+	if len(l.entries) == 0 {
+		return nil
+	}
+	entries := make([]errorListEntry, len(l.entries))
+	copy(entries, l.entries)
+	return &ErrorList{entries: entries}
+}
+
+// Errors returns the collected errors in insertion order.
+// Field errors are wrapped with the field name as context.
+func (l *ErrorList) Errors() []error {
+	result := make([]error, 0, len(l.entries))
+	for _, e := range l.entries {
+		result = append(result, Wrap(e.err, e.field))
+	}
+	return result
+}
+
+// FieldErrors returns the original errors grouped by field name.
+// Errors added without a field name are not included.
+func (l *ErrorList) FieldErrors() map[string][]error {
+	result := make(map[string][]error)
+	for _, e := range l.entries {
+		if e.field == "" {
+			continue
+		}
+		result[e.field] = append(result[e.field], e.err)
+	}
+	return result
+}
+
+// Error returns all collected error messages separated by "; ".
+func (l *ErrorList) Error() string {
+	messages := make([]string, 0, len(l.entries))
+	for _, err := range l.Errors() {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the collected errors so errors.Is, KindOf and UnwrapAll
+// inspect every entry, the same way as for errors.Join.
+func (l *ErrorList) Unwrap() []error {
+	return l.Errors()
+}
+
+// hasValidation reports whether any entry has KindValidation.
+func (l *ErrorList) hasValidation() bool {
+	for _, e := range l.entries {
+		if HasAnyKind(e.err, KindValidation) {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidationList reports whether err is or wraps an ErrorList with a KindValidation entry.
+func isValidationList(err error) bool {
+	var list *ErrorList
+	return errors.As(err, &list) && list.hasValidation()
+}
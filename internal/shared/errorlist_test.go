@@ -0,0 +1,137 @@
+package shared_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+func TestErrorList_Empty(t *testing.T) {
+	var errs shared.ErrorList
+
+	assert.Equal(t, 0, errs.Len())
+	assert.NoError(t, errs.Err())
+	assert.Empty(t, errs.Errors())
+	assert.Empty(t, errs.FieldErrors())
+}
+
+func TestErrorList_IgnoresNil(t *testing.T) {
+	var errs shared.ErrorList
+	errs.Add(nil)
+	errs.AddField("name", nil)
+
+	assert.Equal(t, 0, errs.Len())
+	assert.NoError(t, errs.Err())
+}
+
+func TestErrorList_Message(t *testing.T) {
+	var errs shared.ErrorList
+	errs.AddField("name", errors.New("must not be empty"))
+	errs.Add(errors.New("request is malformed"))
+	errs.AddField("age", errors.New("must be positive"))
+
+	err := errs.Err()
+	require.Error(t, err)
+	assert.Equal(t, 3, errs.Len())
+	assert.Equal(t, "name: must not be empty; request is malformed; age: must be positive", err.Error())
+}
+
+func TestErrorList_Kind(t *testing.T) {
+	t.Run("validation item", func(t *testing.T) {
+		var errs shared.ErrorList
+		errs.AddField("email", shared.MarkKind(errors.New("invalid format"), shared.KindValidation))
+		errs.Add(errors.New("plain"))
+
+		err := errs.Err()
+		assert.Equal(t, shared.KindValidation, shared.KindOf(err))
+		assert.True(t, shared.IsValidation(err))
+	})
+
+	t.Run("validation with higher priority kind", func(t *testing.T) {
+		var errs shared.ErrorList
+		errs.AddField("user", shared.MarkKind(errors.New("user 42"), shared.KindNotFound))
+		errs.AddField("email", shared.MarkKind(errors.New("invalid format"), shared.KindValidation))
+
+		err := errs.Err()
+		assert.Equal(t, shared.KindValidation, shared.KindOf(err))
+		assert.True(t, errors.Is(err, shared.ErrNotFound), "entries stay reachable")
+		assert.Equal(t, []shared.Kind{shared.KindNotFound, shared.KindValidation}, shared.KindsOf(err))
+	})
+
+	t.Run("validation with lower priority kind", func(t *testing.T) {
+		var errs shared.ErrorList
+		errs.AddField("login", shared.MarkKind(errors.New("taken"), shared.KindConflict))
+		errs.AddField("email", shared.MarkKind(errors.New("invalid format"), shared.KindValidation))
+
+		err := errs.Err()
+		assert.Equal(t, shared.KindValidation, shared.KindOf(err))
+		assert.True(t, shared.HasKind(fmt.Errorf("register: %w", err), shared.KindValidation))
+	})
+
+	t.Run("no validation items", func(t *testing.T) {
+		var errs shared.ErrorList
+		errs.Add(shared.MarkKind(errors.New("user 42"), shared.KindNotFound))
+		errs.Add(shared.MarkKind(errors.New("taken"), shared.KindConflict))
+
+		assert.Equal(t, shared.KindNotFound, shared.KindOf(errs.Err()))
+	})
+
+	t.Run("no classified items", func(t *testing.T) {
+		var errs shared.ErrorList
+		errs.Add(errors.New("plain"))
+
+		assert.Equal(t, shared.KindUnknown, shared.KindOf(errs.Err()))
+	})
+}
+
+func TestErrorList_UnwrapAll(t *testing.T) {
+	first := errors.New("first")
+	second := errors.New("second")
+
+	var errs shared.ErrorList
+	errs.Add(first)
+	errs.AddField("field", second)
+
+	err := errs.Err()
+	assert.True(t, errors.Is(err, first))
+	assert.True(t, errors.Is(err, second))
+
+	all := shared.UnwrapAll(err)
+	assert.Contains(t, all, first)
+	assert.Contains(t, all, second)
+}
+
+func TestErrorList_FieldErrors(t *testing.T) {
+	nameEmpty := errors.New("must not be empty")
+	nameShort := errors.New("too short")
+	ageErr := errors.New("must be positive")
+
+	var errs shared.ErrorList
+	errs.AddField("name", nameEmpty)
+	errs.AddField("name", nameShort)
+	errs.AddField("age", ageErr)
+	errs.Add(errors.New("no field"))
+
+	fields := errs.FieldErrors()
+	assert.Equal(t, []error{nameEmpty, nameShort}, fields["name"])
+	assert.Equal(t, []error{ageErr}, fields["age"])
+	assert.Len(t, fields, 2)
+}
+
+func TestErrorList_ErrIsSnapshot(t *testing.T) {
+	var errs shared.ErrorList
+	errs.Add(errors.New("first"))
+	err := errs.Err()
+
+	errs.Add(errors.New("second"))
+
+	var list *shared.ErrorList
+	require.True(t, errors.As(err, &list))
+	assert.Equal(t, 1, list.Len())
+	assert.Equal(t, "first", err.Error())
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"reflect"
 )
 
 // Common domain errors that can be used across the application
@@ -136,6 +137,7 @@ var kindPriorities = []struct {
 //  5. KindInternal, KindInvariantViolated (lowest priority)
 //
 // For errors created with errors.Join, the first matching kind in priority order is returned.
+// An ErrorList with any KindValidation entry is KindValidation unless it is canceled or timed out.
 // Returns KindUnknown for unrecognized errors.
 //
 // Example:
@@ -166,6 +168,11 @@ func KindOf(err error) Kind {
 			if IsTimeout(err) {
 				return KindTimeout
 			}
+			// ErrorList with a validation entry is a validation error as a whole,
+			// even if other entries have kinds of higher priority (e.g. KindNotFound)
+			if isValidationList(err) {
+				return KindValidation
+			}
 		default:
 			if priority.err != nil && errors.Is(err, priority.err) {
 				return priority.kind
@@ -193,8 +200,101 @@ func HasKind(err error, kind Kind) bool {
 	return KindOf(err) == kind
 }
 
+// KindsOf returns every kind present in the error chain in KindOf priority order,
+// without duplicates. Unlike KindOf, it does not stop at the highest-priority kind,
+// so a joined validation and conflict error reports both. The first element equals KindOf(err).
+// The error graph is traversed once. Returns an empty slice for nil and unclassified errors.
+//
+// Example:
+//
+//	err := errors.Join(shared.MarkKind(errEmail, shared.KindValidation), shared.ErrConflict)
+//	shared.KindsOf(err) // [KindValidation KindConflict]
+func KindsOf(err error) []Kind {
+	kinds := []Kind{}
+	if err == nil {
+		return kinds
+	}
+
+	var found [numKinds]bool
+	walkGraph(err, func(err error) ([]error, bool) {
+		markKinds(err, &found)
+		return unwrapOnce(err), true
+	})
+
+	for _, priority := range kindPriorities {
+		if found[priority.kind] {
+			kinds = append(kinds, priority.kind)
+		}
+	}
+	return kinds
+}
+
+// HasAnyKind reports whether any of the given kinds is present in the error chain.
+// Unlike HasKind, it also matches kinds hidden behind a higher-priority one (see KindsOf).
+// KindUnknown matches errors without any kind, including nil, as with HasKind.
+//
+// Example:
+//
+//	if shared.HasAnyKind(err, shared.KindValidation, shared.KindConflict) {
+//	    return http.StatusBadRequest
+//	}
+func HasAnyKind(err error, kinds ...Kind) bool {
+	present := KindsOf(err)
+	for _, kind := range kinds {
+		if kind == KindUnknown && len(present) == 0 {
+			return true
+		}
+		for _, p := range present {
+			if p == kind {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// markKinds records kinds matched by a single error of the chain without unwrapping it.
+// Matching follows errors.Is and errors.As semantics used by KindOf, including Is and As methods.
+func markKinds(err error, found *[numKinds]bool) {
+	if matchesSentinel(err, context.Canceled) {
+		found[KindCanceled] = true
+	}
+	if matchesSentinel(err, context.DeadlineExceeded) || isNetTimeout(err) {
+		found[KindTimeout] = true
+	}
+	for _, priority := range kindPriorities {
+		if priority.err != nil && matchesSentinel(err, priority.err) {
+			found[priority.kind] = true
+		}
+	}
+}
+
+// matchesSentinel reports whether err itself (not its causes) matches target as in errors.Is.
+func matchesSentinel(err, target error) bool {
+	if err == target {
+		return true
+	}
+	if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(target) {
+		return true
+	}
+	return false
+}
+
+// isNetTimeout reports whether err itself (not its causes) is a net.Error timeout as in errors.As.
+func isNetTimeout(err error) bool {
+	if netErr, ok := err.(net.Error); ok {
+		return netErr.Timeout()
+	}
+	var netErr net.Error
+	if x, ok := err.(interface{ As(any) bool }); ok && x.As(&netErr) {
+		return netErr.Timeout()
+	}
+	return false
+}
+
 // ErrorOf returns the sentinel error for the given Kind.
 // For KindUnknown and KindCanceled, it returns nil.
+// ErrorOf and SentinelOf are equivalent and both are supported.
 func ErrorOf(kind Kind) error {
 	if sentinel, exists := kindToSentinel[kind]; exists {
 		return sentinel
@@ -202,11 +302,9 @@ func ErrorOf(kind Kind) error {
 	return nil
 }
 
-// SentinelOf is an alias for ErrorOf that provides a more intuitive name.
-// It returns the sentinel error for the given Kind.
+// SentinelOf returns the sentinel error for the given Kind.
 // For KindUnknown and KindCanceled, it returns nil.
-//
-// Recommended usage: prefer SentinelOf over ErrorOf for better API clarity.
+// It is equivalent to ErrorOf; neither is deprecated, pick one per code base.
 func SentinelOf(kind Kind) error {
 	return ErrorOf(kind)
 }
@@ -264,7 +362,23 @@ func MarkKind(err error, kind Kind) error {
 	}
 
 	// Wrap with the sentinel error
-	return fmt.Errorf("%w: %w", sentinel, err)
+	return &kindMarker{sentinel: sentinel, err: err}
+}
+
+// kindMarker is the error returned by MarkKind. It formats as "sentinel: err" and
+// unwraps to both errors like fmt.Errorf("%w: %w"), but has its own type so Cause
+// and CausesOf can skip the sentinel without dropping sentinels joined by callers.
+type kindMarker struct {
+	sentinel error
+	err      error
+}
+
+func (m *kindMarker) Error() string {
+	return m.sentinel.Error() + ": " + m.err.Error()
+}
+
+func (m *kindMarker) Unwrap() []error {
+	return []error{m.sentinel, m.err}
 }
 
 // Wrap wraps an error with additional context.
@@ -384,9 +498,11 @@ func IsDependencyFailure(err error) bool {
 	return errors.Is(err, ErrDependencyFailure)
 }
 
-// Cause returns the underlying cause of the error by repeatedly unwrapping it.
-// For simple wrap chains, returns the deepest cause.
-// For errors.Join, returns the first root cause found in depth-first order.
+// Cause returns the root cause of the error: the first leaf of the error graph in
+// depth-first, left-to-right order. For simple wrap chains this is the deepest error.
+// For errors.Join it is the deepest leaf of the first joined error in declaration order,
+// so the result is the same for every error of the same shape. Kind sentinels added
+// by MarkKind are markers, not causes, and are skipped.
 // If the error doesn't wrap anything, it returns the error itself.
 // If err is nil, Cause returns nil.
 func Cause(err error) error {
@@ -394,32 +510,97 @@ func Cause(err error) error {
 		return nil
 	}
 
-	// Use UnwrapAll to flatten the error graph, then find the first leaf
-	all := UnwrapAll(err)
-	if len(all) == 0 {
+	var cause error
+	walkLeaves(err, func(leaf error) bool {
+		cause = leaf
+		return false // the first leaf is enough
+	})
+	if cause == nil {
+		// Only possible for a cyclic error graph without leaves
 		return err
 	}
+	return cause
+}
 
-	// The last error in the flattened list is typically the root cause
-	// For Join errors, this gives us a deterministic first leaf
-	for i := len(all) - 1; i >= 0; i-- {
-		candidate := all[i]
+// CausesOf returns all root causes (leaf errors) of the error graph in the same
+// depth-first, left-to-right order as Cause, so CausesOf(err)[0] == Cause(err).
+// A leaf reachable through several paths is returned once.
+// If the error doesn't wrap anything, it returns a slice with the error itself.
+// If err is nil, returns nil slice.
+func CausesOf(err error) []error {
+	if err == nil {
+		return nil
+	}
 
-		// Check if this error has no further nested errors
-		hasNested := false
-		if unwrapper, ok := candidate.(interface{ Unwrap() []error }); ok {
-			hasNested = len(unwrapper.Unwrap()) > 0
+	var causes []error
+	walkLeaves(err, func(leaf error) bool {
+		causes = append(causes, leaf)
+		return true
+	})
+	return causes
+}
+
+// walkLeaves calls visit for each leaf of the error graph in depth-first,
+// left-to-right order until visit returns false. Each error is visited once.
+func walkLeaves(err error, visit func(leaf error) bool) {
+	walkGraph(err, func(err error) ([]error, bool) {
+		var nested []error
+		if marker, ok := err.(*kindMarker); ok {
+			// The kind sentinel added by MarkKind is a marker, not a cause
+			nested = []error{marker.err}
 		} else {
-			hasNested = errors.Unwrap(candidate) != nil
+			nested = unwrapOnce(err)
+		}
+		if len(nested) == 0 {
+			return nil, visit(err)
+		}
+		return nested, true
+	})
+}
+
+// walkGraph walks the error graph in depth-first, left-to-right order, calling
+// visit once for each error. visit returns the errors to descend into and false
+// to stop the walk.
+func walkGraph(err error, visit func(err error) (next []error, ok bool)) {
+	seen := make(map[error]bool) // prevent infinite loops and duplicate visits
+
+	var walk func(err error) bool
+	walk = func(err error) bool {
+		if err == nil {
+			return true
+		}
+		// Errors of uncomparable types cannot be map keys; they cannot form a cycle either
+		if reflect.TypeOf(err).Comparable() {
+			if seen[err] {
+				return true
+			}
+			seen[err] = true
 		}
 
-		if !hasNested {
-			return candidate
+		nested, ok := visit(err)
+		if !ok {
+			return false
 		}
+		for _, next := range nested {
+			if !walk(next) {
+				return false
+			}
+		}
+		return true
 	}
+	walk(err)
+}
 
-	// Fallback: return the original error if no leaf found
-	return err
+// unwrapOnce returns the errors directly wrapped by err: Unwrap() []error
+// (errors.Join case) or Unwrap() error (fmt.Errorf %w case).
+func unwrapOnce(err error) []error {
+	if unwrapper, ok := err.(interface{ Unwrap() []error }); ok {
+		return unwrapper.Unwrap()
+	}
+	if next := errors.Unwrap(err); next != nil {
+		return []error{next}
+	}
+	return nil
 }
 
 // UnwrapAll returns all errors in the error chain, from outermost to innermost.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -1052,55 +1053,98 @@ func TestCauseWithJoin(t *testing.T) {
 	wrappedErr2 := shared.Wrap(rootErr2, "wrapped 2")
 
 	tests := []struct {
-		name        string
-		err         error
-		expectedAny []error // any of these errors is acceptable as root cause
+		name       string
+		err        error
+		expected   error   // Cause: first leaf in depth-first, left-to-right order
+		wantCauses []error // CausesOf: all leaves in the same order
 	}{
 		{
-			name:        "simple join - returns one of the root errors",
-			err:         errors.Join(rootErr1, rootErr2),
-			expectedAny: []error{rootErr1, rootErr2},
+			name:       "simple join - returns the first root error",
+			err:        errors.Join(rootErr1, rootErr2),
+			expected:   rootErr1,
+			wantCauses: []error{rootErr1, rootErr2},
 		},
 		{
-			name:        "join with wrapped errors",
-			err:         errors.Join(wrappedErr1, wrappedErr2),
-			expectedAny: []error{rootErr1, rootErr2},
+			name:       "join with wrapped errors",
+			err:        errors.Join(wrappedErr1, wrappedErr2),
+			expected:   rootErr1,
+			wantCauses: []error{rootErr1, rootErr2},
 		},
 		{
-			name:        "nested join",
-			err:         errors.Join(errors.Join(rootErr1, rootErr2), rootErr3),
-			expectedAny: []error{rootErr1, rootErr2, rootErr3},
+			name:       "nested join",
+			err:        errors.Join(errors.Join(rootErr1, rootErr2), rootErr3),
+			expected:   rootErr1,
+			wantCauses: []error{rootErr1, rootErr2, rootErr3},
 		},
 		{
-			name:        "mixed wrap and join",
-			err:         shared.Wrap(errors.Join(rootErr1, rootErr2), "outer wrapper"),
-			expectedAny: []error{rootErr1, rootErr2},
+			name:       "shallow leaf before deep one",
+			err:        errors.Join(rootErr3, shared.Wrap(wrappedErr1, "deep")),
+			expected:   rootErr3,
+			wantCauses: []error{rootErr3, rootErr1},
 		},
 		{
-			name:        "single error in join",
-			err:         errors.Join(rootErr1),
-			expectedAny: []error{rootErr1},
+			name:       "mixed wrap and join",
+			err:        shared.Wrap(errors.Join(rootErr2, rootErr1), "outer wrapper"),
+			expected:   rootErr2,
+			wantCauses: []error{rootErr2, rootErr1},
+		},
+		{
+			name:       "single error in join",
+			err:        errors.Join(rootErr1),
+			expected:   rootErr1,
+			wantCauses: []error{rootErr1},
+		},
+		{
+			name:       "shared leaf returned once",
+			err:        errors.Join(wrappedErr1, rootErr1, rootErr2),
+			expected:   rootErr1,
+			wantCauses: []error{rootErr1, rootErr2},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := shared.Cause(tt.err)
-			require.NotNil(t, result, "Cause should not return nil for non-nil error")
-
-			// Check that result is one of the expected root causes
-			found := false
-			for _, expected := range tt.expectedAny {
-				if result == expected {
-					found = true
-					break
-				}
+			// Repeated calls give the same result
+			for range 10 {
+				assert.Same(t, tt.expected, shared.Cause(tt.err))
 			}
-			assert.True(t, found, "Cause should return one of %v, got %v", tt.expectedAny, result)
+			assert.Equal(t, tt.wantCauses, shared.CausesOf(tt.err))
 		})
 	}
 }
 
+func TestCausesOf(t *testing.T) {
+	assert.Nil(t, shared.CausesOf(nil))
+
+	plain := errors.New("plain")
+	assert.Equal(t, []error{plain}, shared.CausesOf(plain))
+	assert.Equal(t, []error{plain}, shared.CausesOf(fmt.Errorf("a: %w", fmt.Errorf("b: %w", plain))))
+
+	// Kind sentinels added by MarkKind are not causes
+	marked := shared.MarkKind(shared.Wrap(plain, "query"), shared.KindDependencyFailure)
+	assert.Equal(t, []error{plain}, shared.CausesOf(marked))
+	assert.Same(t, plain, shared.Cause(marked))
+	assert.Equal(t, []error{shared.ErrNotFound, shared.ErrConflict},
+		shared.CausesOf(errors.Join(shared.ErrNotFound, shared.ErrConflict)))
+	// Sentinels joined by callers are causes
+	assert.Equal(t, []error{shared.ErrNotFound, io.EOF}, shared.CausesOf(errors.Join(shared.ErrNotFound, io.EOF)))
+	assert.Equal(t, []error{shared.ErrConflict}, shared.CausesOf(shared.MarkKind(shared.ErrConflict, shared.KindNotFound)))
+
+	// Leaves of uncomparable types are supported
+	leaf := uncomparableError{details: []string{"x"}}
+	causes := shared.CausesOf(errors.Join(plain, leaf))
+	require.Len(t, causes, 2)
+	assert.Equal(t, plain, causes[0])
+	assert.IsType(t, uncomparableError{}, causes[1])
+}
+
+// uncomparableError is an error type that cannot be used as a map key
+type uncomparableError struct {
+	details []string
+}
+
+func (e uncomparableError) Error() string { return "uncomparable" }
+
 func TestUnwrapAll(t *testing.T) {
 	baseErr := errors.New("root cause")
 	wrappedOnce := shared.Wrap(baseErr, "level 1")
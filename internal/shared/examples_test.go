@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
 	"strings"
 
 	"sttbot/internal/shared"
@@ -129,6 +131,35 @@ func Example_invariant() {
 	// Email validation passed
 }
 
+// Example_check demonstrates validating a domain object with all violations reported at once.
+func Example_check() {
+	newReminder := func(chatID int64, text string, delayMinutes int) error {
+		c := shared.NewCheck()
+		c.Require(chatID != 0, "chat id is required")
+		c.NotEmpty("text", text)
+		c.InRange("delay", delayMinutes, 1, 24*60)
+		c.Thatf(len(text) <= 200, "text must be at most %d characters", 200)
+		return c.Err()
+	}
+
+	fmt.Println("Valid:", newReminder(42, "drink water", 30))
+
+	err := newReminder(42, "", 0)
+	fmt.Println("Kind:", shared.KindOf(err))
+	fmt.Println(err.Error())
+
+	// Require skips the remaining checks
+	err = newReminder(0, "", 0)
+	fmt.Println(err.Error())
+
+	// Output:
+	// Valid: <nil>
+	// Kind: InvariantViolated
+	// invariant violated: text must not be empty
+	// invariant violated: delay must be between 1 and 1440, got 0
+	// invariant violated: chat id is required
+}
+
 // Example_httpMapping demonstrates mapping error kinds to HTTP status codes in an adapter.
 func Example_httpMapping() {
 	// This would typically be in an HTTP adapter layer
@@ -183,19 +214,20 @@ func Example_cause() {
 	fmt.Println("Root cause:", rootCause.Error())
 	fmt.Println("Is base error:", rootCause == baseErr)
 
-	// With joined errors - note: specific root depends on implementation
+	// With joined errors the root cause of the first joined error is returned,
+	// CausesOf returns all of them
 	joinedErr := errors.Join(
 		shared.Wrap(errors.New("error 1"), "wrapped 1"),
 		shared.Wrap(errors.New("error 2"), "wrapped 2"),
 	)
-	joinedCause := shared.Cause(joinedErr)
-	fmt.Printf("Joined has root cause: %t\n",
-		joinedCause.Error() == "error 1" || joinedCause.Error() == "error 2")
+	fmt.Println("Joined root cause:", shared.Cause(joinedErr))
+	fmt.Println("Joined root causes:", len(shared.CausesOf(joinedErr)))
 
 	// Output:
 	// Root cause: connection refused
 	// Is base error: true
-	// Joined has root cause: true
+	// Joined root cause: error 1
+	// Joined root causes: 2
 }
 
 // Example_unwrapAll demonstrates getting all errors in a chain.
@@ -266,3 +298,40 @@ func Example_priorityDemo() {
 	// Timeout + Canceled + Dependency → Canceled
 	// Internal + Dependency + Invariant → DependencyFailure
 }
+
+// Example_counter demonstrates counting error kinds in adapter hooks with one line per call site.
+func Example_counter() {
+	counter := &shared.Counter{} // or shared.Observe with shared.DefaultCounter
+
+	// Scheduler hook and HTTP client call site
+	onJobError := func(jobName string, err error) { counter.Observe(err) }
+	onRequestDone := func(err error) { counter.Observe(err) }
+
+	onJobError("digest", shared.MarkKind(errors.New("db locked"), shared.KindDependencyFailure))
+	onJobError("cleanup", context.DeadlineExceeded)
+	onRequestDone(errors.New("unexpected status 418"))
+	onRequestDone(nil)
+
+	snapshot := counter.Snapshot()
+	fmt.Println("Total:", counter.Total(), "Nil:", counter.Nil())
+	fmt.Println("DependencyFailure:", snapshot[shared.KindDependencyFailure])
+	fmt.Println("Timeout:", snapshot[shared.KindTimeout])
+	fmt.Println("Unknown:", snapshot[shared.KindUnknown])
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.LogAttrs(context.Background(), slog.LevelInfo, "error stats", counter.Attrs()...)
+
+	// Output:
+	// Total: 4 Nil: 1
+	// DependencyFailure: 1
+	// Timeout: 1
+	// Unknown: 1
+	// level=INFO msg="error stats" total=4 nil=1 Unknown=1 Timeout=1 DependencyFailure=1
+}
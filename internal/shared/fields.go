@@ -0,0 +1,75 @@
+package shared
+
+// Fields holds structured key/value metadata attached to an error.
+type Fields map[string]any
+
+// fieldsError attaches structured fields to an error without changing its message.
+type fieldsError struct {
+	err    error
+	fields Fields
+}
+
+// Error returns the message of the wrapped error unchanged.
+func (e *fieldsError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap exposes the annotated error, so its kind and sentinels stay visible
+// and FieldsOf can collect fields attached deeper in the chain.
+func (e *fieldsError) Unwrap() error {
+	return e.err
+}
+
+// WithFields attaches structured key/value metadata to an error.
+// The error message is not changed; fields can be extracted later with FieldsOf.
+// The given map is copied, so later modifications by the caller have no effect.
+// If err is nil, WithFields returns nil.
+// If fields is empty, returns the original error.
+//
+// Example:
+//
+//	if err := repo.CreateUser(ctx, u); err != nil {
+//	    return shared.WithFields(err, shared.Fields{"user_id": u.ID, "op": "create"})
+//	}
+func WithFields(err error, fields map[string]any) error {
+	if err == nil {
+		return nil
+	}
+	if len(fields) == 0 {
+		return err
+	}
+
+	copied := make(Fields, len(fields))
+	for k, v := range fields {
+		copied[k] = v
+	}
+	return &fieldsError{err: err, fields: copied}
+}
+
+// FieldsOf returns all fields attached to the error chain, merged into a single map.
+// It traverses wrapped errors, including errors.Join and MarkKind chains.
+// When the same key is attached several times, the outermost value wins.
+// Returns an empty (non-nil) map if no fields are attached or err is nil.
+//
+// Example of emitting fields as slog attributes:
+//
+//	attrs := []any{slog.Any("error", err)}
+//	for k, v := range shared.FieldsOf(err) {
+//	    attrs = append(attrs, slog.Any(k, v))
+//	}
+//	logger.Error("request failed", attrs...)
+func FieldsOf(err error) Fields {
+	result := make(Fields)
+	for _, e := range UnwrapAll(err) {
+		fe, ok := e.(*fieldsError)
+		if !ok {
+			continue
+		}
+		for k, v := range fe.fields {
+			if _, exists := result[k]; !exists {
+				result[k] = v
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package shared_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+func TestWithFields(t *testing.T) {
+	t.Run("nil error", func(t *testing.T) {
+		assert.Nil(t, shared.WithFields(nil, map[string]any{"k": "v"}))
+	})
+
+	t.Run("empty fields returns original", func(t *testing.T) {
+		base := errors.New("original")
+		assert.Same(t, base, shared.WithFields(base, nil))
+	})
+
+	t.Run("message and chain preserved", func(t *testing.T) {
+		base := errors.New("original")
+		err := shared.WithFields(base, map[string]any{"user_id": 123})
+
+		require.NotNil(t, err)
+		assert.Equal(t, "original", err.Error())
+		assert.True(t, errors.Is(err, base))
+	})
+
+	t.Run("input map is copied", func(t *testing.T) {
+		fields := map[string]any{"op": "create"}
+		err := shared.WithFields(errors.New("original"), fields)
+		fields["op"] = "delete"
+
+		assert.Equal(t, "create", shared.FieldsOf(err)["op"])
+	})
+}
+
+func TestFieldsOf(t *testing.T) {
+	t.Run("nil error", func(t *testing.T) {
+		fields := shared.FieldsOf(nil)
+		require.NotNil(t, fields)
+		assert.Empty(t, fields)
+	})
+
+	t.Run("plain error", func(t *testing.T) {
+		fields := shared.FieldsOf(errors.New("plain"))
+		require.NotNil(t, fields)
+		assert.Empty(t, fields)
+	})
+
+	t.Run("merges through wrapping", func(t *testing.T) {
+		err := shared.WithFields(errors.New("db error"), map[string]any{"table": "users"})
+		err = shared.Wrap(err, "failed to create user")
+		err = shared.WithFields(err, map[string]any{"user_id": 123})
+
+		assert.Equal(t, shared.Fields{"table": "users", "user_id": 123}, shared.FieldsOf(err))
+	})
+
+	t.Run("outermost value wins", func(t *testing.T) {
+		err := shared.WithFields(errors.New("inner"), map[string]any{"op": "inner", "a": 1})
+		err = fmt.Errorf("context: %w", err)
+		err = shared.WithFields(err, map[string]any{"op": "outer"})
+
+		fields := shared.FieldsOf(err)
+		assert.Equal(t, "outer", fields["op"])
+		assert.Equal(t, 1, fields["a"])
+	})
+
+	t.Run("through MarkKind", func(t *testing.T) {
+		err := shared.WithFields(errors.New("no rows"), map[string]any{"id": 42})
+		err = shared.MarkKind(err, shared.KindNotFound)
+
+		assert.Equal(t, shared.KindNotFound, shared.KindOf(err))
+		assert.Equal(t, 42, shared.FieldsOf(err)["id"])
+	})
+
+	t.Run("through errors.Join", func(t *testing.T) {
+		err1 := shared.WithFields(errors.New("first"), map[string]any{"first": true})
+		err2 := shared.WithFields(errors.New("second"), map[string]any{"second": true})
+		err := errors.Join(err1, err2)
+
+		assert.Equal(t, shared.Fields{"first": true, "second": true}, shared.FieldsOf(err))
+	})
+
+	t.Run("kind preserved when fields are outermost", func(t *testing.T) {
+		err := shared.WithFields(shared.ErrValidation, map[string]any{"field": "email"})
+
+		assert.True(t, shared.IsValidation(err))
+		assert.Equal(t, shared.KindValidation, shared.KindOf(err))
+	})
+}
@@ -0,0 +1,36 @@
+// Package httpmap maps shared error kinds to HTTP status codes and back.
+//
+// Adapters use it instead of copy-pasting the same switch over shared.KindOf:
+//
+//	status := httpmap.HTTPStatusOf(shared.KindOf(err))
+//
+// HTTP clients use the reverse mapping to classify upstream responses:
+//
+//	kind := httpmap.KindFromHTTPStatus(resp.StatusCode)
+//	return shared.MarkKind(fmt.Errorf("unexpected status %d", resp.StatusCode), kind)
+//
+// Default table:
+//
+//	Kind                  | Status
+//	----------------------|--------------------------
+//	KindNotFound          | 404 Not Found
+//	KindValidation        | 400 Bad Request
+//	KindUnauthorized      | 401 Unauthorized
+//	KindForbidden         | 403 Forbidden
+//	KindConflict          | 409 Conflict
+//	KindTimeout           | 408 Request Timeout
+//	KindInvariantViolated | 422 Unprocessable Entity
+//	KindDependencyFailure | 502 Bad Gateway
+//	KindCanceled          | 499 Client Closed Request
+//	KindInternal          | 500 Internal Server Error
+//	KindUnknown           | 500 Internal Server Error
+//
+// The reverse mapping additionally treats 410 as KindNotFound, 429 and 503 as
+// KindDependencyFailure and 504 as KindTimeout. Other 5xx codes map to KindInternal,
+// other 4xx codes map to KindValidation.
+//
+// Use a Mapper to override individual entries:
+//
+//	mapper := httpmap.NewMapper().WithOverride(shared.KindTimeout, http.StatusGatewayTimeout)
+//	status := mapper.StatusOfError(err)
+package httpmap
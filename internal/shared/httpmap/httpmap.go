@@ -0,0 +1,137 @@
+package httpmap
+
+import (
+	"net/http"
+
+	"sttbot/internal/shared"
+)
+
+// StatusClientClosedRequest is the non-standard status used for canceled requests.
+const StatusClientClosedRequest = 499
+
+// defaultKindToStatus is the default table for Kind -> HTTP status mapping.
+var defaultKindToStatus = map[shared.Kind]int{
+	shared.KindUnknown:           http.StatusInternalServerError,
+	shared.KindNotFound:          http.StatusNotFound,
+	shared.KindValidation:        http.StatusBadRequest,
+	shared.KindUnauthorized:      http.StatusUnauthorized,
+	shared.KindForbidden:         http.StatusForbidden,
+	shared.KindConflict:          http.StatusConflict,
+	shared.KindInternal:          http.StatusInternalServerError,
+	shared.KindTimeout:           http.StatusRequestTimeout,
+	shared.KindInvariantViolated: http.StatusUnprocessableEntity,
+	shared.KindDependencyFailure: http.StatusBadGateway,
+	shared.KindCanceled:          StatusClientClosedRequest,
+}
+
+// defaultStatusToKind is the default table for HTTP status -> Kind mapping.
+// Codes not listed here fall back to KindValidation for 4xx and KindInternal for 5xx.
+var defaultStatusToKind = map[int]shared.Kind{
+	http.StatusBadRequest:          shared.KindValidation,
+	http.StatusUnauthorized:        shared.KindUnauthorized,
+	http.StatusForbidden:           shared.KindForbidden,
+	http.StatusNotFound:            shared.KindNotFound,
+	http.StatusRequestTimeout:      shared.KindTimeout,
+	http.StatusConflict:            shared.KindConflict,
+	http.StatusGone:                shared.KindNotFound,
+	http.StatusUnprocessableEntity: shared.KindInvariantViolated,
+	http.StatusTooManyRequests:     shared.KindDependencyFailure,
+	StatusClientClosedRequest:      shared.KindCanceled,
+	http.StatusInternalServerError: shared.KindInternal,
+	http.StatusBadGateway:          shared.KindDependencyFailure,
+	http.StatusServiceUnavailable:  shared.KindDependencyFailure,
+	http.StatusGatewayTimeout:      shared.KindTimeout,
+}
+
+// Mapper maps error kinds to HTTP status codes and back.
+// A Mapper is immutable: WithOverride returns a modified copy,
+// so the same Mapper can be shared between goroutines.
+type Mapper struct {
+	kindToStatus map[shared.Kind]int
+	statusToKind map[int]shared.Kind
+}
+
+// NewMapper creates a Mapper with the default mapping table.
+func NewMapper() *Mapper {
+	m := &Mapper{
+		kindToStatus: make(map[shared.Kind]int, len(defaultKindToStatus)),
+		statusToKind: make(map[int]shared.Kind, len(defaultStatusToKind)),
+	}
+	for k, v := range defaultKindToStatus {
+		m.kindToStatus[k] = v
+	}
+	for k, v := range defaultStatusToKind {
+		m.statusToKind[k] = v
+	}
+	return m
+}
+
+// WithOverride returns a copy of the Mapper where kind maps to status and status maps back to kind.
+// Overriding both directions keeps the round trip KindOf(StatusOf(kind)) == kind deterministic
+// for the overridden kind only. If another kind already maps to status, it keeps mapping
+// to status, but status now maps back to kind, so that kind loses its round trip;
+// override it as well to move it to a free status:
+//
+//	// 502 is taken by KindDependencyFailure, move it to 503
+//	m := httpmap.NewMapper().
+//		WithOverride(shared.KindTimeout, http.StatusBadGateway).
+//		WithOverride(shared.KindDependencyFailure, http.StatusServiceUnavailable)
+func (m *Mapper) WithOverride(kind shared.Kind, status int) *Mapper {
+	c := &Mapper{
+		kindToStatus: make(map[shared.Kind]int, len(m.kindToStatus)),
+		statusToKind: make(map[int]shared.Kind, len(m.statusToKind)+1),
+	}
+	for k, v := range m.kindToStatus {
+		c.kindToStatus[k] = v
+	}
+	for k, v := range m.statusToKind {
+		c.statusToKind[k] = v
+	}
+	c.kindToStatus[kind] = status
+	c.statusToKind[status] = kind
+	return c
+}
+
+// StatusOf returns the HTTP status code for the given Kind.
+// Unmapped kinds return 500 Internal Server Error.
+func (m *Mapper) StatusOf(kind shared.Kind) int {
+	if status, ok := m.kindToStatus[kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// KindOf returns the Kind for the given HTTP status code.
+// Unmapped 5xx codes return KindInternal, other unmapped 4xx codes return KindValidation.
+// Non-error codes (1xx-3xx) return KindUnknown.
+func (m *Mapper) KindOf(status int) shared.Kind {
+	if kind, ok := m.statusToKind[status]; ok {
+		return kind
+	}
+	switch {
+	case status >= 500 && status <= 599:
+		return shared.KindInternal
+	case status >= 400 && status <= 499:
+		return shared.KindValidation
+	default:
+		return shared.KindUnknown
+	}
+}
+
+// StatusOfError returns the HTTP status code for the Kind of the given error.
+func (m *Mapper) StatusOfError(err error) int {
+	return m.StatusOf(shared.KindOf(err))
+}
+
+// defaultMapper is used by package-level helpers.
+var defaultMapper = NewMapper()
+
+// HTTPStatusOf returns the HTTP status code for the given Kind using the default table.
+func HTTPStatusOf(kind shared.Kind) int {
+	return defaultMapper.StatusOf(kind)
+}
+
+// KindFromHTTPStatus returns the Kind for the given HTTP status code using the default table.
+func KindFromHTTPStatus(status int) shared.Kind {
+	return defaultMapper.KindOf(status)
+}
@@ -0,0 +1,128 @@
+package httpmap_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sttbot/internal/shared"
+	"sttbot/internal/shared/httpmap"
+)
+
+var allKinds = []shared.Kind{
+	shared.KindNotFound,
+	shared.KindValidation,
+	shared.KindUnauthorized,
+	shared.KindForbidden,
+	shared.KindConflict,
+	shared.KindInternal,
+	shared.KindTimeout,
+	shared.KindInvariantViolated,
+	shared.KindDependencyFailure,
+	shared.KindCanceled,
+}
+
+func TestHTTPStatusOf(t *testing.T) {
+	tests := []struct {
+		kind     shared.Kind
+		expected int
+	}{
+		{shared.KindUnknown, http.StatusInternalServerError},
+		{shared.KindNotFound, http.StatusNotFound},
+		{shared.KindValidation, http.StatusBadRequest},
+		{shared.KindUnauthorized, http.StatusUnauthorized},
+		{shared.KindForbidden, http.StatusForbidden},
+		{shared.KindConflict, http.StatusConflict},
+		{shared.KindInternal, http.StatusInternalServerError},
+		{shared.KindTimeout, http.StatusRequestTimeout},
+		{shared.KindInvariantViolated, http.StatusUnprocessableEntity},
+		{shared.KindDependencyFailure, http.StatusBadGateway},
+		{shared.KindCanceled, httpmap.StatusClientClosedRequest},
+		{shared.Kind(100), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind.String(), func(t *testing.T) {
+			assert.Equal(t, tt.expected, httpmap.HTTPStatusOf(tt.kind))
+		})
+	}
+}
+
+func TestKindFromHTTPStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		expected shared.Kind
+	}{
+		{"200 ok", http.StatusOK, shared.KindUnknown},
+		{"302 redirect", http.StatusFound, shared.KindUnknown},
+		{"400", http.StatusBadRequest, shared.KindValidation},
+		{"401", http.StatusUnauthorized, shared.KindUnauthorized},
+		{"403", http.StatusForbidden, shared.KindForbidden},
+		{"404", http.StatusNotFound, shared.KindNotFound},
+		{"409", http.StatusConflict, shared.KindConflict},
+		{"410", http.StatusGone, shared.KindNotFound},
+		{"429", http.StatusTooManyRequests, shared.KindDependencyFailure},
+		{"503", http.StatusServiceUnavailable, shared.KindDependencyFailure},
+		{"504", http.StatusGatewayTimeout, shared.KindTimeout},
+		{"unknown 4xx", http.StatusTeapot, shared.KindValidation},
+		{"unknown 5xx", http.StatusNotImplemented, shared.KindInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, httpmap.KindFromHTTPStatus(tt.status))
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, kind := range allKinds {
+		t.Run(kind.String(), func(t *testing.T) {
+			assert.Equal(t, kind, httpmap.KindFromHTTPStatus(httpmap.HTTPStatusOf(kind)))
+		})
+	}
+}
+
+func TestMapper_WithOverride(t *testing.T) {
+	base := httpmap.NewMapper()
+	m := base.WithOverride(shared.KindTimeout, http.StatusGatewayTimeout)
+
+	assert.Equal(t, http.StatusGatewayTimeout, m.StatusOf(shared.KindTimeout))
+	assert.Equal(t, shared.KindTimeout, m.KindOf(http.StatusGatewayTimeout))
+
+	// The original Mapper is not modified
+	assert.Equal(t, http.StatusRequestTimeout, base.StatusOf(shared.KindTimeout))
+
+	// Round trip stays deterministic after override
+	custom := base.WithOverride(shared.KindConflict, http.StatusPreconditionFailed)
+	for _, kind := range allKinds {
+		assert.Equal(t, kind, custom.KindOf(custom.StatusOf(kind)), kind.String())
+	}
+}
+
+func TestMapper_WithOverride_StatusOfAnotherKind(t *testing.T) {
+	// 502 already belongs to KindDependencyFailure
+	m := httpmap.NewMapper().WithOverride(shared.KindTimeout, http.StatusBadGateway)
+
+	assert.Equal(t, shared.KindTimeout, m.KindOf(m.StatusOf(shared.KindTimeout)))
+	// The previous owner keeps its status but no longer round-trips
+	assert.Equal(t, http.StatusBadGateway, m.StatusOf(shared.KindDependencyFailure))
+	assert.Equal(t, shared.KindTimeout, m.KindOf(m.StatusOf(shared.KindDependencyFailure)))
+
+	// Moving the previous owner to a free status restores its round trip
+	m = m.WithOverride(shared.KindDependencyFailure, http.StatusServiceUnavailable)
+	for _, kind := range allKinds {
+		assert.Equal(t, kind, m.KindOf(m.StatusOf(kind)), kind.String())
+	}
+}
+
+func TestMapper_StatusOfError(t *testing.T) {
+	m := httpmap.NewMapper()
+
+	err := shared.MarkKind(errors.New("no rows"), shared.KindNotFound)
+	assert.Equal(t, http.StatusNotFound, m.StatusOfError(err))
+	assert.Equal(t, http.StatusInternalServerError, m.StatusOfError(errors.New("plain")))
+}
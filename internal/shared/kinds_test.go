@@ -0,0 +1,117 @@
+package shared_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sttbot/internal/shared"
+)
+
+func TestKindsOf(t *testing.T) {
+	timeoutErr := &net.DNSError{Err: "i/o timeout", IsTimeout: true}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected []shared.Kind
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: []shared.Kind{},
+		},
+		{
+			name:     "unclassified error",
+			err:      errors.New("boom"),
+			expected: []shared.Kind{},
+		},
+		{
+			name:     "wrapped unclassified error",
+			err:      fmt.Errorf("load: %w", errors.New("boom")),
+			expected: []shared.Kind{},
+		},
+		{
+			name:     "single sentinel",
+			err:      shared.Wrap(shared.ErrNotFound, "user"),
+			expected: []shared.Kind{shared.KindNotFound},
+		},
+		{
+			name: "joined validation and conflict",
+			err: errors.Join(
+				shared.MarkKind(errors.New("bad email"), shared.KindValidation),
+				shared.MarkKind(errors.New("duplicate login"), shared.KindConflict),
+			),
+			expected: []shared.Kind{shared.KindValidation, shared.KindConflict},
+		},
+		{
+			name:     "priority order regardless of join order",
+			err:      errors.Join(shared.ErrInternal, shared.ErrConflict, shared.ErrNotFound),
+			expected: []shared.Kind{shared.KindNotFound, shared.KindConflict, shared.KindInternal},
+		},
+		{
+			name:     "duplicates removed",
+			err:      errors.Join(shared.ErrValidation, shared.Wrap(shared.ErrValidation, "again"), shared.ErrValidation),
+			expected: []shared.Kind{shared.KindValidation},
+		},
+		{
+			name:     "canceled and deadline",
+			err:      errors.Join(context.DeadlineExceeded, fmt.Errorf("stop: %w", context.Canceled)),
+			expected: []shared.Kind{shared.KindCanceled, shared.KindTimeout},
+		},
+		{
+			name:     "net timeout",
+			err:      errors.Join(fmt.Errorf("dial: %w", timeoutErr), shared.ErrDependencyFailure),
+			expected: []shared.Kind{shared.KindTimeout, shared.KindDependencyFailure},
+		},
+		{
+			name: "nested joins",
+			err: fmt.Errorf("save: %w", errors.Join(
+				shared.ErrUnauthorized,
+				errors.Join(shared.ErrInvariantViolated, shared.ErrForbidden),
+			)),
+			expected: []shared.Kind{shared.KindUnauthorized, shared.KindForbidden, shared.KindInvariantViolated},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kinds := shared.KindsOf(tt.err)
+			assert.NotNil(t, kinds)
+			assert.Equal(t, tt.expected, kinds)
+			if len(kinds) > 0 {
+				assert.Equal(t, shared.KindOf(tt.err), kinds[0], "first kind must match KindOf")
+			} else {
+				assert.Equal(t, shared.KindUnknown, shared.KindOf(tt.err))
+			}
+		})
+	}
+}
+
+func TestKindsOf_Deterministic(t *testing.T) {
+	err := errors.Join(shared.ErrConflict, shared.ErrTimeout, shared.ErrValidation, shared.ErrInternal)
+	first := shared.KindsOf(err)
+	for range 100 {
+		assert.Equal(t, first, shared.KindsOf(err))
+	}
+}
+
+func TestHasAnyKind(t *testing.T) {
+	joined := errors.Join(shared.ErrValidation, shared.ErrConflict)
+
+	assert.True(t, shared.HasAnyKind(joined, shared.KindConflict), "lower-priority kind must be found")
+	assert.True(t, shared.HasAnyKind(joined, shared.KindNotFound, shared.KindValidation))
+	assert.False(t, shared.HasAnyKind(joined, shared.KindNotFound, shared.KindTimeout))
+	assert.False(t, shared.HasAnyKind(joined), "no kinds never match")
+	assert.False(t, shared.HasAnyKind(joined, shared.KindUnknown))
+
+	// KindUnknown matches nil and unclassified errors, like HasKind
+	assert.True(t, shared.HasAnyKind(nil, shared.KindUnknown))
+	assert.False(t, shared.HasAnyKind(nil, shared.KindValidation))
+	assert.True(t, shared.HasAnyKind(errors.New("boom"), shared.KindUnknown))
+	assert.False(t, shared.HasAnyKind(errors.New("boom"), shared.KindInternal))
+}
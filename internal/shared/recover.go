@@ -0,0 +1,80 @@
+package shared
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// panicError is an error recovered from a panic.
+type panicError struct {
+	value any
+}
+
+// Error returns "panic: value".
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+// Unwrap returns ErrInternal and, if the panic value is an error, the value itself,
+// so KindOf reports KindInternal and errors.Is/As find the original error.
+func (e *panicError) Unwrap() []error {
+	if err, ok := e.value.(error); ok {
+		return []error{ErrInternal, err}
+	}
+	return []error{ErrInternal}
+}
+
+// Recover converts a panic into an error stored in *errp.
+// It must be deferred directly, so that its recover call stops the panic:
+//
+//	func (h *Handler) Handle(ctx context.Context) (err error) {
+//	    defer shared.Recover(&err)
+//	    ...
+//	}
+//
+// The resulting error formats as "panic: value", is of KindInternal and carries
+// the stack of the panic site, available through StackOf. If the panic value is an
+// error (including runtime.Error), errors.Is/As find it. A value previously stored
+// in *errp is replaced. If there is no panic, *errp is not changed.
+func Recover(errp *error) {
+	if r := recover(); r != nil {
+		*errp = newPanicError(r)
+	}
+}
+
+// Safe calls fn and returns its error, converting a panic into an error as Recover does.
+func Safe(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
+
+// SafeValue calls fn and returns its result, converting a panic into an error as Recover does.
+// On panic the zero value of T is returned.
+func SafeValue[T any](fn func() (T, error)) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			value, err = zero, newPanicError(r)
+		}
+	}()
+	return fn()
+}
+
+// newPanicError builds the recovered error with the stack of the panic site.
+// It must be called from the deferred function that called recover.
+func newPanicError(value any) error {
+	pcs := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers, newPanicError and the deferred function
+	n := runtime.Callers(3, pcs)
+	pcs = pcs[:n]
+	// Skip runtime panic machinery (gopanic, panicmem, sigpanic) above the panic site
+	for len(pcs) > 0 {
+		fn := runtime.FuncForPC(pcs[0] - 1)
+		if fn == nil || !strings.HasPrefix(fn.Name(), "runtime.") {
+			break
+		}
+		pcs = pcs[1:]
+	}
+	return &traceError{err: &panicError{value: value}, pcs: pcs}
+}
@@ -0,0 +1,102 @@
+package shared_test
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+func panicHelper(value any) (err error) {
+	defer shared.Recover(&err)
+	panic(value)
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("panic with string", func(t *testing.T) {
+		err := panicHelper("boom")
+
+		require.Error(t, err)
+		assert.Equal(t, "panic: boom", err.Error())
+		assert.True(t, shared.IsInternal(err))
+	})
+
+	t.Run("panic with error", func(t *testing.T) {
+		base := errors.New("original")
+		err := panicHelper(base)
+
+		require.Error(t, err)
+		assert.Equal(t, "panic: original", err.Error())
+		assert.True(t, errors.Is(err, base))
+		assert.True(t, shared.IsInternal(err))
+	})
+
+	t.Run("panic with nil", func(t *testing.T) {
+		err := panicHelper(nil)
+
+		require.Error(t, err)
+		var nilErr *runtime.PanicNilError
+		assert.True(t, errors.As(err, &nilErr))
+		assert.True(t, shared.IsInternal(err))
+	})
+
+	t.Run("runtime error is captured", func(t *testing.T) {
+		err := shared.Safe(func() error {
+			var m map[string]int
+			m["key"] = 1
+			return nil
+		})
+
+		require.Error(t, err)
+		var runtimeErr runtime.Error
+		assert.True(t, errors.As(err, &runtimeErr))
+		assert.True(t, shared.IsInternal(err))
+	})
+
+	t.Run("stack points to panic site", func(t *testing.T) {
+		frames := shared.StackOf(panicHelper("boom"))
+
+		require.NotEmpty(t, frames)
+		assert.True(t, strings.HasSuffix(frames[0].Function, "panicHelper"), frames[0].Function)
+		assert.True(t, strings.HasSuffix(frames[0].File, "recover_test.go"), frames[0].File)
+	})
+
+	t.Run("no panic keeps error", func(t *testing.T) {
+		base := errors.New("original")
+		err := func() (err error) {
+			defer shared.Recover(&err)
+			return base
+		}()
+		assert.Equal(t, base, err)
+	})
+}
+
+func TestSafe(t *testing.T) {
+	base := errors.New("original")
+	assert.NoError(t, shared.Safe(func() error { return nil }))
+	assert.Equal(t, base, shared.Safe(func() error { return base }))
+
+	err := shared.Safe(func() error { panic(base) })
+	assert.True(t, errors.Is(err, base))
+	assert.True(t, shared.IsInternal(err))
+}
+
+func TestSafeValue(t *testing.T) {
+	value, err := shared.SafeValue(func() (int, error) { return 42, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+
+	value, err = shared.SafeValue(func() (int, error) {
+		var items []int
+		return items[1], nil
+	})
+	require.Error(t, err)
+	assert.Zero(t, value)
+	assert.True(t, strings.HasPrefix(err.Error(), "panic: runtime error: index out of range"), err.Error())
+	assert.True(t, shared.IsInternal(err))
+}
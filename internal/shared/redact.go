@@ -0,0 +1,115 @@
+package shared
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RedactedPlaceholder replaces sensitive data in redacted error messages.
+const RedactedPlaceholder = "[REDACTED]"
+
+// DefaultRedactions is the pattern set used by Redact when no patterns are given and by SafeError.
+// Patterns with capturing groups have only the groups replaced, so the surrounding
+// context like "password=" or "Bearer" stays readable.
+var DefaultRedactions = []*regexp.Regexp{
+	// Authorization: Bearer <token>
+	regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`),
+	// Telegram bot tokens, e.g. /bot123456789:AAH.../getMe
+	regexp.MustCompile(`\d{6,12}:[A-Za-z0-9_-]{30,}`),
+	// Credentials in query strings and form bodies
+	regexp.MustCompile(`(?i)\b(?:password|passwd|pwd|token|access_token|api_key|apikey|secret)=([^&\s"']+)`),
+}
+
+// redactedError replaces the message of the wrapped error with its scrubbed version.
+type redactedError struct {
+	err     error
+	message string
+}
+
+// Error returns the scrubbed message.
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the original, unredacted error: errors.Is/As and KindOf work
+// on it, so only Error() of this wrapper is safe to log.
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// Redact returns an error whose Error() output has all pattern matches replaced with
+// RedactedPlaceholder. If no patterns are given, DefaultRedactions is used.
+// The original chain is preserved, so errors.Is/As, KindOf and other helpers keep
+// working; note that errors extracted with errors.As still carry unredacted text.
+// Wrapping the result with Wrap, Wrapf, MarkKind or errors.Join keeps the redaction.
+// If err is nil, Redact returns nil.
+//
+// Example:
+//
+//	resp, err := client.Do(ctx, req)
+//	if err != nil {
+//	    return shared.Redact(shared.MarkKind(err, shared.KindDependencyFailure))
+//	}
+func Redact(err error, patterns ...*regexp.Regexp) error {
+	if err == nil {
+		return nil
+	}
+	if len(patterns) == 0 {
+		patterns = DefaultRedactions
+	}
+	return &redactedError{err: err, message: redactText(err.Error(), patterns)}
+}
+
+// SafeError returns the error message scrubbed with DefaultRedactions,
+// for log call sites that only need the text.
+// If err is nil, SafeError returns an empty string.
+//
+// Example:
+//
+//	logger.Warn("telegram request failed", "error", shared.SafeError(err))
+func SafeError(err error) string {
+	if err == nil {
+		return ""
+	}
+	return redactText(err.Error(), DefaultRedactions)
+}
+
+// redactText applies patterns to s in order.
+func redactText(s string, patterns []*regexp.Regexp) string {
+	for _, re := range patterns {
+		if re != nil {
+			s = replaceMatches(s, re)
+		}
+	}
+	return s
+}
+
+// replaceMatches replaces every match of re in s with RedactedPlaceholder.
+// If re has capturing groups, only the participating groups are replaced.
+func replaceMatches(s string, re *regexp.Regexp) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		spans := m[:2]
+		if len(m) > 2 {
+			spans = m[2:]
+		}
+		for i := 0; i+1 < len(spans); i += 2 {
+			start, end := spans[i], spans[i+1]
+			// Skip groups that did not participate and groups nested in a replaced one
+			if start < 0 || start < last {
+				continue
+			}
+			b.WriteString(s[last:start])
+			b.WriteString(RedactedPlaceholder)
+			last = end
+		}
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package shared
+
+import "errors"
+
+// retryableError marks an error as safe to retry without changing its message.
+type retryableError struct {
+	err error
+}
+
+// Error returns the message of the wrapped error unchanged.
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the marked error, so the marker does not hide its kind:
+// IsRetryable still rejects canceled errors that were marked retryable.
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// MarkRetryable marks an error as safe to retry.
+// The error message is not changed, and the marker survives Wrap, Wrapf, MarkKind and errors.Join.
+// If err is nil, MarkRetryable returns nil.
+// If err is already explicitly retryable, it is returned unchanged.
+//
+// Example:
+//
+//	if resp.StatusCode == http.StatusTooManyRequests {
+//	    return shared.MarkRetryable(shared.MarkKind(err, shared.KindDependencyFailure))
+//	}
+func MarkRetryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	if IsExplicitlyRetryable(err) {
+		return err
+	}
+	return &retryableError{err: err}
+}
+
+// IsExplicitlyRetryable reports whether the error chain contains a MarkRetryable marker.
+func IsExplicitlyRetryable(err error) bool {
+	var re *retryableError
+	return errors.As(err, &re)
+}
+
+// IsRetryable reports whether the error is safe to retry.
+// It returns true for errors marked with MarkRetryable and for KindTimeout and
+// KindDependencyFailure errors. Canceled errors are never retryable, even if marked.
+func IsRetryable(err error) bool {
+	if err == nil || IsCanceled(err) {
+		return false
+	}
+	if IsExplicitlyRetryable(err) {
+		return true
+	}
+	return IsTimeout(err) || IsDependencyFailure(err)
+}
@@ -0,0 +1,98 @@
+package shared_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sttbot/internal/shared"
+)
+
+func TestMarkRetryable(t *testing.T) {
+	t.Run("nil error", func(t *testing.T) {
+		assert.Nil(t, shared.MarkRetryable(nil))
+	})
+
+	t.Run("message and chain preserved", func(t *testing.T) {
+		base := errors.New("connection reset")
+		err := shared.MarkRetryable(base)
+
+		assert.Equal(t, "connection reset", err.Error())
+		assert.ErrorIs(t, err, base)
+		assert.True(t, shared.IsExplicitlyRetryable(err))
+		assert.True(t, shared.IsRetryable(err))
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		err := shared.MarkRetryable(errors.New("boom"))
+		assert.Same(t, err, shared.MarkRetryable(err))
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	base := errors.New("boom")
+
+	tests := []struct {
+		name       string
+		err        error
+		retryable  bool
+		explicitly bool
+	}{
+		{name: "nil", err: nil},
+		{name: "plain error", err: base},
+		{name: "validation", err: shared.MarkKind(base, shared.KindValidation)},
+		{name: "timeout kind", err: shared.MarkKind(base, shared.KindTimeout), retryable: true},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, retryable: true},
+		{name: "dependency failure", err: shared.MarkKind(base, shared.KindDependencyFailure), retryable: true},
+		{name: "canceled", err: context.Canceled},
+		{name: "marked canceled", err: shared.MarkRetryable(context.Canceled), explicitly: true},
+		{
+			name:       "marked then wrapped",
+			err:        shared.Wrapf(shared.Wrap(shared.MarkRetryable(base), "fetch"), "attempt %d", 2),
+			retryable:  true,
+			explicitly: true,
+		},
+		{
+			name:       "marked then kind",
+			err:        shared.MarkKind(shared.MarkRetryable(base), shared.KindInternal),
+			retryable:  true,
+			explicitly: true,
+		},
+		{
+			name:       "kind then marked",
+			err:        shared.MarkRetryable(shared.MarkKind(base, shared.KindConflict)),
+			retryable:  true,
+			explicitly: true,
+		},
+		{
+			name:       "joined with marked branch",
+			err:        errors.Join(shared.MarkKind(base, shared.KindValidation), shared.Wrap(shared.MarkRetryable(errors.New("reset")), "upload")),
+			retryable:  true,
+			explicitly: true,
+		},
+		{
+			name:      "joined with dependency failure branch",
+			err:       errors.Join(base, shared.MarkKind(errors.New("upstream"), shared.KindDependencyFailure)),
+			retryable: true,
+		},
+		{
+			name: "joined without retryable branches",
+			err:  errors.Join(base, shared.MarkKind(errors.New("bad"), shared.KindValidation)),
+		},
+		{
+			name:       "marked join",
+			err:        shared.Wrap(shared.MarkRetryable(errors.Join(base, errors.New("other"))), "batch"),
+			retryable:  true,
+			explicitly: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, shared.IsRetryable(tt.err))
+			assert.Equal(t, tt.explicitly, shared.IsExplicitlyRetryable(tt.err))
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package shared
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// maxStackDepth limits the number of frames captured by WrapTrace.
+const maxStackDepth = 32
+
+// Frame describes a single call stack frame captured by WrapTrace.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// String returns the frame formatted as "function file:line".
+func (f Frame) String() string {
+	return fmt.Sprintf("%s %s:%d", f.Function, f.File, f.Line)
+}
+
+// traceError wraps an error with context and the call stack captured at wrap time.
+type traceError struct {
+	context string
+	err     error
+	pcs     []uintptr
+}
+
+// Error returns "context: err" without any stack information.
+func (e *traceError) Error() string {
+	if e.context == "" {
+		return e.err.Error()
+	}
+	return e.context + ": " + e.err.Error()
+}
+
+// Unwrap returns the error the trace was captured for. The context message is
+// part of this link, so Cause skips it and returns the error itself.
+func (e *traceError) Unwrap() error {
+	return e.err
+}
+
+// WrapTrace wraps an error with additional context and captures the call stack.
+// The error formats as "context: err", the stack is available only through StackOf.
+// The stack is captured once per chain: if err already carries a trace,
+// WrapTrace behaves like Wrap and the inner trace is kept.
+// If err is nil, WrapTrace returns nil without capturing anything.
+//
+// Example:
+//
+//	if err := repo.GetUser(ctx, id); err != nil {
+//	    return shared.WrapTrace(err, "failed to get user")
+//	}
+func WrapTrace(err error, context string) error {
+	if err == nil {
+		return nil
+	}
+	if hasTrace(err) {
+		return Wrap(err, context)
+	}
+
+	pcs := make([]uintptr, maxStackDepth)
+	// Skip runtime.Callers and WrapTrace itself
+	n := runtime.Callers(2, pcs)
+	return &traceError{context: context, err: err, pcs: pcs[:n]}
+}
+
+// StackOf returns the deepest call stack captured by WrapTrace in the error chain.
+// Returns nil if no stack was captured or err is nil.
+//
+// Example of logging the stack:
+//
+//	for _, f := range shared.StackOf(err) {
+//	    logger.Debug("stack frame", "func", f.Function, "file", f.File, "line", f.Line)
+//	}
+func StackOf(err error) []Frame {
+	var deepest *traceError
+	for _, e := range UnwrapAll(err) {
+		if te, ok := e.(*traceError); ok {
+			deepest = te
+		}
+	}
+	if deepest == nil || len(deepest.pcs) == 0 {
+		return nil
+	}
+
+	frames := make([]Frame, 0, len(deepest.pcs))
+	callersFrames := runtime.CallersFrames(deepest.pcs)
+	for {
+		f, more := callersFrames.Next()
+		frames = append(frames, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// hasTrace reports whether the error chain already contains a captured stack.
+func hasTrace(err error) bool {
+	for _, e := range UnwrapAll(err) {
+		if _, ok := e.(*traceError); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package shared_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+func wrapTraceHelper(err error) error {
+	return shared.WrapTrace(err, "inner")
+}
+
+func TestWrapTrace(t *testing.T) {
+	t.Run("nil error", func(t *testing.T) {
+		assert.Nil(t, shared.WrapTrace(nil, "context"))
+	})
+
+	t.Run("message has no trace noise", func(t *testing.T) {
+		base := errors.New("original")
+		err := shared.WrapTrace(base, "wrapper")
+
+		require.NotNil(t, err)
+		assert.Equal(t, "wrapper: original", err.Error())
+		assert.True(t, errors.Is(err, base))
+		assert.Equal(t, base, errors.Unwrap(err))
+	})
+
+	t.Run("empty context", func(t *testing.T) {
+		err := shared.WrapTrace(errors.New("original"), "")
+		assert.Equal(t, "original", err.Error())
+		assert.NotEmpty(t, shared.StackOf(err))
+	})
+
+	t.Run("kind preserved", func(t *testing.T) {
+		err := shared.WrapTrace(shared.ErrNotFound, "lookup")
+		assert.True(t, shared.IsNotFound(err))
+	})
+}
+
+func TestStackOf(t *testing.T) {
+	t.Run("nil and plain errors", func(t *testing.T) {
+		assert.Nil(t, shared.StackOf(nil))
+		assert.Nil(t, shared.StackOf(errors.New("plain")))
+	})
+
+	t.Run("frames point to caller", func(t *testing.T) {
+		err := wrapTraceHelper(errors.New("original"))
+
+		frames := shared.StackOf(err)
+		require.NotEmpty(t, frames)
+		assert.True(t, strings.HasSuffix(frames[0].Function, "wrapTraceHelper"), frames[0].Function)
+		assert.True(t, strings.HasSuffix(frames[0].File, "stack_test.go"), frames[0].File)
+		assert.Positive(t, frames[0].Line)
+	})
+
+	t.Run("inner trace wins", func(t *testing.T) {
+		err := wrapTraceHelper(errors.New("original"))
+		err = shared.WrapTrace(err, "outer")
+
+		assert.Equal(t, "outer: inner: original", err.Error())
+		frames := shared.StackOf(err)
+		require.NotEmpty(t, frames)
+		assert.True(t, strings.HasSuffix(frames[0].Function, "wrapTraceHelper"), frames[0].Function)
+	})
+
+	t.Run("survives wrapping and marking", func(t *testing.T) {
+		err := wrapTraceHelper(errors.New("original"))
+		err = shared.MarkKind(shared.Wrap(err, "context"), shared.KindInternal)
+
+		assert.NotEmpty(t, shared.StackOf(err))
+	})
+}
@@ -0,0 +1,64 @@
+package shared
+
+// userMessageError attaches a user-facing message to an error without changing its message.
+type userMessageError struct {
+	err     error
+	message string
+}
+
+// Error returns the message of the wrapped error unchanged, so internal logs are not affected.
+func (e *userMessageError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error. The user-facing message stays on this
+// wrapper, so outer UserMessage calls find it before any inner message.
+func (e *userMessageError) Unwrap() error {
+	return e.err
+}
+
+// WithUserMessage attaches a friendly user-facing message to an error.
+// The message does not appear in Error() output; it can be extracted with UserMessage.
+// It survives Wrap, Wrapf, MarkKind and errors.Join.
+// If err is nil, WithUserMessage returns nil.
+// If msg is empty, returns the original error.
+//
+// Example:
+//
+//	if errors.Is(err, ErrQuotaExceeded) {
+//	    return shared.WithUserMessage(err, "You have used all transcriptions for today")
+//	}
+func WithUserMessage(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	if msg == "" {
+		return err
+	}
+	return &userMessageError{err: err, message: msg}
+}
+
+// UserMessage returns the outermost user-facing message attached to the error chain.
+// For errors.Join, branches are searched in order.
+// Returns false if no message is attached or err is nil.
+func UserMessage(err error) (string, bool) {
+	for _, e := range UnwrapAll(err) {
+		if ue, ok := e.(*userMessageError); ok {
+			return ue.message, true
+		}
+	}
+	return "", false
+}
+
+// UserMessageOr returns the user-facing message attached to the error chain,
+// or fallback if there is none.
+//
+// Example:
+//
+//	_, _ = bot.Send(chatID, shared.UserMessageOr(err, "Something went wrong, please try again later"))
+func UserMessageOr(err error, fallback string) string {
+	if msg, ok := UserMessage(err); ok {
+		return msg
+	}
+	return fallback
+}
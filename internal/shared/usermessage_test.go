@@ -0,0 +1,80 @@
+package shared_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sttbot/internal/shared"
+)
+
+func TestWithUserMessage(t *testing.T) {
+	t.Run("nil error", func(t *testing.T) {
+		assert.Nil(t, shared.WithUserMessage(nil, "oops"))
+	})
+
+	t.Run("empty message returns original", func(t *testing.T) {
+		base := errors.New("original")
+		assert.Same(t, base, shared.WithUserMessage(base, ""))
+	})
+
+	t.Run("message not in Error output", func(t *testing.T) {
+		base := errors.New("pq: connection refused")
+		err := shared.WithUserMessage(base, "Service is temporarily unavailable")
+
+		assert.Equal(t, "pq: connection refused", err.Error())
+		assert.ErrorIs(t, err, base)
+	})
+}
+
+func TestUserMessage(t *testing.T) {
+	base := errors.New("db timeout")
+
+	t.Run("no message", func(t *testing.T) {
+		msg, ok := shared.UserMessage(base)
+		assert.False(t, ok)
+		assert.Empty(t, msg)
+
+		_, ok = shared.UserMessage(nil)
+		assert.False(t, ok)
+	})
+
+	t.Run("survives wrap and mark kind", func(t *testing.T) {
+		err := shared.WithUserMessage(base, "Try again later")
+		err = shared.Wrap(err, "load profile")
+		err = shared.MarkKind(err, shared.KindTimeout)
+		err = shared.Wrapf(err, "handle update %d", 42)
+
+		msg, ok := shared.UserMessage(err)
+		require.True(t, ok)
+		assert.Equal(t, "Try again later", msg)
+		assert.True(t, shared.IsTimeout(err))
+		assert.Equal(t, "handle update 42: operation timed out: load profile: db timeout", err.Error())
+	})
+
+	t.Run("outermost message wins", func(t *testing.T) {
+		inner := shared.WithUserMessage(base, "inner message")
+		outer := shared.WithUserMessage(shared.Wrap(inner, "context"), "outer message")
+
+		assert.Equal(t, "outer message", shared.UserMessageOr(outer, "fallback"))
+	})
+
+	t.Run("joined errors with one branch carrying message", func(t *testing.T) {
+		err := errors.Join(
+			shared.MarkKind(errors.New("invalid chat id"), shared.KindValidation),
+			shared.Wrap(shared.WithUserMessage(base, "Audio is too long"), "transcribe"),
+		)
+
+		msg, ok := shared.UserMessage(shared.Wrap(err, "process"))
+		require.True(t, ok)
+		assert.Equal(t, "Audio is too long", msg)
+		assert.NotContains(t, err.Error(), "Audio is too long")
+	})
+
+	t.Run("joined errors without message", func(t *testing.T) {
+		err := errors.Join(base, errors.New("other"))
+		assert.Equal(t, "fallback", shared.UserMessageOr(err, "fallback"))
+	})
+}
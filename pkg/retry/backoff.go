@@ -0,0 +1,299 @@
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// defaultBackoffInitial is the first delay of Exponential and Fibonacci when Initial is zero
+const defaultBackoffInitial = 100 * time.Millisecond
+
+// maxDuration is the largest representable delay, used when a backoff has no cap
+const maxDuration = time.Duration(math.MaxInt64)
+
+// Backoff computes the delay before the next attempt.
+//
+// Next is called after a failed attempt (attempt starts at 1) with its error and
+// returns the delay before the following attempt, or false to stop retrying.
+// Set Config.Backoff to use a Backoff instead of the legacy delay fields.
+type Backoff interface {
+	Next(attempt int, err error) (time.Duration, bool)
+}
+
+// Exponential grows the delay as Initial * Multiplier^(attempt-1), capped at Max.
+// The zero value starts at 100ms and doubles without a cap.
+type Exponential struct {
+	// Initial is the delay after the first attempt (defaults to 100ms)
+	Initial time.Duration
+	// Max caps the delay (0 = no cap)
+	Max time.Duration
+	// Multiplier is the growth factor (defaults to 2.0, values below 1 are treated as 1)
+	Multiplier float64
+}
+
+// Next returns the exponential delay for the attempt; it never stops retrying
+func (e Exponential) Next(attempt int, _ error) (time.Duration, bool) {
+	initial := e.Initial
+	if initial <= 0 {
+		initial = defaultBackoffInitial
+	}
+	maxDelay := e.Max
+	if maxDelay <= 0 {
+		maxDelay = maxDuration
+	}
+	multiplier := e.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2.0
+	}
+	multiplier = math.Max(multiplier, 1.0)
+
+	delay := initial
+	for i := 1; i < attempt; i++ {
+		// Check for overflow before multiplication
+		if float64(delay)*multiplier >= float64(maxDelay) {
+			return maxDelay, true
+		}
+		delay = time.Duration(float64(delay) * multiplier)
+	}
+	return min(delay, maxDelay), true
+}
+
+// Constant waits the same Delay before every retry
+type Constant struct {
+	Delay time.Duration
+}
+
+// Next returns Delay (negative values are treated as zero)
+func (c Constant) Next(int, error) (time.Duration, bool) {
+	return max(c.Delay, 0), true
+}
+
+// Fibonacci grows the delay as Initial * F(attempt) with F = 1, 1, 2, 3, 5, 8, ...,
+// capped at Max. It grows slower than doubling. The zero value starts at 100ms without a cap.
+type Fibonacci struct {
+	// Initial is the delay after the first and second attempts (defaults to 100ms)
+	Initial time.Duration
+	// Max caps the delay (0 = no cap)
+	Max time.Duration
+}
+
+// Next returns the Fibonacci delay for the attempt; it never stops retrying
+func (f Fibonacci) Next(attempt int, _ error) (time.Duration, bool) {
+	initial := f.Initial
+	if initial <= 0 {
+		initial = defaultBackoffInitial
+	}
+	maxDelay := f.Max
+	if maxDelay <= 0 {
+		maxDelay = maxDuration
+	}
+
+	prev, delay := time.Duration(0), initial
+	for i := 1; i < attempt; i++ {
+		if delay > maxDelay-prev {
+			return maxDelay, true
+		}
+		prev, delay = delay, prev+delay
+	}
+	return min(delay, maxDelay), true
+}
+
+// backoffRunner is implemented by stateful decorators. Do calls run once per call
+// to get a fresh instance bound to the call's random source, clock and start time.
+type backoffRunner interface {
+	run(c *Config, start time.Time) Backoff
+}
+
+// backoffStopper reports why a Backoff returned false, if it knows
+type backoffStopper interface {
+	stopReason() StopReason
+}
+
+// startBackoff returns the per-call instance of b
+func startBackoff(b Backoff, c *Config, start time.Time) Backoff {
+	if r, ok := b.(backoffRunner); ok {
+		return r.run(c, start)
+	}
+	return b
+}
+
+// backoffStopReason returns the stop reason reported by b, or 0 if unknown
+func backoffStopReason(b Backoff) StopReason {
+	if s, ok := b.(backoffStopper); ok {
+		return s.stopReason()
+	}
+	return 0
+}
+
+// WithJitter randomizes the delays of b with the given strategy. With d the delay of b:
+//
+//	JitterNone:         d
+//	JitterFull:         uniform [0, d]
+//	JitterEqual:        d/2 + uniform [0, d/2]
+//	JitterDecorrelated: uniform [first, min(3*prev, d)], first is the delay of b after
+//	                    the first attempt, prev is the previous jittered delay (first at first)
+//
+// The jittered delay never exceeds d. Inside Do the random source is Config.Rand
+// and the state is kept per call; the returned Backoff is safe for concurrent use.
+func WithJitter(b Backoff, strategy JitterStrategy) Backoff {
+	return &jitterBackoff{
+		inner:    b,
+		strategy: strategy,
+		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// jitterBackoff is the Backoff returned by WithJitter
+type jitterBackoff struct {
+	inner    Backoff
+	strategy JitterStrategy
+
+	mu      sync.Mutex
+	rand    *rand.Rand
+	started bool          // first is known
+	first   time.Duration // delay of inner after the first attempt
+	prev    time.Duration // previous jittered delay
+}
+
+func (j *jitterBackoff) run(c *Config, start time.Time) Backoff {
+	return &jitterBackoff{
+		inner:    startBackoff(j.inner, c, start),
+		strategy: j.strategy,
+		rand:     c.Rand,
+	}
+}
+
+func (j *jitterBackoff) stopReason() StopReason {
+	return backoffStopReason(j.inner)
+}
+
+// Next returns the jittered delay of the wrapped Backoff
+func (j *jitterBackoff) Next(attempt int, err error) (time.Duration, bool) {
+	delay, ok := j.inner.Next(attempt, err)
+	if !ok {
+		return 0, false
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	// The first delay is remembered rather than asked again: the inner Backoff
+	// may be stateful (WithMaxElapsed) and must see each attempt once.
+	if attempt == 1 || !j.started {
+		j.started, j.first, j.prev = true, delay, 0
+	}
+	j.prev = jitter(j.rand, j.strategy, delay, j.first, j.prev)
+	return j.prev, true
+}
+
+// jitter applies strategy to delay as documented on WithJitter.
+// first and prev are used by JitterDecorrelated only; prev <= 0 means no previous delay.
+func jitter(r *rand.Rand, strategy JitterStrategy, delay, first, prev time.Duration) time.Duration {
+	switch strategy {
+	case JitterFull:
+		return randBetween(r, 0, delay)
+	case JitterEqual:
+		return randBetween(r, delay/2, delay)
+	case JitterDecorrelated:
+		if prev <= 0 {
+			prev = first
+		}
+		return randBetween(r, min(first, delay), min(3*prev, delay))
+	default:
+		return delay
+	}
+}
+
+// randBetween returns a uniform random duration in [lo, hi] (lo if the range is empty)
+func randBetween(r *rand.Rand, lo, hi time.Duration) time.Duration {
+	if hi <= lo {
+		return lo
+	}
+	return lo + time.Duration(r.Int63n(int64(hi-lo)+1))
+}
+
+// clampBackoff keeps the delays of the wrapped Backoff within [min, max]
+type clampBackoff struct {
+	inner    Backoff
+	min, max time.Duration
+}
+
+func (c clampBackoff) run(cfg *Config, start time.Time) Backoff {
+	return clampBackoff{inner: startBackoff(c.inner, cfg, start), min: c.min, max: c.max}
+}
+
+func (c clampBackoff) stopReason() StopReason {
+	return backoffStopReason(c.inner)
+}
+
+// Next returns the clamped delay of the wrapped Backoff
+func (c clampBackoff) Next(attempt int, err error) (time.Duration, bool) {
+	delay, ok := c.inner.Next(attempt, err)
+	if !ok {
+		return 0, false
+	}
+	return clamp(delay, c.min, c.max), true
+}
+
+// WithMaxElapsed stops retrying once the elapsed time plus the next delay of b would
+// exceed maxElapsed. Inside Do the time is measured with Config.Now from the start
+// of the call and Do returns a RetriesExceededError with StopReason MaxElapsed;
+// outside Do it is measured with time.Now from the first call of Next with attempt 1.
+func WithMaxElapsed(b Backoff, maxElapsed time.Duration) Backoff {
+	return &maxElapsedBackoff{inner: b, maxElapsed: maxElapsed, now: time.Now}
+}
+
+// maxElapsedBackoff is the Backoff returned by WithMaxElapsed
+type maxElapsedBackoff struct {
+	inner      Backoff
+	maxElapsed time.Duration
+	now        func() time.Time
+
+	mu       sync.Mutex
+	start    time.Time
+	started  bool // start is fixed by Do and not reset on attempt 1
+	exceeded bool
+}
+
+func (m *maxElapsedBackoff) run(c *Config, start time.Time) Backoff {
+	return &maxElapsedBackoff{
+		inner:      startBackoff(m.inner, c, start),
+		maxElapsed: m.maxElapsed,
+		now:        c.Now,
+		start:      start,
+		started:    true,
+	}
+}
+
+func (m *maxElapsedBackoff) stopReason() StopReason {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.exceeded {
+		return MaxElapsed
+	}
+	return backoffStopReason(m.inner)
+}
+
+// Next returns the delay of the wrapped Backoff, or false if it would exceed the limit
+func (m *maxElapsedBackoff) Next(attempt int, err error) (time.Duration, bool) {
+	delay, ok := m.inner.Next(attempt, err)
+	if !ok {
+		return 0, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if attempt == 1 && !m.started {
+		m.start = now
+		m.exceeded = false
+	}
+	if now.Sub(m.start)+delay > m.maxElapsed {
+		m.exceeded = true
+		return 0, false
+	}
+	return delay, true
+}
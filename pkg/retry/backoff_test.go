@@ -0,0 +1,290 @@
+package retry
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// transientErrors returns n retryable errors for recordDelays
+func transientErrors(n int) []error {
+	errs := make([]error, n)
+	for i := range errs {
+		errs[i] = errors.New("transient")
+	}
+	return errs
+}
+
+func TestBackoffNext(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name    string
+		backoff Backoff
+		want    []time.Duration
+	}{
+		{
+			name:    "exponential",
+			backoff: Exponential{Initial: 100 * ms, Max: time.Second, Multiplier: 2},
+			want:    []time.Duration{100 * ms, 200 * ms, 400 * ms, 800 * ms, time.Second, time.Second},
+		},
+		{
+			name:    "exponential defaults",
+			backoff: Exponential{},
+			want:    []time.Duration{100 * ms, 200 * ms, 400 * ms},
+		},
+		{
+			name:    "exponential fractional multiplier",
+			backoff: Exponential{Initial: 100 * ms, Max: 300 * ms, Multiplier: 1.5},
+			want:    []time.Duration{100 * ms, 150 * ms, 225 * ms, 300 * ms},
+		},
+		{
+			name:    "constant",
+			backoff: Constant{Delay: 50 * ms},
+			want:    []time.Duration{50 * ms, 50 * ms, 50 * ms},
+		},
+		{
+			name:    "fibonacci",
+			backoff: Fibonacci{Initial: 10 * ms, Max: 60 * ms},
+			want:    []time.Duration{10 * ms, 10 * ms, 20 * ms, 30 * ms, 50 * ms, 60 * ms, 60 * ms},
+		},
+		{
+			name:    "fibonacci defaults",
+			backoff: Fibonacci{},
+			want:    []time.Duration{100 * ms, 100 * ms, 200 * ms, 300 * ms},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, want := range tt.want {
+				got, ok := tt.backoff.Next(i+1, nil)
+				if !ok || got != want {
+					t.Errorf("Next(%d) = %v, %v; want %v, true", i+1, got, ok, want)
+				}
+			}
+		})
+	}
+
+	// No overflow without a cap
+	if d, _ := (Exponential{}).Next(200, nil); d != maxDuration {
+		t.Errorf("Exponential.Next(200) = %v, want %v", d, maxDuration)
+	}
+	if d, _ := (Fibonacci{}).Next(200, nil); d != maxDuration {
+		t.Errorf("Fibonacci.Next(200) = %v, want %v", d, maxDuration)
+	}
+}
+
+// TestBackoffEquivalentToLegacyFields checks that the documented configurations give
+// the same delays and errors whether they use the legacy fields or Config.Backoff.
+func TestBackoffEquivalentToLegacyFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		legacy  Config
+		backoff Backoff
+		errs    int
+	}{
+		{
+			name:    "exponential",
+			legacy:  Config{InitialDelay: 200 * time.Millisecond, MaxDelay: 10 * time.Second, Multiplier: 2},
+			backoff: Exponential{Initial: 200 * time.Millisecond, Max: 10 * time.Second, Multiplier: 2},
+			errs:    8,
+		},
+		{
+			name: "full jitter",
+			legacy: Config{
+				InitialDelay:   200 * time.Millisecond,
+				MinDelay:       time.Nanosecond,
+				MaxDelay:       10 * time.Second,
+				JitterStrategy: JitterFull,
+			},
+			backoff: WithJitter(Exponential{Initial: 200 * time.Millisecond, Max: 10 * time.Second}, JitterFull),
+			errs:    8,
+		},
+		{
+			name: "equal jitter",
+			legacy: Config{
+				InitialDelay:   200 * time.Millisecond,
+				MinDelay:       time.Nanosecond,
+				MaxDelay:       10 * time.Second,
+				JitterStrategy: JitterEqual,
+			},
+			backoff: WithJitter(Exponential{Initial: 200 * time.Millisecond, Max: 10 * time.Second}, JitterEqual),
+			errs:    8,
+		},
+		{
+			name:    "max elapsed time",
+			legacy:  Config{InitialDelay: 200 * time.Millisecond, MaxDelay: 10 * time.Second, MaxElapsedTime: 5 * time.Second},
+			backoff: WithMaxElapsed(Exponential{Initial: 200 * time.Millisecond, Max: 10 * time.Second}, 5*time.Second),
+			errs:    10,
+		},
+		{
+			name: "max elapsed time with jitter",
+			legacy: Config{
+				InitialDelay:   200 * time.Millisecond,
+				MinDelay:       time.Nanosecond,
+				MaxDelay:       10 * time.Second,
+				MaxElapsedTime: 5 * time.Second,
+				JitterStrategy: JitterEqual,
+			},
+			backoff: WithMaxElapsed(WithJitter(Exponential{Initial: 200 * time.Millisecond, Max: 10 * time.Second}, JitterEqual), 5*time.Second),
+			errs:    10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			legacy := tt.legacy
+			legacy.Rand = rand.New(rand.NewSource(1))
+			wantDelays, wantErr := recordDelays(t, legacy, transientErrors(tt.errs))
+
+			cfg := Config{Backoff: tt.backoff, Rand: rand.New(rand.NewSource(1))}
+			gotDelays, gotErr := recordDelays(t, cfg, transientErrors(tt.errs))
+
+			if len(gotDelays) != len(wantDelays) {
+				t.Fatalf("delays = %v, want %v", gotDelays, wantDelays)
+			}
+			for i := range wantDelays {
+				if gotDelays[i] != wantDelays[i] {
+					t.Errorf("delay[%d] = %v, want %v", i, gotDelays[i], wantDelays[i])
+				}
+			}
+
+			wantReason, wantOK := StopReasonOf(wantErr)
+			gotReason, gotOK := StopReasonOf(gotErr)
+			if gotOK != wantOK || gotReason != wantReason {
+				t.Errorf("StopReasonOf = %v, %v; want %v, %v", gotReason, gotOK, wantReason, wantOK)
+			}
+		})
+	}
+}
+
+func TestBackoffSupersedesLegacyFields(t *testing.T) {
+	// InitialDelay is not required with Backoff
+	cfg := Config{MaxAttempts: 3, Backoff: Constant{Delay: time.Second}}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatalf("Normalize() = %v", err)
+	}
+
+	cfg = Config{
+		InitialDelay:   time.Minute,
+		JitterStrategy: JitterFull,
+		Backoff:        Fibonacci{Initial: 10 * time.Millisecond},
+	}
+	delays, err := recordDelays(t, cfg, transientErrors(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []time.Duration{10 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("delay[%d] = %v, want %v", i, delays[i], want[i])
+		}
+	}
+
+	// DelayOverrides still take precedence
+	cfg.DelayOverrides = []DelayRule{{
+		Match: func(err error) bool { return errors.Is(err, errRateLimited) },
+		Delay: func(int, error) time.Duration { return 5 * time.Second },
+	}}
+	delays, err = recordDelays(t, cfg, []error{errRateLimited})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delays) != 1 || delays[0] != 5*time.Second {
+		t.Errorf("delays = %v, want [5s]", delays)
+	}
+}
+
+func TestBackoffStop(t *testing.T) {
+	var giveUpReason string
+	cfg := Config{
+		Backoff: stopAfter(2),
+		OnGiveUp: func(attempts int, total time.Duration, lastErr error, reason string) {
+			giveUpReason = reason
+		},
+	}
+	errs := transientErrors(5)
+	delays, err := recordDelays(t, cfg, errs)
+	if len(delays) != 2 {
+		t.Errorf("delays = %v, want 2 delays", delays)
+	}
+	if err != errs[2] {
+		t.Errorf("err = %v, want the last error unchanged", err)
+	}
+	if giveUpReason != ReasonStoppedByPolicy {
+		t.Errorf("OnGiveUp reason = %q, want %q", giveUpReason, ReasonStoppedByPolicy)
+	}
+}
+
+// stopAfter is a Backoff allowing n retries with a constant delay
+type stopAfter int
+
+func (n stopAfter) Next(attempt int, _ error) (time.Duration, bool) {
+	return time.Second, attempt <= int(n)
+}
+
+func TestWithJitterBounds(t *testing.T) {
+	base := Exponential{Initial: 100 * time.Millisecond, Max: 2 * time.Second}
+	for _, strategy := range []JitterStrategy{JitterNone, JitterFull, JitterEqual, JitterDecorrelated} {
+		b := WithJitter(base, strategy)
+		for range 100 {
+			for attempt := 1; attempt <= 8; attempt++ {
+				d, ok := b.Next(attempt, nil)
+				ceiling, _ := base.Next(attempt, nil)
+				if !ok || d < 0 || d > ceiling {
+					t.Fatalf("strategy %d: Next(%d) = %v, %v; want within [0, %v]", strategy, attempt, d, ok, ceiling)
+				}
+				if strategy == JitterDecorrelated && d < base.Initial {
+					t.Fatalf("decorrelated Next(%d) = %v below first delay %v", attempt, d, base.Initial)
+				}
+			}
+		}
+	}
+}
+
+// countingBackoff records the attempts it was asked about
+type countingBackoff struct {
+	attempts []int
+}
+
+func (c *countingBackoff) Next(attempt int, _ error) (time.Duration, bool) {
+	c.attempts = append(c.attempts, attempt)
+	return time.Duration(attempt) * time.Second, true
+}
+
+func TestWithJitterDecorrelatedAsksEachAttemptOnce(t *testing.T) {
+	inner := &countingBackoff{}
+	b := WithJitter(inner, JitterDecorrelated)
+	for attempt := 1; attempt <= 4; attempt++ {
+		if d, _ := b.Next(attempt, nil); d < time.Second {
+			t.Fatalf("Next(%d) = %v below first delay 1s", attempt, d)
+		}
+	}
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(inner.attempts, want) {
+		t.Errorf("inner attempts = %v, want %v", inner.attempts, want)
+	}
+}
+
+func TestWithMaxElapsedOutsideDo(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := WithMaxElapsed(Constant{Delay: time.Second}, 3*time.Second).(*maxElapsedBackoff)
+	b.now = func() time.Time { return now }
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		d, ok := b.Next(attempt, nil)
+		if !ok {
+			t.Fatalf("Next(%d) stopped early", attempt)
+		}
+		now = now.Add(d)
+	}
+	if _, ok := b.Next(4, nil); ok {
+		t.Error("Next(4) must stop after 3s elapsed")
+	}
+
+	// Attempt 1 starts a new sequence
+	if _, ok := b.Next(1, nil); !ok {
+		t.Error("Next(1) must restart the elapsed time")
+	}
+}
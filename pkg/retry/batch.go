@@ -0,0 +1,103 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrSkipped marks a DoEach item that was never started because the batch
+// context was canceled or the batch MaxElapsedTime ran out.
+var ErrSkipped = errors.New("retry: item skipped")
+
+// BatchResult describes the outcome of DoEach
+type BatchResult struct {
+	// Errors holds the final error of each item by index; nil means the item succeeded.
+	// Skipped items hold an error matching ErrSkipped and the reason they were skipped.
+	Errors []error
+	// Succeeded is the number of items that succeeded
+	Succeeded int
+	// Failed is the number of started items that ended with an error
+	Failed int
+	// Skipped is the number of items that were never started
+	Skipped int
+	// Duration is the total time spent on the batch
+	Duration time.Duration
+}
+
+// DoEach retries fn for every item with at most concurrency items in flight
+// (concurrency <= 0 runs items one by one). Each item is retried independently
+// according to cfg, while cfg.MaxElapsedTime bounds the whole batch: when it runs
+// out, in-flight items see their context done and the remaining items are skipped.
+// Budget and Breaker in cfg are naturally shared by all items.
+//
+// Once ctx is done no new items are started; DoEach waits for the in-flight ones
+// before returning. The returned error joins the errors of failed items (each
+// prefixed with its index) with errors.Join, plus the context error if items were
+// skipped, so errors.Is and shared.KindOf work on it. It is nil when every item succeeded.
+// An invalid cfg is returned as is without calling fn.
+func DoEach[T any](ctx context.Context, cfg Config, items []T, concurrency int, fn func(ctx context.Context, item T) error) (BatchResult, error) {
+	if err := cfg.Normalize(); err != nil {
+		return BatchResult{}, err
+	}
+	startTime := cfg.Now()
+
+	// The batch deadline replaces per-item MaxElapsedTime
+	itemConfig := cfg
+	itemConfig.MaxElapsedTime = 0
+	if cfg.MaxElapsedTime > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.MaxElapsedTime)
+		defer cancel()
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	result := BatchResult{Errors: make([]error, len(items))}
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	started := 0
+	for i, item := range items {
+		select {
+		case <-ctx.Done():
+		case slots <- struct{}{}:
+		}
+		// Both cases may be ready at once; never start an item after ctx is done
+		if ctx.Err() != nil {
+			break
+		}
+		started++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			result.Errors[i] = Do(ctx, itemConfig, func(ctx context.Context) error {
+				return fn(ctx, item)
+			})
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for i, err := range result.Errors[:started] {
+		if err == nil {
+			result.Succeeded++
+			continue
+		}
+		result.Failed++
+		errs = append(errs, fmt.Errorf("item %d: %w", i, err))
+	}
+	if started < len(items) {
+		cause := ctx.Err()
+		for i := started; i < len(items); i++ {
+			result.Errors[i] = fmt.Errorf("%w: %w", ErrSkipped, cause)
+		}
+		result.Skipped = len(items) - started
+		errs = append(errs, fmt.Errorf("%d items skipped: %w", result.Skipped, cause))
+	}
+	result.Duration = cfg.Now().Sub(startTime)
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,232 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sttbot/internal/shared"
+)
+
+func batchConfig() Config {
+	return Config{
+		MaxAttempts:  3,
+		InitialDelay: time.Millisecond,
+		MaxDelay:     5 * time.Millisecond,
+		Multiplier:   2.0,
+	}
+}
+
+func TestDoEach_AllSucceed(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	var mu sync.Mutex
+	attempts := make(map[int]int)
+
+	res, err := DoEach(context.Background(), batchConfig(), items, 3, func(ctx context.Context, item int) error {
+		mu.Lock()
+		attempts[item]++
+		n := attempts[item]
+		mu.Unlock()
+		if item%2 == 0 && n == 1 {
+			return customError{"transient", true}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success, got: %v", err)
+	}
+	if res.Succeeded != len(items) || res.Failed != 0 || res.Skipped != 0 {
+		t.Errorf("unexpected counts: %+v", res)
+	}
+	if len(res.Errors) != len(items) {
+		t.Fatalf("expected %d errors, got %d", len(items), len(res.Errors))
+	}
+	for i, e := range res.Errors {
+		if e != nil {
+			t.Errorf("item %d: unexpected error %v", i, e)
+		}
+	}
+	for _, item := range items {
+		want := 1
+		if item%2 == 0 {
+			want = 2
+		}
+		if attempts[item] != want {
+			t.Errorf("item %d: expected %d attempts, got %d", item, want, attempts[item])
+		}
+	}
+}
+
+func TestDoEach_PartialFailure(t *testing.T) {
+	items := []string{"ok-0", "bad-1", "ok-2", "flaky-3", "bad-4"}
+	errBad := shared.MarkKind(errors.New("bad item"), shared.KindValidation)
+	var flakyAttempts atomic.Int32
+
+	res, err := DoEach(context.Background(), batchConfig(), items, 2, func(ctx context.Context, item string) error {
+		switch item[:len(item)-2] {
+		case "bad":
+			return errBad
+		case "flaky":
+			flakyAttempts.Add(1)
+			return customError{"still failing", true}
+		}
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if res.Succeeded != 2 || res.Failed != 3 || res.Skipped != 0 {
+		t.Errorf("unexpected counts: %+v", res)
+	}
+
+	// Per-item errors are indexed
+	if res.Errors[0] != nil || res.Errors[2] != nil {
+		t.Errorf("expected successful items to have nil errors: %v", res.Errors)
+	}
+	if !errors.Is(res.Errors[1], errBad) || !errors.Is(res.Errors[4], errBad) {
+		t.Errorf("expected bad items to keep their error: %v", res.Errors)
+	}
+	if !IsExhausted(res.Errors[3]) {
+		t.Errorf("expected flaky item to exhaust retries, got %v", res.Errors[3])
+	}
+	if got := flakyAttempts.Load(); got != 3 {
+		t.Errorf("expected 3 attempts for flaky item, got %d", got)
+	}
+
+	// Joined error works with errors.Is and shared kinds
+	if !errors.Is(err, errBad) {
+		t.Errorf("expected joined error to match item error, got %v", err)
+	}
+	if kind := shared.KindOf(err); kind != shared.KindValidation {
+		t.Errorf("expected KindValidation, got %v", kind)
+	}
+	if res.Duration <= 0 {
+		t.Errorf("expected positive duration, got %v", res.Duration)
+	}
+}
+
+func TestDoEach_ConcurrencyLimit(t *testing.T) {
+	items := make([]int, 20)
+	var inFlight, maxInFlight atomic.Int32
+
+	_, err := DoEach(context.Background(), batchConfig(), items, 4, func(ctx context.Context, item int) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := maxInFlight.Load(); got > 4 {
+		t.Errorf("expected at most 4 items in flight, got %d", got)
+	}
+}
+
+func TestDoEach_EarlyCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	items := make([]int, 10)
+	var started, inFlight atomic.Int32
+	running := make(chan struct{}, len(items))
+
+	resCh := make(chan BatchResult, 1)
+	errCh := make(chan error, 1)
+	go func() {
+		res, err := DoEach(ctx, batchConfig(), items, 2, func(ctx context.Context, item int) error {
+			started.Add(1)
+			inFlight.Add(1)
+			defer inFlight.Add(-1)
+			running <- struct{}{}
+			<-ctx.Done()
+			time.Sleep(10 * time.Millisecond) // in-flight items finish after cancellation
+			return ctx.Err()
+		})
+		resCh <- res
+		errCh <- err
+	}()
+
+	<-running
+	<-running
+	cancel()
+
+	res := <-resCh
+	err := <-errCh
+	if got := inFlight.Load(); got != 0 {
+		t.Errorf("expected DoEach to wait for in-flight items, %d still running", got)
+	}
+	if got := started.Load(); got != 2 {
+		t.Errorf("expected no items started after cancellation, started %d", got)
+	}
+	if res.Failed != 2 || res.Skipped != 8 || res.Succeeded != 0 {
+		t.Errorf("unexpected counts: %+v", res)
+	}
+	for i := 2; i < len(items); i++ {
+		if !errors.Is(res.Errors[i], ErrSkipped) || !errors.Is(res.Errors[i], context.Canceled) {
+			t.Errorf("item %d: expected skipped error, got %v", i, res.Errors[i])
+		}
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled in joined error, got %v", err)
+	}
+}
+
+func TestDoEach_MaxElapsedTimeBoundsBatch(t *testing.T) {
+	cfg := batchConfig()
+	cfg.MaxElapsedTime = 50 * time.Millisecond
+
+	items := make([]int, 5)
+	start := time.Now()
+	res, err := DoEach(context.Background(), cfg, items, 1, func(ctx context.Context, item int) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected batch to stop near MaxElapsedTime, took %v", elapsed)
+	}
+	if res.Failed != 1 || res.Skipped != 4 {
+		t.Errorf("unexpected counts: %+v", res)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if kind := shared.KindOf(err); kind != shared.KindTimeout {
+		t.Errorf("expected KindTimeout, got %v", kind)
+	}
+}
+
+func TestDoEach_InvalidConfig(t *testing.T) {
+	called := false
+	_, err := DoEach(context.Background(), Config{}, []int{1}, 1, func(ctx context.Context, item int) error {
+		called = true
+		return nil
+	})
+	if err == nil {
+		t.Error("expected config error")
+	}
+	if called {
+		t.Error("fn must not be called with invalid config")
+	}
+}
+
+func TestDoEach_Empty(t *testing.T) {
+	res, err := DoEach(context.Background(), batchConfig(), []int(nil), 3, func(ctx context.Context, item int) error {
+		return errors.New("unexpected call")
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Succeeded != 0 || res.Failed != 0 || res.Skipped != 0 || len(res.Errors) != 0 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+}
@@ -0,0 +1,226 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a Breaker rejects a call without running it
+var ErrCircuitOpen = errors.New("retry: circuit open")
+
+// errCallPanicked is recorded as the outcome of a call that panicked
+var errCallPanicked = errors.New("retry: call panicked")
+
+// ReasonCircuitOpen means the Breaker rejected an attempt (OnGiveUp only)
+const ReasonCircuitOpen = "circuit open"
+
+// BreakerState is the state of a Breaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets all calls through and counts consecutive failures
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects all calls until OpenTimeout passes
+	BreakerOpen
+	// BreakerHalfOpen lets up to HalfOpenMaxCalls trial calls through
+	BreakerHalfOpen
+)
+
+// String returns the state name
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+// BreakerConfig defines circuit breaker configuration
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit (default 5)
+	FailureThreshold int
+	// SuccessThreshold is the number of consecutive successful trial calls
+	// that closes a half-open circuit (default 1)
+	SuccessThreshold int
+	// OpenTimeout is how long the circuit stays open before trial calls are allowed (default 30s)
+	OpenTimeout time.Duration
+	// HalfOpenMaxCalls is the maximum number of concurrent trial calls in half-open state (default 1)
+	HalfOpenMaxCalls int
+	// OnStateChange is called on every state transition, for example to log it.
+	// It is called with the breaker lock held, so it must not call the Breaker.
+	OnStateChange func(from, to BreakerState)
+	// Now returns current time (for testing, defaults to time.Now)
+	Now func() time.Time
+}
+
+// Breaker is a circuit breaker: after FailureThreshold consecutive failures it
+// rejects calls with ErrCircuitOpen for OpenTimeout, then lets a limited number of
+// trial calls through and closes again after SuccessThreshold successes.
+// Any error counts as a failure, except calls whose context was canceled.
+// Breaker is safe for concurrent use and can be shared by many Do calls via Config.Breaker.
+type Breaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	successThreshold int
+	openTimeout      time.Duration
+	halfOpenMaxCalls int
+	onStateChange    func(from, to BreakerState)
+	now              func() time.Time
+
+	state      BreakerState
+	generation uint64 // incremented on each transition, outcomes of older calls are ignored
+	failures   int
+	successes  int
+	inFlight   int // trial calls in half-open state
+	openedAt   time.Time
+}
+
+// NewBreaker creates a closed circuit breaker
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	b := &Breaker{
+		failureThreshold: cfg.FailureThreshold,
+		successThreshold: cfg.SuccessThreshold,
+		openTimeout:      cfg.OpenTimeout,
+		halfOpenMaxCalls: cfg.HalfOpenMaxCalls,
+		onStateChange:    cfg.OnStateChange,
+		now:              cfg.Now,
+	}
+	if b.failureThreshold <= 0 {
+		b.failureThreshold = 5
+	}
+	if b.successThreshold <= 0 {
+		b.successThreshold = 1
+	}
+	if b.openTimeout <= 0 {
+		b.openTimeout = 30 * time.Second
+	}
+	if b.halfOpenMaxCalls <= 0 {
+		b.halfOpenMaxCalls = 1
+	}
+	if b.now == nil {
+		b.now = time.Now
+	}
+	return b
+}
+
+// Execute calls fn if the breaker allows it and records the outcome.
+// Returns ErrCircuitOpen without calling fn when the circuit is open.
+// A panic in fn is recorded as a failure and propagated.
+func (b *Breaker) Execute(ctx context.Context, fn RetryableFunc) error {
+	generation, err := b.allow()
+	if err != nil {
+		return err
+	}
+	_, err = callThrough(ctx, b, generation, func() (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// callThrough calls fn reserved by b.allow and records its outcome in b (if not nil).
+// The outcome is recorded from a defer, so a panicking fn counts as a failure
+// and releases its half-open slot.
+func callThrough[T any](ctx context.Context, b *Breaker, generation uint64, fn func() (T, error)) (result T, err error) {
+	if b != nil {
+		err = errCallPanicked
+		defer func() { b.record(ctx, generation, err) }()
+	}
+	return fn()
+}
+
+// State returns the current state. An open circuit whose OpenTimeout has passed
+// is reported as half-open.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.checkOpenTimeout()
+	return b.state
+}
+
+// allow reserves a call and returns the generation to pass to record
+func (b *Breaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.checkOpenTimeout()
+	switch b.state {
+	case BreakerOpen:
+		return 0, ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.inFlight >= b.halfOpenMaxCalls {
+			return 0, ErrCircuitOpen
+		}
+		b.inFlight++
+	}
+	return b.generation, nil
+}
+
+// record accounts the outcome of a call reserved by allow.
+// Calls canceled by their context only release the reservation.
+func (b *Breaker) record(ctx context.Context, generation uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return // state changed while the call was running
+	}
+	if b.state == BreakerHalfOpen {
+		b.inFlight--
+	}
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	switch b.state {
+	case BreakerClosed:
+		if err == nil {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.failureThreshold {
+			b.setState(BreakerOpen)
+		}
+	case BreakerHalfOpen:
+		if err != nil {
+			b.setState(BreakerOpen)
+			return
+		}
+		b.successes++
+		if b.successes >= b.successThreshold {
+			b.setState(BreakerClosed)
+		}
+	}
+}
+
+// checkOpenTimeout moves an open circuit to half-open after OpenTimeout. Must be called with mu held.
+func (b *Breaker) checkOpenTimeout() {
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		b.setState(BreakerHalfOpen)
+	}
+}
+
+// setState switches the state and resets the counters. Must be called with mu held.
+func (b *Breaker) setState(state BreakerState) {
+	from := b.state
+	b.state = state
+	b.generation++
+	b.failures = 0
+	b.successes = 0
+	b.inFlight = 0
+	if state == BreakerOpen {
+		b.openedAt = b.now()
+	}
+	if b.onStateChange != nil {
+		b.onStateChange(from, state)
+	}
+}
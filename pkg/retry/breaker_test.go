@@ -0,0 +1,246 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreakerTransitions(t *testing.T) {
+	now := time.Unix(0, 0)
+	var transitions []string
+	b := NewBreaker(BreakerConfig{
+		FailureThreshold: 2,
+		SuccessThreshold: 2,
+		OpenTimeout:      time.Minute,
+		Now:              func() time.Time { return now },
+		OnStateChange: func(from, to BreakerState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+	fail := func(ctx context.Context) error { return errors.New("boom") }
+	ok := func(ctx context.Context) error { return nil }
+	ctx := context.Background()
+
+	// A success resets consecutive failures
+	_ = b.Execute(ctx, fail)
+	_ = b.Execute(ctx, ok)
+	_ = b.Execute(ctx, fail)
+	if b.State() != BreakerClosed {
+		t.Fatalf("expected closed, got %v", b.State())
+	}
+	_ = b.Execute(ctx, fail)
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected open, got %v", b.State())
+	}
+
+	called := false
+	err := b.Execute(ctx, func(ctx context.Context) error { called = true; return nil })
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if called {
+		t.Error("fn must not be called while circuit is open")
+	}
+
+	// A failed trial call opens the circuit again
+	now = now.Add(time.Minute)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("expected half-open, got %v", b.State())
+	}
+	_ = b.Execute(ctx, fail)
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected open after failed trial, got %v", b.State())
+	}
+
+	// SuccessThreshold successful trials close it
+	now = now.Add(time.Minute)
+	_ = b.Execute(ctx, ok)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("expected half-open after one success, got %v", b.State())
+	}
+	_ = b.Execute(ctx, ok)
+	if b.State() != BreakerClosed {
+		t.Fatalf("expected closed, got %v", b.State())
+	}
+
+	expected := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if !reflect.DeepEqual(transitions, expected) {
+		t.Errorf("unexpected transitions: %v", transitions)
+	}
+}
+
+func TestBreakerHalfOpenMaxCalls(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Second,
+		HalfOpenMaxCalls: 2,
+		Now:              func() time.Time { return now },
+	})
+	ctx := context.Background()
+	_ = b.Execute(ctx, func(ctx context.Context) error { return errors.New("boom") })
+	now = now.Add(time.Second)
+
+	release := make(chan struct{})
+	var started sync.WaitGroup
+	var done sync.WaitGroup
+	for range 2 {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			_ = b.Execute(ctx, func(ctx context.Context) error {
+				started.Done()
+				<-release
+				return nil
+			})
+		}()
+	}
+	started.Wait()
+
+	if err := b.Execute(ctx, func(ctx context.Context) error { return nil }); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen over HalfOpenMaxCalls, got %v", err)
+	}
+	close(release)
+	done.Wait()
+	if b.State() != BreakerClosed {
+		t.Errorf("expected closed after successful trials, got %v", b.State())
+	}
+}
+
+func TestBreakerPanicInHalfOpen(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Second,
+		Now:              func() time.Time { return now },
+	})
+	ctx := context.Background()
+	_ = b.Execute(ctx, func(ctx context.Context) error { return errors.New("boom") })
+	now = now.Add(time.Second)
+
+	func() {
+		defer func() {
+			if r := recover(); r != "trial" {
+				t.Errorf("expected panic to propagate, got %v", r)
+			}
+		}()
+		_ = b.Execute(ctx, func(ctx context.Context) error { panic("trial") })
+	}()
+
+	// The panic counted as a failed trial and released the half-open slot
+	if b.State() != BreakerOpen {
+		t.Fatalf("expected open after panicking trial, got %v", b.State())
+	}
+	now = now.Add(time.Second)
+	if err := b.Execute(ctx, func(ctx context.Context) error { return nil }); err != nil {
+		t.Errorf("expected next trial to run, got %v", err)
+	}
+	if b.State() != BreakerClosed {
+		t.Errorf("expected closed after successful trial, got %v", b.State())
+	}
+}
+
+func TestBreakerIgnoresCanceledCalls(t *testing.T) {
+	b := NewBreaker(BreakerConfig{FailureThreshold: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_ = b.Execute(ctx, func(ctx context.Context) error { return ctx.Err() })
+	if b.State() != BreakerClosed {
+		t.Errorf("canceled call must not count as failure, got %v", b.State())
+	}
+}
+
+func TestBreakerConcurrent(t *testing.T) {
+	b := NewBreaker(BreakerConfig{FailureThreshold: 1000})
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				_ = b.Execute(context.Background(), func(ctx context.Context) error {
+					if (i+j)%2 == 0 {
+						return errors.New("boom")
+					}
+					return nil
+				})
+				_ = b.State()
+			}
+		}()
+	}
+	wg.Wait()
+	if b.State() != BreakerClosed {
+		t.Errorf("expected closed, got %v", b.State())
+	}
+}
+
+func TestDoWithBreaker(t *testing.T) {
+	breaker := NewBreaker(BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Hour})
+	config := NewConfig(Attempts(5), InitialDelay(time.Millisecond), WithBreaker(breaker))
+
+	var attempts int32
+	originalErr := customError{"temporary failure", true}
+	var giveUpReason string
+	config.OnGiveUp = func(attempts int, total time.Duration, lastErr error, reason string) {
+		giveUpReason = reason
+	}
+	err := Do(context.Background(), config, func(ctx context.Context) error {
+		atomic.AddInt32(&attempts, 1)
+		return originalErr
+	})
+
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if !errors.Is(err, originalErr) {
+		t.Error("should be able to unwrap to original error")
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts before circuit opened, got %d", attempts)
+	}
+	if giveUpReason != ReasonCircuitOpen {
+		t.Errorf("unexpected give-up reason: %q", giveUpReason)
+	}
+
+	// Open circuit rejects the next call without running it
+	err = Do(context.Background(), config, func(ctx context.Context) error {
+		atomic.AddInt32(&attempts, 1)
+		return nil
+	})
+	if err != ErrCircuitOpen {
+		t.Errorf("expected bare ErrCircuitOpen, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("fn must not be called while circuit is open, got %d attempts", attempts)
+	}
+}
+
+func TestDoWithBreakerNonRetryableCountsAsFailure(t *testing.T) {
+	breaker := NewBreaker(BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Hour})
+	config := NewConfig(Attempts(5), InitialDelay(time.Millisecond), WithBreaker(breaker))
+
+	var attempts int32
+	permanentErr := customError{"permanent failure", false}
+	for range 2 {
+		err := Do(context.Background(), config, func(ctx context.Context) error {
+			atomic.AddInt32(&attempts, 1)
+			return permanentErr
+		})
+		if err != permanentErr {
+			t.Fatalf("expected non-retryable error as is, got %v", err)
+		}
+	}
+	if attempts != 2 {
+		t.Errorf("non-retryable errors must not be retried, got %d attempts", attempts)
+	}
+	if breaker.State() != BreakerOpen {
+		t.Errorf("non-retryable errors must count as failures, got %v", breaker.State())
+	}
+}
@@ -0,0 +1,134 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExhausted is reported when a shared retry Budget denies a retry.
+// Use errors.Is(err, ErrBudgetExhausted) to detect it in RetriesExceededError.
+var ErrBudgetExhausted = errors.New("retry: budget exhausted")
+
+// reasonBudgetExhausted is the RetriesExceededError reason for a denied retry.
+const reasonBudgetExhausted = "retry budget exhausted"
+
+// BudgetConfig defines retry budget configuration
+type BudgetConfig struct {
+	// MaxRetries is the number of retries allowed per Interval (bucket capacity)
+	MaxRetries int
+	// Interval is the period over which MaxRetries tokens are refilled (0 = no time-based refill)
+	Interval time.Duration
+	// SuccessRatio is the fraction of a token earned by each successful call (0 = disabled).
+	// For example, 0.1 allows one extra retry per 10 successful calls.
+	SuccessRatio float64
+	// Now returns current time (for testing, defaults to time.Now)
+	Now func() time.Time
+}
+
+// BudgetStats contains retry budget counters
+type BudgetStats struct {
+	// Granted is the number of retries allowed by the budget
+	Granted uint64
+	// Denied is the number of retries rejected by the budget
+	Denied uint64
+	// Available is the number of retry tokens currently available
+	Available float64
+}
+
+// Budget limits the total number of retries shared between many Do calls.
+// It is a token bucket: each retry consumes one token, tokens are refilled
+// over time and optionally earned by successful calls.
+// Budget is safe for concurrent use.
+type Budget struct {
+	mu         sync.Mutex
+	capacity   float64
+	refillRate float64 // tokens per nanosecond
+	ratio      float64
+	tokens     float64
+	lastRefill time.Time
+	now        func() time.Time
+	granted    uint64
+	denied     uint64
+}
+
+// NewBudget creates a retry budget. The bucket starts full.
+func NewBudget(cfg BudgetConfig) *Budget {
+	now := cfg.Now
+	if now == nil {
+		now = time.Now
+	}
+	capacity := float64(cfg.MaxRetries)
+	if capacity < 0 {
+		capacity = 0
+	}
+	b := &Budget{
+		capacity:   capacity,
+		tokens:     capacity,
+		ratio:      cfg.SuccessRatio,
+		lastRefill: now(),
+		now:        now,
+	}
+	if cfg.Interval > 0 {
+		b.refillRate = capacity / float64(cfg.Interval)
+	}
+	return b
+}
+
+// Allow consumes one retry token and reports whether the retry is permitted.
+func (b *Budget) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	if b.tokens >= 1 {
+		b.tokens--
+		b.granted++
+		return true
+	}
+	b.denied++
+	return false
+}
+
+// RecordSuccess credits the budget for a successful call when SuccessRatio is set.
+func (b *Budget) RecordSuccess() {
+	if b.ratio <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	b.tokens += b.ratio
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
+
+// Stats returns a snapshot of budget counters.
+func (b *Budget) Stats() BudgetStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill()
+	return BudgetStats{
+		Granted:   b.granted,
+		Denied:    b.denied,
+		Available: b.tokens,
+	}
+}
+
+// refill adds tokens accumulated since the last refill. Must be called with mu held.
+func (b *Budget) refill() {
+	now := b.now()
+	if b.refillRate > 0 {
+		elapsed := now.Sub(b.lastRefill)
+		if elapsed > 0 {
+			b.tokens += float64(elapsed) * b.refillRate
+			if b.tokens > b.capacity {
+				b.tokens = b.capacity
+			}
+		}
+	}
+	b.lastRefill = now
+}
@@ -0,0 +1,159 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBudgetAllow(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewBudget(BudgetConfig{
+		MaxRetries: 2,
+		Interval:   time.Second,
+		Now:        func() time.Time { return now },
+	})
+
+	if !b.Allow() || !b.Allow() {
+		t.Fatal("expected first two retries to be allowed")
+	}
+	if b.Allow() {
+		t.Fatal("expected third retry to be denied")
+	}
+
+	// Half an interval refills one token
+	now = now.Add(500 * time.Millisecond)
+	if !b.Allow() {
+		t.Fatal("expected retry to be allowed after refill")
+	}
+	if b.Allow() {
+		t.Fatal("expected retry to be denied after consuming refilled token")
+	}
+
+	stats := b.Stats()
+	if stats.Granted != 3 {
+		t.Errorf("expected 3 granted, got %d", stats.Granted)
+	}
+	if stats.Denied != 2 {
+		t.Errorf("expected 2 denied, got %d", stats.Denied)
+	}
+}
+
+func TestBudgetRefillCapped(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := NewBudget(BudgetConfig{
+		MaxRetries: 2,
+		Interval:   time.Second,
+		Now:        func() time.Time { return now },
+	})
+
+	now = now.Add(time.Hour)
+	if got := b.Stats().Available; got != 2 {
+		t.Errorf("expected available tokens capped at 2, got %v", got)
+	}
+}
+
+func TestBudgetSuccessRatio(t *testing.T) {
+	b := NewBudget(BudgetConfig{
+		MaxRetries:   1,
+		SuccessRatio: 0.5,
+	})
+
+	if !b.Allow() {
+		t.Fatal("expected first retry to be allowed")
+	}
+	if b.Allow() {
+		t.Fatal("expected retry to be denied with empty bucket")
+	}
+
+	b.RecordSuccess()
+	b.RecordSuccess()
+	if !b.Allow() {
+		t.Fatal("expected retry to be allowed after two successes")
+	}
+}
+
+func TestDoBudgetExhausted(t *testing.T) {
+	budget := NewBudget(BudgetConfig{MaxRetries: 1, Interval: time.Hour})
+	config := Config{
+		MaxAttempts:  5,
+		InitialDelay: 1 * time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+		Multiplier:   2.0,
+		Budget:       budget,
+	}
+
+	var attempts int32
+	originalErr := customError{"temporary failure", true}
+	err := Do(context.Background(), config, func(ctx context.Context) error {
+		atomic.AddInt32(&attempts, 1)
+		return originalErr
+	})
+
+	if !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("expected ErrBudgetExhausted, got %v", err)
+	}
+	if !errors.Is(err, originalErr) {
+		t.Error("should be able to unwrap to original error")
+	}
+	var retryErr *RetriesExceededError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("expected RetriesExceededError, got %T", err)
+	}
+	if retryErr.Reason != "retry budget exhausted" {
+		t.Errorf("unexpected reason: %q", retryErr.Reason)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts (one retry allowed), got %d", attempts)
+	}
+}
+
+func TestDoMaxAttemptsNotBudgetExhausted(t *testing.T) {
+	config := Config{
+		MaxAttempts:  2,
+		InitialDelay: 1 * time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+		Multiplier:   2.0,
+	}
+
+	err := Do(context.Background(), config, func(ctx context.Context) error {
+		return customError{"temporary failure", true}
+	})
+	if errors.Is(err, ErrBudgetExhausted) {
+		t.Error("max attempts error must not match ErrBudgetExhausted")
+	}
+}
+
+func TestBudgetConcurrentUse(t *testing.T) {
+	budget := NewBudget(BudgetConfig{MaxRetries: 10, Interval: time.Hour})
+	config := Config{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Millisecond,
+		MaxDelay:     5 * time.Millisecond,
+		Multiplier:   2.0,
+		Budget:       budget,
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = Do(context.Background(), config, func(ctx context.Context) error {
+				return customError{"temporary failure", true}
+			})
+		}()
+	}
+	wg.Wait()
+
+	stats := budget.Stats()
+	if stats.Granted != 10 {
+		t.Errorf("expected exactly 10 granted retries, got %d", stats.Granted)
+	}
+	if stats.Denied == 0 {
+		t.Error("expected some retries to be denied")
+	}
+}
@@ -0,0 +1,31 @@
+package retry
+
+import "time"
+
+// Clock is the time source used by Do for Now and the waits between attempts.
+// RealClock uses the wall clock; retrytest.FakeClock lets tests step through
+// backoff sequences without sleeping.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// RealClock is the Clock backed by time.Now and time.After
+type RealClock struct{}
+
+// Now returns time.Now()
+func (RealClock) Now() time.Time { return time.Now() }
+
+// After returns time.After(d)
+func (RealClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// WithClock returns a copy of the config that reads time from clock by setting
+// Now and After to its methods. A nil clock resets them to the wall clock.
+func (c Config) WithClock(clock Clock) Config {
+	if clock == nil {
+		c.Now, c.After = nil, nil
+		return c
+	}
+	c.Now, c.After = clock.Now, clock.After
+	return c
+}
@@ -0,0 +1,73 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"sttbot/pkg/retry/retrytest"
+)
+
+func TestWithClock_StepsThroughBackoff(t *testing.T) {
+	clock := retrytest.NewFakeClock()
+	start := clock.Now()
+	cfg := NewConfig(
+		Attempts(4),
+		InitialDelay(time.Second),
+		Jitter(JitterNone),
+		WithClock(clock),
+	)
+
+	var attemptTimes []time.Duration
+	done := make(chan error, 1)
+	go func() {
+		done <- Do(context.Background(), cfg, func(ctx context.Context) error {
+			attemptTimes = append(attemptTimes, clock.Now().Sub(start))
+			return customError{"transient", true}
+		})
+	}()
+
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		clock.BlockUntilWaiters(1)
+		clock.Advance(delay)
+	}
+	err := <-done
+
+	var retryErr *RetriesExceededError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 4 {
+		t.Fatalf("expected 4 failed attempts, got %v", err)
+	}
+	want := []time.Duration{0, time.Second, 3 * time.Second, 7 * time.Second}
+	if fmt.Sprint(attemptTimes) != fmt.Sprint(want) {
+		t.Errorf("attempt times = %v, want %v", attemptTimes, want)
+	}
+	if retryErr.TotalDuration != 7*time.Second {
+		t.Errorf("TotalDuration = %v, want 7s", retryErr.TotalDuration)
+	}
+}
+
+func TestConfigWithClock(t *testing.T) {
+	clock := retrytest.NewFakeClock()
+	cfg := DefaultConfig().WithClock(clock)
+	if cfg.Now == nil || cfg.After == nil || !cfg.Now().Equal(clock.Now()) {
+		t.Fatalf("expected Now and After from the clock, got %+v", cfg)
+	}
+
+	// nil resets to the wall clock on Normalize
+	cfg = cfg.WithClock(nil)
+	if cfg.Now != nil || cfg.After != nil {
+		t.Errorf("expected nil Now and After after WithClock(nil)")
+	}
+
+	cfg = DefaultConfig().WithClock(RealClock{})
+	if d := time.Since(cfg.Now()); d < 0 || d > time.Second {
+		t.Errorf("RealClock.Now is off by %v", d)
+	}
+	select {
+	case <-cfg.After(0):
+	case <-time.After(time.Second):
+		t.Error("RealClock.After(0) did not fire")
+	}
+}
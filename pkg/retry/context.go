@@ -0,0 +1,39 @@
+package retry
+
+import "context"
+
+// attemptKey is the context key for per-attempt metadata
+type attemptKey struct{}
+
+// attemptInfo holds metadata about the current attempt
+type attemptInfo struct {
+	attempt int
+	lastErr error
+}
+
+// withAttempt returns a context carrying metadata about the current attempt.
+// Each Do invocation stores its own value, so nested retries don't leak metadata.
+func withAttempt(ctx context.Context, attempt int, lastErr error) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attemptInfo{attempt: attempt, lastErr: lastErr})
+}
+
+// AttemptFromContext returns the current attempt number (starting at 1)
+// of the innermost Do call that invoked the function.
+// Returns false if ctx was not created by Do.
+func AttemptFromContext(ctx context.Context) (int, bool) {
+	info, ok := ctx.Value(attemptKey{}).(attemptInfo)
+	if !ok {
+		return 0, false
+	}
+	return info.attempt, true
+}
+
+// LastErrorFromContext returns the error returned by the previous attempt
+// of the innermost Do call. Returns nil on the first attempt or if ctx was not created by Do.
+func LastErrorFromContext(ctx context.Context) error {
+	info, ok := ctx.Value(attemptKey{}).(attemptInfo)
+	if !ok {
+		return nil
+	}
+	return info.lastErr
+}
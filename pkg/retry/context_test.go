@@ -0,0 +1,96 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAttemptFromContext(t *testing.T) {
+	config := Config{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+		Multiplier:   2.0,
+	}
+
+	var attempts []int
+	var lastErrs []error
+	errFirst := customError{"first", true}
+	errSecond := customError{"second", true}
+
+	err := Do(context.Background(), config, func(ctx context.Context) error {
+		attempt, ok := AttemptFromContext(ctx)
+		if !ok {
+			t.Fatal("expected attempt in context")
+		}
+		attempts = append(attempts, attempt)
+		lastErrs = append(lastErrs, LastErrorFromContext(ctx))
+
+		switch attempt {
+		case 1:
+			return errFirst
+		case 2:
+			return errSecond
+		default:
+			return nil
+		}
+	})
+	if err != nil {
+		t.Fatalf("expected success, got: %v", err)
+	}
+
+	if len(attempts) != 3 || attempts[0] != 1 || attempts[1] != 2 || attempts[2] != 3 {
+		t.Errorf("unexpected attempts: %v", attempts)
+	}
+	if lastErrs[0] != nil {
+		t.Errorf("expected no last error on first attempt, got %v", lastErrs[0])
+	}
+	if !errors.Is(lastErrs[1], errFirst) || !errors.Is(lastErrs[2], errSecond) {
+		t.Errorf("unexpected last errors: %v", lastErrs)
+	}
+}
+
+func TestAttemptFromContextMissing(t *testing.T) {
+	if _, ok := AttemptFromContext(context.Background()); ok {
+		t.Error("expected no attempt in plain context")
+	}
+	if err := LastErrorFromContext(context.Background()); err != nil {
+		t.Errorf("expected nil last error, got %v", err)
+	}
+}
+
+func TestAttemptFromContextNested(t *testing.T) {
+	config := Config{
+		MaxAttempts:  2,
+		InitialDelay: 1 * time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+		Multiplier:   2.0,
+	}
+
+	var innerAttempt int
+	var innerLastErr error
+	err := Do(context.Background(), config, func(ctx context.Context) error {
+		outer, _ := AttemptFromContext(ctx)
+		if outer == 1 {
+			return customError{"outer failure", true}
+		}
+
+		// Nested Do starts its own numbering and doesn't see the outer error
+		return Do(ctx, config, func(ctx context.Context) error {
+			innerAttempt, _ = AttemptFromContext(ctx)
+			innerLastErr = LastErrorFromContext(ctx)
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("expected success, got: %v", err)
+	}
+	if innerAttempt != 1 {
+		t.Errorf("expected inner attempt 1, got %d", innerAttempt)
+	}
+	if innerLastErr != nil {
+		t.Errorf("expected outer last error not to leak, got %v", innerLastErr)
+	}
+}
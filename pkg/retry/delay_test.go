@@ -0,0 +1,273 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// errRateLimited simulates an error class with a server-suggested delay
+var errRateLimited = errors.New("rate limited")
+
+// recordDelays runs Do with the given errors returned in order and records requested delays.
+// Time is virtual: After advances the clock instantly.
+func recordDelays(t *testing.T, cfg Config, errs []error) ([]time.Duration, error) {
+	t.Helper()
+
+	var delays []time.Duration
+	now := time.Unix(0, 0)
+	cfg.MaxAttempts = len(errs) + 1
+	cfg.Now = func() time.Time { return now }
+	cfg.After = func(d time.Duration) <-chan time.Time {
+		delays = append(delays, d)
+		now = now.Add(d)
+		ch := make(chan time.Time, 1)
+		ch <- now
+		return ch
+	}
+
+	call := 0
+	err := DoWithRetryable(context.Background(), cfg, func(ctx context.Context) error {
+		call++
+		if call <= len(errs) {
+			return errs[call-1]
+		}
+		return nil
+	}, func(error) bool { return true })
+	return delays, err
+}
+
+// startDelays returns the per-call Backoff Do would use for the normalized cfg
+func startDelays(cfg *Config) Backoff {
+	return startBackoff(cfg.backoff(), cfg, time.Unix(0, 0))
+}
+
+func TestDelayOverrides(t *testing.T) {
+	transient := errors.New("connection reset")
+	rateLimitRule := DelayRule{
+		Match: func(err error) bool { return errors.Is(err, errRateLimited) },
+		Delay: func(attempt int, err error) time.Duration { return 5 * time.Second },
+	}
+
+	tests := []struct {
+		name       string
+		configure  func(*Config)
+		errs       []error
+		wantDelays []time.Duration
+		wantReason string
+	}{
+		{
+			name: "rate limit fixed, others exponential",
+			errs: []error{transient, errRateLimited, transient, errRateLimited, transient},
+			wantDelays: []time.Duration{
+				100 * time.Millisecond,
+				5 * time.Second,
+				400 * time.Millisecond,
+				5 * time.Second,
+				1600 * time.Millisecond,
+			},
+		},
+		{
+			name:       "override ignores jitter",
+			configure:  func(c *Config) { c.JitterStrategy = JitterFull },
+			errs:       []error{errRateLimited, errRateLimited},
+			wantDelays: []time.Duration{5 * time.Second, 5 * time.Second},
+		},
+		{
+			name:       "override capped at MaxDelay",
+			configure:  func(c *Config) { c.MaxDelay = 2 * time.Second },
+			errs:       []error{errRateLimited},
+			wantDelays: []time.Duration{2 * time.Second},
+		},
+		{
+			name:       "override counts toward MaxElapsedTime",
+			configure:  func(c *Config) { c.MaxElapsedTime = 3 * time.Second },
+			errs:       []error{transient, errRateLimited},
+			wantDelays: []time.Duration{100 * time.Millisecond},
+			wantReason: ReasonMaxElapsedTime,
+		},
+		{
+			name: "first matching rule wins",
+			configure: func(c *Config) {
+				c.DelayOverrides = append([]DelayRule{{
+					Match: func(err error) bool { return true },
+					Delay: func(attempt int, err error) time.Duration { return time.Duration(attempt) * time.Second },
+				}}, c.DelayOverrides...)
+			},
+			errs:       []error{errRateLimited, transient},
+			wantDelays: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name: "override takes precedence over NextDelay",
+			configure: func(c *Config) {
+				c.NextDelay = func(attempt int, err error) (time.Duration, bool) { return time.Second, true }
+			},
+			errs:       []error{transient, errRateLimited},
+			wantDelays: []time.Duration{time.Second, 5 * time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				InitialDelay:   100 * time.Millisecond,
+				MaxDelay:       10 * time.Second,
+				Multiplier:     2,
+				JitterStrategy: JitterNone,
+				Rand:           rand.New(rand.NewSource(1)),
+				DelayOverrides: []DelayRule{rateLimitRule},
+			}
+			if tt.configure != nil {
+				tt.configure(&cfg)
+			}
+
+			delays, err := recordDelays(t, cfg, tt.errs)
+			if tt.wantReason == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantReason != "" {
+				var exceeded *RetriesExceededError
+				if !errors.As(err, &exceeded) || exceeded.Reason != tt.wantReason {
+					t.Fatalf("expected %q, got %v", tt.wantReason, err)
+				}
+			}
+			if len(delays) != len(tt.wantDelays) {
+				t.Fatalf("delays = %v, want %v", delays, tt.wantDelays)
+			}
+			for i := range delays {
+				if delays[i] != tt.wantDelays[i] {
+					t.Errorf("delay[%d] = %v, want %v", i, delays[i], tt.wantDelays[i])
+				}
+			}
+		})
+	}
+}
+
+func TestJitterStrategies(t *testing.T) {
+	const samples = 2000
+	base := 800 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		strategy JitterStrategy
+		lo, hi   time.Duration
+	}{
+		{name: "none", strategy: JitterNone, lo: base, hi: base},
+		{name: "full", strategy: JitterFull, lo: 0, hi: base},
+		{name: "equal", strategy: JitterEqual, lo: base / 2, hi: base},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				MaxAttempts:    5,
+				InitialDelay:   100 * time.Millisecond,
+				MinDelay:       time.Nanosecond, // expose the whole jitter range
+				MaxDelay:       10 * time.Second,
+				Multiplier:     2,
+				JitterStrategy: tt.strategy,
+				Rand:           rand.New(rand.NewSource(42)),
+			}
+			if err := cfg.Normalize(); err != nil {
+				t.Fatal(err)
+			}
+
+			// Attempt 4: 100ms * 2^3 = 800ms
+			minSeen, maxSeen := time.Duration(1<<62), time.Duration(0)
+			for range samples {
+				d, _ := startDelays(&cfg).Next(4, nil)
+				if d < tt.lo || d > tt.hi {
+					t.Fatalf("delay %v outside [%v, %v]", d, tt.lo, tt.hi)
+				}
+				minSeen = min(minSeen, d)
+				maxSeen = max(maxSeen, d)
+			}
+
+			// The whole range is used, not just a part of it
+			spread := tt.hi - tt.lo
+			if minSeen > tt.lo+spread/10 || maxSeen < tt.hi-spread/10 {
+				t.Errorf("observed range [%v, %v] does not cover [%v, %v]", minSeen, maxSeen, tt.lo, tt.hi)
+			}
+		})
+	}
+}
+
+func TestJitterDecorrelatedUsesPreviousDelay(t *testing.T) {
+	cfg := Config{
+		MaxAttempts:    5,
+		InitialDelay:   100 * time.Millisecond,
+		MaxDelay:       time.Hour,
+		Multiplier:     2,
+		JitterStrategy: JitterDecorrelated,
+		Rand:           rand.New(rand.NewSource(7)),
+	}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	// uniform [InitialDelay, min(3*prev, d)] with d the exponential delay of the attempt
+	var grew bool
+	for range 100 {
+		b := startDelays(&cfg)
+		prev := cfg.InitialDelay
+		for attempt := 1; attempt <= 8; attempt++ {
+			d, _ := b.Next(attempt, nil)
+			ceiling := min(3*prev, cfg.calculateDelay(attempt))
+			if d < cfg.InitialDelay || d > ceiling {
+				t.Fatalf("Next(%d) = %v outside [%v, %v]", attempt, d, cfg.InitialDelay, ceiling)
+			}
+			grew = grew || d > prev
+			prev = d
+		}
+	}
+	if !grew {
+		t.Error("delay never grew beyond the previous one")
+	}
+
+	// Within Do every delay is bounded by three times the previous one
+	failures := make([]error, 8)
+	for i := range failures {
+		failures[i] = errors.New("transient")
+	}
+	delays, err := recordDelays(t, cfg, failures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delays) != len(failures) {
+		t.Fatalf("expected %d delays, got %d", len(failures), len(delays))
+	}
+	prev := cfg.InitialDelay
+	for i, d := range delays {
+		if ceiling := min(3*prev, cfg.calculateDelay(i+1)); d < cfg.InitialDelay || d > ceiling {
+			t.Errorf("delay[%d] = %v outside [%v, %v]", i, d, cfg.InitialDelay, ceiling)
+		}
+		prev = d
+	}
+}
+
+func TestJitterClampedToMinDelay(t *testing.T) {
+	cfg := Config{
+		MaxAttempts:    3,
+		InitialDelay:   100 * time.Millisecond,
+		MaxDelay:       time.Second,
+		JitterStrategy: JitterFull,
+		Rand:           rand.New(rand.NewSource(3)),
+	}
+	if err := cfg.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+
+	// MinDelay defaults to InitialDelay
+	for range 1000 {
+		if d, _ := startDelays(&cfg).Next(1, nil); d != cfg.InitialDelay {
+			t.Fatalf("delay %v, want clamped to %v", d, cfg.InitialDelay)
+		}
+	}
+	// Zero base delay does not panic
+	cfg.NextDelay = func(int, error) (time.Duration, bool) { return 0, true }
+	if d, _ := startDelays(&cfg).Next(1, nil); d != cfg.MinDelay {
+		t.Errorf("jittered zero delay = %v, want %v", d, cfg.MinDelay)
+	}
+}
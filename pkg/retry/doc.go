@@ -2,13 +2,18 @@
 // and comprehensive error handling for Go applications.
 //
 // Key Features:
-//   - Multiple jitter strategies (None, Equal, Decorrelated)
+//   - Multiple jitter strategies (None, Full, Equal, Decorrelated)
 //   - Configurable time and attempt limits
 //   - Rich network error detection
-//   - Observability hooks (OnRetry callback)
+//   - Observability hooks (OnRetry and OnGiveUp callbacks, optional slog Logger)
+//   - Composable backoff policies (Exponential, Constant, Fibonacci, WithJitter, WithMaxElapsed)
 //   - Custom delay policies (NextDelay override)
-//   - Full testability support (time abstraction)
+//   - Full testability support (Clock abstraction, retrytest.FakeClock)
 //   - Detailed error reporting
+//   - Shared retry budget to avoid retry storms (Budget)
+//   - Circuit breaker shared between calls (Breaker)
+//   - Generic API for functions returning a value (DoValue, RetryValue)
+//   - Batch retries with bounded concurrency (DoEach)
 //
 // Basic Usage:
 //
@@ -16,6 +21,35 @@
 //	    return someNetworkOperation()
 //	})
 //
+// Returning a value (generic API):
+//
+//	user, err := retry.RetryValue(ctx, func(ctx context.Context) (*User, error) {
+//	    return client.GetUser(ctx, id)
+//	})
+//
+// Retrying a batch of items, at most 5 in flight, 30s for the whole batch:
+//
+//	cfg := retry.DefaultConfig()
+//	cfg.MaxElapsedTime = 30 * time.Second
+//	res, err := retry.DoEach(ctx, cfg, chats, 5, func(ctx context.Context, chatID int64) error {
+//	    return bot.Notify(ctx, chatID)
+//	})
+//	for i, err := range res.Errors {
+//	    if err != nil && !errors.Is(err, retry.ErrSkipped) {
+//	        logger.Warn("notify failed", "chat_id", chats[i], "error", err)
+//	    }
+//	}
+//
+// Functional options adjust the default configuration for a single call:
+//
+//	err := retry.Retry(ctx, fn,
+//	    retry.Attempts(5),
+//	    retry.InitialDelay(200*time.Millisecond),
+//	    retry.Jitter(retry.JitterEqual),
+//	    retry.Retryable(retry.RetryableByKind(shared.KindTimeout)),
+//	)
+//	config := retry.NewConfig(retry.Attempts(5), retry.OnRetry(onRetry)) // reusable Config
+//
 // Advanced Configuration:
 //
 //	config := retry.Config{
@@ -30,6 +64,39 @@
 //	}
 //	err := retry.Do(ctx, config, fn)
 //
+// A similar configuration expressed as a composable Backoff (supersedes the delay fields):
+//
+//	config := retry.Config{
+//	    MaxAttempts: 5,
+//	    Backoff: retry.WithMaxElapsed(
+//	        retry.WithJitter(retry.Exponential{Initial: 200 * time.Millisecond, Max: 10 * time.Second}, retry.JitterEqual),
+//	        60*time.Second,
+//	    ),
+//	}
+//	err := retry.Do(ctx, config, fn)
+//
+// Other policies: retry.Constant{Delay: time.Second}, retry.Fibonacci{Initial: 100 * time.Millisecond},
+// or any type implementing Backoff. Unlike the legacy fields, WithJitter never exceeds the
+// wrapped delay and applies no MinDelay floor.
+//
+// Give-Up Reporting:
+//
+//	config := retry.DefaultConfig()
+//	config.Logger = logger // debug per retry, warning on give up
+//	config.OnGiveUp = func(attempts int, total time.Duration, err error, reason string) {
+//	    metrics.RetryGiveUps.WithLabelValues(reason).Inc()
+//	}
+//
+// OnGiveUp fires exactly once when Do returns a RetriesExceededError or a
+// non-retryable error; it does not fire on success or context cancellation.
+//
+// Structured logging with the shared error kind of each failure (give-up always logs at Warn):
+//
+//	config := retry.NewConfig(retry.Attempts(5), retry.WithLogging(logger, slog.LevelInfo))
+//
+// RetriesExceededError implements slog.LogValuer, so slog.Any("error", err) logs
+// attempts, total_duration, reason, error and kind as a group.
+//
 // Custom Retry Logic:
 //
 //	config := retry.DefaultConfig()
@@ -40,6 +107,102 @@
 //	    return time.Second * time.Duration(attempt), true
 //	}
 //
+// Per-Error-Class Delays (bypass backoff and jitter, still capped at MaxDelay):
+//
+//	config := retry.DefaultConfig()
+//	config.DelayOverrides = []retry.DelayRule{{
+//	    Match: func(err error) bool { return errors.As(err, new(*RateLimitError)) },
+//	    Delay: func(attempt int, err error) time.Duration {
+//	        var rl *RateLimitError
+//	        errors.As(err, &rl)
+//	        return rl.RetryAfter
+//	    },
+//	}}
+//
+// Server-Suggested Delays (non-HTTP protocols):
+//
+//	// In the adapter: attach the hint, e.g. Telegram's parameters.retry_after
+//	return &retry.HintedError{Err: err, RetryAfter: time.Duration(resp.RetryAfter) * time.Second}
+//
+//	// HintAwareDelay waits the hint (capped at MaxDelay and the MaxElapsedTime left)
+//	// and the fallback backoff for errors without one
+//	err := retry.Retry(ctx, send, retry.WithBackoff(retry.HintAwareDelay(retry.Exponential{Max: 10 * time.Second})))
+//
+// DefaultRetryable retries a HintedError only when the error it wraps is retryable.
+//
+// Jitter formulas are documented on JitterStrategy.
+//
+// Classification-Aware Retries (shared error kinds):
+//
+//	isRetryable := retry.Any(retry.DefaultRetryable, retry.RetryableByKind(shared.KindDependencyFailure))
+//	err := retry.DoWithRetryable(ctx, cfg, fn, isRetryable)
+//
+//	// Or the ready-made predicate for KindTimeout and KindDependencyFailure
+//	err = retry.DoWithRetryable(ctx, cfg, fn, retry.DomainRetryable)
+//
+// Attempt Metadata:
+//
+//	err := retry.Retry(ctx, func(ctx context.Context) error {
+//	    attempt, _ := retry.AttemptFromContext(ctx) // starts at 1
+//	    if prev := retry.LastErrorFromContext(ctx); prev != nil {
+//	        logger.Info("retrying", "attempt", attempt, "previous_error", prev)
+//	    }
+//	    return someNetworkOperation(ctx)
+//	})
+//
+// Shared Retry Budget:
+//
+//	budget := retry.NewBudget(retry.BudgetConfig{
+//	    MaxRetries:   50,
+//	    Interval:     time.Minute,
+//	    SuccessRatio: 0.1,
+//	})
+//	config := retry.DefaultConfig()
+//	config.Budget = budget // share the same Budget between many Do calls
+//	err := retry.Do(ctx, config, fn)
+//	if errors.Is(err, retry.ErrBudgetExhausted) {
+//	    // fail fast: downstream is likely down
+//	}
+//
+// Circuit Breaker:
+//
+//	breaker := retry.NewBreaker(retry.BreakerConfig{
+//	    FailureThreshold: 5,
+//	    OpenTimeout:      30 * time.Second,
+//	    OnStateChange: func(from, to retry.BreakerState) {
+//	        logger.Warn("circuit state changed", "from", from, "to", to)
+//	    },
+//	})
+//	err := retry.Retry(ctx, fn, retry.WithBreaker(breaker))
+//	if errors.Is(err, retry.ErrCircuitOpen) {
+//	    // fn was not called: downstream failed too often recently
+//	}
+//
+//	err = breaker.Execute(ctx, fn) // single call without retries
+//
+// Stop Reasons:
+//
+//	config := retry.DefaultConfig()
+//	config.WrapNonRetryable = true // non-retryable errors come back as RetriesExceededError too
+//	err := retry.Do(ctx, config, fn)
+//	if reason, ok := retry.StopReasonOf(err); ok {
+//	    metrics.RetryStops.WithLabelValues(reason.String()).Inc()
+//	}
+//	if retry.IsExhausted(err) {
+//	    // MaxAttempts, MaxElapsed or BudgetExhausted
+//	}
+//
+// Testing Without Sleeping:
+//
+// Config.WithClock (or the WithClock option) replaces Now and After with a Clock.
+// retrytest.FakeClock moves only when the test advances it:
+//
+//	clock := retrytest.NewFakeClock()
+//	config := retry.NewConfig(retry.InitialDelay(time.Second), retry.Jitter(retry.JitterNone), retry.WithClock(clock))
+//	go func() { done <- retry.Do(ctx, config, fn) }()
+//	clock.BlockUntilWaiters(1) // first attempt failed, Do waits 1s
+//	clock.Advance(time.Second) // second attempt starts immediately
+//
 // For HTTP-specific retry logic, consider using internal/platform/httpclient
 // which provides HTTP status code awareness and Retry-After header support.
 package retry
@@ -0,0 +1,161 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// giveUpCall records a single OnGiveUp invocation
+type giveUpCall struct {
+	attempts int
+	err      error
+	reason   string
+}
+
+func giveUpConfig(calls *[]giveUpCall) Config {
+	return Config{
+		MaxAttempts:    3,
+		InitialDelay:   time.Millisecond,
+		MaxDelay:       time.Millisecond,
+		JitterStrategy: JitterNone,
+		OnGiveUp: func(attempts int, total time.Duration, err error, reason string) {
+			*calls = append(*calls, giveUpCall{attempts: attempts, err: err, reason: reason})
+		},
+	}
+}
+
+func TestOnGiveUp(t *testing.T) {
+	failure := errors.New("failure")
+
+	tests := []struct {
+		name         string
+		configure    func(*Config)
+		isRetryable  IsRetryableFunc
+		succeedAfter int
+		wantCalls    int
+		wantAttempts int
+		wantReason   string
+	}{
+		{
+			name:         "max attempts",
+			isRetryable:  func(error) bool { return true },
+			wantCalls:    1,
+			wantAttempts: 3,
+			wantReason:   ReasonMaxAttempts,
+		},
+		{
+			name:         "non-retryable",
+			isRetryable:  func(error) bool { return false },
+			wantCalls:    1,
+			wantAttempts: 1,
+			wantReason:   ReasonNonRetryable,
+		},
+		{
+			name: "stopped by policy",
+			configure: func(c *Config) {
+				c.NextDelay = func(attempt int, err error) (time.Duration, bool) { return 0, attempt < 2 }
+			},
+			isRetryable:  func(error) bool { return true },
+			wantCalls:    1,
+			wantAttempts: 2,
+			wantReason:   ReasonStoppedByPolicy,
+		},
+		{
+			name: "budget exhausted",
+			configure: func(c *Config) {
+				c.Budget = NewBudget(BudgetConfig{MaxRetries: 1})
+			},
+			isRetryable:  func(error) bool { return true },
+			wantCalls:    1,
+			wantAttempts: 2,
+			wantReason:   ReasonBudgetExhausted,
+		},
+		{
+			name:         "success",
+			isRetryable:  func(error) bool { return true },
+			succeedAfter: 2,
+			wantCalls:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []giveUpCall
+			config := giveUpConfig(&calls)
+			if tt.configure != nil {
+				tt.configure(&config)
+			}
+
+			attempts := 0
+			_ = DoWithRetryable(context.Background(), config, func(ctx context.Context) error {
+				attempts++
+				if tt.succeedAfter > 0 && attempts > tt.succeedAfter {
+					return nil
+				}
+				return failure
+			}, tt.isRetryable)
+
+			if len(calls) != tt.wantCalls {
+				t.Fatalf("expected %d OnGiveUp calls, got %d", tt.wantCalls, len(calls))
+			}
+			if tt.wantCalls == 0 {
+				return
+			}
+			if calls[0].attempts != tt.wantAttempts {
+				t.Errorf("expected attempts %d, got %d", tt.wantAttempts, calls[0].attempts)
+			}
+			if calls[0].reason != tt.wantReason {
+				t.Errorf("expected reason %q, got %q", tt.wantReason, calls[0].reason)
+			}
+			if !errors.Is(calls[0].err, failure) {
+				t.Errorf("expected last error %v, got %v", failure, calls[0].err)
+			}
+		})
+	}
+}
+
+func TestOnGiveUpNotCalledOnCancel(t *testing.T) {
+	var calls []giveUpCall
+	config := giveUpConfig(&calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := Do(ctx, config, func(ctx context.Context) error {
+		cancel()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("expected no OnGiveUp calls on cancellation, got %d", len(calls))
+	}
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	config := Config{
+		MaxAttempts:    2,
+		InitialDelay:   time.Millisecond,
+		MaxDelay:       time.Millisecond,
+		JitterStrategy: JitterNone,
+		Logger:         slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+
+	_ = DoWithRetryable(context.Background(), config, func(ctx context.Context) error {
+		return errors.New("failure")
+	}, func(error) bool { return true })
+
+	out := buf.String()
+	if !strings.Contains(out, "level=DEBUG msg=\"retrying after error\" attempt=1") {
+		t.Errorf("expected debug retry record, got:\n%s", out)
+	}
+	if !strings.Contains(out, "level=WARN msg=\"retry gave up\" attempts=2") ||
+		!strings.Contains(out, "reason=\"max attempts exceeded\"") {
+		t.Errorf("expected warn give-up record, got:\n%s", out)
+	}
+}
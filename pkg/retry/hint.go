@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+// HintedError carries a server-suggested delay before the next attempt, such as
+// gRPC retry metadata or Telegram's parameters.retry_after. HintAwareDelay waits
+// RetryAfter instead of the computed backoff; DefaultRetryable retries it when Err is retryable.
+type HintedError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *HintedError) Error() string {
+	if e.Err == nil {
+		return "retry after " + e.RetryAfter.String()
+	}
+	return e.Err.Error()
+}
+
+func (e *HintedError) Unwrap() error {
+	return e.Err
+}
+
+// retryAfterHint returns the positive RetryAfter of the first HintedError in the chain of err
+func retryAfterHint(err error) (time.Duration, bool) {
+	var hinted *HintedError
+	if errors.As(err, &hinted) && hinted.RetryAfter > 0 {
+		return hinted.RetryAfter, true
+	}
+	return 0, false
+}
+
+// HintAwareDelay waits the RetryAfter of a HintedError in the chain of the last error
+// and falls back to the delays of fallback for other errors. Inside Do the hint is
+// capped at Config.MaxDelay and at the time left before Config.MaxElapsedTime; it is
+// not jittered. Zero or negative hints use fallback. The fallback decides when to stop.
+func HintAwareDelay(fallback Backoff) Backoff {
+	return &hintBackoff{fallback: fallback, maxDelay: maxDuration}
+}
+
+// hintBackoff is the Backoff returned by HintAwareDelay
+type hintBackoff struct {
+	fallback   Backoff
+	maxDelay   time.Duration
+	maxElapsed time.Duration
+	now        func() time.Time
+	start      time.Time
+}
+
+func (h *hintBackoff) run(c *Config, start time.Time) Backoff {
+	return &hintBackoff{
+		fallback:   startBackoff(h.fallback, c, start),
+		maxDelay:   c.MaxDelay,
+		maxElapsed: c.MaxElapsedTime,
+		now:        c.Now,
+		start:      start,
+	}
+}
+
+func (h *hintBackoff) stopReason() StopReason {
+	return backoffStopReason(h.fallback)
+}
+
+// Next returns the hinted delay of err, or the delay of the fallback
+func (h *hintBackoff) Next(attempt int, err error) (time.Duration, bool) {
+	hint, ok := retryAfterHint(err)
+	if !ok {
+		return h.fallback.Next(attempt, err)
+	}
+
+	delay := min(hint, h.maxDelay)
+	if h.maxElapsed > 0 && h.now != nil {
+		remaining := h.maxElapsed - h.now().Sub(h.start)
+		delay = min(delay, max(remaining, 0))
+	}
+	return delay, true
+}
@@ -0,0 +1,159 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+// temporaryError is retryable only through its Temporary method
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "temporary" }
+func (temporaryError) Temporary() bool { return true }
+
+func TestHintAwareDelay_MixedErrors(t *testing.T) {
+	ms := time.Millisecond
+	cfg := Config{
+		Backoff:  HintAwareDelay(Constant{Delay: 10 * ms}),
+		MaxDelay: time.Second,
+	}
+	errs := []error{
+		errors.New("transient"),
+		&HintedError{Err: errRateLimited, RetryAfter: 300 * ms},
+		errors.New("transient"),
+		&HintedError{Err: errRateLimited, RetryAfter: 5 * time.Second},
+		&HintedError{Err: errRateLimited},
+		fmt.Errorf("send message: %w", &HintedError{Err: errRateLimited, RetryAfter: 200 * ms}),
+	}
+
+	delays, err := recordDelays(t, cfg, errs)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	want := []time.Duration{10 * ms, 300 * ms, 10 * ms, time.Second, 10 * ms, 200 * ms}
+	if fmt.Sprint(delays) != fmt.Sprint(want) {
+		t.Errorf("delays = %v, want %v", delays, want)
+	}
+}
+
+func TestHintAwareDelay_RemainingBudget(t *testing.T) {
+	ms := time.Millisecond
+	cfg := Config{
+		Backoff:        HintAwareDelay(Constant{Delay: 100 * ms}),
+		MaxElapsedTime: time.Second,
+	}
+	errs := []error{
+		errors.New("transient"),
+		&HintedError{Err: errRateLimited, RetryAfter: 5 * time.Second},
+	}
+
+	delays, err := recordDelays(t, cfg, errs)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	want := []time.Duration{100 * ms, 900 * ms}
+	if fmt.Sprint(delays) != fmt.Sprint(want) {
+		t.Errorf("delays = %v, want %v", delays, want)
+	}
+}
+
+func TestHintAwareDelay_FallbackStops(t *testing.T) {
+	ms := time.Millisecond
+	cfg := Config{
+		Backoff: HintAwareDelay(WithMaxElapsed(Constant{Delay: 100 * ms}, 150*ms)),
+	}
+	errs := []error{
+		errors.New("transient"),
+		errors.New("transient"),
+		errors.New("transient"),
+	}
+
+	delays, err := recordDelays(t, cfg, errs)
+	if reason, _ := StopReasonOf(err); reason != MaxElapsed {
+		t.Fatalf("expected MaxElapsed, got %v", err)
+	}
+	if len(delays) != 1 {
+		t.Errorf("delays = %v, want one delay before the fallback stops", delays)
+	}
+}
+
+func TestHintAwareDelay_WithDefaultRetryable(t *testing.T) {
+	ms := time.Millisecond
+	permanent := errors.New("chat not found")
+	errs := []error{
+		&HintedError{Err: io.EOF, RetryAfter: 50 * ms},
+		io.ErrUnexpectedEOF,
+		&HintedError{Err: temporaryError{}, RetryAfter: 70 * ms},
+		&HintedError{Err: permanent, RetryAfter: 10 * ms},
+	}
+
+	var delays []time.Duration
+	now := time.Unix(0, 0)
+	cfg := Config{
+		MaxAttempts: 10,
+		Backoff:     HintAwareDelay(Constant{Delay: 20 * ms}),
+		Now:         func() time.Time { return now },
+		After: func(d time.Duration) <-chan time.Time {
+			delays = append(delays, d)
+			now = now.Add(d)
+			ch := make(chan time.Time, 1)
+			ch <- now
+			return ch
+		},
+	}
+
+	calls := 0
+	err := Do(context.Background(), cfg, func(ctx context.Context) error {
+		calls++
+		return errs[calls-1]
+	})
+	if !errors.Is(err, permanent) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+	if calls != 4 {
+		t.Errorf("calls = %d, want 4", calls)
+	}
+	want := []time.Duration{50 * ms, 20 * ms, 70 * ms}
+	if fmt.Sprint(delays) != fmt.Sprint(want) {
+		t.Errorf("delays = %v, want %v", delays, want)
+	}
+}
+
+func TestDefaultRetryable_HintedError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "retryable wrapped", err: &HintedError{Err: io.EOF, RetryAfter: time.Second}, want: true},
+		{name: "temporary wrapped", err: &HintedError{Err: temporaryError{}, RetryAfter: time.Second}, want: true},
+		{name: "wrapped twice", err: fmt.Errorf("call: %w", &HintedError{Err: temporaryError{}}), want: true},
+		{name: "permanent wrapped", err: &HintedError{Err: errors.New("bad request"), RetryAfter: time.Second}, want: false},
+		{name: "canceled wrapped", err: &HintedError{Err: context.Canceled, RetryAfter: time.Second}, want: false},
+		{name: "hint only", err: &HintedError{RetryAfter: time.Second}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultRetryable(tt.err); got != tt.want {
+				t.Errorf("DefaultRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHintedError(t *testing.T) {
+	err := &HintedError{Err: errRateLimited, RetryAfter: 3 * time.Second}
+	if err.Error() != "rate limited" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, errRateLimited) {
+		t.Error("expected errors.Is to match the wrapped error")
+	}
+	if got := (&HintedError{RetryAfter: 3 * time.Second}).Error(); got != "retry after 3s" {
+		t.Errorf("Error() without Err = %q", got)
+	}
+}
@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"sttbot/internal/shared"
+)
+
+// WithLogging logs each retry at level and every give-up at Warn regardless of level.
+// Records carry the attempt number, delay, error and its shared.KindOf classification
+// as structured attributes. It composes with OnRetry and OnGiveUp set by earlier
+// options: those callbacks still run before the log record is written. Options
+// applied after WithLogging that set OnRetry or OnGiveUp replace the logging.
+func WithLogging(logger *slog.Logger, level slog.Level) Option {
+	return func(c *Config) {
+		if logger == nil {
+			return
+		}
+		onRetry, onGiveUp := c.OnRetry, c.OnGiveUp
+		c.OnRetry = func(attempt int, err error, nextDelay time.Duration) {
+			if onRetry != nil {
+				onRetry(attempt, err, nextDelay)
+			}
+			logger.LogAttrs(context.Background(), level, "retrying after error",
+				slog.Int("attempt", attempt),
+				slog.Duration("delay", nextDelay),
+				slog.Any("error", err),
+				slog.String("kind", shared.KindOf(err).String()),
+			)
+		}
+		c.OnGiveUp = func(attempts int, totalDuration time.Duration, lastErr error, reason string) {
+			if onGiveUp != nil {
+				onGiveUp(attempts, totalDuration, lastErr, reason)
+			}
+			logger.LogAttrs(context.Background(), slog.LevelWarn, "retry gave up",
+				slog.Int("attempts", attempts),
+				slog.Duration("total_duration", totalDuration),
+				slog.String("reason", reason),
+				slog.Any("error", lastErr),
+				slog.String("kind", shared.KindOf(lastErr).String()),
+			)
+		}
+	}
+}
+
+// LogValue implements slog.LogValuer, so logging the error prints its fields
+// as a group instead of the single Error() string
+func (e *RetriesExceededError) LogValue() slog.Value {
+	reason := e.Reason
+	if reason == "" && e.StopReason != 0 {
+		reason = e.StopReason.String()
+	}
+	attrs := []slog.Attr{
+		slog.Int("attempts", e.Attempts),
+		slog.Duration("total_duration", e.TotalDuration),
+		slog.String("reason", reason),
+	}
+	if e.LastError != nil {
+		attrs = append(attrs,
+			slog.String("error", e.LastError.Error()),
+			slog.String("kind", shared.KindOf(e.LastError).String()),
+		)
+	}
+	return slog.GroupValue(attrs...)
+}
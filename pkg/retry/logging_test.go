@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"sttbot/internal/shared"
+)
+
+func TestWithLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	var retries, giveUps int
+	config := NewConfig(
+		Attempts(3),
+		InitialDelay(time.Millisecond),
+		MaxDelay(time.Millisecond),
+		Jitter(JitterNone),
+		OnRetry(func(attempt int, err error, nextDelay time.Duration) { retries++ }),
+		OnGiveUp(func(attempts int, total time.Duration, err error, reason string) { giveUps++ }),
+		WithLogging(logger, slog.LevelInfo),
+	)
+
+	err := Do(context.Background(), config, func(ctx context.Context) error {
+		return shared.MarkKind(errors.New("upstream down"), shared.KindDependencyFailure)
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if retries != 2 || giveUps != 1 {
+		t.Errorf("earlier callbacks must still run, got %d retries and %d give-ups", retries, giveUps)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		`level=INFO msg="retrying after error" attempt=1 delay=1ms error="dependency failure: upstream down" kind=DependencyFailure`,
+		`level=INFO msg="retrying after error" attempt=2 delay=1ms`,
+		`level=WARN msg="retry gave up" attempts=3`,
+		`reason="max attempts exceeded" error="dependency failure: upstream down" kind=DependencyFailure`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in log, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestWithLogging_GiveUpIgnoresLevel(t *testing.T) {
+	var buf bytes.Buffer
+	// Handler drops Debug, so only the Warn give-up record is written
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	config := NewConfig(Attempts(2), InitialDelay(time.Millisecond), WithLogging(logger, slog.LevelDebug))
+
+	_ = Do(context.Background(), config, func(ctx context.Context) error {
+		return errors.New("failure")
+	})
+
+	out := buf.String()
+	if strings.Contains(out, "retrying after error") {
+		t.Errorf("retry records must use the configured level, got:\n%s", out)
+	}
+	if !strings.Contains(out, `level=WARN msg="retry gave up"`) || !strings.Contains(out, "kind=Unknown") {
+		t.Errorf("expected warn give-up record, got:\n%s", out)
+	}
+}
+
+func TestRetriesExceededErrorLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	err := &RetriesExceededError{
+		LastError:     shared.MarkKind(errors.New("slow"), shared.KindTimeout),
+		Attempts:      3,
+		TotalDuration: 2 * time.Second,
+		StopReason:    MaxElapsed,
+	}
+
+	logger.Error("request failed", slog.Any("error", err))
+
+	out := buf.String()
+	want := `error.attempts=3 error.total_duration=2s error.reason="` + MaxElapsed.String() + `" error.error="operation timed out: slow" error.kind=Timeout`
+	if !strings.Contains(out, want) {
+		t.Errorf("expected %q in log, got:\n%s", want, out)
+	}
+}
@@ -0,0 +1,86 @@
+package retry
+
+import "time"
+
+// Option adjusts a Config. Options are applied in order on top of DefaultConfig
+// by NewConfig, Retry and RetryValue; values are validated later by Normalize.
+type Option func(*Config)
+
+// NewConfig returns DefaultConfig with the options applied
+func NewConfig(opts ...Option) Config {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&config)
+		}
+	}
+	return config
+}
+
+// Attempts sets MaxAttempts (including the first attempt)
+func Attempts(n int) Option {
+	return func(c *Config) { c.MaxAttempts = n }
+}
+
+// InitialDelay sets the initial delay between retries
+func InitialDelay(d time.Duration) Option {
+	return func(c *Config) { c.InitialDelay = d }
+}
+
+// MaxDelay sets the maximum delay between retries
+func MaxDelay(d time.Duration) Option {
+	return func(c *Config) { c.MaxDelay = d }
+}
+
+// Multiplier sets the exponential backoff multiplier
+func Multiplier(m float64) Option {
+	return func(c *Config) { c.Multiplier = m }
+}
+
+// Jitter sets the jitter strategy; JitterNone disables jitter
+func Jitter(strategy JitterStrategy) Option {
+	return func(c *Config) {
+		c.JitterStrategy = strategy
+		c.Jitter = strategy != JitterNone
+	}
+}
+
+// MaxElapsedTime limits the total time spent on retries (0 = no limit)
+func MaxElapsedTime(d time.Duration) Option {
+	return func(c *Config) { c.MaxElapsedTime = d }
+}
+
+// WithBackoff sets Backoff, which supersedes the legacy delay fields
+func WithBackoff(b Backoff) Option {
+	return func(c *Config) { c.Backoff = b }
+}
+
+// WithBudget sets the Budget shared across many calls
+func WithBudget(b *Budget) Option {
+	return func(c *Config) { c.Budget = b }
+}
+
+// WithBreaker sets the circuit Breaker consulted before each attempt
+func WithBreaker(b *Breaker) Option {
+	return func(c *Config) { c.Breaker = b }
+}
+
+// OnRetry sets the callback called before each retry
+func OnRetry(fn func(attempt int, err error, nextDelay time.Duration)) Option {
+	return func(c *Config) { c.OnRetry = fn }
+}
+
+// OnGiveUp sets the callback called once when retrying stops with an error
+func OnGiveUp(fn func(attempts int, totalDuration time.Duration, lastErr error, reason string)) Option {
+	return func(c *Config) { c.OnGiveUp = fn }
+}
+
+// Retryable sets the check deciding which errors are retried (see Config.Retryable)
+func Retryable(fn IsRetryableFunc) Option {
+	return func(c *Config) { c.Retryable = fn }
+}
+
+// WithClock sets the time source used for Now and the waits between attempts
+func WithClock(clock Clock) Option {
+	return func(c *Config) { *c = c.WithClock(clock) }
+}
@@ -0,0 +1,132 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewConfig(t *testing.T) {
+	if cfg := NewConfig(); !reflect.DeepEqual(cfg, DefaultConfig()) {
+		t.Errorf("NewConfig() without options should equal DefaultConfig, got %+v", cfg)
+	}
+
+	cfg := NewConfig(
+		Attempts(5),
+		InitialDelay(200*time.Millisecond),
+		MaxDelay(time.Second),
+		Multiplier(3),
+		MaxElapsedTime(time.Minute),
+		nil, // nil options are ignored
+	)
+	if cfg.MaxAttempts != 5 || cfg.InitialDelay != 200*time.Millisecond || cfg.MaxDelay != time.Second ||
+		cfg.Multiplier != 3 || cfg.MaxElapsedTime != time.Minute {
+		t.Errorf("options not applied: %+v", cfg)
+	}
+	// Fields not touched by options keep their defaults
+	if cfg.JitterStrategy != JitterDecorrelated || !cfg.Jitter {
+		t.Errorf("expected default jitter, got %v (Jitter=%v)", cfg.JitterStrategy, cfg.Jitter)
+	}
+
+	// Later options override earlier ones
+	if cfg := NewConfig(Attempts(2), Attempts(7)); cfg.MaxAttempts != 7 {
+		t.Errorf("expected MaxAttempts=7, got %d", cfg.MaxAttempts)
+	}
+}
+
+func TestJitterOption(t *testing.T) {
+	cfg := NewConfig(Jitter(JitterNone))
+	if err := cfg.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.JitterStrategy != JitterNone {
+		t.Errorf("Jitter(JitterNone) should disable jitter, got %v", cfg.JitterStrategy)
+	}
+	for attempt := 1; attempt <= 3; attempt++ {
+		if d := cfg.calculateDelay(attempt); d != DefaultConfig().InitialDelay<<(attempt-1) {
+			t.Errorf("attempt %d: expected delay without jitter, got %v", attempt, d)
+		}
+	}
+
+	if cfg := NewConfig(Jitter(JitterEqual)); cfg.JitterStrategy != JitterEqual || !cfg.Jitter {
+		t.Errorf("expected JitterEqual, got %v (Jitter=%v)", cfg.JitterStrategy, cfg.Jitter)
+	}
+}
+
+func TestRetryWithOptions(t *testing.T) {
+	ctx := context.Background()
+	errTemporary := errors.New("temporary")
+	errFatal := errors.New("fatal")
+
+	var retries []int
+	attempts := 0
+	err := Retry(ctx, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errTemporary
+		}
+		return errFatal
+	},
+		Attempts(5),
+		InitialDelay(time.Millisecond),
+		Jitter(JitterNone),
+		Retryable(func(err error) bool { return errors.Is(err, errTemporary) }),
+		OnRetry(func(attempt int, err error, nextDelay time.Duration) { retries = append(retries, attempt) }),
+	)
+	if !errors.Is(err, errFatal) {
+		t.Errorf("expected fatal error, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	if !reflect.DeepEqual(retries, []int{1, 2}) {
+		t.Errorf("expected OnRetry for attempts [1 2], got %v", retries)
+	}
+
+	// Validation happens in Normalize
+	if err := Retry(ctx, func(ctx context.Context) error { return nil }, Attempts(0)); err == nil {
+		t.Error("expected validation error for Attempts(0)")
+	}
+	if err := Retry(ctx, func(ctx context.Context) error { return nil }, InitialDelay(time.Minute), MaxDelay(time.Second)); err == nil {
+		t.Error("expected validation error for InitialDelay > MaxDelay")
+	}
+}
+
+func TestRetryValueWithOptions(t *testing.T) {
+	attempts := 0
+	value, err := RetryValue(context.Background(), func(ctx context.Context) (string, error) {
+		attempts++
+		if attempts < 2 {
+			return "", errors.New("temporary")
+		}
+		return "ok", nil
+	}, InitialDelay(time.Millisecond), Retryable(func(error) bool { return true }))
+	if err != nil || value != "ok" || attempts != 2 {
+		t.Errorf("expected ok after 2 attempts, got %q, %v after %d", value, err, attempts)
+	}
+}
+
+func TestConfigRetryable(t *testing.T) {
+	errCustom := errors.New("custom")
+	attempts := 0
+	cfg := NewConfig(InitialDelay(time.Millisecond), Retryable(func(err error) bool { return errors.Is(err, errCustom) }))
+	_ = Do(context.Background(), cfg, func(ctx context.Context) error {
+		attempts++
+		return errCustom
+	})
+	if attempts != cfg.MaxAttempts {
+		t.Errorf("Do should use Config.Retryable: expected %d attempts, got %d", cfg.MaxAttempts, attempts)
+	}
+
+	// The explicit check of DoWithRetryable wins over Config.Retryable
+	attempts = 0
+	_ = DoWithRetryable(context.Background(), cfg, func(ctx context.Context) error {
+		attempts++
+		return errCustom
+	}, func(error) bool { return false })
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+}
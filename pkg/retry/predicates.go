@@ -0,0 +1,41 @@
+package retry
+
+import (
+	"slices"
+
+	"sttbot/internal/shared"
+)
+
+// RetryableByKind returns a predicate that retries errors classified by shared.KindOf
+// as one of the given kinds. Nil errors are never retried.
+func RetryableByKind(kinds ...shared.Kind) IsRetryableFunc {
+	allowed := slices.Clone(kinds)
+	return func(err error) bool {
+		if err == nil {
+			return false
+		}
+		return slices.Contains(allowed, shared.KindOf(err))
+	}
+}
+
+// DomainRetryable retries errors marked as KindTimeout or KindDependencyFailure.
+// Errors of other kinds (KindValidation, KindNotFound, KindCanceled, ...) are never retried.
+var DomainRetryable = RetryableByKind(shared.KindTimeout, shared.KindDependencyFailure)
+
+// Any combines predicates: the error is retried if at least one predicate returns true.
+// Nil predicates are ignored.
+//
+// Example:
+//
+//	isRetryable := retry.Any(retry.DefaultRetryable, retry.RetryableByKind(shared.KindDependencyFailure))
+//	err := retry.DoWithRetryable(ctx, cfg, fn, isRetryable)
+func Any(preds ...IsRetryableFunc) IsRetryableFunc {
+	return func(err error) bool {
+		for _, pred := range preds {
+			if pred != nil && pred(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,123 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sttbot/internal/shared"
+)
+
+func TestRetryableByKind(t *testing.T) {
+	pred := RetryableByKind(shared.KindDependencyFailure)
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil error", nil, false},
+		{"plain error", errors.New("plain"), false},
+		{"marked dependency failure", shared.MarkKind(errors.New("upstream"), shared.KindDependencyFailure), true},
+		{"wrapped dependency failure", fmt.Errorf("call: %w", shared.ErrDependencyFailure), true},
+		{"marked validation", shared.MarkKind(errors.New("bad"), shared.KindValidation), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pred(tt.err); got != tt.expected {
+				t.Errorf("RetryableByKind(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDomainRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil error", nil, false},
+		{"timeout", shared.MarkKind(errors.New("slow"), shared.KindTimeout), true},
+		{"deadline exceeded", context.DeadlineExceeded, true},
+		{"dependency failure", shared.MarkKind(errors.New("upstream"), shared.KindDependencyFailure), true},
+		{"validation", shared.MarkKind(errors.New("bad"), shared.KindValidation), false},
+		{"not found", shared.ErrNotFound, false},
+		{"canceled", context.Canceled, false},
+		{"joined canceled and dependency failure", errors.Join(context.Canceled, shared.ErrDependencyFailure), false},
+		{"joined validation and dependency failure", errors.Join(shared.ErrValidation, shared.ErrDependencyFailure), false},
+		{"joined timeout and validation", errors.Join(shared.ErrValidation, shared.ErrTimeout), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DomainRetryable(tt.err); got != tt.expected {
+				t.Errorf("DomainRetryable(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAny(t *testing.T) {
+	pred := Any(DefaultRetryable, RetryableByKind(shared.KindDependencyFailure))
+
+	if !pred(customError{"temp", true}) {
+		t.Error("expected temporary network-like error to be retryable")
+	}
+	if !pred(shared.MarkKind(errors.New("upstream"), shared.KindDependencyFailure)) {
+		t.Error("expected dependency failure to be retryable")
+	}
+	if pred(shared.ErrValidation) {
+		t.Error("expected validation error not to be retryable")
+	}
+	if Any()(errors.New("any")) {
+		t.Error("expected empty Any to never retry")
+	}
+	if Any(nil)(errors.New("any")) {
+		t.Error("expected nil predicates to be ignored")
+	}
+}
+
+func TestDoWithRetryableByKind(t *testing.T) {
+	config := Config{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+		Multiplier:   2.0,
+	}
+	isRetryable := Any(DefaultRetryable, RetryableByKind(shared.KindDependencyFailure))
+
+	t.Run("dependency failure retried", func(t *testing.T) {
+		var attempts int32
+		err := DoWithRetryable(context.Background(), config, func(ctx context.Context) error {
+			if atomic.AddInt32(&attempts, 1) < 3 {
+				return shared.MarkKind(errors.New("upstream down"), shared.KindDependencyFailure)
+			}
+			return nil
+		}, isRetryable)
+		if err != nil {
+			t.Fatalf("expected success, got: %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("validation not retried", func(t *testing.T) {
+		var attempts int32
+		err := DoWithRetryable(context.Background(), config, func(ctx context.Context) error {
+			atomic.AddInt32(&attempts, 1)
+			return shared.MarkKind(errors.New("bad input"), shared.KindValidation)
+		}, isRetryable)
+		if !shared.IsValidation(err) {
+			t.Errorf("expected validation error, got: %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("expected 1 attempt, got %d", attempts)
+		}
+	})
+}
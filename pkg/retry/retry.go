@@ -5,30 +5,63 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"math/rand"
 	"net"
 	"net/url"
 	"os"
 	"syscall"
 	"time"
+
+	"sttbot/internal/shared"
 )
 
-// JitterStrategy defines the jitter strategy to use
+// JitterStrategy defines the jitter strategy to use.
+//
+// With the exponential base delay d = InitialDelay * Multiplier^(attempt-1), capped at MaxDelay,
+// the delay before the next attempt is:
+//
+//	JitterNone:         d
+//	JitterFull:         uniform [0, d]
+//	JitterEqual:        d/2 + uniform [0, d/2]
+//	JitterDecorrelated: uniform [InitialDelay, min(3*prev, d)], prev is the previous jittered
+//	                    delay (InitialDelay at first)
+//
+// This is WithJitter applied to the Exponential backoff of the legacy fields. The result is always clamped to [MinDelay, MaxDelay]. MinDelay defaults to InitialDelay,
+// so set it lower explicitly to get the whole range of JitterFull and JitterEqual.
 type JitterStrategy int
 
 const (
 	// JitterNone disables jitter
 	JitterNone JitterStrategy = iota
-	// JitterEqual applies uniform jitter (equal chance of any delay in range)
+	// JitterEqual keeps half of the delay and randomizes the other half
 	JitterEqual
-	// JitterDecorrelated applies decorrelated jitter (AWS recommended)
+	// JitterDecorrelated grows the delay from the previous one (AWS recommended)
 	JitterDecorrelated
+	// JitterFull picks any delay between zero and the backoff delay
+	JitterFull
 )
 
-// Config defines retry configuration
+// DelayRule overrides the delay for a class of errors, for example to wait the
+// server-suggested time on rate limiting.
+type DelayRule struct {
+	// Match reports whether the rule applies to the error
+	Match func(err error) bool
+	// Delay returns the delay before the next attempt (negative values are treated as zero)
+	Delay func(attempt int, err error) time.Duration
+}
+
+// Config defines retry configuration.
+//
+// The delay between attempts comes from Backoff if set, otherwise from the legacy
+// fields InitialDelay, MinDelay, MaxDelay, Multiplier, Jitter and JitterStrategy,
+// which describe an Exponential backoff with jitter.
 type Config struct {
 	// MaxAttempts is the maximum number of attempts (including the first one)
 	MaxAttempts int
+	// Backoff computes the delays (optional). When set it supersedes InitialDelay,
+	// MinDelay, Multiplier, Jitter and JitterStrategy; MaxDelay only caps DelayOverrides.
+	Backoff Backoff
 	// InitialDelay is the initial delay between retries
 	InitialDelay time.Duration
 	// MinDelay is the minimum delay between retries (defaults to InitialDelay)
@@ -49,10 +82,85 @@ type Config struct {
 	OnRetry func(attempt int, err error, nextDelay time.Duration)
 	// NextDelay allows custom delay calculation (overrides backoff+jitter if provided)
 	NextDelay func(attempt int, err error) (time.Duration, bool)
+	// DelayOverrides are checked before NextDelay and backoff; the first rule matching
+	// the error sets the delay without jitter. The delay is still capped at MaxDelay
+	// and counted toward MaxElapsedTime.
+	DelayOverrides []DelayRule
 	// Now returns current time (for testing, defaults to time.Now)
 	Now func() time.Time
 	// After creates a timer channel (for testing, defaults to time.After)
 	After func(d time.Duration) <-chan time.Time
+	// Budget limits retries shared across many Do calls (optional)
+	Budget *Budget
+	// Breaker is consulted before each attempt and records its outcome (optional).
+	// Every error counts as a breaker failure, including ones the retryable check
+	// rejects; those are still not retried. When the circuit is open, Do stops
+	// without calling fn and returns an error matching ErrCircuitOpen.
+	Breaker *Breaker
+	// OnGiveUp is called once when Do stops retrying with an error: on RetriesExceededError
+	// or on an error that is not retried (see Reason* constants). It is not called on
+	// success or when the context is canceled or its deadline is exceeded.
+	OnGiveUp func(attempts int, totalDuration time.Duration, lastErr error, reason string)
+	// Logger, if set, receives a debug record for each retry and a warning on give up
+	Logger *slog.Logger
+	// WrapNonRetryable wraps a non-retryable error in RetriesExceededError with
+	// StopReason NonRetryable instead of returning it as is (default false)
+	WrapNonRetryable bool
+	// Retryable reports whether an error should be retried by Do and DoValue
+	// (defaults to DefaultRetryable). DoWithRetryable and DoValueWithRetryable
+	// use their isRetryable argument instead.
+	Retryable IsRetryableFunc
+}
+
+// Give-up reasons reported in RetriesExceededError.Reason and Config.OnGiveUp
+const (
+	// ReasonMaxAttempts means all MaxAttempts attempts failed
+	ReasonMaxAttempts = "max attempts exceeded"
+	// ReasonMaxElapsedTime means the next delay would exceed MaxElapsedTime
+	ReasonMaxElapsedTime = "max elapsed time exceeded"
+	// ReasonBudgetExhausted means the shared Budget denied a retry
+	ReasonBudgetExhausted = reasonBudgetExhausted
+	// ReasonNonRetryable means the error was rejected by the retryable check (OnGiveUp only)
+	ReasonNonRetryable = "non-retryable error"
+	// ReasonStoppedByPolicy means NextDelay asked to stop retrying (OnGiveUp only)
+	ReasonStoppedByPolicy = "stopped by NextDelay"
+	// ReasonContextDone means the context was canceled or its deadline exceeded
+	ReasonContextDone = "context done"
+)
+
+// StopReason is the typed reason why Do stopped retrying
+type StopReason int
+
+// Stop reasons reported in RetriesExceededError.StopReason and by StopReasonOf
+const (
+	// MaxAttempts means all MaxAttempts attempts failed
+	MaxAttempts StopReason = iota + 1
+	// MaxElapsed means the next delay would exceed MaxElapsedTime
+	MaxElapsed
+	// ContextDone means the context was canceled or its deadline exceeded (StopReasonOf only)
+	ContextDone
+	// NonRetryable means the error was rejected by the retryable check (see WrapNonRetryable)
+	NonRetryable
+	// BudgetExhausted means the shared Budget denied a retry
+	BudgetExhausted
+)
+
+// String returns the matching Reason* string
+func (r StopReason) String() string {
+	switch r {
+	case MaxAttempts:
+		return ReasonMaxAttempts
+	case MaxElapsed:
+		return ReasonMaxElapsedTime
+	case ContextDone:
+		return ReasonContextDone
+	case NonRetryable:
+		return ReasonNonRetryable
+	case BudgetExhausted:
+		return ReasonBudgetExhausted
+	default:
+		return fmt.Sprintf("StopReason(%d)", int(r))
+	}
 }
 
 // DefaultConfig returns a sensible default configuration
@@ -79,26 +187,13 @@ func (c *Config) Normalize() error {
 	if c.MaxAttempts <= 0 {
 		return errors.New("retry: MaxAttempts must be positive")
 	}
-	if c.InitialDelay <= 0 {
-		return errors.New("retry: InitialDelay must be positive")
-	}
-	if c.MinDelay <= 0 {
-		c.MinDelay = c.InitialDelay
-	}
 	if c.MaxDelay <= 0 {
 		c.MaxDelay = 30 * time.Second
 	}
-	if c.MinDelay > c.MaxDelay {
-		return errors.New("retry: MinDelay cannot be greater than MaxDelay")
-	}
-	if c.InitialDelay < c.MinDelay || c.InitialDelay > c.MaxDelay {
-		return errors.New("retry: InitialDelay must be between MinDelay and MaxDelay")
-	}
-	if c.Multiplier <= 0 {
-		c.Multiplier = 2.0 // default multiplier
-	}
-	if c.Multiplier < 1.0 {
-		return errors.New("retry: Multiplier must be >= 1.0")
+	if c.Backoff == nil {
+		if err := c.normalizeDelays(); err != nil {
+			return err
+		}
 	}
 	if c.MaxElapsedTime < 0 {
 		return errors.New("retry: MaxElapsedTime cannot be negative")
@@ -123,6 +218,29 @@ func (c *Config) Normalize() error {
 	return nil
 }
 
+// normalizeDelays validates the legacy delay fields used when Backoff is not set
+func (c *Config) normalizeDelays() error {
+	if c.InitialDelay <= 0 {
+		return errors.New("retry: InitialDelay must be positive")
+	}
+	if c.MinDelay <= 0 {
+		c.MinDelay = c.InitialDelay
+	}
+	if c.MinDelay > c.MaxDelay {
+		return errors.New("retry: MinDelay cannot be greater than MaxDelay")
+	}
+	if c.InitialDelay < c.MinDelay || c.InitialDelay > c.MaxDelay {
+		return errors.New("retry: InitialDelay must be between MinDelay and MaxDelay")
+	}
+	if c.Multiplier <= 0 {
+		c.Multiplier = 2.0 // default multiplier
+	}
+	if c.Multiplier < 1.0 {
+		return errors.New("retry: Multiplier must be >= 1.0")
+	}
+	return nil
+}
+
 // RetryableFunc is a function that can be retried
 type RetryableFunc func(ctx context.Context) error
 
@@ -134,11 +252,17 @@ type RetriesExceededError struct {
 	LastError     error
 	Attempts      int
 	TotalDuration time.Duration
-	Reason        string
+	// Reason is the human-readable form of StopReason used in Error()
+	Reason     string
+	StopReason StopReason
 }
 
 func (e *RetriesExceededError) Error() string {
-	return "retry: " + e.Reason + " after " + e.TotalDuration.String() + " (" +
+	reason := e.Reason
+	if reason == "" && e.StopReason != 0 {
+		reason = e.StopReason.String()
+	}
+	return "retry: " + reason + " after " + e.TotalDuration.String() + " (" +
 		fmt.Sprintf("%d", e.Attempts) + " attempts): " + e.LastError.Error()
 }
 
@@ -146,7 +270,44 @@ func (e *RetriesExceededError) Unwrap() error {
 	return e.LastError
 }
 
-// DefaultRetryable returns true for temporary errors and context deadline exceeded
+// Is reports whether the error matches target.
+// It allows errors.Is(err, ErrBudgetExhausted) for retries denied by a Budget.
+func (e *RetriesExceededError) Is(target error) bool {
+	return target == ErrBudgetExhausted && (e.StopReason == BudgetExhausted || e.Reason == reasonBudgetExhausted)
+}
+
+// IsExhausted reports whether err is a RetriesExceededError caused by a retry limit:
+// MaxAttempts, MaxElapsed or BudgetExhausted. Wrapped non-retryable errors do not count.
+func IsExhausted(err error) bool {
+	reason, ok := StopReasonOf(err)
+	if !ok {
+		return false
+	}
+	switch reason {
+	case MaxAttempts, MaxElapsed, BudgetExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// StopReasonOf returns the reason why Do stopped retrying.
+// It reports the StopReason of a RetriesExceededError in the chain, and ContextDone for
+// context.Canceled and context.DeadlineExceeded, which Do returns unwrapped.
+// For other errors (including unwrapped non-retryable errors) ok is false.
+func StopReasonOf(err error) (reason StopReason, ok bool) {
+	var exceeded *RetriesExceededError
+	if errors.As(err, &exceeded) && exceeded.StopReason != 0 {
+		return exceeded.StopReason, true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ContextDone, true
+	}
+	return 0, false
+}
+
+// DefaultRetryable returns true for temporary errors, context deadline exceeded,
+// errors reported as retryable by shared.IsRetryable and HintedError wrapping a retryable error
 func DefaultRetryable(err error) bool {
 	if err == nil {
 		return false
@@ -162,6 +323,11 @@ func DefaultRetryable(err error) bool {
 		return true
 	}
 
+	// Retry errors marked with shared.MarkRetryable or classified as timeout/dependency failure
+	if shared.IsRetryable(err) {
+		return true
+	}
+
 	// Check for net.Error with Timeout
 	type netError interface {
 		Timeout() bool
@@ -211,6 +377,12 @@ func DefaultRetryable(err error) bool {
 		}
 	}
 
+	// Server-suggested delay does not make an error retryable by itself
+	var hinted *HintedError
+	if errors.As(err, &hinted) && hinted.Err != nil && DefaultRetryable(hinted.Err) {
+		return true
+	}
+
 	// Check for temporary interface (fallback for compatibility)
 	type temporary interface {
 		Temporary() bool
@@ -224,31 +396,79 @@ func DefaultRetryable(err error) bool {
 
 // Do executes a function with retry logic using exponential backoff
 func Do(ctx context.Context, config Config, fn RetryableFunc) error {
-	return DoWithRetryable(ctx, config, fn, DefaultRetryable)
+	return DoWithRetryable(ctx, config, fn, config.retryable())
 }
 
 // DoWithRetryable executes a function with retry logic and custom retryable check
 func DoWithRetryable(ctx context.Context, config Config, fn RetryableFunc, isRetryable IsRetryableFunc) error {
+	_, err := DoValueWithRetryable(ctx, config, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	}, isRetryable)
+	return err
+}
+
+// RetryableValueFunc is a function returning a value that can be retried
+type RetryableValueFunc[T any] func(ctx context.Context) (T, error)
+
+// DoValue executes a function returning a value with retry logic using exponential backoff.
+// On failure the zero value of T is returned together with the error.
+func DoValue[T any](ctx context.Context, config Config, fn RetryableValueFunc[T]) (T, error) {
+	return DoValueWithRetryable(ctx, config, fn, config.retryable())
+}
+
+// retryable returns Config.Retryable or DefaultRetryable if it is not set
+func (c Config) retryable() IsRetryableFunc {
+	if c.Retryable != nil {
+		return c.Retryable
+	}
+	return DefaultRetryable
+}
+
+// DoValueWithRetryable executes a function returning a value with retry logic and custom retryable check.
+// On failure the zero value of T is returned together with the error.
+func DoValueWithRetryable[T any](ctx context.Context, config Config, fn RetryableValueFunc[T], isRetryable IsRetryableFunc) (T, error) {
+	var zero T
+
 	// Normalize and validate config
 	configCopy := config // Make a copy to avoid modifying the original
 	if err := configCopy.Normalize(); err != nil {
-		return err
+		return zero, err
 	}
 
 	var lastErr error
 	startTime := configCopy.Now()
+	backoff := startBackoff(configCopy.backoff(), &configCopy, startTime)
 
 	for attempt := 1; attempt <= configCopy.MaxAttempts; attempt++ {
 		// Check context before each attempt
 		if ctx.Err() != nil {
-			return ctx.Err()
+			return zero, ctx.Err()
 		}
 
-		lastErr = fn(ctx)
-		if lastErr == nil {
-			return nil // success
+		var generation uint64
+		if configCopy.Breaker != nil {
+			var openErr error
+			generation, openErr = configCopy.Breaker.allow()
+			if openErr != nil {
+				if lastErr != nil {
+					openErr = fmt.Errorf("%w: %w", openErr, lastErr)
+				}
+				configCopy.giveUp(ctx, attempt-1, startTime, openErr, ReasonCircuitOpen)
+				return zero, openErr
+			}
 		}
 
+		result, err := callThrough(ctx, configCopy.Breaker, generation, func() (T, error) {
+			return fn(withAttempt(ctx, attempt, lastErr))
+		})
+		if err == nil {
+			if configCopy.Budget != nil {
+				configCopy.Budget.RecordSuccess()
+			}
+			return result, nil // success
+		}
+		lastErr = err
+
 		// If this is the last attempt, don't check for retryability
 		if attempt == configCopy.MaxAttempts {
 			break
@@ -256,35 +476,55 @@ func DoWithRetryable(ctx context.Context, config Config, fn RetryableFunc, isRet
 
 		// Check if error is retryable
 		if !isRetryable(lastErr) {
-			return lastErr // Return original error for non-retryable errors
+			configCopy.giveUp(ctx, attempt, startTime, lastErr, ReasonNonRetryable)
+			if configCopy.WrapNonRetryable {
+				return zero, &RetriesExceededError{
+					LastError:     lastErr,
+					Attempts:      attempt,
+					TotalDuration: configCopy.Now().Sub(startTime),
+					Reason:        ReasonNonRetryable,
+					StopReason:    NonRetryable,
+				}
+			}
+			return zero, lastErr // Return original error for non-retryable errors
 		}
 
 		// Calculate delay for next attempt
 		var delay time.Duration
 		var shouldRetry bool
 
-		// Use custom NextDelay if provided
-		if configCopy.NextDelay != nil {
-			delay, shouldRetry = configCopy.NextDelay(attempt, lastErr)
+		if override, ok := configCopy.overrideDelay(attempt, lastErr); ok {
+			// Error-class override bypasses backoff and jitter
+			delay = override
+		} else {
+			delay, shouldRetry = backoff.Next(attempt, lastErr)
 			if !shouldRetry {
-				return lastErr // Return original error if custom policy says stop
+				if backoffStopReason(backoff) == MaxElapsed {
+					configCopy.giveUp(ctx, attempt, startTime, lastErr, ReasonMaxElapsedTime)
+					return zero, &RetriesExceededError{
+						LastError:     lastErr,
+						Attempts:      attempt,
+						TotalDuration: configCopy.Now().Sub(startTime),
+						Reason:        ReasonMaxElapsedTime,
+						StopReason:    MaxElapsed,
+					}
+				}
+				configCopy.giveUp(ctx, attempt, startTime, lastErr, ReasonStoppedByPolicy)
+				return zero, lastErr // Return original error if the backoff or NextDelay says stop
 			}
-		} else {
-			delay = configCopy.calculateDelay(attempt)
 		}
 
-		// Apply jitter if enabled
-		delay = configCopy.applyJitter(delay)
-
 		// Check MaxElapsedTime budget
 		if configCopy.MaxElapsedTime > 0 {
 			elapsed := configCopy.Now().Sub(startTime)
 			if elapsed+delay > configCopy.MaxElapsedTime {
-				return &RetriesExceededError{
+				configCopy.giveUp(ctx, attempt, startTime, lastErr, ReasonMaxElapsedTime)
+				return zero, &RetriesExceededError{
 					LastError:     lastErr,
 					Attempts:      attempt,
 					TotalDuration: elapsed,
-					Reason:        "max elapsed time exceeded",
+					Reason:        ReasonMaxElapsedTime,
+					StopReason:    MaxElapsed,
 				}
 			}
 		}
@@ -292,92 +532,123 @@ func DoWithRetryable(ctx context.Context, config Config, fn RetryableFunc, isRet
 		// Respect context deadline
 		if deadline, ok := ctx.Deadline(); ok {
 			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				// Deadline passed but the context may not be marked done yet
+				return zero, context.DeadlineExceeded
+			}
 			if delay > remaining {
 				delay = remaining
 			}
 		}
 
+		// Check shared retry budget
+		if configCopy.Budget != nil && !configCopy.Budget.Allow() {
+			configCopy.giveUp(ctx, attempt, startTime, lastErr, ReasonBudgetExhausted)
+			return zero, &RetriesExceededError{
+				LastError:     lastErr,
+				Attempts:      attempt,
+				TotalDuration: configCopy.Now().Sub(startTime),
+				Reason:        ReasonBudgetExhausted,
+				StopReason:    BudgetExhausted,
+			}
+		}
+
 		// Call OnRetry callback if provided
 		if configCopy.OnRetry != nil {
 			configCopy.OnRetry(attempt, lastErr, delay)
 		}
+		if configCopy.Logger != nil {
+			configCopy.Logger.Debug("retrying after error", slog.Int("attempt", attempt), slog.Duration("delay", delay), slog.Any("error", lastErr))
+		}
 
 		// Wait with context cancellation support
 		timer := configCopy.After(delay)
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return zero, ctx.Err()
 		case <-timer:
 			// Continue to next attempt
 		}
 	}
 
 	// Return enhanced error with retry metadata
-	return &RetriesExceededError{
+	configCopy.giveUp(ctx, configCopy.MaxAttempts, startTime, lastErr, ReasonMaxAttempts)
+	return zero, &RetriesExceededError{
 		LastError:     lastErr,
 		Attempts:      configCopy.MaxAttempts,
 		TotalDuration: configCopy.Now().Sub(startTime),
-		Reason:        "max attempts exceeded",
+		Reason:        ReasonMaxAttempts,
+		StopReason:    MaxAttempts,
 	}
 }
 
-// calculateDelay calculates the delay for the given attempt using exponential backoff
-func (c Config) calculateDelay(attempt int) time.Duration {
-	// Use integer math to avoid float precision issues
-	delay := c.InitialDelay
-
-	// Apply multiplier (attempt-1) times
-	for i := 1; i < attempt; i++ {
-		// Check for overflow before multiplication
-		if delay > c.MaxDelay/time.Duration(c.Multiplier) {
-			return c.MaxDelay
-		}
-		delay = time.Duration(float64(delay) * c.Multiplier)
-
-		// Early exit if we've exceeded max delay
-		if delay > c.MaxDelay {
-			return c.MaxDelay
-		}
+// giveUp reports the final failure to OnGiveUp and Logger.
+// Nothing is reported when the context is done: cancellation is not a retry failure.
+func (c Config) giveUp(ctx context.Context, attempts int, startTime time.Time, lastErr error, reason string) {
+	if c.OnGiveUp == nil && c.Logger == nil {
+		return
 	}
-
-	// Ensure we're within bounds
-	if delay < c.MinDelay {
-		delay = c.MinDelay
+	if ctx.Err() != nil {
+		return
+	}
+	total := c.Now().Sub(startTime)
+	if c.OnGiveUp != nil {
+		c.OnGiveUp(attempts, total, lastErr, reason)
 	}
-	if delay > c.MaxDelay {
-		delay = c.MaxDelay
+	if c.Logger != nil {
+		c.Logger.Warn("retry gave up", slog.Int("attempts", attempts), slog.Duration("total_duration", total), slog.String("reason", reason), slog.Any("error", lastErr))
 	}
+}
+
+// calculateDelay calculates the delay for the given attempt using the Exponential
+// backoff described by the legacy fields
+func (c Config) calculateDelay(attempt int) time.Duration {
+	delay, _ := c.exponential().Next(attempt, nil)
+	return clamp(delay, c.MinDelay, c.MaxDelay)
+}
 
-	return delay
+// exponential returns the Exponential backoff equivalent to the legacy fields
+func (c Config) exponential() Exponential {
+	return Exponential{Initial: c.InitialDelay, Max: c.MaxDelay, Multiplier: c.Multiplier}
 }
 
-// applyJitter applies the configured jitter strategy to the delay
-func (c Config) applyJitter(baseDelay time.Duration) time.Duration {
-	if c.JitterStrategy == JitterNone && !c.Jitter {
-		return baseDelay
+// overrideDelay returns the delay of the first DelayOverrides rule matching err, capped at MaxDelay
+func (c Config) overrideDelay(attempt int, err error) (time.Duration, bool) {
+	for _, rule := range c.DelayOverrides {
+		if rule.Match == nil || rule.Delay == nil || !rule.Match(err) {
+			continue
+		}
+		return clamp(rule.Delay(attempt, err), 0, c.MaxDelay), true
 	}
+	return 0, false
+}
 
-	switch c.JitterStrategy {
-	case JitterEqual:
-		// Equal jitter: random value between 0 and baseDelay
-		jitter := time.Duration(c.Rand.Int63n(int64(baseDelay)))
-		return clamp(jitter, c.MinDelay, c.MaxDelay)
+// backoff returns the Backoff used by Do: NextDelay or the Exponential described by
+// the legacy fields, jittered with JitterStrategy and clamped to [MinDelay, MaxDelay],
+// or Backoff as is when NextDelay is not set. NextDelay without jitter is used as is.
+func (c *Config) backoff() Backoff {
+	var base Backoff = c.exponential()
+	switch {
+	case c.NextDelay != nil && c.JitterStrategy == JitterNone:
+		return backoffFunc(c.NextDelay)
+	case c.NextDelay != nil:
+		base = backoffFunc(c.NextDelay)
+	case c.Backoff != nil:
+		return c.Backoff
+	}
+	// Same as WithJitter, without its own random source: Do binds Config.Rand
+	return clampBackoff{
+		inner: &jitterBackoff{inner: base, strategy: c.JitterStrategy, rand: c.Rand},
+		min:   c.MinDelay,
+		max:   c.MaxDelay,
+	}
+}
 
-	case JitterDecorrelated:
-		// Decorrelated jitter: 3 * baseDelay / 2 ± baseDelay / 2
-		max := 3 * baseDelay / 2
-		jitter := baseDelay + time.Duration(c.Rand.Int63n(int64(max-baseDelay/2)))
-		return clamp(jitter, c.MinDelay, c.MaxDelay)
+// backoffFunc adapts Config.NextDelay to Backoff
+type backoffFunc func(attempt int, err error) (time.Duration, bool)
 
-	default:
-		// Legacy jitter (±25% for backward compatibility)
-		if c.Jitter {
-			jitterRange := baseDelay / 4 // 25%
-			jitter := baseDelay + time.Duration(c.Rand.Int63n(int64(2*jitterRange))) - jitterRange
-			return clamp(jitter, c.MinDelay, c.MaxDelay)
-		}
-		return baseDelay
-	}
+func (f backoffFunc) Next(attempt int, err error) (time.Duration, bool) {
+	return f(attempt, err)
 }
 
 // clamp ensures the value is within the specified bounds
@@ -392,15 +663,20 @@ func clamp(value, min, max time.Duration) time.Duration {
 }
 
 // Retry is a convenience function that uses default configuration
-func Retry(ctx context.Context, fn RetryableFunc) error {
-	return Do(ctx, DefaultConfig(), fn)
+// adjusted by options (see NewConfig)
+func Retry(ctx context.Context, fn RetryableFunc, opts ...Option) error {
+	return Do(ctx, NewConfig(opts...), fn)
+}
+
+// RetryValue is a convenience function that uses default configuration
+// adjusted by options and returns a value
+func RetryValue[T any](ctx context.Context, fn RetryableValueFunc[T], opts ...Option) (T, error) {
+	return DoValue(ctx, NewConfig(opts...), fn)
 }
 
 // RetryWithAttempts is a convenience function with custom max attempts
 func RetryWithAttempts(ctx context.Context, maxAttempts int, fn RetryableFunc) error {
-	config := DefaultConfig()
-	config.MaxAttempts = maxAttempts
-	return Do(ctx, config, fn)
+	return Retry(ctx, fn, Attempts(maxAttempts))
 }
 
 // RetryWithConfig is a convenience function that validates config and retries
@@ -410,8 +686,5 @@ func RetryWithConfig(ctx context.Context, config Config, fn RetryableFunc) error
 
 // RetryWithTimeout is a convenience function with timeout and max attempts
 func RetryWithTimeout(ctx context.Context, timeout time.Duration, maxAttempts int, fn RetryableFunc) error {
-	config := DefaultConfig()
-	config.MaxAttempts = maxAttempts
-	config.MaxElapsedTime = timeout
-	return Do(ctx, config, fn)
+	return Retry(ctx, fn, Attempts(maxAttempts), MaxElapsedTime(timeout))
 }
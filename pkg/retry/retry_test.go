@@ -13,6 +13,9 @@ import (
 	"syscall"
 	"testing"
 	"time"
+
+	"sttbot/internal/shared"
+	"sttbot/pkg/retry/retrytest"
 )
 
 // customError implements temporary interface for testing
@@ -56,6 +59,9 @@ func TestDefaultRetryable(t *testing.T) {
 		{"temporary error", customError{"temp", true}, true},
 		{"non-temporary error", customError{"not temp", false}, false},
 		{"regular error", errors.New("regular"), false},
+		{"marked retryable", shared.Wrap(shared.MarkRetryable(errors.New("reset")), "upload"), true},
+		{"dependency failure kind", shared.MarkKind(errors.New("upstream"), shared.KindDependencyFailure), true},
+		{"marked canceled", shared.MarkRetryable(context.Canceled), false},
 	}
 
 	for _, tt := range tests {
@@ -244,18 +250,19 @@ func TestDoContextTimeout(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
 	defer cancel()
 
+	// The fake clock never advances, so the backoff wait ends only by the context deadline
+	clock := retrytest.NewFakeClock()
 	config := Config{
 		MaxAttempts:  5,
 		InitialDelay: 100 * time.Millisecond, // longer than context timeout
 		MaxDelay:     200 * time.Millisecond,
 		Multiplier:   2.0,
 		Jitter:       false,
-	}
+	}.WithClock(clock)
 
 	var attempts int32
 	fn := func(ctx context.Context) error {
 		atomic.AddInt32(&attempts, 1)
-		time.Sleep(5 * time.Millisecond) // ensure we use some time
 		return customError{"retryable", true}
 	}
 
@@ -263,10 +270,11 @@ func TestDoContextTimeout(t *testing.T) {
 	if !errors.Is(err, context.DeadlineExceeded) {
 		t.Errorf("expected context.DeadlineExceeded, got: %v", err)
 	}
-
-	// Should timeout within a reasonable number of attempts
-	if attempts > 3 {
-		t.Errorf("expected fewer attempts before timeout, got %d", attempts)
+	if attempts != 1 {
+		t.Errorf("expected a single attempt before timeout, got %d", attempts)
+	}
+	if clock.Waiters() != 1 {
+		t.Errorf("expected Do to wait for the backoff, waiters = %d", clock.Waiters())
 	}
 }
 
@@ -338,12 +346,13 @@ func TestJitterVariation(t *testing.T) {
 		t.Fatalf("config normalize failed: %v", err)
 	}
 
-	// Test that applyJitter produces different delays from the base delay
-	baseDelay := config.calculateDelay(1) // 100ms
+	// Test that jitter produces different delays for the second retry
 	jitteredDelays := make([]time.Duration, 10)
 
 	for i := 0; i < 10; i++ {
-		jitteredDelays[i] = config.applyJitter(baseDelay)
+		b := startBackoff(config.backoff(), &config, time.Now())
+		b.Next(1, nil)
+		jitteredDelays[i], _ = b.Next(2, nil)
 	}
 
 	// Check that jitter produces variations
@@ -472,14 +481,16 @@ func TestConfigNormalize(t *testing.T) {
 }
 
 func TestMaxElapsedTime(t *testing.T) {
+	ms := time.Millisecond
+	clock := retrytest.NewFakeClock()
 	config := Config{
 		MaxAttempts:    10,
-		InitialDelay:   10 * time.Millisecond,
-		MaxDelay:       50 * time.Millisecond,
-		MaxElapsedTime: 100 * time.Millisecond,
+		InitialDelay:   10 * ms,
+		MaxDelay:       50 * ms,
+		MaxElapsedTime: 100 * ms,
 		Multiplier:     2.0,
 		JitterStrategy: JitterNone,
-	}
+	}.WithClock(clock)
 
 	var attempts int32
 	temporaryErr := customError{"temporary failure", true}
@@ -488,9 +499,15 @@ func TestMaxElapsedTime(t *testing.T) {
 		return temporaryErr
 	}
 
-	start := time.Now()
-	err := Do(context.Background(), config, fn)
-	elapsed := time.Since(start)
+	done := make(chan error, 1)
+	go func() { done <- Do(context.Background(), config, fn) }()
+
+	// Attempts at 0, 10ms, 30ms and 70ms; the next 50ms delay would end past 100ms
+	for _, delay := range []time.Duration{10 * ms, 20 * ms, 40 * ms} {
+		clock.BlockUntilWaiters(1)
+		clock.Advance(delay)
+	}
+	err := <-done
 
 	var retryErr *RetriesExceededError
 	if !errors.As(err, &retryErr) {
@@ -500,15 +517,11 @@ func TestMaxElapsedTime(t *testing.T) {
 	if retryErr.Reason != "max elapsed time exceeded" {
 		t.Errorf("expected 'max elapsed time exceeded', got %q", retryErr.Reason)
 	}
-
-	// Should stop before 10 attempts due to time limit
-	if attempts >= 10 {
-		t.Errorf("expected fewer than 10 attempts, got %d", attempts)
+	if attempts != 4 {
+		t.Errorf("expected 4 attempts, got %d", attempts)
 	}
-
-	// Should respect the time budget approximately
-	if elapsed > 200*time.Millisecond {
-		t.Errorf("took too long: %v", elapsed)
+	if retryErr.TotalDuration != 70*ms {
+		t.Errorf("expected total duration 70ms of fake time, got %v", retryErr.TotalDuration)
 	}
 }
 
@@ -628,3 +641,125 @@ func TestRetriesExceededError(t *testing.T) {
 		t.Error("error message should not be empty")
 	}
 }
+
+func TestDoValueSuccess(t *testing.T) {
+	ctx := context.Background()
+	config := Config{
+		MaxAttempts:  3,
+		InitialDelay: 1 * time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+		Multiplier:   2.0,
+		Jitter:       false,
+	}
+
+	var attempts int32
+	result, err := DoValue(ctx, config, func(ctx context.Context) (string, error) {
+		if atomic.AddInt32(&attempts, 1) < 2 {
+			return "", customError{"temp", true}
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("expected success, got error: %v", err)
+	}
+	if result != "ok" {
+		t.Errorf("expected result %q, got %q", "ok", result)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestDoValueFailureReturnsZero(t *testing.T) {
+	ctx := context.Background()
+	config := Config{
+		MaxAttempts:  2,
+		InitialDelay: 1 * time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+		Multiplier:   2.0,
+		Jitter:       false,
+	}
+
+	result, err := DoValue(ctx, config, func(ctx context.Context) (int, error) {
+		return 42, customError{"always fails", true}
+	})
+	if result != 0 {
+		t.Errorf("expected zero value, got %d", result)
+	}
+	var retryErr *RetriesExceededError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("expected RetriesExceededError, got %T", err)
+	}
+	if retryErr.Attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", retryErr.Attempts)
+	}
+}
+
+func TestDoValueContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	config := Config{
+		MaxAttempts:  5,
+		InitialDelay: 50 * time.Millisecond,
+		MaxDelay:     100 * time.Millisecond,
+		Multiplier:   2.0,
+		Jitter:       false,
+	}
+
+	result, err := DoValue(ctx, config, func(ctx context.Context) (*int, error) {
+		cancel()
+		v := 1
+		return &v, customError{"retryable", true}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got: %v", err)
+	}
+	if result != nil {
+		t.Errorf("expected nil result, got %v", *result)
+	}
+}
+
+func TestRetryValue(t *testing.T) {
+	var attempts int32
+	result, err := RetryValue(context.Background(), func(ctx context.Context) ([]byte, error) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			return nil, customError{"temp", true}
+		}
+		return []byte("data"), nil
+	})
+	if err != nil {
+		t.Fatalf("RetryValue failed: %v", err)
+	}
+	if string(result) != "data" {
+		t.Errorf("expected %q, got %q", "data", result)
+	}
+}
+
+// expiredDeadlineContext reports a past deadline before being marked done,
+// like a timer context whose timer has not fired yet.
+type expiredDeadlineContext struct {
+	context.Context
+}
+
+func (expiredDeadlineContext) Deadline() (time.Time, bool) {
+	return time.Now().Add(-time.Millisecond), true
+}
+
+func TestDoStopsAfterDeadlinePassed(t *testing.T) {
+	var attempts int32
+	config := Config{
+		MaxAttempts:  100,
+		InitialDelay: 10 * time.Millisecond,
+		MaxDelay:     10 * time.Millisecond,
+	}
+
+	err := DoWithRetryable(expiredDeadlineContext{context.Background()}, config, func(ctx context.Context) error {
+		atomic.AddInt32(&attempts, 1)
+		return errors.New("busy")
+	}, func(error) bool { return true })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got: %v", err)
+	}
+	if n := atomic.LoadInt32(&attempts); n != 1 {
+		t.Errorf("expected 1 attempt, got %d", n)
+	}
+}
@@ -0,0 +1,107 @@
+// Package retrytest provides a fake clock for deterministic tests of code using retry.
+//
+// A FakeClock satisfies retry.Clock. Time moves only when the test calls Advance,
+// so backoff sequences can be stepped through without sleeping:
+//
+//	clock := retrytest.NewFakeClock()
+//	cfg := retry.DefaultConfig().WithClock(clock)
+//
+//	done := make(chan error, 1)
+//	go func() { done <- retry.Do(ctx, cfg, fn) }()
+//
+//	clock.BlockUntilWaiters(1) // Do is waiting before the second attempt
+//	clock.Advance(time.Second)
+package retrytest
+
+import (
+	"sync"
+	"time"
+)
+
+// FakeClock is a manually advanced clock. It is safe for concurrent use.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []waiter
+}
+
+// waiter is a channel returned by After that has not fired yet
+type waiter struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+// NewFakeClock returns a FakeClock set to a fixed moment (2000-01-01 UTC),
+// so results do not depend on the wall clock.
+func NewFakeClock() *FakeClock {
+	return NewFakeClockAt(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+// NewFakeClockAt returns a FakeClock set to now
+func NewFakeClockAt(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current fake time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the fake time once the clock has been
+// advanced by at least d. A non-positive d fires immediately.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, waiter{deadline: c.now.Add(d), ch: ch})
+	c.cond.Broadcast()
+	return ch
+}
+
+// Advance moves the clock forward by d and fires every After channel whose
+// deadline has been reached.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	clear(c.waiters[len(pending):])
+	c.waiters = pending
+	c.cond.Broadcast()
+}
+
+// BlockUntilWaiters blocks until at least n After channels are waiting to fire,
+// i.e. until the code under test has started its n-th concurrent wait. Channels
+// abandoned by a canceled wait keep counting until Advance reaches their deadline.
+func (c *FakeClock) BlockUntilWaiters(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// Waiters returns the number of After channels waiting to fire
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
@@ -0,0 +1,67 @@
+package retrytest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock_After(t *testing.T) {
+	clock := NewFakeClock()
+	start := clock.Now()
+
+	short := clock.After(time.Second)
+	long := clock.After(3 * time.Second)
+	if n := clock.Waiters(); n != 2 {
+		t.Fatalf("Waiters() = %d, want 2", n)
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	select {
+	case <-short:
+		t.Fatal("fired before its deadline")
+	default:
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	select {
+	case at := <-short:
+		if got := at.Sub(start); got != time.Second {
+			t.Errorf("fired at %v, want 1s", got)
+		}
+	default:
+		t.Fatal("expected the channel to fire at its deadline")
+	}
+	if n := clock.Waiters(); n != 1 {
+		t.Errorf("Waiters() = %d, want 1", n)
+	}
+
+	clock.Advance(time.Hour)
+	select {
+	case <-long:
+	default:
+		t.Fatal("expected the channel to fire after a long advance")
+	}
+
+	select {
+	case <-clock.After(0):
+	default:
+		t.Error("After(0) should fire immediately")
+	}
+	if n := clock.Waiters(); n != 0 {
+		t.Errorf("Waiters() = %d, want 0", n)
+	}
+}
+
+func TestFakeClock_BlockUntilWaiters(t *testing.T) {
+	clock := NewFakeClock()
+	fired := make(chan time.Time)
+	go func() {
+		fired <- <-clock.After(time.Minute)
+	}()
+
+	clock.BlockUntilWaiters(1)
+	clock.Advance(time.Minute)
+	if at := <-fired; !at.Equal(clock.Now()) {
+		t.Errorf("fired at %v, want %v", at, clock.Now())
+	}
+}
@@ -0,0 +1,166 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStopReason(t *testing.T) {
+	failure := errors.New("failure")
+
+	tests := []struct {
+		name          string
+		configure     func(*Config)
+		isRetryable   IsRetryableFunc
+		wantReason    StopReason
+		wantExhausted bool
+	}{
+		{
+			name:          "max attempts",
+			isRetryable:   func(error) bool { return true },
+			wantReason:    MaxAttempts,
+			wantExhausted: true,
+		},
+		{
+			name: "max elapsed",
+			configure: func(c *Config) {
+				c.MaxElapsedTime = time.Millisecond
+				c.MaxDelay = time.Second
+				c.InitialDelay = time.Second
+			},
+			isRetryable:   func(error) bool { return true },
+			wantReason:    MaxElapsed,
+			wantExhausted: true,
+		},
+		{
+			name: "budget exhausted",
+			configure: func(c *Config) {
+				c.Budget = NewBudget(BudgetConfig{MaxRetries: 1, SuccessRatio: 0})
+				c.Budget.Allow() // spend the only token
+			},
+			isRetryable:   func(error) bool { return true },
+			wantReason:    BudgetExhausted,
+			wantExhausted: true,
+		},
+		{
+			name:        "non-retryable wrapped",
+			configure:   func(c *Config) { c.WrapNonRetryable = true },
+			isRetryable: func(error) bool { return false },
+			wantReason:  NonRetryable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{
+				MaxAttempts:    3,
+				InitialDelay:   time.Millisecond,
+				MaxDelay:       time.Millisecond,
+				JitterStrategy: JitterNone,
+			}
+			if tt.configure != nil {
+				tt.configure(&cfg)
+			}
+
+			err := DoWithRetryable(context.Background(), cfg, func(ctx context.Context) error {
+				return failure
+			}, tt.isRetryable)
+
+			var exceeded *RetriesExceededError
+			if !errors.As(err, &exceeded) {
+				t.Fatalf("expected RetriesExceededError, got %v", err)
+			}
+			if exceeded.StopReason != tt.wantReason {
+				t.Errorf("StopReason = %v, want %v", exceeded.StopReason, tt.wantReason)
+			}
+			if exceeded.Reason != tt.wantReason.String() {
+				t.Errorf("Reason = %q, want %q", exceeded.Reason, tt.wantReason.String())
+			}
+			if !errors.Is(err, failure) {
+				t.Error("expected the last error to be wrapped")
+			}
+
+			wrapped := fmt.Errorf("fetch: %w", err)
+			if reason, ok := StopReasonOf(wrapped); !ok || reason != tt.wantReason {
+				t.Errorf("StopReasonOf = %v, %v; want %v, true", reason, ok, tt.wantReason)
+			}
+			if got := IsExhausted(wrapped); got != tt.wantExhausted {
+				t.Errorf("IsExhausted = %v, want %v", got, tt.wantExhausted)
+			}
+		})
+	}
+}
+
+func TestWrapNonRetryable_DefaultReturnsOriginal(t *testing.T) {
+	failure := errors.New("failure")
+	cfg := Config{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	err := DoWithRetryable(context.Background(), cfg, func(ctx context.Context) error {
+		return failure
+	}, func(error) bool { return false })
+
+	if err != failure {
+		t.Fatalf("expected the original error, got %v", err)
+	}
+	if _, ok := StopReasonOf(err); ok {
+		t.Error("StopReasonOf should not report a reason for a bare error")
+	}
+	if IsExhausted(err) {
+		t.Error("IsExhausted should be false for a bare error")
+	}
+}
+
+func TestWrapNonRetryable_Attempts(t *testing.T) {
+	var calls []giveUpCall
+	cfg := giveUpConfig(&calls)
+	cfg.WrapNonRetryable = true
+	failure := errors.New("failure")
+
+	attempt := 0
+	err := DoWithRetryable(context.Background(), cfg, func(ctx context.Context) error {
+		attempt++
+		return failure
+	}, func(error) bool { return attempt < 2 })
+
+	var exceeded *RetriesExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("expected RetriesExceededError, got %v", err)
+	}
+	if exceeded.Attempts != 2 {
+		t.Errorf("Attempts = %d, want 2", exceeded.Attempts)
+	}
+	if len(calls) != 1 || calls[0].reason != ReasonNonRetryable {
+		t.Errorf("expected one OnGiveUp call with %q, got %+v", ReasonNonRetryable, calls)
+	}
+}
+
+func TestStopReasonOf_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Retry(ctx, func(ctx context.Context) error { return errors.New("failure") })
+	if reason, ok := StopReasonOf(err); !ok || reason != ContextDone {
+		t.Errorf("StopReasonOf = %v, %v; want %v, true", reason, ok, ContextDone)
+	}
+	if IsExhausted(err) {
+		t.Error("IsExhausted should be false for context cancellation")
+	}
+}
+
+func TestStopReason_String(t *testing.T) {
+	if got := MaxAttempts.String(); got != ReasonMaxAttempts {
+		t.Errorf("MaxAttempts.String() = %q", got)
+	}
+	if got := StopReason(42).String(); got != "StopReason(42)" {
+		t.Errorf("unknown reason String() = %q", got)
+	}
+
+	// Error() falls back to StopReason when Reason is empty
+	err := &RetriesExceededError{LastError: errors.New("x"), Attempts: 2, StopReason: MaxElapsed}
+	if want := "retry: " + ReasonMaxElapsedTime + " after 0s (2 attempts): x"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}